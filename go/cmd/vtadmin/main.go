@@ -18,6 +18,7 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"io"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/vtadmin"
+	"vitess.io/vitess/go/vt/vtadmin/audit"
 	"vitess.io/vitess/go/vt/vtadmin/cache"
 	"vitess.io/vitess/go/vt/vtadmin/cluster"
 	"vitess.io/vitess/go/vt/vtadmin/grpcserver"
@@ -52,6 +54,10 @@ var (
 
 	cacheRefreshKey string
 
+	auditSinkName  string
+	auditOpts      audit.Options
+	auditClusterID string
+
 	traceCloser io.Closer = &noopCloser{}
 
 	rootCmd = &cobra.Command{
@@ -133,6 +139,31 @@ func run(cmd *cobra.Command, args []string) {
 		clusters[i] = cluster
 	}
 
+	var auditSink audit.Sink
+	if auditSinkName != "" {
+		if auditClusterID != "" {
+			for _, c := range clusters {
+				if c.ID == auditClusterID {
+					auditOpts.DB = c.DB
+					break
+				}
+			}
+
+			if auditOpts.DB == nil {
+				bootSpan.Finish()
+				fatal(fmt.Sprintf("--audit-table-cluster %s is not a configured cluster", auditClusterID))
+			}
+		}
+
+		sink, err := audit.NewSink(auditSinkName, auditOpts)
+		if err != nil {
+			bootSpan.Finish()
+			fatal(err)
+		}
+
+		auditSink = sink
+	}
+
 	if cacheRefreshKey == "" {
 		log.Warningf("no cache-refresh-key set; forcing cache refreshes will not be possible")
 	}
@@ -151,6 +182,7 @@ func run(cmd *cobra.Command, args []string) {
 		HTTPOpts:              httpOpts,
 		RBAC:                  rbacConfig,
 		EnableDynamicClusters: enableDynamicClusters,
+		AuditSink:             auditSink,
 	})
 	bootSpan.Finish()
 
@@ -202,6 +234,13 @@ func main() {
 	rootCmd.Flags().BoolVar(&enableRBAC, "rbac", false, "whether to enable RBAC. must be set if not passing --rbac")
 	rootCmd.Flags().BoolVar(&disableRBAC, "no-rbac", false, "whether to disable RBAC. must be set if not passing --no-rbac")
 
+	// Audit flags
+	rootCmd.Flags().StringVar(&auditSinkName, "audit-sink", "", "where to write the audit records of mutating API calls: file, syslog, table, or the name of a registered sink. omit to disable auditing")
+	rootCmd.Flags().StringVar(&auditOpts.FilePath, "audit-file", "", "path of the JSON lines file the audit records are appended to, with --audit-sink=file")
+	rootCmd.Flags().StringVar(&auditOpts.SyslogTag, "audit-syslog-tag", "vtadmin", "tag of the syslog messages of the audit records, with --audit-sink=syslog")
+	rootCmd.Flags().StringVar(&auditClusterID, "audit-table-cluster", "", "id of the cluster the audit records are written to, with --audit-sink=table")
+	rootCmd.Flags().StringVar(&auditOpts.Table, "audit-table", audit.DefaultTable, "table the audit records are written to, optionally qualified by an unsharded keyspace, with --audit-sink=table")
+
 	// Global cache flags (N.B. there are also cluster-specific cache flags)
 	cacheRefreshHelp := "instructs a request to ignore any cached data (if applicable) and refresh the cache;" +
 		"usable as an HTTP header named 'X-<key>' and as a gRPC metadata key '<key>'\n" +
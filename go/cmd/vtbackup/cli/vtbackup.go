@@ -296,6 +296,12 @@ func run(cc *cobra.Command, args []string) error {
 		}
 	}
 
+	// Copy backups and the binlog archive to the secondary backup storage, if any. This
+	// also catches up on copies that failed during previous runs.
+	if err := mysqlctl.ReplicateBackups(ctx, logutil.NewConsoleLogger(), initKeyspace, initShard); err != nil {
+		return fmt.Errorf("Failed to copy backups to secondary backup storage: %w", err)
+	}
+
 	// Prune old backups.
 	if err := pruneBackups(ctx, backupStorage, backupDir); err != nil {
 		return fmt.Errorf("Couldn't prune old backups: %w", err)
@@ -0,0 +1,177 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/vt/cdc"
+	"vitess.io/vitess/go/vt/grpccommon"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+
+	// Import and register the gRPC vtgateconn client
+	_ "vitess.io/vitess/go/vt/vtgate/grpcvtgateconn"
+)
+
+var (
+	server            string
+	name              string
+	brokers           []string
+	topicPrefix       = "vitess"
+	checkpointTopic   = "vtcdc-checkpoints"
+	replicationFactor = int16(-1)
+	format            = cdc.FormatJSON
+	keyspace          string
+	shards            []string
+	tableFilter       = ".*"
+	tabletType        = "primary"
+	copyTables        bool
+	startFromCurrent  bool
+	retryDelay        = 5 * time.Second
+
+	Main = &cobra.Command{
+		Use:   "vtcdc",
+		Short: "vtcdc streams the changes of a keyspace into Kafka as Debezium change events.",
+		Long: `vtcdc streams the changes of a keyspace from a vtgate VStream into Kafka
+compatible brokers, as Debezium change events encoded in JSON or Avro.
+
+Row changes go to a topic per table named <topic-prefix>.<keyspace>.<table>,
+and DDLs go to the <topic-prefix> topic. Every batch of events is produced in
+a Kafka transaction together with the VGTID it ends at, which is stored in the
+checkpoint topic. Consumers reading committed records see every change exactly
+once, and a restarted vtcdc resumes from the last checkpoint.
+
+When there is no checkpoint yet, vtcdc only starts with --copy, which produces
+the existing rows first, or with --start-from-current.`,
+		Example: `vtcdc --server vtgate:15991 --name commerce-cdc --brokers kafka:9092 --keyspace commerce --copy`,
+		Args:    cobra.NoArgs,
+		Version: servenv.AppVersion.String(),
+		PreRunE: servenv.CobraPreRunE,
+		RunE:    run,
+	}
+)
+
+func init() {
+	servenv.MoveFlagsToCobraCommand(Main)
+
+	Main.Flags().StringVar(&server, "server", server, "vtgate server to stream from")
+	Main.Flags().StringVar(&name, "name", name, "Name of the connector, used as the key of its checkpoints and as the transactional ID of its producer. Must be unique among the connectors writing to the same brokers.")
+	Main.Flags().StringSliceVar(&brokers, "brokers", brokers, "Addresses of the Kafka brokers to bootstrap from")
+	Main.Flags().StringVar(&topicPrefix, "topic-prefix", topicPrefix, "Prefix of the topics the changes are produced to")
+	Main.Flags().StringVar(&checkpointTopic, "checkpoint-topic", checkpointTopic, "Topic the VGTIDs are stored in, created as a compacted topic if it does not exist")
+	Main.Flags().Int16Var(&replicationFactor, "replication-factor", replicationFactor, "Replication factor of the checkpoint topic when it is created. -1 uses the broker default.")
+	Main.Flags().StringVar(&format, "format", format, "Encoding of the records, json or avro")
+	Main.Flags().StringVar(&keyspace, "keyspace", keyspace, "Keyspace to stream the changes of")
+	Main.Flags().StringSliceVar(&shards, "shards", shards, "Shards to stream the changes of. All the shards of the keyspace are streamed when empty.")
+	Main.Flags().StringVar(&tableFilter, "table-filter", tableFilter, "Regular expression matching the tables to stream the changes of")
+	Main.Flags().StringVar(&tabletType, "tablet-type", tabletType, "Type of the tablets to stream from")
+	Main.Flags().BoolVar(&copyTables, "copy", copyTables, "When there is no checkpoint yet, produce the existing rows of the tables before streaming their changes.")
+	Main.Flags().BoolVar(&startFromCurrent, "start-from-current", startFromCurrent, "When there is no checkpoint yet, only produce the changes from the current position on. The first checkpoint is written with the first transaction, so the changes made until then are lost if vtcdc stops before it.")
+	Main.Flags().DurationVar(&retryDelay, "retry-delay", retryDelay, "How long to wait before restarting a failed VStream")
+
+	Main.MarkFlagRequired("server")
+	Main.MarkFlagRequired("name")
+	Main.MarkFlagRequired("brokers")
+	Main.MarkFlagRequired("keyspace")
+	Main.MarkFlagsMutuallyExclusive("copy", "start-from-current")
+
+	grpccommon.RegisterFlags(Main.Flags())
+	acl.RegisterFlags(Main.Flags())
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := logutil.NewConsoleLogger()
+	writer := logutil.NewLoggerWriter(logger)
+	cmd.SetOut(writer)
+	cmd.SetErr(writer)
+	_ = cmd.Flags().Set("logtostderr", "true")
+
+	servenv.Init()
+
+	tt, err := topoproto.ParseTabletType(tabletType)
+	if err != nil {
+		return err
+	}
+	// Without a starting position, the connector refuses to start unless
+	// there is a checkpoint.
+	var vgtid *binlogdatapb.VGtid
+	if copyTables || startFromCurrent {
+		gtid := "current"
+		if copyTables {
+			gtid = ""
+		}
+		vgtid = &binlogdatapb.VGtid{}
+		if len(shards) == 0 {
+			// vtgate streams all the shards of the keyspace.
+			vgtid.ShardGtids = append(vgtid.ShardGtids, &binlogdatapb.ShardGtid{Keyspace: keyspace, Gtid: gtid})
+		}
+		for _, shard := range shards {
+			vgtid.ShardGtids = append(vgtid.ShardGtids, &binlogdatapb.ShardGtid{Keyspace: keyspace, Shard: shard, Gtid: gtid})
+		}
+	}
+	filter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{Match: "/" + tableFilter}},
+	}
+
+	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	conn, err := vtgateconn.Dial(ctx, server)
+	if err != nil {
+		return fmt.Errorf("failed to connect to vtgate %s: %w", server, err)
+	}
+	defer conn.Close()
+	stream := func(ctx context.Context, vgtid *binlogdatapb.VGtid) (vtgateconn.VStreamReader, error) {
+		return conn.VStream(ctx, tt, vgtid, filter, &vtgatepb.VStreamFlags{})
+	}
+
+	broker, err := cdc.NewKafkaBroker(cdc.KafkaConfig{
+		Brokers:           brokers,
+		TransactionalID:   name,
+		ReplicationFactor: replicationFactor,
+	})
+	if err != nil {
+		return err
+	}
+	defer broker.Close()
+	connector, err := cdc.NewConnector(cdc.Config{
+		Name:            name,
+		TopicPrefix:     topicPrefix,
+		CheckpointTopic: checkpointTopic,
+		Format:          format,
+		VGtid:           vgtid,
+		RetryDelay:      retryDelay,
+	}, broker, stream)
+	if err != nil {
+		return err
+	}
+	return connector.Run(ctx)
+}
@@ -81,6 +81,9 @@ func run(cmd *cobra.Command, args []string) error {
 	// Start schema manager service.
 	initSchema(cmd.Context())
 
+	// Start the workflow scheduler.
+	initWorkflowScheduler(cmd.Context())
+
 	// And run the server.
 	servenv.RunDefault()
 
@@ -0,0 +1,59 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"time"
+
+	"vitess.io/vitess/go/timer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vtctl/workflow"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+)
+
+var workflowSchedulerInterval = 30 * time.Second
+
+func init() {
+	Main.Flags().DurationVar(&workflowSchedulerInterval, "workflow-scheduler-interval", workflowSchedulerInterval, "How often the queued vreplication workflows are checked and started when they are ready, and the next steps of the cutover plans of the workflows are run. The workflow scheduler is disabled when zero or lower.")
+}
+
+func initWorkflowScheduler(ctx context.Context) {
+	if workflowSchedulerInterval <= 0 {
+		return
+	}
+	ws := workflow.NewServer(env, ts, tmclient.NewTabletManagerClient())
+	timer := timer.NewTimer(workflowSchedulerInterval)
+	timer.Start(func() {
+		ctx, cancel := context.WithTimeout(ctx, workflowSchedulerInterval)
+		defer cancel()
+		if _, err := ws.ScheduleWorkflows(ctx); err != nil {
+			log.Errorf("Failed to schedule the queued workflows, error: %v", err)
+		}
+		steps, err := ws.RunCutoverPlans(ctx)
+		for wf, logs := range steps {
+			for _, step := range logs {
+				log.Infof("Cutover plan of the %s workflow: %s", wf, step)
+			}
+		}
+		if err != nil {
+			log.Errorf("Failed to run the cutover plans, error: %v", err)
+		}
+	})
+	servenv.OnClose(func() { timer.Stop() })
+}
@@ -76,6 +76,19 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandRestoreFromBackup,
 	}
+	// RestorePlan makes a RestorePlan gRPC call to a vtctld.
+	RestorePlan = &cobra.Command{
+		Use:   "RestorePlan [--restore-to-pos <pos>|--restore-to-timestamp <timestamp>] [--allowed-backup-engines=enginename,] <keyspace/shard>",
+		Short: "Lists the full and incremental backups a restore of the given shard would apply, without restoring anything.",
+		Long: `Lists the full and incremental backups a restore of the given shard would apply, without restoring anything.
+
+The output also includes the expected duration of the restore, based on how long each backup took, and the GTID gaps
+between the incremental backups that follow the full backup. A point in time recovery cannot reach a position within, or
+beyond, a gap.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandRestorePlan,
+	}
 )
 
 var backupOptions = struct {
@@ -169,8 +182,9 @@ func commandBackupShard(cmd *cobra.Command, args []string) error {
 }
 
 var getBackupsOptions = struct {
-	Limit      uint32
-	OutputJSON bool
+	Limit           uint32
+	OutputJSON      bool
+	SecondaryStatus bool
 }{}
 
 func commandGetBackups(cmd *cobra.Command, args []string) error {
@@ -182,9 +196,10 @@ func commandGetBackups(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	resp, err := client.GetBackups(commandCtx, &vtctldatapb.GetBackupsRequest{
-		Keyspace: keyspace,
-		Shard:    shard,
-		Limit:    getBackupsOptions.Limit,
+		Keyspace:        keyspace,
+		Shard:           shard,
+		Limit:           getBackupsOptions.Limit,
+		SecondaryStatus: getBackupsOptions.SecondaryStatus,
 	})
 	if err != nil {
 		return err
@@ -291,6 +306,53 @@ func commandRestoreFromBackup(cmd *cobra.Command, args []string) error {
 	}
 }
 
+var restorePlanOptions = struct {
+	AllowedBackupEngines []string
+	RestoreToPos         string
+	RestoreToTimestamp   string
+}{}
+
+func commandRestorePlan(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if restorePlanOptions.RestoreToPos != "" && restorePlanOptions.RestoreToTimestamp != "" {
+		return fmt.Errorf("--restore-to-pos and --restore-to-timestamp are mutually exclusive")
+	}
+
+	var restoreToTimestamp time.Time
+	if restorePlanOptions.RestoreToTimestamp != "" {
+		restoreToTimestamp, err = mysqlctl.ParseRFC3339(restorePlanOptions.RestoreToTimestamp)
+		if err != nil {
+			return err
+		}
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.RestorePlan(commandCtx, &vtctldatapb.RestorePlanRequest{
+		Keyspace:             keyspace,
+		Shard:                shard,
+		RestoreToPos:         restorePlanOptions.RestoreToPos,
+		RestoreToTimestamp:   protoutil.TimeToProto(restoreToTimestamp),
+		AllowedBackupEngines: restorePlanOptions.AllowedBackupEngines,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
 func init() {
 	Backup.Flags().BoolVar(&backupOptions.AllowPrimary, "allow-primary", false, "Allow the primary of a shard to be used for the backup. WARNING: If using the builtin backup engine, this will shutdown mysqld on the primary and stop writes for the duration of the backup.")
 	Backup.Flags().Int32Var(&backupOptions.Concurrency, "concurrency", 4, "Specifies the number of compression/checksum jobs to run simultaneously.")
@@ -310,6 +372,7 @@ func init() {
 
 	GetBackups.Flags().Uint32VarP(&getBackupsOptions.Limit, "limit", "l", 0, "Retrieve only the most recent N backups.")
 	GetBackups.Flags().BoolVarP(&getBackupsOptions.OutputJSON, "json", "j", false, "Output backup info in JSON format rather than a list of backups.")
+	GetBackups.Flags().BoolVar(&getBackupsOptions.SecondaryStatus, "secondary-status", false, "Include the status of the copies of the backups in the secondary backup storage. Only shown in JSON output.")
 	Root.AddCommand(GetBackups)
 
 	Root.AddCommand(RemoveBackup)
@@ -320,4 +383,9 @@ func init() {
 	RestoreFromBackup.Flags().StringVar(&restoreFromBackupOptions.RestoreToTimestamp, "restore-to-timestamp", "", "Run a point in time recovery that restores up to, and excluding, given timestamp in RFC3339 format (`2006-01-02T15:04:05Z07:00`). This will attempt to use one full backup followed by zero or more incremental backups")
 	RestoreFromBackup.Flags().BoolVar(&restoreFromBackupOptions.DryRun, "dry-run", false, "Only validate restore steps, do not actually restore data")
 	Root.AddCommand(RestoreFromBackup)
+
+	RestorePlan.Flags().StringSliceVar(&restorePlanOptions.AllowedBackupEngines, "allowed-backup-engines", restorePlanOptions.AllowedBackupEngines, "if set, only backups taken with the specified engines are eligible to be restored")
+	RestorePlan.Flags().StringVar(&restorePlanOptions.RestoreToPos, "restore-to-pos", "", "Plan a point in time recovery that ends with the given position.")
+	RestorePlan.Flags().StringVar(&restorePlanOptions.RestoreToTimestamp, "restore-to-timestamp", "", "Plan a point in time recovery that restores up to, and excluding, given timestamp in RFC3339 format (`2006-01-02T15:04:05Z07:00`).")
+	Root.AddCommand(RestorePlan)
 }
@@ -24,6 +24,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/json2"
 	"vitess.io/vitess/go/vt/topo"
 
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
@@ -54,6 +55,39 @@ var (
 		},
 		RunE: commandWriteTopologyPath,
 	}
+
+	// TopoExport makes a TopoExport gRPC call to a vtctld.
+	TopoExport = &cobra.Command{
+		Use:   "TopoExport [--keyspaces=ks1,ks2,...] [--cells=c1,c2,...] [--output-file <file>]",
+		Short: "Exports the topology records to a JSON snapshot.",
+		Long: `Exports the topology records to a JSON snapshot, which TopoImport can write back.
+
+The snapshot has the CellInfos, CellsAliases, Keyspaces, VSchemas, Shards, Tablets,
+routing rules, SrvKeyspaces and SrvVSchemas. The ShardReplication records are
+rebuilt from the Tablets on import.`,
+		Example: `TopoExport --output-file topo.json
+TopoExport --keyspaces commerce --cells zone1 > commerce.json`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandTopoExport,
+	}
+
+	// TopoImport makes a TopoImport gRPC call to a vtctld.
+	TopoImport = &cobra.Command{
+		Use:   "TopoImport [--keyspace <keyspace>] [--dry-run] <file>",
+		Short: "Writes the records of a snapshot from TopoExport which differ from the topology.",
+		Long: `Writes the records of a snapshot from TopoExport which differ from the topology,
+and displays the differences. The records which are only in the topology are
+reported as EXTRA, and are not deleted.
+
+The records are written without taking any lock: nothing else should change
+the topology during the import.`,
+		Example: `TopoImport --dry-run topo.json
+TopoImport --keyspace commerce commerce.json`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandTopoImport,
+	}
 )
 
 var getTopologyPathOptions = struct {
@@ -126,6 +160,74 @@ func commandWriteTopologyPath(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var topoExportOptions = struct {
+	Keyspaces  []string
+	Cells      []string
+	OutputFile string
+}{}
+
+func commandTopoExport(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.TopoExport(commandCtx, &vtctldatapb.TopoExportRequest{
+		Keyspaces: topoExportOptions.Keyspaces,
+		Cells:     topoExportOptions.Cells,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSONPretty(resp.Snapshot)
+	if err != nil {
+		return err
+	}
+
+	if topoExportOptions.OutputFile != "" {
+		return os.WriteFile(topoExportOptions.OutputFile, append(data, '\n'), 0o644)
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+var topoImportOptions = struct {
+	Keyspace string
+	DryRun   bool
+}{}
+
+func commandTopoImport(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	snapshot := &vtctldatapb.TopoSnapshot{}
+	if err := json2.UnmarshalPB(data, snapshot); err != nil {
+		return fmt.Errorf("invalid topo snapshot %s: %w", cmd.Flags().Arg(0), err)
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.TopoImport(commandCtx, &vtctldatapb.TopoImportRequest{
+		Snapshot: snapshot,
+		Keyspace: topoImportOptions.Keyspace,
+		DryRun:   topoImportOptions.DryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err = cli.MarshalJSONPretty(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
 func init() {
 	GetTopologyPath.Flags().Int64Var(&getTopologyPathOptions.version, "version", getTopologyPathOptions.version, "The version of the path's key to get. If not specified, the latest version is returned.")
 	GetTopologyPath.Flags().BoolVar(&getTopologyPathOptions.dataAsJSON, "data-as-json", getTopologyPathOptions.dataAsJSON, "If true, only the data is output and it is in JSON format rather than prototext.")
@@ -133,4 +235,13 @@ func init() {
 
 	WriteTopologyPath.Flags().StringVar(&writeTopologyPathOptions.cell, "cell", topo.GlobalCell, "Topology server cell to copy the file to.")
 	Root.AddCommand(WriteTopologyPath)
+
+	TopoExport.Flags().StringSliceVar(&topoExportOptions.Keyspaces, "keyspaces", nil, "Keyspaces to export. All keyspaces are exported if empty.")
+	TopoExport.Flags().StringSliceVar(&topoExportOptions.Cells, "cells", nil, "Cells to export the tablets, SrvKeyspaces and SrvVSchemas of. All cells are exported if empty.")
+	TopoExport.Flags().StringVar(&topoExportOptions.OutputFile, "output-file", "", "File to write the snapshot to, instead of stdout.")
+	Root.AddCommand(TopoExport)
+
+	TopoImport.Flags().StringVar(&topoImportOptions.Keyspace, "keyspace", "", "Only import the records of this keyspace.")
+	TopoImport.Flags().BoolVar(&topoImportOptions.DryRun, "dry-run", false, "Only display the differences, without writing anything.")
+	Root.AddCommand(TopoImport)
 }
@@ -27,6 +27,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/ptr"
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
@@ -73,6 +75,23 @@ var (
 		ReferenceTables              []string
 		ConfigOverrides              []string
 	}{}
+
+	ScheduleOptions = struct {
+		Queue                  bool
+		Priority               int32
+		DependsOn              []string
+		MaxConcurrentWorkflows int32
+	}{}
+
+	CutoverPlanOptions = struct {
+		Enabled                  bool
+		MaxReplicationLagAllowed time.Duration
+		RequireVDiff             bool
+		WriteWindows             []string
+		RollbackWindow           time.Duration
+		MaxErrorRate             float64
+		AutoComplete             bool
+	}{}
 )
 
 var commandHandlers = make(map[string]func(cmd *cobra.Command))
@@ -298,13 +317,71 @@ func AddCommonCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&CreateOptions.AllCells, "all-cells", "a", false, "Copy table data from any existing cell.")
 	cmd.Flags().Var((*topoproto.TabletTypeListFlag)(&CreateOptions.TabletTypes), "tablet-types", "Source tablet types to replicate table data from (e.g. PRIMARY,REPLICA,RDONLY).")
 	cmd.Flags().BoolVar(&CreateOptions.TabletTypesInPreferenceOrder, "tablet-types-in-preference-order", true, "When performing source tablet selection, look for candidates in the type order as they are listed in the tablet-types flag.")
-	cmd.Flags().StringVar(&CreateOptions.OnDDL, "on-ddl", onDDLDefault, "What to do when DDL is encountered in the VReplication stream. Possible values are IGNORE, STOP, EXEC, and EXEC_IGNORE.")
+	cmd.Flags().StringVar(&CreateOptions.OnDDL, "on-ddl", onDDLDefault, "What to do when DDL is encountered in the VReplication stream. Possible values are IGNORE, STOP, EXEC, EXEC_IGNORE, and EXEC_SAFE.")
 	cmd.Flags().BoolVar(&CreateOptions.DeferSecondaryKeys, "defer-secondary-keys", true, "Defer secondary index creation for a table until after it has been copied.")
 	cmd.Flags().BoolVar(&CreateOptions.AutoStart, "auto-start", true, "Start the workflow after creating it.")
 	cmd.Flags().BoolVar(&CreateOptions.StopAfterCopy, "stop-after-copy", false, "Stop the workflow after it's finished copying the existing rows and before it starts replicating changes.")
 	cmd.Flags().StringSliceVar(&CreateOptions.ConfigOverrides, "config-overrides", []string{}, "Specify one or more VReplication config flags to override as a comma-separated list of key=value pairs.")
 }
 
+// AddCommonScheduleFlags adds the flags used to queue a workflow, rather than
+// start it, so that it is started by the vtctld workflow scheduler.
+func AddCommonScheduleFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&ScheduleOptions.Queue, "queue", false, "Queue the workflow rather than start it, so that it is started by the vtctld workflow scheduler. This is implied by --priority, --depends-on and --max-concurrent-workflows.")
+	cmd.Flags().Int32Var(&ScheduleOptions.Priority, "priority", 0, "Priority of the queued workflow. Queued workflows with a higher priority are started first.")
+	cmd.Flags().StringSliceVar(&ScheduleOptions.DependsOn, "depends-on", nil, "Workflows, as keyspace.workflow, whose copy phase must be done before the queued workflow is started.")
+	cmd.Flags().Int32Var(&ScheduleOptions.MaxConcurrentWorkflows, "max-concurrent-workflows", 1, "Maximum number of workflows copying from each source shard of the queued workflow at the same time. The queued workflow is started once fewer workflows than this are copying from all of its source shards. Zero means no limit.")
+}
+
+// GetWorkflowSchedule returns the schedule of the workflow when it is queued,
+// or nil otherwise.
+func GetWorkflowSchedule(cmd *cobra.Command) *vtctldatapb.WorkflowSchedule {
+	maxConcurrentWorkflowsSet := cmd.Flags().Lookup("max-concurrent-workflows").Changed
+	if !ScheduleOptions.Queue && ScheduleOptions.Priority == 0 && len(ScheduleOptions.DependsOn) == 0 && !maxConcurrentWorkflowsSet {
+		return nil
+	}
+	return &vtctldatapb.WorkflowSchedule{
+		Priority:               ScheduleOptions.Priority,
+		DependsOn:              ScheduleOptions.DependsOn,
+		MaxConcurrentWorkflows: ptr.Of(ScheduleOptions.MaxConcurrentWorkflows),
+	}
+}
+
+// AddCommonCutoverPlanFlags adds the flags used to attach a cutover plan to a
+// workflow, so that its traffic is switched by the vtctld workflow scheduler.
+func AddCommonCutoverPlanFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&CutoverPlanOptions.Enabled, "cutover-plan", false, "Switch the traffic of the workflow, and optionally complete it, from the vtctld workflow scheduler once its copy phase is done, as tuned by the other --cutover-* flags.")
+	cmd.Flags().DurationVar(&CutoverPlanOptions.MaxReplicationLagAllowed, "cutover-max-replication-lag-allowed", MaxReplicationLagDefault, "The maximum vreplication lag allowed when the cutover plan switches traffic.")
+	cmd.Flags().BoolVar(&CutoverPlanOptions.RequireVDiff, "cutover-require-vdiff", true, "Require a VDiff which found no differences before the cutover plan switches traffic. A VDiff is created when the workflow has none.")
+	cmd.Flags().StringArrayVar(&CutoverPlanOptions.WriteWindows, "cutover-write-window", nil, "Maintenance window, in UTC, within which the cutover plan can switch the write traffic, such as \"Sat,Sun 02:00 4h\" or \"22:30 90m\". Can be repeated. The write traffic can be switched at any time when there are none.")
+	cmd.Flags().DurationVar(&CutoverPlanOptions.RollbackWindow, "cutover-rollback-window", 10*time.Minute, "How long the error rate of the queries served by the target primary tablets is watched once the cutover plan has switched the write traffic.")
+	cmd.Flags().Float64Var(&CutoverPlanOptions.MaxErrorRate, "cutover-max-error-rate", 0.05, "The error rate, between 0 and 1, of the queries served by the target primary tablets above which the cutover plan switches the traffic back, and stops the workflow, within the rollback window. Zero means no rollback.")
+	cmd.Flags().BoolVar(&CutoverPlanOptions.AutoComplete, "cutover-auto-complete", false, "Complete the workflow once the rollback window of the cutover plan is over.")
+}
+
+// GetCutoverPlan returns the cutover plan of the workflow when one is
+// requested, or nil otherwise.
+func GetCutoverPlan() (*vtctldatapb.CutoverPlan, error) {
+	if !CutoverPlanOptions.Enabled {
+		return nil, nil
+	}
+	plan := &vtctldatapb.CutoverPlan{
+		MaxReplicationLagAllowed: protoutil.DurationToProto(CutoverPlanOptions.MaxReplicationLagAllowed),
+		RequireVdiff:             CutoverPlanOptions.RequireVDiff,
+		RollbackWindow:           protoutil.DurationToProto(CutoverPlanOptions.RollbackWindow),
+		MaxErrorRate:             CutoverPlanOptions.MaxErrorRate,
+		AutoComplete:             CutoverPlanOptions.AutoComplete,
+	}
+	for _, window := range CutoverPlanOptions.WriteWindows {
+		mw, err := workflow.ParseMaintenanceWindow(window)
+		if err != nil {
+			return nil, err
+		}
+		plan.WriteWindows = append(plan.WriteWindows, mw)
+	}
+	return plan, nil
+}
+
 var MirrorTrafficOptions = struct {
 	DryRun      bool
 	Percent     float32
@@ -122,6 +122,10 @@ func commandCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	createOptions.WorkflowOptions.Config = configOverrides
+	createOptions.WorkflowOptions.Schedule = common.GetWorkflowSchedule(cmd)
+	if createOptions.WorkflowOptions.CutoverPlan, err = common.GetCutoverPlan(); err != nil {
+		return err
+	}
 
 	req := &vtctldatapb.MoveTablesCreateRequest{
 		Workflow:                  common.BaseOptions.Workflow,
@@ -49,6 +49,8 @@ func registerCommands(root *cobra.Command) {
 	root.AddCommand(base)
 
 	common.AddCommonCreateFlags(create)
+	common.AddCommonScheduleFlags(create)
+	common.AddCommonCutoverPlanFlags(create)
 	create.PersistentFlags().StringVar(&createOptions.SourceKeyspace, "source-keyspace", "", "Keyspace where the tables are being moved from.")
 	create.MarkPersistentFlagRequired("source-keyspace")
 	create.Flags().StringSliceVar(&createOptions.SourceShards, "source-shards", nil, "Source shards to copy data from when performing a partial MoveTables (experimental).")
@@ -63,8 +63,14 @@ func commandReshardCreate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	cutoverPlan, err := common.GetCutoverPlan()
+	if err != nil {
+		return err
+	}
 	workflowOptions := &vtctldatapb.WorkflowOptions{
-		Config: configOverrides,
+		Config:      configOverrides,
+		Schedule:    common.GetWorkflowSchedule(cmd),
+		CutoverPlan: cutoverPlan,
 	}
 
 	req := &vtctldatapb.ReshardCreateRequest{
@@ -94,6 +100,8 @@ func commandReshardCreate(cmd *cobra.Command, args []string) error {
 
 func registerCreateCommand(root *cobra.Command) {
 	common.AddCommonCreateFlags(reshardCreate)
+	common.AddCommonScheduleFlags(reshardCreate)
+	common.AddCommonCutoverPlanFlags(reshardCreate)
 	reshardCreate.Flags().StringSliceVar(&reshardCreateOptions.sourceShards, "source-shards", nil, "Source shards.")
 	reshardCreate.Flags().StringSliceVar(&reshardCreateOptions.targetShards, "target-shards", nil, "Target shards.")
 	reshardCreate.Flags().BoolVar(&reshardCreateOptions.skipSchemaCopy, "skip-schema-copy", false, "Skip copying the schema from the source shards to the target shards.")
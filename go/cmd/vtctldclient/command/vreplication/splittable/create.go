@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package splittable
+
+import (
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	createOptions = struct {
+		SourceKeyspace string
+		Table          string
+		Vindex         string
+		VindexColumn   string
+		ColumnMapping  map[string]string
+		NoRoutingRules bool
+	}{}
+
+	// create makes a SplitTableCreate gRPC call to a vtctld.
+	create = &cobra.Command{
+		Use:   "create",
+		Short: "Create and optionally run a SplitTable VReplication workflow.",
+		Long: `SplitTable moves a single table from the source keyspace into the target keyspace, using the
+given primary vindex for the table when the target keyspace is sharded and renaming the columns
+in the column mapping. The workflow is a MoveTables workflow: its traffic is switched and reversed
+with the switchtraffic and reversetraffic commands, which also replicate the renamed columns back
+to the source keyspace, and it is finished with the complete command.`,
+		Example:               `vtctldclient --server localhost:15999 splittable --workflow commerce2customer --target-keyspace customer create --source-keyspace commerce --table customer --vindex xxhash --vindex-column customer_id --column-mapping email=contact_email`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Create"},
+		Args:                  cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return common.ParseAndValidateCreateOptions(cmd)
+		},
+		RunE: commandCreate,
+	}
+)
+
+func commandCreate(cmd *cobra.Command, args []string) error {
+	format, err := common.GetOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	tsp := common.GetTabletSelectionPreference(cmd)
+	cli.FinishedParsing(cmd)
+
+	configOverrides, err := common.ParseConfigOverrides(common.CreateOptions.ConfigOverrides)
+	if err != nil {
+		return err
+	}
+	cutoverPlan, err := common.GetCutoverPlan()
+	if err != nil {
+		return err
+	}
+
+	req := &vtctldatapb.SplitTableCreateRequest{
+		Workflow:                  common.BaseOptions.Workflow,
+		TargetKeyspace:            common.BaseOptions.TargetKeyspace,
+		SourceKeyspace:            createOptions.SourceKeyspace,
+		Table:                     createOptions.Table,
+		Vindex:                    createOptions.Vindex,
+		VindexColumn:              createOptions.VindexColumn,
+		ColumnMapping:             createOptions.ColumnMapping,
+		Cells:                     common.CreateOptions.Cells,
+		TabletTypes:               common.CreateOptions.TabletTypes,
+		TabletSelectionPreference: tsp,
+		OnDdl:                     common.CreateOptions.OnDDL,
+		DeferSecondaryKeys:        common.CreateOptions.DeferSecondaryKeys,
+		AutoStart:                 common.CreateOptions.AutoStart,
+		StopAfterCopy:             common.CreateOptions.StopAfterCopy,
+		NoRoutingRules:            createOptions.NoRoutingRules,
+		WorkflowOptions: &vtctldatapb.WorkflowOptions{
+			// As with MoveTables, MySQL auto_increment clauses are removed when
+			// moving the table into a sharded keyspace.
+			ShardedAutoIncrementHandling: vtctldatapb.ShardedAutoIncrementHandling_REMOVE,
+			Config:                       configOverrides,
+			Schedule:                     common.GetWorkflowSchedule(cmd),
+			CutoverPlan:                  cutoverPlan,
+		},
+	}
+
+	resp, err := common.GetClient().SplitTableCreate(common.GetCommandCtx(), req)
+	if err != nil {
+		return err
+	}
+	if err = common.OutputStatusResponse(resp, format); err != nil {
+		return err
+	}
+	return nil
+}
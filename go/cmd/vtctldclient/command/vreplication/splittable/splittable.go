@@ -0,0 +1,86 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package splittable
+
+import (
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
+)
+
+var (
+	// base is the base command for all actions related to SplitTable.
+	base = &cobra.Command{
+		Use:                   "SplitTable --workflow <workflow> --target-keyspace <keyspace> [command] [command-flags]",
+		Short:                 "Perform commands related to moving a table into a keyspace with a new primary vindex and renamed columns.",
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"splittable"},
+		Args:                  cobra.ExactArgs(1),
+	}
+)
+
+func registerCommands(root *cobra.Command) {
+	common.AddCommonFlags(base)
+	root.AddCommand(base)
+
+	common.AddCommonCreateFlags(create)
+	common.AddCommonScheduleFlags(create)
+	common.AddCommonCutoverPlanFlags(create)
+	create.PersistentFlags().StringVar(&createOptions.SourceKeyspace, "source-keyspace", "", "Keyspace where the table is being moved from.")
+	create.MarkPersistentFlagRequired("source-keyspace")
+	create.Flags().StringVar(&createOptions.Table, "table", "", "Source table to move.")
+	create.MarkFlagRequired("table")
+	create.Flags().StringVar(&createOptions.Vindex, "vindex", "", "Name of the primary vindex of the table in a sharded target keyspace. If the target vschema has no vindex with this name, one is created using the name as its type (e.g. xxhash).")
+	create.Flags().StringVar(&createOptions.VindexColumn, "vindex-column", "", "Column of the target table that the primary vindex is defined on.")
+	create.Flags().StringToStringVar(&createOptions.ColumnMapping, "column-mapping", nil, "Columns to rename in the target table, as source_column=target_column pairs.")
+	create.Flags().BoolVar(&createOptions.NoRoutingRules, "no-routing-rules", false, "(Advanced) Do not create routing rules while creating the workflow. See the reference documentation for limitations if you use this flag.")
+	base.AddCommand(create)
+
+	opts := &common.SubCommandsOpts{
+		SubCommand: "SplitTable",
+		Workflow:   "commerce2customer",
+	}
+	base.AddCommand(common.GetShowCommand(opts))
+	base.AddCommand(common.GetStatusCommand(opts))
+
+	base.AddCommand(common.GetStartCommand(opts))
+	base.AddCommand(common.GetStopCommand(opts))
+
+	switchTrafficCommand := common.GetSwitchTrafficCommand(opts)
+	common.AddCommonSwitchTrafficFlags(switchTrafficCommand, true)
+	base.AddCommand(switchTrafficCommand)
+
+	reverseTrafficCommand := common.GetReverseTrafficCommand(opts)
+	common.AddCommonSwitchTrafficFlags(reverseTrafficCommand, false)
+	base.AddCommand(reverseTrafficCommand)
+
+	complete := common.GetCompleteCommand(opts)
+	complete.Flags().BoolVar(&common.CompleteOptions.KeepData, "keep-data", false, "Keep the original source table data that was copied by the SplitTable workflow.")
+	complete.Flags().BoolVar(&common.CompleteOptions.KeepRoutingRules, "keep-routing-rules", false, "Keep the routing rules in place that direct table traffic from the source keyspace to the target keyspace of the SplitTable workflow.")
+	complete.Flags().BoolVar(&common.CompleteOptions.RenameTables, "rename-tables", false, "Keep the original source table data that was copied by the SplitTable workflow, but rename the table to '_<tablename>_old'.")
+	complete.Flags().BoolVar(&common.CompleteOptions.DryRun, "dry-run", false, "Print the actions that would be taken and report any known errors that would have occurred.")
+	base.AddCommand(complete)
+
+	cancel := common.GetCancelCommand(opts)
+	cancel.Flags().BoolVar(&common.CancelOptions.KeepData, "keep-data", false, "Keep the partially copied table data from the SplitTable workflow in the target keyspace.")
+	cancel.Flags().BoolVar(&common.CancelOptions.KeepRoutingRules, "keep-routing-rules", false, "Keep the routing rules created for the SplitTable workflow.")
+	base.AddCommand(cancel)
+}
+
+func init() {
+	common.RegisterCommandHandler("SplitTable", registerCommands)
+}
@@ -69,6 +69,9 @@ var (
 		MaxDiffDuration             time.Duration
 		RowDiffColumnTruncateAt     int64
 		AutoStart                   bool
+		MaxConcurrentTables         int64
+		MaxTableRanges              int64
+		MaxConcurrentTableRanges    int64
 	}{}
 
 	deleteOptions = struct {
@@ -127,6 +130,15 @@ var (
 		if createOptions.MaxExtraRowsToCompare < 0 {
 			return fmt.Errorf("--max-extra-rows-to-compare must not be a negative value")
 		}
+		if createOptions.MaxConcurrentTables < 0 {
+			return fmt.Errorf("--max-concurrent-tables must not be a negative value")
+		}
+		if createOptions.MaxTableRanges < 0 {
+			return fmt.Errorf("--max-table-ranges must not be a negative value")
+		}
+		if createOptions.MaxConcurrentTableRanges < 0 {
+			return fmt.Errorf("--max-concurrent-table-ranges must not be a negative value")
+		}
 		return nil
 	}
 
@@ -300,6 +312,9 @@ func commandCreate(cmd *cobra.Command, args []string) error {
 		MaxDiffDuration:             protoutil.DurationToProto(createOptions.MaxDiffDuration),
 		RowDiffColumnTruncateAt:     createOptions.RowDiffColumnTruncateAt,
 		AutoStart:                   &createOptions.AutoStart,
+		MaxConcurrentTables:         createOptions.MaxConcurrentTables,
+		MaxTableRanges:              createOptions.MaxTableRanges,
+		MaxConcurrentTableRanges:    createOptions.MaxConcurrentTableRanges,
 	})
 
 	if err != nil {
@@ -683,6 +698,9 @@ func registerCommands(root *cobra.Command) {
 	create.Flags().DurationVar(&createOptions.MaxDiffDuration, "max-diff-duration", 0, "How long should an individual table diff run before being stopped and restarted in order to lessen the impact on tablets due to holding open database snapshots for long periods of time (0 is the default and means no time limit).")
 	create.Flags().Int64Var(&createOptions.RowDiffColumnTruncateAt, "row-diff-column-truncate-at", 128, "When showing row differences, truncate the non Primary Key column values to this length. A value less than 1 means do not truncate.")
 	create.Flags().BoolVar(&createOptions.AutoStart, "auto-start", true, "Start the vdiff upon creation. When false, the vdiff will be created but will not run until resumed.")
+	create.Flags().Int64Var(&createOptions.MaxConcurrentTables, "max-concurrent-tables", 1, "The maximum number of tables to diff at the same time on each target shard. Each table diff holds open its own database snapshots on the source and target tablets.")
+	create.Flags().Int64Var(&createOptions.MaxTableRanges, "max-table-ranges", 1, "The maximum number of primary key ranges to split each large table into on each target shard. The ranges of a table are diffed at the same time, each holding open its own database snapshots on the source and target tablets.")
+	create.Flags().Int64Var(&createOptions.MaxConcurrentTableRanges, "max-concurrent-table-ranges", 0, "The maximum number of primary key ranges of a table to diff at the same time on each target shard. Zero diffs all of the ranges of a table at the same time.")
 	base.AddCommand(create)
 
 	base.AddCommand(delete)
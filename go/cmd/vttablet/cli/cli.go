@@ -171,6 +171,8 @@ func run(cmd *cobra.Command, args []string) error {
 		VREngine:            vreplication.NewEngine(env, config, ts, tabletAlias.Cell, mysqld, qsc.LagThrottler()),
 		SemiSyncMonitor:     semisyncmonitor.NewMonitor(config, qsc.Exporter()),
 		VDiffEngine:         vdiff.NewEngine(ts, tablet, env.CollationEnv(), env.Parser()),
+		BinlogArchiver:      mysqlctl.NewBinlogArchiver(mycnf, mysqld, tablet),
+		LagThrottler:        qsc.LagThrottler(),
 	}
 	if err := tm.Start(tablet, config); err != nil {
 		return fmt.Errorf("failed to parse --tablet-path or initialize DB credentials: %w", err)
@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/netutil"
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vttopo"
+)
+
+var (
+	advertiseAddress string
+	raftAddress      string
+	dataDir          string
+	bootstrapPeers   []string
+
+	peerCert       string
+	peerKey        string
+	peerCA         string
+	peerServerName string
+
+	Main = &cobra.Command{
+		Use:   "vttopo",
+		Short: "vttopo is a topology server for Vitess, which replicates its data among a few nodes with Raft.",
+		Long: "`vttopo` is a topology server for small deployments which don't want to run etcd, ZooKeeper or Consul.\n" +
+			"A cluster is made of 3 or 5 `vttopo` nodes, which replicate their data with Raft, and serve it over gRPC.\n" +
+			"Vitess components use it with `--topo_implementation raft`, and the comma separated list of the gRPC addresses of the nodes as the server address.\n\n" +
+			"The nodes of a new cluster are started with `--bootstrap-peers`, which lists all of them. The flag is ignored once a node has some state.",
+		Example: `vttopo \
+	--grpc_port 15999 \
+	--raft-address 10.0.0.1:16000 \
+	--data-dir ${VTDATAROOT}/vttopo \
+	--bootstrap-peers 10.0.0.1:15999=10.0.0.1:16000,10.0.0.2:15999=10.0.0.2:16000,10.0.0.3:15999=10.0.0.3:16000`,
+		Args:    cobra.NoArgs,
+		Version: servenv.AppVersion.String(),
+		PreRunE: servenv.CobraPreRunE,
+		RunE:    run,
+	}
+)
+
+// parsePeers parses the --bootstrap-peers flag, as a map of the
+// advertise addresses of the nodes to their Raft addresses.
+func parsePeers(peers []string) (map[string]string, error) {
+	result := make(map[string]string, len(peers))
+	for _, peer := range peers {
+		id, address, ok := strings.Cut(peer, "=")
+		if !ok || id == "" || address == "" {
+			return nil, fmt.Errorf("invalid peer %q, expected <advertise address>=<raft address>", peer)
+		}
+		result[id] = address
+	}
+	return result, nil
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	servenv.Init()
+
+	if servenv.GRPCPort() == 0 {
+		return fmt.Errorf("--grpc_port is required")
+	}
+	if raftAddress == "" {
+		return fmt.Errorf("--raft-address is required")
+	}
+	if advertiseAddress == "" {
+		advertiseAddress = netutil.JoinHostPort(netutil.FullyQualifiedHostnameOrPanic(), int32(servenv.GRPCPort()))
+	}
+	peers, err := parsePeers(bootstrapPeers)
+	if err != nil {
+		return err
+	}
+	dialOption, err := grpcclient.SecureDialOption(peerCert, peerKey, peerCA, "", peerServerName)
+	if err != nil {
+		return err
+	}
+
+	server, err := vttopo.NewServer(vttopo.Options{
+		ID:          advertiseAddress,
+		RaftAddress: raftAddress,
+		DataDir:     dataDir,
+		DialOption:  dialOption,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start the node: %w", err)
+	}
+	if len(peers) > 0 {
+		if err := server.Bootstrap(peers); err != nil {
+			server.Close()
+			return fmt.Errorf("failed to bootstrap the cluster: %w", err)
+		}
+	}
+	log.Infof("vttopo node %v started, Raft address %v", advertiseAddress, server.RaftAddress())
+
+	servenv.OnRun(func() {
+		server.Register(servenv.GRPCServer)
+	})
+	servenv.OnClose(func() {
+		if err := server.Close(); err != nil {
+			log.Errorf("failed to stop the node: %v", err)
+		}
+	})
+	servenv.RunDefault()
+	return nil
+}
+
+func init() {
+	servenv.RegisterDefaultFlags()
+	servenv.RegisterFlags()
+	servenv.RegisterGRPCServerFlags()
+	servenv.RegisterGRPCServerAuthFlags()
+
+	servenv.MoveFlagsToCobraCommand(Main)
+
+	Main.Flags().StringVar(&advertiseAddress, "advertise-address", advertiseAddress, "Address of the gRPC service of this node, used by the clients and the other nodes. It identifies the node in the cluster. Defaults to the hostname and the gRPC port.")
+	Main.Flags().StringVar(&raftAddress, "raft-address", raftAddress, "Address the Raft transport of this node listens on, and is reached at by the other nodes.")
+	Main.Flags().StringVar(&dataDir, "data-dir", dataDir, "Directory to store the Raft log and snapshots in. If empty, the data is only kept in memory.")
+	Main.Flags().StringSliceVar(&bootstrapPeers, "bootstrap-peers", bootstrapPeers, "Nodes of a new cluster, as <advertise address>=<raft address>, including this node. Ignored if the node already has some state.")
+	Main.Flags().StringVar(&peerCert, "peer-grpc-cert", peerCert, "The cert to use to connect to the other nodes.")
+	Main.Flags().StringVar(&peerKey, "peer-grpc-key", peerKey, "The key to use to connect to the other nodes.")
+	Main.Flags().StringVar(&peerCA, "peer-grpc-ca", peerCA, "The server ca to use to validate the other nodes when connecting.")
+	Main.Flags().StringVar(&peerServerName, "peer-grpc-server-name", peerServerName, "The server name to use to validate the other nodes' certificates.")
+
+	acl.RegisterFlags(Main.Flags())
+}
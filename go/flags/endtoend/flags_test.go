@@ -59,6 +59,9 @@ var (
 	//go:embed vttlstest.txt
 	vttlstestTxt string
 
+	//go:embed vttopo.txt
+	vttopoTxt string
+
 	//go:embed vtctld.txt
 	vtctldTxt string
 
@@ -83,6 +86,9 @@ var (
 	//go:embed vtbackup.txt
 	vtbackupTxt string
 
+	//go:embed vtcdc.txt
+	vtcdcTxt string
+
 	//go:embed zkctl.txt
 	zkctlTxt string
 
@@ -95,6 +101,7 @@ var (
 		"topo2topo":        topo2topoTxt,
 		"vtaclcheck":       vtaclcheckTxt,
 		"vtbackup":         vtbackupTxt,
+		"vtcdc":            vtcdcTxt,
 		"vtcombo":          vtcomboTxt,
 		"vtctlclient":      vtctlclientTxt,
 		"vtctld":           vtctldTxt,
@@ -106,6 +113,7 @@ var (
 		"vttablet":         vttabletTxt,
 		"vttestserver":     vttestserverTxt,
 		"vttlstest":        vttlstestTxt,
+		"vttopo":           vttopoTxt,
 		"zk":               zkTxt,
 		"zkctl":            zkctlTxt,
 		"zkctld":           zkctldTxt,
@@ -22,8 +22,12 @@ import (
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/subtle"
+	"crypto/x509"
 	"encoding/hex"
+	"encoding/pem"
+	"fmt"
 	"net"
+	"os"
 	"sync"
 
 	"vitess.io/vitess/go/mysql/sqlerror"
@@ -226,16 +230,10 @@ func NewMysqlDialogAuthMethod(layer PlainTextStorage, validator UserValidator, m
 // be called if the return of the first layer indicates the full auth dance is
 // needed.
 //
-// Right now we only support caching_sha2_password over TLS or a Unix socket.
-//
-// If TLS is not enabled, the client needs to encrypt it with the public
-// key of the server. In that case, Vitess is already configured with
-// a certificate anyway, so we recommend to use TLS if you want to use
-// caching_sha2_password in that case instead of allowing the plain
-// text fallback path here.
-//
-// This might change in the future if there's a good argument and implementation
-// for allowing the plain text path here as well.
+// The full auth dance is supported over TLS or a Unix socket, where the
+// client sends the password in plain text. On other connections, it is only
+// supported if the Listener has an RSAKeyPair, which the client uses to
+// encrypt the password. The plain text fallback path is never allowed.
 func NewSha2CachingAuthMethod(layer1 CachingStorage, layer2 PlainTextStorage, validator UserValidator) AuthMethod {
 	authMethod := mysqlCachingSha2AuthMethod{
 		cache:     layer1,
@@ -401,6 +399,115 @@ func EncryptPasswordWithPublicKey(salt []byte, password []byte, pub *rsa.PublicK
 	return enc, nil
 }
 
+// DecryptPasswordWithPrivateKey decrypts a password encrypted by
+// EncryptPasswordWithPublicKey with the server's private key, as required by
+// the server side of caching_sha2_password plugin for "full" authentication
+func DecryptPasswordWithPrivateKey(salt []byte, enc []byte, priv *rsa.PrivateKey) ([]byte, error) {
+	buffer, err := rsa.DecryptOAEP(sha1.New(), nil, priv, enc, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range buffer {
+		buffer[i] ^= salt[i%len(salt)]
+	}
+
+	// The password is encrypted as a zero terminated string.
+	if len(buffer) == 0 || buffer[len(buffer)-1] != 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "received invalid encrypted password, datalen=%v", len(buffer))
+	}
+
+	return buffer[:len(buffer)-1], nil
+}
+
+// RSAKeyPair is the RSA key pair of a server, which clients use to encrypt
+// the password for the full authentication of caching_sha2_password on
+// connections without TLS.
+type RSAKeyPair struct {
+	privateKey   *rsa.PrivateKey
+	publicKeyPEM []byte
+}
+
+// NewRSAKeyPair returns the RSAKeyPair of a private key.
+func NewRSAKeyPair(privateKey *rsa.PrivateKey) (*RSAKeyPair, error) {
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RSAKeyPair{
+		privateKey:   privateKey,
+		publicKeyPEM: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}),
+	}, nil
+}
+
+// LoadRSAKeyPair reads an RSAKeyPair from PEM files, like the
+// caching_sha2_password_private_key_path and caching_sha2_password_public_key_path
+// options of MySQL. The private key can be in PKCS #1 or PKCS #8 form. The
+// public key file is optional, as the public key is derived from the private
+// key. If it is set, it must hold the public key of the private key.
+func LoadRSAKeyPair(privateKeyFile, publicKeyFile string) (*RSAKeyPair, error) {
+	data, err := os.ReadFile(privateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", privateKeyFile)
+	}
+
+	var privateKey *rsa.PrivateKey
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		privateKey = key
+	} else {
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key in %s: %w", privateKeyFile, err)
+		}
+
+		var ok bool
+		if privateKey, ok = key.(*rsa.PrivateKey); !ok {
+			return nil, fmt.Errorf("private key in %s is not an RSA key", privateKeyFile)
+		}
+	}
+
+	keys, err := NewRSAKeyPair(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if publicKeyFile == "" {
+		return keys, nil
+	}
+
+	data, err = os.ReadFile(publicKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ = pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", publicKeyFile)
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key in %s: %w", publicKeyFile, err)
+	}
+
+	if !privateKey.PublicKey.Equal(publicKey) {
+		return nil, fmt.Errorf("public key in %s does not match private key in %s", publicKeyFile, privateKeyFile)
+	}
+
+	return keys, nil
+}
+
+// PublicKeyPEM returns the public key in PEM form, as it is sent to clients.
+func (k *RSAKeyPair) PublicKeyPEM() []byte {
+	return k.publicKeyPEM
+}
+
 type mysqlNativePasswordAuthMethod struct {
 	storage   HashStorage
 	validator UserValidator
@@ -500,7 +607,7 @@ func (n *mysqlCachingSha2AuthMethod) Name() AuthMethodDescription {
 }
 
 func (n *mysqlCachingSha2AuthMethod) HandleUser(conn *Conn, user string) bool {
-	if !conn.TLSEnabled() && !conn.IsUnixSocket() {
+	if !conn.TLSEnabled() && !conn.IsUnixSocket() && conn.rsaKeyPair() == nil {
 		return false
 	}
 	return n.validator.HandleUser(user)
@@ -546,16 +653,25 @@ func (n *mysqlCachingSha2AuthMethod) HandleAuthPluginData(c *Conn, user string,
 		}
 		return result, nil
 	case AuthNeedMoreData:
-		if !c.TLSEnabled() && !c.IsUnixSocket() {
+		secure := c.TLSEnabled() || c.IsUnixSocket()
+		keys := c.rsaKeyPair()
+		if !secure && keys == nil {
 			return nil, sqlerror.NewSQLErrorf(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Access denied for user '%v'", user)
 		}
 
 		data, pos := c.startEphemeralPacketWithHeader(2)
 		pos = writeByte(data, pos, AuthMoreDataPacket)
 		writeByte(data, pos, CachingSha2FullAuth)
-		c.writeEphemeralPacket()
+		if err := c.writeEphemeralPacket(); err != nil {
+			return nil, err
+		}
 
-		password, err := readPacketPasswordString(c)
+		var password string
+		if secure {
+			password, err = readPacketPasswordString(c)
+		} else {
+			password, err = readPacketEncryptedPassword(c, salt, keys)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -632,3 +748,41 @@ func readPacketPasswordString(c *Conn) (string, error) {
 	}
 	return string(data[:len(data)-1]), nil
 }
+
+// readPacketEncryptedPassword reads the password of the caching_sha2_password
+// full auth dance on a connection without TLS. The client may first request
+// the public key of the server, unless it already knows it, before sending the
+// password encrypted with it.
+func readPacketEncryptedPassword(c *Conn, salt []byte, keys *RSAKeyPair) (string, error) {
+	data, err := c.ReadPacket()
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) == 1 && data[0] == CachingSha2RequestPublicKey {
+		pub := keys.PublicKeyPEM()
+		reply, pos := c.startEphemeralPacketWithHeader(1 + len(pub))
+		pos = writeByte(reply, pos, AuthMoreDataPacket)
+		copy(reply[pos:], pub)
+		if err := c.writeEphemeralPacket(); err != nil {
+			return "", err
+		}
+
+		data, err = c.ReadPacket()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Clients send nothing for an empty password.
+	if len(data) == 0 {
+		return "", nil
+	}
+
+	password, err := DecryptPasswordWithPrivateKey(salt, data, keys.privateKey)
+	if err != nil {
+		return "", sqlerror.NewSQLErrorf(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Access denied: cannot decrypt password: %v", err)
+	}
+
+	return string(password), nil
+}
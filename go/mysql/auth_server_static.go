@@ -18,6 +18,8 @@ package mysql
 
 import (
 	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/json"
 	"net"
@@ -115,7 +117,7 @@ func NewAuthServerStatic(file, jsonConfig string, reloadInterval time.Duration)
 		entries:        make(map[string][]*AuthServerStaticEntry),
 	}
 
-	a.methods = []AuthMethod{NewMysqlNativeAuthMethod(a, a)}
+	a.methods = []AuthMethod{NewMysqlNativeAuthMethod(a, a), NewSha2CachingAuthMethod(a, a, a)}
 
 	a.reload()
 	a.installSignalHandlers()
@@ -170,13 +172,46 @@ func (a *AuthServerStatic) UserEntryWithPassword(conn *Conn, user string, passwo
 
 	for _, entry := range entries {
 		// Validate the password.
-		if MatchSourceHost(remoteAddr, entry.SourceHost) && subtle.ConstantTimeCompare([]byte(password), []byte(entry.Password)) == 1 {
+		if MatchSourceHost(remoteAddr, entry.SourceHost) && entry.matchesPassword(password) {
 			return &StaticUserData{entry.UserData, entry.Groups}, nil
 		}
 	}
 	return &StaticUserData{}, sqlerror.NewSQLErrorf(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Access denied for user '%v'", user)
 }
 
+// matchesPassword returns whether a plain text password matches the entry.
+// The plain text password of the entry is checked whenever it is set, and
+// the hashed passwords against the hashes of the password.
+func (entry *AuthServerStaticEntry) matchesPassword(password string) bool {
+	if entry.Password != "" || (entry.MysqlNativePassword == "" && entry.CachingSha2Password == "") {
+		if subtle.ConstantTimeCompare([]byte(password), []byte(entry.Password)) == 1 {
+			return true
+		}
+	}
+
+	if entry.MysqlNativePassword != "" {
+		if hash, err := DecodePasswordHex(entry.MysqlNativePassword); err == nil {
+			stage1 := sha1.Sum([]byte(password))
+			stage2 := sha1.Sum(stage1[:])
+			if subtle.ConstantTimeCompare(stage2[:], hash) == 1 {
+				return true
+			}
+		}
+	}
+
+	if entry.CachingSha2Password != "" {
+		if hash, err := DecodePasswordHex(entry.CachingSha2Password); err == nil {
+			stage1 := sha256.Sum256([]byte(password))
+			stage2 := sha256.Sum256(stage1[:])
+			if subtle.ConstantTimeCompare(stage2[:], hash) == 1 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // UserEntryWithHash implements password lookup based on a
 // mysql_native_password hash that is negotiated with the client.
 func (a *AuthServerStatic) UserEntryWithHash(conn *Conn, salt []byte, user string, authResponse []byte, remoteAddr net.Addr) (Getter, error) {
@@ -212,6 +247,10 @@ func (a *AuthServerStatic) UserEntryWithHash(conn *Conn, salt []byte, user strin
 
 // UserEntryWithCacheHash implements password lookup based on a
 // caching_sha2_password hash that is negotiated with the client.
+//
+// Entries with only a mysql_native_password hash cannot be checked against
+// the caching_sha2_password hash, so the full auth dance is requested for
+// them to get the plain text password.
 func (a *AuthServerStatic) UserEntryWithCacheHash(conn *Conn, salt []byte, user string, authResponse []byte, remoteAddr net.Addr) (Getter, CacheState, error) {
 	a.mu.Lock()
 	entries, ok := a.entries[user]
@@ -221,8 +260,13 @@ func (a *AuthServerStatic) UserEntryWithCacheHash(conn *Conn, salt []byte, user
 		return &StaticUserData{}, AuthRejected, sqlerror.NewSQLErrorf(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Access denied for user '%v'", user)
 	}
 
+	needMoreData := false
 	for _, entry := range entries {
-		if entry.CachingSha2Password != "" {
+		if entry.MysqlNativePassword != "" && entry.CachingSha2Password == "" {
+			if MatchSourceHost(remoteAddr, entry.SourceHost) {
+				needMoreData = true
+			}
+		} else if entry.CachingSha2Password != "" {
 			hash, err := DecodePasswordHex(entry.CachingSha2Password)
 			if err != nil {
 				return &StaticUserData{entry.UserData, entry.Groups}, AuthAccepted, sqlerror.NewSQLErrorf(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Access denied for user '%v'", user)
@@ -242,6 +286,9 @@ func (a *AuthServerStatic) UserEntryWithCacheHash(conn *Conn, salt []byte, user
 			}
 		}
 	}
+	if needMoreData {
+		return &StaticUserData{}, AuthNeedMoreData, nil
+	}
 	return &StaticUserData{}, AuthRejected, sqlerror.NewSQLErrorf(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Access denied for user '%v'", user)
 }
 
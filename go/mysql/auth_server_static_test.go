@@ -326,3 +326,107 @@ func TestStaticCachingSha2Passwords(t *testing.T) {
 		})
 	}
 }
+
+func TestStaticPlainTextPasswords(t *testing.T) {
+	_ = utils.LeakCheckContext(t)
+	jsonConfig := `
+{
+	"user01": [{ "Password": "user01" }],
+	"user02": [{
+		"MysqlNativePassword": "*B3AD996B12F211BEA47A7C666CC136FB26DC96AF"
+	}],
+	"user03": [{
+		"CachingSha2Password": "*0c5cfbb1ce7cae7dcab30b0cfeae014ffe060d93f4221fe6d7e25b27862290bc",
+		"Password": "invalid"
+	}],
+	"user04": [
+		{ "MysqlNativePassword": "*668425423DB5193AF921380129F465A6425216D0" },
+		{ "CachingSha2Password": "*d2a47945c740b8ddc53f575733003b68961290d5224a4aedfdb57c8726bb3979" }
+	],
+	"user05": [{
+		"MysqlNativePassword": "*668425423DB5193AF921380129F465A6425216D0",
+		"Password": "plaintext"
+	}],
+	"user06": [{ "Password": "" }]
+}`
+
+	tests := []struct {
+		user     string
+		password string
+		success  bool
+	}{
+		{"user01", "user01", true},
+		{"user01", "password", false},
+		{"user01", "", false},
+		{"user02", "user02", true},
+		{"user02", "password", false},
+		{"user02", "", false},
+		// The plain text password is still honored when there is a hashed
+		// password too.
+		{"user03", "user03", true},
+		{"user03", "invalid", true},
+		{"user03", "password", false},
+		{"user03", "", false},
+		{"user04", "password1", true},
+		{"user04", "user02", true},
+		{"user04", "", false},
+		{"user05", "password1", true},
+		{"user05", "plaintext", true},
+		{"user05", "password", false},
+		{"user05", "", false},
+		{"user06", "", true},
+		{"user06", "password", false},
+		{"userXX", "", false},
+		{"", "", false},
+	}
+
+	auth := NewAuthServerStatic("", jsonConfig, 0)
+	defer auth.close()
+	ip := net.ParseIP("127.0.0.1")
+	addr := &net.IPAddr{IP: ip, Zone: ""}
+
+	for _, c := range tests {
+		t.Run(fmt.Sprintf("%s-%s", c.user, c.password), func(t *testing.T) {
+			_, err := auth.UserEntryWithPassword(nil, c.user, c.password, addr)
+
+			if c.success {
+				require.NoError(t, err, "authentication should have succeeded: %v", err)
+			} else {
+				require.Error(t, err, "authentication should have failed")
+			}
+		})
+	}
+}
+
+func TestStaticCachingSha2PasswordsNeedMoreData(t *testing.T) {
+	_ = utils.LeakCheckContext(t)
+	jsonConfig := `
+{
+	"user01": [{
+		"MysqlNativePassword": "*668425423DB5193AF921380129F465A6425216D0"
+	}],
+	"user02": [{
+		"MysqlNativePassword": "*B3AD996B12F211BEA47A7C666CC136FB26DC96AF",
+		"SourceHost": "localhost"
+	}]
+}`
+
+	auth := NewAuthServerStatic("", jsonConfig, 0)
+	defer auth.close()
+	ip := net.ParseIP("127.0.0.1")
+	addr := &net.IPAddr{IP: ip, Zone: ""}
+
+	salt, err := newSalt()
+	require.NoError(t, err)
+
+	// The mysql_native_password hash cannot be checked against the
+	// caching_sha2_password hash, so the plain text password is needed.
+	_, status, err := auth.UserEntryWithCacheHash(nil, salt, "user01", ScrambleCachingSha2Password(salt, []byte("password1")), addr)
+	require.NoError(t, err)
+	require.Equal(t, AuthNeedMoreData, status)
+
+	// Unless the entry does not match the source host anyway.
+	_, status, err = auth.UserEntryWithCacheHash(nil, salt, "user02", ScrambleCachingSha2Password(salt, []byte("user02")), addr)
+	require.Error(t, err)
+	require.Equal(t, AuthRejected, status)
+}
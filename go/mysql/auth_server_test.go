@@ -17,9 +17,16 @@ limitations under the license.
 package mysql
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestVerifyHashedCachingSha2Password(t *testing.T) {
@@ -53,3 +60,81 @@ func TestVerifyHashedMysqlNativePassword(t *testing.T) {
 	passwordHash[0] = 0x00
 	assert.False(t, VerifyHashedMysqlNativePassword(reply, salt, passwordHash), "password hash match")
 }
+
+func TestDecryptPasswordWithPrivateKey(t *testing.T) {
+	salt := []byte{10, 47, 74, 111, 75, 73, 34, 48, 88, 76, 114, 74, 37, 13, 3, 80, 82, 2, 23, 21}
+	password := "a password longer than the salt"
+
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	enc, err := EncryptPasswordWithPublicKey(salt, []byte(password), &priv.PublicKey)
+	require.NoError(t, err)
+
+	dec, err := DecryptPasswordWithPrivateKey(salt, enc, priv)
+	require.NoError(t, err)
+	assert.Equal(t, password, string(dec))
+
+	// A different salt does not yield the zero terminated password.
+	otherSalt := append([]byte{}, salt...)
+	otherSalt[len(password)%len(salt)] = 0
+	_, err = DecryptPasswordWithPrivateKey(otherSalt, enc, priv)
+	assert.Error(t, err)
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, err = DecryptPasswordWithPrivateKey(salt, enc, other)
+	assert.Error(t, err)
+}
+
+func TestLoadRSAKeyPair(t *testing.T) {
+	dir := t.TempDir()
+	writePEM := func(name, typ string, der []byte) string {
+		file := path.Join(dir, name)
+		require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600))
+		return file
+	}
+
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	pub, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+	otherPub, err := x509.MarshalPKIXPublicKey(&other.PublicKey)
+	require.NoError(t, err)
+
+	pkcs1File := writePEM("pkcs1.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(priv))
+	pkcs8File := writePEM("pkcs8.pem", "PRIVATE KEY", pkcs8)
+	pubFile := writePEM("public.pem", "PUBLIC KEY", pub)
+	otherPubFile := writePEM("other.pem", "PUBLIC KEY", otherPub)
+	notPEMFile := path.Join(dir, "notpem")
+	require.NoError(t, os.WriteFile(notPEMFile, []byte("not a key"), 0o600))
+
+	expected := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})
+
+	for _, privateKeyFile := range []string{pkcs1File, pkcs8File} {
+		keys, err := LoadRSAKeyPair(privateKeyFile, "")
+		require.NoError(t, err)
+		assert.Equal(t, expected, keys.PublicKeyPEM())
+
+		keys, err = LoadRSAKeyPair(privateKeyFile, pubFile)
+		require.NoError(t, err)
+		assert.Equal(t, expected, keys.PublicKeyPEM())
+	}
+
+	_, err = LoadRSAKeyPair(pkcs1File, otherPubFile)
+	assert.ErrorContains(t, err, "does not match")
+
+	_, err = LoadRSAKeyPair(notPEMFile, "")
+	assert.ErrorContains(t, err, "no PEM data")
+
+	_, err = LoadRSAKeyPair(pubFile, "")
+	assert.Error(t, err)
+
+	_, err = LoadRSAKeyPair(path.Join(dir, "missing.pem"), "")
+	assert.Error(t, err)
+}
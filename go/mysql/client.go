@@ -734,7 +734,7 @@ func parseAuthSwitchRequest(data []byte) (AuthMethodDescription, []byte, error)
 func (c *Conn) requestPublicKey() (rsaKey *rsa.PublicKey, err error) {
 	// get public key from server
 	data, pos := c.startEphemeralPacketWithHeader(1)
-	data[pos] = 0x02
+	data[pos] = CachingSha2RequestPublicKey
 	if err := c.writeEphemeralPacket(); err != nil {
 		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "error sending public key request packet: %v", err)
 	}
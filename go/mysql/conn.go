@@ -166,6 +166,11 @@ type Conn struct {
 	// PrepareData is the map to use a prepared statement.
 	PrepareData map[uint32]*PrepareData
 
+	// QueryAttributes are the query attributes the client sent with the
+	// COM_QUERY or COM_STMT_EXECUTE being handled. They are only sent if
+	// CLIENT_QUERY_ATTRIBUTES was negotiated, and are reset after each command.
+	QueryAttributes map[string]*querypb.BindVariable
+
 	// protects the bufferedWriter and bufferedReader
 	bufMu sync.Mutex
 
@@ -1125,7 +1130,7 @@ func (c *Conn) handleComStmtExecute(handler Handler, data []byte) (kontinue bool
 		}
 	}()
 	queryStart := time.Now()
-	stmtID, _, err := c.parseComStmtExecute(c.PrepareData, data)
+	stmtID, _, attrs, err := c.parseComStmtExecute(c.PrepareData, data)
 	c.recycleReadPacket()
 
 	if stmtID != uint32(0) {
@@ -1140,6 +1145,11 @@ func (c *Conn) handleComStmtExecute(handler Handler, data []byte) (kontinue bool
 		return c.writeErrorPacketFromErrorAndLog(err)
 	}
 
+	c.QueryAttributes = attrs
+	defer func() {
+		c.QueryAttributes = nil
+	}()
+
 	receivedResult := false
 	// sendFinished is set if the response should just be an OK packet.
 	sendFinished := false
@@ -1311,8 +1321,16 @@ func (c *Conn) handleComQueryMulti(handler Handler, data []byte) (kontinue bool)
 	}()
 
 	queryStart := time.Now()
-	query := c.parseComQuery(data)
+	query, attrs, err := c.parseComQuery(data)
 	c.recycleReadPacket()
+	if err != nil {
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
+
+	c.QueryAttributes = attrs
+	defer func() {
+		c.QueryAttributes = nil
+	}()
 
 	res := c.execQueryMulti(query, handler)
 	if res != execSuccess {
@@ -1445,11 +1463,18 @@ func (c *Conn) handleComQuery(handler Handler, data []byte) (kontinue bool) {
 	}()
 
 	queryStart := time.Now()
-	query := c.parseComQuery(data)
+	query, attrs, err := c.parseComQuery(data)
 	c.recycleReadPacket()
+	if err != nil {
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
+
+	c.QueryAttributes = attrs
+	defer func() {
+		c.QueryAttributes = nil
+	}()
 
 	var queries []string
-	var err error
 	if c.Capabilities&CapabilityClientMultiStatements != 0 {
 		queries, err = handler.Env().Parser().SplitStatementToPieces(query)
 		if err != nil {
@@ -1751,6 +1776,15 @@ func (c *Conn) IsUnixSocket() bool {
 	return ok
 }
 
+// rsaKeyPair returns the RSA key pair of the listener of a server
+// connection, if any.
+func (c *Conn) rsaKeyPair() *RSAKeyPair {
+	if c.listener == nil {
+		return nil
+	}
+	return c.listener.RSAKeyPair.Load()
+}
+
 // IsClientUnixSocket returns true if the client connection is over a Unix socket with the server.
 func (c *Conn) IsClientUnixSocket() bool {
 	_, ok := c.conn.(*net.UnixConn)
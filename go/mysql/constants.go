@@ -148,6 +148,18 @@ const (
 	// CapabilityClientDeprecateEOF is CLIENT_DEPRECATE_EOF
 	// Expects an OK (instead of EOF) after the resultset rows of a Text Resultset.
 	CapabilityClientDeprecateEOF = 1 << 24
+
+	// CapabilityClientQueryAttributes is CLIENT_QUERY_ATTRIBUTES
+	// Can send query attributes with COM_QUERY and COM_STMT_EXECUTE.
+	CapabilityClientQueryAttributes = 1 << 27
+)
+
+// Cursor type flags of COM_STMT_EXECUTE.
+const (
+	// ParameterCountAvailable is PARAMETER_COUNT_AVAILABLE
+	// The parameter count is sent even if the statement has no
+	// parameters, so that query attributes can follow it.
+	ParameterCountAvailable = 0x08
 )
 
 // Status flags. They are returned by the server in a few cases.
@@ -264,6 +276,10 @@ const (
 	// AuthMoreDataPacket is sent when server requires more data to authenticate
 	AuthMoreDataPacket = 0x01
 
+	// CachingSha2RequestPublicKey is sent by the client to request the RSA public
+	// key of the server, to encrypt the password for the full authentication
+	CachingSha2RequestPublicKey = 0x02
+
 	// CachingSha2FastAuth is sent before OKPacket when server authenticates using cache
 	CachingSha2FastAuth = 0x03
 
@@ -57,8 +57,8 @@ func Init(ldapAuthConfigFile, ldapAuthConfigString, ldapAuthMethod string) {
 		return
 	}
 
-	if ldapAuthMethod != string(mysql.MysqlClearPassword) && ldapAuthMethod != string(mysql.MysqlDialog) {
-		log.Exitf("Invalid mysql_ldap_auth_method value: only support mysql_clear_password or dialog")
+	if ldapAuthMethod != string(mysql.MysqlClearPassword) && ldapAuthMethod != string(mysql.MysqlDialog) && ldapAuthMethod != string(mysql.CachingSha2Password) {
+		log.Exitf("Invalid mysql_ldap_auth_method value: only support mysql_clear_password, dialog or caching_sha2_password")
 	}
 	ldapAuthServer := &AuthServerLdap{
 		Client:       &ClientImpl{},
@@ -83,8 +83,10 @@ func Init(ldapAuthConfigFile, ldapAuthConfigString, ldapAuthMethod string) {
 		authMethod = mysql.NewMysqlClearAuthMethod(ldapAuthServer, ldapAuthServer)
 	case mysql.MysqlDialog:
 		authMethod = mysql.NewMysqlDialogAuthMethod(ldapAuthServer, ldapAuthServer, "")
+	case mysql.CachingSha2Password:
+		authMethod = mysql.NewSha2CachingAuthMethod(ldapAuthServer, ldapAuthServer, ldapAuthServer)
 	default:
-		log.Exitf("Invalid mysql_ldap_auth_method value: only support mysql_clear_password or dialog")
+		log.Exitf("Invalid mysql_ldap_auth_method value: only support mysql_clear_password, dialog or caching_sha2_password")
 	}
 
 	ldapAuthServer.methods = []mysql.AuthMethod{authMethod}
@@ -115,6 +117,13 @@ func (asl *AuthServerLdap) UserEntryWithPassword(conn *mysql.Conn, user string,
 	return asl.validate(user, password)
 }
 
+// UserEntryWithCacheHash is part of the CachingStorage interface. LDAP only
+// validates plain text passwords, so it always requests the full auth dance
+// of caching_sha2_password.
+func (asl *AuthServerLdap) UserEntryWithCacheHash(conn *mysql.Conn, salt []byte, user string, authResponse []byte, remoteAddr net.Addr) (mysql.Getter, mysql.CacheState, error) {
+	return nil, mysql.AuthNeedMoreData, nil
+}
+
 func (asl *AuthServerLdap) validate(username, password string) (mysql.Getter, error) {
 	if err := asl.Client.Connect("tcp", &asl.ServerConfig); err != nil {
 		return nil, err
@@ -244,12 +244,133 @@ func TestComStmtExecute(t *testing.T) {
 	// This is simulated packets for `select * from test_table where id = ?`
 	data := []byte{23, 18, 0, 0, 0, 128, 1, 0, 0, 0, 0, 1, 1, 128, 1}
 
-	stmtID, _, err := sConn.parseComStmtExecute(cConn.PrepareData, data)
+	stmtID, _, _, err := sConn.parseComStmtExecute(cConn.PrepareData, data)
 	require.NoError(t, err, "parseComStmtExeute failed: %v", err)
 	require.Equal(t, uint32(18), stmtID, "Parsed incorrect values")
 
 }
 
+func TestComQueryAttributes(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	query := []byte("select 1")
+	packet := func(attrs ...byte) []byte {
+		return append(append([]byte{ComQuery}, attrs...), query...)
+	}
+
+	// Without CLIENT_QUERY_ATTRIBUTES, the whole packet is the query.
+	got, attrs, err := sConn.parseComQuery(packet())
+	require.NoError(t, err)
+	assert.Equal(t, "select 1", got)
+	assert.Nil(t, attrs)
+
+	sConn.Capabilities |= CapabilityClientQueryAttributes
+
+	got, attrs, err = sConn.parseComQuery(packet(0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, "select 1", got)
+	assert.Nil(t, attrs)
+
+	got, attrs, err = sConn.parseComQuery(packet(
+		// count, set count, NULL-bitmap and new params bound flag
+		3, 1, 0x04, 1,
+		// types, flags and names
+		0x0f, 0, 8, 't', 'r', 'a', 'c', 'e', '_', 'i', 'd',
+		0x01, 0, 5, 'r', 'e', 't', 'r', 'y',
+		0x06, 0, 4, 'n', 'o', 'n', 'e',
+		// values
+		3, 'a', 'b', 'c',
+		2,
+	))
+	require.NoError(t, err)
+	assert.Equal(t, "select 1", got)
+	assert.Equal(t, map[string]*querypb.BindVariable{
+		"trace_id": sqltypes.StringBindVariable("abc"),
+		"retry":    sqltypes.Int64BindVariable(2),
+		"none":     sqltypes.NullBindVariable,
+	}, attrs)
+
+	// Attributes without their types are rejected.
+	_, _, err = sConn.parseComQuery(packet(1, 1, 0, 0))
+	assert.ErrorContains(t, err, "reading query attributes types failed")
+
+	// Truncated attributes are rejected.
+	_, _, err = sConn.parseComQuery([]byte{ComQuery, 1, 1, 0, 1, 0x0f, 0, 8, 't', 'r'})
+	assert.ErrorContains(t, err, "reading query attribute name failed")
+}
+
+func TestComStmtExecuteQueryAttributes(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	prepare := &PrepareData{
+		StatementID: 18,
+		ParamsCount: 1,
+		ParamsType:  make([]int32, 1),
+		BindVars:    map[string]*querypb.BindVariable{},
+	}
+	prepareDataMap := map[uint32]*PrepareData{
+		prepare.StatementID: prepare,
+		1: {
+			StatementID: 1,
+			BindVars:    map[string]*querypb.BindVariable{},
+		},
+	}
+	sConn.Capabilities |= CapabilityClientQueryAttributes
+
+	// `select * from test_table where id = ?` with a trace_id attribute.
+	data := []byte{
+		ComStmtExecute, 18, 0, 0, 0, 0, 1, 0, 0, 0,
+		// parameter count, NULL-bitmap and new params bound flag
+		2, 0, 1,
+		// types, flags and names
+		0x01, 0x80, 0,
+		0x0f, 0, 8, 't', 'r', 'a', 'c', 'e', '_', 'i', 'd',
+		// values
+		1,
+		3, 'a', 'b', 'c',
+	}
+	stmtID, _, attrs, err := sConn.parseComStmtExecute(prepareDataMap, data)
+	require.NoError(t, err)
+	assert.EqualValues(t, 18, stmtID)
+	assert.EqualValues(t, querypb.Type_INT8, prepare.ParamsType[0])
+	assert.Equal(t, sqltypes.Int64BindVariable(1), prepare.BindVars["v1"])
+	assert.Equal(t, map[string]*querypb.BindVariable{"trace_id": sqltypes.StringBindVariable("abc")}, attrs)
+
+	// A statement without parameters sends the parameter count if it has
+	// attributes.
+	data = []byte{
+		ComStmtExecute, 1, 0, 0, 0, ParameterCountAvailable, 1, 0, 0, 0,
+		1, 0, 1,
+		0x0f, 0, 8, 't', 'r', 'a', 'c', 'e', '_', 'i', 'd',
+		3, 'a', 'b', 'c',
+	}
+	stmtID, _, attrs, err = sConn.parseComStmtExecute(prepareDataMap, data)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, stmtID)
+	assert.Equal(t, map[string]*querypb.BindVariable{"trace_id": sqltypes.StringBindVariable("abc")}, attrs)
+
+	// And does not send anything else without them.
+	data = []byte{ComStmtExecute, 1, 0, 0, 0, 0, 1, 0, 0, 0}
+	_, _, attrs, err = sConn.parseComStmtExecute(prepareDataMap, data)
+	require.NoError(t, err)
+	assert.Nil(t, attrs)
+
+	// Attributes without their types are rejected.
+	data = []byte{ComStmtExecute, 1, 0, 0, 0, ParameterCountAvailable, 1, 0, 0, 0, 1, 0, 0}
+	_, _, _, err = sConn.parseComStmtExecute(prepareDataMap, data)
+	assert.ErrorContains(t, err, "query attributes sent without their types")
+}
+
 func TestComStmtExecuteUpdStmt(t *testing.T) {
 	listener, sConn, cConn := createSocketPair(t)
 	defer func() {
@@ -288,7 +409,7 @@ func TestComStmtExecuteUpdStmt(t *testing.T) {
 		0x35, 0x36, 0x37, 0x38, 0x0c, 0xe9, 0x9f, 0xa9, 0xe5, 0x86, 0xac, 0xe7, 0x9c, 0x9f, 0xe8, 0xb5,
 		0x9e, 0x03, 0x66, 0x6f, 0x6f, 0x07, 0x66, 0x6f, 0x6f, 0x2c, 0x62, 0x61, 0x72}
 
-	stmtID, _, err := sConn.parseComStmtExecute(prepareDataMap, data[4:]) // first 4 are header
+	stmtID, _, _, err := sConn.parseComStmtExecute(prepareDataMap, data[4:]) // first 4 are header
 	require.NoError(t, err)
 	require.EqualValues(t, 1, stmtID)
 
@@ -18,6 +18,8 @@ package mysql
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
 	"crypto/tls"
 	"fmt"
 	"net"
@@ -1212,6 +1214,62 @@ func TestCachingSha2PasswordAuthWithoutTLS(t *testing.T) {
 	}
 }
 
+func TestCachingSha2PasswordAuthWithRSA(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+	th := &testHandler{}
+
+	// The mysql_native_password hash of password1 forces the full auth dance.
+	authServer := NewAuthServerStaticWithAuthMethodDescription("", "", 0, CachingSha2Password)
+	authServer.entries["user1"] = []*AuthServerStaticEntry{
+		{MysqlNativePassword: "*668425423DB5193AF921380129F465A6425216D0"},
+	}
+	defer authServer.close()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keys, err := NewRSAKeyPair(privateKey)
+	require.NoError(t, err)
+
+	// Create the listener.
+	l, err := NewListener("tcp", "127.0.0.1:", authServer, th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err, "NewListener failed: %v", err)
+	l.RSAKeyPair.Store(keys)
+	host := l.Addr().(*net.TCPAddr).IP.String()
+	port := l.Addr().(*net.TCPAddr).Port
+	// Setup the right parameters.
+	params := &ConnParams{
+		Host:    host,
+		Port:    port,
+		Uname:   "user1",
+		Pass:    "password1",
+		SslMode: vttls.Disabled,
+	}
+	go l.Accept()
+	defer cleanupListener(ctx, l, params)
+
+	// Connection should succeed, as the password is encrypted with the
+	// public key of the server.
+	conn, err := Connect(ctx, params)
+	require.NoError(t, err, "unexpected connection error: %v", err)
+
+	defer conn.Close()
+
+	// Run a 'select rows' command with results.
+	result, err := conn.ExecuteFetch("select rows", 10000, true)
+	require.NoError(t, err, "ExecuteFetch failed: %v", err)
+
+	utils.MustMatch(t, result, selectRowsResult)
+
+	// Send a ComQuit to avoid the error message on the server side.
+	conn.writeComQuit()
+
+	// A wrong password is still rejected.
+	badParams := *params
+	badParams.Pass = "password2"
+	_, err = Connect(ctx, &badParams)
+	require.ErrorContains(t, err, "Access denied for user 'user1'")
+}
+
 func checkCountForTLSVer(t *testing.T, version string, expected int64) {
 	connCounts := connCountByTLSVer.Counts()
 	count, ok := connCounts[version]
@@ -0,0 +1,204 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdc
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// avroHeaderPrefix prefixes the names of the record headers that carry the
+// Avro schemas of the key and the value.
+const avroHeaderPrefix = "avro."
+
+// avroSingleObjectMarker starts every Avro single object encoded datum.
+var avroSingleObjectMarker = []byte{0xc3, 0x01}
+
+// avroSerializer writes records in the Avro single object encoding: a marker,
+// the fingerprint of the writer schema and the binary encoded datum. The
+// writer schema travels with the record in a header, in its parsing canonical
+// form, so that consumers do not need a schema registry.
+type avroSerializer struct {
+	schemas map[*connectSchema]*avroSchema
+}
+
+// avroSchema is the Avro form of a Kafka Connect schema.
+type avroSchema struct {
+	canonical   []byte
+	fingerprint uint64
+}
+
+func newAvroSerializer() *avroSerializer {
+	return &avroSerializer{schemas: map[*connectSchema]*avroSchema{}}
+}
+
+func (s *avroSerializer) serialize(schema *connectSchema, payload map[string]any, header string) ([]byte, []kgo.RecordHeader, error) {
+	as, ok := s.schemas[schema]
+	if !ok {
+		var sb strings.Builder
+		writeAvroSchema(&sb, schema, map[string]bool{})
+		canonical := []byte(sb.String())
+		as = &avroSchema{canonical: canonical, fingerprint: avroFingerprint(canonical)}
+		s.schemas[schema] = as
+	}
+	b := append([]byte{}, avroSingleObjectMarker...)
+	b = binary.LittleEndian.AppendUint64(b, as.fingerprint)
+	b, err := appendAvro(b, schema, payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, []kgo.RecordHeader{{Key: avroHeaderPrefix + header, Value: as.canonical}}, nil
+}
+
+// writeAvroSchema writes the parsing canonical form of the Avro schema of a
+// Kafka Connect schema. Records that are already defined are referred to by
+// name.
+func writeAvroSchema(sb *strings.Builder, schema *connectSchema, defined map[string]bool) {
+	if schema.Optional {
+		sb.WriteString(`["null",`)
+		defer sb.WriteString(`]`)
+	}
+	switch schema.Type {
+	case typeStruct:
+		name := recordName(schema.Name)
+		if defined[name] {
+			writeJSONString(sb, name)
+			return
+		}
+		defined[name] = true
+		sb.WriteString(`{"name":`)
+		writeJSONString(sb, name)
+		sb.WriteString(`,"type":"record","fields":[`)
+		for i, field := range schema.Fields {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(`{"name":`)
+			writeJSONString(sb, sanitizeName(field.Field))
+			sb.WriteString(`,"type":`)
+			writeAvroSchema(sb, field, defined)
+			sb.WriteByte('}')
+		}
+		sb.WriteString(`]}`)
+	case typeInt16, typeInt32:
+		sb.WriteString(`"int"`)
+	case typeInt64:
+		sb.WriteString(`"long"`)
+	default:
+		// float, double, string and bytes have the same name in Avro.
+		writeJSONString(sb, schema.Type)
+	}
+}
+
+func writeJSONString(sb *strings.Builder, s string) {
+	b, _ := json.Marshal(s)
+	sb.Write(b)
+}
+
+// appendAvro appends the Avro binary encoding of a value to b.
+func appendAvro(b []byte, schema *connectSchema, value any) ([]byte, error) {
+	null := value == nil
+	if m, ok := value.(map[string]any); ok && m == nil {
+		null = true
+	}
+	if schema.Optional {
+		// Optional values are unions of null and the value.
+		if null {
+			return binary.AppendVarint(b, 0), nil
+		}
+		b = binary.AppendVarint(b, 1)
+	} else if null {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "missing value for required field %q", schema.Field)
+	}
+	var ok bool
+	switch schema.Type {
+	case typeStruct:
+		var m map[string]any
+		if m, ok = value.(map[string]any); ok {
+			for _, field := range schema.Fields {
+				var err error
+				if b, err = appendAvro(b, field, m[field.Field]); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case typeInt16, typeInt32, typeInt64:
+		// Avro int and long share the zigzag varint encoding.
+		var v int64
+		if v, ok = value.(int64); ok {
+			b = binary.AppendVarint(b, v)
+		}
+	case typeFloat:
+		var v float64
+		if v, ok = value.(float64); ok {
+			b = binary.LittleEndian.AppendUint32(b, math.Float32bits(float32(v)))
+		}
+	case typeDouble:
+		var v float64
+		if v, ok = value.(float64); ok {
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+		}
+	case typeString:
+		var v string
+		if v, ok = value.(string); ok {
+			b = binary.AppendVarint(b, int64(len(v)))
+			b = append(b, v...)
+		}
+	case typeBytes:
+		var v []byte
+		if v, ok = value.([]byte); ok {
+			b = binary.AppendVarint(b, int64(len(v)))
+			b = append(b, v...)
+		}
+	}
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected %T value for %s field %q", value, schema.Type, schema.Field)
+	}
+	return b, nil
+}
+
+// avroFingerprintEmpty is the CRC-64-AVRO fingerprint of empty data.
+const avroFingerprintEmpty = 0xc15d213aa4d7a795
+
+var avroFingerprintTable = func() (table [256]uint64) {
+	for i := range table {
+		fp := uint64(i)
+		for range 8 {
+			fp = (fp >> 1) ^ (avroFingerprintEmpty & -(fp & 1))
+		}
+		table[i] = fp
+	}
+	return table
+}()
+
+// avroFingerprint returns the CRC-64-AVRO fingerprint of a schema in parsing
+// canonical form, which identifies the schema in single object encoded data.
+func avroFingerprint(canonical []byte) uint64 {
+	fp := uint64(avroFingerprintEmpty)
+	for _, c := range canonical {
+		fp = (fp >> 8) ^ avroFingerprintTable[(fp^uint64(c))&0xff]
+	}
+	return fp
+}
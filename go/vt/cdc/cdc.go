@@ -0,0 +1,361 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cdc streams the changes of a Vitess cluster from a vtgate VStream
+// into Kafka compatible brokers as Debezium change events.
+//
+// Every batch of events is produced in a Kafka transaction together with a
+// checkpoint record holding the VGTID the batch ends at. Consumers that read
+// committed records see each change exactly once, and a restarted connector
+// resumes the VStream from the last committed checkpoint.
+package cdc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"vitess.io/vitess/go/json2"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// Streamer starts a VStream from the given position.
+type Streamer func(ctx context.Context, vgtid *binlogdatapb.VGtid) (vtgateconn.VStreamReader, error)
+
+// Config is the configuration of a Connector.
+type Config struct {
+	// Name identifies the connector. It is the key of its checkpoint
+	// records.
+	Name string
+	// TopicPrefix prefixes the names of the topics. Row changes go to
+	// <prefix>.<keyspace>.<table> and DDLs go to <prefix>.
+	TopicPrefix string
+	// CheckpointTopic is the topic the VGTIDs are stored in.
+	CheckpointTopic string
+	// Format is the encoding of the records, FormatJSON or FormatAvro.
+	Format string
+	// VGtid is the position to start streaming from when there is no
+	// checkpoint yet. Run fails when there is neither.
+	VGtid *binlogdatapb.VGtid
+	// RetryDelay is how long to wait before restarting a failed VStream.
+	RetryDelay time.Duration
+}
+
+// Broker is where the connector produces records to.
+type Broker interface {
+	// Checkpoint returns the value of the last committed record with the
+	// given key in the topic, or nil if there is none.
+	Checkpoint(ctx context.Context, topic, key string) ([]byte, error)
+	// Produce produces the records in a single transaction: consumers that
+	// read committed records see all of them or none of them.
+	Produce(ctx context.Context, records []*kgo.Record) error
+	// Close closes the connection to the broker.
+	Close()
+}
+
+// Connector produces the events of a VStream to a broker.
+type Connector struct {
+	cfg    Config
+	broker Broker
+	stream Streamer
+	enc    encoder
+
+	// vgtid is the position of the last committed checkpoint.
+	vgtid *binlogdatapb.VGtid
+	// now returns the current time, overridden in tests.
+	now func() time.Time
+}
+
+// producerError is an error of the broker. The VStream is restarted after
+// other errors, but the producer may be fenced off or have an undecided
+// transaction after these, so they stop the connector.
+type producerError struct {
+	err error
+}
+
+func (e *producerError) Error() string {
+	return e.err.Error()
+}
+
+func (e *producerError) Unwrap() error {
+	return e.err
+}
+
+// NewConnector returns a connector that produces the events of the given
+// streamer to the broker.
+func NewConnector(cfg Config, broker Broker, stream Streamer) (*Connector, error) {
+	if cfg.Name == "" || cfg.TopicPrefix == "" || cfg.CheckpointTopic == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a name, a topic prefix and a checkpoint topic are required")
+	}
+	enc, err := newEncoder(cfg.Format, cfg.TopicPrefix)
+	if err != nil {
+		return nil, err
+	}
+	return &Connector{
+		cfg:    cfg,
+		broker: broker,
+		stream: stream,
+		enc:    enc,
+		now:    time.Now,
+	}, nil
+}
+
+// Run streams events until the context is done or the producer fails. The
+// VStream starts from the last checkpoint, and is restarted from the last
+// checkpoint when it fails.
+func (c *Connector) Run(ctx context.Context) error {
+	vgtid, err := c.readCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+	if vgtid == nil {
+		if c.cfg.VGtid == nil {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no checkpoint found for connector %s and no starting position specified", c.cfg.Name)
+		}
+		vgtid = c.cfg.VGtid
+		log.Infof("No checkpoint found for connector %s, starting from %v", c.cfg.Name, vgtid)
+	} else {
+		log.Infof("Connector %s resuming from checkpoint %v", c.cfg.Name, vgtid)
+	}
+	c.vgtid = vgtid
+	for {
+		err := c.runStream(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if perr := (*producerError)(nil); errors.As(err, &perr) {
+			return err
+		}
+		log.Warningf("VStream of connector %s failed, restarting from the last checkpoint in %v: %v", c.cfg.Name, c.cfg.RetryDelay, err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(c.cfg.RetryDelay):
+		}
+	}
+}
+
+// runStream streams from the last checkpoint until the VStream fails.
+func (c *Connector) runStream(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	reader, err := c.stream(ctx, c.vgtid)
+	if err != nil {
+		return err
+	}
+	b := &batch{
+		enc:    c.enc,
+		prefix: c.cfg.TopicPrefix,
+		fields: map[string][]*querypb.Field{},
+		now:    c.now,
+	}
+	for {
+		events, err := reader.Recv()
+		if err != nil {
+			return err
+		}
+		for _, ev := range events {
+			if err := b.add(ev); err != nil {
+				return err
+			}
+		}
+		// vtgate sends the events up to the end of a transaction, a DDL or
+		// the end of a table copy together, so that is when the position
+		// moves.
+		if b.inTransaction || b.vgtid == nil {
+			continue
+		}
+		if err := c.flush(ctx, b.records, b.vgtid); err != nil {
+			return &producerError{err: err}
+		}
+		b.records, b.vgtid = nil, nil
+	}
+}
+
+// flush produces the records and the checkpoint of the position they end at
+// in a single transaction.
+func (c *Connector) flush(ctx context.Context, records []*kgo.Record, vgtid *binlogdatapb.VGtid) error {
+	checkpoint, err := json2.MarshalPB(vgtid)
+	if err != nil {
+		return err
+	}
+	records = append(records, &kgo.Record{
+		Topic: c.cfg.CheckpointTopic,
+		Key:   []byte(c.cfg.Name),
+		Value: checkpoint,
+	})
+	if err := c.broker.Produce(ctx, records); err != nil {
+		return err
+	}
+	c.vgtid = vgtid
+	return nil
+}
+
+// readCheckpoint returns the position of the last committed checkpoint of
+// the connector, or nil if there is none.
+func (c *Connector) readCheckpoint(ctx context.Context) (*binlogdatapb.VGtid, error) {
+	checkpoint, err := c.broker.Checkpoint(ctx, c.cfg.CheckpointTopic, c.cfg.Name)
+	if err != nil || checkpoint == nil {
+		return nil, err
+	}
+	vgtid := &binlogdatapb.VGtid{}
+	if err := json2.UnmarshalPB(checkpoint, vgtid); err != nil {
+		return nil, vterrors.Wrapf(err, "invalid checkpoint %q", checkpoint)
+	}
+	return vgtid, nil
+}
+
+// batch accumulates the records of the events received since the last
+// checkpoint.
+type batch struct {
+	enc    encoder
+	prefix string
+	now    func() time.Time
+	// fields are the columns of the tables, by keyspace, shard and table.
+	fields map[string][]*querypb.Field
+
+	// inTransaction is set between a BEGIN and its COMMIT.
+	inTransaction bool
+	// changes are the changes of the current transaction, or the DDL
+	// being received.
+	changes []*change
+	// txVGtid is the position at the end of the current transaction.
+	txVGtid *binlogdatapb.VGtid
+
+	// records are the encoded changes of the completed transactions.
+	records []*kgo.Record
+	// vgtid is the position at the end of the completed transactions, nil
+	// if no transaction completed since the last checkpoint.
+	vgtid *binlogdatapb.VGtid
+}
+
+func (b *batch) add(ev *binlogdatapb.VEvent) error {
+	switch ev.Type {
+	case binlogdatapb.VEventType_BEGIN:
+		b.inTransaction = true
+	case binlogdatapb.VEventType_FIELD:
+		fe := ev.FieldEvent
+		b.fields[tableKey(fe.Keyspace, fe.Shard, fe.TableName)] = fe.Fields
+	case binlogdatapb.VEventType_ROW:
+		re := ev.RowEvent
+		if re.IsInternalTable {
+			return nil
+		}
+		fields, ok := b.fields[tableKey(re.Keyspace, re.Shard, re.TableName)]
+		if !ok {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "received rows of table %s in shard %s/%s before its fields", re.TableName, re.Keyspace, re.Shard)
+		}
+		for _, rc := range re.RowChanges {
+			b.changes = append(b.changes, &change{
+				keyspace:  re.Keyspace,
+				shard:     re.Shard,
+				table:     unqualified(re.Keyspace, re.TableName),
+				fields:    fields,
+				before:    rc.Before,
+				after:     rc.After,
+				timestamp: ev.Timestamp,
+			})
+		}
+	case binlogdatapb.VEventType_VGTID:
+		b.txVGtid = ev.Vgtid
+	case binlogdatapb.VEventType_DDL:
+		b.changes = append(b.changes, &change{
+			keyspace:  ev.Keyspace,
+			shard:     ev.Shard,
+			ddl:       ev.Statement,
+			timestamp: ev.Timestamp,
+		})
+		return b.commit()
+	case binlogdatapb.VEventType_COMMIT:
+		b.inTransaction = false
+		return b.commit()
+	case binlogdatapb.VEventType_OTHER, binlogdatapb.VEventType_COPY_COMPLETED:
+		return b.commit()
+	}
+	return nil
+}
+
+// commit encodes the changes of the transaction that just ended, at the
+// position it ended at.
+func (b *batch) commit() error {
+	if b.txVGtid == nil {
+		return nil
+	}
+	vgtid, err := json2.MarshalPB(b.txVGtid)
+	if err != nil {
+		return err
+	}
+	tsMs := b.now().UnixMilli()
+	for _, ch := range b.changes {
+		ch.vgtid = string(vgtid)
+		if ch.ddl != "" {
+			record, err := b.enc.encodeSchemaChange(ch, tsMs)
+			if err != nil {
+				return err
+			}
+			record.Topic = b.prefix
+			b.records = append(b.records, record)
+			continue
+		}
+		ch.snapshot = copying(b.txVGtid, ch)
+		records, err := b.enc.encodeRow(ch, tsMs)
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to encode row of table %s.%s", ch.keyspace, ch.table)
+		}
+		for _, record := range records {
+			record.Topic = topicName(b.prefix, ch.keyspace, ch.table)
+		}
+		b.records = append(b.records, records...)
+	}
+	b.changes = nil
+	b.vgtid, b.txVGtid = b.txVGtid, nil
+	return nil
+}
+
+// copying returns true if the table of a row change was still being copied
+// in its shard at the given position. Inserts into such tables are reported
+// as snapshot reads.
+func copying(vgtid *binlogdatapb.VGtid, ch *change) bool {
+	for _, sgtid := range vgtid.ShardGtids {
+		if sgtid.Keyspace != ch.keyspace || sgtid.Shard != ch.shard {
+			continue
+		}
+		for _, tablePK := range sgtid.TablePKs {
+			if tablePK.TableName == ch.table {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func tableKey(keyspace, shard, table string) string {
+	return keyspace + "/" + shard + "/" + table
+}
+
+// unqualified strips the keyspace vtgate prefixes table names with.
+func unqualified(keyspace, table string) string {
+	return strings.TrimPrefix(table, keyspace+".")
+}
@@ -0,0 +1,313 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/json2"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/cdc/memorybroker"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// fakeReader returns batches of events, and then blocks until the stream
+// is canceled.
+type fakeReader struct {
+	ctx     context.Context
+	batches [][]*binlogdatapb.VEvent
+}
+
+func (r *fakeReader) Recv() ([]*binlogdatapb.VEvent, error) {
+	if len(r.batches) == 0 {
+		<-r.ctx.Done()
+		return nil, r.ctx.Err()
+	}
+	events := r.batches[0]
+	r.batches = r.batches[1:]
+	return events, nil
+}
+
+// fakeStreamer returns a streamer that streams the given batches and records
+// the positions it was started from.
+func fakeStreamer(starts chan<- *binlogdatapb.VGtid, batches ...[]*binlogdatapb.VEvent) Streamer {
+	return func(ctx context.Context, vgtid *binlogdatapb.VGtid) (vtgateconn.VStreamReader, error) {
+		select {
+		case starts <- vgtid:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		return &fakeReader{ctx: ctx, batches: batches}, nil
+	}
+}
+
+func vgtid(gtid string) *binlogdatapb.VGtid {
+	return &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{Keyspace: "ks", Shard: "0", Gtid: gtid}}}
+}
+
+var testFields = []*querypb.Field{
+	{Name: "id", Type: sqltypes.Int64, Flags: uint32(querypb.MySqlFlag_PRI_KEY_FLAG | querypb.MySqlFlag_NOT_NULL_FLAG)},
+	{Name: "val", Type: sqltypes.VarChar},
+}
+
+func testRow(id int64, val string) *querypb.Row {
+	return sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(id), sqltypes.NewVarChar(val)})
+}
+
+func rowEvent(changes ...*binlogdatapb.RowChange) *binlogdatapb.VEvent {
+	return &binlogdatapb.VEvent{
+		Type:      binlogdatapb.VEventType_ROW,
+		Timestamp: 1700000000,
+		RowEvent:  &binlogdatapb.RowEvent{TableName: "ks.t1", Keyspace: "ks", Shard: "0", RowChanges: changes},
+	}
+}
+
+func TestConnector(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	broker := memorybroker.New()
+	cfg := Config{
+		Name:            "test",
+		TopicPrefix:     "cdc",
+		CheckpointTopic: "cdc-checkpoints",
+		Format:          FormatJSON,
+		VGtid:           vgtid("current"),
+	}
+	starts := make(chan *binlogdatapb.VGtid, 1)
+	conn, err := NewConnector(cfg, broker, fakeStreamer(starts,
+		[]*binlogdatapb.VEvent{
+			{Type: binlogdatapb.VEventType_FIELD, FieldEvent: &binlogdatapb.FieldEvent{TableName: "ks.t1", Keyspace: "ks", Shard: "0", Fields: testFields}},
+			{Type: binlogdatapb.VEventType_BEGIN},
+			rowEvent(
+				&binlogdatapb.RowChange{After: testRow(1, "a")},
+				&binlogdatapb.RowChange{Before: testRow(1, "a"), After: testRow(1, "b")},
+			),
+			{Type: binlogdatapb.VEventType_VGTID, Vgtid: vgtid("pos1")},
+			{Type: binlogdatapb.VEventType_COMMIT},
+		},
+		[]*binlogdatapb.VEvent{
+			{Type: binlogdatapb.VEventType_BEGIN},
+			rowEvent(&binlogdatapb.RowChange{Before: testRow(1, "b")}),
+			{Type: binlogdatapb.VEventType_VGTID, Vgtid: vgtid("pos2")},
+			{Type: binlogdatapb.VEventType_COMMIT},
+		},
+		[]*binlogdatapb.VEvent{
+			{Type: binlogdatapb.VEventType_VGTID, Vgtid: vgtid("pos3")},
+			{Type: binlogdatapb.VEventType_DDL, Keyspace: "ks", Shard: "0", Statement: "alter table t1 add column x int"},
+		},
+	))
+	require.NoError(t, err)
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() {
+		done <- conn.Run(runCtx)
+	}()
+	assert.True(t, proto.Equal(vgtid("current"), <-starts))
+
+	records, err := broker.WaitForRecords(ctx, "cdc.ks.t1", 4)
+	require.NoError(t, err)
+	var ops []string
+	for _, record := range records[:3] {
+		var value struct {
+			Payload struct {
+				Op    string         `json:"op"`
+				After map[string]any `json:"after"`
+			} `json:"payload"`
+		}
+		require.NoError(t, json.Unmarshal(record.Value, &value))
+		ops = append(ops, value.Payload.Op)
+		if value.Payload.Op == "u" {
+			assert.Equal(t, map[string]any{"id": float64(1), "val": "b"}, value.Payload.After)
+		}
+		assert.JSONEq(t, `{"schema":{"type":"struct","name":"cdc.ks.t1.Key","optional":false,"fields":[{"type":"int64","optional":false,"field":"id"}]},"payload":{"id":1}}`, string(record.Key))
+	}
+	assert.Equal(t, []string{"c", "u", "d"}, ops)
+	// The delete is followed by a tombstone.
+	assert.Nil(t, records[3].Value)
+
+	records, err = broker.WaitForRecords(ctx, "cdc", 1)
+	require.NoError(t, err)
+	var ddl struct {
+		Payload struct {
+			DDL string `json:"ddl"`
+		} `json:"payload"`
+	}
+	require.NoError(t, json.Unmarshal(records[0].Value, &ddl))
+	assert.Equal(t, "alter table t1 add column x int", ddl.Payload.DDL)
+
+	// Each batch committed a checkpoint.
+	records, err = broker.WaitForRecords(ctx, "cdc-checkpoints", 3)
+	require.NoError(t, err)
+	assert.Equal(t, "test", string(records[2].Key))
+	stop()
+	require.NoError(t, <-done)
+
+	// A restarted connector resumes from the last checkpoint.
+	conn, err = NewConnector(cfg, broker, fakeStreamer(starts))
+	require.NoError(t, err)
+	runCtx, stop = context.WithCancel(ctx)
+	defer stop()
+	go func() {
+		done <- conn.Run(runCtx)
+	}()
+	assert.True(t, proto.Equal(vgtid("pos3"), <-starts))
+	stop()
+	require.NoError(t, <-done)
+}
+
+func TestConnectorRetry(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	broker := memorybroker.New()
+	cfg := Config{
+		Name:            "test",
+		TopicPrefix:     "cdc",
+		CheckpointTopic: "cdc-checkpoints",
+		Format:          FormatJSON,
+		VGtid:           vgtid("current"),
+		RetryDelay:      time.Millisecond,
+	}
+	starts := make(chan *binlogdatapb.VGtid, 1)
+	conn, err := NewConnector(cfg, broker, fakeStreamer(starts,
+		[]*binlogdatapb.VEvent{
+			{Type: binlogdatapb.VEventType_BEGIN},
+			{Type: binlogdatapb.VEventType_VGTID, Vgtid: vgtid("pos1")},
+			{Type: binlogdatapb.VEventType_COMMIT},
+		},
+		// Rows of a table whose fields were not received fail the stream.
+		[]*binlogdatapb.VEvent{
+			{Type: binlogdatapb.VEventType_BEGIN},
+			rowEvent(&binlogdatapb.RowChange{After: testRow(1, "a")}),
+		},
+	))
+	require.NoError(t, err)
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	done := make(chan error)
+	go func() {
+		done <- conn.Run(runCtx)
+	}()
+	assert.True(t, proto.Equal(vgtid("current"), <-starts))
+	// The stream restarts from the committed position.
+	assert.True(t, proto.Equal(vgtid("pos1"), <-starts))
+	stop()
+	require.NoError(t, <-done)
+}
+
+func TestConnectorProduceFailure(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	broker := memorybroker.New()
+	cfg := Config{
+		Name:            "test",
+		TopicPrefix:     "cdc",
+		CheckpointTopic: "cdc-checkpoints",
+		Format:          FormatJSON,
+		VGtid:           vgtid("current"),
+	}
+	batches := [][]*binlogdatapb.VEvent{
+		{
+			{Type: binlogdatapb.VEventType_FIELD, FieldEvent: &binlogdatapb.FieldEvent{TableName: "ks.t1", Keyspace: "ks", Shard: "0", Fields: testFields}},
+			{Type: binlogdatapb.VEventType_BEGIN},
+			rowEvent(&binlogdatapb.RowChange{After: testRow(1, "a")}),
+			{Type: binlogdatapb.VEventType_VGTID, Vgtid: vgtid("pos1")},
+			{Type: binlogdatapb.VEventType_COMMIT},
+		},
+	}
+	starts := make(chan *binlogdatapb.VGtid, 1)
+	conn, err := NewConnector(cfg, broker, fakeStreamer(starts, batches...))
+	require.NoError(t, err)
+	broker.FailNextProduce(errors.New("fenced"))
+	// Producer errors stop the connector, and nothing is committed.
+	require.ErrorContains(t, conn.Run(ctx), "fenced")
+	assert.True(t, proto.Equal(vgtid("current"), <-starts))
+	assert.Empty(t, broker.Records("cdc.ks.t1"))
+	assert.Empty(t, broker.Records("cdc-checkpoints"))
+
+	// The restarted connector produces the batch once.
+	conn, err = NewConnector(cfg, broker, fakeStreamer(starts, batches...))
+	require.NoError(t, err)
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	done := make(chan error)
+	go func() {
+		done <- conn.Run(runCtx)
+	}()
+	assert.True(t, proto.Equal(vgtid("current"), <-starts))
+	_, err = broker.WaitForRecords(ctx, "cdc-checkpoints", 1)
+	require.NoError(t, err)
+	stop()
+	require.NoError(t, <-done)
+	assert.Len(t, broker.Records("cdc.ks.t1"), 1)
+}
+
+func TestConnectorNoStartingPosition(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	broker := memorybroker.New()
+	cfg := Config{
+		Name:            "test",
+		TopicPrefix:     "cdc",
+		CheckpointTopic: "cdc-checkpoints",
+		Format:          FormatJSON,
+	}
+	starts := make(chan *binlogdatapb.VGtid, 1)
+	conn, err := NewConnector(cfg, broker, fakeStreamer(starts))
+	require.NoError(t, err)
+	// Without a checkpoint, the connector does not guess where to start.
+	err = conn.Run(ctx)
+	assert.Equal(t, vtrpcpb.Code_FAILED_PRECONDITION, vterrors.Code(err))
+	assert.ErrorContains(t, err, "no checkpoint found for connector test and no starting position specified")
+	assert.Empty(t, starts)
+
+	// With a checkpoint, it resumes from it.
+	checkpoint, err := json2.MarshalPB(vgtid("pos1"))
+	require.NoError(t, err)
+	require.NoError(t, broker.Produce(ctx, []*kgo.Record{{Topic: "cdc-checkpoints", Key: []byte("test"), Value: checkpoint}}))
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	done := make(chan error)
+	go func() {
+		done <- conn.Run(runCtx)
+	}()
+	assert.True(t, proto.Equal(vgtid("pos1"), <-starts))
+	stop()
+	require.NoError(t, <-done)
+}
+
+func TestNewConnector(t *testing.T) {
+	_, err := NewConnector(Config{Name: "test", TopicPrefix: "cdc", CheckpointTopic: "cdc-checkpoints", Format: "xml"}, nil, nil)
+	assert.ErrorContains(t, err, `unsupported format "xml"`)
+	_, err = NewConnector(Config{TopicPrefix: "cdc", CheckpointTopic: "cdc-checkpoints", Format: FormatJSON}, nil, nil)
+	assert.ErrorContains(t, err, "a name, a topic prefix and a checkpoint topic are required")
+	_, err = NewKafkaBroker(KafkaConfig{TransactionalID: "test"})
+	assert.ErrorContains(t, err, "no brokers specified")
+}
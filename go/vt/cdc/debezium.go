@@ -0,0 +1,256 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdc
+
+import (
+	"encoding/json"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// connectSchema is a Kafka Connect schema, as the JSON converter embeds it
+// in records.
+type connectSchema struct {
+	Type     string           `json:"type"`
+	Name     string           `json:"name,omitempty"`
+	Optional bool             `json:"optional"`
+	Field    string           `json:"field,omitempty"`
+	Fields   []*connectSchema `json:"fields,omitempty"`
+}
+
+// connectRecord is a key or value of the Kafka Connect JSON converter.
+type connectRecord struct {
+	Schema  *connectSchema `json:"schema"`
+	Payload any            `json:"payload"`
+}
+
+// debeziumTable holds the schemas of the records of a table.
+type debeziumTable struct {
+	*tableSchema
+	key   *connectSchema
+	value *connectSchema
+}
+
+// serializer writes the key or the value of a record.
+type serializer interface {
+	// serialize returns the bytes of the payload, along with the record
+	// headers that describe them.
+	serialize(schema *connectSchema, payload map[string]any, header string) ([]byte, []kgo.RecordHeader, error)
+}
+
+// debeziumEncoder encodes changes as Debezium records: row changes go to a
+// topic per table in the Debezium change event envelope, and DDLs go to the
+// schema change topic.
+type debeziumEncoder struct {
+	topicPrefix string
+	serializer  serializer
+	// tables are the schemas of the tables by topic, rebuilt when the
+	// columns of a table change.
+	tables map[string]*debeziumTable
+}
+
+func (e *debeziumEncoder) table(ch *change) *debeziumTable {
+	topic := topicName(e.topicPrefix, ch.keyspace, ch.table)
+	if jt, ok := e.tables[topic]; ok && jt.matches(ch.fields) {
+		return jt
+	}
+	ts := newTableSchema(ch.fields)
+	name := recordName(e.topicPrefix, ch.keyspace, ch.table)
+	jt := &debeziumTable{tableSchema: ts}
+	if len(ts.pk) > 0 {
+		jt.key = &connectSchema{Type: typeStruct, Name: name + ".Key"}
+		for _, i := range ts.pk {
+			jt.key.Fields = append(jt.key.Fields, columnSchema(ts, i, false))
+		}
+	}
+	row := &connectSchema{Type: typeStruct, Name: name + ".Value", Optional: true}
+	for i := range ts.fields {
+		row.Fields = append(row.Fields, columnSchema(ts, i, true))
+	}
+	before, after := *row, *row
+	before.Field, after.Field = "before", "after"
+	jt.value = &connectSchema{
+		Type: typeStruct,
+		Name: name + ".Envelope",
+		Fields: []*connectSchema{
+			&before,
+			&after,
+			sourceSchema,
+			{Type: typeString, Field: "op"},
+			{Type: typeInt64, Optional: true, Field: "ts_ms"},
+		},
+	}
+	e.tables[topic] = jt
+	return jt
+}
+
+func columnSchema(ts *tableSchema, i int, optional bool) *connectSchema {
+	return &connectSchema{
+		Type:     ts.types[i].connect,
+		Name:     ts.types[i].name,
+		Optional: optional,
+		Field:    ts.fields[i].Name,
+	}
+}
+
+// sourceSchema is the schema of the source block of the records, which
+// tells where a change comes from.
+var sourceSchema = &connectSchema{
+	Type:  typeStruct,
+	Name:  "io.debezium.connector.vitess.Source",
+	Field: "source",
+	Fields: []*connectSchema{
+		{Type: typeString, Field: "connector"},
+		{Type: typeString, Field: "name"},
+		{Type: typeInt64, Field: "ts_ms"},
+		{Type: typeString, Optional: true, Field: "snapshot"},
+		{Type: typeString, Field: "db"},
+		{Type: typeString, Field: "keyspace"},
+		{Type: typeString, Optional: true, Field: "table"},
+		{Type: typeString, Field: "shard"},
+		{Type: typeString, Field: "vgtid"},
+	},
+}
+
+func (e *debeziumEncoder) source(ch *change) map[string]any {
+	source := map[string]any{
+		"connector": connectorName,
+		"name":      e.topicPrefix,
+		"ts_ms":     ch.timestamp * 1000,
+		"snapshot":  "false",
+		"db":        ch.keyspace,
+		"keyspace":  ch.keyspace,
+		"shard":     ch.shard,
+		"vgtid":     ch.vgtid,
+	}
+	if ch.snapshot {
+		source["snapshot"] = "true"
+	}
+	if ch.table != "" {
+		source["table"] = ch.table
+	}
+	return source
+}
+
+func (e *debeziumEncoder) encodeRow(ch *change, tsMs int64) ([]*kgo.Record, error) {
+	jt := e.table(ch)
+	before, err := jt.row(ch.before)
+	if err != nil {
+		return nil, err
+	}
+	after, err := jt.row(ch.after)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		key     []byte
+		headers []kgo.RecordHeader
+	)
+	if jt.key != nil {
+		// The key is taken from the row image after the change, or before
+		// it for deletes.
+		keyRow := after
+		if keyRow == nil {
+			keyRow = before
+		}
+		payload := map[string]any{}
+		for _, i := range jt.pk {
+			name := jt.fields[i].Name
+			payload[name] = keyRow[name]
+		}
+		if key, headers, err = e.serializer.serialize(jt.key, payload, "key.schema"); err != nil {
+			return nil, err
+		}
+	}
+	payload := map[string]any{
+		"before": before,
+		"after":  after,
+		"source": e.source(ch),
+		"op":     ch.op(),
+		"ts_ms":  tsMs,
+	}
+	value, valueHeaders, err := e.serializer.serialize(jt.value, payload, "value.schema")
+	if err != nil {
+		return nil, err
+	}
+	records := []*kgo.Record{{Key: key, Value: value, Headers: append(headers, valueHeaders...)}}
+	if ch.after == nil && key != nil {
+		records = append(records, &kgo.Record{Key: key})
+	}
+	return records, nil
+}
+
+// row returns the payload of a row image, or nil if there is no image.
+func (jt *debeziumTable) row(row *querypb.Row) (map[string]any, error) {
+	values, err := jt.values(row)
+	if err != nil || values == nil {
+		return nil, err
+	}
+	payload := make(map[string]any, len(values))
+	for i, v := range values {
+		payload[jt.fields[i].Name] = v
+	}
+	return payload, nil
+}
+
+var (
+	schemaChangeKeySchema = &connectSchema{
+		Type: typeStruct,
+		Name: "io.debezium.connector.vitess.SchemaChangeKey",
+		Fields: []*connectSchema{
+			{Type: typeString, Field: "databaseName"},
+		},
+	}
+	schemaChangeValueSchema = &connectSchema{
+		Type: typeStruct,
+		Name: "io.debezium.connector.vitess.SchemaChangeValue",
+		Fields: []*connectSchema{
+			sourceSchema,
+			{Type: typeInt64, Field: "ts_ms"},
+			{Type: typeString, Field: "databaseName"},
+			{Type: typeString, Field: "ddl"},
+		},
+	}
+)
+
+func (e *debeziumEncoder) encodeSchemaChange(ch *change, tsMs int64) (*kgo.Record, error) {
+	key, headers, err := e.serializer.serialize(schemaChangeKeySchema, map[string]any{"databaseName": ch.keyspace}, "key.schema")
+	if err != nil {
+		return nil, err
+	}
+	value, valueHeaders, err := e.serializer.serialize(schemaChangeValueSchema, map[string]any{
+		"source":       e.source(ch),
+		"ts_ms":        tsMs,
+		"databaseName": ch.keyspace,
+		"ddl":          ch.ddl,
+	}, "value.schema")
+	if err != nil {
+		return nil, err
+	}
+	return &kgo.Record{Key: key, Value: value, Headers: append(headers, valueHeaders...)}, nil
+}
+
+// jsonSerializer writes records the way the Kafka Connect JSON converter does
+// with schemas enabled, with the schema embedded next to the payload.
+type jsonSerializer struct{}
+
+func (jsonSerializer) serialize(schema *connectSchema, payload map[string]any, _ string) ([]byte, []kgo.RecordHeader, error) {
+	b, err := json.Marshal(&connectRecord{Schema: schema, Payload: payload})
+	return b, nil, err
+}
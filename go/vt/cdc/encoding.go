@@ -0,0 +1,265 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	// FormatJSON encodes records as Debezium JSON, with the Kafka Connect
+	// schema embedded in every key and value.
+	FormatJSON = "json"
+	// FormatAvro encodes records as Debezium Avro, using the Avro single
+	// object encoding.
+	FormatAvro = "avro"
+)
+
+// connectorName is the name of the connector in the source block of the
+// records, and the namespace of the schemas shared by all tables.
+const connectorName = "vitess"
+
+// change is a row change or a schema change streamed from a keyspace,
+// ready to be encoded as a record.
+type change struct {
+	keyspace string
+	shard    string
+	table    string
+	// fields are the columns of the table when the row changed.
+	fields []*querypb.Field
+	before *querypb.Row
+	after  *querypb.Row
+	// ddl is the statement of a schema change.
+	ddl string
+	// snapshot is set for rows that were read while the table was copied.
+	snapshot bool
+	// timestamp is the time of the binlog event, in seconds.
+	timestamp int64
+	// vgtid is the JSON encoded position of the transaction.
+	vgtid string
+}
+
+// op returns the Debezium operation of a row change.
+func (ch *change) op() string {
+	switch {
+	case ch.before == nil && ch.snapshot:
+		return "r"
+	case ch.before == nil:
+		return "c"
+	case ch.after == nil:
+		return "d"
+	default:
+		return "u"
+	}
+}
+
+// encoder turns changes into Kafka records. The records it returns have no
+// topic; the connector sets it.
+type encoder interface {
+	// encodeRow returns the records of a row change. Deletes return a
+	// tombstone record after the change record, so that compacted topics
+	// eventually drop the row.
+	encodeRow(ch *change, tsMs int64) ([]*kgo.Record, error)
+	// encodeSchemaChange returns the record of a DDL.
+	encodeSchemaChange(ch *change, tsMs int64) (*kgo.Record, error)
+}
+
+func newEncoder(format, topicPrefix string) (encoder, error) {
+	switch format {
+	case FormatJSON:
+		return &debeziumEncoder{topicPrefix: topicPrefix, serializer: jsonSerializer{}, tables: map[string]*debeziumTable{}}, nil
+	case FormatAvro:
+		return &debeziumEncoder{topicPrefix: topicPrefix, serializer: newAvroSerializer(), tables: map[string]*debeziumTable{}}, nil
+	}
+	return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unsupported format %q, must be %q or %q", format, FormatJSON, FormatAvro)
+}
+
+// topicName returns the topic of the row changes of a table.
+func topicName(topicPrefix, keyspace, table string) string {
+	return fmt.Sprintf("%s.%s.%s", topicPrefix, keyspace, table)
+}
+
+// Kafka Connect types of the columns.
+const (
+	typeInt16  = "int16"
+	typeInt32  = "int32"
+	typeInt64  = "int64"
+	typeFloat  = "float"
+	typeDouble = "double"
+	typeString = "string"
+	typeBytes  = "bytes"
+	typeStruct = "struct"
+)
+
+// columnType is the Kafka Connect type of a column, along with the name of
+// the Debezium semantic type the column maps to, if any.
+type columnType struct {
+	connect string
+	name    string
+}
+
+// fieldType maps a MySQL column to the type Debezium uses for it, with
+// decimals as strings and temporal types in their string form.
+func fieldType(field *querypb.Field) columnType {
+	typ := field.Type
+	switch {
+	case typ == sqltypes.Int8 || typ == sqltypes.Uint8 || typ == sqltypes.Int16:
+		return columnType{connect: typeInt16}
+	case typ == sqltypes.Uint16 || typ == sqltypes.Int24 || typ == sqltypes.Uint24 || typ == sqltypes.Int32:
+		return columnType{connect: typeInt32}
+	case typ == sqltypes.Year:
+		return columnType{connect: typeInt32, name: "io.debezium.time.Year"}
+	case sqltypes.IsIntegral(typ):
+		return columnType{connect: typeInt64}
+	case typ == sqltypes.Float32:
+		return columnType{connect: typeFloat}
+	case sqltypes.IsFloat(typ):
+		return columnType{connect: typeDouble}
+	case typ == sqltypes.TypeJSON:
+		return columnType{connect: typeString, name: "io.debezium.data.Json"}
+	case typ == sqltypes.Enum:
+		return columnType{connect: typeString, name: "io.debezium.data.Enum"}
+	case typ == sqltypes.Set:
+		return columnType{connect: typeString, name: "io.debezium.data.EnumSet"}
+	case typ == sqltypes.Bit:
+		return columnType{connect: typeBytes, name: "io.debezium.data.Bits"}
+	case sqltypes.IsBinary(typ) || typ == sqltypes.Geometry:
+		return columnType{connect: typeBytes}
+	}
+	return columnType{connect: typeString}
+}
+
+// isPrimaryKey returns true if the column is part of the primary key.
+func isPrimaryKey(field *querypb.Field) bool {
+	return field.Flags&uint32(querypb.MySqlFlag_PRI_KEY_FLAG) != 0
+}
+
+// tableSchema is the shape of the records of a table, derived from the
+// fields the table was last streamed with.
+type tableSchema struct {
+	fields []*querypb.Field
+	types  []columnType
+	// pk are the indexes of the primary key columns.
+	pk []int
+}
+
+func newTableSchema(fields []*querypb.Field) *tableSchema {
+	ts := &tableSchema{fields: fields}
+	for i, field := range fields {
+		ts.types = append(ts.types, fieldType(field))
+		if isPrimaryKey(field) {
+			ts.pk = append(ts.pk, i)
+		}
+	}
+	return ts
+}
+
+// matches returns true if the schema was derived from the same columns.
+func (ts *tableSchema) matches(fields []*querypb.Field) bool {
+	if len(ts.fields) != len(fields) {
+		return false
+	}
+	for i, field := range fields {
+		if field.Name != ts.fields[i].Name || field.Type != ts.fields[i].Type || field.Flags != ts.fields[i].Flags {
+			return false
+		}
+	}
+	return true
+}
+
+// values returns the column values of a row, converted to the Go types of
+// their columns: int64, float64, string or []byte.
+func (ts *tableSchema) values(row *querypb.Row) ([]any, error) {
+	if row == nil {
+		return nil, nil
+	}
+	vals := sqltypes.MakeRowTrusted(ts.fields, row)
+	if len(vals) != len(ts.fields) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "row has %d values, table has %d columns", len(vals), len(ts.fields))
+	}
+	values := make([]any, len(vals))
+	for i, val := range vals {
+		v, err := columnValue(ts.types[i], val)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "column %s", ts.fields[i].Name)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func columnValue(ct columnType, val sqltypes.Value) (any, error) {
+	if val.IsNull() {
+		return nil, nil
+	}
+	switch ct.connect {
+	case typeInt16, typeInt32, typeInt64:
+		if val.IsUnsigned() {
+			// Like Debezium, unsigned BIGINT values above the int64 range
+			// wrap around.
+			u, err := val.ToUint64()
+			return int64(u), err
+		}
+		return val.ToInt64()
+	case typeFloat, typeDouble:
+		return val.ToFloat64()
+	case typeBytes:
+		return val.ToBytes()
+	}
+	return val.ToString(), nil
+}
+
+// recordName returns a schema name made of the given segments, with the
+// characters that schema names do not allow replaced by underscores.
+func recordName(segments ...string) string {
+	names := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		for _, s := range strings.Split(segment, ".") {
+			names = append(names, sanitizeName(s))
+		}
+	}
+	return strings.Join(names, ".")
+}
+
+func sanitizeName(name string) string {
+	var sb strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteByte('_')
+			}
+		default:
+			r = '_'
+		}
+		sb.WriteRune(r)
+	}
+	if sb.Len() == 0 {
+		return "_"
+	}
+	return sb.String()
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdc
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestFieldType(t *testing.T) {
+	testcases := []struct {
+		typ  querypb.Type
+		want columnType
+	}{
+		{typ: sqltypes.Int8, want: columnType{connect: typeInt16}},
+		{typ: sqltypes.Uint16, want: columnType{connect: typeInt32}},
+		{typ: sqltypes.Uint64, want: columnType{connect: typeInt64}},
+		{typ: sqltypes.Float32, want: columnType{connect: typeFloat}},
+		{typ: sqltypes.Float64, want: columnType{connect: typeDouble}},
+		{typ: sqltypes.Decimal, want: columnType{connect: typeString}},
+		{typ: sqltypes.Datetime, want: columnType{connect: typeString}},
+		{typ: sqltypes.TypeJSON, want: columnType{connect: typeString, name: "io.debezium.data.Json"}},
+		{typ: sqltypes.VarBinary, want: columnType{connect: typeBytes}},
+		{typ: sqltypes.Bit, want: columnType{connect: typeBytes, name: "io.debezium.data.Bits"}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.typ.String(), func(t *testing.T) {
+			assert.Equal(t, tc.want, fieldType(&querypb.Field{Type: tc.typ}))
+		})
+	}
+}
+
+func TestChangeOp(t *testing.T) {
+	row := testRow(1, "a")
+	assert.Equal(t, "c", (&change{after: row}).op())
+	assert.Equal(t, "r", (&change{after: row, snapshot: true}).op())
+	assert.Equal(t, "u", (&change{before: row, after: row}).op())
+	assert.Equal(t, "d", (&change{before: row}).op())
+}
+
+func TestRecordName(t *testing.T) {
+	assert.Equal(t, "cdc.commerce_v2.t1", recordName("cdc", "commerce-v2", "t1"))
+	assert.Equal(t, "cdc.ks._1table", recordName("cdc.ks", "1table"))
+}
+
+func TestAvroEncoder(t *testing.T) {
+	enc, err := newEncoder(FormatAvro, "cdc")
+	require.NoError(t, err)
+	records, err := enc.encodeRow(&change{
+		keyspace: "ks",
+		shard:    "0",
+		table:    "t1",
+		fields:   testFields,
+		after:    testRow(1, "a"),
+		vgtid:    "{}",
+	}, 1000)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	record := records[0]
+
+	headers := map[string]string{}
+	for _, header := range record.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+	keySchema := `{"name":"cdc.ks.t1.Key","type":"record","fields":[{"name":"id","type":"long"}]}`
+	assert.Equal(t, keySchema, headers["avro.key.schema"])
+	assert.Contains(t, headers["avro.value.schema"], `{"name":"after","type":["null","cdc.ks.t1.Value"]}`)
+
+	// Marker, fingerprint of the schema, and the id 1 as a zigzag varint.
+	want := append([]byte{0xc3, 0x01}, binary.LittleEndian.AppendUint64(nil, avroFingerprint([]byte(keySchema)))...)
+	assert.Equal(t, append(want, 0x02), record.Key)
+
+	body := record.Value[10:]
+	// No before image, then an after image with id 1 and val "a", each
+	// column being a union with null.
+	assert.Equal(t, []byte{0x00, 0x02, 0x02, 0x02, 0x02, 0x02, 'a'}, body[:7])
+}
+
+func TestAvroFingerprint(t *testing.T) {
+	assert.Equal(t, uint64(avroFingerprintEmpty), avroFingerprint(nil))
+	assert.NotEqual(t, avroFingerprint([]byte(`"int"`)), avroFingerprint([]byte(`"long"`)))
+}
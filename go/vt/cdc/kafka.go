@@ -0,0 +1,155 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// KafkaConfig is the configuration of the connection to Kafka compatible
+// brokers.
+type KafkaConfig struct {
+	// Brokers are the addresses of the brokers to bootstrap from.
+	Brokers []string
+	// TransactionalID is the transactional ID of the producer. Starting a
+	// producer with the same ID fences off the previous one, so it must be
+	// unique to the connector.
+	TransactionalID string
+	// ReplicationFactor is the replication factor of the checkpoint topic
+	// when it is created. -1 uses the broker default.
+	ReplicationFactor int16
+}
+
+// kafkaBroker produces to Kafka compatible brokers with a transactional
+// producer.
+type kafkaBroker struct {
+	cfg    KafkaConfig
+	opts   []kgo.Opt
+	client *kgo.Client
+}
+
+// NewKafkaBroker returns a broker that produces to Kafka. Extra options are
+// passed to the Kafka clients, to configure TLS or SASL for example.
+func NewKafkaBroker(cfg KafkaConfig, opts ...kgo.Opt) (Broker, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no brokers specified")
+	}
+	if cfg.TransactionalID == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no transactional ID specified")
+	}
+	client, err := kgo.NewClient(append([]kgo.Opt{
+		kgo.SeedBrokers(cfg.Brokers...),
+		kgo.TransactionalID(cfg.TransactionalID),
+		kgo.AllowAutoTopicCreation(),
+	}, opts...)...)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "failed to create Kafka client")
+	}
+	return &kafkaBroker{cfg: cfg, opts: opts, client: client}, nil
+}
+
+// Checkpoint is part of the Broker interface. The topic is created as a
+// compacted topic with a single partition if it does not exist.
+func (kb *kafkaBroker) Checkpoint(ctx context.Context, topic, key string) ([]byte, error) {
+	adm := kadm.NewClient(kb.client)
+	compact := "compact"
+	_, err := adm.CreateTopic(ctx, 1, kb.cfg.ReplicationFactor, map[string]*string{"cleanup.policy": &compact}, topic)
+	if err != nil && !errors.Is(err, kerr.TopicAlreadyExists) {
+		return nil, vterrors.Wrapf(err, "failed to create checkpoint topic %s", topic)
+	}
+	// The last stable offset is past the last decided transaction.
+	offsets, err := adm.ListCommittedOffsets(ctx, topic)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to list offsets of checkpoint topic %s", topic)
+	}
+	end, ok := offsets.Lookup(topic, 0)
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "no offsets for checkpoint topic %s", topic)
+	}
+	if end.Err != nil {
+		return nil, vterrors.Wrapf(end.Err, "failed to list offsets of checkpoint topic %s", topic)
+	}
+	if end.Offset <= 0 {
+		return nil, nil
+	}
+
+	// Read the topic up to that offset. The commit and abort markers of the
+	// transactions are kept, so that the offset is reached even when the
+	// last transaction was aborted.
+	consumer, err := kgo.NewClient(append([]kgo.Opt{
+		kgo.SeedBrokers(kb.cfg.Brokers...),
+		kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{topic: {0: kgo.NewOffset().AtStart()}}),
+		kgo.FetchIsolationLevel(kgo.ReadCommitted()),
+		kgo.KeepControlRecords(),
+	}, kb.opts...)...)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "failed to create Kafka client")
+	}
+	defer consumer.Close()
+	var checkpoint []byte
+	for done := false; !done; {
+		fetches := consumer.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, ferr := range fetches.Errors() {
+			return nil, vterrors.Wrapf(ferr.Err, "failed to read checkpoint topic %s", topic)
+		}
+		fetches.EachRecord(func(r *kgo.Record) {
+			if r.Offset >= end.Offset-1 {
+				done = true
+			}
+			if !r.Attrs.IsControl() && string(r.Key) == key {
+				checkpoint = r.Value
+			}
+		})
+	}
+	return checkpoint, nil
+}
+
+// Produce is part of the Broker interface.
+func (kb *kafkaBroker) Produce(ctx context.Context, records []*kgo.Record) error {
+	if err := kb.client.BeginTransaction(); err != nil {
+		return vterrors.Wrap(err, "failed to begin transaction")
+	}
+	if err := kb.client.ProduceSync(ctx, records...).FirstErr(); err != nil {
+		// Abort the transaction, so that the transactional ID can be used
+		// again right away once the connector restarts.
+		if aerr := kb.client.AbortBufferedRecords(context.Background()); aerr == nil {
+			_ = kb.client.EndTransaction(context.Background(), kgo.TryAbort)
+		}
+		return vterrors.Wrap(err, "failed to produce records")
+	}
+	if err := kb.client.EndTransaction(ctx, kgo.TryCommit); err != nil {
+		return vterrors.Wrap(err, "failed to commit transaction")
+	}
+	return nil
+}
+
+// Close is part of the Broker interface.
+func (kb *kafkaBroker) Close() {
+	kb.client.Close()
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/json2"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+// newKafkaCluster starts an in-process Kafka cluster and returns it along
+// with a broker connected to it.
+func newKafkaCluster(t *testing.T) (*kfake.Cluster, Broker) {
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1))
+	require.NoError(t, err)
+	t.Cleanup(cluster.Close)
+	broker, err := NewKafkaBroker(KafkaConfig{
+		Brokers:         cluster.ListenAddrs(),
+		TransactionalID: "test",
+	})
+	require.NoError(t, err)
+	t.Cleanup(broker.Close)
+	return cluster, broker
+}
+
+// produceCheckpoints commits checkpoint records outside of a transaction.
+func produceCheckpoints(ctx context.Context, t *testing.T, cluster *kfake.Cluster, topic string, records ...*kgo.Record) {
+	client, err := kgo.NewClient(kgo.SeedBrokers(cluster.ListenAddrs()...), kgo.DefaultProduceTopic(topic))
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.ProduceSync(ctx, records...).FirstErr())
+}
+
+func vgtidCheckpoint(t *testing.T, key, gtid string) *kgo.Record {
+	value, err := json2.MarshalPB(vgtid(gtid))
+	require.NoError(t, err)
+	return &kgo.Record{Key: []byte(key), Value: value}
+}
+
+func TestKafkaBrokerCheckpoint(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	cluster, broker := newKafkaCluster(t)
+
+	// The checkpoint topic is created empty.
+	checkpoint, err := broker.Checkpoint(ctx, "cdc-checkpoints", "test")
+	require.NoError(t, err)
+	assert.Nil(t, checkpoint)
+
+	produceCheckpoints(ctx, t, cluster, "cdc-checkpoints",
+		&kgo.Record{Key: []byte("test"), Value: []byte("pos1")},
+		&kgo.Record{Key: []byte("other"), Value: []byte("pos1")},
+		&kgo.Record{Key: []byte("test"), Value: []byte("pos2")},
+		&kgo.Record{Key: []byte("other"), Value: []byte("pos3")},
+	)
+	checkpoint, err = broker.Checkpoint(ctx, "cdc-checkpoints", "test")
+	require.NoError(t, err)
+	assert.Equal(t, "pos2", string(checkpoint))
+	checkpoint, err = broker.Checkpoint(ctx, "cdc-checkpoints", "other")
+	require.NoError(t, err)
+	assert.Equal(t, "pos3", string(checkpoint))
+	checkpoint, err = broker.Checkpoint(ctx, "cdc-checkpoints", "missing")
+	require.NoError(t, err)
+	assert.Nil(t, checkpoint)
+}
+
+func TestKafkaBrokerResume(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	cluster, broker := newKafkaCluster(t)
+	_, err := broker.Checkpoint(ctx, "cdc-checkpoints", "test")
+	require.NoError(t, err)
+	produceCheckpoints(ctx, t, cluster, "cdc-checkpoints",
+		vgtidCheckpoint(t, "test", "pos1"),
+		vgtidCheckpoint(t, "test", "pos2"),
+		vgtidCheckpoint(t, "other", "pos3"),
+	)
+
+	cfg := Config{
+		Name:            "test",
+		TopicPrefix:     "cdc",
+		CheckpointTopic: "cdc-checkpoints",
+		Format:          FormatJSON,
+		VGtid:           vgtid("current"),
+	}
+	starts := make(chan *binlogdatapb.VGtid, 1)
+	conn, err := NewConnector(cfg, broker, fakeStreamer(starts))
+	require.NoError(t, err)
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	done := make(chan error)
+	go func() {
+		done <- conn.Run(runCtx)
+	}()
+	// The connector resumes from its last committed checkpoint rather than
+	// from the starting position.
+	assert.True(t, proto.Equal(vgtid("pos2"), <-starts))
+	stop()
+	require.NoError(t, <-done)
+}
+
+// emulateTransactions makes the cluster accept transactional producers, which
+// kfake does not support: it advertises and acknowledges the transaction
+// requests, fails every transactional produce, and reports the outcome of
+// each EndTxn request on the returned channel.
+func emulateTransactions(ctx context.Context, t *testing.T, cluster *kfake.Cluster) <-chan bool {
+	client, err := kgo.NewClient(kgo.SeedBrokers(cluster.ListenAddrs()...))
+	require.NoError(t, err)
+	defer client.Close()
+	versions, err := kmsg.NewPtrApiVersionsRequest().RequestWith(ctx, client)
+	require.NoError(t, err)
+	for _, key := range []kmsg.Key{kmsg.AddPartitionsToTxn, kmsg.EndTxn} {
+		versions.ApiKeys = append(versions.ApiKeys, kmsg.ApiVersionsResponseApiKey{ApiKey: key.Int16(), MaxVersion: 3})
+	}
+
+	cluster.ControlKey(kmsg.ApiVersions.Int16(), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		cluster.KeepControl()
+		resp := *versions
+		resp.Version = kreq.GetVersion()
+		return &resp, nil, true
+	})
+	cluster.ControlKey(kmsg.InitProducerID.Int16(), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		cluster.KeepControl()
+		req := kreq.(*kmsg.InitProducerIDRequest)
+		if req.TransactionalID == nil {
+			return nil, nil, false
+		}
+		resp := req.ResponseKind().(*kmsg.InitProducerIDResponse)
+		resp.ProducerID = 1000
+		return resp, nil, true
+	})
+	cluster.ControlKey(kmsg.AddPartitionsToTxn.Int16(), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		cluster.KeepControl()
+		req := kreq.(*kmsg.AddPartitionsToTxnRequest)
+		resp := req.ResponseKind().(*kmsg.AddPartitionsToTxnResponse)
+		for _, topic := range req.Topics {
+			rt := kmsg.NewAddPartitionsToTxnResponseTopic()
+			rt.Topic = topic.Topic
+			for _, partition := range topic.Partitions {
+				rp := kmsg.NewAddPartitionsToTxnResponseTopicPartition()
+				rp.Partition = partition
+				rt.Partitions = append(rt.Partitions, rp)
+			}
+			resp.Topics = append(resp.Topics, rt)
+		}
+		return resp, nil, true
+	})
+	cluster.ControlKey(kmsg.Produce.Int16(), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		cluster.KeepControl()
+		req := kreq.(*kmsg.ProduceRequest)
+		if req.TransactionID == nil {
+			return nil, nil, false
+		}
+		resp := req.ResponseKind().(*kmsg.ProduceResponse)
+		for _, topic := range req.Topics {
+			rt := kmsg.NewProduceResponseTopic()
+			rt.Topic = topic.Topic
+			for _, partition := range topic.Partitions {
+				rp := kmsg.NewProduceResponseTopicPartition()
+				rp.Partition = partition.Partition
+				rp.ErrorCode = kerr.InvalidRecord.Code
+				rt.Partitions = append(rt.Partitions, rp)
+			}
+			resp.Topics = append(resp.Topics, rt)
+		}
+		return resp, nil, true
+	})
+	ends := make(chan bool, 10)
+	cluster.ControlKey(kmsg.EndTxn.Int16(), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		cluster.KeepControl()
+		req := kreq.(*kmsg.EndTxnRequest)
+		ends <- req.Commit
+		return req.ResponseKind(), nil, true
+	})
+	return ends
+}
+
+func TestKafkaBrokerAbortedTransaction(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1))
+	require.NoError(t, err)
+	defer cluster.Close()
+	ends := emulateTransactions(ctx, t, cluster)
+	broker, err := NewKafkaBroker(KafkaConfig{
+		Brokers:         cluster.ListenAddrs(),
+		TransactionalID: "test",
+	})
+	require.NoError(t, err)
+	defer broker.Close()
+	_, err = broker.Checkpoint(ctx, "cdc-checkpoints", "test")
+	require.NoError(t, err)
+	produceCheckpoints(ctx, t, cluster, "cdc-checkpoints", vgtidCheckpoint(t, "test", "pos1"))
+
+	cfg := Config{
+		Name:            "test",
+		TopicPrefix:     "cdc",
+		CheckpointTopic: "cdc-checkpoints",
+		Format:          FormatJSON,
+	}
+	batch := []*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_FIELD, FieldEvent: &binlogdatapb.FieldEvent{TableName: "ks.t1", Keyspace: "ks", Shard: "0", Fields: testFields}},
+		{Type: binlogdatapb.VEventType_BEGIN},
+		rowEvent(&binlogdatapb.RowChange{After: testRow(1, "a")}),
+		{Type: binlogdatapb.VEventType_VGTID, Vgtid: vgtid("pos2")},
+		{Type: binlogdatapb.VEventType_COMMIT},
+	}
+	starts := make(chan *binlogdatapb.VGtid, 1)
+	conn, err := NewConnector(cfg, broker, fakeStreamer(starts, batch))
+	require.NoError(t, err)
+	// The failed produce aborts the transaction and stops the connector.
+	require.ErrorContains(t, conn.Run(ctx), "failed to produce records")
+	assert.True(t, proto.Equal(vgtid("pos1"), <-starts))
+	select {
+	case commit := <-ends:
+		assert.False(t, commit, "transaction was committed")
+	default:
+		t.Fatal("transaction was not aborted")
+	}
+
+	// The checkpoint did not move, so a restarted connector streams the
+	// aborted batch again.
+	checkpoint, err := broker.Checkpoint(ctx, "cdc-checkpoints", "test")
+	require.NoError(t, err)
+	want, err := json2.MarshalPB(vgtid("pos1"))
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(checkpoint))
+	conn, err = NewConnector(cfg, broker, fakeStreamer(starts))
+	require.NoError(t, err)
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	done := make(chan error)
+	go func() {
+		done <- conn.Run(runCtx)
+	}()
+	assert.True(t, proto.Equal(vgtid("pos1"), <-starts))
+	stop()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package memorybroker contains an in-memory stand-in for a Kafka compatible
+// broker, to test CDC connectors without one. Transactions are atomic: the
+// records of a failed produce are never visible.
+package memorybroker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Broker keeps the committed records of every topic in memory.
+type Broker struct {
+	mu sync.Mutex
+	// topics are the committed records, by topic.
+	topics map[string][]*kgo.Record
+	// produceErr fails the next produce when set.
+	produceErr error
+	// changed is closed and replaced every time records are committed.
+	changed chan struct{}
+}
+
+// New returns an empty broker.
+func New() *Broker {
+	return &Broker{
+		topics:  map[string][]*kgo.Record{},
+		changed: make(chan struct{}),
+	}
+}
+
+// Checkpoint returns the value of the last committed record with the given
+// key in the topic, or nil if there is none.
+func (b *Broker) Checkpoint(ctx context.Context, topic, key string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	records := b.topics[topic]
+	for i := len(records) - 1; i >= 0; i-- {
+		if string(records[i].Key) == key {
+			return records[i].Value, nil
+		}
+	}
+	return nil, nil
+}
+
+// Produce commits the records in a single transaction, unless a failure was
+// injected with FailNextProduce.
+func (b *Broker) Produce(ctx context.Context, records []*kgo.Record) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.produceErr; err != nil {
+		b.produceErr = nil
+		return err
+	}
+	for _, record := range records {
+		record.Offset = int64(len(b.topics[record.Topic]))
+		b.topics[record.Topic] = append(b.topics[record.Topic], record)
+	}
+	close(b.changed)
+	b.changed = make(chan struct{})
+	return nil
+}
+
+// Close is part of the cdc.Broker interface.
+func (b *Broker) Close() {}
+
+// FailNextProduce makes the next produce fail with the given error, without
+// committing any of its records.
+func (b *Broker) FailNextProduce(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.produceErr = err
+}
+
+// Records returns the committed records of a topic.
+func (b *Broker) Records(topic string) []*kgo.Record {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*kgo.Record(nil), b.topics[topic]...)
+}
+
+// WaitForRecords waits until the topic has at least count committed records
+// and returns them.
+func (b *Broker) WaitForRecords(ctx context.Context, topic string, count int) ([]*kgo.Record, error) {
+	for {
+		b.mu.Lock()
+		records := append([]*kgo.Record(nil), b.topics[topic]...)
+		changed := b.changed
+		b.mu.Unlock()
+		if len(records) >= count {
+			return records, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-changed:
+		}
+	}
+}
@@ -90,6 +90,14 @@ var (
 	// once before the writer blocks
 	backupCompressBlocks = 2
 
+	// backupMaxBytesPerSec limits the rate at which backups read the data they back up, and
+	// restores write it. Zero means no limit.
+	backupMaxBytesPerSec int64
+
+	// backupMaxIOPS limits the number of read (backup) or write (restore) operations per
+	// second. Zero means no limit.
+	backupMaxIOPS int
+
 	EmptyBackupMessage = "no new data to backup, skipping it"
 )
 
@@ -115,6 +123,8 @@ func registerBackupFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&backupStorageCompress, "backup_storage_compress", backupStorageCompress, "if set, the backup files will be compressed.")
 	fs.IntVar(&backupCompressBlockSize, "backup_storage_block_size", backupCompressBlockSize, "if backup_storage_compress is true, backup_storage_block_size sets the byte size for each block while compressing (default is 250000).")
 	fs.IntVar(&backupCompressBlocks, "backup_storage_number_blocks", backupCompressBlocks, "if backup_storage_compress is true, backup_storage_number_blocks sets the number of blocks that can be processed, in parallel, before the writer blocks, during compression (default is 2). It should be equal to the number of CPUs available for compression.")
+	fs.Int64Var(&backupMaxBytesPerSec, "backup-max-bytes-per-sec", backupMaxBytesPerSec, "limits the rate, in bytes per second, at which a backup reads the data it backs up, and at which a restore writes it. The limit is shared by all files processed concurrently. Zero means no limit.")
+	fs.IntVar(&backupMaxIOPS, "backup-max-iops", backupMaxIOPS, "limits the number of read operations per second of a backup, and write operations per second of a restore. The limit is shared by all files processed concurrently. Zero means no limit.")
 }
 
 // Backup is the main entry point for a backup:
@@ -395,9 +405,9 @@ func Restore(ctx context.Context, params RestoreParams) (*BackupManifest, error)
 	// Backups are stored in a directory structure that starts with
 	// <keyspace>/<shard>
 	backupDir := GetBackupDir(params.Keyspace, params.Shard)
-	bhs, err := bs.ListBackups(ctx, backupDir)
+	bhs, secondaryFallbacks, err := listBackupsToRestore(ctx, params.Logger, bs, params.Keyspace, params.Shard, params.IsIncrementalRecovery())
 	if err != nil {
-		return nil, vterrors.Wrap(err, "ListBackups failed")
+		return nil, err
 	}
 
 	if len(bhs) == 0 {
@@ -446,7 +456,14 @@ func Restore(ctx context.Context, params RestoreParams) (*BackupManifest, error)
 	)
 	manifest, err := re.ExecuteRestore(ctx, reParams, bh)
 	if err != nil {
-		return nil, err
+		secondaryBh, ok := secondaryFallbacks[backupHandlePath(bh)]
+		if !ok {
+			return nil, err
+		}
+		params.Logger.Warningf("Restore: failed to restore %v: %v. Restoring its copy from secondary backup storage", bh.Name(), err)
+		if manifest, err = re.ExecuteRestore(ctx, reParams, secondaryBh); err != nil {
+			return nil, err
+		}
 	}
 
 	if re.ShouldStartMySQLAfterRestore() { // all engines except mysqlshell since MySQL is always running there
@@ -489,6 +506,10 @@ func Restore(ctx context.Context, params RestoreParams) (*BackupManifest, error)
 		builtInRE := BackupRestoreEngineMap[builtinBackupEngineName]
 		for _, bh := range handles {
 			manifest, err := builtInRE.ExecuteRestore(ctx, params, bh)
+			if secondaryBh, ok := secondaryFallbacks[backupHandlePath(bh)]; err != nil && ok {
+				params.Logger.Warningf("Restore: failed to apply incremental backup %v: %v. Applying its copy from secondary backup storage", bh.Name(), err)
+				manifest, err = builtInRE.ExecuteRestore(ctx, params, secondaryBh)
+			}
 			if err != nil {
 				return nil, err
 			}
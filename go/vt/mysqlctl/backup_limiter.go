@@ -0,0 +1,162 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"io"
+	"math"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	stats "vitess.io/vitess/go/vt/mysqlctl/backupstats"
+)
+
+// BackupThrottler is consulted by the backup engines before each read of the data they back up,
+// so that a backup taken on a serving tablet backs off when the tablet is under pressure.
+type BackupThrottler interface {
+	// ThrottleCheckOKOrWait returns true if the backup may proceed. Otherwise, it
+	// briefly sleeps and returns false.
+	ThrottleCheckOKOrWait(ctx context.Context) bool
+}
+
+// ioLimiter limits the IO of a backup or a restore. A single ioLimiter is shared by all files
+// of a backup or a restore, so that the limits apply to the operation as a whole regardless
+// of its concurrency.
+type ioLimiter struct {
+	bytes     *rate.Limiter
+	ops       *rate.Limiter
+	throttler BackupThrottler
+	stats     stats.Stats
+}
+
+// newIOLimiter returns an ioLimiter based on the backup-max-* flags and the given throttler.
+// It returns nil if there is nothing to limit.
+func newIOLimiter(throttler BackupThrottler, st stats.Stats) *ioLimiter {
+	if backupMaxBytesPerSec <= 0 && backupMaxIOPS <= 0 && throttler == nil {
+		return nil
+	}
+	l := &ioLimiter{
+		throttler: throttler,
+		stats:     st,
+	}
+	if backupMaxBytesPerSec > 0 {
+		// Allow one second worth of IO in a single burst. Reads and writes are split to fit
+		// in a burst, see maxChunk().
+		burst := int(min(backupMaxBytesPerSec, math.MaxInt32))
+		l.bytes = rate.NewLimiter(rate.Limit(backupMaxBytesPerSec), burst)
+	}
+	if backupMaxIOPS > 0 {
+		l.ops = rate.NewLimiter(rate.Limit(backupMaxIOPS), backupMaxIOPS)
+	}
+	return l
+}
+
+// maxChunk returns the largest number of bytes a single IO operation may transfer.
+func (l *ioLimiter) maxChunk(n int) int {
+	if l.bytes != nil && n > l.bytes.Burst() {
+		return l.bytes.Burst()
+	}
+	return n
+}
+
+// wait blocks until an IO operation of n bytes is allowed to proceed. Operations larger than
+// the burst wait for their bytes one burst at a time.
+func (l *ioLimiter) wait(ctx context.Context, n int) error {
+	waitStart := time.Now()
+	defer func() {
+		if waited := time.Since(waitStart); waited > time.Millisecond && l.stats != nil {
+			l.stats.Scope(stats.Operation("Limiter:Wait")).TimedIncrement(waited)
+		}
+	}()
+	if l.throttler != nil {
+		for !l.throttler.ThrottleCheckOKOrWait(ctx) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+	if l.ops != nil {
+		if err := l.ops.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	for l.bytes != nil && n > 0 {
+		chunk := l.maxChunk(n)
+		if err := l.bytes.WaitN(ctx, chunk); err != nil {
+			return err
+		}
+		n -= chunk
+	}
+	return nil
+}
+
+// reader wraps r so that every read is subject to the limiter. A nil limiter returns r as is.
+func (l *ioLimiter) reader(ctx context.Context, r io.Reader) io.Reader {
+	if l == nil {
+		return r
+	}
+	return &limitedReader{ctx: ctx, limiter: l, r: r}
+}
+
+// writer wraps w so that every write is subject to the limiter. A nil limiter returns w as is.
+func (l *ioLimiter) writer(ctx context.Context, w io.Writer) io.Writer {
+	if l == nil {
+		return w
+	}
+	return &limitedWriter{ctx: ctx, limiter: l, w: w}
+}
+
+type limitedReader struct {
+	ctx     context.Context
+	limiter *ioLimiter
+	r       io.Reader
+}
+
+// Read is part of the io.Reader interface.
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	p = p[:lr.limiter.maxChunk(len(p))]
+	if err := lr.limiter.wait(lr.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return lr.r.Read(p)
+}
+
+type limitedWriter struct {
+	ctx     context.Context
+	limiter *ioLimiter
+	w       io.Writer
+}
+
+// Write is part of the io.Writer interface.
+func (lw *limitedWriter) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		chunk := lw.limiter.maxChunk(len(p))
+		if err := lw.limiter.wait(lw.ctx, chunk); err != nil {
+			return written, err
+		}
+		n, err := lw.w.Write(p[:chunk])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[chunk:]
+	}
+	return written, nil
+}
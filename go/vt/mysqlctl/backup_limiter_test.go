@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+)
+
+type fakeBackupThrottler struct {
+	throttledChecks int
+	checks          int
+}
+
+func (ft *fakeBackupThrottler) ThrottleCheckOKOrWait(ctx context.Context) bool {
+	ft.checks++
+	return ft.checks > ft.throttledChecks
+}
+
+func withBackupLimits(t *testing.T, maxBytesPerSec int64, maxIOPS int) {
+	previousMaxBytesPerSec, previousMaxIOPS := backupMaxBytesPerSec, backupMaxIOPS
+	backupMaxBytesPerSec, backupMaxIOPS = maxBytesPerSec, maxIOPS
+	t.Cleanup(func() {
+		backupMaxBytesPerSec, backupMaxIOPS = previousMaxBytesPerSec, previousMaxIOPS
+	})
+}
+
+func TestIOLimiterDisabled(t *testing.T) {
+	withBackupLimits(t, 0, 0)
+	limiter := newIOLimiter(nil, backupstats.NoStats())
+	require.Nil(t, limiter)
+
+	r := bytes.NewReader([]byte("data"))
+	assert.Equal(t, io.Reader(r), limiter.reader(context.Background(), r))
+	var w bytes.Buffer
+	assert.Equal(t, io.Writer(&w), limiter.writer(context.Background(), &w))
+}
+
+func TestIOLimiterBytes(t *testing.T) {
+	withBackupLimits(t, 64*1024, 0)
+	limiter := newIOLimiter(nil, backupstats.NoStats())
+	require.NotNil(t, limiter)
+
+	data := bytes.Repeat([]byte("a"), 96*1024)
+
+	// The first 64KiB are allowed as a burst, the next 32KiB take about half a second.
+	start := time.Now()
+	var w bytes.Buffer
+	n, err := limiter.writer(context.Background(), &w).Write(data)
+	require.NoError(t, err)
+	assert.Equal(t, len(data), n)
+	assert.Equal(t, data, w.Bytes())
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+
+	// Reads never exceed the burst size.
+	buf := make([]byte, 128*1024)
+	n, err = limiter.reader(context.Background(), bytes.NewReader(data)).Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, 64*1024, n)
+
+	// Operations larger than the burst wait for it more than once.
+	start = time.Now()
+	require.NoError(t, limiter.wait(context.Background(), 96*1024))
+	assert.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+}
+
+func TestIOLimiterIOPS(t *testing.T) {
+	withBackupLimits(t, 0, 10)
+	limiter := newIOLimiter(nil, backupstats.NoStats())
+	require.NotNil(t, limiter)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	r := limiter.reader(ctx, bytes.NewReader(bytes.Repeat([]byte("a"), 100)))
+	buf := make([]byte, 1)
+	reads := 0
+	for {
+		if _, err := r.Read(buf); err != nil {
+			break
+		}
+		reads++
+	}
+	// The burst allows 10 operations, after which the limiter runs out of time.
+	assert.Equal(t, 10, reads)
+}
+
+func TestIOLimiterThrottler(t *testing.T) {
+	withBackupLimits(t, 0, 0)
+	throttler := &fakeBackupThrottler{throttledChecks: 3}
+	limiter := newIOLimiter(throttler, backupstats.NoStats())
+	require.NotNil(t, limiter)
+
+	data, err := io.ReadAll(limiter.reader(context.Background(), bytes.NewReader([]byte("data"))))
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+	assert.Greater(t, throttler.checks, throttler.throttledChecks)
+
+	// A throttled backup gives up once its context is done.
+	throttler = &fakeBackupThrottler{throttledChecks: 1000}
+	limiter = newIOLimiter(throttler, backupstats.NoStats())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = limiter.reader(ctx, bytes.NewReader([]byte("data"))).Read(make([]byte, 4))
+	assert.ErrorIs(t, err, context.Canceled)
+}
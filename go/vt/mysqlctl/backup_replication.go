@@ -0,0 +1,327 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"sort"
+	"sync"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+var (
+	// backupReplicationMu makes sure a single ReplicateBackups runs at a time in this process.
+	backupReplicationMu sync.Mutex
+
+	backupReplicationCopies = stats.NewCounter("BackupReplicationCopies", "Number of backups copied to the secondary backup storage")
+	backupReplicationErrors = stats.NewCounter("BackupReplicationErrors", "Number of backups that failed to be copied to the secondary backup storage")
+)
+
+// ReplicateBackups copies the complete backups of the given shard, as well as its binlog
+// archive, to the secondary backup storage, unless the secondary backup storage already has
+// a complete copy of them. It does nothing if there is no secondary backup storage.
+func ReplicateBackups(ctx context.Context, logger logutil.Logger, keyspace, shard string) error {
+	secondary, err := backupstorage.GetSecondaryBackupStorage()
+	if err != nil || secondary == nil {
+		return err
+	}
+	defer secondary.Close()
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return err
+	}
+	defer bs.Close()
+
+	backupReplicationMu.Lock()
+	defer backupReplicationMu.Unlock()
+
+	var errs []error
+	for _, dir := range []string{GetBackupDir(keyspace, shard), GetBinlogArchiveDir(keyspace, shard)} {
+		if err := replicateBackupDir(ctx, logger, bs, secondary, dir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return vterrors.Aggregate(errs)
+}
+
+// replicateBackupDir copies the complete backups of a single directory of the backup
+// storage to the secondary backup storage.
+func replicateBackupDir(ctx context.Context, logger logutil.Logger, bs, secondary backupstorage.BackupStorage, dir string) error {
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return vterrors.Wrapf(err, "ListBackups failed for %v", dir)
+	}
+	secondaryBhs, err := secondary.ListBackups(ctx, dir)
+	if err != nil {
+		return vterrors.Wrapf(err, "ListBackups failed on secondary backup storage for %v", dir)
+	}
+	secondaryByName := make(map[string]backupstorage.BackupHandle, len(secondaryBhs))
+	for _, bh := range secondaryBhs {
+		secondaryByName[bh.Name()] = bh
+	}
+
+	var errs []error
+	for _, bh := range bhs {
+		manifestData, err := readBackupFile(ctx, bh, backupManifestFileName)
+		if err != nil {
+			// In progress or failed backup, there is nothing to copy (yet).
+			continue
+		}
+		if secondaryBh, ok := secondaryByName[bh.Name()]; ok {
+			if _, err := GetBackupManifest(ctx, secondaryBh); err == nil {
+				continue
+			}
+			// A previous copy did not complete. Start over.
+			if err := secondary.RemoveBackup(ctx, dir, bh.Name()); err != nil {
+				errs = append(errs, vterrors.Wrapf(err, "cannot remove incomplete copy of backup %v/%v", dir, bh.Name()))
+				continue
+			}
+		}
+		logger.Infof("Copying backup %v/%v to secondary backup storage", dir, bh.Name())
+		if err := replicateBackup(ctx, bh, secondary, manifestData); err != nil {
+			backupReplicationErrors.Add(1)
+			errs = append(errs, vterrors.Wrapf(err, "cannot copy backup %v/%v to secondary backup storage", dir, bh.Name()))
+			continue
+		}
+		backupReplicationCopies.Add(1)
+	}
+	return vterrors.Aggregate(errs)
+}
+
+// replicateBackup copies a single backup to the secondary backup storage. The MANIFEST is
+// written last, so that a partial copy is never mistaken for a complete backup.
+func replicateBackup(ctx context.Context, bh backupstorage.BackupHandle, secondary backupstorage.BackupStorage, manifestData []byte) (err error) {
+	fileNames, err := backupFileNames(manifestData)
+	if err != nil {
+		return err
+	}
+	secondaryBh, err := secondary.StartBackup(ctx, bh.Directory(), bh.Name())
+	if err != nil {
+		return vterrors.Wrap(err, "StartBackup failed")
+	}
+	defer func() {
+		if err != nil {
+			if abortErr := secondaryBh.AbortBackup(ctx); abortErr != nil {
+				err = vterrors.Wrapf(err, "AbortBackup failed: %v", abortErr)
+			}
+		}
+	}()
+
+	for _, fileName := range fileNames {
+		if err := copyBackupFile(ctx, bh, secondaryBh, fileName); err != nil {
+			return err
+		}
+	}
+	wc, err := secondaryBh.AddFile(ctx, backupManifestFileName, int64(len(manifestData)))
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot add %v", backupManifestFileName)
+	}
+	if _, err := wc.Write(manifestData); err != nil {
+		wc.Close()
+		return vterrors.Wrapf(err, "cannot write %v", backupManifestFileName)
+	}
+	if err := wc.Close(); err != nil {
+		return vterrors.Wrapf(err, "cannot close %v", backupManifestFileName)
+	}
+	return secondaryBh.EndBackup(ctx)
+}
+
+// copyBackupFile copies a single file from one backup to another.
+func copyBackupFile(ctx context.Context, from, to backupstorage.BackupHandle, fileName string) error {
+	rc, err := from.ReadFile(ctx, fileName)
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot read %v", fileName)
+	}
+	defer rc.Close()
+	wc, err := to.AddFile(ctx, fileName, backupstorage.FileSizeUnknown)
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot add %v", fileName)
+	}
+	if _, err := io.Copy(wc, rc); err != nil {
+		wc.Close()
+		return vterrors.Wrapf(err, "cannot copy %v", fileName)
+	}
+	if err := wc.Close(); err != nil {
+		return vterrors.Wrapf(err, "cannot close %v", fileName)
+	}
+	return nil
+}
+
+// backupFileNames returns the names of the files that make up a backup, based on its
+// MANIFEST. The MANIFEST itself is not included.
+func backupFileNames(manifestData []byte) ([]string, error) {
+	var manifest BackupManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, vterrors.Wrap(err, "can't decode MANIFEST")
+	}
+	switch manifest.BackupMethod {
+	case builtinBackupEngineName:
+		var bm builtinBackupManifest
+		if err := json.Unmarshal(manifestData, &bm); err != nil {
+			return nil, vterrors.Wrap(err, "can't decode MANIFEST")
+		}
+		fileNames := make([]string, 0, len(bm.FileEntries))
+		for i := range bm.FileEntries {
+			fileNames = append(fileNames, fmt.Sprintf("%v", i))
+		}
+		return fileNames, nil
+	case xtrabackupEngineName:
+		var bm xtraBackupManifest
+		if err := json.Unmarshal(manifestData, &bm); err != nil {
+			return nil, vterrors.Wrap(err, "can't decode MANIFEST")
+		}
+		if bm.FileName == "" {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "MANIFEST does not name the backup file")
+		}
+		if bm.NumStripes <= 1 {
+			return []string{bm.FileName}, nil
+		}
+		fileNames := make([]string, 0, bm.NumStripes)
+		for i := 0; i < int(bm.NumStripes); i++ {
+			fileNames = append(fileNames, stripeFileName(bm.FileName, i))
+		}
+		return fileNames, nil
+	case logicalBackupEngineName:
+		var bm logicalBackupManifest
+		if err := json.Unmarshal(manifestData, &bm); err != nil {
+			return nil, vterrors.Wrap(err, "can't decode MANIFEST")
+		}
+		var fileNames []string
+		for _, table := range bm.Tables {
+			fileNames = append(fileNames, table.Files...)
+		}
+		return fileNames, nil
+	default:
+		// mysqlshell backups, for instance, keep their data outside of the backup storage.
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "backups created with %q engine cannot be copied", manifest.BackupMethod)
+	}
+}
+
+// readBackupFile reads a whole file from a backup.
+func readBackupFile(ctx context.Context, bh backupstorage.BackupHandle, fileName string) ([]byte, error) {
+	rc, err := bh.ReadFile(ctx, fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, rc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// listSecondaryBackups returns the backups of the given directory in the secondary backup
+// storage, or nil if there is no secondary backup storage.
+func listSecondaryBackups(ctx context.Context, dir string) ([]backupstorage.BackupHandle, error) {
+	secondary, err := backupstorage.GetSecondaryBackupStorage()
+	if err != nil || secondary == nil {
+		return nil, err
+	}
+	defer secondary.Close()
+	bhs, err := secondary.ListBackups(ctx, dir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed on secondary backup storage")
+	}
+	return bhs, nil
+}
+
+// mergeSecondaryBackups merges the backups of the secondary backup storage into the given
+// backups. A secondary backup replaces a backup with the same name when the latter has no
+// usable MANIFEST. The returned map holds the secondary backups that were not used, by
+// backupHandlePath, so that a failed restore can fall back to them.
+func mergeSecondaryBackups(ctx context.Context, bhs []backupstorage.BackupHandle, secondaryBhs []backupstorage.BackupHandle) ([]backupstorage.BackupHandle, map[string]backupstorage.BackupHandle) {
+	fallbacks := make(map[string]backupstorage.BackupHandle, len(secondaryBhs))
+	for _, bh := range secondaryBhs {
+		fallbacks[backupHandlePath(bh)] = bh
+	}
+	merged := make([]backupstorage.BackupHandle, 0, len(bhs)+len(secondaryBhs))
+	for _, bh := range bhs {
+		secondaryBh, ok := fallbacks[backupHandlePath(bh)]
+		if !ok {
+			merged = append(merged, bh)
+			continue
+		}
+		if _, err := GetBackupManifest(ctx, bh); err != nil {
+			merged = append(merged, secondaryBh)
+			delete(fallbacks, backupHandlePath(bh))
+			continue
+		}
+		merged = append(merged, bh)
+	}
+	seen := make(map[string]bool, len(merged))
+	for _, bh := range merged {
+		seen[bh.Name()] = true
+	}
+	for _, bh := range secondaryBhs {
+		if !seen[bh.Name()] {
+			merged = append(merged, bh)
+			delete(fallbacks, backupHandlePath(bh))
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Name() < merged[j].Name()
+	})
+	return merged, fallbacks
+}
+
+// backupHandlePath returns the directory and name of a backup, which identifies it across
+// the directories of a shard.
+func backupHandlePath(bh backupstorage.BackupHandle) string {
+	return path.Join(bh.Directory(), bh.Name())
+}
+
+// GetSecondaryBackupStatuses returns the status of the copies in the secondary backup storage
+// of the given backups of a directory, by backup name. Only the MANIFESTs of these copies are
+// read. A backup that has no copy is not present in the map. It returns nil if there is no
+// secondary backup storage.
+func GetSecondaryBackupStatuses(ctx context.Context, dir string, names []string) (map[string]mysqlctlpb.BackupInfo_Status, error) {
+	secondary, err := backupstorage.GetSecondaryBackupStorage()
+	if err != nil || secondary == nil {
+		return nil, err
+	}
+	defer secondary.Close()
+	bhs, err := secondary.ListBackups(ctx, dir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed on secondary backup storage")
+	}
+	statuses := make(map[string]mysqlctlpb.BackupInfo_Status, len(names))
+	for _, bh := range bhs {
+		if !slices.Contains(names, bh.Name()) {
+			continue
+		}
+		if _, err := GetBackupManifest(ctx, bh); err != nil {
+			statuses[bh.Name()] = mysqlctlpb.BackupInfo_INCOMPLETE
+			continue
+		}
+		statuses[bh.Name()] = mysqlctlpb.BackupInfo_COMPLETE
+	}
+	return statuses, nil
+}
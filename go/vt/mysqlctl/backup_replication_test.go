@@ -0,0 +1,189 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+
+	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
+)
+
+func setupSecondaryBackupStorage(t *testing.T) (backupstorage.BackupStorage, backupstorage.BackupStorage) {
+	previousBackupStorageImplementation := backupstorage.BackupStorageImplementation
+	previousSecondaryBackupStorageImplementation := backupstorage.SecondaryBackupStorageImplementation
+	previousFileBackupStorageRoot := filebackupstorage.FileBackupStorageRoot
+	previousFileBackupStorageSecondaryRoot := filebackupstorage.FileBackupStorageSecondaryRoot
+	backupstorage.BackupStorageImplementation = "file"
+	backupstorage.SecondaryBackupStorageImplementation = "file"
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	filebackupstorage.FileBackupStorageSecondaryRoot = t.TempDir()
+	t.Cleanup(func() {
+		backupstorage.BackupStorageImplementation = previousBackupStorageImplementation
+		backupstorage.SecondaryBackupStorageImplementation = previousSecondaryBackupStorageImplementation
+		filebackupstorage.FileBackupStorageRoot = previousFileBackupStorageRoot
+		filebackupstorage.FileBackupStorageSecondaryRoot = previousFileBackupStorageSecondaryRoot
+	})
+
+	bs, err := backupstorage.GetBackupStorage()
+	require.NoError(t, err)
+	secondary, err := backupstorage.GetSecondaryBackupStorage()
+	require.NoError(t, err)
+	require.NotNil(t, secondary)
+	return bs, secondary
+}
+
+// writeTestBackup writes a builtin backup with the given files. The MANIFEST is omitted
+// when manifest is false, which is what an in progress backup looks like.
+func writeTestBackup(t *testing.T, bs backupstorage.BackupStorage, dir, name string, files []string, manifest bool) {
+	ctx := context.Background()
+	bh, err := bs.StartBackup(ctx, dir, name)
+	require.NoError(t, err)
+	bm := &builtinBackupManifest{BackupManifest: BackupManifest{BackupMethod: builtinBackupEngineName}}
+	for i, content := range files {
+		wc, err := bh.AddFile(ctx, fmt.Sprint(i), int64(len(content)))
+		require.NoError(t, err)
+		_, err = wc.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+		bm.FileEntries = append(bm.FileEntries, FileEntry{Base: backupData, Name: content})
+	}
+	if manifest {
+		data, err := json.Marshal(bm)
+		require.NoError(t, err)
+		wc, err := bh.AddFile(ctx, backupManifestFileName, int64(len(data)))
+		require.NoError(t, err)
+		_, err = wc.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+	}
+	require.NoError(t, bh.EndBackup(ctx))
+}
+
+func TestReplicateBackups(t *testing.T) {
+	ctx := context.Background()
+	bs, secondary := setupSecondaryBackupStorage(t)
+	dir := GetBackupDir("ks", "-80")
+
+	writeTestBackup(t, bs, dir, "2025-01-01.000000.zone1-0000000101", []string{"a", "b"}, true)
+	writeTestBackup(t, bs, dir, "2025-01-02.000000.zone1-0000000101", []string{"c"}, true)
+	writeTestBackup(t, bs, dir, "2025-01-03.000000.zone1-0000000101", []string{"d"}, false)
+	// A partial copy is replaced.
+	writeTestBackup(t, secondary, dir, "2025-01-02.000000.zone1-0000000101", []string{"c"}, false)
+	// The binlog archive is copied too.
+	archiveDir := GetBinlogArchiveDir("ks", "-80")
+	writeTestBackup(t, bs, archiveDir, "2025-01-02.120000.zone1-0000000101", []string{"binlog.000002"}, true)
+
+	names := []string{"2025-01-01.000000.zone1-0000000101", "2025-01-02.000000.zone1-0000000101"}
+	statuses, err := GetSecondaryBackupStatuses(ctx, dir, names)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]mysqlctlpb.BackupInfo_Status{
+		"2025-01-02.000000.zone1-0000000101": mysqlctlpb.BackupInfo_INCOMPLETE,
+	}, statuses)
+
+	require.NoError(t, ReplicateBackups(ctx, logutil.NewMemoryLogger(), "ks", "-80"))
+
+	statuses, err = GetSecondaryBackupStatuses(ctx, dir, names)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]mysqlctlpb.BackupInfo_Status{
+		"2025-01-01.000000.zone1-0000000101": mysqlctlpb.BackupInfo_COMPLETE,
+		"2025-01-02.000000.zone1-0000000101": mysqlctlpb.BackupInfo_COMPLETE,
+	}, statuses)
+
+	// Only the MANIFESTs of the requested backups are read.
+	statuses, err = GetSecondaryBackupStatuses(ctx, dir, names[1:])
+	require.NoError(t, err)
+	assert.Equal(t, map[string]mysqlctlpb.BackupInfo_Status{
+		"2025-01-02.000000.zone1-0000000101": mysqlctlpb.BackupInfo_COMPLETE,
+	}, statuses)
+
+	archiveStatuses, err := GetSecondaryBackupStatuses(ctx, archiveDir, []string{"2025-01-02.120000.zone1-0000000101"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]mysqlctlpb.BackupInfo_Status{
+		"2025-01-02.120000.zone1-0000000101": mysqlctlpb.BackupInfo_COMPLETE,
+	}, archiveStatuses)
+
+	secondaryBhs, err := secondary.ListBackups(ctx, dir)
+	require.NoError(t, err)
+	require.Len(t, secondaryBhs, 2)
+	content, err := readBackupFile(ctx, secondaryBhs[0], "1")
+	require.NoError(t, err)
+	assert.Equal(t, "b", string(content))
+
+	// Nothing left to copy.
+	copies := backupReplicationCopies.Get()
+	require.NoError(t, ReplicateBackups(ctx, logutil.NewMemoryLogger(), "ks", "-80"))
+	assert.Equal(t, copies, backupReplicationCopies.Get())
+}
+
+func TestMergeSecondaryBackups(t *testing.T) {
+	ctx := context.Background()
+	bs, secondary := setupSecondaryBackupStorage(t)
+	dir := GetBackupDir("ks", "-80")
+
+	writeTestBackup(t, bs, dir, "2025-01-01.000000.zone1-0000000101", []string{"a"}, true)
+	writeTestBackup(t, secondary, dir, "2025-01-01.000000.zone1-0000000101", []string{"a"}, true)
+	// The backup storage lost the MANIFEST of this one.
+	writeTestBackup(t, bs, dir, "2025-01-02.000000.zone1-0000000101", []string{"b"}, false)
+	writeTestBackup(t, secondary, dir, "2025-01-02.000000.zone1-0000000101", []string{"b"}, true)
+	// The backup storage lost this one altogether.
+	writeTestBackup(t, secondary, dir, "2025-01-03.000000.zone1-0000000101", []string{"c"}, true)
+
+	bhs, err := bs.ListBackups(ctx, dir)
+	require.NoError(t, err)
+	secondaryBhs, err := listSecondaryBackups(ctx, dir)
+	require.NoError(t, err)
+
+	merged, fallbacks := mergeSecondaryBackups(ctx, bhs, secondaryBhs)
+	require.Len(t, merged, 3)
+	assert.Equal(t, bhs[0], merged[0])
+	assert.Equal(t, secondaryBhs[1], merged[1])
+	assert.Equal(t, secondaryBhs[2], merged[2])
+	assert.Equal(t, map[string]backupstorage.BackupHandle{
+		"ks/-80/2025-01-01.000000.zone1-0000000101": secondaryBhs[0],
+	}, fallbacks)
+}
+
+func TestBackupFileNames(t *testing.T) {
+	fileNames, err := backupFileNames([]byte(`{"BackupMethod": "builtin", "FileEntries": [{"Name": "a"}, {"Name": "b"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0", "1"}, fileNames)
+
+	fileNames, err = backupFileNames([]byte(`{"BackupMethod": "xtrabackup", "FileName": "backup.xbstream.gz", "NumStripes": 2}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"backup.xbstream.gz-000", "backup.xbstream.gz-001"}, fileNames)
+
+	fileNames, err = backupFileNames([]byte(`{"BackupMethod": "xtrabackup", "FileName": "backup.xbstream.gz"}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"backup.xbstream.gz"}, fileNames)
+
+	fileNames, err = backupFileNames([]byte(`{"BackupMethod": "logical", "Tables": [{"Name": "t1", "Files": ["0.0", "0.1"]}, {"Name": "v1"}, {"Name": "t2", "Files": ["2.0"]}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0.0", "0.1", "2.0"}, fileNames)
+
+	_, err = backupFileNames([]byte(`{"BackupMethod": "mysqlshell"}`))
+	assert.ErrorContains(t, err, "cannot be copied")
+}
@@ -92,6 +92,14 @@ type BackupParams struct {
 	MysqlShutdownTimeout time.Duration
 	// BackupEngine allows us to override which backup engine should be used for a request
 	BackupEngine string
+	// Throttler, if set, is consulted by the backup engine while it reads the data it backs up,
+	// so that the backup backs off when the tablet is under pressure.
+	Throttler BackupThrottler
+	// DbName is the name of the managed database / schema
+	DbName string
+	// TableStreamer, if set, streams the rows of the database from a consistent snapshot.
+	// The logical backup engine needs it.
+	TableStreamer TableStreamer
 }
 
 func (b *BackupParams) Copy() BackupParams {
@@ -110,6 +118,9 @@ func (b *BackupParams) Copy() BackupParams {
 		Stats:                b.Stats,
 		UpgradeSafe:          b.UpgradeSafe,
 		MysqlShutdownTimeout: b.MysqlShutdownTimeout,
+		Throttler:            b.Throttler,
+		DbName:               b.DbName,
+		TableStreamer:        b.TableStreamer,
 	}
 }
 
@@ -716,6 +727,33 @@ func prepareToRestore(ctx context.Context, cnf *Mycnf, mysqld MysqlDaemon, logge
 	return nil
 }
 
+// disableSuperReadOnly disables super_read_only for a restore into a running mysqld. The
+// returned function sets it back to its original value.
+func disableSuperReadOnly(ctx context.Context, params RestoreParams) (func(), error) {
+	readonly, err := params.Mysqld.IsSuperReadOnly(ctx)
+	if err != nil {
+		return nil, vterrors.Wrap(err, fmt.Sprintf("checking if mysqld has super_read_only=enable: %v", err))
+	}
+
+	params.Logger.Infof("Is Super Read Only: %v", readonly)
+
+	if readonly {
+		resetFunc, err := params.Mysqld.SetSuperReadOnly(ctx, false)
+		if err != nil {
+			return nil, vterrors.Wrap(err, fmt.Sprintf("unable to disable super-read-only: %v", err))
+		}
+
+		return func() {
+			err := resetFunc()
+			if err != nil {
+				params.Logger.Errorf("Not able to set super_read_only to its original value after restore")
+			}
+		}, nil
+	}
+
+	return func() {}, nil
+}
+
 // create restore state file
 func createStateFile(cnf *Mycnf) error {
 	// if we start writing content to this file:
@@ -34,6 +34,12 @@ var (
 	// BackupStorageImplementation is the implementation to use
 	// for BackupStorage. Exported for test purposes.
 	BackupStorageImplementation string
+
+	// SecondaryBackupStorageImplementation is the implementation to use
+	// for the secondary BackupStorage, which holds copies of the backups.
+	// Exported for test purposes.
+	SecondaryBackupStorageImplementation string
+
 	// FileSizeUnknown is a special value indicating that the file size is not known.
 	// This is typically used while creating a file programmatically, where it is
 	// impossible to compute the final size on disk ahead of time.
@@ -42,6 +48,7 @@ var (
 
 func registerBackupFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&BackupStorageImplementation, "backup_storage_implementation", "", "Which backup storage implementation to use for creating and restoring backups.")
+	fs.StringVar(&SecondaryBackupStorageImplementation, "backup-storage-secondary-implementation", "", "Which backup storage implementation to copy backups to, after they are created. Restores fall back to this storage when a backup is missing or unusable in the main backup storage. The secondary storage is configured with its own flags, e.g. --file-backup-storage-secondary-root or --s3-backup-storage-secondary-bucket, so it may use the same implementation as --backup_storage_implementation.")
 }
 
 func init() {
@@ -142,3 +149,24 @@ func GetBackupStorage() (BackupStorage, error) {
 	}
 	return bs, nil
 }
+
+// SecondaryBackupStorageMap contains the registered implementations for the
+// secondary BackupStorage. They are separate instances from the ones in
+// BackupStorageMap, configured with their own location, so that backups can
+// be copied to another bucket or directory of the same implementation.
+var SecondaryBackupStorageMap = make(map[string]BackupStorage)
+
+// GetSecondaryBackupStorage returns the current secondary BackupStorage
+// implementation, or nil if there is none.
+// Should be called after flags have been initialized.
+// When all operations are done, call BackupStorage.Close() to free resources.
+func GetSecondaryBackupStorage() (BackupStorage, error) {
+	if SecondaryBackupStorageImplementation == "" {
+		return nil, nil
+	}
+	bs, ok := SecondaryBackupStorageMap[SecondaryBackupStorageImplementation]
+	if !ok {
+		return nil, fmt.Errorf("no registered implementation of secondary BackupStorage for %v", SecondaryBackupStorageImplementation)
+	}
+	return bs, nil
+}
@@ -0,0 +1,426 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// This file handles continuous binary log archiving. Closed binary logs are uploaded to the
+// BackupStorage as they rotate, one archive entry per binary log. Each archive entry is written
+// in the format of a builtin incremental backup, which means a point in time recovery can apply
+// archived binary logs on top of a full backup exactly as it would apply incremental backups.
+
+const (
+	// binlogArchiveDirSuffix is appended to the keyspace/shard backup directory to
+	// form the directory where archived binary logs are stored.
+	binlogArchiveDirSuffix = ".binlog-archive"
+)
+
+var (
+	// binlogArchiveInterval is how often the archiver looks for closed binary logs. Zero disables archiving.
+	binlogArchiveInterval time.Duration
+
+	// binlogArchiveFlush, when set, rotates the binary log on every archive interval if it
+	// contains transactions that are not yet archived.
+	binlogArchiveFlush bool
+
+	binlogArchiveFiles         = stats.NewCounter("BinlogArchiveFiles", "Number of binary log files uploaded to the binlog archive")
+	binlogArchiveErrors        = stats.NewCounter("BinlogArchiveErrors", "Number of failed binlog archive attempts")
+	binlogArchiveGaps          = stats.NewCounter("BinlogArchiveGaps", "Number of times the binlog archive could not pick up from its last archived position")
+	binlogArchiveLastTimestamp = stats.NewGauge("BinlogArchiveLastTimestamp", "Unix timestamp of the last transaction in the binlog archive")
+)
+
+func init() {
+	servenv.OnParseFor("vttablet", registerBinlogArchiveFlags)
+}
+
+func registerBinlogArchiveFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&binlogArchiveInterval, "binlog-archive-interval", binlogArchiveInterval, "how often the primary uploads closed binary logs to the backup storage, to be used by point in time recoveries. Zero disables binlog archiving.")
+	fs.BoolVar(&binlogArchiveFlush, "binlog-archive-flush", binlogArchiveFlush, "if set, the primary rotates the binary log every --binlog-archive-interval when it has unarchived transactions, so that the archive never lags by more than one interval.")
+}
+
+// GetBinlogArchiveDir returns the directory where archived binary logs for the
+// given keyspace/shard are (or will be) stored.
+func GetBinlogArchiveDir(keyspace, shard string) string {
+	return GetBackupDir(keyspace, shard) + binlogArchiveDirSuffix
+}
+
+// BinlogArchiver uploads closed binary logs to the BackupStorage while the tablet runs. It is
+// only opened on the primary, so that a single tablet of the shard writes to its archive.
+// Only one archiving pass runs at a time. The archiver keeps the position it has archived up
+// to in memory, and picks up from the most recent archive entry in the BackupStorage when opened.
+type BinlogArchiver struct {
+	cnf         *Mycnf
+	mysqld      MysqlDaemon
+	keyspace    string
+	shard       string
+	tabletAlias string
+	interval    time.Duration
+	flush       bool
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	// done is closed when the archiving goroutine last started terminates.
+	done chan struct{}
+	wg   sync.WaitGroup
+
+	// The following fields are only accessed by the archiving goroutine.
+	archivedPos   replication.Position
+	lastEntryTime time.Time
+}
+
+// NewBinlogArchiver creates a new BinlogArchiver for the given tablet. The archiver is
+// configured with the --binlog-archive-* flags, and is a no-op when --binlog-archive-interval is zero.
+func NewBinlogArchiver(cnf *Mycnf, mysqld MysqlDaemon, tablet *topodatapb.Tablet) *BinlogArchiver {
+	return &BinlogArchiver{
+		cnf:         cnf,
+		mysqld:      mysqld,
+		keyspace:    tablet.Keyspace,
+		shard:       tablet.Shard,
+		tabletAlias: topoproto.TabletAliasString(tablet.Alias),
+		interval:    binlogArchiveInterval,
+		flush:       binlogArchiveFlush,
+	}
+}
+
+// Open starts archiving in the background. It is a no-op if archiving is disabled or already
+// running. If the archiver was stopped and its last archive pass is still running, archiving
+// resumes once that pass terminates.
+func (a *BinlogArchiver) Open(ctx context.Context) {
+	if a.interval <= 0 {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cancel != nil {
+		return
+	}
+	prev := a.done
+	a.done = make(chan struct{})
+	ctx, a.cancel = context.WithCancel(ctx)
+	a.wg.Add(1)
+	go a.run(ctx, prev, a.done)
+}
+
+// Stop stops archiving without waiting for a running archive pass to terminate, so that it
+// can be called while holding locks that a slow upload must not block.
+func (a *BinlogArchiver) Stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cancel == nil {
+		return
+	}
+	a.cancel()
+	a.cancel = nil
+}
+
+// Close stops archiving and waits for any running archive pass to terminate.
+func (a *BinlogArchiver) Close() {
+	a.Stop()
+	a.wg.Wait()
+}
+
+func (a *BinlogArchiver) run(ctx context.Context, prev <-chan struct{}, done chan<- struct{}) {
+	defer a.wg.Done()
+	defer close(done)
+
+	if prev != nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-prev:
+		}
+	}
+	// The server may have been restored since we last ran. We reload the archived
+	// position from the BackupStorage upon the first pass.
+	a.archivedPos = replication.Position{}
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := a.archive(ctx); err != nil && ctx.Err() == nil {
+			binlogArchiveErrors.Add(1)
+			log.Errorf("binlog archive: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// archive runs a single archiving pass, uploading all closed binary logs which have
+// transactions not yet found in the archive. It returns the number of uploaded binary logs.
+func (a *BinlogArchiver) archive(ctx context.Context) (archived int, err error) {
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return 0, vterrors.Wrap(err, "unable to get backup storage")
+	}
+	defer bs.Close()
+
+	if a.archivedPos.IsZero() {
+		if a.archivedPos, err = a.findArchivedPosition(ctx, bs); err != nil {
+			return 0, err
+		}
+	}
+	if a.flush {
+		if err := a.flushIfNeeded(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	binaryLogs, err := a.mysqld.GetBinaryLogs(ctx)
+	if err != nil {
+		return 0, vterrors.Wrap(err, "cannot get binary logs")
+	}
+	gtidPurged, err := a.mysqld.GetGTIDPurged(ctx)
+	if err != nil {
+		return 0, vterrors.Wrap(err, "can't get @@gtid_purged")
+	}
+	purgedGTIDSet, ok := gtidPurged.GTIDSet.(replication.Mysql56GTIDSet)
+	if !ok {
+		return 0, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "failed to parse a valid MySQL GTID set from value: %v", gtidPurged)
+	}
+
+	previousGTIDs := map[string]string{}
+	getBinlogPreviousGTIDs := func(ctx context.Context, binlog string) (string, error) {
+		if gtids, ok := previousGTIDs[binlog]; ok {
+			return gtids, nil
+		}
+		gtids, err := a.mysqld.GetPreviousGTIDs(ctx, binlog)
+		if err != nil {
+			return "", err
+		}
+		previousGTIDs[binlog] = gtids
+		return gtids, nil
+	}
+
+	// With an empty archive, we archive all binary logs still available on the server.
+	var archiveFromGTIDSet replication.GTIDSet = purgedGTIDSet
+	if !a.archivedPos.IsZero() {
+		archiveFromGTIDSet = a.archivedPos.GTIDSet
+	}
+	binlogs, fromGTID, toGTID, err := ChooseBinlogsForIncrementalBackup(ctx, archiveFromGTIDSet, purgedGTIDSet, binaryLogs, getBinlogPreviousGTIDs)
+	if err != nil && vterrors.Code(err) == vtrpc.Code_FAILED_PRECONDITION && !a.archivedPos.IsZero() {
+		// The binary logs on this server cannot extend the archive: the binary logs following the
+		// archived position have been purged, or this server's history diverges from the archive.
+		// We start over from the binary logs we do have. The archive has a gap, and a point in time
+		// recovery into the gap will need a newer full backup.
+		binlogArchiveGaps.Add(1)
+		log.Warningf("binlog archive: cannot continue from archived position %v: %v. Archiving all available binary logs", a.archivedPos, err)
+		binlogs, fromGTID, toGTID, err = ChooseBinlogsForIncrementalBackup(ctx, purgedGTIDSet, purgedGTIDSet, binaryLogs, getBinlogPreviousGTIDs)
+	}
+	if err != nil {
+		return 0, vterrors.Wrap(err, "cannot choose binary logs to archive")
+	}
+	if len(binlogs) == 0 {
+		return 0, nil
+	}
+
+	serverUUID, err := a.mysqld.GetServerUUID(ctx)
+	if err != nil {
+		return 0, vterrors.Wrap(err, "can't get server uuid")
+	}
+	mysqlVersion, err := a.mysqld.GetVersionString(ctx)
+	if err != nil {
+		return 0, vterrors.Wrap(err, "can't get MySQL version")
+	}
+
+	// ChooseBinlogsForIncrementalBackup returns a contiguous range of closed binary logs. The Previous-GTIDs
+	// of each binary log are where the previous binary log ends, and toGTID is where the last one ends.
+	for i, binlog := range binlogs {
+		entryFromGTID := fromGTID
+		if i > 0 {
+			if entryFromGTID, err = getBinlogPreviousGTIDs(ctx, binlog); err != nil {
+				return archived, err
+			}
+		}
+		entryToGTID := toGTID
+		if i < len(binlogs)-1 {
+			if entryToGTID, err = getBinlogPreviousGTIDs(ctx, binlogs[i+1]); err != nil {
+				return archived, err
+			}
+		}
+		fromPos, err := replication.ParsePosition(replication.Mysql56FlavorID, entryFromGTID)
+		if err != nil {
+			return archived, vterrors.Wrapf(err, "cannot parse position %v", entryFromGTID)
+		}
+		toPos, err := replication.ParsePosition(replication.Mysql56FlavorID, entryToGTID)
+		if err != nil {
+			return archived, vterrors.Wrapf(err, "cannot parse position %v", entryToGTID)
+		}
+		if !fromPos.GTIDSet.Contains(toPos.GTIDSet) {
+			// Same as with incremental backups, the archived position includes the purged GTIDs.
+			toPos.GTIDSet = toPos.GTIDSet.Union(gtidPurged.GTIDSet)
+			if err := a.archiveBinlog(ctx, bs, binlog, fromPos, toPos, gtidPurged, serverUUID, mysqlVersion); err != nil {
+				return archived, vterrors.Wrapf(err, "cannot archive binary log %v", binlog)
+			}
+			archived++
+			binlogArchiveFiles.Add(1)
+		}
+		// A binary log without transactions is skipped, but the archive still moves past it.
+		a.archivedPos = toPos
+	}
+	return archived, nil
+}
+
+// archiveBinlog uploads a single binary log as an archive entry.
+func (a *BinlogArchiver) archiveBinlog(
+	ctx context.Context,
+	bs backupstorage.BackupStorage,
+	binlog string,
+	fromPos replication.Position,
+	toPos replication.Position,
+	purgedPos replication.Position,
+	serverUUID string,
+	mysqlVersion string,
+) error {
+	// Archive entries are named like backups. Names must be unique, and binary logs
+	// can rotate more than once per second.
+	entryTime := time.Now().UTC().Truncate(time.Second)
+	if !entryTime.After(a.lastEntryTime) {
+		entryTime = a.lastEntryTime.Add(time.Second)
+	}
+	name := fmt.Sprintf("%v.%v", entryTime.Format(BackupTimestampFormat), a.tabletAlias)
+	params := BackupParams{
+		Cnf:                a.cnf,
+		Mysqld:             a.mysqld,
+		Logger:             logutil.NewConsoleLogger(),
+		Concurrency:        1,
+		Keyspace:           a.keyspace,
+		Shard:              a.shard,
+		TabletAlias:        a.tabletAlias,
+		BackupTime:         entryTime,
+		IncrementalFromPos: replication.EncodePosition(fromPos),
+		Stats:              backupstats.NoStats(),
+	}
+
+	fe := FileEntry{Base: backupBinlogDir, Name: binlog}
+	fullPath, err := fe.fullPath(a.cnf)
+	if err != nil {
+		return err
+	}
+	req := &mysqlctlpb.ReadBinlogFilesTimestampsRequest{BinlogFileNames: []string{fullPath}}
+	resp, err := a.mysqld.ReadBinlogFilesTimestamps(ctx, req)
+	if err != nil {
+		return vterrors.Wrapf(err, "reading timestamps from binlog file %v", binlog)
+	}
+	if resp.FirstTimestampBinlog == "" || resp.LastTimestampBinlog == "" {
+		return vterrors.Errorf(vtrpc.Code_ABORTED, "empty binlog name in response. Request=%v, Response=%v", req, resp)
+	}
+	lastTimestamp := protoutil.TimeFromProto(resp.LastTimestamp).UTC()
+	incrDetails := &IncrementalBackupDetails{
+		FirstTimestamp:       FormatRFC3339(protoutil.TimeFromProto(resp.FirstTimestamp).UTC()),
+		FirstTimestampBinlog: filepath.Base(resp.FirstTimestampBinlog),
+		LastTimestamp:        FormatRFC3339(lastTimestamp),
+		LastTimestampBinlog:  filepath.Base(resp.LastTimestampBinlog),
+	}
+
+	bh, err := bs.StartBackup(ctx, GetBinlogArchiveDir(a.keyspace, a.shard), name)
+	if err != nil {
+		return vterrors.Wrap(err, "StartBackup failed")
+	}
+	be := &BuiltinBackupEngine{}
+	if err := be.backupFiles(ctx, params, bh, toPos, purgedPos, fromPos, "", []string{binlog}, serverUUID, mysqlVersion, incrDetails); err != nil {
+		if abortErr := bh.AbortBackup(ctx); abortErr != nil {
+			log.Errorf("binlog archive: failed to abort archive entry %v: %v", name, abortErr)
+		}
+		return err
+	}
+	if err := bh.EndBackup(ctx); err != nil {
+		return err
+	}
+	a.lastEntryTime = entryTime
+	binlogArchiveLastTimestamp.Set(lastTimestamp.Unix())
+	log.Infof("binlog archive: archived %v as %v, position %v", binlog, name, toPos)
+	return nil
+}
+
+// findArchivedPosition returns the position of the most recent archive entry, or
+// an empty position if the archive is empty.
+func (a *BinlogArchiver) findArchivedPosition(ctx context.Context, bs backupstorage.BackupStorage) (replication.Position, error) {
+	bhs, err := bs.ListBackups(ctx, GetBinlogArchiveDir(a.keyspace, a.shard))
+	if err != nil {
+		return replication.Position{}, vterrors.Wrap(err, "ListBackups failed")
+	}
+	_, manifest, err := findLatestSuccessfulBackup(ctx, logutil.NewConsoleLogger(), bhs, "")
+	if err == ErrNoCompleteBackup {
+		return replication.Position{}, nil
+	}
+	if err != nil {
+		return replication.Position{}, err
+	}
+	return manifest.Position, nil
+}
+
+// flushIfNeeded rotates the binary log if the current binary log has any transactions.
+func (a *BinlogArchiver) flushIfNeeded(ctx context.Context) error {
+	binaryLogs, err := a.mysqld.GetBinaryLogs(ctx)
+	if err != nil {
+		return vterrors.Wrap(err, "cannot get binary logs")
+	}
+	if len(binaryLogs) == 0 {
+		return nil
+	}
+	previousGTIDs, err := a.mysqld.GetPreviousGTIDs(ctx, binaryLogs[len(binaryLogs)-1])
+	if err != nil {
+		return err
+	}
+	previousGTIDsPos, err := replication.ParsePosition(replication.Mysql56FlavorID, previousGTIDs)
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot parse position %v", previousGTIDs)
+	}
+	gtidPurged, err := a.mysqld.GetGTIDPurged(ctx)
+	if err != nil {
+		return vterrors.Wrap(err, "can't get @@gtid_purged")
+	}
+	executedPos, err := a.mysqld.PrimaryPosition(ctx)
+	if err != nil {
+		return vterrors.Wrap(err, "can't get @@gtid_executed")
+	}
+	if executedPos.GTIDSet == nil || previousGTIDsPos.GTIDSet.Union(gtidPurged.GTIDSet).Contains(executedPos.GTIDSet) {
+		// No transactions in the current binary log.
+		return nil
+	}
+	if err := a.mysqld.FlushBinaryLogs(ctx); err != nil {
+		return vterrors.Wrap(err, "cannot flush binary logs")
+	}
+	return nil
+}
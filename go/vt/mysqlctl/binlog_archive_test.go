@@ -0,0 +1,257 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"os"
+	"path"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+
+	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestBinlogArchiver(t *testing.T) {
+	ctx := context.Background()
+	const uuid = "16b1039f-22b6-11ed-b765-0a43f95f28a3"
+
+	previousBackupStorageImplementation := backupstorage.BackupStorageImplementation
+	previousFileBackupStorageRoot := filebackupstorage.FileBackupStorageRoot
+	backupstorage.BackupStorageImplementation = "file"
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	defer func() {
+		backupstorage.BackupStorageImplementation = previousBackupStorageImplementation
+		filebackupstorage.FileBackupStorageRoot = previousFileBackupStorageRoot
+	}()
+
+	binlogDir := t.TempDir()
+	cnf := &Mycnf{BinLogPath: path.Join(binlogDir, "vt-bin")}
+	addBinlog := func(mysqld *FakeMysqlDaemon, name string, previousGTIDs string) {
+		require.NoError(t, os.WriteFile(path.Join(binlogDir, name), []byte(name), 0o644))
+		mysqld.BinaryLogs = append(mysqld.BinaryLogs, name)
+		mysqld.BinlogPreviousGTIDs[name] = previousGTIDs
+	}
+
+	mysqld := NewFakeMysqlDaemon(nil)
+	mysqld.BinaryLogs = []string{}
+	mysqld.BinlogPreviousGTIDs = map[string]string{}
+	gtidPurged, err := replication.ParsePosition(replication.Mysql56FlavorID, "")
+	require.NoError(t, err)
+	mysqld.GTIDPurged = gtidPurged
+	binlogTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mysqld.ReadBinlogFilesTimestampsFunc = func(req *mysqlctlpb.ReadBinlogFilesTimestampsRequest) (*mysqlctlpb.ReadBinlogFilesTimestampsResponse, error) {
+		binlogTime = binlogTime.Add(time.Minute)
+		return &mysqlctlpb.ReadBinlogFilesTimestampsResponse{
+			FirstTimestamp:       protoutil.TimeToProto(binlogTime),
+			FirstTimestampBinlog: req.BinlogFileNames[0],
+			LastTimestamp:        protoutil.TimeToProto(binlogTime.Add(30 * time.Second)),
+			LastTimestampBinlog:  req.BinlogFileNames[0],
+		}, nil
+	}
+	addBinlog(mysqld, "vt-bin.000001", "")
+	addBinlog(mysqld, "vt-bin.000002", uuid+":1-10")
+	addBinlog(mysqld, "vt-bin.000003", uuid+":1-20")
+
+	tablet := &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+		Keyspace: "ks",
+		Shard:    "-80",
+	}
+	archiver := NewBinlogArchiver(cnf, mysqld, tablet)
+
+	// The last binary log is still open, so all but the last binary log are archived.
+	archived, err := archiver.archive(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, archived)
+	assert.Equal(t, uuid+":1-20", archiver.archivedPos.GTIDSet.String())
+
+	// Nothing new to archive.
+	archived, err = archiver.archive(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, archived)
+
+	addBinlog(mysqld, "vt-bin.000004", uuid+":1-25")
+	archived, err = archiver.archive(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, archived)
+
+	// A new archiver picks up from the archive.
+	archiver = NewBinlogArchiver(cnf, mysqld, tablet)
+	archived, err = archiver.archive(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, archived)
+	assert.Equal(t, uuid+":1-25", archiver.archivedPos.GTIDSet.String())
+
+	bs, err := backupstorage.GetBackupStorage()
+	require.NoError(t, err)
+	defer bs.Close()
+	bhs, err := bs.ListBackups(ctx, GetBinlogArchiveDir("ks", "-80"))
+	require.NoError(t, err)
+	require.Len(t, bhs, 3)
+
+	manifests := []*BackupManifest{}
+	for _, bh := range bhs {
+		_, alias, err := ParseBackupName(bh.Directory(), bh.Name())
+		require.NoError(t, err)
+		assert.Equal(t, tablet.Alias, alias)
+
+		manifest, err := GetBackupManifest(ctx, bh)
+		require.NoError(t, err)
+		assert.True(t, manifest.Incremental)
+		assert.Equal(t, builtinBackupEngineName, manifest.BackupMethod)
+		require.NotNil(t, manifest.IncrementalDetails)
+		manifests = append(manifests, manifest)
+	}
+
+	// A point in time recovery applies the archived binary logs on top of a full backup.
+	fullBackupPos, err := replication.ParsePosition(replication.Mysql56FlavorID, uuid+":1-5")
+	require.NoError(t, err)
+	manifests = append(manifests, &BackupManifest{
+		BackupMethod:   builtinBackupEngineName,
+		Position:       fullBackupPos,
+		PurgedPosition: gtidPurged,
+		BackupTime:     FormatRFC3339(binlogTime),
+		FinishedTime:   FormatRFC3339(binlogTime),
+	})
+	restoreToPos, err := replication.ParsePosition(replication.Mysql56FlavorID, uuid+":1-22")
+	require.NoError(t, err)
+	restorePath, err := FindPITRPath(restoreToPos.GTIDSet, manifests)
+	require.NoError(t, err)
+	require.Len(t, restorePath, 4)
+	assert.False(t, restorePath[0].Incremental)
+	assert.Equal(t, uuid+":1-25", restorePath[3].Position.GTIDSet.String())
+}
+
+func TestBinlogArchiverStop(t *testing.T) {
+	ctx := context.Background()
+	const uuid = "16b1039f-22b6-11ed-b765-0a43f95f28a3"
+
+	previousBackupStorageImplementation := backupstorage.BackupStorageImplementation
+	previousFileBackupStorageRoot := filebackupstorage.FileBackupStorageRoot
+	backupstorage.BackupStorageImplementation = "file"
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	// The archive pass is canceled while it uploads, and the compressor doesn't wait for its
+	// pending writes when its context is canceled.
+	previousBackupStorageCompress := backupStorageCompress
+	backupStorageCompress = false
+	defer func() {
+		backupstorage.BackupStorageImplementation = previousBackupStorageImplementation
+		filebackupstorage.FileBackupStorageRoot = previousFileBackupStorageRoot
+		backupStorageCompress = previousBackupStorageCompress
+	}()
+
+	binlogDir := t.TempDir()
+	cnf := &Mycnf{BinLogPath: path.Join(binlogDir, "vt-bin")}
+	mysqld := NewFakeMysqlDaemon(nil)
+	mysqld.BinaryLogs = []string{}
+	mysqld.BinlogPreviousGTIDs = map[string]string{}
+	addBinlog := func(name string, previousGTIDs string) {
+		require.NoError(t, os.WriteFile(path.Join(binlogDir, name), []byte(name), 0o644))
+		mysqld.BinaryLogs = append(mysqld.BinaryLogs, name)
+		mysqld.BinlogPreviousGTIDs[name] = previousGTIDs
+	}
+	addBinlog("vt-bin.000001", "")
+	addBinlog("vt-bin.000002", uuid+":1-10")
+	gtidPurged, err := replication.ParsePosition(replication.Mysql56FlavorID, "")
+	require.NoError(t, err)
+	mysqld.GTIDPurged = gtidPurged
+
+	// Archive passes block until released.
+	var running, maxRunning atomic.Int32
+	entered := make(chan struct{}, 10)
+	release := make(chan struct{})
+	mysqld.ReadBinlogFilesTimestampsFunc = func(req *mysqlctlpb.ReadBinlogFilesTimestampsRequest) (*mysqlctlpb.ReadBinlogFilesTimestampsResponse, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		if n > maxRunning.Load() {
+			maxRunning.Store(n)
+		}
+		entered <- struct{}{}
+		<-release
+		binlogTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		return &mysqlctlpb.ReadBinlogFilesTimestampsResponse{
+			FirstTimestamp:       protoutil.TimeToProto(binlogTime),
+			FirstTimestampBinlog: req.BinlogFileNames[0],
+			LastTimestamp:        protoutil.TimeToProto(binlogTime.Add(30 * time.Second)),
+			LastTimestampBinlog:  req.BinlogFileNames[0],
+		}, nil
+	}
+
+	archiver := NewBinlogArchiver(cnf, mysqld, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+		Keyspace: "ks",
+		Shard:    "-80",
+	})
+	archiver.interval = time.Hour
+	archiver.Open(ctx)
+	<-entered
+
+	// Stop doesn't wait for the running archive pass.
+	archiver.Stop()
+
+	// Archiving resumes once the stopped archive pass terminates, so that two passes
+	// never run at the same time.
+	archiver.Open(ctx)
+	addBinlog("vt-bin.000003", uuid+":1-20")
+	close(release)
+	select {
+	case <-entered:
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "archiving did not resume")
+	}
+	archiver.Close()
+	assert.EqualValues(t, 1, maxRunning.Load())
+}
+
+func TestBinlogArchiverFlush(t *testing.T) {
+	ctx := context.Background()
+	const uuid = "16b1039f-22b6-11ed-b765-0a43f95f28a3"
+
+	mysqld := NewFakeMysqlDaemon(nil)
+	mysqld.BinaryLogs = []string{"vt-bin.000001", "vt-bin.000002"}
+	mysqld.BinlogPreviousGTIDs = map[string]string{
+		"vt-bin.000001": "",
+		"vt-bin.000002": uuid + ":1-10",
+	}
+	gtidPurged, err := replication.ParsePosition(replication.Mysql56FlavorID, "")
+	require.NoError(t, err)
+	mysqld.GTIDPurged = gtidPurged
+	mysqld.CurrentPrimaryPosition, err = replication.ParsePosition(replication.Mysql56FlavorID, uuid+":1-10")
+	require.NoError(t, err)
+
+	archiver := &BinlogArchiver{mysqld: mysqld}
+
+	// The current binary log has no transactions.
+	require.NoError(t, archiver.flushIfNeeded(ctx))
+
+	mysqld.CurrentPrimaryPosition, err = replication.ParsePosition(replication.Mysql56FlavorID, uuid+":1-12")
+	require.NoError(t, err)
+	mysqld.ExpectedExecuteSuperQueryList = []string{"FAKE FLUSH BINARY LOGS"}
+	require.NoError(t, archiver.flushIfNeeded(ctx))
+	assert.Equal(t, 1, mysqld.ExpectedExecuteSuperQueryCurrent)
+}
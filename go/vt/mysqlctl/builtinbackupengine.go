@@ -88,6 +88,11 @@ var (
 	// The path should exist.
 	// When empty, the default OS temp dir is assumed.
 	builtinIncrementalRestorePath = ""
+
+	// If set, full backups drain the tablet, which stops serving until the backup completes.
+	// Otherwise the tablet keeps its type while mysqld is shut down, and vtgate routes
+	// around it because it reports itself unhealthy.
+	builtinBackupShouldDrain = false
 )
 
 // BuiltinBackupEngine encapsulates the logic of the builtin engine
@@ -172,6 +177,7 @@ func registerBuiltinBackupEngineFlags(fs *pflag.FlagSet) {
 	fs.UintVar(&builtinBackupFileReadBufferSize, "builtinbackup-file-read-buffer-size", builtinBackupFileReadBufferSize, "read files using an IO buffer of this many bytes. Golang defaults are used when set to 0.")
 	fs.UintVar(&builtinBackupFileWriteBufferSize, "builtinbackup-file-write-buffer-size", builtinBackupFileWriteBufferSize, "write files using an IO buffer of this many bytes. Golang defaults are used when set to 0.")
 	fs.StringVar(&builtinIncrementalRestorePath, "builtinbackup-incremental-restore-path", builtinIncrementalRestorePath, "the directory where incremental restore files, namely binlog files, are extracted to. In k8s environments, this should be set to a directory that is shared between the vttablet and mysqld pods. The path should exist. When empty, the default OS temp dir is assumed.")
+	fs.BoolVar(&builtinBackupShouldDrain, "builtinbackup-should-drain", builtinBackupShouldDrain, "if set, full backups change the tablet type to BACKUP so that it stops serving. Otherwise the tablet keeps its type while mysqld is shut down for the backup.")
 }
 
 // fullPath returns the full path of the entry, based on its type
@@ -613,8 +619,10 @@ func (be *BuiltinBackupEngine) backupFiles(
 	}
 	params.Logger.Infof("found %v files to backup", len(fes))
 
+	limiter := newIOLimiter(params.Throttler, params.Stats)
+
 	// The error here can be ignored safely. Failed FileEntry's are handled in the next 'if' statement.
-	_ = be.backupFileEntries(ctx, fes, bh, params)
+	_ = be.backupFileEntries(ctx, fes, bh, params, limiter)
 
 	// BackupHandle supports the BackupErrorRecorder interface for tracking errors
 	// across any goroutines that fan out to take the backup. This means that we
@@ -645,7 +653,7 @@ func (be *BuiltinBackupEngine) backupFiles(
 			}
 			bh.ResetErrorForFile(file)
 		}
-		err = be.backupFileEntries(ctx, newFEs, bh, params)
+		err = be.backupFileEntries(ctx, newFEs, bh, params, limiter)
 		if err != nil {
 			return err
 		}
@@ -670,7 +678,7 @@ func (be *BuiltinBackupEngine) backupFiles(
 // This function will ignore empty FileEntry, allowing the retry mechanism to send a partially empty slice, to not
 // mess up the index of retriable FileEntry.
 // This function does not leave any background operation behind itself, all calls to bh.AddFile will be finished or canceled.
-func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, params BackupParams) error {
+func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, params BackupParams, limiter *ioLimiter) error {
 	ctxCancel, cancel := context.WithCancel(ctx)
 	defer func() {
 		// If we reached this defer in all cases we can cancel the context.
@@ -704,7 +712,7 @@ func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []File
 
 			// Backup the individual file.
 			var errBackupFile error
-			if errBackupFile = be.backupFile(ctxCancel, params, bh, fe, name); errBackupFile != nil {
+			if errBackupFile = be.backupFile(ctxCancel, params, bh, fe, name, limiter); errBackupFile != nil {
 				bh.RecordError(name, vterrors.Wrapf(errBackupFile, "failed to backup file '%s'", name))
 				if fe.RetryCount >= maxRetriesPerFile {
 					// this is the last attempt, and we have an error, we can cancel everything and fail fast.
@@ -832,8 +840,8 @@ func (bp *backupPipe) ReportProgress(ctx context.Context, period time.Duration,
 	}
 }
 
-// backupFile backs up an individual file.
-func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string) (finalErr error) {
+// backupFile backs up an individual file. Reads from the source file are subject to the given limiter, if any.
+func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string, limiter *ioLimiter) (finalErr error) {
 	// We need another context that does not live outside of this function.
 	// Reporting progress, compressing and writing are operations that will be
 	// over by the time we exit this function, they can use this cancelable context.
@@ -866,7 +874,7 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 	}
 
 	retryStr := retryToString(fe.RetryCount)
-	br := newBackupReader(fe.Name, fi.Size(), timedSource)
+	br := newBackupReader(fe.Name, fi.Size(), limiter.reader(cancelableCtx, timedSource))
 	go br.ReportProgress(cancelableCtx, builtinBackupProgress, params.Logger, false /*restore*/, retryStr)
 
 	// Open the destination file for writing, and a buffer.
@@ -1171,7 +1179,8 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 		}
 	}
 	fes := bm.FileEntries
-	_ = be.restoreFileEntries(ctx, fes, bh, bm, params, createdDir)
+	limiter := newIOLimiter(nil, params.Stats)
+	_ = be.restoreFileEntries(ctx, fes, bh, bm, params, createdDir, limiter)
 	if files := bh.GetFailedFiles(); len(files) > 0 {
 		newFEs := make([]FileEntry, len(fes))
 		for _, file := range files {
@@ -1189,7 +1198,7 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 			}
 			bh.ResetErrorForFile(file)
 		}
-		err = be.restoreFileEntries(ctx, newFEs, bh, bm, params, createdDir)
+		err = be.restoreFileEntries(ctx, newFEs, bh, bm, params, createdDir, limiter)
 		if err != nil {
 			return "", err
 		}
@@ -1197,7 +1206,7 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 	return createdDir, nil
 }
 
-func (be *BuiltinBackupEngine) restoreFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, bm builtinBackupManifest, params RestoreParams, createdDir string) error {
+func (be *BuiltinBackupEngine) restoreFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, bm builtinBackupManifest, params RestoreParams, createdDir string, limiter *ioLimiter) error {
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(params.Concurrency)
 
@@ -1224,7 +1233,7 @@ func (be *BuiltinBackupEngine) restoreFileEntries(ctx context.Context, fes []Fil
 
 			// And restore the file.
 			params.Logger.Infof("Copying file %v: %v %s", name, fe.Name, retryToString(fe.RetryCount))
-			if errRestore := be.restoreFile(ctx, params, bh, fe, bm, name); errRestore != nil {
+			if errRestore := be.restoreFile(ctx, params, bh, fe, bm, name, limiter); errRestore != nil {
 				bh.RecordError(name, vterrors.Wrapf(errRestore, "failed to restore file %v to %v", name, fe.Name))
 				if fe.RetryCount >= maxRetriesPerFile {
 					// this is the last attempt, and we have an error, we can return an error, which will let errgroup
@@ -1239,8 +1248,8 @@ func (be *BuiltinBackupEngine) restoreFileEntries(ctx context.Context, fes []Fil
 	return bh.Error()
 }
 
-// restoreFile restores an individual file.
-func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, fe *FileEntry, bm builtinBackupManifest, name string) (finalErr error) {
+// restoreFile restores an individual file. Writes to the destination file are subject to the given limiter, if any.
+func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, fe *FileEntry, bm builtinBackupManifest, name string, limiter *ioLimiter) (finalErr error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -1291,7 +1300,7 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 	writeStats := params.Stats.Scope(stats.Operation("Destination:Write"))
 	timedDest := ioutil.NewMeteredWriter(dest, writeStats.TimedIncrementBytes)
 
-	bufferedDest := bufio.NewWriterSize(timedDest, int(builtinBackupFileWriteBufferSize))
+	bufferedDest := bufio.NewWriterSize(limiter.writer(ctx, timedDest), int(builtinBackupFileWriteBufferSize))
 
 	// Create the uncompresser if needed.
 	if !bm.SkipCompress {
@@ -1358,14 +1367,14 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 	return nil
 }
 
-// ShouldDrainForBackup satisfies the BackupEngine interface
-// backup requires query service to be stopped, hence true
+// ShouldDrainForBackup satisfies the BackupEngine interface.
+// Full backups only drain the tablet if --builtinbackup-should-drain is set.
 func (be *BuiltinBackupEngine) ShouldDrainForBackup(req *tabletmanagerdatapb.BackupRequest) bool {
 	if req != nil && req.IncrementalFromPos != "" {
 		// Incremental backup: we do not drain the tablet.
 		return false
 	}
-	return true
+	return builtinBackupShouldDrain
 }
 
 // ShouldStartMySQLAfterRestore signifies if this backup engine needs to restart MySQL once the restore is completed.
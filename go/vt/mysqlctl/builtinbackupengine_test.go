@@ -71,8 +71,16 @@ func TestGetIncrementalFromPosGTIDSet(t *testing.T) {
 }
 
 func TestShouldDrainForBackupBuiltIn(t *testing.T) {
+	original := builtinBackupShouldDrain
+	defer func() { builtinBackupShouldDrain = original }()
+
 	be := &BuiltinBackupEngine{}
 
+	builtinBackupShouldDrain = false
+	assert.False(t, be.ShouldDrainForBackup(&tabletmanagerdatapb.BackupRequest{}))
+	assert.False(t, be.ShouldDrainForBackup(&tabletmanagerdatapb.BackupRequest{IncrementalFromPos: "auto"}))
+
+	builtinBackupShouldDrain = true
 	assert.True(t, be.ShouldDrainForBackup(&tabletmanagerdatapb.BackupRequest{}))
 	assert.False(t, be.ShouldDrainForBackup(&tabletmanagerdatapb.BackupRequest{IncrementalFromPos: "auto"}))
 	assert.False(t, be.ShouldDrainForBackup(&tabletmanagerdatapb.BackupRequest{IncrementalFromPos: "99ca8ed4-399c-11ee-861b-0a43f95f28a3:1-197"}))
@@ -198,6 +198,19 @@ type FakeMysqlDaemon struct {
 
 	// Version is the version that will be returned by GetVersionString.
 	Version string
+
+	// BinaryLogs, if non-nil, is returned by GetBinaryLogs.
+	BinaryLogs []string
+
+	// BinlogPreviousGTIDs, if non-nil, maps binary log names to the
+	// Previous-GTIDs returned by GetPreviousGTIDs.
+	BinlogPreviousGTIDs map[string]string
+
+	// GTIDPurged is returned by GetGTIDPurged.
+	GTIDPurged replication.Position
+
+	// ReadBinlogFilesTimestampsFunc provides the return value for ReadBinlogFilesTimestamps.
+	ReadBinlogFilesTimestampsFunc func(req *mysqlctlpb.ReadBinlogFilesTimestampsRequest) (*mysqlctlpb.ReadBinlogFilesTimestampsResponse, error)
 }
 
 // NewFakeMysqlDaemon returns a FakeMysqlDaemon where mysqld appears
@@ -270,6 +283,9 @@ func (fmd *FakeMysqlDaemon) ApplyBinlogFile(ctx context.Context, req *mysqlctlpb
 
 // ReadBinlogFilesTimestamps is part of the MysqlDaemon interface.
 func (fmd *FakeMysqlDaemon) ReadBinlogFilesTimestamps(ctx context.Context, req *mysqlctlpb.ReadBinlogFilesTimestampsRequest) (*mysqlctlpb.ReadBinlogFilesTimestampsResponse, error) {
+	if fmd.ReadBinlogFilesTimestampsFunc != nil {
+		return fmd.ReadBinlogFilesTimestampsFunc(req)
+	}
 	return nil, nil
 }
 
@@ -356,7 +372,7 @@ func (fmd *FakeMysqlDaemon) ReplicationConfiguration(ctx context.Context) (*repl
 
 // GetGTIDPurged is part of the MysqlDaemon interface.
 func (fmd *FakeMysqlDaemon) GetGTIDPurged(ctx context.Context) (replication.Position, error) {
-	return replication.Position{}, nil
+	return fmd.GTIDPurged, nil
 }
 
 // ResetReplication is part of the MysqlDaemon interface.
@@ -396,6 +412,9 @@ func (fmd *FakeMysqlDaemon) FlushBinaryLogs(ctx context.Context) (err error) {
 
 // GetBinaryLogs is part of the MysqlDaemon interface.
 func (fmd *FakeMysqlDaemon) GetBinaryLogs(ctx context.Context) (binaryLogs []string, err error) {
+	if fmd.BinaryLogs != nil {
+		return fmd.BinaryLogs, nil
+	}
 	return []string{}, fmd.ExecuteSuperQueryList(ctx, []string{
 		"FAKE SHOW BINARY LOGS",
 	})
@@ -403,6 +422,9 @@ func (fmd *FakeMysqlDaemon) GetBinaryLogs(ctx context.Context) (binaryLogs []str
 
 // GetPreviousGTIDs is part of the MysqlDaemon interface.
 func (fmd *FakeMysqlDaemon) GetPreviousGTIDs(ctx context.Context, binlog string) (previousGtids string, err error) {
+	if fmd.BinlogPreviousGTIDs != nil {
+		return fmd.BinlogPreviousGTIDs[binlog], nil
+	}
 	return "", fmd.ExecuteSuperQueryList(ctx, []string{
 		fmt.Sprintf("FAKE SHOW BINLOG EVENTS IN '%s' LIMIT 2", binlog),
 	})
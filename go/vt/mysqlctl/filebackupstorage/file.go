@@ -41,11 +41,18 @@ var (
 	// Exported for test purposes.
 	FileBackupStorageRoot string
 
-	defaultFileBackupStorage = newFileBackupStorage(backupstorage.NoParams())
+	// FileBackupStorageSecondaryRoot is where the copies of the backups
+	// go when "file" is the secondary backup storage implementation.
+	// Exported for test purposes.
+	FileBackupStorageSecondaryRoot string
+
+	defaultFileBackupStorage          = newFileBackupStorage(backupstorage.NoParams(), &FileBackupStorageRoot)
+	defaultSecondaryFileBackupStorage = newFileBackupStorage(backupstorage.NoParams(), &FileBackupStorageSecondaryRoot)
 )
 
 func registerFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&FileBackupStorageRoot, "file_backup_storage_root", "", "Root directory for the file backup storage.")
+	fs.StringVar(&FileBackupStorageSecondaryRoot, "file-backup-storage-secondary-root", "", "Root directory for the file backup storage, when used as --backup-storage-secondary-implementation.")
 }
 
 func init() {
@@ -96,7 +103,7 @@ func (fbh *FileBackupHandle) AddFile(ctx context.Context, filename string, files
 	if fbh.readOnly {
 		return nil, fmt.Errorf("AddFile cannot be called on read-only backup")
 	}
-	p := path.Join(FileBackupStorageRoot, fbh.dir, fbh.name, filename)
+	p := path.Join(*fbh.fbs.root, fbh.dir, fbh.name, filename)
 	f, err := os2.Create(p)
 	if err != nil {
 		return nil, err
@@ -126,7 +133,7 @@ func (fbh *FileBackupHandle) ReadFile(ctx context.Context, filename string) (io.
 	if !fbh.readOnly {
 		return nil, fmt.Errorf("ReadFile cannot be called on read-write backup")
 	}
-	p := path.Join(FileBackupStorageRoot, fbh.dir, fbh.name, filename)
+	p := path.Join(*fbh.fbs.root, fbh.dir, fbh.name, filename)
 	f, err := os.Open(p)
 	if err != nil {
 		return nil, err
@@ -138,16 +145,18 @@ func (fbh *FileBackupHandle) ReadFile(ctx context.Context, filename string) (io.
 // FileBackupStorage implements BackupStorage for local file system.
 type FileBackupStorage struct {
 	params backupstorage.Params
+	// root points to the flag holding the root directory of this storage.
+	root *string
 }
 
-func newFileBackupStorage(params backupstorage.Params) *FileBackupStorage {
-	return &FileBackupStorage{params}
+func newFileBackupStorage(params backupstorage.Params, root *string) *FileBackupStorage {
+	return &FileBackupStorage{params: params, root: root}
 }
 
 // ListBackups is part of the BackupStorage interface
 func (fbs *FileBackupStorage) ListBackups(ctx context.Context, dir string) ([]backupstorage.BackupHandle, error) {
 	// ReadDir already sorts the results
-	p := path.Join(FileBackupStorageRoot, dir)
+	p := path.Join(*fbs.root, dir)
 	fi, err := os.ReadDir(p)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -172,7 +181,7 @@ func (fbs *FileBackupStorage) ListBackups(ctx context.Context, dir string) ([]ba
 // StartBackup is part of the BackupStorage interface
 func (fbs *FileBackupStorage) StartBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
 	// Make sure the directory exists.
-	p := path.Join(FileBackupStorageRoot, dir)
+	p := path.Join(*fbs.root, dir)
 	if err := os2.MkdirAll(p); err != nil {
 		return nil, err
 	}
@@ -188,7 +197,7 @@ func (fbs *FileBackupStorage) StartBackup(ctx context.Context, dir, name string)
 
 // RemoveBackup is part of the BackupStorage interface
 func (fbs *FileBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
-	p := path.Join(FileBackupStorageRoot, dir, name)
+	p := path.Join(*fbs.root, dir, name)
 	return os.RemoveAll(p)
 }
 
@@ -198,9 +207,10 @@ func (fbs *FileBackupStorage) Close() error {
 }
 
 func (fbs *FileBackupStorage) WithParams(params backupstorage.Params) backupstorage.BackupStorage {
-	return &FileBackupStorage{params}
+	return newFileBackupStorage(params, fbs.root)
 }
 
 func init() {
 	backupstorage.BackupStorageMap["file"] = defaultFileBackupStorage
+	backupstorage.SecondaryBackupStorageMap["file"] = defaultSecondaryFileBackupStorage
 }
@@ -35,7 +35,7 @@ import (
 // returns a FileBackupStorage based on it
 func setupFileBackupStorage(t *testing.T) backupstorage.BackupStorage {
 	FileBackupStorageRoot = t.TempDir()
-	return newFileBackupStorage(backupstorage.NoParams())
+	return newFileBackupStorage(backupstorage.NoParams(), &FileBackupStorageRoot)
 }
 
 func TestListBackups(t *testing.T) {
@@ -192,3 +192,29 @@ func TestFileContents(t *testing.T) {
 		t.Fatalf("rc.Close failed: %v", err)
 	}
 }
+
+func TestSecondaryRoot(t *testing.T) {
+	FileBackupStorageRoot = t.TempDir()
+	FileBackupStorageSecondaryRoot = t.TempDir()
+	ctx := context.Background()
+
+	fbs := backupstorage.BackupStorageMap["file"]
+	secondary := backupstorage.SecondaryBackupStorageMap["file"].WithParams(backupstorage.NoParams())
+
+	dir := "keyspace/shard"
+	bh, err := secondary.StartBackup(ctx, dir, "cell-0001-2015-01-14-10-00-00")
+	if err != nil {
+		t.Fatalf("secondary.StartBackup failed: %v", err)
+	}
+	if err := bh.EndBackup(ctx); err != nil {
+		t.Fatalf("bh.EndBackup failed: %v", err)
+	}
+
+	// the backup only shows up in the secondary root
+	if bhs, err := secondary.ListBackups(ctx, dir); err != nil || len(bhs) != 1 {
+		t.Fatalf("secondary.ListBackups returned wrong results: %v %v", err, bhs)
+	}
+	if bhs, err := fbs.ListBackups(ctx, dir); err != nil || len(bhs) != 0 {
+		t.Fatalf("ListBackups returned wrong results: %v %v", err, bhs)
+	}
+}
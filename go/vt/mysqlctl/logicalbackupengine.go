@@ -0,0 +1,693 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"golang.org/x/sync/errgroup"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/dbconnpool"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	logicalBackupEngineName = "logical"
+
+	// logicalBackupMaxStatementSize is the size at which an INSERT statement is cut, so that
+	// restores stay well below the max_allowed_packet of the server.
+	logicalBackupMaxStatementSize = 1024 * 1024
+)
+
+var (
+	// logicalBackupChunkSize is the uncompressed size at which the rows of a table continue in another file.
+	logicalBackupChunkSize int64 = 64 * 1024 * 1024
+	// logicalBackupExcludeTables are the tables that logical backups skip.
+	logicalBackupExcludeTables []string
+	// logicalRestoreTables, if set, are the only tables that logical restores restore.
+	logicalRestoreTables []string
+	// logicalRestoreExcludeTables are the tables that logical restores skip.
+	logicalRestoreExcludeTables []string
+)
+
+// TableStreamer streams the rows of all the tables of the database of a tablet, from a single
+// consistent snapshot. The vstreamer of the tablet server implements it, and fills in the
+// target of the request.
+type TableStreamer interface {
+	StreamTables(ctx context.Context, request *binlogdatapb.VStreamTablesRequest, send func(*binlogdatapb.VStreamTablesResponse) error) error
+}
+
+// LogicalBackupEngine takes backups as SQL statements, streamed table by table from a
+// consistent snapshot of a running mysqld. Its backups can be restored into a different
+// MySQL version, and can be restored partially.
+type LogicalBackupEngine struct{}
+
+// logicalBackupManifest represents the backup.
+type logicalBackupManifest struct {
+	// BackupManifest is an anonymous embedding of the base manifest struct.
+	BackupManifest
+
+	// CompressionEngine stores which compression engine was used to compress the files.
+	CompressionEngine string `json:",omitempty"`
+	// ExternalDecompressor is the external decompressor command, if any.
+	ExternalDecompressor string `json:",omitempty"`
+
+	// DatabaseSchema is the CREATE DATABASE statement, with {{.DatabaseName}} in place of the
+	// database name.
+	DatabaseSchema string
+	// Tables are the tables and views of the backup.
+	Tables []logicalBackupTable
+}
+
+// logicalBackupTable describes a table or view of a logical backup.
+type logicalBackupTable struct {
+	Name string
+	// Type is either tmutils.TableBaseTable or tmutils.TableView.
+	Type string
+	// Schema is the CREATE statement of the table or view.
+	Schema string
+	// Files are the names of the files holding the rows of the table, in order.
+	Files []string `json:",omitempty"`
+	// Rows is the number of rows of the table.
+	Rows int64 `json:",omitempty"`
+}
+
+func init() {
+	BackupRestoreEngineMap[logicalBackupEngineName] = &LogicalBackupEngine{}
+
+	for _, cmd := range []string{"vtbackup", "vtcombo", "vttablet", "vttestserver"} {
+		servenv.OnParseFor(cmd, registerLogicalBackupEngineFlags)
+	}
+}
+
+func registerLogicalBackupEngineFlags(fs *pflag.FlagSet) {
+	fs.Int64Var(&logicalBackupChunkSize, "logical-backup-chunk-size", logicalBackupChunkSize, "uncompressed size in bytes at which the logical backup engine continues the rows of a table in another file")
+	fs.StringSliceVar(&logicalBackupExcludeTables, "logical-backup-exclude-tables", logicalBackupExcludeTables, "tables that the logical backup engine does not back up. Use /regexp/ for a pattern")
+	fs.StringSliceVar(&logicalRestoreTables, "logical-restore-tables", logicalRestoreTables, "if set, the only tables that are restored from logical backups, leaving the other tables of the tablet untouched. Use /regexp/ for a pattern")
+	fs.StringSliceVar(&logicalRestoreExcludeTables, "logical-restore-exclude-tables", logicalRestoreExcludeTables, "tables that are not restored from logical backups, leaving them untouched in the tablet. Use /regexp/ for a pattern")
+}
+
+// Name is part of the BackupEngine interface.
+func (be *LogicalBackupEngine) Name() string { return logicalBackupEngineName }
+
+// ShouldDrainForBackup satisfies the BackupEngine interface.
+// Logical backups read from a consistent snapshot, so the tablet keeps serving.
+func (be *LogicalBackupEngine) ShouldDrainForBackup(req *tabletmanagerdatapb.BackupRequest) bool {
+	return false
+}
+
+// ShouldStartMySQLAfterRestore signifies if this backup engine needs to restart MySQL once the restore is completed.
+// Logical restores run against a running mysqld.
+func (be *LogicalBackupEngine) ShouldStartMySQLAfterRestore() bool {
+	return false
+}
+
+// ExecuteBackup runs a backup based on given params. This could be a full or incremental backup.
+// The function returns a BackupResult that indicates the usability of the backup, and an overall error.
+func (be *LogicalBackupEngine) ExecuteBackup(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle) (result BackupResult, finalErr error) {
+	if params.TableStreamer == nil {
+		return BackupUnusable, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the %s backup engine can only run in a serving tablet", logicalBackupEngineName)
+	}
+	if params.DbName == "" {
+		return BackupUnusable, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the %s backup engine needs a database name", logicalBackupEngineName)
+	}
+	params.Logger.Infof("Starting logical backup of %v", params.DbName)
+
+	serverUUID, err := params.Mysqld.GetServerUUID(ctx)
+	if err != nil {
+		return BackupUnusable, vterrors.Wrap(err, "can't get server uuid")
+	}
+	mysqlVersion, err := params.Mysqld.GetVersionString(ctx)
+	if err != nil {
+		return BackupUnusable, vterrors.Wrap(err, "can't get MySQL version")
+	}
+	// The schemas of the tables come with their rows, from the snapshot. Only the database
+	// and the views, which hold no rows, are read here.
+	sd, err := params.Mysqld.GetSchema(ctx, params.DbName, &tabletmanagerdatapb.GetSchemaRequest{
+		ExcludeTables:   logicalBackupExcludeTables,
+		IncludeViews:    true,
+		TableSchemaOnly: true,
+	})
+	if err != nil {
+		return BackupUnusable, vterrors.Wrap(err, "can't get schema")
+	}
+	bm := &logicalBackupManifest{
+		DatabaseSchema: sd.DatabaseSchema,
+	}
+
+	// The position of the snapshot comes with the rows. If there are no rows to stream,
+	// the current position is as good as any.
+	position, err := params.Mysqld.PrimaryPosition(ctx)
+	if err != nil {
+		return BackupUnusable, vterrors.Wrap(err, "can't get position")
+	}
+
+	var tw *logicalTableWriter
+	defer func() {
+		if tw != nil {
+			finalErr = errors.Join(finalErr, tw.close())
+		}
+	}()
+	request := &binlogdatapb.VStreamTablesRequest{
+		ExcludeTables: logicalBackupExcludeTables,
+		TableSchemas:  true,
+	}
+	// Waiting on the limiter holds back the stream, and so the reads of the tablet.
+	limiter := newIOLimiter(params.Throttler, params.Stats)
+	err = params.TableStreamer.StreamTables(ctx, request, func(resp *binlogdatapb.VStreamTablesResponse) error {
+		if limiter != nil {
+			if err := limiter.wait(ctx, resp.SizeVT()); err != nil {
+				return err
+			}
+		}
+		if resp.Gtid != "" {
+			pos, err := replication.DecodePosition(resp.Gtid)
+			if err != nil {
+				return vterrors.Wrapf(err, "can't decode snapshot position %v", resp.Gtid)
+			}
+			position = pos
+		}
+		if tw == nil || tw.table.Name != resp.TableName {
+			if tw != nil {
+				err := tw.close()
+				tw = nil
+				if err != nil {
+					return err
+				}
+			}
+			if resp.TableSchema == "" {
+				return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "got rows for table %v before its schema", resp.TableName)
+			}
+			// The snapshot holds the table from the moment its schema is read, so its
+			// generated columns cannot change anymore either.
+			generatedColumns, err := be.generatedColumns(ctx, params, resp.TableName)
+			if err != nil {
+				return err
+			}
+			params.Logger.Infof("Backing up table %v", resp.TableName)
+			bm.Tables = append(bm.Tables, logicalBackupTable{
+				Name:   resp.TableName,
+				Type:   tmutils.TableBaseTable,
+				Schema: resp.TableSchema,
+			})
+			i := len(bm.Tables) - 1
+			tw = newLogicalTableWriter(ctx, params, bh, i, &bm.Tables[i], generatedColumns)
+		}
+		return tw.write(resp)
+	})
+	if err != nil {
+		return BackupUnusable, vterrors.Wrap(err, "failed to stream tables")
+	}
+	if tw != nil {
+		err := tw.close()
+		tw = nil
+		if err != nil {
+			return BackupUnusable, err
+		}
+	}
+	for _, td := range sd.TableDefinitions {
+		if td.Type == tmutils.TableView {
+			bm.Tables = append(bm.Tables, logicalBackupTable{
+				Name:   td.Name,
+				Type:   td.Type,
+				Schema: td.Schema,
+			})
+		}
+	}
+
+	bm.BackupManifest = BackupManifest{
+		BackupName:     bh.Name(),
+		BackupMethod:   logicalBackupEngineName,
+		Position:       position,
+		PurgedPosition: position,
+		BackupTime:     FormatRFC3339(params.BackupTime.UTC()),
+		FinishedTime:   FormatRFC3339(time.Now().UTC()),
+		ServerUUID:     serverUUID,
+		TabletAlias:    params.TabletAlias,
+		Keyspace:       params.Keyspace,
+		Shard:          params.Shard,
+		MySQLVersion:   mysqlVersion,
+		UpgradeSafe:    true,
+	}
+	bm.CompressionEngine = CompressionEngineName
+	if ExternalCompressorCmd != "" {
+		bm.CompressionEngine = ExternalCompressor
+		bm.ExternalDecompressor = ManifestExternalDecompressorCmd
+	}
+
+	params.Logger.Infof("Writing backup MANIFEST")
+	mwc, err := bh.AddFile(ctx, backupManifestFileName, backupstorage.FileSizeUnknown)
+	if err != nil {
+		return BackupUnusable, vterrors.Wrapf(err, "cannot add %v to backup", backupManifestFileName)
+	}
+	defer closeFile(mwc, backupManifestFileName, params.Logger, &finalErr)
+
+	data, err := json.MarshalIndent(bm, "", "  ")
+	if err != nil {
+		return BackupUnusable, vterrors.Wrapf(err, "cannot JSON encode %v", backupManifestFileName)
+	}
+	if _, err := mwc.Write(data); err != nil {
+		return BackupUnusable, vterrors.Wrapf(err, "cannot write %v", backupManifestFileName)
+	}
+
+	params.Logger.Infof("Logical backup of %v tables completed at position %v", len(bm.Tables), position)
+	return BackupUsable, nil
+}
+
+// generatedColumns returns the generated columns of a table. Those cannot be inserted
+// into, so backups leave them out.
+func (be *LogicalBackupEngine) generatedColumns(ctx context.Context, params BackupParams, table string) (map[string]bool, error) {
+	qr, err := params.Mysqld.FetchSuperQuery(ctx, fmt.Sprintf(
+		"SELECT COLUMN_NAME FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = %s AND TABLE_NAME = %s AND GENERATION_EXPRESSION != ''",
+		sqltypes.EncodeStringSQL(params.DbName), sqltypes.EncodeStringSQL(table)))
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "can't get generated columns of table %v", table)
+	}
+	generatedColumns := make(map[string]bool, len(qr.Rows))
+	for _, row := range qr.Rows {
+		generatedColumns[row[0].ToString()] = true
+	}
+	return generatedColumns, nil
+}
+
+// logicalTableWriter writes the rows of a table as INSERT statements, one per line, into
+// compressed files of about logicalBackupChunkSize uncompressed bytes each.
+type logicalTableWriter struct {
+	ctx              context.Context
+	params           BackupParams
+	bh               backupstorage.BackupHandle
+	index            int
+	table            *logicalBackupTable
+	generatedColumns map[string]bool
+
+	// fields are the fields of the stream, which only come with its first response.
+	fields []*querypb.Field
+	// columns are the indexes of the fields that are inserted.
+	columns []int
+	// introducers are the charset introducers of the inserted columns, if any. Restores run
+	// with binary names, so text and JSON values must say which charset they are in.
+	introducers []string
+	// insert is the beginning of every INSERT statement.
+	insert string
+	stmt   bytes.Buffer
+
+	wc         io.WriteCloser
+	compressor io.WriteCloser
+	written    int64
+}
+
+func newLogicalTableWriter(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, index int, table *logicalBackupTable, generatedColumns map[string]bool) *logicalTableWriter {
+	return &logicalTableWriter{
+		ctx:              ctx,
+		params:           params,
+		bh:               bh,
+		index:            index,
+		table:            table,
+		generatedColumns: generatedColumns,
+	}
+}
+
+func (tw *logicalTableWriter) setFields(fields []*querypb.Field) {
+	tw.fields = fields
+	tw.columns = tw.columns[:0]
+	tw.introducers = tw.introducers[:0]
+	var buf strings.Builder
+	buf.WriteString("INSERT INTO ")
+	buf.WriteString(sqlescape.EscapeID(tw.table.Name))
+	buf.WriteString(" (")
+	for i, field := range fields {
+		if tw.generatedColumns[field.Name] {
+			continue
+		}
+		if len(tw.columns) > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(sqlescape.EscapeID(field.Name))
+		tw.columns = append(tw.columns, i)
+		tw.introducers = append(tw.introducers, logicalBackupIntroducer(field))
+	}
+	buf.WriteString(") VALUES ")
+	tw.insert = buf.String()
+}
+
+// logicalBackupIntroducer returns the charset introducer of the values of a field. JSON values
+// are streamed as utf8mb4 text, and MySQL refuses to create JSON values out of binary strings.
+// Text values are in the charset of their column.
+func logicalBackupIntroducer(field *querypb.Field) string {
+	switch {
+	case field.Type == querypb.Type_JSON:
+		return "_utf8mb4"
+	case sqltypes.IsText(field.Type) && field.Charset != collations.CollationBinaryID:
+		if charset := collations.MySQL8().LookupCharsetName(collations.ID(field.Charset)); charset != "" {
+			return "_" + charset
+		}
+	}
+	return ""
+}
+
+func (tw *logicalTableWriter) write(resp *binlogdatapb.VStreamTablesResponse) error {
+	if len(resp.Fields) > 0 {
+		tw.setFields(resp.Fields)
+	}
+	for _, row := range resp.Rows {
+		if tw.fields == nil {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "got rows for table %v before its fields", tw.table.Name)
+		}
+		values := sqltypes.MakeRowTrusted(tw.fields, row)
+		if tw.stmt.Len() == 0 {
+			tw.stmt.WriteString(tw.insert)
+		} else {
+			tw.stmt.WriteByte(',')
+		}
+		tw.stmt.WriteByte('(')
+		for i, col := range tw.columns {
+			if i > 0 {
+				tw.stmt.WriteByte(',')
+			}
+			if !values[col].IsNull() {
+				tw.stmt.WriteString(tw.introducers[i])
+			}
+			values[col].EncodeSQL(&tw.stmt)
+		}
+		tw.stmt.WriteByte(')')
+		tw.table.Rows++
+		if tw.stmt.Len() >= logicalBackupMaxStatementSize {
+			if err := tw.flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// flush writes the pending INSERT statement, and moves on to the next file once the
+// current one is large enough.
+func (tw *logicalTableWriter) flush() error {
+	if tw.stmt.Len() == 0 {
+		return nil
+	}
+	if tw.wc == nil {
+		if err := tw.openFile(); err != nil {
+			return err
+		}
+	}
+	// Values are escaped, so statements never span lines.
+	tw.stmt.WriteByte('\n')
+	n, err := tw.compressor.Write(tw.stmt.Bytes())
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot write rows of table %v", tw.table.Name)
+	}
+	tw.written += int64(n)
+	tw.stmt.Reset()
+	if tw.written >= logicalBackupChunkSize {
+		return tw.closeFile()
+	}
+	return nil
+}
+
+func (tw *logicalTableWriter) openFile() error {
+	name := fmt.Sprintf("%d.%d", tw.index, len(tw.table.Files))
+	wc, err := tw.bh.AddFile(tw.ctx, name, backupstorage.FileSizeUnknown)
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot add file %v to backup", name)
+	}
+	var compressor io.WriteCloser
+	if ExternalCompressorCmd != "" {
+		compressor, err = newExternalCompressor(tw.ctx, ExternalCompressorCmd, wc, tw.params.Logger)
+	} else {
+		compressor, err = newBuiltinCompressor(CompressionEngineName, wc, tw.params.Logger)
+	}
+	if err != nil {
+		return errors.Join(vterrors.Wrap(err, "can't create compressor"), wc.Close())
+	}
+	tw.wc, tw.compressor, tw.written = wc, compressor, 0
+	tw.table.Files = append(tw.table.Files, name)
+	return nil
+}
+
+func (tw *logicalTableWriter) closeFile() error {
+	if tw.wc == nil {
+		return nil
+	}
+	err := tw.compressor.Close()
+	if err != nil {
+		err = vterrors.Wrapf(err, "cannot close compressor of table %v", tw.table.Name)
+	}
+	if closeErr := tw.wc.Close(); closeErr != nil {
+		err = errors.Join(err, vterrors.Wrapf(closeErr, "cannot close file of table %v", tw.table.Name))
+	}
+	tw.wc, tw.compressor = nil, nil
+	return err
+}
+
+// close writes the pending rows of the table, and closes its last file.
+func (tw *logicalTableWriter) close() error {
+	err := tw.flush()
+	return errors.Join(err, tw.closeFile())
+}
+
+// ExecuteRestore restores from a backup. Unless --logical-restore-tables or
+// --logical-restore-exclude-tables are set, the database is replaced by the one in the backup,
+// and the returned manifest positions the tablet at the position of the backup. Otherwise, only
+// the selected tables are replaced, and the tablet keeps its position.
+func (be *LogicalBackupEngine) ExecuteRestore(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle) (*BackupManifest, error) {
+	var bm logicalBackupManifest
+	if err := getBackupManifestInto(ctx, bh, &bm); err != nil {
+		return nil, err
+	}
+	filter, err := tmutils.NewTableFilter(logicalRestoreTables, logicalRestoreExcludeTables, true)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "invalid table filter")
+	}
+	partial := len(logicalRestoreTables) > 0 || len(logicalRestoreExcludeTables) > 0
+	var tables []logicalBackupTable
+	for _, table := range bm.Tables {
+		if filter.Includes(table.Name, table.Type) {
+			tables = append(tables, table)
+		}
+	}
+	params.Logger.Infof("Restoring %v of %v tables of logical backup %v into %v", len(tables), len(bm.Tables), bh.Name(), params.DbName)
+
+	// mark restore as in progress
+	if err := createStateFile(params.Cnf); err != nil {
+		return nil, err
+	}
+	// make sure semi-sync is disabled, otherwise we will wait forever for acknowledgements
+	if err := params.Mysqld.SetSemiSyncEnabled(ctx, false, false); err != nil {
+		return nil, vterrors.Wrap(err, "disable semi-sync failed")
+	}
+	resetFunc, err := disableSuperReadOnly(ctx, params)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "unable to disable super-read-only")
+	}
+	defer resetFunc()
+
+	manifest := bm.BackupManifest
+	if partial {
+		// The tables that are not restored keep their data, so the tablet keeps its position.
+		if err := params.Mysqld.StopReplication(ctx, params.HookExtraEnv); err != nil {
+			return nil, vterrors.Wrap(err, "unable to stop replication")
+		}
+		if manifest.Position, err = params.Mysqld.PrimaryPosition(ctx); err != nil {
+			return nil, vterrors.Wrap(err, "can't get position")
+		}
+		manifest.PurgedPosition = manifest.Position
+	} else if err := params.Mysqld.ResetReplication(ctx); err != nil {
+		return nil, vterrors.Wrap(err, "unable to reset replication")
+	}
+
+	if err := be.restoreSchema(ctx, params, &bm, tables, partial); err != nil {
+		return nil, err
+	}
+	// For optimization, we are replacing pargzip with pgzip, so newBuiltinDecompressor doesn't have to print a warning for every file.
+	if bm.CompressionEngine == PargzipCompressor {
+		params.Logger.Warningf(`engine "pargzip" doesn't support decompression, using "pgzip" instead`)
+		bm.CompressionEngine = PgzipCompressor
+	}
+	if err := be.restoreRows(ctx, params, bh, &bm, tables, newIOLimiter(nil, params.Stats)); err != nil {
+		return nil, err
+	}
+
+	params.Logger.Infof("Logical restore completed")
+	return &manifest, nil
+}
+
+// logicalRestoreConn returns a connection for a logical restore. The connection is not pooled,
+// since the restore changes session variables that must not leak into other queries.
+func logicalRestoreConn(ctx context.Context, params RestoreParams) (*dbconnpool.DBConnection, error) {
+	conn, err := params.Mysqld.GetDbaConnection(ctx)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "unable to obtain a connection to the database")
+	}
+	// Each tablet restores on its own, so the restore must not be replicated. Rows are
+	// streamed with binary names, which keeps string values exactly as they are stored.
+	for _, query := range []string{
+		"SET sql_log_bin = 0",
+		"SET foreign_key_checks = 0",
+		"SET unique_checks = 0",
+		"SET NAMES binary",
+	} {
+		if _, err := conn.ExecuteFetch(query, 0, false); err != nil {
+			conn.Close()
+			return nil, vterrors.Wrapf(err, "failed to execute %v", query)
+		}
+	}
+	return conn, nil
+}
+
+// restoreSchema (re)creates the database, or only the given tables if the restore is partial.
+func (be *LogicalBackupEngine) restoreSchema(ctx context.Context, params RestoreParams, bm *logicalBackupManifest, tables []logicalBackupTable, partial bool) error {
+	conn, err := logicalRestoreConn(ctx, params)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	dbName := sqlescape.EscapeID(params.DbName)
+	var queries []string
+	if partial {
+		queries = append(queries, "CREATE DATABASE IF NOT EXISTS "+dbName, "USE "+dbName)
+		for _, table := range tables {
+			if table.Type == tmutils.TableView {
+				queries = append(queries, "DROP VIEW IF EXISTS "+sqlescape.EscapeID(table.Name))
+			} else {
+				queries = append(queries, "DROP TABLE IF EXISTS "+sqlescape.EscapeID(table.Name))
+			}
+		}
+	} else {
+		params.Logger.Infof("Restore: dropping database %v", params.DbName)
+		queries = append(queries,
+			"DROP DATABASE IF EXISTS "+dbName,
+			strings.Replace(bm.DatabaseSchema, "{{.DatabaseName}}", dbName, 1),
+			"USE "+dbName,
+		)
+	}
+	// Views are created last, since they may select from any of the tables.
+	for _, tableType := range []string{tmutils.TableBaseTable, tmutils.TableView} {
+		for _, table := range tables {
+			if table.Type == tableType {
+				queries = append(queries, strings.ReplaceAll(table.Schema, "{{.DatabaseName}}", dbName))
+			}
+		}
+	}
+	for _, query := range queries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := conn.ExecuteFetch(query, 0, false); err != nil {
+			return vterrors.Wrapf(err, "failed to execute %v", query)
+		}
+	}
+	return nil
+}
+
+// restoreRows loads the rows of the given tables, up to params.Concurrency tables at a time.
+// Reading the rows is subject to the given limiter, if any.
+func (be *LogicalBackupEngine) restoreRows(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, bm *logicalBackupManifest, tables []logicalBackupTable, limiter *ioLimiter) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(params.Concurrency, 1))
+	for _, table := range tables {
+		if len(table.Files) == 0 {
+			continue
+		}
+		g.Go(func() error {
+			params.Logger.Infof("Restoring %v rows of table %v", table.Rows, table.Name)
+			conn, err := logicalRestoreConn(ctx, params)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+			if _, err := conn.ExecuteFetch("USE "+sqlescape.EscapeID(params.DbName), 0, false); err != nil {
+				return vterrors.Wrapf(err, "cannot use database %v", params.DbName)
+			}
+			for _, name := range table.Files {
+				if err := be.restoreFile(ctx, params, bh, bm, conn, name, limiter); err != nil {
+					return vterrors.Wrapf(err, "cannot restore table %v", table.Name)
+				}
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// restoreFile executes the INSERT statements of one file of a backup.
+func (be *LogicalBackupEngine) restoreFile(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, bm *logicalBackupManifest, conn *dbconnpool.DBConnection, name string, limiter *ioLimiter) (finalErr error) {
+	source, err := bh.ReadFile(ctx, name)
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot read file %v", name)
+	}
+	defer source.Close()
+
+	var decompressor io.ReadCloser
+	switch {
+	case ExternalDecompressorCmd != "":
+		decompressor, err = newExternalDecompressor(ctx, ExternalDecompressorCmd, source, params.Logger)
+	case bm.CompressionEngine == ExternalCompressor:
+		decompressor, err = newExternalDecompressor(ctx, bm.ExternalDecompressor, source, params.Logger)
+	default:
+		decompressor, err = newBuiltinDecompressor(bm.CompressionEngine, source, params.Logger)
+	}
+	if err != nil {
+		return vterrors.Wrap(err, "can't create decompressor")
+	}
+	defer func() {
+		if err := decompressor.Close(); err != nil {
+			finalErr = errors.Join(finalErr, vterrors.Wrap(err, "failed to close decompressor"))
+		}
+	}()
+
+	reader := bufio.NewReaderSize(limiter.reader(ctx, decompressor), logicalBackupMaxStatementSize)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return vterrors.Wrapf(err, "cannot read file %v", name)
+		}
+		if stmt := strings.TrimSuffix(line, "\n"); stmt != "" {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if _, err := conn.ExecuteFetch(stmt, 0, false); err != nil {
+				return vterrors.Wrapf(err, "failed to insert rows from file %v", name)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
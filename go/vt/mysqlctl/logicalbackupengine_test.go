@@ -0,0 +1,321 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/fakesqldb"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+const logicalBackupTestPosition = "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-100"
+
+type fakeTableStreamer struct {
+	responses []*binlogdatapb.VStreamTablesResponse
+}
+
+// StreamTables sends the responses of the tables that the request does not exclude, with the
+// schema of each table in its first response, as the vstreamer does.
+func (fts *fakeTableStreamer) StreamTables(ctx context.Context, request *binlogdatapb.VStreamTablesRequest, send func(*binlogdatapb.VStreamTablesResponse) error) error {
+	filter, err := tmutils.NewTableFilter(nil, request.ExcludeTables, false)
+	if err != nil {
+		return err
+	}
+	for _, resp := range fts.responses {
+		if !filter.Includes(resp.TableName, tmutils.TableBaseTable) {
+			continue
+		}
+		if !request.TableSchemas {
+			resp = resp.CloneVT()
+			resp.TableSchema = ""
+		}
+		if err := send(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newLogicalBackupTestTableStreamer streams t1(id, name, generated) with three rows, and
+// t2(id, doc, note) with one row.
+func newLogicalBackupTestTableStreamer() *fakeTableStreamer {
+	t1 := sqltypes.MakeTestResult(sqltypes.MakeTestFields("id|name|generated", "int64|varbinary|int64"),
+		"1|a|2",
+		"2|it's\nmultiline|4",
+	)
+	t1More := sqltypes.MakeTestResult(t1.Fields, "3|NULL|6")
+	t1More.Rows[0][1] = sqltypes.NULL
+	t2 := sqltypes.MakeTestResult(sqltypes.MakeTestFields("id|doc|note", "int64|json|varchar"), `10|{"a": "it's"}|café`)
+	t2.Fields[1].Charset = collations.CollationBinaryID
+	t2.Fields[2].Charset = uint32(collations.CollationUtf8mb4ID)
+	return &fakeTableStreamer{
+		responses: []*binlogdatapb.VStreamTablesResponse{{
+			TableName:   "t1",
+			TableSchema: logicalBackupTestT1Schema,
+			Fields:      t1.Fields,
+			Gtid:        logicalBackupTestPosition,
+			Rows:        sqltypes.RowsToProto3(t1.Rows),
+		}, {
+			TableName: "t1",
+			Gtid:      logicalBackupTestPosition,
+			Rows:      sqltypes.RowsToProto3(t1More.Rows),
+		}, {
+			TableName:   "excluded",
+			TableSchema: "CREATE TABLE `excluded` (`id` bigint, PRIMARY KEY (`id`))",
+			Fields:      t2.Fields[:1],
+			Gtid:        logicalBackupTestPosition,
+			Rows:        sqltypes.RowsToProto3([][]sqltypes.Value{{sqltypes.NewInt64(1)}}),
+		}, {
+			TableName:   "t2",
+			TableSchema: "CREATE TABLE `t2` (`id` bigint, `doc` json, `note` varchar(32), PRIMARY KEY (`id`))",
+			Fields:      t2.Fields,
+			Gtid:        logicalBackupTestPosition,
+			Rows:        sqltypes.RowsToProto3(t2.Rows),
+		}},
+	}
+}
+
+// logicalBackupTestT1Schema is the schema of t1 as of the snapshot. The schema that GetSchema
+// returns predates a DDL, so the backup must not use it.
+const logicalBackupTestT1Schema = "CREATE TABLE `t1` (`id` bigint, `name` varbinary(32), `generated` bigint AS (`id` * 2), PRIMARY KEY (`id`))"
+
+func newLogicalBackupTestSchema() *tabletmanagerdatapb.SchemaDefinition {
+	return &tabletmanagerdatapb.SchemaDefinition{
+		DatabaseSchema: "CREATE DATABASE {{.DatabaseName}} /*!40100 DEFAULT CHARACTER SET utf8mb4 */",
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+			Name:   "t1",
+			Type:   tmutils.TableBaseTable,
+			Schema: "CREATE TABLE `t1` (`id` bigint, `name` varbinary(16), PRIMARY KEY (`id`))",
+		}, {
+			Name:   "t2",
+			Type:   tmutils.TableBaseTable,
+			Schema: "CREATE TABLE `t2` (`id` bigint, PRIMARY KEY (`id`))",
+		}, {
+			Name:   "v1",
+			Type:   tmutils.TableView,
+			Schema: "CREATE VIEW {{.DatabaseName}}.`v1` AS SELECT `id` FROM {{.DatabaseName}}.`t1`",
+		}, {
+			Name:   "excluded",
+			Type:   tmutils.TableBaseTable,
+			Schema: "CREATE TABLE `excluded` (`id` bigint, PRIMARY KEY (`id`))",
+		}},
+	}
+}
+
+// logicalBackupTestQueries records the queries that a restore runs, leaving out the ones
+// that only set up its connections.
+type logicalBackupTestQueries struct {
+	mu      sync.Mutex
+	queries []string
+}
+
+func (q *logicalBackupTestQueries) add(query string) {
+	if strings.HasPrefix(query, "SET ") {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.queries = append(q.queries, query)
+}
+
+func (q *logicalBackupTestQueries) get() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return slices.Clone(q.queries)
+}
+
+func TestLogicalBackupEngine(t *testing.T) {
+	ctx := context.Background()
+	previousBackupStorageImplementation := backupstorage.BackupStorageImplementation
+	previousFileBackupStorageRoot := filebackupstorage.FileBackupStorageRoot
+	previousChunkSize := logicalBackupChunkSize
+	previousExcludeTables := logicalBackupExcludeTables
+	backupstorage.BackupStorageImplementation = "file"
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	// Every INSERT statement goes to a file of its own.
+	logicalBackupChunkSize = 1
+	logicalBackupExcludeTables = []string{"/^exclude/"}
+	defer func() {
+		backupstorage.BackupStorageImplementation = previousBackupStorageImplementation
+		filebackupstorage.FileBackupStorageRoot = previousFileBackupStorageRoot
+		logicalBackupChunkSize = previousChunkSize
+		logicalBackupExcludeTables = previousExcludeTables
+		logicalRestoreTables = nil
+		logicalRestoreExcludeTables = nil
+	}()
+	bs, err := backupstorage.GetBackupStorage()
+	require.NoError(t, err)
+	defer bs.Close()
+
+	fakedb := fakesqldb.New(t)
+	defer fakedb.Close()
+	mysqld := NewFakeMysqlDaemon(fakedb)
+	defer mysqld.Close()
+	mysqld.Schema = newLogicalBackupTestSchema()
+	mysqld.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		"information_schema.COLUMNS .* TABLE_NAME = 't1'": sqltypes.MakeTestResult(sqltypes.MakeTestFields("COLUMN_NAME", "varchar"), "generated"),
+		"information_schema.COLUMNS .* TABLE_NAME = 't2'": sqltypes.MakeTestResult(sqltypes.MakeTestFields("COLUMN_NAME", "varchar")),
+	}
+
+	engine := &LogicalBackupEngine{}
+	bh, err := bs.StartBackup(ctx, GetBackupDir("ks", "0"), "2025-01-01.000000.zone1-0000000101")
+	require.NoError(t, err)
+
+	params := BackupParams{
+		Mysqld:      mysqld,
+		Logger:      logutil.NewMemoryLogger(),
+		Keyspace:    "ks",
+		Shard:       "0",
+		TabletAlias: "zone1-0000000101",
+		BackupTime:  time.Now(),
+		DbName:      "vt_ks",
+	}
+	_, err = engine.ExecuteBackup(ctx, params, bh)
+	require.ErrorContains(t, err, "can only run in a serving tablet")
+
+	params.TableStreamer = newLogicalBackupTestTableStreamer()
+	throttler := &fakeBackupThrottler{}
+	params.Throttler = throttler
+	result, err := engine.ExecuteBackup(ctx, params, bh)
+	require.NoError(t, err)
+	require.Equal(t, BackupUsable, result)
+	// The throttler is checked before each of the three responses that are not excluded.
+	assert.Equal(t, 3, throttler.checks)
+	require.NoError(t, bh.EndBackup(ctx))
+	bhs, err := bs.ListBackups(ctx, GetBackupDir("ks", "0"))
+	require.NoError(t, err)
+	require.Len(t, bhs, 1)
+	bh = bhs[0]
+
+	var bm logicalBackupManifest
+	require.NoError(t, getBackupManifestInto(ctx, bh, &bm))
+	assert.Equal(t, logicalBackupEngineName, bm.BackupMethod)
+	assert.Equal(t, logicalBackupTestPosition, replication.EncodePosition(bm.Position))
+	assert.True(t, bm.UpgradeSafe)
+	require.Len(t, bm.Tables, 3)
+	assert.Equal(t, logicalBackupTable{Name: "t1", Type: tmutils.TableBaseTable, Schema: logicalBackupTestT1Schema, Files: []string{"0.0"}, Rows: 3}, bm.Tables[0])
+	assert.Equal(t, "t2", bm.Tables[1].Name)
+	assert.Equal(t, []string{"1.0"}, bm.Tables[1].Files)
+	assert.Equal(t, "v1", bm.Tables[2].Name)
+	assert.Empty(t, bm.Tables[2].Files)
+
+	fileNames, err := backupFileNames(mustReadBackupFile(t, bh, backupManifestFileName))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0.0", "1.0"}, fileNames)
+
+	t1Rows := []string{
+		"INSERT INTO `t1` (`id`,`name`) VALUES (1,_binary'a'),(2,_binary'it\\'s\\nmultiline'),(3,null)",
+	}
+	t2Rows := []string{
+		"INSERT INTO `t2` (`id`,`doc`,`note`) VALUES " + `(10,_utf8mb4'{"a": "it\'s"}',_utf8mb4'café')`,
+	}
+	restore := func(t *testing.T, expectedSuperQueries ...string) []string {
+		var queries logicalBackupTestQueries
+		fakedb.ClearQueryPattern()
+		fakedb.AddQueryPatternWithCallback(".*", &sqltypes.Result{}, queries.add)
+		mysqld.ExpectedExecuteSuperQueryList = expectedSuperQueries
+		mysqld.ExpectedExecuteSuperQueryCurrent = 0
+		mysqld.CurrentPrimaryPosition, err = replication.DecodePosition("MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-200")
+		require.NoError(t, err)
+
+		manifest, err := engine.ExecuteRestore(ctx, RestoreParams{
+			Cnf:         &Mycnf{DataDir: path.Join(t.TempDir(), "data")},
+			Mysqld:      mysqld,
+			Logger:      logutil.NewMemoryLogger(),
+			Concurrency: 1,
+			DbName:      "vt_other",
+		}, bh)
+		require.NoError(t, err)
+		assert.Equal(t, len(expectedSuperQueries), mysqld.ExpectedExecuteSuperQueryCurrent)
+		if len(logicalRestoreTables) == 0 {
+			assert.Equal(t, logicalBackupTestPosition, replication.EncodePosition(manifest.Position))
+		} else {
+			assert.Equal(t, mysqld.CurrentPrimaryPosition, manifest.Position)
+		}
+		return queries.get()
+	}
+
+	t.Run("full restore", func(t *testing.T) {
+		queries := restore(t, "FAKE RESET ALL REPLICATION")
+		assert.Equal(t, slices.Concat([]string{
+			"DROP DATABASE IF EXISTS `vt_other`",
+			"CREATE DATABASE `vt_other` /*!40100 DEFAULT CHARACTER SET utf8mb4 */",
+			"USE `vt_other`",
+			bm.Tables[0].Schema,
+			bm.Tables[1].Schema,
+			"CREATE VIEW `vt_other`.`v1` AS SELECT `id` FROM `vt_other`.`t1`",
+			"USE `vt_other`",
+		}, t1Rows, []string{"USE `vt_other`"}, t2Rows), queries)
+	})
+
+	t.Run("partial restore", func(t *testing.T) {
+		logicalRestoreTables = []string{"/^t/"}
+		logicalRestoreExcludeTables = []string{"t1"}
+		queries := restore(t, "STOP REPLICA")
+		assert.Equal(t, slices.Concat([]string{
+			"CREATE DATABASE IF NOT EXISTS `vt_other`",
+			"USE `vt_other`",
+			"DROP TABLE IF EXISTS `t2`",
+			bm.Tables[1].Schema,
+			"USE `vt_other`",
+		}, t2Rows), queries)
+	})
+}
+
+func mustReadBackupFile(t *testing.T, bh backupstorage.BackupHandle, fileName string) []byte {
+	data, err := readBackupFile(context.Background(), bh, fileName)
+	require.NoError(t, err)
+	return data
+}
+
+func TestLogicalBackupEngineTableWriter(t *testing.T) {
+	fields := []*querypb.Field{{Name: "id", Type: sqltypes.Int64}, {Name: "v", Type: sqltypes.VarBinary}}
+	tw := newLogicalTableWriter(context.Background(), BackupParams{}, nil, 0, &logicalBackupTable{Name: "t"}, nil)
+	tw.setFields(fields)
+	assert.Equal(t, "INSERT INTO `t` (`id`,`v`) VALUES ", tw.insert)
+
+	tw = newLogicalTableWriter(context.Background(), BackupParams{}, nil, 0, &logicalBackupTable{Name: "t"}, map[string]bool{"id": true})
+	tw.setFields(fields)
+	assert.Equal(t, "INSERT INTO `t` (`v`) VALUES ", tw.insert)
+	assert.Equal(t, []int{1}, tw.columns)
+
+	err := tw.write(&binlogdatapb.VStreamTablesResponse{TableName: "t"})
+	require.NoError(t, err)
+	tw = newLogicalTableWriter(context.Background(), BackupParams{}, nil, 0, &logicalBackupTable{Name: "t"}, nil)
+	err = tw.write(&binlogdatapb.VStreamTablesResponse{TableName: "t", Rows: sqltypes.RowsToProto3([][]sqltypes.Value{{sqltypes.NewInt64(1)}})})
+	assert.ErrorContains(t, err, "before its fields")
+}
@@ -27,6 +27,7 @@ import (
 	"os/exec"
 	"path"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -134,6 +135,15 @@ func (be *MySQLShellBackupEngine) ExecuteBackup(ctx context.Context, params Back
 		return BackupUnusable, vterrors.Wrap(err, "can't get MySQL version")
 	}
 
+	dumpFlags, err := mysqlShellDumpFlagsWithLimits()
+	if err != nil {
+		return BackupUnusable, vterrors.Wrap(err, "failed backup precheck")
+	}
+	if params.Throttler != nil || backupMaxIOPS > 0 {
+		// MySQL Shell writes the dump on its own, so only its rate can be limited.
+		params.Logger.Warningf("the %s engine does not support the tablet throttler nor --backup-max-iops", mysqlShellBackupEngineName)
+	}
+
 	args := []string{}
 	if mysqlShellFlags != "" {
 		args = append(args, strings.Fields(mysqlShellFlags)...)
@@ -141,7 +151,7 @@ func (be *MySQLShellBackupEngine) ExecuteBackup(ctx context.Context, params Back
 
 	args = append(args, "-e", fmt.Sprintf("util.dumpInstance(%q, %s)",
 		location,
-		mysqlShellDumpFlags,
+		dumpFlags,
 	))
 
 	// to be able to get the consistent GTID sets, we will acquire a global read lock before starting mysql shell.
@@ -325,7 +335,7 @@ func (be *MySQLShellBackupEngine) ExecuteRestore(ctx context.Context, params Res
 
 	// we need to disable SuperReadOnly otherwise we won't be able to restore the backup properly.
 	// once the backups is complete, we will restore it to its previous state.
-	resetFunc, err := be.handleSuperReadOnly(ctx, params)
+	resetFunc, err := disableSuperReadOnly(ctx, params)
 	if err != nil {
 		return nil, vterrors.Wrap(err, "unable to disable super-read-only")
 	}
@@ -396,6 +406,33 @@ func (be *MySQLShellBackupEngine) ShouldStartMySQLAfterRestore() bool {
 
 func (be *MySQLShellBackupEngine) Name() string { return mysqlShellBackupEngineName }
 
+// mysqlShellDumpFlagsWithLimits returns the dump flags, with a maxRate that honors
+// --backup-max-bytes-per-sec unless the flags set one already. MySQL Shell applies
+// maxRate to each of its threads.
+func mysqlShellDumpFlagsWithLimits() (string, error) {
+	if backupMaxBytesPerSec <= 0 {
+		return mysqlShellDumpFlags, nil
+	}
+	dumpFlags := map[string]any{}
+	if err := json.Unmarshal([]byte(mysqlShellDumpFlags), &dumpFlags); err != nil {
+		return "", fmt.Errorf("%w: unable to parse JSON of dump flags", ErrMySQLShellPreCheck)
+	}
+	if _, ok := dumpFlags["maxRate"]; ok {
+		return mysqlShellDumpFlags, nil
+	}
+	// MySQL Shell dumps with 4 threads by default.
+	threads := int64(4)
+	if t, ok := dumpFlags["threads"].(float64); ok && t >= 1 {
+		threads = int64(t)
+	}
+	dumpFlags["maxRate"] = strconv.FormatInt(max(backupMaxBytesPerSec/threads, 1), 10)
+	data, err := json.Marshal(dumpFlags)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 func (be *MySQLShellBackupEngine) backupPreCheck(location string) error {
 	if mysqlShellBackupLocation == "" {
 		return fmt.Errorf("%w: no backup location set via --mysql-shell-backup-location", ErrMySQLShellPreCheck)
@@ -472,31 +509,6 @@ func (be *MySQLShellBackupEngine) restorePreCheck(ctx context.Context, params Re
 	return shouldDeleteUsers, nil
 }
 
-func (be *MySQLShellBackupEngine) handleSuperReadOnly(ctx context.Context, params RestoreParams) (func(), error) {
-	readonly, err := params.Mysqld.IsSuperReadOnly(ctx)
-	if err != nil {
-		return nil, vterrors.Wrap(err, fmt.Sprintf("checking if mysqld has super_read_only=enable: %v", err))
-	}
-
-	params.Logger.Infof("Is Super Read Only: %v", readonly)
-
-	if readonly {
-		resetFunc, err := params.Mysqld.SetSuperReadOnly(ctx, false)
-		if err != nil {
-			return nil, vterrors.Wrap(err, fmt.Sprintf("unable to disable super-read-only: %v", err))
-		}
-
-		return func() {
-			err := resetFunc()
-			if err != nil {
-				params.Logger.Errorf("Not able to set super_read_only to its original value after restore")
-			}
-		}, nil
-	}
-
-	return func() {}, nil
-}
-
 // releaseReadLock will keep reading the MySQL Shell STDERR waiting until the point it has acquired its lock
 func releaseReadLock(ctx context.Context, reader io.Reader, params BackupParams, wg *sync.WaitGroup, lockAcquired time.Time) {
 	defer wg.Done()
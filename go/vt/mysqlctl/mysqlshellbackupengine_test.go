@@ -197,6 +197,38 @@ func TestShouldDrainForBackupMySQLShell(t *testing.T) {
 	assert.True(t, engine.ShouldDrainForBackup(&tabletmanagerdatapb.BackupRequest{}))
 }
 
+func TestMySQLShellDumpFlagsWithLimits(t *testing.T) {
+	originalDumpFlags, originalMaxBytesPerSec := mysqlShellDumpFlags, backupMaxBytesPerSec
+	defer func() { mysqlShellDumpFlags, backupMaxBytesPerSec = originalDumpFlags, originalMaxBytesPerSec }()
+
+	mysqlShellDumpFlags = `{"threads": 4}`
+	backupMaxBytesPerSec = 0
+	dumpFlags, err := mysqlShellDumpFlagsWithLimits()
+	require.NoError(t, err)
+	assert.Equal(t, mysqlShellDumpFlags, dumpFlags)
+
+	// The limit is split among the threads.
+	backupMaxBytesPerSec = 1000
+	dumpFlags, err = mysqlShellDumpFlagsWithLimits()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"threads": 4, "maxRate": "250"}`, dumpFlags)
+
+	mysqlShellDumpFlags = `{}`
+	dumpFlags, err = mysqlShellDumpFlagsWithLimits()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"maxRate": "250"}`, dumpFlags)
+
+	// An explicit maxRate wins.
+	mysqlShellDumpFlags = `{"threads": 2, "maxRate": "1M"}`
+	dumpFlags, err = mysqlShellDumpFlagsWithLimits()
+	require.NoError(t, err)
+	assert.Equal(t, mysqlShellDumpFlags, dumpFlags)
+
+	mysqlShellDumpFlags = `{"threads": 2`
+	_, err = mysqlShellDumpFlagsWithLimits()
+	require.ErrorIs(t, err, ErrMySQLShellPreCheck)
+}
+
 func TestCleanupMySQL(t *testing.T) {
 	type userRecord struct {
 		user, host string
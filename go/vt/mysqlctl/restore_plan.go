@@ -0,0 +1,254 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// RestorePlanParams describes the restore to plan.
+type RestorePlanParams struct {
+	Logger   logutil.Logger
+	Keyspace string
+	Shard    string
+	// RestoreToPos, if set, plans a point in time recovery that reaches this position.
+	RestoreToPos replication.Position
+	// RestoreToTimestamp, if set, plans a point in time recovery up to this time.
+	RestoreToTimestamp time.Time
+	// AllowedBackupEngines, if set, ignores backups taken with other engines.
+	AllowedBackupEngines []string
+}
+
+// IsIncrementalRecovery returns true if the plan is for a point in time recovery.
+func (p *RestorePlanParams) IsIncrementalRecovery() bool {
+	return !p.RestoreToPos.IsZero() || !p.RestoreToTimestamp.IsZero()
+}
+
+// RestorePlan describes the backups a restore would apply, without restoring anything.
+type RestorePlan struct {
+	// Path is a full backup followed by zero or more incremental backups, in the order
+	// in which they would be restored. It is empty if no backups reach the restore point.
+	Path []*BackupManifest
+	// PathError explains why Path is empty.
+	PathError error
+	// Gaps are the GTID sets missing between consecutive incremental backups that follow
+	// the full backup of Path, or the latest full backup if Path is empty.
+	Gaps []string
+
+	manifestHandleMap *ManifestHandleMap
+}
+
+// Handle returns the handle of a backup of the plan.
+func (p *RestorePlan) Handle(manifest *BackupManifest) backupstorage.BackupHandle {
+	return p.manifestHandleMap.Handle(manifest)
+}
+
+// BackupDuration returns how long the given backup took to take. It returns 0 if the
+// MANIFEST does not say.
+func BackupDuration(manifest *BackupManifest) time.Duration {
+	startTime, err := ParseRFC3339(manifest.BackupTime)
+	if err != nil {
+		return 0
+	}
+	finishedTime, err := ParseRFC3339(manifest.FinishedTime)
+	if err != nil || finishedTime.Before(startTime) {
+		return 0
+	}
+	return finishedTime.Sub(startTime)
+}
+
+// TotalBackupDuration returns the sum of the durations of the backups of the plan.
+func (p *RestorePlan) TotalBackupDuration() (d time.Duration) {
+	for _, manifest := range p.Path {
+		d += BackupDuration(manifest)
+	}
+	return d
+}
+
+// listBackupsToRestore returns the backups a restore of the given shard may use, in the
+// order in which they are listed in the backup storage. For point in time recoveries, this
+// includes the binlog archive. The returned map holds the secondary copies a failed restore
+// can fall back to, by backup name.
+func listBackupsToRestore(ctx context.Context, logger logutil.Logger, bs backupstorage.BackupStorage, keyspace, shard string, incremental bool) ([]backupstorage.BackupHandle, map[string]backupstorage.BackupHandle, error) {
+	backupDir := GetBackupDir(keyspace, shard)
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+	// Backups that are missing or unusable in the backup storage may be restored from
+	// their copy in the secondary backup storage.
+	secondaryBhs, err := listSecondaryBackups(ctx, backupDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	var secondaryFallbacks map[string]backupstorage.BackupHandle
+	if len(secondaryBhs) > 0 {
+		logger.Infof("Restore: found %v backups in secondary backup storage", len(secondaryBhs))
+		bhs, secondaryFallbacks = mergeSecondaryBackups(ctx, bhs, secondaryBhs)
+	}
+	if len(bhs) == 0 || !incremental {
+		return bhs, secondaryFallbacks, nil
+	}
+
+	// A point in time recovery can apply archived binary logs on top of a full backup,
+	// the same way it applies incremental backups.
+	archiveDir := GetBinlogArchiveDir(keyspace, shard)
+	archivedBhs, err := bs.ListBackups(ctx, archiveDir)
+	if err != nil {
+		return nil, nil, vterrors.Wrap(err, "ListBackups failed for binlog archive")
+	}
+	secondaryArchivedBhs, err := listSecondaryBackups(ctx, archiveDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(secondaryArchivedBhs) > 0 {
+		var archiveFallbacks map[string]backupstorage.BackupHandle
+		archivedBhs, archiveFallbacks = mergeSecondaryBackups(ctx, archivedBhs, secondaryArchivedBhs)
+		if secondaryFallbacks == nil {
+			secondaryFallbacks = archiveFallbacks
+		} else {
+			maps.Copy(secondaryFallbacks, archiveFallbacks)
+		}
+	}
+	if len(archivedBhs) > 0 {
+		logger.Infof("Restore: found %v binlog archive entries", len(archivedBhs))
+		bhs = append(bhs, archivedBhs...)
+	}
+	return bhs, secondaryFallbacks, nil
+}
+
+// PlanRestore computes the backups a restore of the given shard would apply, as well as the
+// gaps in its chain of incremental backups. It only reads the backup MANIFESTs, and does not
+// need a running mysqld.
+func PlanRestore(ctx context.Context, params RestorePlanParams) (*RestorePlan, error) {
+	if !params.RestoreToPos.IsZero() && !params.RestoreToTimestamp.IsZero() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "RestoreToPos and RestoreToTimestamp are mutually exclusive")
+	}
+	if params.Logger == nil {
+		params.Logger = logutil.NewMemoryLogger()
+	}
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+
+	bhs, _, err := listBackupsToRestore(ctx, params.Logger, bs, params.Keyspace, params.Shard, params.IsIncrementalRecovery())
+	if err != nil {
+		return nil, err
+	}
+	plan := &RestorePlan{
+		manifestHandleMap: NewManifestHandleMap(),
+	}
+	manifests := make([]*BackupManifest, 0, len(bhs))
+	for _, bh := range bhs {
+		bm, err := GetBackupManifest(ctx, bh)
+		if err != nil {
+			params.Logger.Warningf("Possibly incomplete backup %v in directory %v on BackupStorage: can't read MANIFEST: %v)", bh.Name(), bh.Directory(), err)
+			continue
+		}
+		if len(params.AllowedBackupEngines) > 0 && !slices.Contains(params.AllowedBackupEngines, bm.BackupMethod) {
+			continue
+		}
+		manifests = append(manifests, bm)
+		plan.manifestHandleMap.Map(bm, bh)
+	}
+
+	var fullBackup *BackupManifest
+	for i := len(manifests) - 1; i >= 0; i-- {
+		if !manifests[i].Incremental {
+			fullBackup = manifests[i]
+			break
+		}
+	}
+	switch {
+	case !params.RestoreToPos.IsZero():
+		plan.Path, plan.PathError = FindPITRPath(params.RestoreToPos.GTIDSet, manifests)
+	case !params.RestoreToTimestamp.IsZero():
+		plan.Path, plan.PathError = FindPITRToTimePath(params.RestoreToTimestamp, manifests)
+	case fullBackup != nil:
+		plan.Path = []*BackupManifest{fullBackup}
+	case len(bhs) == 0:
+		plan.PathError = ErrNoBackup
+	default:
+		plan.PathError = ErrNoCompleteBackup
+	}
+	if len(plan.Path) > 0 {
+		fullBackup = plan.Path[0]
+	}
+	if fullBackup != nil && !fullBackup.Position.IsZero() {
+		plan.Gaps = findIncrementalBackupGaps(fullBackup, manifests)
+	}
+	return plan, nil
+}
+
+// findIncrementalBackupGaps walks the incremental backups that follow the given full backup,
+// and returns the GTID sets that none of them covers.
+func findIncrementalBackupGaps(fullBackup *BackupManifest, manifests []*BackupManifest) (gaps []string) {
+	var incrementals []*BackupManifest
+	for _, m := range manifests {
+		if m.Incremental && m.Position.GTIDSet != nil && m.FromPosition.GTIDSet != nil {
+			incrementals = append(incrementals, m)
+		}
+	}
+	covered := fullBackup.Position.GTIDSet
+	purged := fullBackup.PurgedPosition.GTIDSet
+	if purged == nil {
+		purged = covered
+	}
+	for {
+		// Extend the covered set as much as possible.
+		extended := false
+		for _, m := range incrementals {
+			if IsValidIncrementalBakcup(covered, purged, m) {
+				covered = covered.Union(m.Position.GTIDSet)
+				extended = true
+			}
+		}
+		if extended {
+			continue
+		}
+		// No backup follows the covered set. Jump to the backup that starts the earliest
+		// among those that are beyond the covered set.
+		var next *BackupManifest
+		for _, m := range incrementals {
+			if covered.Contains(m.Position.GTIDSet) {
+				continue
+			}
+			if next == nil || next.FromPosition.GTIDSet.Contains(m.FromPosition.GTIDSet) {
+				next = m
+			}
+		}
+		if next == nil {
+			return gaps
+		}
+		if gap, err := replication.Subtract(next.FromPosition.GTIDSet.String(), covered.String()); err == nil && gap != "" {
+			gaps = append(gaps, gap)
+		}
+		covered = covered.Union(next.Position.GTIDSet)
+	}
+}
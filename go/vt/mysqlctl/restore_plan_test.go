@@ -0,0 +1,195 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+const restorePlanTestUUID = "16b1039f-22b6-11ed-b765-0a43f95f28a3"
+
+func restorePlanTestPosition(t *testing.T, gtids string) replication.Position {
+	if gtids == "" {
+		return replication.Position{}
+	}
+	pos, err := replication.DecodePosition("MySQL56/" + restorePlanTestUUID + ":" + gtids)
+	require.NoError(t, err)
+	return pos
+}
+
+func writeTestBackupManifest(t *testing.T, bs backupstorage.BackupStorage, dir string, manifest *BackupManifest) {
+	ctx := context.Background()
+	bh, err := bs.StartBackup(ctx, dir, manifest.BackupName)
+	require.NoError(t, err)
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	wc, err := bh.AddFile(ctx, backupManifestFileName, int64(len(data)))
+	require.NoError(t, err)
+	_, err = wc.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+	require.NoError(t, bh.EndBackup(ctx))
+}
+
+func TestFindIncrementalBackupGaps(t *testing.T) {
+	tt := []struct {
+		name         string
+		full         string
+		incrementals [][2]string
+		expectGaps   []string
+	}{
+		{
+			name: "no incrementals",
+			full: "1-10",
+		},
+		{
+			name:         "contiguous",
+			full:         "1-10",
+			incrementals: [][2]string{{"1-10", "1-20"}, {"1-20", "1-30"}},
+		},
+		{
+			name:         "overlapping",
+			full:         "1-10",
+			incrementals: [][2]string{{"1-5", "1-20"}, {"1-15", "1-30"}},
+		},
+		{
+			name:         "one gap",
+			full:         "1-10",
+			incrementals: [][2]string{{"1-10", "1-20"}, {"1-25", "1-30"}, {"1-30", "1-40"}},
+			expectGaps:   []string{restorePlanTestUUID + ":21-25"},
+		},
+		{
+			name:         "two gaps",
+			full:         "1-10",
+			incrementals: [][2]string{{"1-12", "1-20"}, {"1-25", "1-30"}},
+			expectGaps:   []string{restorePlanTestUUID + ":11-12", restorePlanTestUUID + ":21-25"},
+		},
+		{
+			name:         "incrementals before the full backup",
+			full:         "1-30",
+			incrementals: [][2]string{{"1-10", "1-20"}, {"1-20", "1-30"}},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fullBackup := &BackupManifest{
+				Position:       restorePlanTestPosition(t, tc.full),
+				PurgedPosition: restorePlanTestPosition(t, "1"),
+			}
+			manifests := []*BackupManifest{fullBackup}
+			for _, incremental := range tc.incrementals {
+				manifests = append(manifests, &BackupManifest{
+					Incremental:  true,
+					FromPosition: restorePlanTestPosition(t, incremental[0]),
+					Position:     restorePlanTestPosition(t, incremental[1]),
+				})
+			}
+			assert.Equal(t, tc.expectGaps, findIncrementalBackupGaps(fullBackup, manifests))
+		})
+	}
+}
+
+func TestPlanRestore(t *testing.T) {
+	ctx := context.Background()
+	previousBackupStorageImplementation := backupstorage.BackupStorageImplementation
+	previousFileBackupStorageRoot := filebackupstorage.FileBackupStorageRoot
+	backupstorage.BackupStorageImplementation = "file"
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	defer func() {
+		backupstorage.BackupStorageImplementation = previousBackupStorageImplementation
+		filebackupstorage.FileBackupStorageRoot = previousFileBackupStorageRoot
+	}()
+	bs, err := backupstorage.GetBackupStorage()
+	require.NoError(t, err)
+	dir := GetBackupDir("ks", "0")
+
+	writeTestBackupManifest(t, bs, dir, &BackupManifest{
+		BackupName:   "2025-01-01.000000.zone1-0000000101",
+		BackupMethod: builtinBackupEngineName,
+		Position:     restorePlanTestPosition(t, "1-10"),
+		BackupTime:   "2025-01-01T00:00:00Z",
+		FinishedTime: "2025-01-01T00:10:00Z",
+	})
+	writeTestBackupManifest(t, bs, dir, &BackupManifest{
+		BackupName:   "2025-01-01.010000.zone1-0000000101",
+		BackupMethod: builtinBackupEngineName,
+		Incremental:  true,
+		FromPosition: restorePlanTestPosition(t, "1-10"),
+		Position:     restorePlanTestPosition(t, "1-20"),
+		BackupTime:   "2025-01-01T01:00:00Z",
+		FinishedTime: "2025-01-01T01:01:00Z",
+	})
+	writeTestBackupManifest(t, bs, dir, &BackupManifest{
+		BackupName:   "2025-01-01.020000.zone1-0000000101",
+		BackupMethod: builtinBackupEngineName,
+		Incremental:  true,
+		FromPosition: restorePlanTestPosition(t, "1-25"),
+		Position:     restorePlanTestPosition(t, "1-30"),
+		BackupTime:   "2025-01-01T02:00:00Z",
+		FinishedTime: "2025-01-01T02:01:00Z",
+	})
+
+	t.Run("latest full backup", func(t *testing.T) {
+		plan, err := PlanRestore(ctx, RestorePlanParams{Keyspace: "ks", Shard: "0"})
+		require.NoError(t, err)
+		require.NoError(t, plan.PathError)
+		require.Len(t, plan.Path, 1)
+		assert.Equal(t, "2025-01-01.000000.zone1-0000000101", plan.Handle(plan.Path[0]).Name())
+		assert.Equal(t, 10*time.Minute, plan.TotalBackupDuration())
+		assert.Equal(t, []string{restorePlanTestUUID + ":21-25"}, plan.Gaps)
+	})
+	t.Run("restore to position", func(t *testing.T) {
+		plan, err := PlanRestore(ctx, RestorePlanParams{Keyspace: "ks", Shard: "0", RestoreToPos: restorePlanTestPosition(t, "1-15")})
+		require.NoError(t, err)
+		require.NoError(t, plan.PathError)
+		require.Len(t, plan.Path, 2)
+		assert.Equal(t, "2025-01-01.010000.zone1-0000000101", plan.Handle(plan.Path[1]).Name())
+		assert.Equal(t, 11*time.Minute, plan.TotalBackupDuration())
+	})
+	t.Run("restore to position beyond gap", func(t *testing.T) {
+		plan, err := PlanRestore(ctx, RestorePlanParams{Keyspace: "ks", Shard: "0", RestoreToPos: restorePlanTestPosition(t, "1-28")})
+		require.NoError(t, err)
+		assert.ErrorContains(t, plan.PathError, "no path found")
+		assert.Empty(t, plan.Path)
+		assert.Equal(t, []string{restorePlanTestUUID + ":21-25"}, plan.Gaps)
+	})
+	t.Run("disallowed engine", func(t *testing.T) {
+		plan, err := PlanRestore(ctx, RestorePlanParams{Keyspace: "ks", Shard: "0", AllowedBackupEngines: []string{xtrabackupEngineName}})
+		require.NoError(t, err)
+		assert.ErrorIs(t, plan.PathError, ErrNoCompleteBackup)
+		assert.Empty(t, plan.Gaps)
+	})
+	t.Run("position and timestamp", func(t *testing.T) {
+		_, err := PlanRestore(ctx, RestorePlanParams{
+			Keyspace:           "ks",
+			Shard:              "0",
+			RestoreToPos:       restorePlanTestPosition(t, "1-15"),
+			RestoreToTimestamp: time.Now(),
+		})
+		assert.ErrorContains(t, err, "mutually exclusive")
+	})
+}
@@ -79,6 +79,15 @@ var (
 	// root is a prefix added to all object names.
 	root string
 
+	// secondaryBucket, secondaryRegion and secondaryRoot are the location of
+	// the copies of the backups, when s3 is the secondary backup storage.
+	secondaryBucket string
+	secondaryRegion string
+	secondaryRoot   string
+
+	primaryLocation   = s3Location{bucket: &bucket, region: &region, root: &root, bucketFlag: "s3_backup_storage_bucket"}
+	secondaryLocation = s3Location{bucket: &secondaryBucket, region: &secondaryRegion, root: &secondaryRoot, bucketFlag: "s3-backup-storage-secondary-bucket"}
+
 	// forcePath is used to ensure that the certificate and path used match the endpoint + region
 	forcePath bool
 
@@ -105,6 +114,9 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&endpoint, "s3_backup_aws_endpoint", "", "endpoint of the S3 backend (region must be provided).")
 	fs.StringVar(&bucket, "s3_backup_storage_bucket", "", "S3 bucket to use for backups.")
 	fs.StringVar(&root, "s3_backup_storage_root", "", "root prefix for all backup-related object names.")
+	fs.StringVar(&secondaryBucket, "s3-backup-storage-secondary-bucket", "", "S3 bucket to copy backups to, when s3 is the --backup-storage-secondary-implementation.")
+	fs.StringVar(&secondaryRegion, "s3-backup-storage-secondary-region", "", "AWS region of --s3-backup-storage-secondary-bucket. Defaults to --s3_backup_aws_region.")
+	fs.StringVar(&secondaryRoot, "s3-backup-storage-secondary-root", "", "root prefix for all object names in --s3-backup-storage-secondary-bucket.")
 	fs.BoolVar(&forcePath, "s3_backup_force_path_style", false, "force the s3 path style.")
 	fs.BoolVar(&tlsSkipVerifyCert, "s3_backup_tls_skip_verify_cert", false, "skip the 'certificate is valid' check for SSL connections.")
 	fs.StringVar(&requiredLogLevel, "s3_backup_log_level", "LogOff", "determine the S3 loglevel to use from LogOff, LogDebug, LogDebugWithSigning, LogDebugWithHTTPBody, LogDebugWithRequestRetries, LogDebugWithRequestErrors.")
@@ -199,10 +211,10 @@ func (bh *S3BackupHandle) handleAddFile(ctx context.Context, filename string, pa
 		uploader := manager.NewUploader(bh.client, func(u *manager.Uploader) {
 			u.PartSize = partSizeBytes
 		})
-		object := objName(bh.dir, bh.name, filename)
+		object := bh.bs.location.objName(bh.dir, bh.name, filename)
 		sendStats := bh.bs.params.Stats.Scope(stats.Operation("AWS:Request:Send"))
 		_, err := uploader.Upload(ctx, &s3.PutObjectInput{
-			Bucket:               &bucket,
+			Bucket:               bh.bs.location.bucket,
 			Key:                  &object,
 			Body:                 reader,
 			ServerSideEncryption: bh.bs.s3SSE.awsAlg,
@@ -276,10 +288,10 @@ func (bh *S3BackupHandle) ReadFile(ctx context.Context, filename string) (io.Rea
 	if !bh.readOnly {
 		return nil, fmt.Errorf("ReadFile cannot be called on read-write backup")
 	}
-	object := objName(bh.dir, bh.name, filename)
+	object := bh.bs.location.objName(bh.dir, bh.name, filename)
 	sendStats := bh.bs.params.Stats.Scope(stats.Operation("AWS:Request:Send"))
 	out, err := bh.client.GetObject(ctx, &s3.GetObjectInput{
-		Bucket:               &bucket,
+		Bucket:               bh.bs.location.bucket,
 		Key:                  &object,
 		SSECustomerAlgorithm: bh.bs.s3SSE.customerAlg,
 		SSECustomerKey:       bh.bs.s3SSE.customerKey,
@@ -342,6 +354,34 @@ func (s3ServerSideEncryption *S3ServerSideEncryption) reset() {
 	s3ServerSideEncryption.customerMd5 = nil
 }
 
+// s3Location is where a S3BackupStorage keeps its backups. Its fields point to
+// the flags configuring it, as they are parsed after the storage is registered.
+type s3Location struct {
+	bucket *string
+	region *string
+	root   *string
+	// bucketFlag is the name of the flag that sets bucket, for error messages.
+	bucketFlag string
+}
+
+// awsRegion returns the region of the bucket. It defaults to --s3_backup_aws_region.
+func (l *s3Location) awsRegion() string {
+	if *l.region != "" {
+		return *l.region
+	}
+	return region
+}
+
+// objName returns the name of the object made of the given parts, under the root prefix.
+func (l *s3Location) objName(parts ...string) string {
+	res := ""
+	if *l.root != "" {
+		res += *l.root + delimiter
+	}
+	res += strings.Join(parts, delimiter)
+	return res
+}
+
 // S3BackupStorage implements the backupstorage.BackupStorage interface.
 type S3BackupStorage struct {
 	_client   *s3.Client
@@ -349,21 +389,22 @@ type S3BackupStorage struct {
 	s3SSE     S3ServerSideEncryption
 	params    backupstorage.Params
 	transport *http.Transport
+	location  *s3Location
 }
 
-func newS3BackupStorage() *S3BackupStorage {
+func newS3BackupStorage(location *s3Location) *S3BackupStorage {
 	// This initialises a new transport based off http.DefaultTransport the first time and returns the same
 	// transport on subsequent calls so connections can be reused as part of the same transport.
 	tlsClientConf := &tls.Config{InsecureSkipVerify: tlsSkipVerifyCert}
 	transport := http.DefaultTransport.(*http.Transport).Clone()
 	transport.TLSClientConfig = tlsClientConf
 
-	return &S3BackupStorage{params: backupstorage.NoParams(), transport: transport}
+	return &S3BackupStorage{params: backupstorage.NoParams(), transport: transport, location: location}
 }
 
 // ListBackups is part of the backupstorage.BackupStorage interface.
 func (bs *S3BackupStorage) ListBackups(ctx context.Context, dir string) ([]backupstorage.BackupHandle, error) {
-	log.Infof("ListBackups: [s3] dir: %v, bucket: %v", dir, bucket)
+	log.Infof("ListBackups: [s3] dir: %v, bucket: %v", dir, *bs.location.bucket)
 	c, err := bs.client()
 	if err != nil {
 		return nil, err
@@ -371,14 +412,14 @@ func (bs *S3BackupStorage) ListBackups(ctx context.Context, dir string) ([]backu
 
 	var searchPrefix string
 	if dir == "/" {
-		searchPrefix = objName("")
+		searchPrefix = bs.location.objName("")
 	} else {
-		searchPrefix = objName(dir, "")
+		searchPrefix = bs.location.objName(dir, "")
 	}
 	log.Infof("objName: %s", searchPrefix)
 
 	query := &s3.ListObjectsV2Input{
-		Bucket:    &bucket,
+		Bucket:    bs.location.bucket,
 		Delimiter: &delimiter,
 		Prefix:    &searchPrefix,
 	}
@@ -419,7 +460,7 @@ func (bs *S3BackupStorage) ListBackups(ctx context.Context, dir string) ([]backu
 
 // StartBackup is part of the backupstorage.BackupStorage interface.
 func (bs *S3BackupStorage) StartBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
-	log.Infof("StartBackup: [s3] dir: %v, name: %v, bucket: %v", dir, name, bucket)
+	log.Infof("StartBackup: [s3] dir: %v, name: %v, bucket: %v", dir, name, *bs.location.bucket)
 	c, err := bs.client()
 	if err != nil {
 		return nil, err
@@ -436,16 +477,16 @@ func (bs *S3BackupStorage) StartBackup(ctx context.Context, dir, name string) (b
 
 // RemoveBackup is part of the backupstorage.BackupStorage interface.
 func (bs *S3BackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
-	log.Infof("RemoveBackup: [s3] dir: %v, name: %v, bucket: %v", dir, name, bucket)
+	log.Infof("RemoveBackup: [s3] dir: %v, name: %v, bucket: %v", dir, name, *bs.location.bucket)
 
 	c, err := bs.client()
 	if err != nil {
 		return err
 	}
 
-	path := objName(dir, name)
+	path := bs.location.objName(dir, name)
 	query := &s3.ListObjectsV2Input{
-		Bucket: &bucket,
+		Bucket: bs.location.bucket,
 		Prefix: &path,
 	}
 
@@ -464,7 +505,7 @@ func (bs *S3BackupStorage) RemoveBackup(ctx context.Context, dir, name string) e
 
 		quiet := true // return less in the Delete response
 		out, err := c.DeleteObjects(ctx, &s3.DeleteObjectsInput{
-			Bucket: &bucket,
+			Bucket: bs.location.bucket,
 			Delete: &types.Delete{
 				Objects: objIds,
 				Quiet:   &quiet,
@@ -499,7 +540,7 @@ func (bs *S3BackupStorage) Close() error {
 }
 
 func (bs *S3BackupStorage) WithParams(params backupstorage.Params) backupstorage.BackupStorage {
-	return &S3BackupStorage{params: params, transport: bs.transport}
+	return &S3BackupStorage{params: params, transport: bs.transport, location: bs.location}
 }
 
 var _ backupstorage.BackupStorage = (*S3BackupStorage)(nil)
@@ -522,7 +563,7 @@ func (bs *S3BackupStorage) client() (*s3.Client, error) {
 		httpClient := &http.Client{Transport: bs.transport}
 
 		cfg, err := config.LoadDefaultConfig(context.Background(),
-			config.WithRegion(region),
+			config.WithRegion(bs.location.awsRegion()),
 			config.WithClientLogMode(logLevel),
 			config.WithHTTPClient(httpClient),
 		)
@@ -549,11 +590,11 @@ func (bs *S3BackupStorage) client() (*s3.Client, error) {
 
 		bs._client = s3.NewFromConfig(cfg, options...)
 
-		if len(bucket) == 0 {
-			return nil, fmt.Errorf("--s3_backup_storage_bucket required")
+		if len(*bs.location.bucket) == 0 {
+			return nil, fmt.Errorf("--%s required", bs.location.bucketFlag)
 		}
 
-		if _, err := bs._client.HeadBucket(context.Background(), &s3.HeadBucketInput{Bucket: &bucket}); err != nil {
+		if _, err := bs._client.HeadBucket(context.Background(), &s3.HeadBucketInput{Bucket: bs.location.bucket}); err != nil {
 			return nil, err
 		}
 
@@ -564,17 +605,9 @@ func (bs *S3BackupStorage) client() (*s3.Client, error) {
 	return bs._client, nil
 }
 
-func objName(parts ...string) string {
-	res := ""
-	if root != "" {
-		res += root + delimiter
-	}
-	res += strings.Join(parts, delimiter)
-	return res
-}
-
 func init() {
-	backupstorage.BackupStorageMap["s3"] = newS3BackupStorage()
+	backupstorage.BackupStorageMap["s3"] = newS3BackupStorage(&primaryLocation)
+	backupstorage.SecondaryBackupStorageMap["s3"] = newS3BackupStorage(&secondaryLocation)
 
 	logNameMap = logNameToLogLevel{
 		"LogOff":                     0,
@@ -54,7 +54,7 @@ func InitFlag(cfg FakeConfig) {
 }
 
 func NewFakeS3BackupHandle(ctx context.Context, dir, name string, logger logutil.Logger, stats backupstats.Stats) (*FakeS3BackupHandle, error) {
-	s := newS3BackupStorage()
+	s := newS3BackupStorage(&primaryLocation)
 	bs := s.WithParams(backupstorage.Params{
 		Logger: logger,
 		Stats:  stats,
@@ -71,7 +71,7 @@ func NewFakeS3BackupHandle(ctx context.Context, dir, name string, logger logutil
 }
 
 func NewFakeS3RestoreHandle(ctx context.Context, dir string, logger logutil.Logger, stats backupstats.Stats) (*FakeS3BackupHandle, error) {
-	s := newS3BackupStorage()
+	s := newS3BackupStorage(&primaryLocation)
 	bs := s.WithParams(backupstorage.Params{
 		Logger: logger,
 		Stats:  stats,
@@ -72,7 +72,8 @@ func TestAddFileError(t *testing.T) {
 	bh := &S3BackupHandle{
 		client: &s3FakeClient{err: errors.New("some error")},
 		bs: &S3BackupStorage{
-			params: backupstorage.NoParams(),
+			location: &primaryLocation,
+			params:   backupstorage.NoParams(),
 			s3SSE: S3ServerSideEncryption{
 				customerAlg: new(string),
 				customerKey: new(string),
@@ -105,6 +106,7 @@ func TestAddFileStats(t *testing.T) {
 	bh := &S3BackupHandle{
 		client: &s3FakeClient{delay: delay},
 		bs: &S3BackupStorage{
+			location: &primaryLocation,
 			params: backupstorage.Params{
 				Logger: logutil.NewMemoryLogger(),
 				Stats:  fakeStats,
@@ -154,6 +156,7 @@ func TestAddFileErrorStats(t *testing.T) {
 			err:   errors.New("some error"),
 		},
 		bs: &S3BackupStorage{
+			location: &primaryLocation,
 			params: backupstorage.Params{
 				Logger: logutil.NewMemoryLogger(),
 				Stats:  fakeStats,
@@ -310,7 +313,7 @@ func TestSSECustomerFileBase64Key(t *testing.T) {
 }
 
 func TestNewS3Transport(t *testing.T) {
-	s3 := newS3BackupStorage()
+	s3 := newS3BackupStorage(&primaryLocation)
 
 	// checking some of the values are present in the returned transport and match the http.DefaultTransport.
 	assert.Equal(t, http.DefaultTransport.(*http.Transport).IdleConnTimeout, s3.transport.IdleConnTimeout)
@@ -320,7 +323,7 @@ func TestNewS3Transport(t *testing.T) {
 }
 
 func TestWithParams(t *testing.T) {
-	bases3 := newS3BackupStorage()
+	bases3 := newS3BackupStorage(&primaryLocation)
 	s3 := bases3.WithParams(backupstorage.Params{}).(*S3BackupStorage)
 	// checking some of the values are present in the returned transport and match the http.DefaultTransport.
 	assert.Equal(t, http.DefaultTransport.(*http.Transport).IdleConnTimeout, s3.transport.IdleConnTimeout)
@@ -393,3 +396,27 @@ func TestCalculateUploadPartSize(t *testing.T) {
 		})
 	}
 }
+
+func TestSecondaryLocation(t *testing.T) {
+	originalRegion, originalRoot := region, root
+	originalSecondaryRegion, originalSecondaryRoot := secondaryRegion, secondaryRoot
+	defer func() {
+		region, root = originalRegion, originalRoot
+		secondaryRegion, secondaryRoot = originalSecondaryRegion, originalSecondaryRoot
+	}()
+
+	region, root = "us-east-1", "primary"
+	secondaryRegion, secondaryRoot = "", "secondary"
+
+	primary := backupstorage.BackupStorageMap["s3"].(*S3BackupStorage)
+	secondary := backupstorage.SecondaryBackupStorageMap["s3"].WithParams(backupstorage.NoParams()).(*S3BackupStorage)
+
+	assert.Equal(t, "primary/ks/0/backup", primary.location.objName("ks/0", "backup"))
+	assert.Equal(t, "secondary/ks/0/backup", secondary.location.objName("ks/0", "backup"))
+
+	// The secondary bucket is in the same region unless told otherwise.
+	assert.Equal(t, "us-east-1", secondary.location.awsRegion())
+	secondaryRegion = "eu-west-1"
+	assert.Equal(t, "eu-west-1", secondary.location.awsRegion())
+	assert.Equal(t, "us-east-1", primary.location.awsRegion())
+}
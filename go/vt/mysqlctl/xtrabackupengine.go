@@ -428,8 +428,10 @@ func (be *XtrabackupEngine) backupFiles(
 	}
 	// Add a buffer in front of the raw stdout pipe so io.CopyN() can use the
 	// buffered reader's WriteTo() method instead of allocating a new buffer
-	// every time.
-	backupOutBuf := bufio.NewReaderSize(backupOut, int(blockSize))
+	// every time. Limiting reads of the pipe makes xtrabackup wait on its writes,
+	// which in turn slows down its reads of the data files.
+	var backupOutBuf io.Reader = bufio.NewReaderSize(backupOut, int(blockSize))
+	backupOutBuf = newIOLimiter(params.Throttler, params.Stats).reader(ctx, backupOutBuf)
 	if _, err := copyToStripes(destWriters, backupOutBuf, blockSize); err != nil {
 		return replicationPosition, vterrors.Wrap(err, "cannot copy output from xtrabackup command")
 	}
@@ -486,7 +488,7 @@ func (be *XtrabackupEngine) ExecuteRestore(ctx context.Context, params RestorePa
 	// copy / extract files
 	params.Logger.Infof("Restore: Extracting files from %v", bm.FileName)
 
-	if err := be.restoreFromBackup(ctx, params.Cnf, bh, bm, params.Logger); err != nil {
+	if err := be.restoreFromBackup(ctx, params.Cnf, bh, bm, params.Logger, newIOLimiter(nil, params.Stats)); err != nil {
 		// don't delete the file here because that is how we detect an interrupted restore
 		return nil, err
 	}
@@ -495,7 +497,7 @@ func (be *XtrabackupEngine) ExecuteRestore(ctx context.Context, params RestorePa
 	return &bm.BackupManifest, nil
 }
 
-func (be *XtrabackupEngine) restoreFromBackup(ctx context.Context, cnf *Mycnf, bh backupstorage.BackupHandle, bm xtraBackupManifest, logger logutil.Logger) error {
+func (be *XtrabackupEngine) restoreFromBackup(ctx context.Context, cnf *Mycnf, bh backupstorage.BackupHandle, bm xtraBackupManifest, logger logutil.Logger, limiter *ioLimiter) error {
 	// first download the file into a tmp dir
 	// and extract all the files
 	tempDir := fmt.Sprintf("%v/%v", cnf.TmpDir, time.Now().UTC().Format("xtrabackup-2006-01-02.150405"))
@@ -521,7 +523,7 @@ func (be *XtrabackupEngine) restoreFromBackup(ctx context.Context, cnf *Mycnf, b
 		}()
 	}
 
-	if err := be.extractFiles(ctx, logger, bh, bm, tempDir); err != nil {
+	if err := be.extractFiles(ctx, logger, bh, bm, tempDir, limiter); err != nil {
 		logger.Errorf("error extracting backup files: %v", err)
 		return err
 	}
@@ -597,8 +599,9 @@ func (be *XtrabackupEngine) restoreFromBackup(ctx context.Context, cnf *Mycnf, b
 	return nil
 }
 
-// restoreFile extracts all the files from the backup archive
-func (be *XtrabackupEngine) extractFiles(ctx context.Context, logger logutil.Logger, bh backupstorage.BackupHandle, bm xtraBackupManifest, tempDir string) error {
+// extractFiles extracts all the files from the backup archive. The extracted data is subject
+// to the given limiter, if any.
+func (be *XtrabackupEngine) extractFiles(ctx context.Context, logger logutil.Logger, bh backupstorage.BackupHandle, bm xtraBackupManifest, tempDir string, limiter *ioLimiter) error {
 	// Pull details from the MANIFEST where available, so we can still restore
 	// backups taken with different flags. Some fields were not always present,
 	// so if necessary we default to the flag values.
@@ -672,7 +675,7 @@ func (be *XtrabackupEngine) extractFiles(ctx context.Context, logger logutil.Log
 		}
 	}()
 
-	reader := stripeReader(srcReaders, int64(bm.StripeBlockSize))
+	reader := limiter.reader(ctx, stripeReader(srcReaders, int64(bm.StripeBlockSize)))
 
 	switch streamMode {
 	case streamModeTar:
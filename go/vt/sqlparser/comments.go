@@ -594,6 +594,26 @@ func BuildQueryHints(stmt Statement) (qh QueryHints, err error) {
 	return qh, nil
 }
 
+// BuildQueryHintsFromDirectives builds the query hints set by directives
+// which are not part of a statement, like the ones a client sends as query
+// attributes. Only directives which do not depend on the statement are used.
+func BuildQueryHintsFromDirectives(values map[string]string) (qh QueryHints, err error) {
+	directives := &CommentDirectives{m: make(map[string]string, len(values))}
+	for key, val := range values {
+		directives.m[strings.ToLower(key)] = val
+	}
+
+	qh.Priority, err = getPriority(directives)
+	if err != nil {
+		return qh, err
+	}
+	qh.IgnoreMaxMemoryRows = directives.IsSet(DirectiveIgnoreMaxMemoryRows)
+	qh.Workload = getWorkload(directives)
+	qh.Timeout = getQueryTimeout(directives)
+
+	return qh, nil
+}
+
 // getConsolidator returns the consolidator option.
 func getConsolidator(stmt Statement, directives *CommentDirectives) querypb.ExecuteOptions_Consolidator {
 	if _, isSelect := stmt.(SelectStatement); !isSelect {
@@ -547,6 +547,60 @@ func TestGetPriorityFromStatement(t *testing.T) {
 	}
 }
 
+func TestBuildQueryHintsFromDirectives(t *testing.T) {
+	timeout := 100
+	testCases := []struct {
+		name          string
+		directives    map[string]string
+		expected      QueryHints
+		expectedError error
+	}{
+		{
+			name:       "no directives",
+			directives: nil,
+			expected:   QueryHints{},
+		},
+		{
+			name: "all directives",
+			directives: map[string]string{
+				"workload_name":          "app",
+				"PRIORITY":               "10",
+				"query_timeout_ms":       "100",
+				"ignore_max_memory_rows": "1",
+				"another_directive":      "ignored",
+			},
+			expected: QueryHints{
+				IgnoreMaxMemoryRows: true,
+				Workload:            "app",
+				Priority:            "10",
+				Timeout:             &timeout,
+			},
+		},
+		{
+			name:          "invalid priority",
+			directives:    map[string]string{"priority": "200"},
+			expectedError: ErrInvalidPriority,
+		},
+		{
+			name:       "invalid timeout",
+			directives: map[string]string{"query_timeout_ms": "soon"},
+			expected:   QueryHints{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			qh, err := BuildQueryHintsFromDirectives(testCase.directives)
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, qh)
+		})
+	}
+}
+
 // TestGetMySQLSetVarValue tests the functionality of GetMySQLSetVarValue
 func TestGetMySQLSetVarValue(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,621 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+var _ Conn = (*CacheConn)(nil)
+
+// cacheConnWatchBufferSize is the size of the channels returned by Watch and
+// WatchRecursive, so that a slow caller does not hold up the shared watch.
+const cacheConnWatchBufferSize = 10
+
+var (
+	// topoCache enables the CacheConn for the global and cell connections.
+	topoCache bool
+
+	// topoCacheTTL is how long Get and List results are cached for,
+	// when they are not kept up to date by a watch.
+	topoCacheTTL time.Duration
+)
+
+func init() {
+	for _, cmd := range []string{"vtgate", "vttablet"} {
+		servenv.OnParseFor(cmd, registerTopoCacheFlags)
+	}
+}
+
+func registerTopoCacheFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&topoCache, "topo_cache", topoCache, "Share a single topo server watch between all the identical Watch and WatchRecursive calls of the process, and serve Get calls on watched files from the data of the watch. Writes and locks still go to the topo server.")
+	fs.DurationVar(&topoCacheTTL, "topo_cache_ttl", topoCacheTTL, "How long the results of topo Get and List calls are cached for with --topo_cache, when they are not kept up to date by a watch. Changes made by other processes may be seen this late. 0 disables caching them.")
+}
+
+// cacheConn returns conn wrapped in a CacheConn if --topo_cache is set,
+// and conn itself otherwise.
+func cacheConn(cell string, conn Conn) Conn {
+	if !topoCache {
+		return conn
+	}
+	return NewCacheConn(cell, conn, topoCacheTTL)
+}
+
+// cachedFile is the result of a Get call.
+type cachedFile struct {
+	contents []byte
+	version  Version
+	expires  time.Time
+}
+
+// cachedList is the result of a List call.
+type cachedList struct {
+	kvs     []KVInfo
+	expires time.Time
+}
+
+// watchKey identifies the watches which can be shared.
+type watchKey struct {
+	path      string
+	recursive bool
+}
+
+// sharedWatch is a watch on the topo server, whose changes are sent to
+// all the subscribers watching the same path.
+type sharedWatch struct {
+	key    watchKey
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// ready is closed once the watch is started, or failed to start,
+	// in which case err is set.
+	ready chan struct{}
+	err   error
+
+	// The following fields are protected by CacheConn.mu.
+	// current has the current value of every watched file, once the
+	// watch is started. A non-recursive watch has a single file, with
+	// an empty path.
+	current     map[string]*WatchDataRecursive
+	subscribers map[*watchSubscriber]bool
+}
+
+// watchSubscriber is a Watch or WatchRecursive call served by a
+// sharedWatch. It never blocks the sharedWatch: if the caller falls
+// behind, the changes to the same file are merged, and only the last
+// one is sent.
+type watchSubscriber struct {
+	wake chan struct{}
+
+	mu      sync.Mutex
+	pending []*WatchDataRecursive
+	index   map[string]int
+	final   *WatchDataRecursive
+}
+
+// The CacheConn is a wrapper for a Conn that shares identical watches,
+// and serves Get and List calls from a versioned cache. A file which
+// is watched is always served from the data of the watch. Otherwise,
+// the results of Get and List are cached for the TTL, if it is not 0.
+// Writes and locks go through, and invalidate the cached data they
+// change. A failed write also drops the file from the cache, so that
+// callers retrying on ErrBadVersion read the current version.
+type CacheConn struct {
+	cell string
+	conn Conn
+	ttl  time.Duration
+
+	// mu protects the following fields.
+	mu sync.Mutex
+	// generation is incremented by every write, so results read
+	// before a write are not cached after it.
+	generation uint64
+	files      map[string]*cachedFile
+	lists      map[string]*cachedList
+	watches    map[watchKey]*sharedWatch
+	// lastSweep is the last time the expired results were dropped.
+	lastSweep time.Time
+}
+
+// NewCacheConn returns a CacheConn
+func NewCacheConn(cell string, conn Conn, ttl time.Duration) *CacheConn {
+	return &CacheConn{
+		cell:    cell,
+		conn:    conn,
+		ttl:     ttl,
+		files:   make(map[string]*cachedFile),
+		lists:   make(map[string]*cachedList),
+		watches: make(map[watchKey]*sharedWatch),
+	}
+}
+
+// ListDir is part of the Conn interface
+func (c *CacheConn) ListDir(ctx context.Context, dirPath string, full bool) ([]DirEntry, error) {
+	return c.conn.ListDir(ctx, dirPath, full)
+}
+
+// Create is part of the Conn interface
+func (c *CacheConn) Create(ctx context.Context, filePath string, contents []byte) (Version, error) {
+	generation := c.invalidate(filePath)
+	version, err := c.conn.Create(ctx, filePath, contents)
+	if err == nil {
+		c.written(generation, filePath, contents, version)
+	}
+	return version, err
+}
+
+// Update is part of the Conn interface
+func (c *CacheConn) Update(ctx context.Context, filePath string, contents []byte, version Version) (Version, error) {
+	generation := c.invalidate(filePath)
+	newVersion, err := c.conn.Update(ctx, filePath, contents, version)
+	if err == nil {
+		c.written(generation, filePath, contents, newVersion)
+	}
+	return newVersion, err
+}
+
+// Get is part of the Conn interface
+func (c *CacheConn) Get(ctx context.Context, filePath string) ([]byte, Version, error) {
+	statsKey := []string{"Get", c.cell}
+	c.mu.Lock()
+	if w := c.watches[watchKey{path: filePath}]; w != nil {
+		if wd := w.current[""]; wd != nil {
+			c.mu.Unlock()
+			topoStatsConnCacheHits.Add(statsKey, 1)
+			return wd.Contents, wd.Version, nil
+		}
+	}
+	if f := c.files[filePath]; f != nil && time.Now().Before(f.expires) {
+		c.mu.Unlock()
+		topoStatsConnCacheHits.Add(statsKey, 1)
+		return f.contents, f.version, nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	topoStatsConnCacheMisses.Add(statsKey, 1)
+	contents, version, err := c.conn.Get(ctx, filePath)
+	if err != nil || c.ttl == 0 {
+		return contents, version, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == generation {
+		c.sweep()
+		c.files[filePath] = &cachedFile{
+			contents: contents,
+			version:  version,
+			expires:  time.Now().Add(c.ttl),
+		}
+	}
+	return contents, version, nil
+}
+
+// GetVersion is part of the Conn interface.
+func (c *CacheConn) GetVersion(ctx context.Context, filePath string, version int64) ([]byte, error) {
+	return c.conn.GetVersion(ctx, filePath, version)
+}
+
+// List is part of the Conn interface
+func (c *CacheConn) List(ctx context.Context, filePathPrefix string) ([]KVInfo, error) {
+	statsKey := []string{"List", c.cell}
+	c.mu.Lock()
+	if l := c.lists[filePathPrefix]; l != nil && time.Now().Before(l.expires) {
+		c.mu.Unlock()
+		topoStatsConnCacheHits.Add(statsKey, 1)
+		return l.kvs, nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	topoStatsConnCacheMisses.Add(statsKey, 1)
+	kvs, err := c.conn.List(ctx, filePathPrefix)
+	if err != nil || c.ttl == 0 {
+		return kvs, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == generation {
+		c.sweep()
+		c.lists[filePathPrefix] = &cachedList{
+			kvs:     kvs,
+			expires: time.Now().Add(c.ttl),
+		}
+	}
+	return kvs, nil
+}
+
+// Delete is part of the Conn interface
+func (c *CacheConn) Delete(ctx context.Context, filePath string, version Version) error {
+	c.invalidate(filePath)
+	return c.conn.Delete(ctx, filePath, version)
+}
+
+// Lock is part of the Conn interface
+func (c *CacheConn) Lock(ctx context.Context, dirPath, contents string) (LockDescriptor, error) {
+	return c.conn.Lock(ctx, dirPath, contents)
+}
+
+// LockWithTTL is part of the Conn interface
+func (c *CacheConn) LockWithTTL(ctx context.Context, dirPath, contents string, ttl time.Duration) (LockDescriptor, error) {
+	return c.conn.LockWithTTL(ctx, dirPath, contents, ttl)
+}
+
+// LockName is part of the Conn interface
+func (c *CacheConn) LockName(ctx context.Context, dirPath, contents string) (LockDescriptor, error) {
+	return c.conn.LockName(ctx, dirPath, contents)
+}
+
+// TryLock is part of the Conn interface
+func (c *CacheConn) TryLock(ctx context.Context, dirPath, contents string) (LockDescriptor, error) {
+	return c.conn.TryLock(ctx, dirPath, contents)
+}
+
+// Watch is part of the Conn interface
+func (c *CacheConn) Watch(ctx context.Context, filePath string) (*WatchData, <-chan *WatchData, error) {
+	initial, sub, err := c.subscribe(ctx, watchKey{path: filePath})
+	if err != nil {
+		return nil, nil, err
+	}
+	changes := make(chan *WatchData, cacheConnWatchBufferSize)
+	go c.run(ctx, sub, watchKey{path: filePath}, func(wd *WatchDataRecursive) bool {
+		data := wd.WatchData
+		return sendWatchData(ctx, changes, &data)
+	}, func() {
+		close(changes)
+	})
+	current := initial[0].WatchData
+	return &current, changes, nil
+}
+
+// WatchRecursive is part of the Conn interface
+func (c *CacheConn) WatchRecursive(ctx context.Context, path string) ([]*WatchDataRecursive, <-chan *WatchDataRecursive, error) {
+	initial, sub, err := c.subscribe(ctx, watchKey{path: path, recursive: true})
+	if err != nil {
+		return nil, nil, err
+	}
+	changes := make(chan *WatchDataRecursive, cacheConnWatchBufferSize)
+	go c.run(ctx, sub, watchKey{path: path, recursive: true}, func(wd *WatchDataRecursive) bool {
+		return sendWatchData(ctx, changes, wd)
+	}, func() {
+		close(changes)
+	})
+	return initial, changes, nil
+}
+
+// NewLeaderParticipation is part of the Conn interface
+func (c *CacheConn) NewLeaderParticipation(name, id string) (LeaderParticipation, error) {
+	return c.conn.NewLeaderParticipation(name, id)
+}
+
+// Close is part of the Conn interface
+func (c *CacheConn) Close() {
+	c.mu.Lock()
+	for key, w := range c.watches {
+		delete(c.watches, key)
+		w.cancel()
+		if w.current != nil {
+			topoStatsConnCacheWatches.Add(c.watchStatsKey(key), -1)
+		}
+	}
+	c.mu.Unlock()
+	c.conn.Close()
+}
+
+// invalidate drops the cached data a write to filePath changes, and
+// returns the generation of the write.
+func (c *CacheConn) invalidate(filePath string) uint64 {
+	normalized := strings.TrimPrefix(path.Clean(filePath), "/")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	delete(c.files, filePath)
+	for prefix := range c.lists {
+		if strings.HasPrefix(normalized, strings.TrimPrefix(path.Clean(prefix), "/")) {
+			delete(c.lists, prefix)
+		}
+	}
+	return c.generation
+}
+
+// written caches the contents of a file after a successful write, if
+// nothing else was written since. If the file is watched, Get returns
+// them until the watch sends a newer change.
+func (c *CacheConn) written(generation uint64, filePath string, contents []byte, version Version) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation != generation {
+		return
+	}
+	wd := &WatchDataRecursive{WatchData: WatchData{Contents: contents, Version: version}}
+	if w := c.watches[watchKey{path: filePath}]; w != nil && w.current != nil {
+		w.current[""] = wd
+	}
+	if c.ttl == 0 {
+		return
+	}
+	c.sweep()
+	c.files[filePath] = &cachedFile{
+		contents: contents,
+		version:  version,
+		expires:  time.Now().Add(c.ttl),
+	}
+}
+
+// sweep drops the expired results, at most once per TTL, so the cache
+// doesn't grow with files which are not read any more. It must be
+// called with mu held.
+func (c *CacheConn) sweep() {
+	now := time.Now()
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	c.lastSweep = now
+	for filePath, f := range c.files {
+		if !now.Before(f.expires) {
+			delete(c.files, filePath)
+		}
+	}
+	for prefix, l := range c.lists {
+		if !now.Before(l.expires) {
+			delete(c.lists, prefix)
+		}
+	}
+}
+
+func (c *CacheConn) watchStatsKey(key watchKey) []string {
+	if key.recursive {
+		return []string{"WatchRecursive", c.cell}
+	}
+	return []string{"Watch", c.cell}
+}
+
+// subscribe adds a subscriber to the shared watch of the key, which is
+// started if there is none. It returns the current value of the
+// watched files.
+func (c *CacheConn) subscribe(ctx context.Context, key watchKey) ([]*WatchDataRecursive, *watchSubscriber, error) {
+	statsKey := c.watchStatsKey(key)
+	for {
+		c.mu.Lock()
+		w, ok := c.watches[key]
+		if !ok {
+			w = &sharedWatch{
+				key:         key,
+				ready:       make(chan struct{}),
+				subscribers: make(map[*watchSubscriber]bool),
+			}
+			w.ctx, w.cancel = context.WithCancel(context.Background())
+			c.watches[key] = w
+		}
+		c.mu.Unlock()
+
+		if ok {
+			select {
+			case <-w.ready:
+			case <-ctx.Done():
+				return nil, nil, NewError(Interrupted, key.path)
+			}
+		} else {
+			topoStatsConnCacheMisses.Add(statsKey, 1)
+			c.start(w)
+		}
+		if w.err != nil {
+			return nil, nil, w.err
+		}
+
+		c.mu.Lock()
+		if c.watches[key] != w {
+			// The watch ended before we could subscribe to
+			// it, start a new one.
+			c.mu.Unlock()
+			continue
+		}
+		if ok {
+			topoStatsConnCacheHits.Add(statsKey, 1)
+		}
+		sub := &watchSubscriber{
+			wake:  make(chan struct{}, 1),
+			index: make(map[string]int),
+		}
+		w.subscribers[sub] = true
+		initial := make([]*WatchDataRecursive, 0, len(w.current))
+		for _, wd := range w.current {
+			initial = append(initial, wd)
+		}
+		c.mu.Unlock()
+		return initial, sub, nil
+	}
+}
+
+// start starts the watch on the topo server, and the goroutine sending
+// its changes to the subscribers.
+func (c *CacheConn) start(w *sharedWatch) {
+	defer close(w.ready)
+
+	current := make(map[string]*WatchDataRecursive)
+	var changes <-chan *WatchDataRecursive
+	var err error
+	if w.key.recursive {
+		var initial []*WatchDataRecursive
+		initial, changes, err = c.conn.WatchRecursive(w.ctx, w.key.path)
+		for _, wd := range initial {
+			current[wd.Path] = wd
+		}
+	} else {
+		var wd *WatchData
+		var fileChanges <-chan *WatchData
+		wd, fileChanges, err = c.conn.Watch(w.ctx, w.key.path)
+		if err == nil {
+			current[""] = &WatchDataRecursive{WatchData: *wd}
+			recursiveChanges := make(chan *WatchDataRecursive)
+			go func() {
+				defer close(recursiveChanges)
+				for wd := range fileChanges {
+					recursiveChanges <- &WatchDataRecursive{WatchData: *wd}
+				}
+			}()
+			changes = recursiveChanges
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		w.cancel()
+		w.err = err
+		if c.watches[w.key] == w {
+			delete(c.watches, w.key)
+		}
+		return
+	}
+	w.current = current
+	if c.watches[w.key] == w {
+		topoStatsConnCacheWatches.Add(c.watchStatsKey(w.key), 1)
+	}
+	go c.forward(w, changes)
+}
+
+// forward sends the changes of a shared watch to its subscribers,
+// until the watch ends.
+func (c *CacheConn) forward(w *sharedWatch, changes <-chan *WatchDataRecursive) {
+	for wd := range changes {
+		// The changes of a recursive watch include the deleted
+		// files, with ErrNoNode. Any other error ends the watch.
+		deleted := w.key.recursive && wd.Path != "" && IsErrType(wd.Err, NoNode)
+		final := wd.Err != nil && !deleted
+
+		c.mu.Lock()
+		switch {
+		case final:
+			if c.watches[w.key] == w {
+				delete(c.watches, w.key)
+				topoStatsConnCacheWatches.Add(c.watchStatsKey(w.key), -1)
+			}
+		case deleted:
+			delete(w.current, wd.Path)
+		default:
+			w.current[wd.Path] = wd
+		}
+		for sub := range w.subscribers {
+			sub.push(wd, final)
+		}
+		if final {
+			w.subscribers = make(map[*watchSubscriber]bool)
+		}
+		c.mu.Unlock()
+	}
+}
+
+// unsubscribe removes a subscriber from its shared watch, and stops
+// the watch if it was the last one.
+func (c *CacheConn) unsubscribe(key watchKey, sub *watchSubscriber) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w, ok := c.watches[key]
+	if !ok || !w.subscribers[sub] {
+		return
+	}
+	delete(w.subscribers, sub)
+	if len(w.subscribers) == 0 {
+		delete(c.watches, key)
+		w.cancel()
+		topoStatsConnCacheWatches.Add(c.watchStatsKey(key), -1)
+	}
+}
+
+// run sends the changes of a subscriber to its caller, until the
+// watch ends or ctx is canceled. send returns false when ctx is
+// canceled while the caller is not reading the changes.
+func (c *CacheConn) run(ctx context.Context, sub *watchSubscriber, key watchKey, send func(*WatchDataRecursive) bool, done func()) {
+	defer done()
+	for {
+		select {
+		case <-sub.wake:
+		case <-ctx.Done():
+			// Stop getting changes before telling the caller, so that
+			// the shared watch does not queue changes nobody reads.
+			c.unsubscribe(key, sub)
+			send(&WatchDataRecursive{WatchData: WatchData{Err: NewError(Interrupted, key.path)}})
+			return
+		}
+
+		sub.mu.Lock()
+		pending, final := sub.pending, sub.final
+		sub.pending = nil
+		sub.index = make(map[string]int)
+		sub.mu.Unlock()
+
+		for _, wd := range pending {
+			if !send(wd) {
+				c.unsubscribe(key, sub)
+				return
+			}
+		}
+		if final != nil {
+			send(final)
+			return
+		}
+	}
+}
+
+// sendWatchData sends wd to changes. It gives up when ctx is canceled
+// and changes is full, and returns false then.
+func sendWatchData[T any](ctx context.Context, changes chan<- T, wd T) bool {
+	// Prefer delivering wd when there is room, even if ctx is canceled,
+	// so that the caller gets the final Interrupted error.
+	select {
+	case changes <- wd:
+		return true
+	default:
+	}
+	select {
+	case changes <- wd:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// push queues a change for the subscriber. A change to a file which
+// is already queued replaces it.
+func (sub *watchSubscriber) push(wd *WatchDataRecursive, final bool) {
+	sub.mu.Lock()
+	switch i, ok := sub.index[wd.Path]; {
+	case final:
+		sub.final = wd
+	case ok:
+		sub.pending[i] = wd
+	default:
+		sub.index[wd.Path] = len(sub.pending)
+		sub.pending = append(sub.pending, wd)
+	}
+	sub.mu.Unlock()
+
+	select {
+	case sub.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/test"
+)
+
+// cacheFactory creates memorytopo connections wrapped in a CacheConn.
+type cacheFactory struct {
+	*memorytopo.Factory
+	ttl time.Duration
+}
+
+// Create is part of the topo.Factory interface.
+func (f cacheFactory) Create(cell, serverAddr, root string) (topo.Conn, error) {
+	conn, err := f.Factory.Create(cell, serverAddr, root)
+	if err != nil {
+		return nil, err
+	}
+	return topo.NewCacheConn(cell, conn, f.ttl), nil
+}
+
+func TestCacheConnTopoServerTestSuite(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, ttl := range []time.Duration{0, time.Minute} {
+		t.Run(ttl.String(), func(t *testing.T) {
+			test.TopoServerTestSuite(t, ctx, func() *topo.Server {
+				_, factory := memorytopo.NewServerAndFactory(ctx, test.LocalCellName)
+				ts, err := topo.NewWithFactory(cacheFactory{Factory: factory, ttl: ttl}, "", "")
+				require.NoError(t, err)
+				return ts
+			}, []string{"checkTryLock", "checkShardWithLock"})
+		})
+	}
+}
+
+// watchCountConn counts the watches open on a Conn.
+type watchCountConn struct {
+	topo.Conn
+	watches atomic.Int64
+}
+
+func (c *watchCountConn) count(changes <-chan *topo.WatchData) <-chan *topo.WatchData {
+	c.watches.Add(1)
+	result := make(chan *topo.WatchData)
+	go func() {
+		defer c.watches.Add(-1)
+		defer close(result)
+		for wd := range changes {
+			result <- wd
+		}
+	}()
+	return result
+}
+
+// Watch is part of the topo.Conn interface.
+func (c *watchCountConn) Watch(ctx context.Context, filePath string) (*topo.WatchData, <-chan *topo.WatchData, error) {
+	current, changes, err := c.Conn.Watch(ctx, filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return current, c.count(changes), nil
+}
+
+// WatchRecursive is part of the topo.Conn interface.
+func (c *watchCountConn) WatchRecursive(ctx context.Context, path string) ([]*topo.WatchDataRecursive, <-chan *topo.WatchDataRecursive, error) {
+	c.watches.Add(1)
+	current, changes, err := c.Conn.WatchRecursive(ctx, path)
+	if err != nil {
+		c.watches.Add(-1)
+		return nil, nil, err
+	}
+	result := make(chan *topo.WatchDataRecursive)
+	go func() {
+		defer c.watches.Add(-1)
+		defer close(result)
+		for wd := range changes {
+			result <- wd
+		}
+	}()
+	return current, result, nil
+}
+
+// newCacheConn returns a CacheConn to a memorytopo cell, the Conn it
+// wraps, and the memorytopo Factory.
+func newCacheConn(t *testing.T, ttl time.Duration) (*topo.CacheConn, *watchCountConn, *memorytopo.Factory) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	_, factory := memorytopo.NewServerAndFactory(ctx, "zone1")
+	conn, err := factory.Create("zone1", "", "")
+	require.NoError(t, err)
+	counted := &watchCountConn{Conn: conn}
+	cc := topo.NewCacheConn("zone1", counted, ttl)
+	t.Cleanup(cc.Close)
+	return cc, counted, factory
+}
+
+func TestCacheConnWatch(t *testing.T) {
+	ctx := context.Background()
+	cc, conn, factory := newCacheConn(t, 0)
+	_, err := conn.Create(ctx, "file", []byte("a"))
+	require.NoError(t, err)
+
+	// Both watches share a single watch on the topo server.
+	ctx1, cancel1 := context.WithCancel(ctx)
+	current1, changes1, err := cc.Watch(ctx1, "file")
+	require.NoError(t, err)
+	ctx2, cancel2 := context.WithCancel(ctx)
+	defer cancel2()
+	current2, changes2, err := cc.Watch(ctx2, "file")
+	require.NoError(t, err)
+	require.Equal(t, "a", string(current1.Contents))
+	require.Equal(t, "a", string(current2.Contents))
+	require.EqualValues(t, 1, conn.watches.Load())
+
+	// Both get the changes, and Get is served from the watch.
+	_, err = conn.Update(ctx, "file", []byte("b"), nil)
+	require.NoError(t, err)
+	wd := <-changes1
+	require.Equal(t, "b", string(wd.Contents))
+	wd = <-changes2
+	require.Equal(t, "b", string(wd.Contents))
+	gets := factory.GetCallStats().Counts()["Get"]
+	contents, _, err := cc.Get(ctx, "file")
+	require.NoError(t, err)
+	require.Equal(t, "b", string(contents))
+	require.Equal(t, gets, factory.GetCallStats().Counts()["Get"])
+
+	// Canceling one watch leaves the other one running.
+	cancel1()
+	wd = <-changes1
+	require.True(t, topo.IsErrType(wd.Err, topo.Interrupted))
+	_, ok := <-changes1
+	require.False(t, ok)
+	_, err = conn.Update(ctx, "file", []byte("c"), nil)
+	require.NoError(t, err)
+	wd = <-changes2
+	require.Equal(t, "c", string(wd.Contents))
+	require.EqualValues(t, 1, conn.watches.Load())
+
+	// Canceling the last one stops the watch on the topo server.
+	cancel2()
+	wd = <-changes2
+	require.True(t, topo.IsErrType(wd.Err, topo.Interrupted))
+	require.Eventually(t, func() bool {
+		return conn.watches.Load() == 0
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// Deleting the file ends the watches.
+	_, changes3, err := cc.Watch(ctx, "file")
+	require.NoError(t, err)
+	require.NoError(t, conn.Delete(ctx, "file", nil))
+	wd = <-changes3
+	require.True(t, topo.IsErrType(wd.Err, topo.NoNode))
+	_, ok = <-changes3
+	require.False(t, ok)
+	_, _, err = cc.Watch(ctx, "file")
+	require.True(t, topo.IsErrType(err, topo.NoNode))
+}
+
+func TestCacheConnWatchNotRead(t *testing.T) {
+	ctx := context.Background()
+	cc, conn, _ := newCacheConn(t, 0)
+	_, err := conn.Create(ctx, "file", []byte("0"))
+	require.NoError(t, err)
+
+	// The caller stops reading the changes, and cancels the watch once
+	// the channel is full.
+	watchCtx, cancel := context.WithCancel(ctx)
+	_, changes, err := cc.Watch(watchCtx, "file")
+	require.NoError(t, err)
+	for i := 1; i <= 20; i++ {
+		_, err = conn.Update(ctx, "file", []byte(fmt.Sprint(i)), nil)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return len(changes) == min(i, cap(changes))
+		}, 10*time.Second, time.Millisecond)
+	}
+	cancel()
+
+	// The watch on the topo server stops, and the channel is closed
+	// once the buffered changes are read.
+	require.Eventually(t, func() bool {
+		return conn.watches.Load() == 0
+	}, 10*time.Second, 10*time.Millisecond)
+	var count int
+	for range changes {
+		count++
+	}
+	require.Equal(t, cap(changes), count)
+}
+
+func TestCacheConnWatchRecursive(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cc, conn, _ := newCacheConn(t, 0)
+	_, err := conn.Create(ctx, "dir/a", []byte("a"))
+	require.NoError(t, err)
+
+	_, changes1, err := cc.WatchRecursive(ctx, "dir")
+	require.NoError(t, err)
+	_, err = conn.Create(ctx, "dir/b", []byte("b"))
+	require.NoError(t, err)
+	wd := <-changes1
+	require.Equal(t, "b", string(wd.Contents))
+
+	// A new watch gets the current files, and the deletions.
+	initial, changes2, err := cc.WatchRecursive(ctx, "dir")
+	require.NoError(t, err)
+	require.Len(t, initial, 2)
+	require.EqualValues(t, 1, conn.watches.Load())
+	require.NoError(t, conn.Delete(ctx, "dir/a", nil))
+	for _, changes := range []<-chan *topo.WatchDataRecursive{changes1, changes2} {
+		wd = <-changes
+		require.True(t, topo.IsErrType(wd.Err, topo.NoNode))
+		require.NotEmpty(t, wd.Path)
+	}
+	initial, _, err = cc.WatchRecursive(ctx, "dir")
+	require.NoError(t, err)
+	require.Len(t, initial, 1)
+}
+
+func TestCacheConnGet(t *testing.T) {
+	ctx := context.Background()
+	cc, conn, factory := newCacheConn(t, time.Minute)
+	gets := func() int64 {
+		return factory.GetCallStats().Counts()["Get"]
+	}
+	_, err := conn.Create(ctx, "file", []byte("a"))
+	require.NoError(t, err)
+
+	// The second Get is served from the cache.
+	_, version, err := cc.Get(ctx, "file")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, gets())
+	contents, _, err := cc.Get(ctx, "file")
+	require.NoError(t, err)
+	require.Equal(t, "a", string(contents))
+	require.EqualValues(t, 1, gets())
+
+	// Writes through the cache update it.
+	version, err = cc.Update(ctx, "file", []byte("b"), version)
+	require.NoError(t, err)
+	contents, got, err := cc.Get(ctx, "file")
+	require.NoError(t, err)
+	require.Equal(t, "b", string(contents))
+	require.Equal(t, version, got)
+	require.EqualValues(t, 1, gets())
+
+	// A write by someone else is not seen, until a write with the
+	// cached version fails.
+	_, err = conn.Update(ctx, "file", []byte("c"), nil)
+	require.NoError(t, err)
+	contents, version, err = cc.Get(ctx, "file")
+	require.NoError(t, err)
+	require.Equal(t, "b", string(contents))
+	_, err = cc.Update(ctx, "file", []byte("d"), version)
+	require.True(t, topo.IsErrType(err, topo.BadVersion))
+	contents, _, err = cc.Get(ctx, "file")
+	require.NoError(t, err)
+	require.Equal(t, "c", string(contents))
+	require.EqualValues(t, 2, gets())
+
+	// List results are cached, and dropped by the writes under them.
+	kvs, err := cc.List(ctx, "fi")
+	require.NoError(t, err)
+	require.Len(t, kvs, 1)
+	_, err = cc.Create(ctx, "file2", []byte("e"))
+	require.NoError(t, err)
+	kvs, err = cc.List(ctx, "fi")
+	require.NoError(t, err)
+	require.Len(t, kvs, 2)
+	require.EqualValues(t, 2, factory.GetCallStats().Counts()["List"])
+	_, err = cc.List(ctx, "fi")
+	require.NoError(t, err)
+	require.EqualValues(t, 2, factory.GetCallStats().Counts()["List"])
+
+	// Deleted files are not served from the cache.
+	require.NoError(t, cc.Delete(ctx, "file2", nil))
+	_, _, err = cc.Get(ctx, "file2")
+	require.True(t, topo.IsErrType(err, topo.NoNode))
+}
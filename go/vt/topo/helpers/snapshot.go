@@ -0,0 +1,406 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// SnapshotVersion is the version of the TopoSnapshot format written by
+// ExportSnapshot. It must be increased when the meaning of the existing
+// fields changes.
+const SnapshotVersion = 1
+
+// ExportSnapshot reads the records of the global and cell topo servers
+// into a TopoSnapshot. If keyspaces is not empty, only these keyspaces
+// are exported. If cells is not empty, only the tablets, SrvKeyspaces
+// and SrvVSchemas of these cells are exported.
+//
+// The snapshot has the CellInfos, CellsAliases, Keyspaces, VSchemas,
+// Shards, Tablets, routing rules, SrvKeyspaces and SrvVSchemas. The
+// ShardReplication records are not in it, as they are rebuilt from the
+// tablets on import.
+func ExportSnapshot(ctx context.Context, ts *topo.Server, keyspaces, cells []string) (*vtctldatapb.TopoSnapshot, error) {
+	snapshot := &vtctldatapb.TopoSnapshot{
+		Version:      SnapshotVersion,
+		Time:         protoutil.TimeToProto(time.Now()),
+		CellInfos:    make(map[string]*topodatapb.CellInfo),
+		SrvVschemas:  make(map[string]*vschemapb.SrvVSchema),
+		CellsAliases: make(map[string]*topodatapb.CellsAlias),
+	}
+
+	cellNames, err := ts.GetCellInfoNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetCellInfoNames: %w", err)
+	}
+	for _, cell := range cellNames {
+		ci, err := ts.GetCellInfo(ctx, cell, true /* strongRead */)
+		if err != nil {
+			return nil, fmt.Errorf("GetCellInfo(%v): %w", cell, err)
+		}
+		snapshot.CellInfos[cell] = ci
+	}
+	if len(cells) == 0 {
+		cells = cellNames
+	}
+	if snapshot.CellsAliases, err = ts.GetCellsAliases(ctx, true /* strongRead */); err != nil {
+		return nil, fmt.Errorf("GetCellsAliases: %w", err)
+	}
+
+	if len(keyspaces) == 0 {
+		if keyspaces, err = ts.GetKeyspaces(ctx); err != nil {
+			return nil, fmt.Errorf("GetKeyspaces: %w", err)
+		}
+	}
+	byName := make(map[string]*vtctldatapb.TopoSnapshotKeyspace)
+	for _, keyspace := range keyspaces {
+		ks, err := exportKeyspace(ctx, ts, keyspace, cells)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Keyspaces = append(snapshot.Keyspaces, ks)
+		byName[keyspace] = ks
+	}
+
+	for _, cell := range cells {
+		tablets, err := ts.GetTabletsByCell(ctx, cell, nil)
+		if err != nil {
+			return nil, fmt.Errorf("GetTabletsByCell(%v): %w", cell, err)
+		}
+		for _, ti := range tablets {
+			if ks, ok := byName[ti.Keyspace]; ok {
+				ks.Tablets = append(ks.Tablets, ti.Tablet)
+			}
+		}
+
+		srvVSchema, err := ts.GetSrvVSchema(ctx, cell)
+		switch {
+		case err == nil:
+			snapshot.SrvVschemas[cell] = srvVSchema
+		case topo.IsErrType(err, topo.NoNode):
+			// Nothing to do.
+		default:
+			return nil, fmt.Errorf("GetSrvVSchema(%v): %w", cell, err)
+		}
+	}
+	for _, ks := range snapshot.Keyspaces {
+		sort.Slice(ks.Tablets, func(i, j int) bool {
+			return topoproto.TabletAliasString(ks.Tablets[i].Alias) < topoproto.TabletAliasString(ks.Tablets[j].Alias)
+		})
+	}
+
+	if snapshot.RoutingRules, err = ts.GetRoutingRules(ctx); err != nil {
+		return nil, fmt.Errorf("GetRoutingRules: %w", err)
+	}
+	if snapshot.ShardRoutingRules, err = ts.GetShardRoutingRules(ctx); err != nil {
+		return nil, fmt.Errorf("GetShardRoutingRules: %w", err)
+	}
+	if snapshot.KeyspaceRoutingRules, err = ts.GetKeyspaceRoutingRules(ctx); err != nil {
+		return nil, fmt.Errorf("GetKeyspaceRoutingRules: %w", err)
+	}
+	if snapshot.MirrorRules, err = ts.GetMirrorRules(ctx); err != nil {
+		return nil, fmt.Errorf("GetMirrorRules: %w", err)
+	}
+	return snapshot, nil
+}
+
+// exportKeyspace reads the records of a keyspace, with the SrvKeyspaces
+// of the given cells.
+func exportKeyspace(ctx context.Context, ts *topo.Server, keyspace string, cells []string) (*vtctldatapb.TopoSnapshotKeyspace, error) {
+	ki, err := ts.GetKeyspace(ctx, keyspace)
+	if err != nil {
+		return nil, fmt.Errorf("GetKeyspace(%v): %w", keyspace, err)
+	}
+	ks := &vtctldatapb.TopoSnapshotKeyspace{
+		Name:         keyspace,
+		Keyspace:     ki.Keyspace,
+		Shards:       make(map[string]*topodatapb.Shard),
+		SrvKeyspaces: make(map[string]*topodatapb.SrvKeyspace),
+	}
+
+	ksvs, err := ts.GetVSchema(ctx, keyspace)
+	switch {
+	case err == nil:
+		ks.Vschema = ksvs.Keyspace
+	case topo.IsErrType(err, topo.NoNode):
+		// Nothing to do.
+	default:
+		return nil, fmt.Errorf("GetVSchema(%v): %w", keyspace, err)
+	}
+
+	shards, err := ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return nil, fmt.Errorf("GetShardNames(%v): %w", keyspace, err)
+	}
+	for _, shard := range shards {
+		si, err := ts.GetShard(ctx, keyspace, shard)
+		if err != nil {
+			return nil, fmt.Errorf("GetShard(%v, %v): %w", keyspace, shard, err)
+		}
+		ks.Shards[shard] = si.Shard
+	}
+
+	for _, cell := range cells {
+		srvKeyspace, err := ts.GetSrvKeyspace(ctx, cell, keyspace)
+		switch {
+		case err == nil:
+			ks.SrvKeyspaces[cell] = srvKeyspace
+		case topo.IsErrType(err, topo.NoNode):
+			// Nothing to do.
+		default:
+			return nil, fmt.Errorf("GetSrvKeyspace(%v, %v): %w", cell, keyspace, err)
+		}
+	}
+	return ks, nil
+}
+
+// snapshotRecord is a file of a TopoSnapshot.
+type snapshotRecord struct {
+	cell  string
+	path  string
+	value proto.Message
+	// tablet is set for the Tablet records, whose ShardReplication
+	// record is updated on import.
+	tablet *topodatapb.Tablet
+}
+
+func (r *snapshotRecord) key() string {
+	return r.cell + ":" + r.path
+}
+
+// sortedKeys returns the keys of a map in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// snapshotRecords returns the files of a TopoSnapshot, in the order
+// they can be written in: the CellInfos are first, so the cell topo
+// servers can be reached. If keyspace is set, only the records of this
+// keyspace are returned.
+func snapshotRecords(snapshot *vtctldatapb.TopoSnapshot, keyspace string) []*snapshotRecord {
+	var records []*snapshotRecord
+	add := func(cell, filePath string, value proto.Message) {
+		records = append(records, &snapshotRecord{cell: cell, path: filePath, value: value})
+	}
+	// addRules adds the routing rules, unless there are none: they
+	// are the same as a missing file.
+	addRules := func(filePath string, value proto.Message) {
+		if value != nil && proto.Size(value) > 0 {
+			add(topo.GlobalCell, filePath, value)
+		}
+	}
+
+	if keyspace == "" {
+		for _, cell := range sortedKeys(snapshot.CellInfos) {
+			add(topo.GlobalCell, path.Join(topo.CellsPath, cell, topo.CellInfoFile), snapshot.CellInfos[cell])
+		}
+		for _, alias := range sortedKeys(snapshot.CellsAliases) {
+			add(topo.GlobalCell, path.Join(topo.CellsAliasesPath, alias, topo.CellsAliasFile), snapshot.CellsAliases[alias])
+		}
+	}
+
+	for _, ks := range snapshot.Keyspaces {
+		if keyspace != "" && ks.Name != keyspace {
+			continue
+		}
+		add(topo.GlobalCell, path.Join(topo.KeyspacesPath, ks.Name, topo.KeyspaceFile), ks.Keyspace)
+		if ks.Vschema != nil {
+			add(topo.GlobalCell, path.Join(topo.KeyspacesPath, ks.Name, topo.VSchemaFile), ks.Vschema)
+		}
+		for _, shard := range sortedKeys(ks.Shards) {
+			add(topo.GlobalCell, path.Join(topo.KeyspacesPath, ks.Name, topo.ShardsPath, shard, topo.ShardFile), ks.Shards[shard])
+		}
+		for _, tablet := range ks.Tablets {
+			records = append(records, &snapshotRecord{
+				cell:   tablet.Alias.Cell,
+				path:   path.Join(topo.TabletsPath, topoproto.TabletAliasString(tablet.Alias), topo.TabletFile),
+				value:  tablet,
+				tablet: tablet,
+			})
+		}
+		for _, cell := range sortedKeys(ks.SrvKeyspaces) {
+			add(cell, path.Join(topo.KeyspacesPath, ks.Name, topo.SrvKeyspaceFile), ks.SrvKeyspaces[cell])
+		}
+	}
+
+	if keyspace == "" {
+		addRules(topo.RoutingRulesFile, snapshot.RoutingRules)
+		addRules(topo.ShardRoutingRulesFile, snapshot.ShardRoutingRules)
+		addRules(path.Join(topo.RoutingRulesPath, topo.KeyspaceRoutingRulesPath, topo.CommonRoutingRulesFile), snapshot.KeyspaceRoutingRules)
+		addRules(topo.MirrorRulesFile, snapshot.MirrorRules)
+		for _, cell := range sortedKeys(snapshot.SrvVschemas) {
+			add(cell, topo.SrvVSchemaFile, snapshot.SrvVschemas[cell])
+		}
+	}
+	return records
+}
+
+// DiffSnapshot returns the differences between a TopoSnapshot and the
+// topo servers. The records are compared field by field, so the way
+// they are serialized doesn't matter. If keyspace is set, only the
+// records of this keyspace are compared.
+func DiffSnapshot(ctx context.Context, ts *topo.Server, snapshot *vtctldatapb.TopoSnapshot, keyspace string) ([]*vtctldatapb.TopoDiff, error) {
+	diffs, _, err := diffSnapshot(ctx, ts, snapshot, keyspace)
+	return diffs, err
+}
+
+func diffSnapshot(ctx context.Context, ts *topo.Server, snapshot *vtctldatapb.TopoSnapshot, keyspace string) ([]*vtctldatapb.TopoDiff, map[string]*snapshotRecord, error) {
+	if snapshot.Version < 1 || snapshot.Version > SnapshotVersion {
+		return nil, nil, fmt.Errorf("unsupported topo snapshot version %v, expected 1 to %v", snapshot.Version, SnapshotVersion)
+	}
+
+	// If the keyspace doesn't exist yet, there is nothing to compare
+	// with.
+	exists := true
+	var keyspaces []string
+	if keyspace != "" {
+		existing, err := ts.GetKeyspaces(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("GetKeyspaces: %w", err)
+		}
+		exists = slices.Contains(existing, keyspace)
+		keyspaces = []string{keyspace}
+	}
+	var currentRecords []*snapshotRecord
+	if exists {
+		current, err := ExportSnapshot(ctx, ts, keyspaces, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		currentRecords = snapshotRecords(current, keyspace)
+	}
+	currentByKey := make(map[string]*snapshotRecord, len(currentRecords))
+	for _, record := range currentRecords {
+		currentByKey[record.key()] = record
+	}
+
+	var diffs []*vtctldatapb.TopoDiff
+	changed := make(map[string]*snapshotRecord)
+	seen := make(map[string]bool)
+	for _, record := range snapshotRecords(snapshot, keyspace) {
+		seen[record.key()] = true
+		current, ok := currentByKey[record.key()]
+		switch {
+		case !ok:
+			diffs = append(diffs, &vtctldatapb.TopoDiff{
+				Type: vtctldatapb.TopoDiff_CREATE,
+				Cell: record.cell,
+				Path: record.path,
+			})
+		case !proto.Equal(current.value, record.value):
+			diffs = append(diffs, &vtctldatapb.TopoDiff{
+				Type:   vtctldatapb.TopoDiff_UPDATE,
+				Cell:   record.cell,
+				Path:   record.path,
+				Fields: diffFields("", current.value.ProtoReflect(), record.value.ProtoReflect()),
+			})
+		default:
+			continue
+		}
+		changed[record.key()] = record
+	}
+	for _, record := range currentRecords {
+		if !seen[record.key()] {
+			diffs = append(diffs, &vtctldatapb.TopoDiff{
+				Type: vtctldatapb.TopoDiff_EXTRA,
+				Cell: record.cell,
+				Path: record.path,
+			})
+		}
+	}
+	return diffs, changed, nil
+}
+
+// diffFields returns the paths of the fields which differ between two
+// messages of the same type. It goes down into the message fields which
+// are set in both.
+func diffFields(prefix string, a, b protoreflect.Message) []string {
+	var fields []string
+	fds := a.Descriptor().Fields()
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		name := prefix + string(fd.Name())
+		switch {
+		case a.Has(fd) != b.Has(fd):
+			fields = append(fields, name)
+		case fd.Message() != nil && !fd.IsList() && !fd.IsMap() && a.Has(fd):
+			fields = append(fields, diffFields(name+".", a.Get(fd).Message(), b.Get(fd).Message())...)
+		case !a.Get(fd).Equal(b.Get(fd)):
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// ImportSnapshot writes the records of a TopoSnapshot which differ from
+// the topo servers, and returns the differences. If keyspace is set,
+// only the records of this keyspace are imported. If dryRun is set,
+// nothing is written. The records which are only in the topo servers
+// are not deleted.
+//
+// The records are written as they are, without taking any lock, so
+// nothing else should change the topology during the import.
+func ImportSnapshot(ctx context.Context, ts *topo.Server, snapshot *vtctldatapb.TopoSnapshot, keyspace string, dryRun bool) ([]*vtctldatapb.TopoDiff, error) {
+	diffs, changed, err := diffSnapshot(ctx, ts, snapshot, keyspace)
+	if err != nil || dryRun {
+		return diffs, err
+	}
+
+	for _, record := range snapshotRecords(snapshot, keyspace) {
+		if changed[record.key()] == nil {
+			continue
+		}
+		conn, err := ts.ConnForCell(ctx, record.cell)
+		if err != nil {
+			return nil, fmt.Errorf("ConnForCell(%v): %w", record.cell, err)
+		}
+		data, err := proto.Marshal(record.value)
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal %v in cell %v: %w", record.path, record.cell, err)
+		}
+		if _, err := conn.Update(ctx, record.path, data, nil); err != nil {
+			return nil, fmt.Errorf("cannot write %v in cell %v: %w", record.path, record.cell, err)
+		}
+		if record.tablet != nil {
+			if err := topo.UpdateShardReplicationRecord(ctx, ts, record.tablet.Keyspace, record.tablet.Shard, record.tablet.Alias); err != nil {
+				return nil, fmt.Errorf("UpdateShardReplicationRecord(%v): %w", topoproto.TabletAliasString(record.tablet.Alias), err)
+			}
+		}
+	}
+	return diffs, nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	fromTS, toTS := createSetup(ctx, t)
+
+	snapshot, err := ExportSnapshot(ctx, fromTS, nil, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, SnapshotVersion, snapshot.Version)
+	require.Len(t, snapshot.Keyspaces, 1)
+	assert.Len(t, snapshot.Keyspaces[0].Tablets, 2)
+	assert.Len(t, snapshot.Keyspaces[0].Shards, 1)
+
+	// A dry run only reports what would be created.
+	diffs, err := ImportSnapshot(ctx, toTS, snapshot, "", true /* dryRun */)
+	require.NoError(t, err)
+	created := make(map[string]bool)
+	for _, diff := range diffs {
+		assert.Equal(t, vtctldatapb.TopoDiff_CREATE, diff.Type, "%v", diff)
+		created[diff.Cell+":"+diff.Path] = true
+	}
+	assert.Equal(t, map[string]bool{
+		"global:keyspaces/test_keyspace/Keyspace":       true,
+		"global:keyspaces/test_keyspace/shards/0/Shard": true,
+		"test_cell:tablets/test_cell-0000000123/Tablet": true,
+		"test_cell:tablets/test_cell-0000000234/Tablet": true,
+		"global:RoutingRules":                           true,
+	}, created)
+	keyspaces, err := toTS.GetKeyspaces(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, keyspaces)
+
+	// The import writes the records, and rebuilds the ShardReplication.
+	_, err = ImportSnapshot(ctx, toTS, snapshot, "", false /* dryRun */)
+	require.NoError(t, err)
+	diffs, err = DiffSnapshot(ctx, toTS, snapshot, "")
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+	sri, err := toTS.GetShardReplication(ctx, "test_cell", "test_keyspace", "0")
+	require.NoError(t, err)
+	assert.Len(t, sri.Nodes, 2)
+
+	// The changed fields are reported, and the extra records are kept.
+	_, err = toTS.UpdateTabletFields(ctx, &topodatapb.TabletAlias{Cell: "test_cell", Uid: 234}, func(tablet *topodatapb.Tablet) error {
+		tablet.Hostname = "otherhost"
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, toTS.CreateKeyspace(ctx, "other_keyspace", &topodatapb.Keyspace{}))
+	diffs, err = ImportSnapshot(ctx, toTS, snapshot, "", false /* dryRun */)
+	require.NoError(t, err)
+	require.Len(t, diffs, 2)
+	assert.Equal(t, vtctldatapb.TopoDiff_UPDATE, diffs[0].Type)
+	assert.Equal(t, "tablets/test_cell-0000000234/Tablet", diffs[0].Path)
+	assert.Equal(t, []string{"hostname"}, diffs[0].Fields)
+	assert.Equal(t, vtctldatapb.TopoDiff_EXTRA, diffs[1].Type)
+	assert.Equal(t, "keyspaces/other_keyspace/Keyspace", diffs[1].Path)
+	tablet, err := toTS.GetTablet(ctx, &topodatapb.TabletAlias{Cell: "test_cell", Uid: 234})
+	require.NoError(t, err)
+	assert.Equal(t, "replicahost", tablet.Hostname)
+	_, err = toTS.GetKeyspace(ctx, "other_keyspace")
+	require.NoError(t, err)
+
+	// Only the records of the keyspace are compared.
+	diffs, err = DiffSnapshot(ctx, toTS, snapshot, "other_keyspace")
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+	assert.Equal(t, vtctldatapb.TopoDiff_EXTRA, diffs[0].Type)
+	assert.Equal(t, topo.GlobalCell, diffs[0].Cell)
+
+	snapshot.Version = SnapshotVersion + 1
+	_, err = DiffSnapshot(ctx, toTS, snapshot, "")
+	assert.ErrorContains(t, err, "unsupported topo snapshot version")
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stopo
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// Path components
+	locksPath     = "locks"
+	electionsPath = "elections"
+
+	// nodeKind is the kind of the custom resources storing the files.
+	nodeKind = "VitessTopoNode"
+
+	// rootLabel is the label holding the hash of the root of the
+	// server that owns a node, so that the nodes of a server can be
+	// listed and watched without seeing the ones of other roots.
+	rootLabel = "topo.vitess.io/root"
+
+	// contentsAnnotation is the annotation holding the contents of a
+	// lock Lease, which is the leader id for elections.
+	contentsAnnotation = "topo.vitess.io/contents"
+	// pathAnnotation is the annotation holding the path of a lock
+	// Lease, as its name is a hash of it.
+	pathAnnotation = "topo.vitess.io/path"
+)
+
+// nodesResource is the resource of the VitessTopoNode custom resources.
+var nodesResource = schema.GroupVersionResource{
+	Group:    "topo.vitess.io",
+	Version:  "v1beta1",
+	Resource: "vitesstoponodes",
+}
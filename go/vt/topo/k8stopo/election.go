@@ -0,0 +1,233 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stopo
+
+import (
+	"context"
+	"path"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// NewLeaderParticipation is part of the topo.Server interface
+func (s *Server) NewLeaderParticipation(name, id string) (topo.LeaderParticipation, error) {
+	return &kubernetesLeaderParticipation{
+		s:    s,
+		name: name,
+		id:   id,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}, nil
+}
+
+// kubernetesLeaderParticipation implements topo.LeaderParticipation.
+//
+// We use a Lease (for the global election path, with the name), the
+// holder of which is the leader. Its id is stored in an annotation of the
+// Lease.
+type kubernetesLeaderParticipation struct {
+	// s is our parent Kubernetes topo Server
+	s *Server
+
+	// name is the name of this LeaderParticipation
+	name string
+
+	// id is the process's current id.
+	id string
+
+	// stop is a channel closed when Stop is called.
+	stop chan struct{}
+
+	// done is a channel closed when we're done processing the Stop
+	done chan struct{}
+}
+
+// WaitForLeadership is part of the topo.LeaderParticipation interface.
+func (mp *kubernetesLeaderParticipation) WaitForLeadership() (context.Context, error) {
+	// If Stop was already called, mp.done is closed, so we are interrupted.
+	select {
+	case <-mp.done:
+		return nil, topo.NewError(topo.Interrupted, "Leadership")
+	default:
+	}
+
+	electionPath := path.Join(electionsPath, mp.name)
+	var ld topo.LockDescriptor
+
+	// We use a cancelable context here. If stop is closed,
+	// we just cancel that context.
+	lockCtx, lockCancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-mp.s.running:
+			return
+		case <-mp.stop:
+		}
+		if ld != nil {
+			if err := ld.Unlock(context.Background()); err != nil {
+				log.Errorf("failed to unlock electionPath %v: %v", electionPath, err)
+			}
+		}
+		lockCancel()
+		close(mp.done)
+	}()
+
+	// Try to get the primaryship, by getting a lock.
+	var err error
+	ld, err = mp.s.lock(lockCtx, electionPath, mp.id, leaseTTL)
+	if err != nil {
+		// It can be that we were interrupted.
+		return nil, err
+	}
+
+	// We got the lock. Return the lockContext. If Stop() is called,
+	// it will cancel the lockCtx, and cancel the returned context.
+	// We also cancel it if we lose the Lease, as we're not the leader
+	// anymore then.
+	go func() {
+		select {
+		case <-ld.(*kubernetesLockDescriptor).lost:
+			lockCancel()
+		case <-lockCtx.Done():
+		}
+	}()
+	return lockCtx, nil
+}
+
+// Stop is part of the topo.LeaderParticipation interface
+func (mp *kubernetesLeaderParticipation) Stop() {
+	close(mp.stop)
+	<-mp.done
+}
+
+// electionPath returns the path of the Lease of the election.
+func (mp *kubernetesLeaderParticipation) electionPath() string {
+	return path.Join(mp.s.root, electionsPath, mp.name, locksPath)
+}
+
+// leaderID returns the id of the leader holding the given Lease, or an
+// empty string if it's not held.
+func leaderID(lease *coordinationv1.Lease) string {
+	if !leaseHeld(lease, time.Now()) {
+		return ""
+	}
+	return lease.Annotations[contentsAnnotation]
+}
+
+// GetCurrentLeaderID is part of the topo.LeaderParticipation interface
+func (mp *kubernetesLeaderParticipation) GetCurrentLeaderID(ctx context.Context) (string, error) {
+	electionPath := mp.electionPath()
+
+	lease, err := mp.s.leases.Get(ctx, objectName(electionPath), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		// No Lease, means nobody is the primary.
+		return "", nil
+	}
+	if err != nil {
+		return "", convertError(err, electionPath)
+	}
+	return leaderID(lease), nil
+}
+
+// WaitForNewLeader is part of the topo.LeaderParticipation interface
+func (mp *kubernetesLeaderParticipation) WaitForNewLeader(ctx context.Context) (<-chan string, error) {
+	electionPath := mp.electionPath()
+
+	notifications := make(chan string, 8)
+	ctx, cancel := context.WithCancel(ctx)
+
+	// Get the current leader
+	var leader, resourceVersion string
+	lease, err := mp.s.leases.Get(ctx, objectName(electionPath), metav1.GetOptions{})
+	switch {
+	case err == nil:
+		leader = leaderID(lease)
+		resourceVersion = lease.ResourceVersion
+		if leader != "" {
+			notifications <- leader
+		}
+	case !apierrors.IsNotFound(err):
+		cancel()
+		return nil, convertError(err, electionPath)
+	}
+
+	// Create the Watcher. We start watching from the Lease we got.
+	watcher, err := mp.s.watchLease(ctx, electionPath, resourceVersion)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go func() {
+		defer cancel()
+		defer close(notifications)
+		for {
+			select {
+			case <-mp.s.running:
+				watcher.Stop()
+				return
+			case <-mp.done:
+				watcher.Stop()
+				return
+			case <-ctx.Done():
+				watcher.Stop()
+				return
+			case event, ok := <-watcher.ResultChan():
+				if !ok {
+					// The API server ends watches after a while,
+					// so we start a new one where we stopped.
+					watcher, err = mp.s.watchLease(ctx, electionPath, resourceVersion)
+					if err != nil {
+						return
+					}
+					continue
+				}
+				lease, ok := event.Object.(*coordinationv1.Lease)
+				if !ok || event.Type == watch.Error {
+					// We start over from the current Lease.
+					watcher.Stop()
+					if watcher, err = mp.s.watchLease(ctx, electionPath, ""); err != nil {
+						return
+					}
+					continue
+				}
+				resourceVersion = lease.ResourceVersion
+				if event.Type == watch.Deleted {
+					leader = ""
+					continue
+				}
+				newLeader := leaderID(lease)
+				if newLeader == "" || newLeader == leader {
+					// The Leases are renewed regularly, we only
+					// notify about new leaders.
+					continue
+				}
+				leader = newLeader
+				notifications <- leader
+			}
+		}
+	}()
+
+	return notifications, nil
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stopo
+
+import (
+	"context"
+	"encoding/base64"
+	"path"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// newNode returns the VitessTopoNode storing the given file.
+func (s *Server) newNode(nodePath string, contents []byte) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": nodesResource.GroupVersion().String(),
+			"kind":       nodeKind,
+			"metadata": map[string]any{
+				"name": objectName(nodePath),
+				"labels": map[string]any{
+					rootLabel: s.rootHash,
+				},
+			},
+			"spec": map[string]any{
+				"key":   nodePath,
+				"value": base64.StdEncoding.EncodeToString(contents),
+			},
+		},
+	}
+}
+
+// nodeKey returns the path of the file stored in a VitessTopoNode.
+func nodeKey(node *unstructured.Unstructured) string {
+	key, _, _ := unstructured.NestedString(node.Object, "spec", "key")
+	return key
+}
+
+// nodeContents returns the contents of the file stored in a
+// VitessTopoNode.
+func nodeContents(node *unstructured.Unstructured) ([]byte, error) {
+	value, _, err := unstructured.NestedString(node.Object, "spec", "value")
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(value)
+}
+
+// listNodes returns the VitessTopoNodes of this server whose paths start
+// with the given prefix, sorted by path. Kubernetes can only select
+// objects on exact label values, so we get all of the nodes of the root
+// and filter them here.
+func (s *Server) listNodes(ctx context.Context, nodePathPrefix string) ([]unstructured.Unstructured, string, error) {
+	list, err := s.nodes.List(ctx, metav1.ListOptions{
+		LabelSelector: rootLabel + "=" + s.rootHash,
+	})
+	if err != nil {
+		return nil, "", convertError(err, nodePathPrefix)
+	}
+	var nodes []unstructured.Unstructured
+	for _, node := range list.Items {
+		if strings.HasPrefix(nodeKey(&node), nodePathPrefix) {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodeKey(&nodes[i]) < nodeKey(&nodes[j])
+	})
+	return nodes, list.GetResourceVersion(), nil
+}
+
+// Create is part of the topo.Conn interface.
+func (s *Server) Create(ctx context.Context, filePath string, contents []byte) (topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	node, err := s.nodes.Create(ctx, s.newNode(nodePath, contents), metav1.CreateOptions{})
+	if err != nil {
+		return nil, convertError(err, nodePath)
+	}
+	return KubernetesVersion(node.GetResourceVersion()), nil
+}
+
+// Update is part of the topo.Conn interface.
+func (s *Server) Update(ctx context.Context, filePath string, contents []byte, version topo.Version) (topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	if version != nil {
+		// The update only succeeds if the resourceVersion of the
+		// node is still the one we expect.
+		node := s.newNode(nodePath, contents)
+		node.SetResourceVersion(string(version.(KubernetesVersion)))
+		node, err := s.nodes.Update(ctx, node, metav1.UpdateOptions{})
+		if err != nil {
+			return nil, convertError(err, nodePath)
+		}
+		return KubernetesVersion(node.GetResourceVersion()), nil
+	}
+
+	// No version specified. Custom resources can't be updated without
+	// a resourceVersion, so we use the current one, and retry if the
+	// node changed in between.
+	for {
+		current, err := s.nodes.Get(ctx, objectName(nodePath), metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			node, err := s.nodes.Create(ctx, s.newNode(nodePath, contents), metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				continue
+			}
+			if err != nil {
+				return nil, convertError(err, nodePath)
+			}
+			return KubernetesVersion(node.GetResourceVersion()), nil
+		case err != nil:
+			return nil, convertError(err, nodePath)
+		}
+
+		node := s.newNode(nodePath, contents)
+		node.SetResourceVersion(current.GetResourceVersion())
+		node, err = s.nodes.Update(ctx, node, metav1.UpdateOptions{})
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, convertError(err, nodePath)
+		}
+		return KubernetesVersion(node.GetResourceVersion()), nil
+	}
+}
+
+// Get is part of the topo.Conn interface.
+func (s *Server) Get(ctx context.Context, filePath string) ([]byte, topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	node, err := s.nodes.Get(ctx, objectName(nodePath), metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, convertError(err, nodePath)
+	}
+	contents, err := nodeContents(node)
+	if err != nil {
+		return nil, nil, err
+	}
+	return contents, KubernetesVersion(node.GetResourceVersion()), nil
+}
+
+// GetVersion is part of the topo.Conn interface.
+func (s *Server) GetVersion(ctx context.Context, filePath string, version int64) ([]byte, error) {
+	// The API server doesn't keep the history of the objects.
+	return nil, topo.NewError(topo.NoImplementation, "GetVersion not supported in Kubernetes topo")
+}
+
+// List is part of the topo.Conn interface.
+func (s *Server) List(ctx context.Context, filePathPrefix string) ([]topo.KVInfo, error) {
+	nodePathPrefix := path.Join(s.root, filePathPrefix)
+
+	nodes, _, err := s.listNodes(ctx, nodePathPrefix)
+	if err != nil {
+		return []topo.KVInfo{}, err
+	}
+	if len(nodes) == 0 {
+		return []topo.KVInfo{}, topo.NewError(topo.NoNode, nodePathPrefix)
+	}
+	results := make([]topo.KVInfo, len(nodes))
+	for n := range nodes {
+		contents, err := nodeContents(&nodes[n])
+		if err != nil {
+			return []topo.KVInfo{}, err
+		}
+		results[n].Key = []byte(nodeKey(&nodes[n]))
+		results[n].Value = contents
+		results[n].Version = KubernetesVersion(nodes[n].GetResourceVersion())
+	}
+	return results, nil
+}
+
+// Delete is part of the topo.Conn interface.
+func (s *Server) Delete(ctx context.Context, filePath string, version topo.Version) error {
+	nodePath := path.Join(s.root, filePath)
+
+	var opts metav1.DeleteOptions
+	if version != nil {
+		// The deletion only succeeds if the resourceVersion of the
+		// node is still the one we expect.
+		opts.Preconditions = metav1.NewRVDeletionPrecondition(string(version.(KubernetesVersion))).Preconditions
+	}
+	return convertError(s.nodes.Delete(ctx, objectName(nodePath), opts), nodePath)
+}
@@ -0,0 +1,415 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stopo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/pflag"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"vitess.io/vitess/go/ptr"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+var (
+	leaseTTL = 30 // This is the default used for all non-named locks
+)
+
+func init() {
+	servenv.RegisterFlagsForTopoBinaries(registerLockFlags)
+}
+
+func registerLockFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&leaseTTL, "topo_k8s_lease_ttl", leaseTTL, "Lease TTL in seconds for locks and leader election. The client renews the Leases while it holds them.")
+}
+
+// errLockLost is returned when a Lease was taken over by another client,
+// because we failed to renew it in time.
+var errLockLost = errors.New("lost the lock Lease to another holder")
+
+// kubernetesLockDescriptor implements topo.LockDescriptor.
+type kubernetesLockDescriptor struct {
+	s        *Server
+	nodePath string
+	holder   string
+
+	// stop is closed to stop renewing the Lease, and done is closed
+	// when we stopped renewing it.
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+	// lost is closed if the Lease was taken over by another client.
+	lost chan struct{}
+
+	// mu protects lease.
+	mu sync.Mutex
+	// lease is the last version of the Lease that we wrote.
+	lease *coordinationv1.Lease
+}
+
+// leaseHeld returns true if the Lease has a holder which renewed it
+// recently enough.
+func leaseHeld(lease *coordinationv1.Lease, now time.Time) bool {
+	spec := lease.Spec
+	if spec.HolderIdentity == nil || *spec.HolderIdentity == "" || spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+		return false
+	}
+	return spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second).After(now)
+}
+
+// leaseExpiry returns how long until the Lease expires if it isn't
+// renewed.
+func leaseExpiry(lease *coordinationv1.Lease, now time.Time) time.Duration {
+	spec := lease.Spec
+	if spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+		return 0
+	}
+	return spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second).Sub(now)
+}
+
+// TryLock is part of the topo.Conn interface.
+func (s *Server) TryLock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	// We list the directory first to make sure it exists.
+	if _, err := s.ListDir(ctx, dirPath, false /*full*/); err != nil {
+		return nil, err
+	}
+
+	// If the Lease is held, then we can assume that someone else
+	// already has a lock. Throw error in this case.
+	nodePath := path.Join(s.root, dirPath, locksPath)
+	lease, err := s.leases.Get(ctx, objectName(nodePath), metav1.GetOptions{})
+	switch {
+	case err == nil:
+		if leaseHeld(lease, time.Now()) {
+			return nil, topo.NewError(topo.NodeExists, fmt.Sprintf("lock already exists at path %s", dirPath))
+		}
+	case !apierrors.IsNotFound(err):
+		return nil, convertError(err, nodePath)
+	}
+
+	// everything is good let's acquire the lock.
+	return s.lock(ctx, dirPath, contents, leaseTTL)
+}
+
+// Lock is part of the topo.Conn interface.
+func (s *Server) Lock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	// We list the directory first to make sure it exists.
+	if _, err := s.ListDir(ctx, dirPath, false /*full*/); err != nil {
+		return nil, err
+	}
+
+	return s.lock(ctx, dirPath, contents, leaseTTL)
+}
+
+// LockWithTTL is part of the topo.Conn interface.
+func (s *Server) LockWithTTL(ctx context.Context, dirPath, contents string, ttl time.Duration) (topo.LockDescriptor, error) {
+	// We list the directory first to make sure it exists.
+	if _, err := s.ListDir(ctx, dirPath, false /*full*/); err != nil {
+		return nil, err
+	}
+
+	return s.lock(ctx, dirPath, contents, int(ttl.Seconds()))
+}
+
+// LockName is part of the topo.Conn interface.
+func (s *Server) LockName(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	return s.lock(ctx, dirPath, contents, int(topo.NamedLockTTL.Seconds()))
+}
+
+// lock is used by both Lock() and primary election.
+// It blocks until it holds the Lease of the given path, and then keeps
+// renewing it until it is unlocked.
+func (s *Server) lock(ctx context.Context, nodePath, contents string, ttl int) (topo.LockDescriptor, error) {
+	nodePath = path.Join(s.root, nodePath, locksPath)
+	if ttl < 1 {
+		ttl = 1
+	}
+	holder := fmt.Sprintf("%s-%s", s.identity, uuid.NewString())
+
+	for {
+		lease, current, err := s.acquireLease(ctx, nodePath, holder, contents, ttl)
+		if err != nil {
+			return nil, err
+		}
+		if lease != nil {
+			// We're it!
+			ld := &kubernetesLockDescriptor{
+				s:        s,
+				nodePath: nodePath,
+				holder:   holder,
+				stop:     make(chan struct{}),
+				done:     make(chan struct{}),
+				lost:     make(chan struct{}),
+				lease:    lease,
+			}
+			go ld.renew(time.Duration(ttl) * time.Second / 3)
+			return ld, nil
+		}
+		if current != nil {
+			// Wait until the holder releases the Lease, or it
+			// expires.
+			if err := s.waitForLeaseChange(ctx, nodePath, current); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// acquireLease tries to acquire the Lease of the given path once. It
+// returns the Lease if we acquired it, or the current Lease if it's held
+// by someone else. Both are nil if the Lease changed while we tried to
+// acquire it, in which case we should try again.
+func (s *Server) acquireLease(ctx context.Context, nodePath, holder, contents string, ttl int) (*coordinationv1.Lease, *coordinationv1.Lease, error) {
+	now := metav1.NowMicro()
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: objectName(nodePath),
+			Labels: map[string]string{
+				rootLabel: s.rootHash,
+			},
+			Annotations: map[string]string{
+				contentsAnnotation: contents,
+				pathAnnotation:     nodePath,
+			},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holder,
+			LeaseDurationSeconds: ptr.Of(int32(ttl)),
+			AcquireTime:          &now,
+			RenewTime:            &now,
+		},
+	}
+
+	created, err := s.leases.Create(ctx, lease, metav1.CreateOptions{})
+	if err == nil {
+		return created, nil, nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		if ctx.Err() != nil {
+			// Our context was canceled as we were sending a
+			// creation request. We don't know if it succeeded or
+			// not. In any case, let's try to delete the Lease, so
+			// we don't leave it behind for ttl seconds.
+			releaseCtx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+			s.releaseLease(releaseCtx, nodePath, holder)
+			cancel()
+		}
+		return nil, nil, convertError(err, nodePath)
+	}
+
+	current, err := s.leases.Get(ctx, lease.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, convertError(err, nodePath)
+	}
+	if leaseHeld(current, time.Now()) {
+		return nil, current, nil
+	}
+
+	// The Lease was released or it expired, take it over. The
+	// resourceVersion makes sure that only one client does.
+	lease.ResourceVersion = current.ResourceVersion
+	transitions := int32(0)
+	if current.Spec.LeaseTransitions != nil {
+		transitions = *current.Spec.LeaseTransitions
+	}
+	lease.Spec.LeaseTransitions = ptr.Of(transitions + 1)
+	updated, err := s.leases.Update(ctx, lease, metav1.UpdateOptions{})
+	if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, convertError(err, nodePath)
+	}
+	return updated, nil, nil
+}
+
+// waitForLeaseChange waits until the given Lease changes, or until it
+// expires.
+func (s *Server) waitForLeaseChange(ctx context.Context, nodePath string, current *coordinationv1.Lease) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	watcher, err := s.watchLease(ctx, nodePath, current.ResourceVersion)
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	expiry := time.NewTimer(leaseExpiry(current, time.Now()))
+	defer expiry.Stop()
+
+	select {
+	case <-ctx.Done():
+		return convertError(ctx.Err(), nodePath)
+	case <-s.running:
+		return topo.NewError(topo.Interrupted, nodePath)
+	case <-watcher.ResultChan():
+		// Either the Lease changed, or the watch stopped. In both
+		// cases, we try to acquire it again.
+	case <-expiry.C:
+	}
+	return nil
+}
+
+// watchLease watches the Lease of the given path, from the given
+// resourceVersion.
+func (s *Server) watchLease(ctx context.Context, nodePath, resourceVersion string) (watch.Interface, error) {
+	watcher, err := s.leases.Watch(ctx, metav1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", objectName(nodePath)).String(),
+		ResourceVersion: resourceVersion,
+	})
+	if err != nil {
+		return nil, convertError(err, nodePath)
+	}
+	return watcher, nil
+}
+
+// releaseLease deletes the Lease of the given path if it's held by the
+// given holder.
+func (s *Server) releaseLease(ctx context.Context, nodePath, holder string) {
+	lease, err := s.leases.Get(ctx, objectName(nodePath), metav1.GetOptions{})
+	if err != nil {
+		return
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != holder {
+		return
+	}
+	if err := s.leases.Delete(ctx, lease.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &lease.ResourceVersion},
+	}); err != nil && !apierrors.IsNotFound(err) {
+		log.Warningf("failed to delete the Lease of %v, may have left it behind: %v", nodePath, err)
+	}
+}
+
+// renew renews the Lease at the given interval, until the lock is
+// unlocked or the Lease is lost.
+func (ld *kubernetesLockDescriptor) renew(interval time.Duration) {
+	defer close(ld.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ld.stop:
+			return
+		case <-ld.s.running:
+			return
+		case <-ticker.C:
+		}
+
+		err := ld.renewOnce()
+		if errors.Is(err, errLockLost) {
+			log.Errorf("Lock of %v lost: %v", ld.nodePath, err)
+			close(ld.lost)
+			return
+		}
+		if err != nil {
+			// We'll try again at the next tick, the Lease may
+			// still be valid by then.
+			log.Warningf("failed to renew the Lease of %v: %v", ld.nodePath, err)
+		}
+	}
+}
+
+// renewOnce renews the Lease once.
+func (ld *kubernetesLockDescriptor) renewOnce() error {
+	ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+	defer cancel()
+
+	ld.mu.Lock()
+	defer ld.mu.Unlock()
+
+	lease := ld.lease.DeepCopy()
+	now := metav1.NowMicro()
+	lease.Spec.RenewTime = &now
+	updated, err := ld.s.leases.Update(ctx, lease, metav1.UpdateOptions{})
+	if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+		// Someone else changed the Lease, check if we still hold it.
+		current, err := ld.s.leases.Get(ctx, lease.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return errLockLost
+		}
+		if err != nil {
+			return err
+		}
+		if current.Spec.HolderIdentity == nil || *current.Spec.HolderIdentity != ld.holder {
+			return errLockLost
+		}
+		ld.lease = current
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	ld.lease = updated
+	return nil
+}
+
+// Check is part of the topo.LockDescriptor interface.
+// We get the Lease to make sure we still hold it.
+func (ld *kubernetesLockDescriptor) Check(ctx context.Context) error {
+	select {
+	case <-ld.lost:
+		return errLockLost
+	default:
+	}
+	lease, err := ld.s.leases.Get(ctx, objectName(ld.nodePath), metav1.GetOptions{})
+	if err != nil {
+		return convertError(err, ld.nodePath)
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != ld.holder || !leaseHeld(lease, time.Now()) {
+		return errLockLost
+	}
+	return nil
+}
+
+// Unlock is part of the topo.LockDescriptor interface.
+func (ld *kubernetesLockDescriptor) Unlock(ctx context.Context) error {
+	ld.stopOnce.Do(func() {
+		close(ld.stop)
+	})
+	<-ld.done
+
+	ld.mu.Lock()
+	defer ld.mu.Unlock()
+	// The preconditions make sure we only delete the Lease if we
+	// still hold it.
+	err := ld.s.leases.Delete(ctx, ld.lease.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{
+			UID:             &ld.lease.UID,
+			ResourceVersion: &ld.lease.ResourceVersion,
+		},
+	})
+	return convertError(err, ld.nodePath)
+}
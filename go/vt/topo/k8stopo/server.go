@@ -0,0 +1,182 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package k8stopo implements topo.Server with the Kubernetes API server as
+the backend, so that Vitess doesn't need its own topo cluster when it runs
+on Kubernetes.
+
+We store the data as follows:
+  - Each file is a VitessTopoNode custom resource (see
+    VitessTopoNodes-crd.yaml), named after a hash of its path, which has
+    the path and the base64 encoded contents of the file in its spec.
+  - The nodes have a label with a hash of the root of the server, which is
+    used to list and watch the nodes of a server.
+  - The resourceVersion of a node is its topo.Version.
+  - Locks and elections are coordination Leases, which are kept alive as
+    long as they are held.
+
+We follow these conventions within this package:
+  - Call convertError(err) on any errors returned from the Kubernetes client
+    library. Functions defined in this package can be assumed to have
+    already converted errors as necessary.
+*/
+package k8stopo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+var (
+	kubeconfigPath string
+	kubeContext    string
+	namespace      string
+)
+
+func init() {
+	servenv.RegisterFlagsForTopoBinaries(registerServerFlags)
+	topo.RegisterFactory("k8s", Factory{})
+}
+
+func registerServerFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&kubeconfigPath, "topo_k8s_kubeconfig", kubeconfigPath, "Path to the kubeconfig to use to connect to the Kubernetes topo server. The in-cluster configuration is used if it's not set.")
+	fs.StringVar(&kubeContext, "topo_k8s_context", kubeContext, "The kubeconfig context to use to connect to the Kubernetes topo server.")
+	fs.StringVar(&namespace, "topo_k8s_namespace", namespace, "The namespace of the Kubernetes topo server objects. The namespace of the kubeconfig context, or of the pod, is used if it's not set.")
+}
+
+// Factory is the Kubernetes topo.Factory implementation.
+type Factory struct{}
+
+// HasGlobalReadOnlyCell is part of the topo.Factory interface.
+func (f Factory) HasGlobalReadOnlyCell(serverAddr, root string) bool {
+	return false
+}
+
+// Create is part of the topo.Factory interface.
+func (f Factory) Create(cell, serverAddr, root string) (topo.Conn, error) {
+	return NewServer(serverAddr, root)
+}
+
+// Server is the implementation of topo.Server for Kubernetes.
+type Server struct {
+	// nodes is the client for the VitessTopoNodes of the namespace.
+	nodes dynamic.ResourceInterface
+	// leases is the client for the Leases of the namespace.
+	leases coordinationv1client.LeaseInterface
+
+	// root is the root path for this client.
+	root string
+	// rootHash is the value of the root label of the nodes of this client.
+	rootHash string
+	// identity is the prefix of the holders of the Leases of this client.
+	identity string
+
+	// mu protects informer.
+	mu sync.Mutex
+	// informer caches the nodes of this client. It is started by the
+	// first watch, and is used by all of them.
+	informer cache.SharedIndexInformer
+
+	running chan struct{}
+}
+
+// NewServer returns a new k8stopo.Server. The optional serverAddr is the
+// address of the Kubernetes API server, which overrides the one of the
+// kubeconfig.
+func NewServer(serverAddr, root string) (*Server, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfigPath
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: kubeContext,
+	}
+	overrides.ClusterInfo.Server = serverAddr
+	overrides.Context.Namespace = namespace
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the Kubernetes client configuration: %w", err)
+	}
+	ns, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the Kubernetes namespace: %w", err)
+	}
+	return NewServerWithConfig(config, ns, root)
+}
+
+// NewServerWithConfig returns a new k8stopo.Server using the objects of
+// the given namespace.
+func NewServerWithConfig(config *rest.Config, ns, root string) (*Server, error) {
+	// The custom resources can only be served as JSON, so we use it for
+	// the Leases too.
+	config = rest.CopyConfig(config)
+	config.ContentType = runtime.ContentTypeJSON
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	coordinationClient, err := coordinationv1client.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		nodes:    dynamicClient.Resource(nodesResource).Namespace(ns),
+		leases:   coordinationClient.Leases(ns),
+		root:     root,
+		rootHash: hash(root)[:32],
+		identity: hostname,
+		running:  make(chan struct{}),
+	}, nil
+}
+
+// Close implements topo.Server.Close.
+// It stops the informer and all the watches.
+func (s *Server) Close() {
+	close(s.running)
+}
+
+// hash returns the hex encoded SHA-256 of the given path.
+func hash(p string) string {
+	sum := sha256.Sum256([]byte(p))
+	return hex.EncodeToString(sum[:])
+}
+
+// objectName returns the name of the object storing the given path. The
+// paths can't be used directly, as Kubernetes names are restricted to
+// DNS subdomains.
+func objectName(nodePath string) string {
+	return "vt-" + hash(nodePath)
+}
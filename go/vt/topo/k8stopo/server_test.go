@@ -0,0 +1,432 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stopo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/test"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// fakeEvent is a change of an object of the fake API server.
+type fakeEvent struct {
+	resource        string
+	eventType       string
+	object          map[string]any
+	resourceVersion int64
+}
+
+// fakeAPIServer is a minimal Kubernetes API server, which serves the
+// VitessTopoNodes and Leases. It implements the parts of the API that the
+// clients use: CRUD operations with resourceVersion preconditions, lists
+// with label and field selectors, and watches from a resourceVersion.
+// The fake clientsets of client-go can't be used, as they don't check
+// the resourceVersions.
+type fakeAPIServer struct {
+	mu sync.Mutex
+	// resourceVersion is the version of the last change.
+	resourceVersion int64
+	// objects are the objects by resource and name.
+	objects map[string]map[string]map[string]any
+	// events are all the changes, in order.
+	events []*fakeEvent
+	// changed is closed and replaced on each change.
+	changed chan struct{}
+}
+
+func newFakeAPIServer(t *testing.T) *httptest.Server {
+	s := &fakeAPIServer{
+		objects: make(map[string]map[string]map[string]any),
+		changed: make(chan struct{}),
+	}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// ServeHTTP implements http.Handler, for the paths
+// /apis/{group}/{version}/namespaces/{namespace}/{resource}[/{name}].
+func (s *fakeAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 6 || parts[0] != "apis" || parts[3] != "namespaces" {
+		writeStatus(w, http.StatusNotFound, "NotFound", "unknown path "+r.URL.Path)
+		return
+	}
+	resource := parts[5]
+	name := ""
+	if len(parts) > 6 {
+		name = parts[6]
+	}
+
+	switch {
+	case r.Method == http.MethodGet && name == "" && r.URL.Query().Get("watch") == "true":
+		s.watch(w, r, resource)
+	case r.Method == http.MethodGet && name == "":
+		s.list(w, r, resource)
+	case r.Method == http.MethodGet:
+		s.get(w, resource, name)
+	case r.Method == http.MethodPost:
+		s.create(w, r, resource)
+	case r.Method == http.MethodPut:
+		s.update(w, r, resource, name)
+	case r.Method == http.MethodDelete:
+		s.delete(w, r, resource, name)
+	default:
+		writeStatus(w, http.StatusMethodNotAllowed, "MethodNotAllowed", r.Method)
+	}
+}
+
+func writeStatus(w http.ResponseWriter, code int, reason, message string) {
+	writeJSON(w, code, map[string]any{
+		"kind":       "Status",
+		"apiVersion": "v1",
+		"metadata":   map[string]any{},
+		"status":     "Failure",
+		"message":    message,
+		"reason":     reason,
+		"code":       code,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, obj any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(obj)
+}
+
+func metadata(obj map[string]any) map[string]any {
+	md, ok := obj["metadata"].(map[string]any)
+	if !ok {
+		md = map[string]any{}
+		obj["metadata"] = md
+	}
+	return md
+}
+
+// matches returns true if the object matches the label and field
+// selectors of the request, which are only equality requirements here.
+func matches(obj map[string]any, r *http.Request) bool {
+	md := metadata(obj)
+	if selector := r.URL.Query().Get("labelSelector"); selector != "" {
+		labels, _ := md["labels"].(map[string]any)
+		for _, requirement := range strings.Split(selector, ",") {
+			k, v, _ := strings.Cut(requirement, "=")
+			if labels[k] != v {
+				return false
+			}
+		}
+	}
+	if selector := r.URL.Query().Get("fieldSelector"); selector != "" {
+		for _, requirement := range strings.Split(selector, ",") {
+			k, v, _ := strings.Cut(requirement, "=")
+			if k != "metadata.name" || md["name"] != v {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// record records a change of an object, and gives it a new
+// resourceVersion. It must be called with the mutex held.
+func (s *fakeAPIServer) record(resource, eventType string, obj map[string]any) {
+	s.resourceVersion++
+	metadata(obj)["resourceVersion"] = strconv.FormatInt(s.resourceVersion, 10)
+	s.events = append(s.events, &fakeEvent{
+		resource:        resource,
+		eventType:       eventType,
+		object:          obj,
+		resourceVersion: s.resourceVersion,
+	})
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *fakeAPIServer) get(w http.ResponseWriter, resource, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.objects[resource][name]
+	if !ok {
+		writeStatus(w, http.StatusNotFound, "NotFound", name+" not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *fakeAPIServer) list(w http.ResponseWriter, r *http.Request, resource string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := []any{}
+	var apiVersion, kind string
+	for _, obj := range s.objects[resource] {
+		if matches(obj, r) {
+			items = append(items, obj)
+		}
+	}
+	switch resource {
+	case "leases":
+		apiVersion, kind = "coordination.k8s.io/v1", "LeaseList"
+	default:
+		apiVersion, kind = nodesResource.GroupVersion().String(), nodeKind+"List"
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]any{
+			"resourceVersion": strconv.FormatInt(s.resourceVersion, 10),
+		},
+		"items": items,
+	})
+}
+
+func (s *fakeAPIServer) create(w http.ResponseWriter, r *http.Request, resource string) {
+	var obj map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+		writeStatus(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	md := metadata(obj)
+	name, _ := md["name"].(string)
+	if _, ok := s.objects[resource][name]; ok {
+		writeStatus(w, http.StatusConflict, "AlreadyExists", name+" already exists")
+		return
+	}
+	md["uid"] = fmt.Sprintf("uid-%d", s.resourceVersion+1)
+	md["creationTimestamp"] = time.Now().UTC().Format(time.RFC3339)
+	if s.objects[resource] == nil {
+		s.objects[resource] = make(map[string]map[string]any)
+	}
+	s.objects[resource][name] = obj
+	s.record(resource, "ADDED", obj)
+	writeJSON(w, http.StatusCreated, obj)
+}
+
+func (s *fakeAPIServer) update(w http.ResponseWriter, r *http.Request, resource, name string) {
+	var obj map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+		writeStatus(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.objects[resource][name]
+	if !ok {
+		writeStatus(w, http.StatusNotFound, "NotFound", name+" not found")
+		return
+	}
+	md, currentMD := metadata(obj), metadata(current)
+	if md["resourceVersion"] != currentMD["resourceVersion"] {
+		writeStatus(w, http.StatusConflict, "Conflict", name+" has been modified")
+		return
+	}
+	md["uid"] = currentMD["uid"]
+	md["creationTimestamp"] = currentMD["creationTimestamp"]
+	s.objects[resource][name] = obj
+	s.record(resource, "MODIFIED", obj)
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *fakeAPIServer) delete(w http.ResponseWriter, r *http.Request, resource, name string) {
+	var opts struct {
+		Preconditions struct {
+			UID             *string `json:"uid"`
+			ResourceVersion *string `json:"resourceVersion"`
+		} `json:"preconditions"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&opts)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.objects[resource][name]
+	if !ok {
+		writeStatus(w, http.StatusNotFound, "NotFound", name+" not found")
+		return
+	}
+	md := metadata(obj)
+	if (opts.Preconditions.UID != nil && *opts.Preconditions.UID != md["uid"]) ||
+		(opts.Preconditions.ResourceVersion != nil && *opts.Preconditions.ResourceVersion != md["resourceVersion"]) {
+		writeStatus(w, http.StatusConflict, "Conflict", name+" has been modified")
+		return
+	}
+	delete(s.objects[resource], name)
+	s.record(resource, "DELETED", obj)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"kind":       "Status",
+		"apiVersion": "v1",
+		"metadata":   map[string]any{},
+		"status":     "Success",
+	})
+}
+
+// watch streams the changes after the requested resourceVersion. Without
+// a resourceVersion, it first sends the current objects as added.
+func (s *fakeAPIServer) watch(w http.ResponseWriter, r *http.Request, resource string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	send := func(eventType string, obj map[string]any) {
+		if !matches(obj, r) {
+			return
+		}
+		_ = encoder.Encode(map[string]any{"type": eventType, "object": obj})
+	}
+
+	s.mu.Lock()
+	since, err := strconv.ParseInt(r.URL.Query().Get("resourceVersion"), 10, 64)
+	if err != nil || since == 0 {
+		for _, obj := range s.objects[resource] {
+			send("ADDED", obj)
+		}
+		since = s.resourceVersion
+	}
+	s.mu.Unlock()
+	flusher.Flush()
+
+	for {
+		s.mu.Lock()
+		for _, event := range s.events {
+			if event.resource == resource && event.resourceVersion > since {
+				send(event.eventType, event.object)
+			}
+		}
+		since = s.resourceVersion
+		changed := s.changed
+		s.mu.Unlock()
+		flusher.Flush()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changed:
+		}
+	}
+}
+
+func TestKubernetesTopo(t *testing.T) {
+	server := newFakeAPIServer(t)
+
+	testIndex := 0
+	newServer := func() *topo.Server {
+		// Each test will use its own sub-directories.
+		testRoot := fmt.Sprintf("/test-%v", testIndex)
+		testIndex++
+
+		// Create the server on the new root.
+		ts, err := topo.OpenServer("k8s", server.URL, path.Join(testRoot, topo.GlobalCell))
+		require.NoError(t, err)
+
+		// Create the CellInfo.
+		err = ts.CreateCellInfo(context.Background(), test.LocalCellName, &topodatapb.CellInfo{
+			ServerAddress: server.URL,
+			Root:          path.Join(testRoot, test.LocalCellName),
+		})
+		require.NoError(t, err)
+
+		return ts
+	}
+
+	// Run the TopoServerTestSuite tests.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	test.TopoServerTestSuite(t, ctx, func() *topo.Server {
+		return newServer()
+	}, []string{})
+}
+
+func TestLockExpiry(t *testing.T) {
+	server := newFakeAPIServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	conn, err := NewServer(server.URL, "/vitess/global")
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Create(ctx, "keyspaces/ks/Keyspace", []byte{})
+	require.NoError(t, err)
+
+	ld, err := conn.LockWithTTL(ctx, "keyspaces/ks", "first", time.Second)
+	require.NoError(t, err)
+	// Stop renewing the Lease, as if the holder was gone.
+	lockDescriptor := ld.(*kubernetesLockDescriptor)
+	lockDescriptor.stopOnce.Do(func() {
+		close(lockDescriptor.stop)
+	})
+	<-lockDescriptor.done
+
+	// The Lease expires, and can then be taken over.
+	ld2, err := conn.LockWithTTL(ctx, "keyspaces/ks", "second", time.Second)
+	require.NoError(t, err)
+	require.Error(t, ld.Check(ctx))
+	require.NoError(t, ld2.Check(ctx))
+	// The first holder can't release the Lease of the second one.
+	require.True(t, topo.IsErrType(ld.Unlock(ctx), topo.BadVersion))
+	require.NoError(t, ld2.Unlock(ctx))
+}
+
+func TestVersions(t *testing.T) {
+	server := newFakeAPIServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	conn, err := NewServer(server.URL, "/vitess/global")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	version, err := conn.Create(ctx, "file", []byte("a"))
+	require.NoError(t, err)
+	_, err = conn.Update(ctx, "file", []byte("b"), version)
+	require.NoError(t, err)
+
+	// The resourceVersion of the file changed.
+	_, err = conn.Update(ctx, "file", []byte("c"), version)
+	require.True(t, topo.IsErrType(err, topo.BadVersion), err)
+	err = conn.Delete(ctx, "file", version)
+	require.True(t, topo.IsErrType(err, topo.BadVersion), err)
+
+	contents, version, err := conn.Get(ctx, "file")
+	require.NoError(t, err)
+	require.Equal(t, "b", string(contents))
+	require.NoError(t, conn.Delete(ctx, "file", version))
+
+	_, err = conn.GetVersion(ctx, "file", 1)
+	require.True(t, topo.IsErrType(err, topo.NoImplementation), err)
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stopo
+
+import (
+	"strconv"
+)
+
+// KubernetesVersion is Kubernetes's idea of a version.
+// It implements topo.Version.
+// We use the resourceVersion of the objects, which is an opaque string.
+type KubernetesVersion string
+
+// String is part of the topo.Version interface.
+func (v KubernetesVersion) String() string {
+	return string(v)
+}
+
+// newerThan returns true if the resourceVersion v was written after
+// the resourceVersion other. Kubernetes only guarantees that
+// resourceVersions are opaque strings, but the API server backed by
+// etcd uses its revisions, so we compare them as integers when we can.
+// Versions which can't be compared are considered newer, as watchers
+// may get duplicate notifications.
+func (v KubernetesVersion) newerThan(other KubernetesVersion) bool {
+	a, err := strconv.ParseUint(string(v), 10, 64)
+	if err != nil {
+		return true
+	}
+	b, err := strconv.ParseUint(string(other), 10, 64)
+	if err != nil {
+		return true
+	}
+	return a > b
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stopo
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// nodeEvent is a change of a node seen by the informer.
+type nodeEvent struct {
+	node    *unstructured.Unstructured
+	deleted bool
+	// unknownVersion is true for deletions which were only noticed when
+	// relisting the nodes, which come with the last version of the node
+	// that the informer saw, rather than the version of the deletion.
+	unknownVersion bool
+}
+
+// getInformer returns the informer for the nodes of this server, and
+// starts it if needed. It waits until the informer has listed the nodes.
+func (s *Server) getInformer(ctx context.Context) (cache.SharedIndexInformer, error) {
+	s.mu.Lock()
+	if s.informer == nil {
+		selector := rootLabel + "=" + s.rootHash
+		s.informer = cache.NewSharedIndexInformer(&cache.ListWatch{
+			ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+				options.LabelSelector = selector
+				return s.nodes.List(ctx, options)
+			},
+			WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+				options.LabelSelector = selector
+				return s.nodes.Watch(ctx, options)
+			},
+		}, &unstructured.Unstructured{}, 0, cache.Indexers{})
+		go s.informer.Run(s.running)
+	}
+	informer := s.informer
+	s.mu.Unlock()
+
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return nil, convertError(ctx.Err(), s.root)
+	}
+	return informer, nil
+}
+
+// watchNodes sends the changes of the nodes for which the filter returns
+// true to the returned channel, until the context is canceled. The
+// returned function must be called to stop watching.
+func (s *Server) watchNodes(ctx context.Context, filter func(nodePath string) bool) (<-chan *nodeEvent, func(), error) {
+	informer, err := s.getInformer(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The informer buffers the events of each handler, so it's fine
+	// to block until the watcher reads them.
+	events := make(chan *nodeEvent)
+	send := func(event *nodeEvent) {
+		if !filter(nodeKey(event.node)) {
+			return
+		}
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}
+	registration, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			if node, ok := obj.(*unstructured.Unstructured); ok {
+				send(&nodeEvent{node: node})
+			}
+		},
+		UpdateFunc: func(_, obj any) {
+			if node, ok := obj.(*unstructured.Unstructured); ok {
+				send(&nodeEvent{node: node})
+			}
+		},
+		DeleteFunc: func(obj any) {
+			unknownVersion := false
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+				unknownVersion = true
+			}
+			if node, ok := obj.(*unstructured.Unstructured); ok {
+				send(&nodeEvent{node: node, deleted: true, unknownVersion: unknownVersion})
+			}
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	stop := func() {
+		_ = informer.RemoveEventHandler(registration)
+	}
+	return events, stop, nil
+}
+
+// Watch is part of the topo.Conn interface.
+func (s *Server) Watch(ctx context.Context, filePath string) (*topo.WatchData, <-chan *topo.WatchData, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	// Create an outer context that will be canceled on return and will cancel all inner watches.
+	outerCtx, outerCancel := context.WithCancel(ctx)
+
+	// We start watching before we get the initial version of the file,
+	// so we don't miss any change. The informer replays the nodes it
+	// has when we start watching, and may be behind or ahead of the
+	// initial version, so we skip the events which are not newer.
+	initialCtx, initialCancel := context.WithTimeout(outerCtx, topo.RemoteOperationTimeout)
+	defer initialCancel()
+	events, stop, err := s.watchNodes(outerCtx, func(p string) bool {
+		return p == nodePath
+	})
+	if err != nil {
+		outerCancel()
+		return nil, nil, err
+	}
+	contents, version, err := s.Get(initialCtx, filePath)
+	if err != nil {
+		stop()
+		outerCancel()
+		return nil, nil, err
+	}
+	wd := &topo.WatchData{
+		Contents: contents,
+		Version:  version,
+	}
+
+	// Create the notifications channel, send updates to it.
+	notifications := make(chan *topo.WatchData, 10)
+	go func() {
+		defer close(notifications)
+		defer outerCancel()
+		defer stop()
+
+		current := version.(KubernetesVersion)
+		for {
+			select {
+			case <-s.running:
+				return
+			case <-outerCtx.Done():
+				// This includes context cancellation errors.
+				notifications <- &topo.WatchData{
+					Err: convertError(outerCtx.Err(), nodePath),
+				}
+				return
+			case event := <-events:
+				eventVersion := KubernetesVersion(event.node.GetResourceVersion())
+				if !event.unknownVersion && !eventVersion.newerThan(current) {
+					continue
+				}
+				if event.deleted {
+					// Node is gone, send a final notice.
+					notifications <- &topo.WatchData{
+						Err: topo.NewError(topo.NoNode, nodePath),
+					}
+					return
+				}
+				contents, err := nodeContents(event.node)
+				if err != nil {
+					notifications <- &topo.WatchData{Err: err}
+					return
+				}
+				current = eventVersion
+				notifications <- &topo.WatchData{
+					Contents: contents,
+					Version:  eventVersion,
+				}
+			}
+		}
+	}()
+
+	return wd, notifications, nil
+}
+
+// WatchRecursive is part of the topo.Conn interface.
+func (s *Server) WatchRecursive(ctx context.Context, dirpath string) ([]*topo.WatchDataRecursive, <-chan *topo.WatchDataRecursive, error) {
+	nodePath := path.Join(s.root, dirpath)
+	if !strings.HasSuffix(nodePath, "/") {
+		nodePath = nodePath + "/"
+	}
+
+	// Create an outer context that will be canceled on return and will cancel all inner watches.
+	outerCtx, outerCancel := context.WithCancel(ctx)
+
+	// As for Watch, we start watching before we list the initial
+	// versions of the files, and skip the events which are not newer
+	// than the list.
+	events, stop, err := s.watchNodes(outerCtx, func(p string) bool {
+		return strings.HasPrefix(p, nodePath)
+	})
+	if err != nil {
+		outerCancel()
+		return nil, nil, err
+	}
+	nodes, listVersion, err := s.listNodes(outerCtx, nodePath)
+	if err != nil {
+		stop()
+		outerCancel()
+		return nil, nil, err
+	}
+
+	var initialwd []*topo.WatchDataRecursive
+	for _, node := range nodes {
+		contents, err := nodeContents(&node)
+		if err != nil {
+			stop()
+			outerCancel()
+			return nil, nil, err
+		}
+		var wd topo.WatchDataRecursive
+		wd.Path = nodeKey(&node)
+		wd.Contents = contents
+		wd.Version = KubernetesVersion(node.GetResourceVersion())
+		initialwd = append(initialwd, &wd)
+	}
+
+	// Create the notifications channel, send updates to it.
+	notifications := make(chan *topo.WatchDataRecursive, 10)
+	go func() {
+		defer close(notifications)
+		defer outerCancel()
+		defer stop()
+
+		since := KubernetesVersion(listVersion)
+		for {
+			select {
+			case <-s.running:
+				return
+			case <-outerCtx.Done():
+				// This includes context cancellation errors.
+				notifications <- &topo.WatchDataRecursive{
+					WatchData: topo.WatchData{Err: convertError(outerCtx.Err(), nodePath)},
+				}
+				return
+			case event := <-events:
+				eventVersion := KubernetesVersion(event.node.GetResourceVersion())
+				if !event.unknownVersion && !eventVersion.newerThan(since) {
+					continue
+				}
+				if event.deleted {
+					notifications <- &topo.WatchDataRecursive{
+						Path: nodeKey(event.node),
+						WatchData: topo.WatchData{
+							Err: topo.NewError(topo.NoNode, nodePath),
+						},
+					}
+					continue
+				}
+				contents, err := nodeContents(event.node)
+				if err != nil {
+					notifications <- &topo.WatchDataRecursive{
+						Path:      nodeKey(event.node),
+						WatchData: topo.WatchData{Err: err},
+					}
+					continue
+				}
+				notifications <- &topo.WatchDataRecursive{
+					Path: nodeKey(event.node),
+					WatchData: topo.WatchData{
+						Contents: contents,
+						Version:  eventVersion,
+					},
+				}
+			}
+		}
+	}()
+
+	return initialwd, notifications, nil
+}
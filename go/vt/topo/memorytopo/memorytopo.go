@@ -23,6 +23,7 @@ import (
 	"context"
 	"errors"
 	"math/rand/v2"
+	"path"
 	"regexp"
 	"strings"
 	"sync"
@@ -228,6 +229,17 @@ func (n *node) recurseContents(callback func(n *node)) {
 	}
 }
 
+// pathFrom returns the path of the node, given one of its parent
+// directories and the path of that directory. It is the path the
+// recursive watches get when the node changes.
+func (n *node) pathFrom(dir *node, dirpath string) string {
+	var names []string
+	for p := n; p != nil && p != dir; p = p.parent {
+		names = append([]string{p.name}, names...)
+	}
+	return path.Join(append([]string{dirpath}, names...)...)
+}
+
 func (n *node) propagateRecursiveWatch(ev *topo.WatchDataRecursive) {
 	for parent := n.parent; parent != nil; parent = parent.parent {
 		for _, w := range parent.watches {
@@ -102,12 +102,12 @@ func (c *Conn) WatchRecursive(ctx context.Context, dirpath string) ([]*topo.Watc
 	}
 
 	var initialwd []*topo.WatchDataRecursive
-	n.recurseContents(func(n *node) {
+	n.recurseContents(func(child *node) {
 		initialwd = append(initialwd, &topo.WatchDataRecursive{
-			Path: n.name,
+			Path: child.pathFrom(n, dirpath),
 			WatchData: topo.WatchData{
-				Contents: n.contents,
-				Version:  NodeVersion(n.version),
+				Contents: child.contents,
+				Version:  NodeVersion(child.version),
 			},
 		})
 	})
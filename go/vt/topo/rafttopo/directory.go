@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rafttopo
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+)
+
+// ListDir is part of the topo.Conn interface.
+func (s *Server) ListDir(ctx context.Context, dirPath string, full bool) ([]topo.DirEntry, error) {
+	nodePath := path.Join(s.root, dirPath) + "/"
+	if nodePath == "//" {
+		// Special case where s.root is "/", dirPath is empty,
+		// we would end up with "//". in that case, we want "/".
+		nodePath = "/"
+	}
+
+	resp, err := s.cli.List(ctx, &vttopopb.ListRequest{
+		Prefix:   nodePath,
+		KeysOnly: true,
+	})
+	if err != nil {
+		return nil, convertError(err, dirPath)
+	}
+	if len(resp.Kvs) == 0 {
+		// No key starts with this prefix, means the directory
+		// doesn't exist.
+		return nil, topo.NewError(topo.NoNode, nodePath)
+	}
+
+	prefixLen := len(nodePath)
+	var result []topo.DirEntry
+	for _, kv := range resp.Kvs {
+		p := kv.Key
+
+		// Remove the prefix, base path.
+		if !strings.HasPrefix(p, nodePath) {
+			return nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "vttopo returned key %v which doesn't start with %v", p, nodePath)
+		}
+		p = p[prefixLen:]
+
+		// Keep only the part until the first '/'.
+		t := topo.TypeFile
+		if i := strings.Index(p, "/"); i >= 0 {
+			p = p[:i]
+			t = topo.TypeDirectory
+		}
+
+		// Remove duplicates, add to list.
+		if len(result) == 0 || result[len(result)-1].Name != p {
+			e := topo.DirEntry{
+				Name: p,
+			}
+			if full {
+				e.Type = t
+				if kv.LeaseId != 0 {
+					// Only locks have a lease associated with them.
+					e.Ephemeral = true
+				}
+			}
+			result = append(result, e)
+		}
+	}
+
+	return result, nil
+}
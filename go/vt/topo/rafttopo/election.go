@@ -0,0 +1,220 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rafttopo
+
+import (
+	"context"
+	"path"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+)
+
+// NewLeaderParticipation is part of the topo.Server interface
+func (s *Server) NewLeaderParticipation(name, id string) (topo.LeaderParticipation, error) {
+	return &raftLeaderParticipation{
+		s:    s,
+		name: name,
+		id:   id,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}, nil
+}
+
+// raftLeaderParticipation implements topo.LeaderParticipation.
+//
+// We use a directory (in global election path, with the name) with
+// ephemeral files in it, that contains the id. The oldest version
+// wins the election.
+type raftLeaderParticipation struct {
+	// s is our parent vttopo topo Server
+	s *Server
+
+	// name is the name of this LeaderParticipation
+	name string
+
+	// id is the process's current id.
+	id string
+
+	// stop is a channel closed when Stop is called.
+	stop chan struct{}
+
+	// done is a channel closed when we're done processing the Stop
+	done chan struct{}
+}
+
+// WaitForLeadership is part of the topo.LeaderParticipation interface.
+func (mp *raftLeaderParticipation) WaitForLeadership() (context.Context, error) {
+	// If Stop was already called, mp.done is closed, so we are interrupted.
+	select {
+	case <-mp.done:
+		return nil, topo.NewError(topo.Interrupted, "Leadership")
+	default:
+	}
+
+	electionPath := path.Join(electionsPath, mp.name)
+	var ld topo.LockDescriptor
+
+	// We use a cancelable context here. If stop is closed,
+	// we just cancel that context.
+	lockCtx, lockCancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-mp.s.running:
+			return
+		case <-mp.stop:
+		}
+		if ld != nil {
+			if err := ld.Unlock(context.Background()); err != nil {
+				log.Errorf("failed to unlock electionPath %v: %v", electionPath, err)
+			}
+		}
+		lockCancel()
+		close(mp.done)
+	}()
+
+	// Try to get the primaryship, by getting a lock.
+	var err error
+	ld, err = mp.s.lock(lockCtx, electionPath, mp.id, leaseTTL)
+	if err != nil {
+		// It can be that we were interrupted.
+		return nil, err
+	}
+
+	// We got the lock. Return the lockContext. If Stop() is called,
+	// it will cancel the lockCtx, and cancel the returned context.
+	return lockCtx, nil
+}
+
+// Stop is part of the topo.LeaderParticipation interface
+func (mp *raftLeaderParticipation) Stop() {
+	close(mp.stop)
+	<-mp.done
+}
+
+// oldest returns the oldest of the given files, or nil.
+func oldest(kvs []*vttopopb.KeyValue) *vttopopb.KeyValue {
+	var result *vttopopb.KeyValue
+	for _, kv := range kvs {
+		if result == nil || kv.Version < result.Version {
+			result = kv
+		}
+	}
+	return result
+}
+
+// GetCurrentLeaderID is part of the topo.LeaderParticipation interface
+func (mp *raftLeaderParticipation) GetCurrentLeaderID(ctx context.Context) (string, error) {
+	electionPath := path.Join(mp.s.root, electionsPath, mp.name)
+
+	// Get the files in the directory, the oldest is the leader.
+	resp, err := mp.s.cli.List(ctx, &vttopopb.ListRequest{Prefix: electionPath + "/"})
+	if err != nil {
+		return "", convertError(err, electionPath)
+	}
+	kv := oldest(resp.Kvs)
+	if kv == nil {
+		// No key starts with this prefix, means nobody is the primary.
+		return "", nil
+	}
+	return string(kv.Value), nil
+}
+
+// WaitForNewLeader is part of the topo.LeaderParticipation interface
+func (mp *raftLeaderParticipation) WaitForNewLeader(ctx context.Context) (<-chan string, error) {
+	electionPath := path.Join(mp.s.root, electionsPath, mp.name)
+
+	notifications := make(chan string, 8)
+	ctx, cancel := context.WithCancel(ctx)
+
+	// Create the Watcher. vttopo sends the current files first, and then
+	// their changes.
+	stream, err := mp.s.cli.Watch(ctx, &vttopopb.WatchRequest{
+		Key:    electionPath + "/",
+		Prefix: true,
+	})
+	if err != nil {
+		cancel()
+		return nil, convertError(err, electionPath)
+	}
+	initial, err := stream.Recv()
+	if err != nil {
+		cancel()
+		return nil, convertError(err, electionPath)
+	}
+
+	// Get the current leader
+	files := make(map[string]*vttopopb.KeyValue)
+	for _, kv := range initial.Kvs {
+		files[kv.Key] = kv
+	}
+	currentLeader := func() string {
+		kvs := make([]*vttopopb.KeyValue, 0, len(files))
+		for _, kv := range files {
+			kvs = append(kvs, kv)
+		}
+		if kv := oldest(kvs); kv != nil {
+			return string(kv.Value)
+		}
+		return ""
+	}
+	leader := currentLeader()
+	if leader != "" {
+		notifications <- leader
+	}
+
+	go func() {
+		defer cancel()
+		defer close(notifications)
+
+		// Recv returns when ctx is canceled, which we do when we're
+		// done or stopped.
+		go func() {
+			select {
+			case <-mp.s.running:
+			case <-mp.done:
+			case <-ctx.Done():
+			}
+			cancel()
+		}()
+
+		for {
+			wresp, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			for _, ev := range wresp.Events {
+				switch ev.Type {
+				case vttopopb.WatchEvent_PUT:
+					files[ev.Kv.Key] = ev.Kv
+				case vttopopb.WatchEvent_DELETE:
+					delete(files, ev.Kv.Key)
+				}
+			}
+			if newLeader := currentLeader(); newLeader != leader {
+				leader = newLeader
+				if leader != "" {
+					notifications <- leader
+				}
+			}
+		}
+	}()
+
+	return notifications, nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rafttopo
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// convertError converts a vttopo error into a topo error. All errors
+// are either application-level errors, or context errors.
+func convertError(err error, nodePath string) error {
+	if err == nil {
+		return nil
+	}
+
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.NotFound:
+			return topo.NewError(topo.NoNode, nodePath)
+		case codes.AlreadyExists:
+			return topo.NewError(topo.NodeExists, nodePath)
+		case codes.FailedPrecondition:
+			return topo.NewError(topo.BadVersion, nodePath)
+		case codes.Canceled:
+			return topo.NewError(topo.Interrupted, nodePath)
+		case codes.DeadlineExceeded, codes.Unavailable:
+			// vttopo returns Unavailable when there is no Raft
+			// leader, which is a timeout for our callers, like
+			// for etcd.
+			return topo.NewError(topo.Timeout, nodePath)
+		case codes.ResourceExhausted:
+			return topo.NewError(topo.ResourceExhausted, nodePath)
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return topo.NewError(topo.Interrupted, nodePath)
+	case errors.Is(err, context.DeadlineExceeded):
+		return topo.NewError(topo.Timeout, nodePath)
+	default:
+		return err
+	}
+}
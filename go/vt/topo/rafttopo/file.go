@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rafttopo
+
+import (
+	"context"
+	"path"
+
+	"vitess.io/vitess/go/vt/topo"
+
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+)
+
+// Create is part of the topo.Conn interface.
+func (s *Server) Create(ctx context.Context, filePath string, contents []byte) (topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	resp, err := s.cli.Create(ctx, &vttopopb.CreateRequest{
+		Key:   nodePath,
+		Value: contents,
+	})
+	if err != nil {
+		return nil, convertError(err, nodePath)
+	}
+	return RaftVersion(resp.Version), nil
+}
+
+// Update is part of the topo.Conn interface.
+func (s *Server) Update(ctx context.Context, filePath string, contents []byte, version topo.Version) (topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	req := &vttopopb.UpdateRequest{
+		Key:   nodePath,
+		Value: contents,
+	}
+	if version != nil {
+		req.Version = int64(version.(RaftVersion))
+	}
+	resp, err := s.cli.Update(ctx, req)
+	if err != nil {
+		return nil, convertError(err, nodePath)
+	}
+	return RaftVersion(resp.Version), nil
+}
+
+// Get is part of the topo.Conn interface.
+func (s *Server) Get(ctx context.Context, filePath string) ([]byte, topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	resp, err := s.cli.Get(ctx, &vttopopb.GetRequest{Key: nodePath})
+	if err != nil {
+		return nil, nil, convertError(err, nodePath)
+	}
+	return resp.Kv.Value, RaftVersion(resp.Kv.Version), nil
+}
+
+// GetVersion is part of the topo.Conn interface.
+func (s *Server) GetVersion(ctx context.Context, filePath string, version int64) ([]byte, error) {
+	// vttopo doesn't keep the history of the files.
+	return nil, topo.NewError(topo.NoImplementation, "GetVersion not supported in vttopo")
+}
+
+// List is part of the topo.Conn interface.
+func (s *Server) List(ctx context.Context, filePathPrefix string) ([]topo.KVInfo, error) {
+	nodePathPrefix := path.Join(s.root, filePathPrefix)
+
+	resp, err := s.cli.List(ctx, &vttopopb.ListRequest{Prefix: nodePathPrefix})
+	if err != nil {
+		return []topo.KVInfo{}, convertError(err, nodePathPrefix)
+	}
+	if len(resp.Kvs) == 0 {
+		return []topo.KVInfo{}, topo.NewError(topo.NoNode, nodePathPrefix)
+	}
+	results := make([]topo.KVInfo, len(resp.Kvs))
+	for n, kv := range resp.Kvs {
+		results[n].Key = []byte(kv.Key)
+		results[n].Value = kv.Value
+		results[n].Version = RaftVersion(kv.Version)
+	}
+	return results, nil
+}
+
+// Delete is part of the topo.Conn interface.
+func (s *Server) Delete(ctx context.Context, filePath string, version topo.Version) error {
+	nodePath := path.Join(s.root, filePath)
+
+	req := &vttopopb.DeleteRequest{Key: nodePath}
+	if version != nil {
+		req.Version = int64(version.(RaftVersion))
+	}
+	_, err := s.cli.Delete(ctx, req)
+	return convertError(err, nodePath)
+}
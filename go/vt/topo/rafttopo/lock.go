@@ -0,0 +1,312 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rafttopo
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
+
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+)
+
+var (
+	leaseTTL = 30 // This is the default used for all non-named locks
+)
+
+func init() {
+	servenv.RegisterFlagsForTopoBinaries(registerLockFlags)
+}
+
+func registerLockFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&leaseTTL, "topo_raft_lease_ttl", leaseTTL, "Lease TTL for locks and leader election. The client will use KeepAlive to keep the lease going.")
+}
+
+// raftLease is a lease kept alive until it is revoked.
+type raftLease struct {
+	s  *Server
+	id int64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// grant creates a lease, and keeps it alive until it is revoked.
+func (s *Server) grant(ctx context.Context, ttl int) (*raftLease, error) {
+	resp, err := s.cli.Grant(ctx, &vttopopb.GrantRequest{Ttl: int64(ttl)})
+	if err != nil {
+		return nil, convertError(err, "lease")
+	}
+	l := &raftLease{
+		s:    s,
+		id:   resp.LeaseId,
+		stop: make(chan struct{}),
+	}
+	go l.keepAlive(time.Duration(ttl) * time.Second)
+	return l, nil
+}
+
+// keepAlive keeps the lease alive, until it's revoked or gone.
+func (l *raftLease) keepAlive(ttl time.Duration) {
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-l.s.running:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), ttl/3)
+			_, err := l.s.cli.KeepAlive(ctx, &vttopopb.KeepAliveRequest{LeaseId: l.id})
+			cancel()
+			if status.Code(err) == codes.NotFound {
+				// The lease expired, there is nothing left to
+				// keep alive.
+				log.Warningf("lease %v expired", l.id)
+				return
+			}
+			if err != nil {
+				log.Warningf("KeepAlive(%v) failed: %v", l.id, err)
+			}
+		}
+	}
+}
+
+// revoke stops keeping the lease alive, and revokes it, which deletes
+// the files attached to it.
+func (l *raftLease) revoke(ctx context.Context) error {
+	l.stopOnce.Do(func() {
+		close(l.stop)
+	})
+	_, err := l.s.cli.Revoke(ctx, &vttopopb.RevokeRequest{LeaseId: l.id})
+	return err
+}
+
+// newUniqueEphemeralKV creates a new file in the provided directory.
+// It is linked to the Lease.
+// Errors returned are converted to topo errors.
+func (s *Server) newUniqueEphemeralKV(ctx context.Context, lease *raftLease, nodePath string, contents string) (string, int64, error) {
+	// Use the lease ID as the file name, so it's guaranteed unique.
+	newKey := fmt.Sprintf("%v/%v", nodePath, lease.id)
+
+	resp, err := s.cli.Create(ctx, &vttopopb.CreateRequest{
+		Key:     newKey,
+		Value:   []byte(contents),
+		LeaseId: lease.id,
+	})
+	if err != nil {
+		return "", 0, convertError(err, newKey)
+	}
+	return newKey, resp.Version, nil
+}
+
+// waitOnLastRev waits on all revisions of the files in the provided
+// directory that have revisions smaller than the provided revision.
+// It returns true only if there is no more other older files.
+func (s *Server) waitOnLastRev(ctx context.Context, nodePath string, revision int64) (bool, error) {
+	// Get the keys that are blocking us, if any.
+	resp, err := s.cli.List(ctx, &vttopopb.ListRequest{
+		Prefix:   nodePath + "/",
+		KeysOnly: true,
+	})
+	if err != nil {
+		return false, convertError(err, nodePath)
+	}
+	var lastKey *vttopopb.KeyValue
+	for _, kv := range resp.Kvs {
+		if kv.Version < revision && (lastKey == nil || kv.Version > lastKey.Version) {
+			lastKey = kv
+		}
+	}
+	if lastKey == nil {
+		// No older key, we're done waiting.
+		return true, nil
+	}
+
+	// Wait for release on blocking key. Cancel the watch when we
+	// exit this function.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := s.cli.Watch(ctx, &vttopopb.WatchRequest{Key: lastKey.Key})
+	if err != nil {
+		return false, convertError(err, nodePath)
+	}
+	for {
+		wresp, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return false, convertError(ctx.Err(), nodePath)
+			}
+			// The Watch stopped, we're not sure if there are
+			// more items.
+			return false, nil
+		}
+		if wresp.Kvs == nil && wresp.Events == nil {
+			// The first response is empty: the key is
+			// already gone.
+			return false, nil
+		}
+		for _, ev := range wresp.Events {
+			if ev.Type == vttopopb.WatchEvent_DELETE {
+				// There might still be older keys,
+				// but not this one.
+				return false, nil
+			}
+		}
+	}
+}
+
+// raftLockDescriptor implements topo.LockDescriptor.
+type raftLockDescriptor struct {
+	s     *Server
+	lease *raftLease
+}
+
+// TryLock is part of the topo.Conn interface.
+func (s *Server) TryLock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	// We list all the entries under dirPath
+	entries, err := s.ListDir(ctx, dirPath, true)
+	if err != nil {
+		// We need to return the right error codes, like
+		// topo.ErrNoNode and topo.ErrInterrupted, and the
+		// easiest way to do this is to return convertError(err).
+		// It may lose some of the context, if this is an issue,
+		// maybe logging the error would work here.
+		return nil, convertError(err, dirPath)
+	}
+
+	// If there is a folder '/locks' with some entries in it then we can assume that someone else already has a lock.
+	// Throw error in this case
+	for _, e := range entries {
+		if e.Name == locksPath && e.Type == topo.TypeDirectory && e.Ephemeral {
+			return nil, topo.NewError(topo.NodeExists, fmt.Sprintf("lock already exists at path %s", dirPath))
+		}
+	}
+
+	// everything is good let's acquire the lock.
+	return s.lock(ctx, dirPath, contents, leaseTTL)
+}
+
+// Lock is part of the topo.Conn interface.
+func (s *Server) Lock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	// We list the directory first to make sure it exists.
+	if _, err := s.ListDir(ctx, dirPath, false /*full*/); err != nil {
+		// We need to return the right error codes, like
+		// topo.ErrNoNode and topo.ErrInterrupted, and the
+		// easiest way to do this is to return convertError(err).
+		// It may lose some of the context, if this is an issue,
+		// maybe logging the error would work here.
+		return nil, convertError(err, dirPath)
+	}
+
+	return s.lock(ctx, dirPath, contents, leaseTTL)
+}
+
+// LockWithTTL is part of the topo.Conn interface.
+func (s *Server) LockWithTTL(ctx context.Context, dirPath, contents string, ttl time.Duration) (topo.LockDescriptor, error) {
+	// We list the directory first to make sure it exists.
+	if _, err := s.ListDir(ctx, dirPath, false /*full*/); err != nil {
+		// We need to return the right error codes, like
+		// topo.ErrNoNode and topo.ErrInterrupted, and the
+		// easiest way to do this is to return convertError(err).
+		// It may lose some of the context, if this is an issue,
+		// maybe logging the error would work here.
+		return nil, convertError(err, dirPath)
+	}
+
+	return s.lock(ctx, dirPath, contents, int(ttl.Seconds()))
+}
+
+// LockName is part of the topo.Conn interface.
+func (s *Server) LockName(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	return s.lock(ctx, dirPath, contents, int(topo.NamedLockTTL.Seconds()))
+}
+
+// lock is used by both Lock() and primary election.
+func (s *Server) lock(ctx context.Context, nodePath, contents string, ttl int) (topo.LockDescriptor, error) {
+	nodePath = path.Join(s.root, nodePath, locksPath)
+
+	// Get a lease, which is kept alive until we revoke it.
+	lease, err := s.grant(ctx, ttl)
+	if err != nil {
+		return nil, convertError(err, nodePath)
+	}
+
+	// Create an ephemeral node in the locks directory.
+	key, revision, err := s.newUniqueEphemeralKV(ctx, lease, nodePath, contents)
+	if err != nil {
+		// Our context may have been canceled as we were sending
+		// the creation request, so we don't know if it succeeded
+		// or not. In any case, let's revoke the lease, so we don't
+		// leave it, or an orphan node, behind.
+		rctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+		defer cancel()
+		if rerr := lease.revoke(rctx); rerr != nil {
+			log.Warningf("revoke(%d) failed: %v", lease.id, rerr)
+		}
+		return nil, err
+	}
+
+	// Wait until all older nodes in the locks directory are gone.
+	for {
+		done, err := s.waitOnLastRev(ctx, nodePath, revision)
+		if err != nil {
+			// We had an error waiting on the last node.
+			// Revoke our lease, this will delete the file.
+			if rerr := lease.revoke(context.Background()); rerr != nil {
+				log.Warningf("revoke(%d) failed, may have left %v behind: %v", lease.id, key, rerr)
+			}
+			return nil, err
+		}
+		if done {
+			// No more older nodes, we're it!
+			return &raftLockDescriptor{
+				s:     s,
+				lease: lease,
+			}, nil
+		}
+	}
+}
+
+// Check is part of the topo.LockDescriptor interface.
+// We use KeepAlive to make sure the lease is still active and well.
+func (ld *raftLockDescriptor) Check(ctx context.Context) error {
+	_, err := ld.s.cli.KeepAlive(ctx, &vttopopb.KeepAliveRequest{LeaseId: ld.lease.id})
+	if err != nil {
+		return convertError(err, "lease")
+	}
+	return nil
+}
+
+// Unlock is part of the topo.LockDescriptor interface.
+func (ld *raftLockDescriptor) Unlock(ctx context.Context) error {
+	if err := ld.lease.revoke(ctx); err != nil {
+		return convertError(err, "lease")
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package rafttopo implements topo.Server with vttopo as the backend. vttopo
+is a topology server made of a few nodes which replicate their data with
+Raft, see the vttopo package.
+
+The server address is the comma separated list of the addresses of the
+nodes. We connect to the first available one, and fail over to the next
+ones.
+
+We use the vttopo primitives the same way etcd2topo uses the etcd ones:
+  - The version of a file is the Raft index of its last change.
+  - Locks and elections are ephemeral files attached to a lease kept
+    alive by the client. The oldest file wins.
+
+We follow these conventions within this package:
+  - Call convertError(err) on any errors returned from the vttopo client.
+    Functions defined in this package can be assumed to have already
+    converted errors as necessary.
+*/
+package rafttopo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
+
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+)
+
+var (
+	clientCertPath string
+	clientKeyPath  string
+	serverCaPath   string
+)
+
+func init() {
+	servenv.RegisterFlagsForTopoBinaries(registerServerFlags)
+	topo.RegisterFactory("raft", Factory{})
+}
+
+func registerServerFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&clientCertPath, "topo_raft_tls_cert", clientCertPath, "path to the client cert to use to connect to the vttopo server, requires topo_raft_tls_key, enables TLS")
+	fs.StringVar(&clientKeyPath, "topo_raft_tls_key", clientKeyPath, "path to the client key to use to connect to the vttopo server, enables TLS")
+	fs.StringVar(&serverCaPath, "topo_raft_tls_ca", serverCaPath, "path to the ca to use to validate the server cert when connecting to the vttopo server")
+}
+
+// Factory is the vttopo topo.Factory implementation.
+type Factory struct{}
+
+// HasGlobalReadOnlyCell is part of the topo.Factory interface.
+func (f Factory) HasGlobalReadOnlyCell(serverAddr, root string) bool {
+	return false
+}
+
+// Create is part of the topo.Factory interface.
+func (f Factory) Create(cell, serverAddr, root string) (topo.Conn, error) {
+	return NewServer(serverAddr, root)
+}
+
+// Server is the implementation of topo.Server for vttopo.
+type Server struct {
+	conn *grpc.ClientConn
+	cli  vttopopb.TopoClient
+
+	// root is the root path for this client.
+	root string
+
+	running chan struct{}
+}
+
+// NewServerWithOpts creates a new server with the provided TLS options.
+func NewServerWithOpts(serverAddr, root, certPath, keyPath, caPath string) (*Server, error) {
+	opt, err := grpcclient.SecureDialOption(certPath, keyPath, caPath, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	// The manual resolver gives all the nodes to the default pick_first
+	// balancer, which fails over to the next node when one is down.
+	r := manual.NewBuilderWithScheme("vttopo")
+	var addresses []resolver.Address
+	for _, addr := range strings.Split(serverAddr, ",") {
+		addresses = append(addresses, resolver.Address{Addr: addr})
+	}
+	r.InitialState(resolver.State{Addresses: addresses})
+
+	conn, err := grpcclient.DialContext(context.Background(), r.Scheme()+":///"+serverAddr, grpcclient.FailFast(false), opt, grpc.WithResolvers(r))
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		conn:    conn,
+		cli:     vttopopb.NewTopoClient(conn),
+		root:    root,
+		running: make(chan struct{}),
+	}, nil
+}
+
+// NewServer returns a new rafttopo.Server.
+func NewServer(serverAddr, root string) (*Server, error) {
+	return NewServerWithOpts(serverAddr, root, clientCertPath, clientKeyPath, serverCaPath)
+}
+
+// Close implements topo.Server.Close.
+// It will nil out the client, so any attempt to re-use this server will
+// panic.
+func (s *Server) Close() {
+	close(s.running)
+	s.conn.Close()
+	s.conn = nil
+	s.cli = nil
+}
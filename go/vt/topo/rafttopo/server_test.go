@@ -0,0 +1,242 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rafttopo
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/test"
+	"vitess.io/vitess/go/vt/vttopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// testNode is a vttopo node of a test cluster.
+type testNode struct {
+	*vttopo.Server
+	addr string
+	gs   *grpc.Server
+	once sync.Once
+}
+
+func (n *testNode) stop() {
+	n.once.Do(func() {
+		n.gs.Stop()
+		n.Server.Close()
+	})
+}
+
+// startCluster starts a vttopo cluster in process, and waits until it
+// has a leader. The nodes are stopped at the end of the test.
+func startCluster(t *testing.T, count int) []*testNode {
+	nodes := make([]*testNode, count)
+	peers := make(map[string]string)
+	for i := range nodes {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		server, err := vttopo.NewServer(vttopo.Options{
+			ID:          addr,
+			RaftAddress: "127.0.0.1:0",
+		})
+		require.NoError(t, err)
+		gs := grpc.NewServer()
+		server.Register(gs)
+		go gs.Serve(listener)
+
+		node := &testNode{Server: server, addr: addr, gs: gs}
+		t.Cleanup(node.stop)
+		nodes[i] = node
+		peers[addr] = server.RaftAddress()
+	}
+
+	require.NoError(t, nodes[0].Bootstrap(peers))
+	require.Eventually(t, func() bool {
+		for _, node := range nodes {
+			if node.Leader() == "" {
+				return false
+			}
+		}
+		return true
+	}, 30*time.Second, 100*time.Millisecond)
+	return nodes
+}
+
+// clientAddr returns the server address for the clients of the cluster.
+// The followers are listed first, so the changes are forwarded to the
+// leader.
+func clientAddr(nodes []*testNode) string {
+	var followers, leaders []string
+	for _, node := range nodes {
+		if node.IsLeader() {
+			leaders = append(leaders, node.addr)
+		} else {
+			followers = append(followers, node.addr)
+		}
+	}
+	return strings.Join(append(followers, leaders...), ",")
+}
+
+func TestRaftTopo(t *testing.T) {
+	nodes := startCluster(t, 3)
+	addr := clientAddr(nodes)
+
+	testIndex := 0
+	newServer := func() *topo.Server {
+		// Each test will use its own sub-directories.
+		testRoot := fmt.Sprintf("/test-%v", testIndex)
+		testIndex++
+
+		// Create the server on the new root.
+		ts, err := topo.OpenServer("raft", addr, path.Join(testRoot, topo.GlobalCell))
+		require.NoError(t, err)
+
+		// Create the CellInfo.
+		err = ts.CreateCellInfo(context.Background(), test.LocalCellName, &topodatapb.CellInfo{
+			ServerAddress: addr,
+			Root:          path.Join(testRoot, test.LocalCellName),
+		})
+		require.NoError(t, err)
+
+		return ts
+	}
+
+	// Run the TopoServerTestSuite tests.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	test.TopoServerTestSuite(t, ctx, func() *topo.Server {
+		return newServer()
+	}, []string{})
+
+	// Run vttopo-specific tests.
+	ts := newServer()
+	testKeyspaceLock(t, ts)
+	ts.Close()
+}
+
+// testKeyspaceLock tests the lease keep alive (TTL).
+// Note TTL granularity is in seconds, even though the API uses time.Duration.
+// So we have to wait a long time in these tests.
+func testKeyspaceLock(t *testing.T, ts *topo.Server) {
+	ctx := context.Background()
+	keyspacePath := path.Join(topo.KeyspacesPath, "test_keyspace")
+	err := ts.CreateKeyspace(ctx, "test_keyspace", &topodatapb.Keyspace{})
+	require.NoError(t, err)
+
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	require.NoError(t, err)
+
+	// Short TTL, make sure it doesn't expire.
+	defer func(ttl int) {
+		leaseTTL = ttl
+	}(leaseTTL)
+	leaseTTL = 1
+	lockDescriptor, err := conn.Lock(ctx, keyspacePath, "short ttl")
+	require.NoError(t, err)
+	time.Sleep(2 * time.Second)
+	require.NoError(t, lockDescriptor.Check(ctx))
+	require.NoError(t, lockDescriptor.Unlock(ctx))
+}
+
+func TestLeaseExpiry(t *testing.T) {
+	nodes := startCluster(t, 3)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	conn, err := NewServer(clientAddr(nodes), "/vitess/global")
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Create(ctx, "keyspaces/ks/Keyspace", []byte{})
+	require.NoError(t, err)
+
+	ld, err := conn.LockWithTTL(ctx, "keyspaces/ks", "first", time.Second)
+	require.NoError(t, err)
+	// Stop keeping the lease alive, as if the holder was gone.
+	lease := ld.(*raftLockDescriptor).lease
+	lease.stopOnce.Do(func() {
+		close(lease.stop)
+	})
+
+	// The lease expires, and the lock can then be taken.
+	ld2, err := conn.LockWithTTL(ctx, "keyspaces/ks", "second", time.Second)
+	require.NoError(t, err)
+	require.True(t, topo.IsErrType(ld.Check(ctx), topo.NoNode))
+	require.NoError(t, ld2.Check(ctx))
+	require.NoError(t, ld2.Unlock(ctx))
+}
+
+func TestLeaderFailover(t *testing.T) {
+	nodes := startCluster(t, 3)
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	// Connect to the leader first, so we fail over to another node.
+	var leader *testNode
+	var addrs []string
+	for _, node := range nodes {
+		if node.IsLeader() {
+			leader = node
+			addrs = append([]string{node.addr}, addrs...)
+		} else {
+			addrs = append(addrs, node.addr)
+		}
+	}
+	require.NotNil(t, leader)
+	conn, err := NewServer(strings.Join(addrs, ","), "/vitess/global")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	version, err := conn.Create(ctx, "file", []byte("a"))
+	require.NoError(t, err)
+	_, watch, err := conn.Watch(ctx, "file")
+	require.NoError(t, err)
+
+	// Stop the leader, the watch ends, and the others elect a new one.
+	leader.stop()
+	wd := <-watch
+	require.Error(t, wd.Err)
+	require.Eventually(t, func() bool {
+		for _, node := range nodes {
+			if node != leader && node.IsLeader() {
+				return true
+			}
+		}
+		return false
+	}, 30*time.Second, 100*time.Millisecond)
+
+	// The data is still there, and can be changed.
+	var newVersion topo.Version
+	require.Eventually(t, func() bool {
+		newVersion, err = conn.Update(ctx, "file", []byte("b"), version)
+		return err == nil
+	}, 30*time.Second, 100*time.Millisecond)
+	contents, got, err := conn.Get(ctx, "file")
+	require.NoError(t, err)
+	require.Equal(t, "b", string(contents))
+	require.Equal(t, newVersion, got)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rafttopo
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"vitess.io/vitess/go/vt/topo"
+
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+)
+
+// Watch is part of the topo.Conn interface.
+func (s *Server) Watch(ctx context.Context, filePath string) (*topo.WatchData, <-chan *topo.WatchData, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	// Create an outer context that will be canceled on return and will cancel the watch.
+	outerCtx, outerCancel := context.WithCancel(ctx)
+
+	// Create the watch. vttopo sends the initial version of the file
+	// first, and then its changes, so we don't miss any.
+	stream, err := s.cli.Watch(outerCtx, &vttopopb.WatchRequest{Key: nodePath})
+	if err != nil {
+		outerCancel()
+		return nil, nil, convertError(err, nodePath)
+	}
+	initial, err := stream.Recv()
+	if err != nil {
+		outerCancel()
+		return nil, nil, convertError(err, nodePath)
+	}
+	if len(initial.Kvs) != 1 {
+		// Node doesn't exist.
+		outerCancel()
+		return nil, nil, topo.NewError(topo.NoNode, nodePath)
+	}
+	wd := &topo.WatchData{
+		Contents: initial.Kvs[0].Value,
+		Version:  RaftVersion(initial.Kvs[0].Version),
+	}
+
+	// Create the notifications channel, send updates to it.
+	notifications := make(chan *topo.WatchData, 10)
+	go func() {
+		defer close(notifications)
+		defer outerCancel()
+
+		for {
+			wresp, err := stream.Recv()
+			if err != nil {
+				// This includes context cancellation errors.
+				notifications <- &topo.WatchData{
+					Err: convertError(err, nodePath),
+				}
+				return
+			}
+			for _, ev := range wresp.Events {
+				switch ev.Type {
+				case vttopopb.WatchEvent_PUT:
+					notifications <- &topo.WatchData{
+						Contents: ev.Kv.Value,
+						Version:  RaftVersion(ev.Kv.Version),
+					}
+				case vttopopb.WatchEvent_DELETE:
+					// Node is gone, send a final notice.
+					notifications <- &topo.WatchData{
+						Err: topo.NewError(topo.NoNode, nodePath),
+					}
+					return
+				}
+			}
+		}
+	}()
+
+	return wd, notifications, nil
+}
+
+// WatchRecursive is part of the topo.Conn interface.
+func (s *Server) WatchRecursive(ctx context.Context, dirpath string) ([]*topo.WatchDataRecursive, <-chan *topo.WatchDataRecursive, error) {
+	nodePath := path.Join(s.root, dirpath)
+	if !strings.HasSuffix(nodePath, "/") {
+		nodePath = nodePath + "/"
+	}
+
+	// Create an outer context that will be canceled on return and will cancel the watch.
+	outerCtx, outerCancel := context.WithCancel(ctx)
+
+	// Create the watch. vttopo sends the initial versions of the files
+	// first, and then their changes, so we don't miss any.
+	stream, err := s.cli.Watch(outerCtx, &vttopopb.WatchRequest{
+		Key:    nodePath,
+		Prefix: true,
+	})
+	if err != nil {
+		outerCancel()
+		return nil, nil, convertError(err, nodePath)
+	}
+	initial, err := stream.Recv()
+	if err != nil {
+		outerCancel()
+		return nil, nil, convertError(err, nodePath)
+	}
+
+	var initialwd []*topo.WatchDataRecursive
+	for _, kv := range initial.Kvs {
+		var wd topo.WatchDataRecursive
+		wd.Path = kv.Key
+		wd.Contents = kv.Value
+		wd.Version = RaftVersion(kv.Version)
+		initialwd = append(initialwd, &wd)
+	}
+
+	// Create the notifications channel, send updates to it.
+	notifications := make(chan *topo.WatchDataRecursive, 10)
+	go func() {
+		defer close(notifications)
+		defer outerCancel()
+
+		for {
+			wresp, err := stream.Recv()
+			if err != nil {
+				// This includes context cancellation errors.
+				notifications <- &topo.WatchDataRecursive{
+					WatchData: topo.WatchData{Err: convertError(err, nodePath)},
+				}
+				return
+			}
+			for _, ev := range wresp.Events {
+				switch ev.Type {
+				case vttopopb.WatchEvent_PUT:
+					notifications <- &topo.WatchDataRecursive{
+						Path: ev.Kv.Key,
+						WatchData: topo.WatchData{
+							Contents: ev.Kv.Value,
+							Version:  RaftVersion(ev.Kv.Version),
+						},
+					}
+				case vttopopb.WatchEvent_DELETE:
+					notifications <- &topo.WatchDataRecursive{
+						Path: ev.Kv.Key,
+						WatchData: topo.WatchData{
+							Err: topo.NewError(topo.NoNode, nodePath),
+						},
+					}
+				}
+			}
+		}
+	}()
+
+	return initialwd, notifications, nil
+}
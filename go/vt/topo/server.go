@@ -218,7 +218,7 @@ func NewWithFactory(factory Factory, serverAddress, root string) (*Server, error
 	if err != nil {
 		return nil, err
 	}
-	conn = NewStatsConn(GlobalCell, conn, globalReadSem)
+	conn = NewStatsConn(GlobalCell, cacheConn(GlobalCell, conn), globalReadSem)
 
 	var connReadOnly Conn
 	if factory.HasGlobalReadOnlyCell(serverAddress, root) {
@@ -226,7 +226,7 @@ func NewWithFactory(factory Factory, serverAddress, root string) (*Server, error
 		if err != nil {
 			return nil, err
 		}
-		connReadOnly = NewStatsConn(GlobalReadOnlyCell, connReadOnly, globalReadSem)
+		connReadOnly = NewStatsConn(GlobalReadOnlyCell, cacheConn(GlobalReadOnlyCell, connReadOnly), globalReadSem)
 	} else {
 		connReadOnly = conn
 	}
@@ -309,7 +309,7 @@ func (ts *Server) ConnForCell(ctx context.Context, cell string) (Conn, error) {
 	switch {
 	case err == nil:
 		cellReadSem := semaphore.NewWeighted(DefaultReadConcurrency)
-		conn = NewStatsConn(cell, conn, cellReadSem)
+		conn = NewStatsConn(cell, cacheConn(cell, conn), cellReadSem)
 		ts.cellConns[cell] = cellConn{ci, conn}
 		return conn, nil
 	case IsErrType(err, NoNode):
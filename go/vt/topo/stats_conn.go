@@ -44,6 +44,21 @@ var (
 		"TopologyConnReadWaits",
 		"TopologyConnReadWait timings",
 		[]string{"Operation", "Cell"})
+
+	topoStatsConnCacheHits = stats.NewCountersWithMultiLabels(
+		"TopologyConnCacheHits",
+		"TopologyConnCacheHits reads and watches served by the topo cache per operation",
+		[]string{"Operation", "Cell"})
+
+	topoStatsConnCacheMisses = stats.NewCountersWithMultiLabels(
+		"TopologyConnCacheMisses",
+		"TopologyConnCacheMisses reads and watches sent to the topo server by the topo cache per operation",
+		[]string{"Operation", "Cell"})
+
+	topoStatsConnCacheWatches = stats.NewGaugesWithMultiLabels(
+		"TopologyConnCacheWatches",
+		"TopologyConnCacheWatches watches open on the topo server and shared by the topo cache",
+		[]string{"Operation", "Cell"})
 )
 
 const readOnlyErrorStrFormat = "cannot perform %s on %s as the topology server connection is read-only"
@@ -18,6 +18,7 @@ package test
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -72,7 +73,7 @@ func waitForInitialValue(t *testing.T, conn topo.Conn, srvKeyspace *topodatapb.S
 // waitForInitialValue waits for the initial value of
 // keyspaces/test_keyspace/SrvKeyspace to appear, and match the
 // provided srvKeyspace.
-func waitForInitialValueRecursive(t *testing.T, conn topo.Conn, srvKeyspace *topodatapb.SrvKeyspace) (changes <-chan *topo.WatchDataRecursive, cancel context.CancelFunc, err error) {
+func waitForInitialValueRecursive(t *testing.T, conn topo.Conn, srvKeyspace *topodatapb.SrvKeyspace) (initialPath string, changes <-chan *topo.WatchDataRecursive, cancel context.CancelFunc, err error) {
 	var current []*topo.WatchDataRecursive
 	ctx, cancel := context.WithCancel(context.Background())
 	start := time.Now()
@@ -90,7 +91,7 @@ func waitForInitialValueRecursive(t *testing.T, conn topo.Conn, srvKeyspace *top
 		if topo.IsErrType(err, topo.NoImplementation) {
 			// If this is not supported, skip the test
 			cancel()
-			return nil, nil, err
+			return "", nil, nil, err
 		}
 		if err != nil {
 			cancel()
@@ -109,7 +110,14 @@ func waitForInitialValueRecursive(t *testing.T, conn topo.Conn, srvKeyspace *top
 		t.Fatalf("got bad data: %v expected: %v", got, srvKeyspace)
 	}
 
-	return changes, cancel, nil
+	// The path is the one of the file, like in the changes, and not only
+	// its name.
+	if !strings.HasSuffix(current[0].Path, "keyspaces/test_keyspace/SrvKeyspace") {
+		cancel()
+		t.Fatalf("got bad initial path: %v", current[0].Path)
+	}
+
+	return current[0].Path, changes, cancel, nil
 }
 
 // checkWatch runs the tests on the Watch part of the Conn API.
@@ -329,7 +337,7 @@ func checkWatchRecursive(t *testing.T, ctx context.Context, ts *topo.Server) {
 	}
 
 	// start watching again, it should work
-	changes, secondCancel, err := waitForInitialValueRecursive(t, conn, srvKeyspace)
+	initialPath, changes, secondCancel, err := waitForInitialValueRecursive(t, conn, srvKeyspace)
 	if topo.IsErrType(err, topo.NoImplementation) {
 		// Skip the rest if there's no implementation
 		t.Logf("%T does not support WatchRecursive()", conn)
@@ -365,6 +373,9 @@ func checkWatchRecursive(t *testing.T, ctx context.Context, ts *topo.Server) {
 		}
 		if got.Partitions[0].ShardReferences[0].Name == "new_name" {
 			// watch worked, good
+			if wd.Path != initialPath {
+				t.Fatalf("got path %v for the change, but %v initially", wd.Path, initialPath)
+			}
 			break
 		}
 		t.Fatalf("got unknown SrvKeyspace: %v", got)
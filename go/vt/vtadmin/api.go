@@ -48,6 +48,7 @@ import (
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtadmin/audit"
 	"vitess.io/vitess/go/vt/vtadmin/cluster"
 	"vitess.io/vitess/go/vt/vtadmin/cluster/dynamic"
 	"vitess.io/vitess/go/vt/vtadmin/errors"
@@ -87,6 +88,7 @@ type API struct {
 	router       *mux.Router
 
 	authz *rbac.Authorizer
+	audit audit.Sink
 
 	options Options
 
@@ -106,6 +108,9 @@ type Options struct {
 	// EnableDynamicClusters makes it so that clients can pass clusters dynamically
 	// in a session-like way, either via HTTP cookies or gRPC metadata.
 	EnableDynamicClusters bool
+	// AuditSink, if set, is where the records of the mutating API calls are
+	// written. If it also implements audit.Querier, it serves GetAuditRecords.
+	AuditSink audit.Sink
 }
 
 // NewAPI returns a new API, configured to service the given set of clusters,
@@ -152,10 +157,17 @@ func NewAPI(env *vtenv.Environment, clusters []*cluster.Cluster, opts Options) *
 		})
 	}
 
+	if opts.AuditSink != nil {
+		// The audit interceptor must run after the authentication one, so the
+		// actor is known.
+		opts.GRPCOpts.UnaryInterceptors = append(opts.GRPCOpts.UnaryInterceptors, audit.UnaryServerInterceptor(opts.AuditSink))
+	}
+
 	api := &API{
 		clusters:   clusters,
 		clusterMap: clusterMap,
 		authz:      authz,
+		audit:      opts.AuditSink,
 		env:        env,
 	}
 
@@ -314,6 +326,7 @@ func (api *API) WithCluster(c *cluster.Cluster, id string) dynamic.API {
 		router:  api.router,
 		serv:    api.serv,
 		authz:   api.authz,
+		audit:   api.audit,
 		options: api.options,
 		env:     api.env,
 	}
@@ -368,8 +381,14 @@ func (api *API) Handler() http.Handler {
 	router.Use(handlers.CORS(
 		handlers.AllowCredentials(), handlers.AllowedOrigins(api.options.HTTPOpts.CORSOrigins), handlers.AllowedMethods([]string{"GET", "HEAD", "POST", "PUT", "DELETE", "OPTIONS"})))
 
+	if api.audit != nil {
+		// Runs after the authentication middleware of the outer router.
+		router.Use(audit.HTTPMiddleware(api.audit))
+	}
+
 	httpAPI := vtadminhttp.NewAPI(api, api.options.HTTPOpts)
 
+	router.HandleFunc("/audit", httpAPI.Adapt(vtadminhttp.GetAuditRecords)).Name("API.GetAuditRecords").Methods("GET")
 	router.HandleFunc("/backups", httpAPI.Adapt(vtadminhttp.GetBackups)).Name("API.GetBackups")
 	router.HandleFunc("/cells", httpAPI.Adapt(vtadminhttp.GetCellInfos)).Name("API.GetCellInfos")
 	router.HandleFunc("/cells_aliases", httpAPI.Adapt(vtadminhttp.GetCellsAliases)).Name("API.GetCellsAliases")
@@ -394,6 +413,7 @@ func (api *API) Handler() http.Handler {
 	router.HandleFunc("/migration/{cluster_id}/{keyspace}/retry", httpAPI.Adapt(vtadminhttp.RetrySchemaMigration)).Name("API.RetrySchemaMigration").Methods("PUT", "OPTIONS")
 	router.HandleFunc("/migrations/", httpAPI.Adapt(vtadminhttp.GetSchemaMigrations)).Name("API.GetSchemaMigrations")
 	router.HandleFunc("/movetables/{cluster_id}/complete", httpAPI.Adapt(vtadminhttp.MoveTablesComplete)).Name("API.MoveTablesComplete")
+	router.HandleFunc("/queryinsights", httpAPI.Adapt(vtadminhttp.GetQueryInsights)).Name("API.GetQueryInsights").Methods("GET")
 	router.HandleFunc("/schema/{table}", httpAPI.Adapt(vtadminhttp.FindSchema)).Name("API.FindSchema")
 	router.HandleFunc("/schema/{cluster_id}/{keyspace}/{table}", httpAPI.Adapt(vtadminhttp.GetSchema)).Name("API.GetSchema")
 	router.HandleFunc("/schemas", httpAPI.Adapt(vtadminhttp.GetSchemas)).Name("API.GetSchemas")
@@ -440,7 +460,10 @@ func (api *API) Handler() http.Handler {
 	router.HandleFunc("/workflow/{cluster_id}/{keyspace}/{name}/start", httpAPI.Adapt(vtadminhttp.StartWorkflow)).Name("API.StartWorkflow")
 	router.HandleFunc("/workflow/{cluster_id}/{keyspace}/{name}/stop", httpAPI.Adapt(vtadminhttp.StopWorkflow)).Name("API.StopWorkflow")
 	router.HandleFunc("/workflow/{cluster_id}/switchtraffic", httpAPI.Adapt(vtadminhttp.WorkflowSwitchTraffic)).Name("API.WorkflowSwitchTraffic")
+	router.HandleFunc("/workflow/{cluster_id}/reversetraffic", httpAPI.Adapt(vtadminhttp.WorkflowReverseTraffic)).Name("API.WorkflowReverseTraffic").Methods("POST")
 	router.HandleFunc("/workflow/{cluster_id}/delete", httpAPI.Adapt(vtadminhttp.WorkflowDelete)).Name("API.WorkflowDelete")
+	router.HandleFunc("/workflow/{cluster_id}/cancel", httpAPI.Adapt(vtadminhttp.WorkflowCancel)).Name("API.WorkflowCancel").Methods("POST")
+	router.HandleFunc("/workflow/{cluster_id}/complete", httpAPI.Adapt(vtadminhttp.WorkflowComplete)).Name("API.WorkflowComplete").Methods("POST")
 	router.HandleFunc("/workflow/{cluster_id}/materialize", httpAPI.Adapt(vtadminhttp.MaterializeCreate)).Name("API.MaterializeCreate").Methods("POST")
 	router.HandleFunc("/workflow/{cluster_id}/movetables", httpAPI.Adapt(vtadminhttp.MoveTablesCreate)).Name("API.MoveTablesCreate").Methods("POST")
 	router.HandleFunc("/workflow/{cluster_id}/reshard", httpAPI.Adapt(vtadminhttp.ReshardCreate)).Name("API.ReshardCreate").Methods("POST")
@@ -788,6 +811,49 @@ func (api *API) FindSchema(ctx context.Context, req *vtadminpb.FindSchemaRequest
 	}
 }
 
+// GetAuditRecords is part of the vtadminpb.VTAdminServer interface.
+func (api *API) GetAuditRecords(ctx context.Context, req *vtadminpb.GetAuditRecordsRequest) (*vtadminpb.GetAuditRecordsResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.GetAuditRecords")
+	defer span.Finish()
+
+	if api.audit == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "audit log is not enabled")
+	}
+
+	clusters, _ := api.getClustersForRequest(req.ClusterIds)
+
+	ids := make([]string, 0, len(clusters))
+	for _, c := range clusters {
+		if !api.authz.IsAuthorized(ctx, c.ID, rbac.AuditRecordResource, rbac.GetAction) {
+			continue
+		}
+
+		ids = append(ids, c.ID)
+	}
+
+	if len(ids) == 0 {
+		return &vtadminpb.GetAuditRecordsResponse{}, nil
+	}
+
+	query := req.CloneVT()
+	query.ClusterIds = ids
+
+	span.Annotate("cluster_ids", strings.Join(ids, ","))
+	span.Annotate("actor", req.Actor)
+	span.Annotate("resource", req.Resource)
+	span.Annotate("action", req.Action)
+	span.Annotate("limit", req.Limit)
+
+	records, err := audit.Query(ctx, api.audit, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtadminpb.GetAuditRecordsResponse{
+		Records: records,
+	}, nil
+}
+
 // GetBackups is part of the vtadminpb.VTAdminServer interface.
 func (api *API) GetBackups(ctx context.Context, req *vtadminpb.GetBackupsRequest) (*vtadminpb.GetBackupsResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "API.GetBackups")
@@ -1086,6 +1152,57 @@ func (api *API) GetKeyspaces(ctx context.Context, req *vtadminpb.GetKeyspacesReq
 	}, nil
 }
 
+// GetQueryInsights is part of the vtadminpb.VTAdminServer interface.
+func (api *API) GetQueryInsights(ctx context.Context, req *vtadminpb.GetQueryInsightsRequest) (*vtadminpb.GetQueryInsightsResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.GetQueryInsights")
+	defer span.Finish()
+
+	span.Annotate("sort", req.Sort.String())
+	span.Annotate("limit", req.Limit)
+	span.Annotate("table", req.Table)
+
+	clusters, _ := api.getClustersForRequest(req.ClusterIds)
+
+	var (
+		insights []*vtadminpb.ClusterQueryInsights
+		wg       sync.WaitGroup
+		er       concurrency.AllErrorRecorder
+		m        sync.Mutex
+	)
+
+	for _, c := range clusters {
+		if !api.authz.IsAuthorized(ctx, c.ID, rbac.QueryInsightsResource, rbac.GetAction) {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(c *cluster.Cluster) {
+			defer wg.Done()
+
+			ci, err := c.GetQueryInsights(ctx, req)
+			if err != nil {
+				er.RecordError(err)
+				return
+			}
+
+			m.Lock()
+			insights = append(insights, ci)
+			m.Unlock()
+		}(c)
+	}
+
+	wg.Wait()
+
+	if er.HasErrors() {
+		return nil, er.Error()
+	}
+
+	return &vtadminpb.GetQueryInsightsResponse{
+		Insights: insights,
+	}, nil
+}
+
 // GetSchema is part of the vtadminpb.VTAdminServer interface.
 func (api *API) GetSchema(ctx context.Context, req *vtadminpb.GetSchemaRequest) (*vtadminpb.Schema, error) {
 	span, ctx := trace.NewSpan(ctx, "API.GetSchema")
@@ -2842,6 +2959,54 @@ func (api *API) VTExplain(ctx context.Context, req *vtadminpb.VTExplainRequest)
 	}, nil
 }
 
+// WorkflowCancel is part of the vtadminpb.VTAdminServer interface.
+func (api *API) WorkflowCancel(ctx context.Context, req *vtadminpb.WorkflowCancelRequest) (*vtctldatapb.WorkflowDeleteResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.WorkflowCancel")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	if req.Request == nil {
+		return nil, fmt.Errorf("%w: request cannot be nil", errors.ErrInvalidRequest)
+	}
+
+	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.WorkflowResource, rbac.CancelAction) {
+		return nil, fmt.Errorf("%w: cannot cancel workflow in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Vtctld.WorkflowDelete(ctx, req.Request)
+}
+
+// WorkflowComplete is part of the vtadminpb.VTAdminServer interface.
+func (api *API) WorkflowComplete(ctx context.Context, req *vtadminpb.WorkflowCompleteRequest) (*vtctldatapb.MoveTablesCompleteResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.WorkflowComplete")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	if req.Request == nil {
+		return nil, fmt.Errorf("%w: request cannot be nil", errors.ErrInvalidRequest)
+	}
+
+	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.WorkflowResource, rbac.CompleteAction) {
+		return nil, fmt.Errorf("%w: cannot complete workflow in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	// MoveTablesComplete also completes Reshard workflows, which is what
+	// `vtctldclient Reshard complete` uses.
+	return c.Vtctld.MoveTablesComplete(ctx, req.Request)
+}
+
 // WorkflowDelete is part of the vtadminpb.VTAdminServer interface.
 func (api *API) WorkflowDelete(ctx context.Context, req *vtadminpb.WorkflowDeleteRequest) (*vtctldatapb.WorkflowDeleteResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "API.WorkflowDelete")
@@ -2869,7 +3034,7 @@ func (api *API) WorkflowSwitchTraffic(ctx context.Context, req *vtadminpb.Workfl
 
 	span.Annotate("cluster_id", req.ClusterId)
 
-	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.WorkflowResource, rbac.CreateAction) {
+	if !api.isAuthorizedToSwitchTraffic(ctx, req.ClusterId) {
 		return nil, fmt.Errorf("%w: cannot switch traffic for workflow in %s", errors.ErrUnauthorized, req.ClusterId)
 	}
 
@@ -2878,9 +3043,73 @@ func (api *API) WorkflowSwitchTraffic(ctx context.Context, req *vtadminpb.Workfl
 		return nil, err
 	}
 
+	setSwitchTrafficDefaults(req.Request)
+
 	return c.Vtctld.WorkflowSwitchTraffic(ctx, req.Request)
 }
 
+// WorkflowReverseTraffic is part of the vtadminpb.VTAdminServer interface.
+func (api *API) WorkflowReverseTraffic(ctx context.Context, req *vtadminpb.WorkflowSwitchTrafficRequest) (*vtctldatapb.WorkflowSwitchTrafficResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.WorkflowReverseTraffic")
+	defer span.Finish()
+
+	span.Annotate("cluster_id", req.ClusterId)
+
+	if !api.isAuthorizedToSwitchTraffic(ctx, req.ClusterId) {
+		return nil, fmt.Errorf("%w: cannot reverse traffic for workflow in %s", errors.ErrUnauthorized, req.ClusterId)
+	}
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Request == nil {
+		req.Request = &vtctldatapb.WorkflowSwitchTrafficRequest{}
+	}
+
+	setSwitchTrafficDefaults(req.Request)
+	req.Request.Direction = int32(workflow.DirectionBackward)
+
+	return c.Vtctld.WorkflowSwitchTraffic(ctx, req.Request)
+}
+
+// isAuthorizedToSwitchTraffic returns whether the actor in the context may
+// switch or reverse the traffic of the workflows in the cluster.
+//
+// Switching traffic used to require the create action on workflows. Until
+// v24, that action still grants it, with a warning. Either way, the request is
+// audited as a single check of the switch traffic action.
+func (api *API) isAuthorizedToSwitchTraffic(ctx context.Context, clusterID string) bool {
+	authorized, byCreate := api.authz.IsAuthorizedWithFallback(ctx, clusterID, rbac.WorkflowResource, rbac.SwitchWorkflowTrafficAction, rbac.CreateAction)
+	if !byCreate {
+		return authorized
+	}
+
+	name := "unknown"
+	if actor, ok := rbac.FromContext(ctx); ok {
+		name = actor.Name
+	}
+	log.Warningf("actor %s is authorized to switch workflow traffic in cluster %s by the create action, which is deprecated for it and will not be accepted in v24; grant the %s action on %s instead",
+		name, clusterID, rbac.SwitchWorkflowTrafficAction, rbac.WorkflowResource)
+
+	return true
+}
+
+// setSwitchTrafficDefaults switches the traffic of all tablet types when
+// none are given, like `vtctldclient SwitchTraffic` and `ReverseTraffic` do.
+func setSwitchTrafficDefaults(req *vtctldatapb.WorkflowSwitchTrafficRequest) {
+	if req == nil || len(req.TabletTypes) > 0 {
+		return
+	}
+
+	req.TabletTypes = []topodatapb.TabletType{
+		topodatapb.TabletType_PRIMARY,
+		topodatapb.TabletType_REPLICA,
+		topodatapb.TabletType_RDONLY,
+	}
+}
+
 func (api *API) getClusterForRequest(id string) (*cluster.Cluster, error) {
 	api.clusterMu.Lock()
 	defer api.clusterMu.Unlock()
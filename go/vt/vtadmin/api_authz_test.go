@@ -3453,6 +3453,266 @@ func TestValidateVersionKeyspace(t *testing.T) {
 	})
 }
 
+func TestWorkflowCancel(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Workflow",
+					Actions:  []string{"cancel"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(vtenv.NewTestEnv(), testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.WorkflowCancel(ctx, &vtadminpb.WorkflowCancelRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.WorkflowDeleteRequest{
+				Keyspace: "test",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to WorkflowCancel", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to WorkflowCancel", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.WorkflowCancel(ctx, &vtadminpb.WorkflowCancelRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.WorkflowDeleteRequest{
+				Keyspace: "test",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to WorkflowCancel", actor)
+	})
+}
+
+func TestWorkflowComplete(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Workflow",
+					Actions:  []string{"complete"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(vtenv.NewTestEnv(), testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.WorkflowComplete(ctx, &vtadminpb.WorkflowCompleteRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.MoveTablesCompleteRequest{
+				TargetKeyspace: "test",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to WorkflowComplete", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to WorkflowComplete", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.WorkflowComplete(ctx, &vtadminpb.WorkflowCompleteRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.MoveTablesCompleteRequest{
+				TargetKeyspace: "test",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to WorkflowComplete", actor)
+	})
+}
+
+func TestWorkflowReverseTraffic(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Workflow",
+					Actions:  []string{"switch_workflow_traffic"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(vtenv.NewTestEnv(), testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.WorkflowReverseTraffic(ctx, &vtadminpb.WorkflowSwitchTrafficRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.WorkflowSwitchTrafficRequest{
+				Keyspace: "test",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to WorkflowReverseTraffic", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to WorkflowReverseTraffic", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.WorkflowReverseTraffic(ctx, &vtadminpb.WorkflowSwitchTrafficRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.WorkflowSwitchTrafficRequest{
+				Keyspace: "test",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to WorkflowReverseTraffic", actor)
+	})
+}
+
+func TestWorkflowSwitchTraffic(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Workflow",
+					Actions:  []string{"switch_workflow_traffic"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(vtenv.NewTestEnv(), testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.WorkflowSwitchTraffic(ctx, &vtadminpb.WorkflowSwitchTrafficRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.WorkflowSwitchTrafficRequest{
+				Keyspace: "test",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to WorkflowSwitchTraffic", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to WorkflowSwitchTraffic", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.WorkflowSwitchTraffic(ctx, &vtadminpb.WorkflowSwitchTrafficRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.WorkflowSwitchTrafficRequest{
+				Keyspace: "test",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to WorkflowSwitchTraffic", actor)
+	})
+}
+
 func testClusters(t testing.TB) []*cluster.Cluster {
 	configs := []testutil.TestClusterConfig{
 		{
@@ -3670,6 +3930,14 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 						Response: &vtctldatapb.LaunchSchemaMigrationResponse{},
 					},
 				},
+				MoveTablesCompleteResults: map[string]struct {
+					Response *vtctldatapb.MoveTablesCompleteResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.MoveTablesCompleteResponse{},
+					},
+				},
 				PingTabletResults: map[string]error{
 					"zone1-0000000100": nil,
 				},
@@ -3761,6 +4029,22 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 						Response: &vtctldatapb.ValidateVersionKeyspaceResponse{},
 					},
 				},
+				WorkflowDeleteResults: map[string]struct {
+					Response *vtctldatapb.WorkflowDeleteResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.WorkflowDeleteResponse{},
+					},
+				},
+				WorkflowSwitchTrafficResults: map[string]struct {
+					Response *vtctldatapb.WorkflowSwitchTrafficResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.WorkflowSwitchTrafficResponse{},
+					},
+				},
 			},
 			Tablets: []*vtadminpb.Tablet{
 				{
@@ -26,22 +26,28 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
 
 	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vtgate"
 
 	_flag "vitess.io/vitess/go/internal/flag"
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtadmin/audit"
 	"vitess.io/vitess/go/vt/vtadmin/cluster"
 	"vitess.io/vitess/go/vt/vtadmin/cluster/discovery/fakediscovery"
 	vtadminerrors "vitess.io/vitess/go/vt/vtadmin/errors"
+	"vitess.io/vitess/go/vt/vtadmin/rbac"
 	vtadmintestutil "vitess.io/vitess/go/vt/vtadmin/testutil"
 	"vitess.io/vitess/go/vt/vtadmin/vtctldclient/fakevtctldclient"
 	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
@@ -817,6 +823,104 @@ func TestFindSchema(t *testing.T) {
 	})
 }
 
+func TestGetAuditRecords(t *testing.T) {
+	t.Parallel()
+
+	sink, err := audit.NewFileSink(filepath.Join(t.TempDir(), "audit.log"))
+	require.NoError(t, err)
+	t.Cleanup(func() { sink.Close() })
+
+	ctx := context.Background()
+	for _, clusterID := range []string{"test", "other", "other"} {
+		require.NoError(t, sink.Write(ctx, &vtadminpb.AuditRecord{
+			Time:      protoutil.TimeToProto(time.Now()),
+			Actor:     "someone",
+			ClusterId: clusterID,
+			Resource:  "Tablet",
+			Action:    "manage_tablet_writability",
+		}))
+	}
+
+	opts := Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "AuditRecord",
+					Actions:  []string{"get"},
+					Subjects: []string{"user:allowed-all"},
+					Clusters: []string{"*"},
+				},
+				{
+					Resource: "AuditRecord",
+					Actions:  []string{"get"},
+					Subjects: []string{"user:allowed-other"},
+					Clusters: []string{"other"},
+				},
+			},
+		},
+		AuditSink: sink,
+	}
+	err = opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := NewAPI(vtenv.NewTestEnv(), []*cluster.Cluster{
+		{
+			ID:        "test",
+			Name:      "test",
+			Discovery: fakediscovery.New(),
+		},
+		{
+			ID:        "other",
+			Name:      "other",
+			Discovery: fakediscovery.New(),
+		},
+	}, opts)
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "unauthorized"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.GetAuditRecords(ctx, &vtadminpb.GetAuditRecordsRequest{})
+		assert.NoError(t, err)
+		assert.Empty(t, resp.Records, "actor %+v should not be permitted to GetAuditRecords", actor)
+	})
+
+	t.Run("partial access", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed-other"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.GetAuditRecords(ctx, &vtadminpb.GetAuditRecordsRequest{})
+		require.NoError(t, err)
+		assert.Len(t, resp.Records, 2, "'other' actor should be able to see the 2 audit records in cluster 'other'")
+
+		resp, err = api.GetAuditRecords(ctx, &vtadminpb.GetAuditRecordsRequest{ClusterIds: []string{"test"}})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Records, "'other' actor should not be able to see the audit records in cluster 'test'")
+	})
+
+	t.Run("full access", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed-all"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.GetAuditRecords(ctx, &vtadminpb.GetAuditRecordsRequest{})
+		require.NoError(t, err)
+		assert.Len(t, resp.Records, 3, "'all' actor should be able to see audit records in all clusters")
+	})
+}
+
 func TestGetClusters(t *testing.T) {
 	t.Parallel()
 
@@ -1338,6 +1442,82 @@ func TestGetKeyspaces(t *testing.T) {
 	}
 }
 
+func TestGetQueryInsights(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/queryz", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&vtgate.QueryzJSON{
+			ExecTimeBuckets: []time.Duration{time.Millisecond},
+			Queries: []*vtgate.QueryzJSONRow{
+				{
+					Query:     "select * from t1",
+					PlanType:  "Passthrough",
+					QueryType: "SELECT",
+					Tables:    []string{"ks.t1"},
+					Count:     3,
+					Time:      3 * time.Millisecond,
+					ExecTimes: []uint64{3, 0},
+				},
+			},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	fakedisco1 := fakediscovery.New()
+	fakedisco1.AddTaggedGates(nil, &vtadminpb.VTGate{
+		Hostname: "cluster1-gate1",
+		FQDN:     server.Listener.Addr().String(),
+	}, &vtadminpb.VTGate{
+		Hostname: "cluster1-gate2",
+		FQDN:     server.Listener.Addr().String(),
+	}, &vtadminpb.VTGate{
+		// Gates without an FQDN are reported as warnings.
+		Hostname: "cluster1-gate3",
+	})
+	cluster1 := &cluster.Cluster{
+		ID:        "c1",
+		Name:      "cluster1",
+		Discovery: fakedisco1,
+	}
+
+	fakedisco2 := fakediscovery.New()
+	fakedisco2.AddTaggedGates(nil, &vtadminpb.VTGate{
+		Hostname: "cluster2-gate1",
+	})
+	cluster2 := &cluster.Cluster{
+		ID:        "c2",
+		Name:      "cluster2",
+		Discovery: fakedisco2,
+	}
+
+	api := NewAPI(vtenv.NewTestEnv(), []*cluster.Cluster{cluster1, cluster2}, Options{})
+	ctx := context.Background()
+
+	resp, err := api.GetQueryInsights(ctx, &vtadminpb.GetQueryInsightsRequest{ClusterIds: []string{cluster1.ID}})
+	require.NoError(t, err)
+	require.Len(t, resp.Insights, 1)
+
+	insights := resp.Insights[0]
+	assert.Equal(t, cluster1.ID, insights.Cluster.Id)
+	assert.EqualValues(t, 2, insights.Gates)
+	require.Len(t, insights.Warnings, 1)
+	assert.Contains(t, insights.Warnings[0], "cluster1-gate3")
+
+	require.Len(t, insights.Queries, 1)
+	assert.Equal(t, "select * from t1", insights.Queries[0].Query)
+	assert.EqualValues(t, 2, insights.Queries[0].Gates)
+	assert.EqualValues(t, 6, insights.Queries[0].Metrics.Count)
+
+	require.Len(t, insights.Tables, 1)
+	assert.Equal(t, "ks.t1", insights.Tables[0].Table)
+
+	// Every gate of cluster2 fails.
+	_, err = api.GetQueryInsights(ctx, &vtadminpb.GetQueryInsightsRequest{})
+	assert.Error(t, err)
+}
+
 func TestGetSchema(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -5334,6 +5514,114 @@ type ServeHTTPResult struct {
 	Clusters []*vtadminpb.Cluster `json:"clusters"`
 }
 
+func TestWorkflowSwitchTrafficDeprecatedCreateAction(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Workflow",
+					Actions:  []string{"create"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := NewAPI(vtenv.NewTestEnv(), []*cluster.Cluster{
+		vtadmintestutil.BuildCluster(t, vtadmintestutil.TestClusterConfig{
+			Cluster: &vtadminpb.Cluster{
+				Id:   "test",
+				Name: "test",
+			},
+			VtctldClient: &fakevtctldclient.VtctldClient{
+				WorkflowSwitchTrafficResults: map[string]struct {
+					Response *vtctldatapb.WorkflowSwitchTrafficResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.WorkflowSwitchTrafficResponse{},
+					},
+				},
+			},
+		}),
+	}, opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	// Until v24, the create action still grants switching and reversing
+	// traffic, which used to require it. Each request is audited as a
+	// single check of the switch traffic action.
+	ctx, checks := rbac.NewAuthorizationChecksContext(rbac.NewContext(context.Background(), &rbac.Actor{Name: "allowed"}))
+	req := &vtadminpb.WorkflowSwitchTrafficRequest{
+		ClusterId: "test",
+		Request: &vtctldatapb.WorkflowSwitchTrafficRequest{
+			Keyspace: "test",
+		},
+	}
+	resp, err := api.WorkflowSwitchTraffic(ctx, req)
+	require.NoError(t, err)
+	assert.NotNil(t, resp)
+
+	resp, err = api.WorkflowReverseTraffic(ctx, req)
+	require.NoError(t, err)
+	assert.NotNil(t, resp)
+
+	allowed := rbac.AuthorizationCheck{
+		ClusterID:  "test",
+		Resource:   rbac.WorkflowResource,
+		Action:     rbac.SwitchWorkflowTrafficAction,
+		Authorized: true,
+	}
+	assert.Equal(t, []rbac.AuthorizationCheck{allowed, allowed}, checks())
+
+	ctx, checks = rbac.NewAuthorizationChecksContext(rbac.NewContext(context.Background(), &rbac.Actor{Name: "other"}))
+	_, err = api.WorkflowSwitchTraffic(ctx, req)
+	assert.ErrorIs(t, err, vtadminerrors.ErrUnauthorized)
+
+	denied := allowed
+	denied.Authorized = false
+	assert.Equal(t, []rbac.AuthorizationCheck{denied}, checks())
+}
+
+func TestWorkflowCancelAndCompleteRequireRequest(t *testing.T) {
+	t.Parallel()
+
+	api := NewAPI(vtenv.NewTestEnv(), []*cluster.Cluster{
+		vtadmintestutil.BuildCluster(t, vtadmintestutil.TestClusterConfig{
+			Cluster: &vtadminpb.Cluster{
+				Id:   "test",
+				Name: "test",
+			},
+			VtctldClient: &fakevtctldclient.VtctldClient{},
+		}),
+	}, Options{})
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	ctx := context.Background()
+	_, err := api.WorkflowCancel(ctx, &vtadminpb.WorkflowCancelRequest{ClusterId: "test"})
+	assert.ErrorIs(t, err, vtadminerrors.ErrInvalidRequest)
+
+	_, err = api.WorkflowComplete(ctx, &vtadminpb.WorkflowCompleteRequest{ClusterId: "test"})
+	assert.ErrorIs(t, err, vtadminerrors.ErrInvalidRequest)
+}
+
 func TestServeHTTP(t *testing.T) {
 	t.Parallel()
 
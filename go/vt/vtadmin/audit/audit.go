@@ -0,0 +1,292 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package audit records the mutating actions taken through the vtadmin API.
+
+Every request to the API is checked against the rbac rules, once per
+<cluster, resource, action> it touches. The gRPC interceptor and the HTTP
+middleware of this package collect those checks, and write one AuditRecord
+per mutating <cluster, resource, action> to a Sink once the request is done,
+with the actor, the request payload and the outcome. Actions other than "get"
+and "ping" are mutating.
+
+Sinks are pluggable. VTAdmin ships a "file" sink (JSON lines), a "syslog" sink,
+and a "table" sink writing to a table in one of the clusters. Other sinks may
+be registered at runtime via RegisterSink. Sinks which also implement Querier
+serve the GetAuditRecords API.
+*/
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vtadmin/rbac"
+	"vitess.io/vitess/go/vt/vtadmin/vtsql"
+
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+)
+
+// DefaultLimit is the number of records returned by a query which does not
+// set a limit.
+const DefaultLimit = 100
+
+var (
+	// ErrNotQueryable is returned when querying the audit records of a sink
+	// which does not implement Querier.
+	ErrNotQueryable = errors.New("audit sink does not support queries")
+	// ErrUnregisteredSink is returned by NewSink for an unknown sink name.
+	ErrUnregisteredSink = errors.New("unregistered audit sink")
+)
+
+// Sink is where the audit records are written.
+type Sink interface {
+	// Write writes an audit record. It is called once the audited request is
+	// done.
+	Write(ctx context.Context, record *vtadminpb.AuditRecord) error
+	// Close releases the resources of the sink.
+	Close() error
+}
+
+// Querier is implemented by sinks which can read back their audit records.
+type Querier interface {
+	// Query returns the records matching the request, newest first, up to
+	// the limit of the request (or DefaultLimit).
+	Query(ctx context.Context, req *vtadminpb.GetAuditRecordsRequest) ([]*vtadminpb.AuditRecord, error)
+}
+
+// Options configure the built-in sinks. Sinks registered via RegisterSink
+// receive them as well.
+type Options struct {
+	// FilePath is the file the "file" sink appends to.
+	FilePath string
+	// SyslogTag is the tag of the messages of the "syslog" sink. Defaults to
+	// "vtadmin".
+	SyslogTag string
+	// DB is the database of the cluster the "table" sink writes to.
+	DB vtsql.DB
+	// Table is the table the "table" sink writes to, optionally qualified by
+	// its keyspace. Defaults to DefaultTable.
+	Table string
+}
+
+// SinkFactory creates a sink from the given options.
+type SinkFactory func(opts Options) (Sink, error)
+
+var (
+	sinks = map[string]SinkFactory{
+		"file": func(opts Options) (Sink, error) {
+			sink, err := NewFileSink(opts.FilePath)
+			if err != nil {
+				return nil, err
+			}
+			return sink, nil
+		},
+		"syslog": func(opts Options) (Sink, error) {
+			sink, err := NewSyslogSink(opts.SyslogTag)
+			if err != nil {
+				return nil, err
+			}
+			return sink, nil
+		},
+		"table": func(opts Options) (Sink, error) {
+			sink, err := NewTableSink(opts.DB, opts.Table)
+			if err != nil {
+				return nil, err
+			}
+			return sink, nil
+		},
+	}
+	sinksM sync.Mutex
+)
+
+// RegisterSink registers a sink implementation by name, so it can be selected
+// with --audit-sink.
+func RegisterSink(name string, factory SinkFactory) {
+	sinksM.Lock()
+	defer sinksM.Unlock()
+
+	if _, ok := sinks[name]; ok {
+		panic(fmt.Sprintf("audit sink already registered with name: %s", name))
+	}
+
+	sinks[name] = factory
+}
+
+// NewSink returns the sink registered with the given name.
+func NewSink(name string, opts Options) (Sink, error) {
+	sinksM.Lock()
+	factory, ok := sinks[name]
+	sinksM.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnregisteredSink, name)
+	}
+
+	return factory(opts)
+}
+
+// Query returns the audit records of the sink matching the request.
+func Query(ctx context.Context, sink Sink, req *vtadminpb.GetAuditRecordsRequest) ([]*vtadminpb.AuditRecord, error) {
+	querier, ok := sink.(Querier)
+	if !ok {
+		return nil, ErrNotQueryable
+	}
+
+	return querier.Query(ctx, req)
+}
+
+// IsMutating returns whether an action changes the state of a cluster, and
+// should therefore be audited.
+func IsMutating(action rbac.Action) bool {
+	switch action {
+	case rbac.GetAction, rbac.PingAction:
+		return false
+	}
+
+	return true
+}
+
+// record writes an audit record for each mutating <cluster, resource, action>
+// the request was checked for. The request payload is only built if there is
+// something to record. Failing to write a record is logged, and does not fail
+// the request.
+func record(ctx context.Context, sink Sink, method string, request func() string, checks []rbac.AuthorizationCheck, err error) {
+	recs := records(ctx, method, checks, err)
+	if len(recs) == 0 {
+		return
+	}
+
+	payload := request()
+	for _, rec := range recs {
+		rec.Request = payload
+		if err := sink.Write(ctx, rec); err != nil {
+			log.Errorf("failed to write audit record for %s by %s in %s: %v", rec.Method, rec.Actor, rec.ClusterId, err)
+		}
+	}
+}
+
+func records(ctx context.Context, method string, checks []rbac.AuthorizationCheck, err error) []*vtadminpb.AuditRecord {
+	type key struct {
+		clusterID string
+		resource  rbac.Resource
+		action    rbac.Action
+	}
+
+	// A request may be checked for the same tuple more than once, for
+	// example when looking up a tablet across clusters. It is authorized if
+	// any of those checks passed.
+	authorized := map[key]bool{}
+	var keys []key
+	for _, check := range checks {
+		if !IsMutating(check.Action) {
+			continue
+		}
+
+		k := key{check.ClusterID, check.Resource, check.Action}
+		if _, ok := authorized[k]; !ok {
+			keys = append(keys, k)
+		}
+
+		authorized[k] = authorized[k] || check.Authorized
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	var (
+		actorName string
+		roles     []string
+	)
+	if actor, ok := rbac.FromContext(ctx); ok && actor != nil {
+		actorName = actor.Name
+		roles = actor.Roles
+	}
+
+	now := protoutil.TimeToProto(time.Now())
+	records := make([]*vtadminpb.AuditRecord, 0, len(keys))
+	for _, k := range keys {
+		rec := &vtadminpb.AuditRecord{
+			Time:      now,
+			Actor:     actorName,
+			Roles:     roles,
+			Method:    method,
+			ClusterId: k.clusterID,
+			Resource:  string(k.resource),
+			Action:    string(k.action),
+		}
+
+		switch {
+		case !authorized[k]:
+			rec.Outcome = vtadminpb.AuditRecord_DENIED
+		case err != nil:
+			rec.Outcome = vtadminpb.AuditRecord_FAILURE
+			rec.Error = err.Error()
+		default:
+			rec.Outcome = vtadminpb.AuditRecord_SUCCESS
+		}
+
+		records = append(records, rec)
+	}
+
+	return records
+}
+
+// Matches returns whether a record matches the filters of a request.
+func Matches(req *vtadminpb.GetAuditRecordsRequest, rec *vtadminpb.AuditRecord) bool {
+	if len(req.ClusterIds) > 0 && !slices.Contains(req.ClusterIds, rec.ClusterId) {
+		return false
+	}
+
+	if req.Actor != "" && req.Actor != rec.Actor {
+		return false
+	}
+
+	if req.Resource != "" && req.Resource != rec.Resource {
+		return false
+	}
+
+	if req.Action != "" && req.Action != rec.Action {
+		return false
+	}
+
+	t := protoutil.TimeFromProto(rec.Time)
+	if req.Since != nil && t.Before(protoutil.TimeFromProto(req.Since)) {
+		return false
+	}
+
+	if req.Until != nil && !t.Before(protoutil.TimeFromProto(req.Until)) {
+		return false
+	}
+
+	return true
+}
+
+func limit(req *vtadminpb.GetAuditRecordsRequest) int {
+	if req.Limit == 0 {
+		return DefaultLimit
+	}
+
+	return int(req.Limit)
+}
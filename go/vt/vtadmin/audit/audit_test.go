@@ -0,0 +1,210 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/vtadmin/rbac"
+
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+)
+
+// memorySink keeps the records written to it, for tests.
+type memorySink struct {
+	m       sync.Mutex
+	records []*vtadminpb.AuditRecord
+}
+
+func (sink *memorySink) Write(ctx context.Context, record *vtadminpb.AuditRecord) error {
+	sink.m.Lock()
+	defer sink.m.Unlock()
+
+	sink.records = append(sink.records, record)
+	return nil
+}
+
+func (sink *memorySink) Close() error { return nil }
+
+func TestRecords(t *testing.T) {
+	t.Parallel()
+
+	ctx := rbac.NewContext(context.Background(), &rbac.Actor{Name: "alice", Roles: []string{"dba"}})
+	checks := []rbac.AuthorizationCheck{
+		{ClusterID: "c1", Resource: rbac.TabletResource, Action: rbac.GetAction, Authorized: true},
+		{ClusterID: "c1", Resource: rbac.TabletResource, Action: rbac.ManageTabletWritabilityAction, Authorized: false},
+		{ClusterID: "c1", Resource: rbac.TabletResource, Action: rbac.ManageTabletWritabilityAction, Authorized: true},
+		{ClusterID: "c2", Resource: rbac.TabletResource, Action: rbac.ManageTabletWritabilityAction, Authorized: false},
+	}
+
+	recs := records(ctx, "SetReadOnly", checks, nil)
+	require.Len(t, recs, 2, "get checks are not audited, and repeated checks are merged")
+	for _, rec := range recs {
+		assert.Equal(t, "alice", rec.Actor)
+		assert.Equal(t, []string{"dba"}, rec.Roles)
+		assert.Equal(t, "SetReadOnly", rec.Method)
+		assert.Equal(t, "Tablet", rec.Resource)
+		assert.Equal(t, "manage_tablet_writability", rec.Action)
+		assert.NotNil(t, rec.Time)
+	}
+	assert.Equal(t, "c1", recs[0].ClusterId)
+	assert.Equal(t, vtadminpb.AuditRecord_SUCCESS, recs[0].Outcome)
+	assert.Equal(t, "c2", recs[1].ClusterId)
+	assert.Equal(t, vtadminpb.AuditRecord_DENIED, recs[1].Outcome)
+
+	recs = records(ctx, "SetReadOnly", checks, errors.New("tablet is unreachable"))
+	require.Len(t, recs, 2)
+	assert.Equal(t, vtadminpb.AuditRecord_FAILURE, recs[0].Outcome)
+	assert.Equal(t, "tablet is unreachable", recs[0].Error)
+	assert.Equal(t, vtadminpb.AuditRecord_DENIED, recs[1].Outcome)
+	assert.Empty(t, recs[1].Error)
+
+	assert.Empty(t, records(ctx, "GetTablets", checks[:1], nil))
+}
+
+func TestRecord(t *testing.T) {
+	t.Parallel()
+
+	sink := &memorySink{}
+	built := 0
+	request := func() string {
+		built++
+		return `{"alias":"zone1-100"}`
+	}
+
+	record(context.Background(), sink, "GetTablet", request, []rbac.AuthorizationCheck{
+		{ClusterID: "c1", Resource: rbac.TabletResource, Action: rbac.GetAction, Authorized: true},
+	}, nil)
+	assert.Empty(t, sink.records)
+	assert.Zero(t, built, "the request payload should only be built when there is something to record")
+
+	record(context.Background(), sink, "DeleteTablet", request, []rbac.AuthorizationCheck{
+		{ClusterID: "c1", Resource: rbac.TabletResource, Action: rbac.DeleteAction, Authorized: true},
+	}, nil)
+	require.Len(t, sink.records, 1)
+	assert.Equal(t, 1, built)
+	assert.Equal(t, `{"alias":"zone1-100"}`, sink.records[0].Request)
+	assert.Empty(t, sink.records[0].Actor)
+}
+
+func TestMatches(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	rec := &vtadminpb.AuditRecord{
+		Time:      protoutil.TimeToProto(now),
+		Actor:     "alice",
+		ClusterId: "c1",
+		Resource:  "Shard",
+		Action:    "emergency_failover_shard",
+	}
+
+	tests := []struct {
+		name     string
+		req      *vtadminpb.GetAuditRecordsRequest
+		expected bool
+	}{
+		{
+			name:     "no filters",
+			req:      &vtadminpb.GetAuditRecordsRequest{},
+			expected: true,
+		},
+		{
+			name: "all filters",
+			req: &vtadminpb.GetAuditRecordsRequest{
+				ClusterIds: []string{"c2", "c1"},
+				Actor:      "alice",
+				Resource:   "Shard",
+				Action:     "emergency_failover_shard",
+				Since:      protoutil.TimeToProto(now),
+				Until:      protoutil.TimeToProto(now.Add(time.Second)),
+			},
+			expected: true,
+		},
+		{
+			name:     "other cluster",
+			req:      &vtadminpb.GetAuditRecordsRequest{ClusterIds: []string{"c2"}},
+			expected: false,
+		},
+		{
+			name:     "other actor",
+			req:      &vtadminpb.GetAuditRecordsRequest{Actor: "bob"},
+			expected: false,
+		},
+		{
+			name:     "other resource",
+			req:      &vtadminpb.GetAuditRecordsRequest{Resource: "Tablet"},
+			expected: false,
+		},
+		{
+			name:     "other action",
+			req:      &vtadminpb.GetAuditRecordsRequest{Action: "planned_failover_shard"},
+			expected: false,
+		},
+		{
+			name:     "too old",
+			req:      &vtadminpb.GetAuditRecordsRequest{Since: protoutil.TimeToProto(now.Add(time.Second))},
+			expected: false,
+		},
+		{
+			name:     "until is exclusive",
+			req:      &vtadminpb.GetAuditRecordsRequest{Until: protoutil.TimeToProto(now)},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Matches(tt.req, rec))
+		})
+	}
+}
+
+func TestNewSink(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewSink("nonexistent", Options{})
+	assert.ErrorIs(t, err, ErrUnregisteredSink)
+
+	_, err = NewSink("file", Options{})
+	assert.ErrorContains(t, err, "requires --audit-file")
+
+	_, err = NewSink("table", Options{})
+	assert.ErrorContains(t, err, "requires --audit-table-cluster")
+
+	// The name is unique so the test can run more than once in a process.
+	name := fmt.Sprintf("memory-%d", time.Now().UnixNano())
+	RegisterSink(name, func(opts Options) (Sink, error) { return &memorySink{}, nil })
+	sink, err := NewSink(name, Options{})
+	require.NoError(t, err)
+
+	_, err = Query(context.Background(), sink, &vtadminpb.GetAuditRecordsRequest{})
+	assert.ErrorIs(t, err, ErrNotQueryable)
+
+	assert.Panics(t, func() {
+		RegisterSink(name, func(opts Options) (Sink, error) { return &memorySink{}, nil })
+	})
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+)
+
+// maxLineLength is the longest record the FileSink reads back.
+const maxLineLength = 16 * 1024 * 1024
+
+// FileSink appends the audit records to a file, one JSON object per line. It
+// implements Querier by scanning the file.
+type FileSink struct {
+	path string
+
+	m    sync.Mutex
+	file *os.File
+}
+
+var (
+	_ Sink    = (*FileSink)(nil)
+	_ Querier = (*FileSink)(nil)
+)
+
+// NewFileSink returns a FileSink appending to the file at path, which is
+// created if needed.
+func NewFileSink(path string) (*FileSink, error) {
+	if path == "" {
+		return nil, errors.New("file audit sink requires --audit-file")
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file: %w", err)
+	}
+
+	return &FileSink{path: path, file: file}, nil
+}
+
+// Write is part of the Sink interface.
+func (sink *FileSink) Write(ctx context.Context, record *vtadminpb.AuditRecord) error {
+	line, err := protojson.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	sink.m.Lock()
+	defer sink.m.Unlock()
+
+	if sink.file == nil {
+		return os.ErrClosed
+	}
+
+	_, err = sink.file.Write(append(line, '\n'))
+	return err
+}
+
+// Query is part of the Querier interface.
+func (sink *FileSink) Query(ctx context.Context, req *vtadminpb.GetAuditRecordsRequest) ([]*vtadminpb.AuditRecord, error) {
+	// Hold the lock so a concurrent Write is not read half-written.
+	sink.m.Lock()
+	defer sink.m.Unlock()
+
+	file, err := os.Open(sink.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// The records are appended in order, so the newest are at the end of
+	// the file. Keep the last n matching ones.
+	n := limit(req)
+	records := make([]*vtadminpb.AuditRecord, 0, n)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxLineLength)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		record := &vtadminpb.AuditRecord{}
+		if err := protojson.Unmarshal(scanner.Bytes(), record); err != nil {
+			return nil, fmt.Errorf("failed to parse audit file %s: %w", sink.path, err)
+		}
+
+		if !Matches(req, record) {
+			continue
+		}
+
+		if len(records) == n {
+			records = append(records[:0], records[1:]...)
+		}
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(records)
+	return records, nil
+}
+
+// Close is part of the Sink interface.
+func (sink *FileSink) Close() error {
+	sink.m.Lock()
+	defer sink.m.Unlock()
+
+	if sink.file == nil {
+		return nil
+	}
+
+	err := sink.file.Close()
+	sink.file = nil
+	return err
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+)
+
+func TestFileSink(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		clusterID := "c1"
+		if i%2 == 1 {
+			clusterID = "c2"
+		}
+
+		require.NoError(t, sink.Write(ctx, &vtadminpb.AuditRecord{
+			Time:      protoutil.TimeToProto(start.Add(time.Duration(i) * time.Second)),
+			Actor:     "alice",
+			Method:    fmt.Sprintf("Method%d", i),
+			ClusterId: clusterID,
+			Resource:  "Tablet",
+			Action:    "delete",
+			Request:   `{"alias":"zone1-100"}`,
+			Outcome:   vtadminpb.AuditRecord_SUCCESS,
+		}))
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(data)), "\n"), 5, "there should be one record per line")
+
+	methods := func(records []*vtadminpb.AuditRecord) []string {
+		var methods []string
+		for _, rec := range records {
+			methods = append(methods, rec.Method)
+		}
+		return methods
+	}
+
+	records, err := sink.Query(ctx, &vtadminpb.GetAuditRecordsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Method4", "Method3", "Method2", "Method1", "Method0"}, methods(records), "records should be newest first")
+	assert.Equal(t, `{"alias":"zone1-100"}`, records[0].Request)
+
+	records, err = sink.Query(ctx, &vtadminpb.GetAuditRecordsRequest{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Method4", "Method3"}, methods(records))
+
+	records, err = sink.Query(ctx, &vtadminpb.GetAuditRecordsRequest{
+		ClusterIds: []string{"c1"},
+		Since:      protoutil.TimeToProto(start.Add(time.Second)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Method4", "Method2"}, methods(records))
+
+	// Records are appended to an existing file.
+	require.NoError(t, sink.Close())
+	sink, err = NewFileSink(path)
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(ctx, &vtadminpb.AuditRecord{
+		Time:   protoutil.TimeToProto(start.Add(time.Minute)),
+		Method: "Method5",
+	}))
+
+	records, err = sink.Query(ctx, &vtadminpb.GetAuditRecordsRequest{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Method5"}, methods(records))
+}
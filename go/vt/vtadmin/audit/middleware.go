@@ -0,0 +1,191 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/vt/vtadmin/rbac"
+)
+
+const (
+	// maxRequestBodyLength is how much of the body of an HTTP request is kept
+	// in its audit records.
+	maxRequestBodyLength = 64 * 1024
+	// maxErrorBodyLength is how much of the body of a failed HTTP response is
+	// read to find its error message.
+	maxErrorBodyLength = 4 * 1024
+)
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor which writes
+// the audit records of each request to the sink. It must run after the
+// authentication interceptor, so the actor is known.
+func UnaryServerInterceptor(sink Sink) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, checks := rbac.NewAuthorizationChecksContext(ctx)
+		resp, err := handler(ctx, req)
+
+		method := info.FullMethod
+		if i := strings.LastIndex(method, "/"); i >= 0 {
+			method = method[i+1:]
+		}
+
+		record(ctx, sink, method, func() string {
+			msg, ok := req.(proto.Message)
+			if !ok {
+				return ""
+			}
+
+			payload, err := protojson.Marshal(msg)
+			if err != nil {
+				return ""
+			}
+
+			return string(payload)
+		}, checks(), err)
+
+		return resp, err
+	}
+}
+
+// HTTPMiddleware returns an http middleware which writes the audit records of
+// each request to the sink. It must run after the authentication middleware,
+// so the actor is known, and on the router the API routes are registered on,
+// so the method is known.
+//
+// The request payload of the records is a JSON object with the HTTP method,
+// the path, the query and the body of the request. Responses with a status
+// of 400 and above are failures.
+func HTTPMiddleware(sink Sink) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, checks := rbac.NewAuthorizationChecksContext(r.Context())
+
+			var body []byte
+			if r.Body != nil {
+				body, _ = io.ReadAll(io.LimitReader(r.Body, maxRequestBodyLength))
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+			}
+
+			rw := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			r = r.WithContext(ctx)
+			next.ServeHTTP(rw, r)
+
+			record(ctx, sink, httpMethod(r), func() string {
+				return httpPayload(r, body)
+			}, checks(), rw.err())
+		})
+	}
+}
+
+// httpMethod returns the name of the API method of a request, which is the
+// name of its route without the "API." prefix.
+func httpMethod(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if name := route.GetName(); name != "" {
+			return strings.TrimPrefix(name, "API.")
+		}
+	}
+
+	return r.Method + " " + r.URL.Path
+}
+
+func httpPayload(r *http.Request, body []byte) string {
+	payload := struct {
+		Method string `json:"method"`
+		Path   string `json:"path"`
+		Query  string `json:"query,omitempty"`
+		Body   any    `json:"body,omitempty"`
+	}{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+	}
+
+	if len(body) > 0 {
+		if json.Valid(body) {
+			payload.Body = json.RawMessage(body)
+		} else {
+			payload.Body = string(body)
+		}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
+
+// responseRecorder records the status of a response, and the start of its
+// body if it failed.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rw *responseRecorder) WriteHeader(status int) {
+	rw.status = status
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *responseRecorder) Write(p []byte) (int, error) {
+	if rw.status >= http.StatusBadRequest && rw.body.Len() < maxErrorBodyLength {
+		rw.body.Write(p[:min(len(p), maxErrorBodyLength-rw.body.Len())])
+	}
+
+	return rw.ResponseWriter.Write(p)
+}
+
+// err returns the error of a failed response, taken from the error message
+// of its JSON body if it has one.
+func (rw *responseRecorder) err() error {
+	if rw.status < http.StatusBadRequest {
+		return nil
+	}
+
+	var resp struct {
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rw.body.Bytes(), &resp); err == nil && resp.Error != nil && resp.Error.Message != "" {
+		return errors.New(resp.Error.Message)
+	}
+
+	if msg := strings.TrimSpace(rw.body.String()); msg != "" {
+		return errors.New(msg)
+	}
+
+	return errors.New(http.StatusText(rw.status))
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"vitess.io/vitess/go/vt/vtadmin/rbac"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+)
+
+func testAuthorizer(t *testing.T) *rbac.Authorizer {
+	authz, err := rbac.NewAuthorizer(&rbac.Config{
+		Rules: []*struct {
+			Resource string
+			Actions  []string
+			Subjects []string
+			Clusters []string
+		}{
+			{
+				Resource: "Tablet",
+				Actions:  []string{"*"},
+				Subjects: []string{"user:alice"},
+				Clusters: []string{"c1"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	return authz
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	authz := testAuthorizer(t)
+	sink := &memorySink{}
+	interceptor := UnaryServerInterceptor(sink)
+
+	req := &vtadminpb.SetReadOnlyRequest{
+		Alias:      &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		ClusterIds: []string{"c1", "c2"},
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/vtadmin.VTAdmin/SetReadOnly"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		for _, clusterID := range []string{"c1", "c2"} {
+			authz.IsAuthorized(ctx, clusterID, rbac.TabletResource, rbac.GetAction)
+			authz.IsAuthorized(ctx, clusterID, rbac.TabletResource, rbac.ManageTabletWritabilityAction)
+		}
+
+		return nil, errors.New("tablet is unreachable")
+	}
+
+	ctx := rbac.NewContext(context.Background(), &rbac.Actor{Name: "alice"})
+	_, err := interceptor(ctx, req, info, handler)
+	assert.ErrorContains(t, err, "tablet is unreachable")
+
+	require.Len(t, sink.records, 2)
+	assert.Equal(t, "SetReadOnly", sink.records[0].Method)
+	assert.Equal(t, "alice", sink.records[0].Actor)
+	assert.Equal(t, "c1", sink.records[0].ClusterId)
+	assert.Equal(t, vtadminpb.AuditRecord_FAILURE, sink.records[0].Outcome)
+	assert.Equal(t, "c2", sink.records[1].ClusterId)
+	assert.Equal(t, vtadminpb.AuditRecord_DENIED, sink.records[1].Outcome)
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal([]byte(sink.records[0].Request), &payload))
+	assert.Equal(t, []any{"c1", "c2"}, payload["clusterIds"])
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	t.Parallel()
+
+	authz := testAuthorizer(t)
+	sink := &memorySink{}
+
+	router := mux.NewRouter().PathPrefix("/api").Subrouter()
+	router.Use(func(next http.Handler) http.Handler {
+		// Stands in for the authentication middleware.
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(rbac.NewContext(r.Context(), &rbac.Actor{Name: "alice"})))
+		})
+	})
+	router.Use(HTTPMiddleware(sink))
+	router.HandleFunc("/tablet/{tablet}", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"allow_primary":true}`, string(body), "the handler should still see the whole body")
+
+		authz.IsAuthorized(r.Context(), "c1", rbac.TabletResource, rbac.DeleteAction)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":{"message":"tablet is primary","code":"unknown"},"ok":false}`))
+	}).Name("API.DeleteTablet").Methods("DELETE")
+	router.HandleFunc("/tablets", func(w http.ResponseWriter, r *http.Request) {
+		authz.IsAuthorized(r.Context(), "c1", rbac.TabletResource, rbac.GetAction)
+		w.Write([]byte(`{"ok":true}`))
+	}).Name("API.GetTablets")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tablets", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, sink.records, "reads should not be audited")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/tablet/zone1-100?cluster_id=c1", strings.NewReader(`{"allow_primary":true}`)))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "tablet is primary", "the response should be written through")
+
+	require.Len(t, sink.records, 1)
+	rec := sink.records[0]
+	assert.Equal(t, "DeleteTablet", rec.Method)
+	assert.Equal(t, "alice", rec.Actor)
+	assert.Equal(t, "c1", rec.ClusterId)
+	assert.Equal(t, "Tablet", rec.Resource)
+	assert.Equal(t, "delete", rec.Action)
+	assert.Equal(t, vtadminpb.AuditRecord_FAILURE, rec.Outcome)
+	assert.Equal(t, "tablet is primary", rec.Error)
+	assert.JSONEq(t, `{"method":"DELETE","path":"/api/tablet/zone1-100","query":"cluster_id=c1","body":{"allow_primary":true}}`, rec.Request)
+}
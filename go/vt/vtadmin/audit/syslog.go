@@ -0,0 +1,67 @@
+//go:build !windows
+
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"log/syslog"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+)
+
+// SyslogSink writes the audit records to the local syslog, as JSON objects.
+// It does not implement Querier.
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+var _ Sink = (*SyslogSink)(nil)
+
+// NewSyslogSink returns a SyslogSink writing messages with the given tag,
+// which defaults to "vtadmin".
+func NewSyslogSink(tag string) (*SyslogSink, error) {
+	if tag == "" {
+		tag = "vtadmin"
+	}
+
+	writer, err := syslog.New(syslog.LOG_NOTICE|syslog.LOG_AUTH, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+
+	return &SyslogSink{writer: writer}, nil
+}
+
+// Write is part of the Sink interface.
+func (sink *SyslogSink) Write(ctx context.Context, record *vtadminpb.AuditRecord) error {
+	msg, err := protojson.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return sink.writer.Notice(string(msg))
+}
+
+// Close is part of the Sink interface.
+func (sink *SyslogSink) Close() error {
+	return sink.writer.Close()
+}
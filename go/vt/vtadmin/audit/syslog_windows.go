@@ -0,0 +1,48 @@
+//go:build windows
+
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"errors"
+
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+)
+
+var errSyslogUnsupported = errors.New("syslog audit sink is not supported on windows")
+
+// SyslogSink is not supported on windows.
+type SyslogSink struct{}
+
+var _ Sink = (*SyslogSink)(nil)
+
+// NewSyslogSink returns an error, as syslog is not supported on windows.
+func NewSyslogSink(tag string) (*SyslogSink, error) {
+	return nil, errSyslogUnsupported
+}
+
+// Write is part of the Sink interface.
+func (sink *SyslogSink) Write(ctx context.Context, record *vtadminpb.AuditRecord) error {
+	return errSyslogUnsupported
+}
+
+// Close is part of the Sink interface.
+func (sink *SyslogSink) Close() error {
+	return nil
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtadmin/vtsql"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+)
+
+// DefaultTable is the table the TableSink writes to if none is configured.
+const DefaultTable = "vtadmin_audit"
+
+// timeLayout is the layout of the DATETIME(6) time column, in UTC.
+const timeLayout = "2006-01-02 15:04:05.999999"
+
+const createTableQuery = `CREATE TABLE IF NOT EXISTS %s (
+	id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+	time DATETIME(6) NOT NULL,
+	actor VARCHAR(255) NOT NULL,
+	roles TEXT NOT NULL,
+	method VARCHAR(255) NOT NULL,
+	cluster_id VARCHAR(255) NOT NULL,
+	resource VARCHAR(255) NOT NULL,
+	action VARCHAR(255) NOT NULL,
+	request MEDIUMTEXT NOT NULL,
+	outcome VARCHAR(16) NOT NULL,
+	error TEXT NOT NULL,
+	PRIMARY KEY (id),
+	KEY time_idx (time)
+)`
+
+// TableSink writes the audit records to a table of a cluster, through its
+// vtgates. The table is created on first use; it should be in an unsharded
+// keyspace, as its rows are keyed by an auto-increment column. It implements
+// Querier.
+type TableSink struct {
+	db    vtsql.DB
+	table string // escaped, and ready to be used as a format string
+
+	m       sync.Mutex
+	created bool
+}
+
+var (
+	_ Sink    = (*TableSink)(nil)
+	_ Querier = (*TableSink)(nil)
+)
+
+// NewTableSink returns a TableSink writing to the given table, optionally
+// qualified by its keyspace ("keyspace.table"), through db.
+func NewTableSink(db vtsql.DB, table string) (*TableSink, error) {
+	if db == nil {
+		return nil, errors.New("table audit sink requires --audit-table-cluster")
+	}
+
+	if table == "" {
+		table = DefaultTable
+	}
+
+	parts := strings.Split(table, ".")
+	if len(parts) > 2 {
+		return nil, fmt.Errorf("invalid audit table %q: expected [keyspace.]table", table)
+	}
+
+	for i, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("invalid audit table %q: expected [keyspace.]table", table)
+		}
+
+		parts[i] = strings.ReplaceAll(sqlescape.EscapeID(part), "%", "%%")
+	}
+
+	return &TableSink{db: db, table: strings.Join(parts, ".")}, nil
+}
+
+// createTable creates the table if it has not been created by this sink yet.
+// A failure is retried on the next call.
+func (sink *TableSink) createTable(ctx context.Context) error {
+	sink.m.Lock()
+	defer sink.m.Unlock()
+
+	if sink.created {
+		return nil
+	}
+
+	query, err := sqlparser.ParseAndBind(fmt.Sprintf(createTableQuery, sink.table))
+	if err != nil {
+		return err
+	}
+
+	if _, err := sink.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create audit table: %w", err)
+	}
+
+	sink.created = true
+	return nil
+}
+
+// Write is part of the Sink interface.
+func (sink *TableSink) Write(ctx context.Context, record *vtadminpb.AuditRecord) error {
+	if err := sink.createTable(ctx); err != nil {
+		return err
+	}
+
+	roles, err := json.Marshal(record.Roles)
+	if err != nil {
+		return err
+	}
+
+	query, err := sqlparser.ParseAndBind(
+		"INSERT INTO "+sink.table+" (time, actor, roles, method, cluster_id, resource, action, request, outcome, error) VALUES (%a, %a, %a, %a, %a, %a, %a, %a, %a, %a)",
+		sqltypes.StringBindVariable(protoutil.TimeFromProto(record.Time).UTC().Format(timeLayout)),
+		sqltypes.StringBindVariable(record.Actor),
+		sqltypes.StringBindVariable(string(roles)),
+		sqltypes.StringBindVariable(record.Method),
+		sqltypes.StringBindVariable(record.ClusterId),
+		sqltypes.StringBindVariable(record.Resource),
+		sqltypes.StringBindVariable(record.Action),
+		sqltypes.StringBindVariable(record.Request),
+		sqltypes.StringBindVariable(record.Outcome.String()),
+		sqltypes.StringBindVariable(record.Error),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = sink.db.ExecContext(ctx, query)
+	return err
+}
+
+// Query is part of the Querier interface.
+func (sink *TableSink) Query(ctx context.Context, req *vtadminpb.GetAuditRecordsRequest) ([]*vtadminpb.AuditRecord, error) {
+	if err := sink.createTable(ctx); err != nil {
+		return nil, err
+	}
+
+	query, err := sink.selectQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sink.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*vtadminpb.AuditRecord
+	for rows.Next() {
+		var (
+			t       string
+			roles   string
+			outcome string
+			record  vtadminpb.AuditRecord
+		)
+
+		if err := rows.Scan(&t, &record.Actor, &roles, &record.Method, &record.ClusterId, &record.Resource, &record.Action, &record.Request, &outcome, &record.Error); err != nil {
+			return nil, err
+		}
+
+		parsed, err := time.ParseInLocation(timeLayout, t, time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse audit record time %q: %w", t, err)
+		}
+		record.Time = protoutil.TimeToProto(parsed)
+
+		if err := json.Unmarshal([]byte(roles), &record.Roles); err != nil {
+			return nil, fmt.Errorf("failed to parse audit record roles %q: %w", roles, err)
+		}
+
+		record.Outcome = vtadminpb.AuditRecord_Outcome(vtadminpb.AuditRecord_Outcome_value[outcome])
+		records = append(records, &record)
+	}
+
+	return records, rows.Err()
+}
+
+// selectQuery returns the query selecting the records matching the request,
+// newest first.
+func (sink *TableSink) selectQuery(req *vtadminpb.GetAuditRecordsRequest) (string, error) {
+	var (
+		conditions []string
+		binds      []*querypb.BindVariable
+	)
+	addCondition := func(condition string, bind *querypb.BindVariable) {
+		conditions = append(conditions, condition)
+		binds = append(binds, bind)
+	}
+
+	if len(req.ClusterIds) > 0 {
+		bind, err := sqltypes.BuildBindVariable(req.ClusterIds)
+		if err != nil {
+			return "", err
+		}
+		addCondition("cluster_id IN %a", bind)
+	}
+
+	if req.Actor != "" {
+		addCondition("actor = %a", sqltypes.StringBindVariable(req.Actor))
+	}
+
+	if req.Resource != "" {
+		addCondition("resource = %a", sqltypes.StringBindVariable(req.Resource))
+	}
+
+	if req.Action != "" {
+		addCondition("action = %a", sqltypes.StringBindVariable(req.Action))
+	}
+
+	if req.Since != nil {
+		addCondition("time >= %a", sqltypes.StringBindVariable(protoutil.TimeFromProto(req.Since).UTC().Format(timeLayout)))
+	}
+
+	if req.Until != nil {
+		addCondition("time < %a", sqltypes.StringBindVariable(protoutil.TimeFromProto(req.Until).UTC().Format(timeLayout)))
+	}
+
+	var where string
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return sqlparser.ParseAndBind(
+		"SELECT time, actor, roles, method, cluster_id, resource, action, request, outcome, error FROM "+sink.table+where+fmt.Sprintf(" ORDER BY id DESC LIMIT %d", limit(req)),
+		binds...,
+	)
+}
+
+// Close is part of the Sink interface. It does not close the DB, which
+// belongs to its cluster.
+func (sink *TableSink) Close() error {
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/vtadmin/vtsql"
+
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+)
+
+// execDB is a vtsql.DB which records the queries passed to ExecContext.
+type execDB struct {
+	vtsql.DB
+	queries []string
+	err     error
+}
+
+func (db *execDB) ExecContext(ctx context.Context, query string) (sql.Result, error) {
+	db.queries = append(db.queries, query)
+	return nil, db.err
+}
+
+func TestTableSinkWrite(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db := &execDB{err: errors.New("keyspace not found")}
+	sink, err := NewTableSink(db, "admin.audit")
+	require.NoError(t, err)
+
+	record := &vtadminpb.AuditRecord{
+		Time:      protoutil.TimeToProto(time.Date(2025, 3, 4, 5, 6, 7, 891000000, time.UTC)),
+		Actor:     "alice",
+		Roles:     []string{"dba"},
+		Method:    "SetReadOnly",
+		ClusterId: "c1",
+		Resource:  "Tablet",
+		Action:    "manage_tablet_writability",
+		Request:   `{"alias":"zone1-100","note":"it's"}`,
+		Outcome:   vtadminpb.AuditRecord_DENIED,
+	}
+
+	// A failure to create the table is retried.
+	assert.ErrorContains(t, sink.Write(ctx, record), "failed to create audit table")
+	db.err = nil
+	require.NoError(t, sink.Write(ctx, record))
+	require.NoError(t, sink.Write(ctx, record))
+
+	require.Len(t, db.queries, 4)
+	assert.True(t, strings.HasPrefix(db.queries[1], "CREATE TABLE IF NOT EXISTS `admin`.`audit` ("), db.queries[1])
+	assert.Equal(t,
+		"INSERT INTO `admin`.`audit` (time, actor, roles, method, cluster_id, resource, action, request, outcome, error) "+
+			`VALUES ('2025-03-04 05:06:07.891', 'alice', '["dba"]', 'SetReadOnly', 'c1', 'Tablet', 'manage_tablet_writability', '{"alias":"zone1-100","note":"it\'s"}', 'DENIED', '')`,
+		db.queries[2])
+	assert.Equal(t, db.queries[2], db.queries[3], "the table should only be created once")
+}
+
+func TestTableSinkSelectQuery(t *testing.T) {
+	t.Parallel()
+
+	sink, err := NewTableSink(&execDB{}, "")
+	require.NoError(t, err)
+
+	query, err := sink.selectQuery(&vtadminpb.GetAuditRecordsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT time, actor, roles, method, cluster_id, resource, action, request, outcome, error FROM `vtadmin_audit` ORDER BY id DESC LIMIT 100", query)
+
+	query, err = sink.selectQuery(&vtadminpb.GetAuditRecordsRequest{
+		ClusterIds: []string{"c1", "c2"},
+		Actor:      "o'brien",
+		Resource:   "Shard",
+		Action:     "emergency_failover_shard",
+		Since:      protoutil.TimeToProto(time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)),
+		Until:      protoutil.TimeToProto(time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)),
+		Limit:      10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT time, actor, roles, method, cluster_id, resource, action, request, outcome, error FROM `vtadmin_audit` "+
+		`WHERE cluster_id IN ('c1', 'c2') AND actor = 'o\'brien' AND resource = 'Shard' AND action = 'emergency_failover_shard' `+
+		"AND time >= '2025-03-04 00:00:00' AND time < '2025-03-05 00:00:00' ORDER BY id DESC LIMIT 10", query)
+
+	_, err = NewTableSink(&execDB{}, "a.b.c")
+	assert.ErrorContains(t, err, "expected [keyspace.]table")
+}
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"io"
 	"math/rand/v2"
+	"net/http"
 	"sort"
 	"strings"
 	"sync"
@@ -44,6 +45,7 @@ import (
 	"vitess.io/vitess/go/vt/vtadmin/cluster/internal/caches/schemacache"
 	"vitess.io/vitess/go/vt/vtadmin/debug"
 	"vitess.io/vitess/go/vt/vtadmin/errors"
+	"vitess.io/vitess/go/vt/vtadmin/queryinsights"
 	"vitess.io/vitess/go/vt/vtadmin/vtadminproto"
 	"vitess.io/vitess/go/vt/vtadmin/vtctldclient"
 	"vitess.io/vitess/go/vt/vtadmin/vtsql"
@@ -1259,6 +1261,66 @@ func (c *Cluster) GetKeyspaces(ctx context.Context) ([]*vtadminpb.Keyspace, erro
 	return keyspaces, nil
 }
 
+// GetQueryInsights returns the query stats of the VTGates in the cluster,
+// merged across them, as requested. The stats are fetched from the
+// /debug/queryz page of each VTGate, at its FQDN.
+//
+// Note that if only a subset of the VTGates fail to return their stats, this
+// is treated as a partial success, and the ClusterQueryInsights response will
+// include any errors in the Warnings slice. If all VTGates fail, this is
+// treated as an error.
+func (c *Cluster) GetQueryInsights(ctx context.Context, req *vtadminpb.GetQueryInsightsRequest) (*vtadminpb.ClusterQueryInsights, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.GetQueryInsights")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+
+	gates, err := c.GetGates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	span.Annotate("num_gates", len(gates))
+
+	var (
+		m   sync.Mutex
+		wg  sync.WaitGroup
+		rec concurrency.AllErrorRecorder
+		agg = queryinsights.NewAggregator()
+	)
+
+	for _, gate := range gates {
+		wg.Add(1)
+
+		go func(gate *vtadminpb.VTGate) {
+			defer wg.Done()
+
+			queryz, err := queryinsights.Fetch(ctx, http.DefaultClient, gate)
+			if err != nil {
+				rec.RecordError(fmt.Errorf("GetQueryInsights(vtgate = %s) failed: %w", gate.Hostname, err))
+				return
+			}
+
+			m.Lock()
+			defer m.Unlock()
+			agg.Add(queryz)
+		}(gate)
+	}
+
+	wg.Wait()
+
+	// If every VTGate failed, treat this as an error.
+	if rec.HasErrors() && len(rec.Errors) == len(gates) {
+		return nil, rec.Error()
+	}
+
+	insights := agg.Insights(req)
+	insights.Cluster = c.ToProto()
+	insights.Warnings = rec.ErrorStrings()
+
+	return insights, nil
+}
+
 // GetSrvKeyspaces returns all SrvKeyspaces for all keyspaces in a cluster.
 func (c *Cluster) GetSrvKeyspaces(ctx context.Context, cells []string) (map[string]*vtctldatapb.GetSrvKeyspacesResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "Cluster.GetKeyspaces")
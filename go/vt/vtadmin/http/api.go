@@ -82,6 +82,10 @@ func (api *API) Adapt(handler VTAdminHandler) http.HandlerFunc {
 			ctx = rbac.NewContext(ctx, actor)
 		}
 
+		// Keep recording the authorization checks for the audit middleware,
+		// if it is installed.
+		ctx = rbac.CopyAuthorizationChecksContext(ctx, r.Context())
+
 		if cache.ShouldRefreshFromRequest(r) {
 			ctx = cache.NewIncomingRefreshContext(ctx)
 		}
@@ -0,0 +1,66 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/vt/concurrency"
+
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+)
+
+// GetAuditRecords implements the http wrapper for
+// /audit[?cluster_id=[&cluster_id=]][&actor=][&resource=][&action=][&since=][&until=][&limit=].
+//
+// The since and until parameters are RFC 3339 times.
+func GetAuditRecords(ctx context.Context, r Request, api *API) *JSONResponse {
+	query := r.URL.Query()
+
+	rec := concurrency.AllErrorRecorder{} // Aggregate any BadRequest type errors
+
+	since, err := r.ParseQueryParamAsTime("since")
+	if err != nil {
+		rec.RecordError(err)
+	}
+
+	until, err := r.ParseQueryParamAsTime("until")
+	if err != nil {
+		rec.RecordError(err)
+	}
+
+	limit, err := r.ParseQueryParamAsUint32("limit", 0)
+	if err != nil {
+		rec.RecordError(err)
+	}
+
+	if rec.HasErrors() {
+		return NewJSONResponse(nil, rec.Error())
+	}
+
+	records, err := api.server.GetAuditRecords(ctx, &vtadminpb.GetAuditRecordsRequest{
+		ClusterIds: query["cluster_id"],
+		Actor:      query.Get("actor"),
+		Resource:   query.Get("resource"),
+		Action:     query.Get("action"),
+		Since:      since,
+		Until:      until,
+		Limit:      limit,
+	})
+
+	return NewJSONResponse(records, err)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/vtadmin/errors"
+
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+)
+
+// GetQueryInsights implements the http wrapper for
+// /queryinsights[?cluster_id=[&cluster_id=]][&sort=][&limit=][&table=].
+//
+// The sort parameter is the name of a GetQueryInsightsRequest.Sort, in any
+// case, for example "count" or "p99_latency".
+func GetQueryInsights(ctx context.Context, r Request, api *API) *JSONResponse {
+	query := r.URL.Query()
+
+	rec := concurrency.AllErrorRecorder{} // Aggregate any BadRequest type errors
+
+	var sort vtadminpb.GetQueryInsightsRequest_Sort
+	if param := query.Get("sort"); param != "" {
+		val, ok := vtadminpb.GetQueryInsightsRequest_Sort_value[strings.ToUpper(param)]
+		if !ok {
+			rec.RecordError(&errors.BadRequest{
+				Err:        fmt.Errorf("unknown sort %s", param),
+				ErrDetails: fmt.Sprintf("could not parse query parameter sort (= %v) into a query insights sort", param),
+			})
+		}
+
+		sort = vtadminpb.GetQueryInsightsRequest_Sort(val)
+	}
+
+	limit, err := r.ParseQueryParamAsUint32("limit", 0)
+	if err != nil {
+		rec.RecordError(err)
+	}
+
+	if rec.HasErrors() {
+		return NewJSONResponse(nil, rec.Error())
+	}
+
+	insights, err := api.server.GetQueryInsights(ctx, &vtadminpb.GetQueryInsightsRequest{
+		ClusterIds: query["cluster_id"],
+		Sort:       sort,
+		Limit:      limit,
+		Table:      query.Get("table"),
+	})
+
+	return NewJSONResponse(insights, err)
+}
@@ -20,13 +20,16 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtadmin/errors"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vttimepb "vitess.io/vitess/go/vt/proto/vttime"
 )
 
 // Request wraps an *http.Request to provide some convenience functions for
@@ -115,6 +118,24 @@ func (r Request) ParseQueryParamAsInt64(name string, defaultVal int64) (int64, e
 	return defaultVal, nil
 }
 
+// ParseQueryParamAsTime attempts to parse the query parameter of the given
+// name as an RFC 3339 time. If the parameter is not set, nil is returned.
+func (r Request) ParseQueryParamAsTime(name string) (*vttimepb.Time, error) {
+	if param := r.URL.Query().Get(name); param != "" {
+		val, err := time.Parse(time.RFC3339Nano, param)
+		if err != nil {
+			return nil, &errors.BadRequest{
+				Err:        err,
+				ErrDetails: fmt.Sprintf("could not parse query parameter %s (= %v) into RFC 3339 time value", name, param),
+			}
+		}
+
+		return protoutil.TimeToProto(val), nil
+	}
+
+	return nil, nil
+}
+
 // Vars is a mapping of the route variable values in a given request.
 //
 // See (gorilla/mux).Vars for details. We define a type here to add some
@@ -194,6 +194,30 @@ func WorkflowSwitchTraffic(ctx context.Context, r Request, api *API) *JSONRespon
 	return NewJSONResponse(res, err)
 }
 
+// WorkflowReverseTraffic implements the http wrapper for the VTAdminServer.WorkflowReverseTraffic
+// method.
+//
+// Its route is /workflow/{cluster_id}/reversetraffic
+func WorkflowReverseTraffic(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	var req vtctldatapb.WorkflowSwitchTrafficRequest
+	if err := decoder.Decode(&req); err != nil {
+		return NewJSONResponse(nil, &errors.BadRequest{
+			Err: err,
+		})
+	}
+
+	res, err := api.server.WorkflowReverseTraffic(ctx, &vtadminpb.WorkflowSwitchTrafficRequest{
+		ClusterId: vars["cluster_id"],
+		Request:   &req,
+	})
+
+	return NewJSONResponse(res, err)
+}
+
 // ReshardCreate implements the http wrapper for the VTAdminServer.ReshardCreate
 // method.
 //
@@ -218,6 +242,54 @@ func ReshardCreate(ctx context.Context, r Request, api *API) *JSONResponse {
 	return NewJSONResponse(res, err)
 }
 
+// WorkflowCancel implements the http wrapper for the VTAdminServer.WorkflowCancel
+// method.
+//
+// Its route is /workflow/{cluster_id}/cancel
+func WorkflowCancel(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	var req vtctldatapb.WorkflowDeleteRequest
+	if err := decoder.Decode(&req); err != nil {
+		return NewJSONResponse(nil, &errors.BadRequest{
+			Err: err,
+		})
+	}
+
+	res, err := api.server.WorkflowCancel(ctx, &vtadminpb.WorkflowCancelRequest{
+		ClusterId: vars["cluster_id"],
+		Request:   &req,
+	})
+
+	return NewJSONResponse(res, err)
+}
+
+// WorkflowComplete implements the http wrapper for the VTAdminServer.WorkflowComplete
+// method.
+//
+// Its route is /workflow/{cluster_id}/complete
+func WorkflowComplete(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	var req vtctldatapb.MoveTablesCompleteRequest
+	if err := decoder.Decode(&req); err != nil {
+		return NewJSONResponse(nil, &errors.BadRequest{
+			Err: err,
+		})
+	}
+
+	res, err := api.server.WorkflowComplete(ctx, &vtadminpb.WorkflowCompleteRequest{
+		ClusterId: vars["cluster_id"],
+		Request:   &req,
+	})
+
+	return NewJSONResponse(res, err)
+}
+
 // WorkflowDelete implements the http wrapper for the VTAdminServer.WorkflowDelete
 // method.
 //
@@ -0,0 +1,413 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package queryinsights merges the query stats of the VTGates of a cluster.
+
+Each VTGate serves the stats of its query plans on /debug/queryz?format=json,
+which is fetched from the FQDN of the VTGate. The stats of the plans of the
+same query and plan type are merged, across plans and VTGates, and ranked to
+find the top queries of the cluster. They are also merged by table.
+*/
+package queryinsights
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/vtgate"
+
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+)
+
+// DefaultLimit is the number of queries returned by a request which does not
+// set a limit.
+const DefaultLimit = 20
+
+// overflowBound is the bound of the last bucket of the execution time
+// histograms, which counts the executions slower than all the others.
+const overflowBound = time.Duration(math.MaxInt64)
+
+// URL returns the URL of the query stats of a VTGate.
+func URL(gate *vtadminpb.VTGate) (string, error) {
+	if gate.FQDN == "" {
+		return "", fmt.Errorf("vtgate %s has no FQDN", gate.Hostname)
+	}
+
+	addr := gate.FQDN
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+
+	return strings.TrimSuffix(addr, "/") + vtgate.QueryzHandler + "?format=json", nil
+}
+
+// Fetch returns the query stats of a VTGate.
+func Fetch(ctx context.Context, client *http.Client, gate *vtadminpb.VTGate) (*vtgate.QueryzJSON, error) {
+	url, err := URL(gate)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("GET %s: %s: %s", url, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var queryz vtgate.QueryzJSON
+	if err := json.NewDecoder(resp.Body).Decode(&queryz); err != nil {
+		return nil, fmt.Errorf("GET %s: failed to decode query stats: %w", url, err)
+	}
+
+	return &queryz, nil
+}
+
+// Aggregator merges the query stats of VTGates. It is not safe for concurrent
+// use.
+type Aggregator struct {
+	gates   int
+	queries map[queryKey]*query
+}
+
+type queryKey struct {
+	query    string
+	planType string
+}
+
+type query struct {
+	queryType string
+	tables    []string
+	gates     int
+	metrics   metrics
+}
+
+type metrics struct {
+	count        uint64
+	time         time.Duration
+	shardQueries uint64
+	rowsAffected uint64
+	rowsReturned uint64
+	errors       uint64
+	// execTimes is the execution time histogram, keyed by the upper bound of
+	// each bucket, so VTGates with different buckets can be merged.
+	execTimes map[time.Duration]uint64
+}
+
+// NewAggregator returns an empty Aggregator.
+func NewAggregator() *Aggregator {
+	return &Aggregator{queries: map[queryKey]*query{}}
+}
+
+// Add merges the query stats of a VTGate.
+func (agg *Aggregator) Add(queryz *vtgate.QueryzJSON) {
+	agg.gates++
+
+	seen := map[queryKey]bool{}
+	for _, row := range queryz.Queries {
+		// Plans which were never executed only add noise.
+		if row.Count == 0 {
+			continue
+		}
+
+		key := queryKey{query: row.Query, planType: row.PlanType}
+		q, ok := agg.queries[key]
+		if !ok {
+			q = &query{queryType: row.QueryType}
+			agg.queries[key] = q
+		}
+
+		if !seen[key] {
+			seen[key] = true
+			q.gates++
+		}
+
+		for _, table := range row.Tables {
+			if !slices.Contains(q.tables, table) {
+				q.tables = append(q.tables, table)
+			}
+		}
+
+		q.metrics.addRow(row, queryz.ExecTimeBuckets)
+	}
+}
+
+func (m *metrics) addRow(row *vtgate.QueryzJSONRow, buckets []time.Duration) {
+	m.count += row.Count
+	m.time += row.Time
+	m.shardQueries += row.ShardQueries
+	m.rowsAffected += row.RowsAffected
+	m.rowsReturned += row.RowsReturned
+	m.errors += row.Errors
+
+	// VTGates which do not serve the histogram do not contribute to the
+	// latency percentiles.
+	if len(row.ExecTimes) != len(buckets)+1 {
+		return
+	}
+
+	if m.execTimes == nil {
+		m.execTimes = map[time.Duration]uint64{}
+	}
+
+	for i, count := range row.ExecTimes {
+		bound := overflowBound
+		if i < len(buckets) {
+			bound = buckets[i]
+		}
+
+		m.execTimes[bound] += count
+	}
+}
+
+func (m *metrics) add(other *metrics) {
+	m.count += other.count
+	m.time += other.time
+	m.shardQueries += other.shardQueries
+	m.rowsAffected += other.rowsAffected
+	m.rowsReturned += other.rowsReturned
+	m.errors += other.errors
+
+	for bound, count := range other.execTimes {
+		if m.execTimes == nil {
+			m.execTimes = map[time.Duration]uint64{}
+		}
+
+		m.execTimes[bound] += count
+	}
+}
+
+// percentile returns the upper bound of the bucket of the execution time
+// histogram the pth percentile falls into, or the last finite bound if it is
+// beyond all of them. It returns 0 if there is no histogram.
+func (m *metrics) percentile(p float64) time.Duration {
+	var total uint64
+	bounds := make([]time.Duration, 0, len(m.execTimes))
+	for bound, count := range m.execTimes {
+		total += count
+		bounds = append(bounds, bound)
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	slices.Sort(bounds)
+
+	target := uint64(math.Ceil(p * float64(total)))
+	var cumulative uint64
+	for i, bound := range bounds {
+		cumulative += m.execTimes[bound]
+		if cumulative < target {
+			continue
+		}
+
+		if bound == overflowBound && i > 0 {
+			return bounds[i-1]
+		}
+
+		return bound
+	}
+
+	return bounds[len(bounds)-1]
+}
+
+func (m *metrics) averageTime() time.Duration {
+	if m.count == 0 {
+		return 0
+	}
+
+	return m.time / time.Duration(m.count)
+}
+
+func (m *metrics) toProto() *vtadminpb.QueryMetrics {
+	metrics := &vtadminpb.QueryMetrics{
+		Count:        m.count,
+		TotalTime:    protoutil.DurationToProto(m.time),
+		ShardQueries: m.shardQueries,
+		RowsAffected: m.rowsAffected,
+		RowsReturned: m.rowsReturned,
+		Errors:       m.errors,
+	}
+
+	if len(m.execTimes) > 0 {
+		metrics.P50Latency = protoutil.DurationToProto(m.percentile(0.5))
+		metrics.P90Latency = protoutil.DurationToProto(m.percentile(0.9))
+		metrics.P99Latency = protoutil.DurationToProto(m.percentile(0.99))
+	}
+
+	return metrics
+}
+
+// sortKey returns the value the metrics are ranked by for the given sort.
+func (m *metrics) sortKey(sort vtadminpb.GetQueryInsightsRequest_Sort) float64 {
+	switch sort {
+	case vtadminpb.GetQueryInsightsRequest_COUNT:
+		return float64(m.count)
+	case vtadminpb.GetQueryInsightsRequest_AVERAGE_TIME:
+		return float64(m.averageTime())
+	case vtadminpb.GetQueryInsightsRequest_P99_LATENCY:
+		return float64(m.percentile(0.99))
+	case vtadminpb.GetQueryInsightsRequest_SHARD_QUERIES:
+		return float64(m.shardQueries)
+	case vtadminpb.GetQueryInsightsRequest_ROWS_AFFECTED:
+		return float64(m.rowsAffected)
+	case vtadminpb.GetQueryInsightsRequest_ROWS_RETURNED:
+		return float64(m.rowsReturned)
+	case vtadminpb.GetQueryInsightsRequest_ERRORS:
+		return float64(m.errors)
+	default:
+		return float64(m.time)
+	}
+}
+
+// matchesTable returns whether any of the keyspace-qualified tables is the
+// given table, which may or may not be qualified.
+func matchesTable(tables []string, table string) bool {
+	for _, t := range tables {
+		if t == table || strings.HasSuffix(t, "."+table) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Insights returns the top queries of the merged stats, and their stats by
+// table, as requested. The Cluster of the result is not set.
+func (agg *Aggregator) Insights(req *vtadminpb.GetQueryInsightsRequest) *vtadminpb.ClusterQueryInsights {
+	type rankedQuery struct {
+		key   queryKey
+		query *query
+		rank  float64
+	}
+
+	type table struct {
+		name    string
+		queries int
+		metrics metrics
+		rank    float64
+	}
+
+	var (
+		queries []rankedQuery
+		tables  = map[string]*table{}
+	)
+
+	for key, q := range agg.queries {
+		if req.Table != "" && !matchesTable(q.tables, req.Table) {
+			continue
+		}
+
+		queries = append(queries, rankedQuery{key: key, query: q, rank: q.metrics.sortKey(req.Sort)})
+
+		for _, name := range q.tables {
+			if req.Table != "" && !matchesTable([]string{name}, req.Table) {
+				continue
+			}
+
+			t, ok := tables[name]
+			if !ok {
+				t = &table{name: name}
+				tables[name] = t
+			}
+
+			t.queries++
+			t.metrics.add(&q.metrics)
+		}
+	}
+
+	slices.SortFunc(queries, func(a, b rankedQuery) int {
+		if c := cmp.Compare(b.rank, a.rank); c != 0 {
+			return c
+		}
+
+		if c := cmp.Compare(a.key.query, b.key.query); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.key.planType, b.key.planType)
+	})
+
+	limit := DefaultLimit
+	if req.Limit > 0 {
+		limit = int(req.Limit)
+	}
+
+	if len(queries) > limit {
+		queries = queries[:limit]
+	}
+
+	insights := &vtadminpb.ClusterQueryInsights{
+		Queries: make([]*vtadminpb.QueryStats, 0, len(queries)),
+		Tables:  make([]*vtadminpb.TableQueryStats, 0, len(tables)),
+		Gates:   int32(agg.gates),
+	}
+
+	for _, q := range queries {
+		insights.Queries = append(insights.Queries, &vtadminpb.QueryStats{
+			Query:     q.key.query,
+			PlanType:  q.key.planType,
+			QueryType: q.query.queryType,
+			Tables:    q.query.tables,
+			Metrics:   q.query.metrics.toProto(),
+			Gates:     int32(q.query.gates),
+		})
+	}
+
+	rankedTables := make([]*table, 0, len(tables))
+	for _, t := range tables {
+		t.rank = t.metrics.sortKey(req.Sort)
+		rankedTables = append(rankedTables, t)
+	}
+
+	slices.SortFunc(rankedTables, func(a, b *table) int {
+		if c := cmp.Compare(b.rank, a.rank); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.name, b.name)
+	})
+
+	for _, t := range rankedTables {
+		insights.Tables = append(insights.Tables, &vtadminpb.TableQueryStats{
+			Table:   t.name,
+			Queries: int32(t.queries),
+			Metrics: t.metrics.toProto(),
+		})
+	}
+
+	return insights
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queryinsights
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/vtgate"
+
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+)
+
+var testBuckets = []time.Duration{time.Millisecond, 10 * time.Millisecond, 100 * time.Millisecond}
+
+func testQueryz() []*vtgate.QueryzJSON {
+	return []*vtgate.QueryzJSON{
+		{
+			ExecTimeBuckets: testBuckets,
+			Queries: []*vtgate.QueryzJSONRow{
+				{
+					Query:        "select * from t1",
+					PlanType:     "Scatter",
+					QueryType:    "SELECT",
+					Tables:       []string{"ks.t1"},
+					Count:        10,
+					Time:         50 * time.Millisecond,
+					ShardQueries: 20,
+					RowsReturned: 100,
+					ExecTimes:    []uint64{5, 5, 0, 0},
+				},
+				{
+					// Another plan of the same query, for example in another
+					// keyspace.
+					Query:        "select * from t1",
+					PlanType:     "Scatter",
+					QueryType:    "SELECT",
+					Tables:       []string{"ks.t1"},
+					Count:        2,
+					Time:         10 * time.Millisecond,
+					ShardQueries: 4,
+					RowsReturned: 20,
+					ExecTimes:    []uint64{0, 2, 0, 0},
+				},
+				{
+					Query:        "update t1 join t2 set t1.a = t2.a",
+					PlanType:     "Join",
+					QueryType:    "UPDATE",
+					Tables:       []string{"ks.t1", "ks.t2"},
+					Count:        1,
+					Time:         time.Second,
+					ShardQueries: 2,
+					RowsAffected: 3,
+					Errors:       1,
+					ExecTimes:    []uint64{0, 0, 0, 1},
+				},
+				{
+					Query:     "select * from t3",
+					PlanType:  "Passthrough",
+					QueryType: "SELECT",
+					Tables:    []string{"ks.t3"},
+					ExecTimes: []uint64{0, 0, 0, 0},
+				},
+			},
+		},
+		{
+			ExecTimeBuckets: testBuckets,
+			Queries: []*vtgate.QueryzJSONRow{
+				{
+					Query:        "select * from t1",
+					PlanType:     "Scatter",
+					QueryType:    "SELECT",
+					Tables:       []string{"ks.t1"},
+					Count:        8,
+					Time:         40 * time.Millisecond,
+					ShardQueries: 16,
+					RowsReturned: 80,
+					ExecTimes:    []uint64{0, 8, 0, 0},
+				},
+				{
+					// A VTGate which does not serve the histogram.
+					Query:        "update t1 join t2 set t1.a = t2.a",
+					PlanType:     "Join",
+					QueryType:    "UPDATE",
+					Tables:       []string{"ks.t1", "ks.t2"},
+					Count:        1,
+					Time:         200 * time.Millisecond,
+					ShardQueries: 2,
+					RowsAffected: 2,
+				},
+			},
+		},
+	}
+}
+
+func TestInsights(t *testing.T) {
+	t.Parallel()
+
+	selectStats := &vtadminpb.QueryStats{
+		Query:     "select * from t1",
+		PlanType:  "Scatter",
+		QueryType: "SELECT",
+		Tables:    []string{"ks.t1"},
+		Metrics: &vtadminpb.QueryMetrics{
+			Count:        20,
+			TotalTime:    protoutil.DurationToProto(100 * time.Millisecond),
+			P50Latency:   protoutil.DurationToProto(10 * time.Millisecond),
+			P90Latency:   protoutil.DurationToProto(10 * time.Millisecond),
+			P99Latency:   protoutil.DurationToProto(10 * time.Millisecond),
+			ShardQueries: 40,
+			RowsReturned: 200,
+		},
+		Gates: 2,
+	}
+	updateStats := &vtadminpb.QueryStats{
+		Query:     "update t1 join t2 set t1.a = t2.a",
+		PlanType:  "Join",
+		QueryType: "UPDATE",
+		Tables:    []string{"ks.t1", "ks.t2"},
+		Metrics: &vtadminpb.QueryMetrics{
+			Count:     2,
+			TotalTime: protoutil.DurationToProto(1200 * time.Millisecond),
+			// Slower than the last bucket.
+			P50Latency:   protoutil.DurationToProto(100 * time.Millisecond),
+			P90Latency:   protoutil.DurationToProto(100 * time.Millisecond),
+			P99Latency:   protoutil.DurationToProto(100 * time.Millisecond),
+			ShardQueries: 4,
+			RowsAffected: 5,
+			Errors:       1,
+		},
+		Gates: 2,
+	}
+	t1Stats := &vtadminpb.TableQueryStats{
+		Table:   "ks.t1",
+		Queries: 2,
+		Metrics: &vtadminpb.QueryMetrics{
+			Count:        22,
+			TotalTime:    protoutil.DurationToProto(1300 * time.Millisecond),
+			P50Latency:   protoutil.DurationToProto(10 * time.Millisecond),
+			P90Latency:   protoutil.DurationToProto(10 * time.Millisecond),
+			P99Latency:   protoutil.DurationToProto(100 * time.Millisecond),
+			ShardQueries: 44,
+			RowsAffected: 5,
+			RowsReturned: 200,
+			Errors:       1,
+		},
+	}
+	t2Stats := &vtadminpb.TableQueryStats{
+		Table:   "ks.t2",
+		Queries: 1,
+		Metrics: updateStats.Metrics,
+	}
+
+	tests := []struct {
+		name     string
+		req      *vtadminpb.GetQueryInsightsRequest
+		expected *vtadminpb.ClusterQueryInsights
+	}{
+		{
+			name: "by total time",
+			req:  &vtadminpb.GetQueryInsightsRequest{},
+			expected: &vtadminpb.ClusterQueryInsights{
+				Queries: []*vtadminpb.QueryStats{updateStats, selectStats},
+				Tables:  []*vtadminpb.TableQueryStats{t1Stats, t2Stats},
+				Gates:   2,
+			},
+		},
+		{
+			name: "by count",
+			req: &vtadminpb.GetQueryInsightsRequest{
+				Sort: vtadminpb.GetQueryInsightsRequest_COUNT,
+			},
+			expected: &vtadminpb.ClusterQueryInsights{
+				Queries: []*vtadminpb.QueryStats{selectStats, updateStats},
+				Tables:  []*vtadminpb.TableQueryStats{t1Stats, t2Stats},
+				Gates:   2,
+			},
+		},
+		{
+			name: "limit",
+			req: &vtadminpb.GetQueryInsightsRequest{
+				Sort:  vtadminpb.GetQueryInsightsRequest_ROWS_RETURNED,
+				Limit: 1,
+			},
+			expected: &vtadminpb.ClusterQueryInsights{
+				Queries: []*vtadminpb.QueryStats{selectStats},
+				Tables:  []*vtadminpb.TableQueryStats{t1Stats, t2Stats},
+				Gates:   2,
+			},
+		},
+		{
+			name: "unqualified table",
+			req: &vtadminpb.GetQueryInsightsRequest{
+				Table: "t2",
+			},
+			expected: &vtadminpb.ClusterQueryInsights{
+				Queries: []*vtadminpb.QueryStats{updateStats},
+				Tables:  []*vtadminpb.TableQueryStats{t2Stats},
+				Gates:   2,
+			},
+		},
+		{
+			name: "qualified table",
+			req: &vtadminpb.GetQueryInsightsRequest{
+				Table: "ks.t1",
+				Sort:  vtadminpb.GetQueryInsightsRequest_SHARD_QUERIES,
+			},
+			expected: &vtadminpb.ClusterQueryInsights{
+				Queries: []*vtadminpb.QueryStats{selectStats, updateStats},
+				Tables:  []*vtadminpb.TableQueryStats{t1Stats},
+				Gates:   2,
+			},
+		},
+		{
+			name: "unknown table",
+			req: &vtadminpb.GetQueryInsightsRequest{
+				Table: "t3",
+			},
+			expected: &vtadminpb.ClusterQueryInsights{
+				Queries: []*vtadminpb.QueryStats{},
+				Tables:  []*vtadminpb.TableQueryStats{},
+				Gates:   2,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			agg := NewAggregator()
+			for _, queryz := range testQueryz() {
+				agg.Add(queryz)
+			}
+
+			utils.MustMatch(t, tt.expected, agg.Insights(tt.req))
+		})
+	}
+}
+
+func TestURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		fqdn      string
+		expected  string
+		shouldErr bool
+	}{
+		{
+			fqdn:     "gate1:15001",
+			expected: "http://gate1:15001/debug/queryz?format=json",
+		},
+		{
+			fqdn:     "https://gate1.example.com/",
+			expected: "https://gate1.example.com/debug/queryz?format=json",
+		},
+		{
+			fqdn:      "",
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		url, err := URL(&vtadminpb.VTGate{Hostname: "gate1", FQDN: tt.fqdn})
+		if tt.shouldErr {
+			assert.Error(t, err, "fqdn %q", tt.fqdn)
+			continue
+		}
+
+		require.NoError(t, err, "fqdn %q", tt.fqdn)
+		assert.Equal(t, tt.expected, url)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	t.Parallel()
+
+	queryz := testQueryz()[0]
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/queryz", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("format") != "json" {
+			http.Error(w, "not json", http.StatusBadRequest)
+			return
+		}
+
+		json.NewEncoder(w).Encode(queryz)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx := context.Background()
+
+	got, err := Fetch(ctx, server.Client(), &vtadminpb.VTGate{Hostname: "gate1", FQDN: strings.TrimPrefix(server.URL, "http://")})
+	require.NoError(t, err)
+	assert.Equal(t, queryz, got)
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "access denied", http.StatusForbidden)
+	}))
+	defer broken.Close()
+
+	_, err = Fetch(ctx, broken.Client(), &vtadminpb.VTGate{Hostname: "gate2", FQDN: broken.URL})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "access denied")
+}
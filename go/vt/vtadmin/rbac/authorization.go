@@ -18,6 +18,7 @@ package rbac
 
 import (
 	"context"
+	"sync"
 )
 
 // Authorizer contains a set of rules that determine which actors may take which
@@ -62,6 +63,44 @@ func NewAuthorizer(cfg *Config) (*Authorizer, error) {
 // the given action on the given resource in the given cluster.
 func (authz *Authorizer) IsAuthorized(ctx context.Context, clusterID string, resource Resource, action Action) bool {
 	actor, _ := FromContext(ctx) // nil is ok here, since rule.Allows handles it
+	authorized := authz.isAuthorized(clusterID, resource, action, actor)
+	recordCheck(ctx, clusterID, resource, action, authorized)
+
+	return authorized
+}
+
+// IsAuthorizedWithFallback returns whether an Actor (from the context) is
+// permitted to take the given action on the given resource in the given
+// cluster, either by being permitted to take that action or the fallback one.
+// byFallback is true when only the fallback action permits it. Only the
+// outcome for the given action is recorded for auditing, so a request checked
+// this way has a single audit record.
+func (authz *Authorizer) IsAuthorizedWithFallback(ctx context.Context, clusterID string, resource Resource, action Action, fallback Action) (authorized bool, byFallback bool) {
+	actor, _ := FromContext(ctx)
+	authorized = authz.isAuthorized(clusterID, resource, action, actor)
+	if !authorized {
+		authorized = authz.isAuthorized(clusterID, resource, fallback, actor)
+		byFallback = authorized
+	}
+	recordCheck(ctx, clusterID, resource, action, authorized)
+
+	return authorized, byFallback
+}
+
+// recordCheck records an authorization check in the context, if it records
+// them.
+func recordCheck(ctx context.Context, clusterID string, resource Resource, action Action, authorized bool) {
+	if checks, ok := ctx.Value(checksKey{}).(*authorizationChecks); ok {
+		checks.record(AuthorizationCheck{
+			ClusterID:  clusterID,
+			Resource:   resource,
+			Action:     action,
+			Authorized: authorized,
+		})
+	}
+}
+
+func (authz *Authorizer) isAuthorized(clusterID string, resource Resource, action Action, actor *Actor) bool {
 	if p, ok := authz.policies["*"]; ok {
 		// We have policies for the wildcard resource to check first
 		for _, rule := range p {
@@ -81,3 +120,49 @@ func (authz *Authorizer) IsAuthorized(ctx context.Context, clusterID string, res
 
 	return false
 }
+
+// AuthorizationCheck is an authorization check made by IsAuthorized.
+type AuthorizationCheck struct {
+	ClusterID  string
+	Resource   Resource
+	Action     Action
+	Authorized bool
+}
+
+type checksKey struct{}
+
+type authorizationChecks struct {
+	m      sync.Mutex
+	checks []AuthorizationCheck
+}
+
+func (c *authorizationChecks) record(check AuthorizationCheck) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.checks = append(c.checks, check)
+}
+
+// NewAuthorizationChecksContext returns a context in which the authorization
+// checks are recorded, and a function returning the checks made so far. This
+// is used by the audit interceptors and middlewares to find which actions a
+// request took, in which clusters.
+func NewAuthorizationChecksContext(ctx context.Context) (context.Context, func() []AuthorizationCheck) {
+	checks := &authorizationChecks{}
+	return context.WithValue(ctx, checksKey{}, checks), func() []AuthorizationCheck {
+		checks.m.Lock()
+		defer checks.m.Unlock()
+
+		return append([]AuthorizationCheck(nil), checks.checks...)
+	}
+}
+
+// CopyAuthorizationChecksContext returns a copy of ctx which records its
+// authorization checks along with those of from, if from records them.
+func CopyAuthorizationChecksContext(ctx context.Context, from context.Context) context.Context {
+	if checks, ok := from.Value(checksKey{}).(*authorizationChecks); ok {
+		return context.WithValue(ctx, checksKey{}, checks)
+	}
+
+	return ctx
+}
@@ -32,7 +32,10 @@ import (
 // cfg.Reify. A config must be reified before first use.
 type Config struct {
 	Authenticator string
-	Rules         []*struct {
+	// OIDC configures the built-in authenticator, when Authenticator is
+	// "oidc".
+	OIDC  *OIDCConfig
+	Rules []*struct {
 		Resource string
 		Actions  []string
 		Subjects []string
@@ -134,6 +137,13 @@ func (c *Config) Reify() error {
 			return err
 		}
 
+		c.authenticator = authn
+	case c.Authenticator == OIDCAuthenticatorName:
+		authn, err := NewOIDCAuthenticator(c.OIDC)
+		if err != nil {
+			return err
+		}
+
 		c.authenticator = authn
 	case c.Authenticator != "":
 		factory, ok := authenticators[c.Authenticator]
@@ -174,6 +184,7 @@ func DefaultConfig() *Config {
 		string(DeleteAction),
 		string(PutAction),
 		string(CompleteAction),
+		string(CancelAction),
 		string(PingAction),
 		string(ReloadAction),
 		string(EmergencyFailoverShardAction),
@@ -182,6 +193,7 @@ func DefaultConfig() *Config {
 		string(ManageTabletReplicationAction),
 		string(ManageTabletWritabilityAction),
 		string(RefreshTabletReplicationSourceAction),
+		string(SwitchWorkflowTrafficAction),
 	}
 	subjects := []string{"*"}
 	clusters := []string{"*"}
@@ -0,0 +1,400 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"google.golang.org/grpc/metadata"
+
+	"vitess.io/vitess/go/sets"
+)
+
+// OIDCAuthenticatorName is the name of the built-in authenticator which
+// validates OIDC ID tokens and other bearer JWTs. It is configured by the
+// oidc section of the rbac config.
+const OIDCAuthenticatorName = "oidc"
+
+var (
+	// ErrMissingToken is returned by the OIDCAuthenticator when a request
+	// has no bearer token.
+	ErrMissingToken = errors.New("missing bearer token")
+
+	// oidcSignatureAlgorithms are the algorithms the tokens may be signed
+	// with. Only asymmetric algorithms are allowed, as the keys come from
+	// a JWKS endpoint.
+	oidcSignatureAlgorithms = []jose.SignatureAlgorithm{
+		jose.RS256, jose.RS384, jose.RS512,
+		jose.PS256, jose.PS384, jose.PS512,
+		jose.ES256, jose.ES384, jose.ES512,
+		jose.EdDSA,
+	}
+
+	// oidcKeysMinRefreshInterval limits how often the JWKS endpoint is
+	// fetched, including again after a failed fetch or when a token is
+	// signed with an unknown key.
+	oidcKeysMinRefreshInterval = time.Minute
+)
+
+// OIDCConfig is the configuration of the OIDC authenticator, in the oidc
+// section of the rbac config. For example:
+//
+//	authenticator: oidc
+//	oidc:
+//	  issuer: https://accounts.example.com
+//	  audiences: ["vtadmin"]
+//	  group_roles:
+//	    - group: vitess-admins
+//	      roles: ["admin"]
+//	rules:
+//	  - resource: "*"
+//	    actions: ["*"]
+//	    subjects: ["role:admin"]
+//	    clusters: ["*"]
+type OIDCConfig struct {
+	// Issuer is the expected "iss" claim of the tokens. If JWKSURL is not
+	// set, the keys are found through the OpenID discovery document of
+	// the issuer.
+	Issuer string
+	// JWKSURL is the URL of the JSON Web Key Set the tokens are signed
+	// with.
+	JWKSURL string `mapstructure:"jwks_url"`
+	// Audiences are the accepted "aud" claims. A token must have at least
+	// one of them. Required, so that the tokens issued to other clients of
+	// the identity provider are not accepted.
+	Audiences []string
+	// SubjectClaim is the claim used as the name of the actor. Defaults to
+	// "sub".
+	SubjectClaim string `mapstructure:"subject_claim"`
+	// GroupsClaim is the claim holding the groups of the actor. Defaults
+	// to "groups".
+	GroupsClaim string `mapstructure:"groups_claim"`
+	// CookieName is the name of a cookie holding the token, for HTTP
+	// requests without an Authorization header.
+	CookieName string `mapstructure:"cookie_name"`
+	// GroupRoles maps the groups to the roles used in the rules. If empty,
+	// the groups are used as roles.
+	GroupRoles []*struct {
+		Group string
+		Roles []string
+	} `mapstructure:"group_roles"`
+}
+
+// OIDCAuthenticator is an Authenticator which validates OIDC ID tokens and
+// other bearer JWTs against the keys of a JWKS endpoint. The token is taken
+// from the "authorization" metadata of gRPC requests, and from the
+// Authorization header or the configured cookie of HTTP requests.
+type OIDCAuthenticator struct {
+	cfg        OIDCConfig
+	groupRoles map[string][]string
+	client     *http.Client
+
+	m           sync.Mutex
+	jwksURL     string
+	keys        *jose.JSONWebKeySet
+	lastFetched time.Time
+	// fetchErr is the error of the last fetch of the keys, if it failed.
+	fetchErr error
+	// fetching is closed when the fetch of the keys in progress, if any,
+	// is done.
+	fetching chan struct{}
+}
+
+var _ Authenticator = (*OIDCAuthenticator)(nil)
+
+// NewOIDCAuthenticator returns an OIDCAuthenticator for the given config.
+// The keys are fetched on first use, so the identity provider need not be
+// reachable when vtadmin starts.
+func NewOIDCAuthenticator(cfg *OIDCConfig) (*OIDCAuthenticator, error) {
+	if cfg == nil || (cfg.Issuer == "" && cfg.JWKSURL == "") {
+		return nil, errors.New("oidc authenticator requires oidc.issuer or oidc.jwks_url")
+	}
+	if len(cfg.Audiences) == 0 {
+		return nil, errors.New("oidc authenticator requires oidc.audiences")
+	}
+
+	authn := &OIDCAuthenticator{
+		cfg:     *cfg,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		jwksURL: cfg.JWKSURL,
+	}
+	if authn.cfg.SubjectClaim == "" {
+		authn.cfg.SubjectClaim = "sub"
+	}
+	if authn.cfg.GroupsClaim == "" {
+		authn.cfg.GroupsClaim = "groups"
+	}
+
+	if len(cfg.GroupRoles) > 0 {
+		authn.groupRoles = make(map[string][]string, len(cfg.GroupRoles))
+		for i, mapping := range cfg.GroupRoles {
+			if mapping == nil || mapping.Group == "" {
+				return nil, fmt.Errorf("oidc.group_roles %d: group must not be empty", i)
+			}
+			authn.groupRoles[mapping.Group] = append(authn.groupRoles[mapping.Group], mapping.Roles...)
+		}
+	}
+
+	return authn, nil
+}
+
+// Authenticate is part of the Authenticator interface.
+func (authn *OIDCAuthenticator) Authenticate(ctx context.Context) (*Actor, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if token, ok := bearerToken(value); ok {
+			return authn.verify(ctx, token)
+		}
+	}
+
+	return nil, ErrMissingToken
+}
+
+// AuthenticateHTTP is part of the Authenticator interface.
+func (authn *OIDCAuthenticator) AuthenticateHTTP(r *http.Request) (*Actor, error) {
+	if token, ok := bearerToken(r.Header.Get("Authorization")); ok {
+		return authn.verify(r.Context(), token)
+	}
+
+	if authn.cfg.CookieName != "" {
+		if cookie, err := r.Cookie(authn.cfg.CookieName); err == nil && cookie.Value != "" {
+			return authn.verify(r.Context(), cookie.Value)
+		}
+	}
+
+	return nil, ErrMissingToken
+}
+
+func bearerToken(value string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(value), " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// verify validates the signature and the claims of a token, and returns
+// the actor it identifies.
+func (authn *OIDCAuthenticator) verify(ctx context.Context, raw string) (*Actor, error) {
+	token, err := jwt.ParseSigned(raw, oidcSignatureAlgorithms)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	if len(token.Headers) != 1 {
+		return nil, errors.New("invalid token: expected a single signature")
+	}
+
+	key, err := authn.key(ctx, token.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		claims jwt.Claims
+		extra  map[string]any
+	)
+	if err := token.Claims(key, &claims, &extra); err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	expected := jwt.Expected{
+		Issuer:      authn.cfg.Issuer,
+		AnyAudience: authn.cfg.Audiences,
+		Time:        time.Now(),
+	}
+	if err := claims.ValidateWithLeeway(expected, jwt.DefaultLeeway); err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	name, _ := extra[authn.cfg.SubjectClaim].(string)
+	if name == "" {
+		return nil, fmt.Errorf("invalid token: missing %s claim", authn.cfg.SubjectClaim)
+	}
+
+	return &Actor{
+		Name:  name,
+		Roles: authn.roles(claimStrings(extra[authn.cfg.GroupsClaim])),
+	}, nil
+}
+
+// claimStrings returns the values of a claim which is either a string or
+// a list of strings.
+func claimStrings(claim any) []string {
+	switch claim := claim.(type) {
+	case string:
+		return []string{claim}
+	case []any:
+		values := make([]string, 0, len(claim))
+		for _, v := range claim {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
+
+// roles maps the groups of an actor to its roles.
+func (authn *OIDCAuthenticator) roles(groups []string) []string {
+	if authn.groupRoles == nil {
+		return groups
+	}
+
+	roles := sets.New[string]()
+	for _, group := range groups {
+		roles.Insert(authn.groupRoles[group]...)
+	}
+
+	return sets.List(roles)
+}
+
+// key returns the key with the given ID. The keys are fetched again if there
+// is no such key, at most once every oidcKeysMinRefreshInterval, so keys
+// added by a rotation are found. The keys are fetched without holding
+// authn.m, and concurrent requests wait for the fetch in progress.
+func (authn *OIDCAuthenticator) key(ctx context.Context, kid string) (*jose.JSONWebKey, error) {
+	for {
+		authn.m.Lock()
+		if key := findKey(authn.keys, kid); key != nil {
+			authn.m.Unlock()
+			return key, nil
+		}
+
+		if fetching := authn.fetching; fetching != nil {
+			authn.m.Unlock()
+			select {
+			case <-fetching:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		if !authn.lastFetched.IsZero() && time.Since(authn.lastFetched) < oidcKeysMinRefreshInterval {
+			err := authn.fetchErr
+			authn.m.Unlock()
+			if err != nil {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf("invalid token: unknown signing key %q", kid)
+		}
+
+		fetching := make(chan struct{})
+		authn.fetching = fetching
+		authn.m.Unlock()
+
+		// The keys are shared by all the requests, so the fetch is not
+		// canceled with the request that started it.
+		keys, err := authn.fetchKeys(context.WithoutCancel(ctx))
+
+		authn.m.Lock()
+		if err == nil {
+			authn.keys = keys
+		}
+		authn.fetchErr = err
+		authn.lastFetched = time.Now()
+		authn.fetching = nil
+		close(fetching)
+		authn.m.Unlock()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func findKey(keys *jose.JSONWebKeySet, kid string) *jose.JSONWebKey {
+	if keys == nil {
+		return nil
+	}
+
+	if kid == "" {
+		// Tokens without a key ID are only accepted if there is a single
+		// key to check them with.
+		if len(keys.Keys) == 1 {
+			return &keys.Keys[0]
+		}
+
+		return nil
+	}
+
+	if found := keys.Key(kid); len(found) > 0 {
+		return &found[0]
+	}
+
+	return nil
+}
+
+// fetchKeys fetches the JWKS, finding its URL through the discovery document
+// of the issuer first if needed. Only one fetch runs at a time.
+func (authn *OIDCAuthenticator) fetchKeys(ctx context.Context) (*jose.JSONWebKeySet, error) {
+	if authn.jwksURL == "" {
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+
+		if err := authn.getJSON(ctx, strings.TrimSuffix(authn.cfg.Issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+			return nil, fmt.Errorf("failed to fetch the OpenID configuration of %s: %w", authn.cfg.Issuer, err)
+		}
+
+		if discovery.JWKSURI == "" {
+			return nil, fmt.Errorf("the OpenID configuration of %s has no jwks_uri", authn.cfg.Issuer)
+		}
+
+		authn.jwksURL = discovery.JWKSURI
+	}
+
+	var keys jose.JSONWebKeySet
+	if err := authn.getJSON(ctx, authn.jwksURL, &keys); err != nil {
+		return nil, fmt.Errorf("failed to fetch the signing keys from %s: %w", authn.jwksURL, err)
+	}
+
+	return &keys, nil
+}
+
+func (authn *OIDCAuthenticator) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := authn.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,360 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+// fakeIssuer is a local stand-in for an OIDC identity provider, serving a
+// discovery document and a JWKS, and signing tokens.
+type fakeIssuer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+	kid string
+
+	// fetches counts the requests for the JWKS.
+	fetches atomic.Int32
+	// unavailable makes the JWKS requests fail.
+	unavailable atomic.Bool
+	// blocked makes the JWKS requests wait until unblock is closed.
+	blocked atomic.Bool
+	unblock chan struct{}
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	issuer := &fakeIssuer{key: key, kid: "key1", unblock: make(chan struct{})}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   issuer.URL,
+			"jwks_uri": issuer.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		issuer.fetches.Add(1)
+		if issuer.blocked.Load() {
+			<-issuer.unblock
+		}
+		if issuer.unavailable.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{{
+				Key:       &issuer.key.PublicKey,
+				KeyID:     issuer.kid,
+				Algorithm: string(jose.RS256),
+				Use:       "sig",
+			}},
+		})
+	})
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+
+	return issuer
+}
+
+func (issuer *fakeIssuer) token(t *testing.T, claims map[string]any) string {
+	return issuer.tokenWithKeyID(t, issuer.kid, claims)
+}
+
+func (issuer *fakeIssuer) tokenWithKeyID(t *testing.T, kid string, claims map[string]any) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: issuer.key}, (&jose.SignerOptions{}).WithHeader(jose.HeaderKey("kid"), kid))
+	require.NoError(t, err)
+
+	token, err := jwt.Signed(signer).Claims(claims).Serialize()
+	require.NoError(t, err)
+
+	return token
+}
+
+func TestOIDCAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	issuer := newFakeIssuer(t)
+	authn, err := NewOIDCAuthenticator(&OIDCConfig{
+		Issuer:     issuer.URL,
+		Audiences:  []string{"vtadmin"},
+		CookieName: "vtadmin_token",
+		GroupRoles: []*struct {
+			Group string
+			Roles []string
+		}{
+			{Group: "DBAs", Roles: []string{"dba", "dev"}},
+			{Group: "Developers", Roles: []string{"dev"}},
+		},
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	claims := func(overrides map[string]any) map[string]any {
+		c := map[string]any{
+			"iss":    issuer.URL,
+			"aud":    "vtadmin",
+			"sub":    "alice",
+			"exp":    now.Add(time.Hour).Unix(),
+			"iat":    now.Unix(),
+			"groups": []string{"DBAs", "Developers", "Other"},
+		}
+		for k, v := range overrides {
+			c[k] = v
+		}
+		return c
+	}
+
+	tests := []struct {
+		name          string
+		claims        map[string]any
+		expected      *Actor
+		expectedError string
+	}{
+		{
+			name:     "valid token",
+			claims:   claims(nil),
+			expected: &Actor{Name: "alice", Roles: []string{"dba", "dev"}},
+		},
+		{
+			name:     "single group",
+			claims:   claims(map[string]any{"groups": "Developers"}),
+			expected: &Actor{Name: "alice", Roles: []string{"dev"}},
+		},
+		{
+			name:          "expired",
+			claims:        claims(map[string]any{"exp": now.Add(-time.Hour).Unix()}),
+			expectedError: "expired",
+		},
+		{
+			name:          "wrong audience",
+			claims:        claims(map[string]any{"aud": "other"}),
+			expectedError: "audience",
+		},
+		{
+			name:          "wrong issuer",
+			claims:        claims(map[string]any{"iss": "https://other.example.com"}),
+			expectedError: "issuer",
+		},
+		{
+			name:          "missing subject",
+			claims:        claims(map[string]any{"sub": ""}),
+			expectedError: "missing sub claim",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := issuer.token(t, tt.claims)
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+			actor, err := authn.Authenticate(ctx)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actor)
+
+			r := httptest.NewRequest(http.MethodGet, "/api/clusters", nil)
+			r.Header.Set("Authorization", "Bearer "+token)
+			actor, err = authn.AuthenticateHTTP(r)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actor)
+
+			r = httptest.NewRequest(http.MethodGet, "/api/clusters", nil)
+			r.AddCookie(&http.Cookie{Name: "vtadmin_token", Value: token})
+			actor, err = authn.AuthenticateHTTP(r)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actor)
+		})
+	}
+
+	t.Run("missing token", func(t *testing.T) {
+		_, err := authn.Authenticate(context.Background())
+		assert.ErrorIs(t, err, ErrMissingToken)
+
+		_, err = authn.AuthenticateHTTP(httptest.NewRequest(http.MethodGet, "/api/clusters", nil))
+		assert.ErrorIs(t, err, ErrMissingToken)
+	})
+
+	t.Run("untrusted key", func(t *testing.T) {
+		other := newFakeIssuer(t)
+		token := other.token(t, claims(nil))
+
+		r := httptest.NewRequest(http.MethodGet, "/api/clusters", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		_, err := authn.AuthenticateHTTP(r)
+		assert.ErrorContains(t, err, "invalid token")
+	})
+}
+
+func TestOIDCAuthenticatorConfig(t *testing.T) {
+	t.Parallel()
+
+	issuer := newFakeIssuer(t)
+	cfg := &Config{
+		Authenticator: OIDCAuthenticatorName,
+		OIDC: &OIDCConfig{
+			JWKSURL:   issuer.URL + "/keys",
+			Audiences: []string{"vtadmin"},
+		},
+	}
+	require.NoError(t, cfg.Reify())
+
+	// Without group mappings, the groups are used as roles.
+	r := httptest.NewRequest(http.MethodGet, "/api/clusters", nil)
+	r.Header.Set("Authorization", "Bearer "+issuer.token(t, map[string]any{
+		"aud":    "vtadmin",
+		"sub":    "bob",
+		"groups": []string{"dev"},
+	}))
+	actor, err := cfg.GetAuthenticator().AuthenticateHTTP(r)
+	require.NoError(t, err)
+	assert.Equal(t, &Actor{Name: "bob", Roles: []string{"dev"}}, actor)
+
+	err = (&Config{Authenticator: OIDCAuthenticatorName}).Reify()
+	assert.ErrorContains(t, err, "requires oidc.issuer or oidc.jwks_url")
+
+	// Without audiences, the tokens issued to any client of the identity
+	// provider would be accepted.
+	err = (&Config{Authenticator: OIDCAuthenticatorName, OIDC: &OIDCConfig{Issuer: issuer.URL}}).Reify()
+	assert.ErrorContains(t, err, "requires oidc.audiences")
+}
+
+func TestOIDCAuthenticatorKeyFetches(t *testing.T) {
+	t.Parallel()
+
+	authenticate := func(authn *OIDCAuthenticator, token string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		_, err := authn.Authenticate(metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token)))
+		return err
+	}
+	claims := map[string]any{
+		"aud": "vtadmin",
+		"sub": "alice",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+
+	t.Run("failed fetch", func(t *testing.T) {
+		issuer := newFakeIssuer(t)
+		issuer.unavailable.Store(true)
+		authn, err := NewOIDCAuthenticator(&OIDCConfig{JWKSURL: issuer.URL + "/keys", Audiences: []string{"vtadmin"}})
+		require.NoError(t, err)
+
+		token := issuer.token(t, claims)
+		assert.ErrorContains(t, authenticate(authn, token), "unexpected status 503")
+
+		// The keys are not fetched again until oidcKeysMinRefreshInterval
+		// has passed, even though none were ever fetched.
+		issuer.unavailable.Store(false)
+		assert.ErrorContains(t, authenticate(authn, token), "unexpected status 503")
+		assert.EqualValues(t, 1, issuer.fetches.Load())
+	})
+
+	t.Run("concurrent fetches", func(t *testing.T) {
+		issuer := newFakeIssuer(t)
+		authn, err := NewOIDCAuthenticator(&OIDCConfig{JWKSURL: issuer.URL + "/keys", Audiences: []string{"vtadmin"}})
+		require.NoError(t, err)
+
+		token := issuer.token(t, claims)
+		require.NoError(t, authenticate(authn, token))
+
+		// Tokens signed with an unknown key fetch the keys again, once.
+		authn.m.Lock()
+		authn.lastFetched = time.Now().Add(-oidcKeysMinRefreshInterval)
+		authn.m.Unlock()
+		issuer.blocked.Store(true)
+
+		unknown := issuer.tokenWithKeyID(t, "key2", claims)
+		var wg sync.WaitGroup
+		errs := make([]error, 5)
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = authenticate(authn, unknown)
+			}()
+		}
+
+		// Tokens signed with a known key are verified while the keys are
+		// fetched.
+		require.Eventually(t, func() bool { return issuer.fetches.Load() == 2 }, 5*time.Second, 10*time.Millisecond)
+		require.NoError(t, authenticate(authn, token))
+
+		close(issuer.unblock)
+		wg.Wait()
+		for _, err := range errs {
+			assert.ErrorContains(t, err, `unknown signing key "key2"`)
+		}
+		assert.EqualValues(t, 2, issuer.fetches.Load())
+	})
+}
+
+func TestLoadOIDCConfig(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "rbac.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+authenticator: oidc
+oidc:
+  issuer: https://accounts.example.com
+  jwks_url: https://accounts.example.com/keys
+  audiences: ["vtadmin"]
+  groups_claim: roles
+  group_roles:
+    - group: Vitess-Admins
+      roles: ["admin"]
+rules:
+  - resource: "*"
+    actions: ["*"]
+    subjects: ["role:admin"]
+    clusters: ["*"]
+`), 0o644))
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.OIDC)
+	assert.Equal(t, "https://accounts.example.com/keys", cfg.OIDC.JWKSURL)
+	assert.Equal(t, "roles", cfg.OIDC.GroupsClaim)
+	require.Len(t, cfg.OIDC.GroupRoles, 1)
+	assert.Equal(t, "Vitess-Admins", cfg.OIDC.GroupRoles[0].Group)
+
+	authn, ok := cfg.GetAuthenticator().(*OIDCAuthenticator)
+	require.True(t, ok)
+	assert.Equal(t, []string{"admin"}, authn.roles([]string{"Vitess-Admins"}))
+}
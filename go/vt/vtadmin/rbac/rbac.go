@@ -41,6 +41,10 @@ registered at runtime via the rbac.RegisterAuthenticator method, or may be set
 as a Go plugin (built via `go build -buildmode=plugin`) by setting the
 authenticator name as a path ending in ".so" in the rbac config.
 
+VTAdmin also ships an "oidc" authenticator, which validates OIDC ID tokens and
+other bearer JWTs against a JWKS endpoint, and maps the groups of the token to
+roles. It is configured by the oidc section of the rbac config; see OIDCConfig.
+
 2. Permissions are additive. There is no concept of a negative permission (or
 revocation). To "revoke" a permission from a user or role, structure your rules
 such that they are never granted that permission.
@@ -94,6 +98,10 @@ const (
 	ManageTabletReplicationAction        Action = "manage_tablet_replication" // Start/Stop Replication
 	ManageTabletWritabilityAction        Action = "manage_tablet_writability" // SetRead{Only,Write}
 	RefreshTabletReplicationSourceAction Action = "refresh_tablet_replication_source"
+
+	/* workflow-specific actions */
+
+	SwitchWorkflowTrafficAction Action = "switch_workflow_traffic" // Switch and reverse traffic
 )
 
 // Resource is an enum representing all resources managed by vtadmin.
@@ -139,4 +147,8 @@ const (
 	VExplainResource Resource = "VExplain"
 
 	TabletFullStatusResource Resource = "TabletFullStatus"
+
+	AuditRecordResource Resource = "AuditRecord"
+
+	QueryInsightsResource Resource = "QueryInsights"
 )
@@ -117,6 +117,10 @@ type VtctldClient struct {
 		Response *vtctldatapb.LaunchSchemaMigrationResponse
 		Error    error
 	}
+	MoveTablesCompleteResults map[string]struct {
+		Response *vtctldatapb.MoveTablesCompleteResponse
+		Error    error
+	}
 	PingTabletResults           map[string]error
 	PlannedReparentShardResults map[string]struct {
 		Response *vtctldatapb.PlannedReparentShardResponse
@@ -167,6 +171,14 @@ type VtctldClient struct {
 		Response *vtctldatapb.ValidateVersionKeyspaceResponse
 		Error    error
 	}
+	WorkflowDeleteResults map[string]struct {
+		Response *vtctldatapb.WorkflowDeleteResponse
+		Error    error
+	}
+	WorkflowSwitchTrafficResults map[string]struct {
+		Response *vtctldatapb.WorkflowSwitchTrafficResponse
+		Error    error
+	}
 	WorkflowUpdateResults map[string]struct {
 		Response *vtctldatapb.WorkflowUpdateResponse
 		Error    error
@@ -545,6 +557,19 @@ func (fake *VtctldClient) LaunchSchemaMigration(ctx context.Context, req *vtctld
 	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
 }
 
+// MoveTablesComplete is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) MoveTablesComplete(ctx context.Context, req *vtctldatapb.MoveTablesCompleteRequest, opts ...grpc.CallOption) (*vtctldatapb.MoveTablesCompleteResponse, error) {
+	if fake.MoveTablesCompleteResults == nil {
+		return nil, fmt.Errorf("%w: MoveTablesCompleteResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	if result, ok := fake.MoveTablesCompleteResults[req.TargetKeyspace]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, req.TargetKeyspace)
+}
+
 // PingTablet is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) PingTablet(ctx context.Context, req *vtctldatapb.PingTabletRequest, opts ...grpc.CallOption) (*vtctldatapb.PingTabletResponse, error) {
 	if fake.PingTabletResults == nil {
@@ -807,6 +832,32 @@ func (fake *VtctldClient) ValidateVersionKeyspace(ctx context.Context, req *vtct
 	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
 }
 
+// WorkflowDelete is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) WorkflowDelete(ctx context.Context, req *vtctldatapb.WorkflowDeleteRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowDeleteResponse, error) {
+	if fake.WorkflowDeleteResults == nil {
+		return nil, fmt.Errorf("%w: WorkflowDeleteResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	if result, ok := fake.WorkflowDeleteResults[req.Keyspace]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, req.Keyspace)
+}
+
+// WorkflowSwitchTraffic is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) WorkflowSwitchTraffic(ctx context.Context, req *vtctldatapb.WorkflowSwitchTrafficRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowSwitchTrafficResponse, error) {
+	if fake.WorkflowSwitchTrafficResults == nil {
+		return nil, fmt.Errorf("%w: WorkflowSwitchTrafficResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	if result, ok := fake.WorkflowSwitchTrafficResults[req.Keyspace]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, req.Keyspace)
+}
+
 // WorkflowUpdate is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) WorkflowUpdate(ctx context.Context, req *vtctldatapb.WorkflowUpdateRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowUpdateResponse, error) {
 	if fake.WorkflowUpdateResults == nil {
@@ -52,6 +52,13 @@ type DB interface {
 	// VExplain executes query - `vexplain [ALL|PLAN|QUERIES|TRACE|KEYS] query` and returns the results
 	VExplain(ctx context.Context, query string, vexplainStmt *sqlparser.VExplainStmt) (*vtadminpb.VExplainResponse, error)
 
+	// QueryContext executes a query which returns rows. It behaves like
+	// (*sql.DB).QueryContext.
+	QueryContext(ctx context.Context, query string) (*sql.Rows, error)
+	// ExecContext executes a query which returns no rows. It behaves like
+	// (*sql.DB).ExecContext.
+	ExecContext(ctx context.Context, query string) (sql.Result, error)
+
 	// Ping behaves like (*sql.DB).Ping.
 	Ping() error
 	// PingContext behaves like (*sql.DB).PingContext.
@@ -181,6 +188,26 @@ func (vtgate *VTGateProxy) ShowTablets(ctx context.Context) (*sql.Rows, error) {
 	return vtgate.conn.QueryContext(vtgate.getQueryContext(ctx), "SHOW vitess_tablets")
 }
 
+// QueryContext is part of the DB interface.
+func (vtgate *VTGateProxy) QueryContext(ctx context.Context, query string) (*sql.Rows, error) {
+	span, ctx := trace.NewSpan(ctx, "VTGateProxy.QueryContext")
+	defer span.Finish()
+
+	vtadminproto.AnnotateClusterSpan(vtgate.cluster, span)
+
+	return vtgate.conn.QueryContext(vtgate.getQueryContext(ctx), query)
+}
+
+// ExecContext is part of the DB interface.
+func (vtgate *VTGateProxy) ExecContext(ctx context.Context, query string) (sql.Result, error) {
+	span, ctx := trace.NewSpan(ctx, "VTGateProxy.ExecContext")
+	defer span.Finish()
+
+	vtadminproto.AnnotateClusterSpan(vtgate.cluster, span)
+
+	return vtgate.conn.ExecContext(vtgate.getQueryContext(ctx), query)
+}
+
 // VExplain is part of the DB interface.
 func (vtgate *VTGateProxy) VExplain(ctx context.Context, query string, vexplainStmt *sqlparser.VExplainStmt) (*vtadminpb.VExplainResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "VTGateProxy.VExplain")
@@ -839,6 +839,15 @@ func (client *gRPCVtctldClient) RestoreFromBackup(ctx context.Context, in *vtctl
 	return client.c.RestoreFromBackup(ctx, in, opts...)
 }
 
+// RestorePlan is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) RestorePlan(ctx context.Context, in *vtctldatapb.RestorePlanRequest, opts ...grpc.CallOption) (*vtctldatapb.RestorePlanResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.RestorePlan(ctx, in, opts...)
+}
+
 // RetrySchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) RetrySchemaMigration(ctx context.Context, in *vtctldatapb.RetrySchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.RetrySchemaMigrationResponse, error) {
 	if client.c == nil {
@@ -956,6 +965,15 @@ func (client *gRPCVtctldClient) SourceShardDelete(ctx context.Context, in *vtctl
 	return client.c.SourceShardDelete(ctx, in, opts...)
 }
 
+// SplitTableCreate is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) SplitTableCreate(ctx context.Context, in *vtctldatapb.SplitTableCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowStatusResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.SplitTableCreate(ctx, in, opts...)
+}
+
 // StartReplication is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) StartReplication(ctx context.Context, in *vtctldatapb.StartReplicationRequest, opts ...grpc.CallOption) (*vtctldatapb.StartReplicationResponse, error) {
 	if client.c == nil {
@@ -983,6 +1001,24 @@ func (client *gRPCVtctldClient) TabletExternallyReparented(ctx context.Context,
 	return client.c.TabletExternallyReparented(ctx, in, opts...)
 }
 
+// TopoExport is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) TopoExport(ctx context.Context, in *vtctldatapb.TopoExportRequest, opts ...grpc.CallOption) (*vtctldatapb.TopoExportResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.TopoExport(ctx, in, opts...)
+}
+
+// TopoImport is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) TopoImport(ctx context.Context, in *vtctldatapb.TopoImportRequest, opts ...grpc.CallOption) (*vtctldatapb.TopoImportResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.TopoImport(ctx, in, opts...)
+}
+
 // UpdateCellInfo is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) UpdateCellInfo(ctx context.Context, in *vtctldatapb.UpdateCellInfoRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateCellInfoResponse, error) {
 	if client.c == nil {
@@ -37,6 +37,7 @@ import (
 	"google.golang.org/grpc"
 
 	"vitess.io/vitess/go/event"
+	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/netutil"
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sets"
@@ -68,6 +69,7 @@ import (
 	"vitess.io/vitess/go/vt/schemamanager"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/helpers"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools"
 	"vitess.io/vitess/go/vt/topotools/events"
@@ -1545,6 +1547,18 @@ func (s *VtctldServer) GetBackups(ctx context.Context, req *vtctldatapb.GetBacku
 	backupsToSkip := len(bhs) - totalBackups
 	backupsToSkipDetails := len(bhs) - totalDetailedBackups
 
+	var secondaryStatuses map[string]mysqlctlpb.BackupInfo_Status
+	if req.SecondaryStatus {
+		names := make([]string, 0, totalBackups)
+		for _, bh := range bhs[max(backupsToSkip, 0):] {
+			names = append(names, bh.Name())
+		}
+		secondaryStatuses, err = mysqlctl.GetSecondaryBackupStatuses(ctx, bucket, names)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	for i, bh := range bhs {
 		if i < backupsToSkip {
 			continue
@@ -1553,6 +1567,12 @@ func (s *VtctldServer) GetBackups(ctx context.Context, req *vtctldatapb.GetBacku
 		bi := mysqlctlproto.BackupHandleToProto(bh)
 		bi.Keyspace = req.Keyspace
 		bi.Shard = req.Shard
+		if secondaryStatuses != nil {
+			bi.SecondaryStatus = mysqlctlpb.BackupInfo_INCOMPLETE
+			if status, ok := secondaryStatuses[bh.Name()]; ok {
+				bi.SecondaryStatus = status
+			}
+		}
 
 		if req.Detailed {
 			if i >= backupsToSkipDetails { // nolint:staticcheck
@@ -3808,6 +3828,61 @@ func (s *VtctldServer) RestoreFromBackup(req *vtctldatapb.RestoreFromBackupReque
 	}
 }
 
+// RestorePlan is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) RestorePlan(ctx context.Context, req *vtctldatapb.RestorePlanRequest) (resp *vtctldatapb.RestorePlanResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.RestorePlan")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("restore_to_pos", req.RestoreToPos)
+	span.Annotate("allowed_backup_engines", strings.Join(req.AllowedBackupEngines, ","))
+
+	params := mysqlctl.RestorePlanParams{
+		Logger:               logutil.NewConsoleLogger(),
+		Keyspace:             req.Keyspace,
+		Shard:                req.Shard,
+		RestoreToTimestamp:   protoutil.TimeFromProto(req.RestoreToTimestamp).UTC(),
+		AllowedBackupEngines: req.AllowedBackupEngines,
+	}
+	if req.RestoreToPos != "" {
+		params.RestoreToPos, _, err = replication.DecodePositionMySQL56(req.RestoreToPos)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "unable to decode restore_to_pos: %s", req.RestoreToPos)
+		}
+	}
+
+	plan, err := mysqlctl.PlanRestore(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.RestorePlanResponse{
+		TotalBackupDuration: protoutil.DurationToProto(plan.TotalBackupDuration()),
+		Gaps:                plan.Gaps,
+	}
+	if plan.PathError != nil {
+		resp.Error = plan.PathError.Error()
+	}
+	for _, manifest := range plan.Path {
+		bi := mysqlctlproto.BackupHandleToProto(plan.Handle(manifest))
+		bi.Keyspace = req.Keyspace
+		bi.Shard = req.Shard
+		bi.Engine = manifest.BackupMethod
+		resp.Steps = append(resp.Steps, &vtctldatapb.RestorePlanResponse_Step{
+			Backup:       bi,
+			Incremental:  manifest.Incremental,
+			FromPosition: replication.EncodePosition(manifest.FromPosition),
+			Position:     replication.EncodePosition(manifest.Position),
+			Duration:     protoutil.DurationToProto(mysqlctl.BackupDuration(manifest)),
+		})
+	}
+
+	return resp, nil
+}
+
 // RetrySchemaMigration is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) RetrySchemaMigration(ctx context.Context, req *vtctldatapb.RetrySchemaMigrationRequest) (resp *vtctldatapb.RetrySchemaMigrationResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.RetrySchemaMigration")
@@ -4354,6 +4429,25 @@ func (s *VtctldServer) SourceShardDelete(ctx context.Context, req *vtctldatapb.S
 	return resp, err
 }
 
+// SplitTableCreate is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) SplitTableCreate(ctx context.Context, req *vtctldatapb.SplitTableCreateRequest) (resp *vtctldatapb.WorkflowStatusResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.SplitTableCreate")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("table", req.Table)
+	span.Annotate("vindex", req.Vindex)
+	span.Annotate("cells", req.Cells)
+	span.Annotate("tablet_types", req.TabletTypes)
+	span.Annotate("on_ddl", req.OnDdl)
+
+	resp, err = s.ws.SplitTableCreate(ctx, req)
+	return resp, err
+}
+
 // StartReplication is part of the vtctldservicepb.VtctldServer interface.
 func (s *VtctldServer) StartReplication(ctx context.Context, req *vtctldatapb.StartReplicationRequest) (resp *vtctldatapb.StartReplicationResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.StartReplication")
@@ -4526,6 +4620,47 @@ func (s *VtctldServer) TabletExternallyReparented(ctx context.Context, req *vtct
 	return resp, nil
 }
 
+// TopoExport is part of the vtctldservicepb.VtctldServer interface.
+func (s *VtctldServer) TopoExport(ctx context.Context, req *vtctldatapb.TopoExportRequest) (resp *vtctldatapb.TopoExportResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.TopoExport")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspaces", strings.Join(req.Keyspaces, ","))
+	span.Annotate("cells", strings.Join(req.Cells, ","))
+
+	snapshot, err := helpers.ExportSnapshot(ctx, s.ts, req.Keyspaces, req.Cells)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.TopoExportResponse{Snapshot: snapshot}, nil
+}
+
+// TopoImport is part of the vtctldservicepb.VtctldServer interface.
+func (s *VtctldServer) TopoImport(ctx context.Context, req *vtctldatapb.TopoImportRequest) (resp *vtctldatapb.TopoImportResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.TopoImport")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	if req.Snapshot == nil {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "TopoImportRequest.Snapshot must not be nil")
+		return nil, err
+	}
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("dry_run", req.DryRun)
+
+	diffs, err := helpers.ImportSnapshot(ctx, s.ts, req.Snapshot, req.Keyspace, req.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.TopoImportResponse{Diffs: diffs}, nil
+}
+
 // UpdateCellInfo is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) UpdateCellInfo(ctx context.Context, req *vtctldatapb.UpdateCellInfoRequest) (resp *vtctldatapb.UpdateCellInfoResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.UpdateCellInfo")
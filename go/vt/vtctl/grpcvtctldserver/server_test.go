@@ -45,6 +45,7 @@ import (
 	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/callerid"
 	hk "vitess.io/vitess/go/vt/hook"
+	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 	"vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/proto/vttime"
@@ -11099,6 +11100,55 @@ func TestRestoreFromBackup(t *testing.T) {
 	}
 }
 
+func TestRestorePlan(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx)
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	testutil.BackupStorage.Backups = map[string][]string{}
+
+	resp, err := vtctld.RestorePlan(ctx, &vtctldatapb.RestorePlanRequest{
+		Keyspace: "testkeyspace",
+		Shard:    "-",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Steps)
+	assert.Equal(t, mysqlctl.ErrNoBackup.Error(), resp.Error)
+
+	t.Run("invalid position", func(t *testing.T) {
+		_, err := vtctld.RestorePlan(ctx, &vtctldatapb.RestorePlanRequest{
+			Keyspace:     "testkeyspace",
+			Shard:        "-",
+			RestoreToPos: "invalid",
+		})
+		assert.ErrorContains(t, err, "unable to decode restore_to_pos")
+	})
+
+	t.Run("position and timestamp", func(t *testing.T) {
+		_, err := vtctld.RestorePlan(ctx, &vtctldatapb.RestorePlanRequest{
+			Keyspace:           "testkeyspace",
+			Shard:              "-",
+			RestoreToPos:       "16b1039f-22b6-11ed-b765-0a43f95f28a3:1-10",
+			RestoreToTimestamp: protoutil.TimeToProto(time.Now()),
+		})
+		assert.ErrorContains(t, err, "mutually exclusive")
+	})
+
+	t.Run("listbackups error", func(t *testing.T) {
+		testutil.BackupStorage.ListBackupsError = assert.AnError
+		defer func() { testutil.BackupStorage.ListBackupsError = nil }()
+
+		_, err := vtctld.RestorePlan(ctx, &vtctldatapb.RestorePlanRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		})
+		assert.Error(t, err)
+	})
+}
+
 func TestRetrySchemaMigration(t *testing.T) {
 	t.Parallel()
 
@@ -13418,6 +13468,45 @@ func TestTabletExternallyReparented(t *testing.T) {
 	}
 }
 
+func TestTopoExportImport(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+	testutil.AddKeyspace(ctx, t, ts, &vtctldatapb.Keyspace{
+		Name:     "testkeyspace",
+		Keyspace: &topodatapb.Keyspace{},
+	})
+
+	resp, err := vtctld.TopoExport(ctx, &vtctldatapb.TopoExportRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Snapshot.Keyspaces, 1)
+	assert.Equal(t, "testkeyspace", resp.Snapshot.Keyspaces[0].Name)
+	assert.Contains(t, resp.Snapshot.CellInfos, "cell1")
+
+	// Importing into a topo without the keyspace creates it.
+	ts2 := memorytopo.NewServer(ctx, "cell1")
+	vtctld2 := testutil.NewVtctldServerWithTabletManagerClient(t, ts2, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+	importResp, err := vtctld2.TopoImport(ctx, &vtctldatapb.TopoImportRequest{
+		Snapshot: resp.Snapshot,
+		Keyspace: "testkeyspace",
+	})
+	require.NoError(t, err)
+	require.Len(t, importResp.Diffs, 1)
+	assert.Equal(t, vtctldatapb.TopoDiff_CREATE, importResp.Diffs[0].Type)
+	_, err = ts2.GetKeyspace(ctx, "testkeyspace")
+	assert.NoError(t, err)
+
+	_, err = vtctld2.TopoImport(ctx, &vtctldatapb.TopoImportRequest{})
+	assert.Error(t, err)
+}
+
 func TestUpdateCellInfo(t *testing.T) {
 	t.Parallel()
 
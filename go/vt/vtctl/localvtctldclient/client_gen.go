@@ -617,6 +617,11 @@ func (client *localVtctldClient) RestoreFromBackup(ctx context.Context, in *vtct
 	return stream, nil
 }
 
+// RestorePlan is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) RestorePlan(ctx context.Context, in *vtctldatapb.RestorePlanRequest, opts ...grpc.CallOption) (*vtctldatapb.RestorePlanResponse, error) {
+	return client.s.RestorePlan(ctx, in)
+}
+
 // RetrySchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) RetrySchemaMigration(ctx context.Context, in *vtctldatapb.RetrySchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.RetrySchemaMigrationResponse, error) {
 	return client.s.RetrySchemaMigration(ctx, in)
@@ -682,6 +687,11 @@ func (client *localVtctldClient) SourceShardDelete(ctx context.Context, in *vtct
 	return client.s.SourceShardDelete(ctx, in)
 }
 
+// SplitTableCreate is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) SplitTableCreate(ctx context.Context, in *vtctldatapb.SplitTableCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowStatusResponse, error) {
+	return client.s.SplitTableCreate(ctx, in)
+}
+
 // StartReplication is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) StartReplication(ctx context.Context, in *vtctldatapb.StartReplicationRequest, opts ...grpc.CallOption) (*vtctldatapb.StartReplicationResponse, error) {
 	return client.s.StartReplication(ctx, in)
@@ -697,6 +707,16 @@ func (client *localVtctldClient) TabletExternallyReparented(ctx context.Context,
 	return client.s.TabletExternallyReparented(ctx, in)
 }
 
+// TopoExport is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) TopoExport(ctx context.Context, in *vtctldatapb.TopoExportRequest, opts ...grpc.CallOption) (*vtctldatapb.TopoExportResponse, error) {
+	return client.s.TopoExport(ctx, in)
+}
+
+// TopoImport is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) TopoImport(ctx context.Context, in *vtctldatapb.TopoImportRequest, opts ...grpc.CallOption) (*vtctldatapb.TopoImportResponse, error) {
+	return client.s.TopoImport(ctx, in)
+}
+
 // UpdateCellInfo is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) UpdateCellInfo(ctx context.Context, in *vtctldatapb.UpdateCellInfoRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateCellInfoResponse, error) {
 	return client.s.UpdateCellInfo(ctx, in)
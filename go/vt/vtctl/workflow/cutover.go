@@ -0,0 +1,509 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/ptr"
+	"vitess.io/vitess/go/textutil"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vdiff"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// cutoverPlanLockName is the name of the topo lock held while the cutover
+// plans are run, so that concurrent vtctlds don't run the same step twice.
+const cutoverPlanLockName = "cutover-plans"
+
+// maxMaintenanceWindowDuration is the longest allowed maintenance window, as
+// the windows recur every week.
+const maxMaintenanceWindowDuration = 7 * 24 * time.Hour
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseMaintenanceWindow parses a maintenance window of the form
+// "[DAYS ]HH:MM DURATION", in UTC, such as "Sat,Sun 02:00 4h" or "22:30 90m".
+// The window starts on every day when no days are given.
+func ParseMaintenanceWindow(window string) (*vtctldatapb.MaintenanceWindow, error) {
+	fields := strings.Fields(window)
+	mw := &vtctldatapb.MaintenanceWindow{}
+	switch len(fields) {
+	case 2:
+	case 3:
+		for _, day := range strings.Split(fields[0], ",") {
+			weekday, ok := weekdays[strings.ToLower(day)]
+			if !ok {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid day %q in maintenance window %q", day, window)
+			}
+			mw.Weekdays = append(mw.Weekdays, int32(weekday))
+		}
+		fields = fields[1:]
+	default:
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid maintenance window %q, expected [DAYS ]HH:MM DURATION", window)
+	}
+	start, err := time.Parse("15:04", fields[0])
+	if err != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid start time in maintenance window %q: %v", window, err)
+	}
+	mw.StartMinute = int32(start.Hour()*60 + start.Minute())
+	duration, err := time.ParseDuration(fields[1])
+	if err != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid duration in maintenance window %q: %v", window, err)
+	}
+	mw.Duration = protoutil.DurationToProto(duration)
+	if err := validateMaintenanceWindow(mw); err != nil {
+		return nil, err
+	}
+	return mw, nil
+}
+
+func validateMaintenanceWindow(mw *vtctldatapb.MaintenanceWindow) error {
+	for _, weekday := range mw.Weekdays {
+		if weekday < int32(time.Sunday) || weekday > int32(time.Saturday) {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid maintenance window weekday %d", weekday)
+		}
+	}
+	if mw.StartMinute < 0 || mw.StartMinute >= 24*60 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid maintenance window start minute %d", mw.StartMinute)
+	}
+	duration, _, err := protoutil.DurationFromProto(mw.Duration)
+	if err != nil {
+		return vterrors.Wrap(err, "invalid maintenance window duration")
+	}
+	if duration <= 0 || duration > maxMaintenanceWindowDuration {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid maintenance window duration %v, it must be positive and at most %v",
+			duration, maxMaintenanceWindowDuration)
+	}
+	return nil
+}
+
+// inMaintenanceWindow returns true if the given time is within one of the
+// given maintenance windows, or if there are none.
+func inMaintenanceWindow(windows []*vtctldatapb.MaintenanceWindow, now time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, mw := range windows {
+		duration, _, err := protoutil.DurationFromProto(mw.Duration)
+		if err != nil {
+			continue
+		}
+		// The window may have started on any of the days of the past week.
+		for days := 0; days <= 7; days++ {
+			day := midnight.AddDate(0, 0, -days)
+			if len(mw.Weekdays) > 0 && !weekdayIn(day.Weekday(), mw.Weekdays) {
+				continue
+			}
+			start := day.Add(time.Duration(mw.StartMinute) * time.Minute)
+			if !now.Before(start) && now.Before(start.Add(duration)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func weekdayIn(weekday time.Weekday, weekdays []int32) bool {
+	for _, wd := range weekdays {
+		if time.Weekday(wd) == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// validateCutoverPlan checks the cutover plan of a workflow which is being
+// created.
+func validateCutoverPlan(plan *vtctldatapb.CutoverPlan) error {
+	if plan == nil {
+		return nil
+	}
+	if plan.MaxErrorRate < 0 || plan.MaxErrorRate > 1 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid cutover plan max error rate %v, it must be between 0 and 1", plan.MaxErrorRate)
+	}
+	if _, _, err := protoutil.DurationFromProto(plan.MaxReplicationLagAllowed); err != nil {
+		return vterrors.Wrap(err, "invalid cutover plan max replication lag allowed")
+	}
+	if _, _, err := protoutil.DurationFromProto(plan.RollbackWindow); err != nil {
+		return vterrors.Wrap(err, "invalid cutover plan rollback window")
+	}
+	for _, mw := range plan.WriteWindows {
+		if err := validateMaintenanceWindow(mw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryCounts are the numbers of queries, and of query errors, served by a
+// tablet since it started.
+type queryCounts struct {
+	queries int64
+	errors  int64
+}
+
+// getTabletQueryCounts returns the query counts of the tablet at the given
+// address, from its debug vars.
+var getTabletQueryCounts = func(ctx context.Context, tabletAddr string) (*queryCounts, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+tabletAddr+"/debug/vars", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var vars struct {
+		Queries struct {
+			TotalCount int64
+		}
+		Errors map[string]int64
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&vars); err != nil {
+		return nil, err
+	}
+	counts := &queryCounts{queries: vars.Queries.TotalCount}
+	for _, count := range vars.Errors {
+		counts.errors += count
+	}
+	return counts, nil
+}
+
+// RunCutoverPlans runs the next step of the cutover plan of each workflow
+// which has one. The steps are, in order:
+//   - waiting for the copy phase to be done and for the replication lag to
+//     be below the allowed lag.
+//   - creating a VDiff, and waiting for it to complete without finding any
+//     differences, when required.
+//   - switching the read traffic.
+//   - switching the write traffic, within one of the maintenance windows.
+//   - watching the error rate of the queries served by the target primary
+//     tablets within the rollback window, and switching the traffic back and
+//     stopping the workflow when it is too high.
+//   - completing the workflow once the rollback window is over, when
+//     required.
+//
+// It returns the steps which were run, or are waited for, by keyspace.workflow.
+func (s *Server) RunCutoverPlans(ctx context.Context) (steps map[string][]string, err error) {
+	lockCtx, unlock, lockErr := s.ts.LockName(ctx, cutoverPlanLockName, "RunCutoverPlans")
+	if lockErr != nil {
+		return nil, vterrors.Wrap(lockErr, "failed to lock the cutover plans")
+	}
+	ctx = lockCtx
+	defer unlock(&err)
+
+	keyspaces, err := s.ts.GetKeyspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	steps = make(map[string][]string)
+	var errs []error
+	for _, keyspace := range keyspaces {
+		res, err := s.GetWorkflows(ctx, &vtctldatapb.GetWorkflowsRequest{Keyspace: keyspace})
+		if err != nil {
+			errs = append(errs, vterrors.Wrapf(err, "failed to get the workflows in the %s keyspace", keyspace))
+			continue
+		}
+		for _, wf := range res.Workflows {
+			// Reverse workflows inherit the options of their workflow.
+			if wf.GetOptions().GetCutoverPlan() == nil || strings.HasSuffix(wf.Name, "_reverse") {
+				continue
+			}
+			sw := &scheduledWorkflow{keyspace: keyspace, workflow: wf}
+			lr := NewLogRecorder()
+			if err := s.runCutoverPlan(ctx, sw, lr); err != nil {
+				lr.Logf("Cutover plan step failed: %v", err)
+				errs = append(errs, fmt.Errorf("failed to run the cutover plan of the %s workflow: %w", sw.name(), err))
+			}
+			if logs := lr.GetLogs(); len(logs) > 0 {
+				steps[sw.name()] = logs
+			}
+		}
+	}
+	return steps, vterrors.Aggregate(errs)
+}
+
+// runCutoverPlan runs the next step of the cutover plan of the given workflow.
+func (s *Server) runCutoverPlan(ctx context.Context, sw *scheduledWorkflow, lr *LogRecorder) error {
+	plan := sw.workflow.GetOptions().GetCutoverPlan()
+	if sw.queued() || sw.stoppedWith(CutoverRolledBack) {
+		return nil
+	}
+	ts, state, err := s.getWorkflowState(ctx, sw.keyspace, sw.workflow.Name)
+	if err != nil {
+		return err
+	}
+	if state.WorkflowType != TypeMoveTables && state.WorkflowType != TypeReshard {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cutover plans are not supported for %s workflows", state.WorkflowType)
+	}
+	maxLag, set, err := protoutil.DurationFromProto(plan.MaxReplicationLagAllowed)
+	if err != nil {
+		return err
+	}
+	if !set {
+		maxLag = DefaultTimeout
+	}
+	if state.WritesSwitched {
+		return s.watchCutover(ctx, ts, sw, plan, lr)
+	}
+
+	if !sw.copied() {
+		lr.Log("Waiting for the copy phase to be done")
+		return nil
+	}
+	for _, shardStream := range sw.workflow.ShardStreams {
+		for _, stream := range shardStream.Streams {
+			if stream.State != binlogdatapb.VReplicationWorkflowState_Running.String() {
+				lr.Logf("Waiting for stream %d on shard %s to be running, it is %s", stream.Id, stream.Shard, stream.State)
+				return nil
+			}
+		}
+	}
+	if lag := time.Duration(sw.workflow.MaxVReplicationLag) * time.Second; lag > maxLag {
+		lr.Logf("Waiting for the replication lag of %v to be below %v", lag, maxLag)
+		return nil
+	}
+	if plan.RequireVdiff {
+		if clean, err := s.cutoverVDiffClean(ctx, sw, lr); err != nil || !clean {
+			return err
+		}
+	}
+
+	req := &vtctldatapb.WorkflowSwitchTrafficRequest{
+		Keyspace:                 sw.keyspace,
+		Workflow:                 sw.workflow.Name,
+		MaxReplicationLagAllowed: protoutil.DurationToProto(maxLag),
+		EnableReverseReplication: true,
+		Direction:                int32(DirectionForward),
+	}
+	if len(state.ReplicaCellsNotSwitched) > 0 || len(state.RdonlyCellsNotSwitched) > 0 {
+		req.TabletTypes = []topodatapb.TabletType{topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY}
+		if _, err := s.WorkflowSwitchTraffic(ctx, req); err != nil {
+			return vterrors.Wrap(err, "failed to switch the read traffic")
+		}
+		lr.Log("Switched the read traffic")
+		return nil
+	}
+	if !inMaintenanceWindow(plan.WriteWindows, time.Now()) {
+		lr.Log("Waiting for a maintenance window to switch the write traffic")
+		return nil
+	}
+	req.TabletTypes = []topodatapb.TabletType{topodatapb.TabletType_PRIMARY}
+	req.InitializeTargetSequences = state.WorkflowType == TypeMoveTables
+	if _, err := s.WorkflowSwitchTraffic(ctx, req); err != nil {
+		return vterrors.Wrap(err, "failed to switch the write traffic")
+	}
+	lr.Log("Switched the write traffic")
+	return nil
+}
+
+// cutoverVDiffClean returns true if the last VDiff of the workflow completed
+// without finding any differences. It creates a VDiff if there is none.
+func (s *Server) cutoverVDiffClean(ctx context.Context, sw *scheduledWorkflow, lr *LogRecorder) (bool, error) {
+	resp, err := s.VDiffShow(ctx, &vtctldatapb.VDiffShowRequest{
+		TargetKeyspace: sw.keyspace,
+		Workflow:       sw.workflow.Name,
+		Arg:            "last",
+	})
+	if err != nil {
+		return false, vterrors.Wrap(err, "failed to get the last VDiff")
+	}
+	var uuid string
+	for _, tabletResp := range resp.TabletResponses {
+		if tabletResp.GetVdiffUuid() != "" {
+			uuid = tabletResp.GetVdiffUuid()
+		}
+	}
+	summary, err := BuildSummary(sw.keyspace, sw.workflow.Name, uuid, resp, false)
+	if err != nil {
+		return false, vterrors.Wrap(err, "failed to get the last VDiff")
+	}
+	switch {
+	case summary.Shards == "":
+		res, err := s.VDiffCreate(ctx, &vtctldatapb.VDiffCreateRequest{
+			TargetKeyspace: sw.keyspace,
+			Workflow:       sw.workflow.Name,
+			AutoRetry:      true,
+		})
+		if err != nil {
+			return false, vterrors.Wrap(err, "failed to create a VDiff")
+		}
+		lr.Logf("Created VDiff %s", res.UUID)
+		return false, nil
+	case summary.State != vdiff.CompletedState:
+		lr.Logf("Waiting for VDiff %s to complete, it is %s", summary.UUID, summary.State)
+		return false, nil
+	case summary.HasMismatch:
+		lr.Logf("VDiff %s found differences, a new VDiff must complete without finding any", summary.UUID)
+		return false, nil
+	}
+	return true, nil
+}
+
+// watchCutover watches the error rate of the queries served by the target
+// primary tablets of a workflow whose write traffic was switched, within the
+// rollback window of its cutover plan, and completes it once the window is
+// over if the plan says so.
+func (s *Server) watchCutover(ctx context.Context, ts *trafficSwitcher, sw *scheduledWorkflow, plan *vtctldatapb.CutoverPlan, lr *LogRecorder) error {
+	rollbackWindow, _, err := protoutil.DurationFromProto(plan.RollbackWindow)
+	if err != nil {
+		return err
+	}
+	// The streams of the workflow were last updated when they were frozen, as
+	// its write traffic was switched.
+	if time.Since(sw.updatedAt()) < rollbackWindow {
+		if plan.MaxErrorRate <= 0 {
+			return nil
+		}
+		errorRate, err := s.cutoverErrorRate(ctx, sw.name(), ts)
+		if err != nil {
+			return vterrors.Wrap(err, "failed to get the error rate of the target primary tablets")
+		}
+		if errorRate <= plan.MaxErrorRate {
+			return nil
+		}
+		lr.Logf("The error rate of %.4f of the target primary tablets is above %.4f, switching the traffic back", errorRate, plan.MaxErrorRate)
+		if _, err := s.WorkflowSwitchTraffic(ctx, &vtctldatapb.WorkflowSwitchTrafficRequest{
+			Keyspace:                 sw.keyspace,
+			Workflow:                 sw.workflow.Name,
+			TabletTypes:              []topodatapb.TabletType{topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY},
+			MaxReplicationLagAllowed: plan.MaxReplicationLagAllowed,
+			EnableReverseReplication: true,
+			Direction:                int32(DirectionBackward),
+		}); err != nil {
+			return vterrors.Wrap(err, "failed to switch the traffic back")
+		}
+		lr.Log("Switched the traffic back")
+		s.forgetCutoverQueryCounts(sw.name())
+		// Stop the workflow, so that its cutover is not run again until it is
+		// started by hand.
+		if _, err := s.WorkflowUpdate(ctx, &vtctldatapb.WorkflowUpdateRequest{
+			Keyspace: sw.keyspace,
+			TabletRequest: &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
+				Workflow: sw.workflow.Name,
+				State:    ptr.Of(binlogdatapb.VReplicationWorkflowState_Stopped),
+				Message:  ptr.Of(CutoverRolledBack),
+				// Don't change anything else, so pass simulated NULLs.
+				Cells:       textutil.SimulatedNullStringSlice,
+				TabletTypes: textutil.SimulatedNullTabletTypeSlice,
+			},
+		}); err != nil {
+			return vterrors.Wrap(err, "failed to stop the workflow")
+		}
+		lr.Log("Stopped the workflow")
+		return nil
+	}
+	s.forgetCutoverQueryCounts(sw.name())
+	if !plan.AutoComplete {
+		return nil
+	}
+	if _, err := s.MoveTablesComplete(ctx, &vtctldatapb.MoveTablesCompleteRequest{
+		TargetKeyspace: sw.keyspace,
+		Workflow:       sw.workflow.Name,
+	}); err != nil {
+		return vterrors.Wrap(err, "failed to complete the workflow")
+	}
+	lr.Log("Completed the workflow")
+	return nil
+}
+
+// cutoverErrorRate returns the error rate of the queries served by the target
+// primary tablets of the given workflow since they were first sampled.
+func (s *Server) cutoverErrorRate(ctx context.Context, workflow string, ts *trafficSwitcher) (float64, error) {
+	current := make(map[string]*queryCounts, len(ts.targets))
+	for _, target := range ts.targets {
+		primary := target.GetPrimary()
+		if primary == nil {
+			continue
+		}
+		counts, err := getTabletQueryCounts(ctx, primary.Addr())
+		if err != nil {
+			return 0, vterrors.Wrapf(err, "failed to get the query counts of tablet %s", primary.AliasString())
+		}
+		current[primary.AliasString()] = counts
+	}
+
+	s.cutoverMu.Lock()
+	defer s.cutoverMu.Unlock()
+	if s.cutoverQueryCounts == nil {
+		s.cutoverQueryCounts = make(map[string]map[string]*queryCounts)
+	}
+	first, ok := s.cutoverQueryCounts[workflow]
+	if !ok {
+		s.cutoverQueryCounts[workflow] = current
+		return 0, nil
+	}
+	var queries, queryErrors int64
+	for alias, counts := range current {
+		base, ok := first[alias]
+		if !ok || counts.queries < base.queries {
+			// The primary tablet changed, or restarted.
+			first[alias] = counts
+			continue
+		}
+		queries += counts.queries - base.queries
+		queryErrors += counts.errors - base.errors
+	}
+	if queries == 0 {
+		return 0, nil
+	}
+	return float64(queryErrors) / float64(queries), nil
+}
+
+func (s *Server) forgetCutoverQueryCounts(workflow string) {
+	s.cutoverMu.Lock()
+	defer s.cutoverMu.Unlock()
+	delete(s.cutoverQueryCounts, workflow)
+}
+
+// updatedAt returns when the workflow was last updated, which is the latest
+// update time of its streams.
+func (sw *scheduledWorkflow) updatedAt() time.Time {
+	var updatedAt int64
+	for _, shardStream := range sw.workflow.ShardStreams {
+		for _, stream := range shardStream.Streams {
+			if stream.TimeUpdated != nil && stream.TimeUpdated.Seconds > updatedAt {
+				updatedAt = stream.TimeUpdated.Seconds
+			}
+		}
+	}
+	return time.Unix(updatedAt, 0)
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/sqltypes"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vttimepb "vitess.io/vitess/go/vt/proto/vttime"
+)
+
+func TestParseMaintenanceWindow(t *testing.T) {
+	testCases := []struct {
+		window  string
+		want    *vtctldatapb.MaintenanceWindow
+		wantErr string
+	}{
+		{
+			window: "Sat,sun 02:00 4h",
+			want: &vtctldatapb.MaintenanceWindow{
+				Weekdays:    []int32{6, 0},
+				StartMinute: 120,
+				Duration:    &vttimepb.Duration{Seconds: 4 * 60 * 60},
+			},
+		},
+		{
+			window: "22:30 90m",
+			want: &vtctldatapb.MaintenanceWindow{
+				StartMinute: 22*60 + 30,
+				Duration:    &vttimepb.Duration{Seconds: 90 * 60},
+			},
+		},
+		{
+			window:  "Someday 02:00 4h",
+			wantErr: `invalid day "Someday" in maintenance window "Someday 02:00 4h"`,
+		},
+		{
+			window:  "02:00",
+			wantErr: `invalid maintenance window "02:00", expected [DAYS ]HH:MM DURATION`,
+		},
+		{
+			window:  "02:00 8d",
+			wantErr: `invalid duration in maintenance window "02:00 8d": time: unknown unit "d" in duration "8d"`,
+		},
+		{
+			window:  "02:00 200h",
+			wantErr: "invalid maintenance window duration 200h0m0s, it must be positive and at most 168h0m0s",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.window, func(t *testing.T) {
+			got, err := ParseMaintenanceWindow(tc.window)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestInMaintenanceWindow(t *testing.T) {
+	weekend, err := ParseMaintenanceWindow("Sat,Sun 23:00 2h")
+	require.NoError(t, err)
+	daily, err := ParseMaintenanceWindow("12:00 30m")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		windows []*vtctldatapb.MaintenanceWindow
+		now     string
+		want    bool
+	}{
+		{
+			name: "no windows",
+			now:  "2025-03-05 10:00",
+			want: true,
+		},
+		{
+			name:    "before the weekend window",
+			windows: []*vtctldatapb.MaintenanceWindow{weekend},
+			now:     "2025-03-08 22:59",
+			want:    false,
+		},
+		{
+			name:    "within the weekend window",
+			windows: []*vtctldatapb.MaintenanceWindow{weekend},
+			now:     "2025-03-08 23:00",
+			want:    true,
+		},
+		{
+			name:    "within the weekend window after midnight on Monday",
+			windows: []*vtctldatapb.MaintenanceWindow{weekend},
+			now:     "2025-03-10 00:59",
+			want:    true,
+		},
+		{
+			name:    "after the weekend window",
+			windows: []*vtctldatapb.MaintenanceWindow{weekend},
+			now:     "2025-03-10 01:00",
+			want:    false,
+		},
+		{
+			name:    "within the daily window",
+			windows: []*vtctldatapb.MaintenanceWindow{weekend, daily},
+			now:     "2025-03-05 12:15",
+			want:    true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			now, err := time.Parse("2006-01-02 15:04", tc.now)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, inMaintenanceWindow(tc.windows, now))
+		})
+	}
+}
+
+func TestValidateCutoverPlan(t *testing.T) {
+	require.NoError(t, validateCutoverPlan(nil))
+	require.NoError(t, validateCutoverPlan(&vtctldatapb.CutoverPlan{MaxErrorRate: 0.1}))
+	require.EqualError(t, validateCutoverPlan(&vtctldatapb.CutoverPlan{MaxErrorRate: 1.5}),
+		"invalid cutover plan max error rate 1.5, it must be between 0 and 1")
+	require.EqualError(t, validateCutoverPlan(&vtctldatapb.CutoverPlan{
+		WriteWindows: []*vtctldatapb.MaintenanceWindow{{StartMinute: 24 * 60, Duration: &vttimepb.Duration{Seconds: 60}}},
+	}), "invalid maintenance window start minute 1440")
+}
+
+func TestCutoverErrorRate(t *testing.T) {
+	counts := map[string]*queryCounts{}
+	defer func(f func(context.Context, string) (*queryCounts, error)) {
+		getTabletQueryCounts = f
+	}(getTabletQueryCounts)
+	getTabletQueryCounts = func(ctx context.Context, tabletAddr string) (*queryCounts, error) {
+		c, ok := counts[tabletAddr]
+		if !ok {
+			return nil, fmt.Errorf("unknown tablet %s", tabletAddr)
+		}
+		return c, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	env := newTestEnv(t, ctx, defaultCellName, &testKeyspace{
+		KeyspaceName: "source",
+		ShardNames:   []string{"0"},
+	}, &testKeyspace{
+		KeyspaceName: "target",
+		ShardNames:   []string{"-80", "80-"},
+	})
+	defer env.close()
+	env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+		"t1": {},
+	}
+	ts, _, err := env.ws.getWorkflowState(ctx, "target", "wf1")
+	require.NoError(t, err)
+	var addrs []string
+	for _, target := range ts.targets {
+		target.GetPrimary().Hostname = target.GetShard().ShardName()
+		addrs = append(addrs, target.GetPrimary().Addr())
+	}
+	require.Len(t, addrs, 2)
+
+	// The first sample is the baseline.
+	counts[addrs[0]] = &queryCounts{queries: 1000, errors: 10}
+	counts[addrs[1]] = &queryCounts{queries: 2000, errors: 20}
+	rate, err := env.ws.cutoverErrorRate(ctx, "target.wf1", ts)
+	require.NoError(t, err)
+	require.Zero(t, rate)
+
+	counts[addrs[0]] = &queryCounts{queries: 1100, errors: 10}
+	counts[addrs[1]] = &queryCounts{queries: 2100, errors: 40}
+	rate, err = env.ws.cutoverErrorRate(ctx, "target.wf1", ts)
+	require.NoError(t, err)
+	require.Equal(t, 0.1, rate)
+
+	// A restarted tablet is sampled again.
+	counts[addrs[1]] = &queryCounts{queries: 10, errors: 0}
+	rate, err = env.ws.cutoverErrorRate(ctx, "target.wf1", ts)
+	require.NoError(t, err)
+	require.Zero(t, rate)
+
+	env.ws.forgetCutoverQueryCounts("target.wf1")
+	require.Empty(t, env.ws.cutoverQueryCounts)
+}
+
+func TestRunCutoverPlans(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	testCases := []struct {
+		name      string
+		state     binlogdatapb.VReplicationWorkflowState
+		message   string
+		position  string
+		updatedAt time.Time
+		want      []string
+	}{
+		{
+			name:      "queued",
+			state:     binlogdatapb.VReplicationWorkflowState_Stopped,
+			message:   Queued,
+			updatedAt: time.Now(),
+		},
+		{
+			name:      "rolled back",
+			state:     binlogdatapb.VReplicationWorkflowState_Stopped,
+			message:   CutoverRolledBack,
+			position:  position,
+			updatedAt: time.Now(),
+		},
+		{
+			name:      "copying",
+			state:     binlogdatapb.VReplicationWorkflowState_Copying,
+			updatedAt: time.Now(),
+			want:      []string{"Waiting for the copy phase to be done"},
+		},
+		{
+			name:      "lagging",
+			state:     binlogdatapb.VReplicationWorkflowState_Running,
+			position:  position,
+			updatedAt: time.Now().Add(-time.Hour),
+			want:      []string{"Waiting for the replication lag of 1h0m0s to be below 30s"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newTestEnv(t, ctx, defaultCellName, &testKeyspace{
+				KeyspaceName: "source",
+				ShardNames:   []string{"0"},
+			}, &testKeyspace{
+				KeyspaceName: "target",
+				ShardNames:   []string{"0"},
+			})
+			defer env.close()
+			env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+				"t1": {},
+			}
+
+			env.tmc.AddVReplicationWorkflowsResponse("target/0", &tabletmanagerdatapb.ReadVReplicationWorkflowsResponse{
+				Workflows: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+					{
+						Workflow:     "wf1",
+						WorkflowType: binlogdatapb.VReplicationWorkflowType_MoveTables,
+						Options:      `{"cutover_plan":{"max_replication_lag_allowed":{"seconds":30},"require_vdiff":true}}`,
+						Streams: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{{
+							Id:          1,
+							State:       tc.state,
+							Message:     tc.message,
+							Pos:         tc.position,
+							TimeUpdated: protoutil.TimeToProto(tc.updatedAt),
+							Bls: &binlogdatapb.BinlogSource{
+								Keyspace: "source",
+								Shard:    "0",
+								Filter: &binlogdatapb.Filter{
+									Rules: []*binlogdatapb.Rule{{Match: "t1"}},
+								},
+							},
+						}},
+					},
+				},
+			})
+			env.tmc.expectVRQuery(startingTargetTabletUID, "select vrepl_id, table_name, lastpk from _vt.copy_state where vrepl_id in (1) and id in (select max(id) from _vt.copy_state where vrepl_id in (1) group by vrepl_id, table_name)", &sqltypes.Result{})
+
+			steps, err := env.ws.RunCutoverPlans(ctx)
+			require.NoError(t, err)
+			if tc.want == nil {
+				require.Empty(t, steps)
+				return
+			}
+			require.Equal(t, map[string][]string{"target.wf1": tc.want}, steps)
+		})
+	}
+}
@@ -325,6 +325,10 @@ func (mz *materializer) deploySchema() error {
 		}
 
 		var applyDDLs []string
+		targetDDLs := make(map[string]string, len(targetSchema.TableDefinitions))
+		for _, td := range targetSchema.TableDefinitions {
+			targetDDLs[td.Name] = td.Schema
+		}
 		for _, ts := range mz.ms.TableSettings {
 			if hasTargetTable[ts.TargetTable] {
 				// Table already exists.
@@ -368,6 +372,16 @@ func (mz *materializer) deploySchema() error {
 					return fmt.Errorf("source table %v does not exist", ts.TargetTable)
 				}
 
+				// The source expressions of SplitTable workflows, which are MoveTables
+				// workflows, rename columns, so we copy the columns under the names
+				// they are selected as. Materialize workflows copy the schema as is.
+				if mz.ms.MaterializationIntent == vtctldatapb.MaterializationIntent_MOVETABLES {
+					ddl, err = renameSelectedColumns(ddl, ts.SourceExpression, mz.env.Parser())
+					if err != nil {
+						return err
+					}
+				}
+
 				if createDDL == createDDLAsCopyDropConstraint {
 					strippedDDL, err := stripTableConstraints(ddl, mz.env.Parser())
 					if err != nil {
@@ -433,6 +447,45 @@ func (mz *materializer) deploySchema() error {
 			}
 
 			applyDDLs = append(applyDDLs, createDDL)
+			targetDDLs[ts.TargetTable] = createDDL
+		}
+
+		// Create the state tables of the aggregations that are recomputed
+		// from them.
+		for _, ts := range mz.ms.TableSettings {
+			if !isAggregationQuery(ts.SourceExpression, mz.env.Parser()) {
+				continue
+			}
+			aggregations, err := vreplication.AnalyzeMaterializeQuery(ts.SourceExpression, mz.env.Parser())
+			if err != nil {
+				return err
+			}
+			if len(aggregations) == 0 {
+				continue
+			}
+			sourceTableName, err := mz.env.Parser().TableFromStatement(ts.SourceExpression)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			if len(sourceDDLs) == 0 {
+				sourceDDLs, err = getSourceTableDDLs(mz.ctx, mz.sourceTs, mz.tmc, mz.sourceShards)
+			}
+			mu.Unlock()
+			if err != nil {
+				return err
+			}
+			sourceDDL, ok := sourceDDLs[sourceTableName.Name.String()]
+			if !ok {
+				return fmt.Errorf("source table %v does not exist", sqlparser.String(sourceTableName))
+			}
+			for _, aggregation := range aggregations {
+				ddl, err := vreplication.AggregateStateTableDDL(mz.env.Parser(), targetDDLs[ts.TargetTable], sourceDDL, aggregation)
+				if err != nil {
+					return vterrors.Wrapf(err, "cannot create the state table of column %s of table %s", aggregation.Column, ts.TargetTable)
+				}
+				applyDDLs = append(applyDDLs, ddl)
+			}
 		}
 
 		if len(applyDDLs) > 0 {
@@ -635,6 +688,16 @@ func (mz *materializer) validateEmptyTables() error {
 }
 
 func (mz *materializer) startStreams(ctx context.Context) error {
+	return mz.updateStreams(ctx, binlogdatapb.VReplicationWorkflowState_Running, nil)
+}
+
+// queueStreams leaves the streams of the workflow stopped and marks them as
+// queued, so that they are started by the workflow scheduler.
+func (mz *materializer) queueStreams(ctx context.Context) error {
+	return mz.updateStreams(ctx, binlogdatapb.VReplicationWorkflowState_Stopped, ptr.Of(Queued))
+}
+
+func (mz *materializer) updateStreams(ctx context.Context, state binlogdatapb.VReplicationWorkflowState, message *string) error {
 	return forAllShards(mz.targetShards, func(target *topo.ShardInfo) error {
 		targetPrimary, err := mz.ts.GetTablet(ctx, target.PrimaryAlias)
 		if err != nil {
@@ -642,7 +705,8 @@ func (mz *materializer) startStreams(ctx context.Context) error {
 		}
 		if _, err := mz.tmc.UpdateVReplicationWorkflow(ctx, targetPrimary.Tablet, &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
 			Workflow: mz.ms.Workflow,
-			State:    ptr.Of(binlogdatapb.VReplicationWorkflowState_Running),
+			State:    ptr.Of(state),
+			Message:  message,
 			// Don't change anything else, so pass simulated NULLs.
 			Cells:       textutil.SimulatedNullStringSlice,
 			TabletTypes: textutil.SimulatedNullTabletTypeSlice,
@@ -3529,3 +3529,61 @@ func TestValidateEmptyTables(t *testing.T) {
 	// Check if the error message doesn't include duplicate tables
 	assert.Equal(t, strings.Count(err.Error(), "table3"), 1)
 }
+
+func TestDeploySchemaAggregateStateTables(t *testing.T) {
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:       "workflow",
+		SourceKeyspace: "sourceks",
+		TargetKeyspace: "targetks",
+		TableSettings: []*vtctldatapb.TableMaterializeSettings{{
+			TargetTable:      "totals",
+			SourceExpression: "select c1, max(c2) as hi, count(distinct c3) as n from t1 group by c1",
+			CreateDdl:        "create table totals (c1 varchar(16) not null, hi int, n bigint, primary key (c1))",
+		}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newTestMaterializerEnv(t, ctx, ms, []string{"0"}, []string{"0"})
+	defer env.close()
+	env.tmc.schema[ms.SourceKeyspace+".t1"] = &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+			Name:   "t1",
+			Schema: "create table t1 (id int primary key, c1 varchar(16), c2 int, c3 varchar(8) collate utf8mb4_bin)",
+		}},
+	}
+
+	// The target table exists, so only the state tables are created.
+	env.tmc.expectFetchAsAllPrivsQuery(startingTargetTabletUID, "select 1 from `totals` limit 1", &sqltypes.Result{})
+	env.tmc.expectVRQuery(startingTargetTabletUID, "create table if not exists _vt_agg_totals_hi (\n\tc1 varchar(16) not null,\n\t_vt_value int not null,\n\t_vt_count bigint unsigned not null,\n\tprimary key (c1, _vt_value)\n)", &sqltypes.Result{})
+	env.tmc.expectVRQuery(startingTargetTabletUID, "\ncreate table if not exists _vt_agg_totals_n (\n\tc1 varchar(16) not null,\n\t_vt_value varchar(8) collate utf8mb4_bin not null,\n\t_vt_count bigint unsigned not null,\n\tprimary key (c1, _vt_value)\n)", &sqltypes.Result{})
+
+	mz := &materializer{
+		ctx:          ctx,
+		ts:           env.topoServ,
+		sourceTs:     env.topoServ,
+		tmc:          env.tmc,
+		ms:           ms,
+		workflowType: binlogdatapb.VReplicationWorkflowType_Materialize,
+		env:          env.venv,
+	}
+	require.NoError(t, mz.buildMaterializer())
+	require.NoError(t, mz.deploySchema())
+	require.Empty(t, env.tmc.vrQueries[startingTargetTabletUID])
+
+	// Unsupported expressions are rejected before anything is created.
+	ms.TableSettings[0].SourceExpression = "select c1, avg(c2) as hi from t1 group by c1"
+	err := validateMaterializeSettings(ms, env.venv.Parser())
+	require.ErrorContains(t, err, "invalid source expression for table totals: unsupported aggregation function: avg(c2)")
+
+	// So are aggregations in queries without a GROUP BY.
+	ms.TableSettings[0].SourceExpression = "select min(c2) as lo from t1"
+	err = validateMaterializeSettings(ms, env.venv.Parser())
+	require.ErrorContains(t, err, "invalid source expression for table totals: unsupported aggregation without a group by clause: lo")
+	ms.TableSettings[0].SourceExpression = "select c1, count(distinct c3) as n from t1"
+	err = validateMaterializeSettings(ms, env.venv.Parser())
+	require.ErrorContains(t, err, "invalid source expression for table totals: unsupported aggregation without a group by clause: n")
+
+	// Other queries are left to fail when the workflow is created.
+	ms.TableSettings[0].SourceExpression = "select c1, c2 + 1 from t1"
+	require.NoError(t, validateMaterializeSettings(ms, env.venv.Parser()))
+}
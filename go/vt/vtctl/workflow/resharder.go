@@ -336,6 +336,16 @@ func (rs *resharder) createStreams(ctx context.Context) error {
 }
 
 func (rs *resharder) startStreams(ctx context.Context) error {
+	return rs.updateStreams(ctx, binlogdatapb.VReplicationWorkflowState_Running, nil)
+}
+
+// queueStreams leaves the streams of the workflow stopped and marks them as
+// queued, so that they are started by the workflow scheduler.
+func (rs *resharder) queueStreams(ctx context.Context) error {
+	return rs.updateStreams(ctx, binlogdatapb.VReplicationWorkflowState_Stopped, ptr.Of(Queued))
+}
+
+func (rs *resharder) updateStreams(ctx context.Context, state binlogdatapb.VReplicationWorkflowState, message *string) error {
 	err := forAllShards(rs.targetShards, func(target *topo.ShardInfo) error {
 		targetPrimary := rs.targetPrimaries[target.ShardName()]
 		// This is the rare case where we truly want to update every stream/record
@@ -344,11 +354,12 @@ func (rs *resharder) startStreams(ctx context.Context) error {
 		// that we've created on the new shards as we're migrating them.
 		req := &tabletmanagerdatapb.UpdateVReplicationWorkflowsRequest{
 			AllWorkflows: true,
-			State:        ptr.Of(binlogdatapb.VReplicationWorkflowState_Running),
+			State:        ptr.Of(state),
+			Message:      message,
 		}
 		if _, err := rs.s.tmc.UpdateVReplicationWorkflows(ctx, targetPrimary.Tablet, req); err != nil {
 			return vterrors.Wrapf(err, "UpdateVReplicationWorkflows(%v, 'state='%s')",
-				targetPrimary.Tablet, binlogdatapb.VReplicationWorkflowState_Running.String())
+				targetPrimary.Tablet, state.String())
 		}
 		return nil
 	})
@@ -0,0 +1,298 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"vitess.io/vitess/go/ptr"
+	"vitess.io/vitess/go/textutil"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// workflowSchedulerLockName is the name of the topo lock held while the queued
+// workflows are scheduled, so that concurrent vtctlds don't start more
+// workflows than allowed.
+const workflowSchedulerLockName = "workflow-scheduler"
+
+// defaultMaxConcurrentWorkflows is the maximum number of workflows copying
+// from each source shard of a queued workflow whose schedule does not set it.
+const defaultMaxConcurrentWorkflows = 1
+
+// scheduledWorkflow is a workflow seen by the workflow scheduler.
+type scheduledWorkflow struct {
+	keyspace string
+	workflow *vtctldatapb.Workflow
+}
+
+// name returns the keyspace.workflow name of the workflow.
+func (sw *scheduledWorkflow) name() string {
+	return sw.keyspace + "." + sw.workflow.Name
+}
+
+// queued returns true if the workflow is waiting to be started by the
+// workflow scheduler, which is when all of its streams are stopped with the
+// queued message.
+func (sw *scheduledWorkflow) queued() bool {
+	return sw.stoppedWith(Queued)
+}
+
+// stoppedWith returns true if all of the streams of the workflow are stopped
+// with the given message.
+func (sw *scheduledWorkflow) stoppedWith(message string) bool {
+	streams := 0
+	for _, shardStream := range sw.workflow.ShardStreams {
+		for _, stream := range shardStream.Streams {
+			if stream.State != binlogdatapb.VReplicationWorkflowState_Stopped.String() || stream.Message != message {
+				return false
+			}
+			streams++
+		}
+	}
+	return streams > 0
+}
+
+// copying returns true if any stream of the workflow is running its copy
+// phase, or has yet to start it.
+func (sw *scheduledWorkflow) copying() bool {
+	if sw.queued() {
+		return false
+	}
+	for _, shardStream := range sw.workflow.ShardStreams {
+		for _, stream := range shardStream.Streams {
+			if stream.State == binlogdatapb.VReplicationWorkflowState_Stopped.String() {
+				continue
+			}
+			if len(stream.CopyStates) > 0 || stream.Position == "" ||
+				stream.State == binlogdatapb.VReplicationWorkflowState_Copying.String() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// copied returns true if all of the streams of the workflow are done with
+// their copy phase.
+func (sw *scheduledWorkflow) copied() bool {
+	if sw.queued() {
+		return false
+	}
+	for _, shardStream := range sw.workflow.ShardStreams {
+		for _, stream := range shardStream.Streams {
+			if len(stream.CopyStates) > 0 || stream.Position == "" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sourceShards returns the source keyspace shards of the workflow, as
+// keyspace/shard.
+func (sw *scheduledWorkflow) sourceShards() []string {
+	source := sw.workflow.GetSource()
+	shards := make([]string, 0, len(source.GetShards()))
+	for _, shard := range source.GetShards() {
+		shards = append(shards, topoproto.KeyspaceShardString(source.Keyspace, shard))
+	}
+	return shards
+}
+
+// maxConcurrentWorkflows returns the maximum number of workflows copying from
+// each source shard of the workflow at the same time, as set by its schedule.
+// Zero means no limit.
+func (sw *scheduledWorkflow) maxConcurrentWorkflows() int {
+	schedule := sw.workflow.GetOptions().GetSchedule()
+	if schedule == nil || schedule.MaxConcurrentWorkflows == nil {
+		return defaultMaxConcurrentWorkflows
+	}
+	return int(*schedule.MaxConcurrentWorkflows)
+}
+
+// queuedAt returns when the workflow was queued, which is the earliest update
+// time of its streams.
+func (sw *scheduledWorkflow) queuedAt() int64 {
+	queuedAt := int64(math.MaxInt64)
+	for _, shardStream := range sw.workflow.ShardStreams {
+		for _, stream := range shardStream.Streams {
+			if stream.TimeUpdated != nil && stream.TimeUpdated.Seconds < queuedAt {
+				queuedAt = stream.TimeUpdated.Seconds
+			}
+		}
+	}
+	return queuedAt
+}
+
+// parseWorkflowDependency splits a keyspace.workflow workflow dependency.
+func parseWorkflowDependency(dependency string) (string, string, error) {
+	keyspace, workflow, ok := strings.Cut(dependency, ".")
+	if !ok || keyspace == "" || workflow == "" {
+		return "", "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid workflow dependency %q, expected keyspace.workflow", dependency)
+	}
+	return keyspace, workflow, nil
+}
+
+// validateWorkflowSchedule checks that the dependencies of the given schedule
+// of a workflow which is being created exist, and that its limit is valid.
+func (s *Server) validateWorkflowSchedule(ctx context.Context, keyspace, workflow string, schedule *vtctldatapb.WorkflowSchedule) error {
+	if schedule.GetMaxConcurrentWorkflows() < 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid maximum number of concurrent workflows %d for workflow %s.%s, it cannot be negative",
+			schedule.GetMaxConcurrentWorkflows(), keyspace, workflow)
+	}
+	for _, dependency := range schedule.GetDependsOn() {
+		depKeyspace, depWorkflow, err := parseWorkflowDependency(dependency)
+		if err != nil {
+			return err
+		}
+		if depKeyspace == keyspace && depWorkflow == workflow {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "workflow %s.%s cannot depend on itself", keyspace, workflow)
+		}
+		res, err := s.GetWorkflows(ctx, &vtctldatapb.GetWorkflowsRequest{
+			Keyspace: depKeyspace,
+			Workflow: depWorkflow,
+		})
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to get the %s workflow dependency", dependency)
+		}
+		if len(res.Workflows) == 0 {
+			return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "workflow dependency %s does not exist", dependency)
+		}
+	}
+	return nil
+}
+
+// ScheduleWorkflows starts the queued workflows which are ready to start, in
+// order of priority and then of the time that they were queued. A queued
+// workflow is ready to start when the workflows that it depends on are done
+// with their copy phase, and when fewer workflows than the maximum set by its
+// schedule are copying from each of its source shards. A dependency which does
+// not exist anymore, such as a completed MoveTables workflow, is considered
+// done. It returns the started workflows, as keyspace.workflow.
+func (s *Server) ScheduleWorkflows(ctx context.Context) (started []string, err error) {
+	lockCtx, unlock, lockErr := s.ts.LockName(ctx, workflowSchedulerLockName, "ScheduleWorkflows")
+	if lockErr != nil {
+		return nil, vterrors.Wrap(lockErr, "failed to lock the workflow scheduler")
+	}
+	ctx = lockCtx
+	defer unlock(&err)
+
+	keyspaces, err := s.ts.GetKeyspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var workflows []*scheduledWorkflow
+	for _, keyspace := range keyspaces {
+		res, err := s.GetWorkflows(ctx, &vtctldatapb.GetWorkflowsRequest{Keyspace: keyspace})
+		if err != nil {
+			// We don't start workflows based on partial information, as it
+			// could exceed the concurrency limits.
+			return nil, vterrors.Wrapf(err, "failed to get the workflows in the %s keyspace", keyspace)
+		}
+		for _, wf := range res.Workflows {
+			workflows = append(workflows, &scheduledWorkflow{keyspace: keyspace, workflow: wf})
+		}
+	}
+
+	var errs []error
+	for _, sw := range workflowsToStart(workflows) {
+		if _, err := s.WorkflowUpdate(ctx, &vtctldatapb.WorkflowUpdateRequest{
+			Keyspace: sw.keyspace,
+			TabletRequest: &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
+				Workflow: sw.workflow.Name,
+				State:    ptr.Of(binlogdatapb.VReplicationWorkflowState_Running),
+				// Don't change anything else, so pass simulated NULLs.
+				Cells:       textutil.SimulatedNullStringSlice,
+				TabletTypes: textutil.SimulatedNullTabletTypeSlice,
+			},
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to start the queued %s workflow: %w", sw.name(), err))
+			continue
+		}
+		s.Logger().Infof("Started the queued %s workflow", sw.name())
+		started = append(started, sw.name())
+	}
+	return started, vterrors.Aggregate(errs)
+}
+
+// workflowsToStart returns the queued workflows, among the given ones, which
+// are ready to start, in the order in which they should be started.
+func workflowsToStart(workflows []*scheduledWorkflow) []*scheduledWorkflow {
+	byName := make(map[string]*scheduledWorkflow, len(workflows))
+	copying := make(map[string]int)
+	var queued []*scheduledWorkflow
+	for _, sw := range workflows {
+		byName[sw.name()] = sw
+		switch {
+		case sw.queued():
+			queued = append(queued, sw)
+		case sw.copying():
+			for _, shard := range sw.sourceShards() {
+				copying[shard]++
+			}
+		}
+	}
+	sort.SliceStable(queued, func(i, j int) bool {
+		pi, pj := queued[i].workflow.GetOptions().GetSchedule().GetPriority(), queued[j].workflow.GetOptions().GetSchedule().GetPriority()
+		if pi != pj {
+			return pi > pj
+		}
+		if ti, tj := queued[i].queuedAt(), queued[j].queuedAt(); ti != tj {
+			return ti < tj
+		}
+		return queued[i].name() < queued[j].name()
+	})
+
+	ready := func(sw *scheduledWorkflow) bool {
+		for _, dependency := range sw.workflow.GetOptions().GetSchedule().GetDependsOn() {
+			if dep, ok := byName[dependency]; ok && !dep.copied() {
+				return false
+			}
+		}
+		maxConcurrentWorkflows := sw.maxConcurrentWorkflows()
+		if maxConcurrentWorkflows <= 0 {
+			return true
+		}
+		for _, shard := range sw.sourceShards() {
+			if copying[shard] >= maxConcurrentWorkflows {
+				return false
+			}
+		}
+		return true
+	}
+	var toStart []*scheduledWorkflow
+	for _, sw := range queued {
+		if !ready(sw) {
+			continue
+		}
+		toStart = append(toStart, sw)
+		for _, shard := range sw.sourceShards() {
+			copying[shard]++
+		}
+	}
+	return toStart
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/ptr"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/textutil"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vttimepb "vitess.io/vitess/go/vt/proto/vttime"
+)
+
+func TestWorkflowsToStart(t *testing.T) {
+	const (
+		queued  = "queued"
+		copying = "copying"
+		copied  = "copied"
+		stopped = "stopped"
+	)
+	// newWorkflow returns a workflow in the target keyspace, copying from the
+	// given source keyspace shards.
+	newWorkflow := func(name, status string, sourceShards []string, queuedAt int64, schedule *vtctldatapb.WorkflowSchedule) *scheduledWorkflow {
+		stream := &vtctldatapb.Workflow_Stream{
+			Id:          1,
+			Shard:       "0",
+			TimeUpdated: &vttimepb.Time{Seconds: queuedAt},
+		}
+		switch status {
+		case queued:
+			stream.State = binlogdatapb.VReplicationWorkflowState_Stopped.String()
+			stream.Message = Queued
+		case copying:
+			stream.State = binlogdatapb.VReplicationWorkflowState_Copying.String()
+			stream.CopyStates = []*vtctldatapb.Workflow_Stream_CopyState{{Table: "t1"}}
+		case copied:
+			stream.State = binlogdatapb.VReplicationWorkflowState_Running.String()
+			stream.Position = position
+		case stopped:
+			stream.State = binlogdatapb.VReplicationWorkflowState_Stopped.String()
+			stream.CopyStates = []*vtctldatapb.Workflow_Stream_CopyState{{Table: "t1"}}
+		}
+		return &scheduledWorkflow{
+			keyspace: "target",
+			workflow: &vtctldatapb.Workflow{
+				Name:   name,
+				Source: &vtctldatapb.Workflow_ReplicationLocation{Keyspace: "source", Shards: sourceShards},
+				ShardStreams: map[string]*vtctldatapb.Workflow_ShardStream{
+					"0/zone1-0000000200": {Streams: []*vtctldatapb.Workflow_Stream{stream}},
+				},
+				Options: &vtctldatapb.WorkflowOptions{Schedule: schedule},
+			},
+		}
+	}
+	noLimit := &vtctldatapb.WorkflowSchedule{MaxConcurrentWorkflows: ptr.Of(int32(0))}
+	defaultLimit := &vtctldatapb.WorkflowSchedule{}
+
+	testCases := []struct {
+		name      string
+		workflows []*scheduledWorkflow
+		want      []string
+	}{
+		{
+			name: "priority then queue order",
+			workflows: []*scheduledWorkflow{
+				newWorkflow("wf1", queued, []string{"-80"}, 2, noLimit),
+				newWorkflow("wf2", queued, []string{"80-"}, 3, &vtctldatapb.WorkflowSchedule{Priority: 10}),
+				newWorkflow("wf3", queued, []string{"-80"}, 1, noLimit),
+			},
+			want: []string{"target.wf2", "target.wf3", "target.wf1"},
+		},
+		{
+			name: "max concurrent workflows per source shard",
+			workflows: []*scheduledWorkflow{
+				newWorkflow("wf1", copying, []string{"-80"}, 1, nil),
+				newWorkflow("wf2", queued, []string{"-80"}, 2, defaultLimit),
+				newWorkflow("wf3", queued, []string{"-80", "80-"}, 3, defaultLimit),
+				newWorkflow("wf4", queued, []string{"80-"}, 4, defaultLimit),
+				newWorkflow("wf5", queued, []string{"80-"}, 5, defaultLimit),
+			},
+			want: []string{"target.wf4"},
+		},
+		{
+			name: "max concurrent workflows of each workflow",
+			workflows: []*scheduledWorkflow{
+				newWorkflow("wf1", copying, []string{"-80"}, 1, nil),
+				newWorkflow("wf2", queued, []string{"-80"}, 2, &vtctldatapb.WorkflowSchedule{MaxConcurrentWorkflows: ptr.Of(int32(2))}),
+				newWorkflow("wf3", queued, []string{"-80"}, 3, &vtctldatapb.WorkflowSchedule{MaxConcurrentWorkflows: ptr.Of(int32(2))}),
+				newWorkflow("wf4", queued, []string{"-80"}, 4, noLimit),
+			},
+			want: []string{"target.wf2", "target.wf4"},
+		},
+		{
+			name: "copied and stopped workflows don't count",
+			workflows: []*scheduledWorkflow{
+				newWorkflow("wf1", copied, []string{"-80"}, 1, nil),
+				newWorkflow("wf2", stopped, []string{"-80"}, 1, nil),
+				newWorkflow("wf3", queued, []string{"-80"}, 2, defaultLimit),
+			},
+			want: []string{"target.wf3"},
+		},
+		{
+			name: "no limit",
+			workflows: []*scheduledWorkflow{
+				newWorkflow("wf1", copying, []string{"-80"}, 1, nil),
+				newWorkflow("wf2", queued, []string{"-80"}, 2, noLimit),
+				newWorkflow("wf3", queued, []string{"-80"}, 3, noLimit),
+			},
+			want: []string{"target.wf2", "target.wf3"},
+		},
+		{
+			name: "dependencies",
+			workflows: []*scheduledWorkflow{
+				newWorkflow("wf1", copying, []string{"-80"}, 1, nil),
+				newWorkflow("wf2", copied, []string{"-80"}, 1, nil),
+				newWorkflow("wf3", queued, []string{"80-"}, 2, &vtctldatapb.WorkflowSchedule{DependsOn: []string{"target.wf1"}}),
+				newWorkflow("wf4", queued, []string{"80-"}, 3, &vtctldatapb.WorkflowSchedule{DependsOn: []string{"target.wf2", "target.gone"}}),
+				newWorkflow("wf5", queued, []string{"80-"}, 4, &vtctldatapb.WorkflowSchedule{DependsOn: []string{"target.wf4"}}),
+			},
+			want: []string{"target.wf4"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, sw := range workflowsToStart(tc.workflows) {
+				got = append(got, sw.name())
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestScheduleWorkflows(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "source",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "target",
+		ShardNames:   []string{"0"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+
+	env.tmc.AddVReplicationWorkflowsResponse("target/0", &tabletmanagerdatapb.ReadVReplicationWorkflowsResponse{
+		Workflows: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+			{
+				Workflow:     "wf1",
+				WorkflowType: binlogdatapb.VReplicationWorkflowType_MoveTables,
+				Options:      `{"schedule":{"priority":1}}`,
+				Streams: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{{
+					Id:      1,
+					State:   binlogdatapb.VReplicationWorkflowState_Stopped,
+					Message: Queued,
+					Bls: &binlogdatapb.BinlogSource{
+						Keyspace: "source",
+						Shard:    "0",
+						Filter: &binlogdatapb.Filter{
+							Rules: []*binlogdatapb.Rule{{Match: "t1"}},
+						},
+					},
+				}},
+			},
+		},
+	})
+	env.tmc.expectVRQuery(startingTargetTabletUID, "select vrepl_id, table_name, lastpk from _vt.copy_state where vrepl_id in (1) and id in (select max(id) from _vt.copy_state where vrepl_id in (1) group by vrepl_id, table_name)", &sqltypes.Result{})
+	env.tmc.AddUpdateVReplicationWorkflowRequestResponse(startingTargetTabletUID, &updateVReplicationWorkflowRequestResponse{
+		req: &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
+			Workflow:    "wf1",
+			State:       ptr.Of(binlogdatapb.VReplicationWorkflowState_Running),
+			Cells:       textutil.SimulatedNullStringSlice,
+			TabletTypes: textutil.SimulatedNullTabletTypeSlice,
+		},
+		res: &tabletmanagerdatapb.UpdateVReplicationWorkflowResponse{
+			Result: &querypb.QueryResult{RowsAffected: 1},
+		},
+	})
+
+	started, err := env.ws.ScheduleWorkflows(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"target.wf1"}, started)
+}
+
+func TestValidateWorkflowSchedule(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	env := newTestEnv(t, ctx, defaultCellName, &testKeyspace{
+		KeyspaceName: "source",
+		ShardNames:   []string{"0"},
+	}, &testKeyspace{
+		KeyspaceName: "target",
+		ShardNames:   []string{"0"},
+	})
+	defer env.close()
+
+	testCases := []struct {
+		dependsOn string
+		wantErr   string
+	}{
+		{
+			dependsOn: "wf2",
+			wantErr:   `invalid workflow dependency "wf2", expected keyspace.workflow`,
+		},
+		{
+			dependsOn: "target.wf1",
+			wantErr:   "workflow target.wf1 cannot depend on itself",
+		},
+		{
+			dependsOn: "target.wf2",
+			wantErr:   "workflow dependency target.wf2 does not exist",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.dependsOn, func(t *testing.T) {
+			env.tmc.AddVReplicationWorkflowsResponse("target/0", &tabletmanagerdatapb.ReadVReplicationWorkflowsResponse{})
+			err := env.ws.validateWorkflowSchedule(ctx, "target", "wf1", &vtctldatapb.WorkflowSchedule{
+				DependsOn: []string{tc.dependsOn},
+			})
+			require.EqualError(t, err, tc.wantErr)
+		})
+	}
+
+	err := env.ws.validateWorkflowSchedule(ctx, "target", "wf1", &vtctldatapb.WorkflowSchedule{
+		MaxConcurrentWorkflows: ptr.Of(int32(-1)),
+	})
+	require.EqualError(t, err, "invalid maximum number of concurrent workflows -1 for workflow target.wf1, it cannot be negative")
+}
@@ -138,6 +138,13 @@ type Server struct {
 	sem     *semaphore.Weighted
 	env     *vtenv.Environment
 	options serverOptions
+
+	cutoverMu sync.Mutex
+	// The query counts of the target primary tablets of the workflows whose
+	// write traffic was switched by their cutover plan, when they were first
+	// sampled within the rollback window, by keyspace.workflow and then by
+	// tablet alias.
+	cutoverQueryCounts map[string]map[string]*queryCounts
 }
 
 // NewServer returns a new server instance with the given topo.Server and
@@ -795,7 +802,7 @@ func (s *Server) Materialize(ctx context.Context, ms *vtctldatapb.MaterializeSet
 		cells[i] = strings.TrimSpace(cells[i])
 	}
 
-	if err := validateMaterializeSettings(ms); err != nil {
+	if err := validateMaterializeSettings(ms, s.env.Parser()); err != nil {
 		return err
 	}
 
@@ -1013,26 +1020,60 @@ func (s *Server) validateAndGetStreamsAndSourceKeyspace(ctx context.Context, tar
 	return streamsByTargetShard, sourceKeyspace, workflowType, err
 }
 
-func validateMaterializeSettings(ms *vtctldatapb.MaterializeSettings) error {
+func validateMaterializeSettings(ms *vtctldatapb.MaterializeSettings, parser *sqlparser.Parser) error {
 	switch {
 	case len(ms.ReferenceTables) == 0 && len(ms.TableSettings) == 0:
 		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "either --table-settings or --reference-tables must be specified")
 	case len(ms.ReferenceTables) > 0 && len(ms.TableSettings) > 0:
 		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot specify both --table-settings and --reference-tables")
 	}
+	// Reject the aggregation queries the workflow streams would fail to plan.
+	// Other queries fail when the schema is deployed or when the streams
+	// start, as they always have.
+	for _, ts := range ms.TableSettings {
+		if !isAggregationQuery(ts.SourceExpression, parser) {
+			continue
+		}
+		if _, err := vreplication.AnalyzeMaterializeQuery(ts.SourceExpression, parser); err != nil {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid source expression for table %s: %v", ts.TargetTable, err)
+		}
+	}
 
 	return nil
 }
 
+// isAggregationQuery returns true if the given Materialize source expression
+// is a SELECT statement with a GROUP BY clause or an aggregate expression.
+func isAggregationQuery(query string, parser *sqlparser.Parser) bool {
+	if query == "" {
+		return false
+	}
+	stmt, err := parser.Parse(query)
+	if err != nil {
+		return false
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok {
+		return false
+	}
+	if sel.GroupBy != nil && len(sel.GroupBy.Exprs) > 0 {
+		return true
+	}
+	return sqlparser.ContainsAggregation(sel.SelectExprs)
+}
+
 // MoveTablesCreate is part of the vtctlservicepb.VtctldServer interface.
 // It passes the embedded TabletRequest object to the given keyspace's
 // target primary tablets that will be executing the workflow.
 func (s *Server) MoveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTablesCreateRequest) (res *vtctldatapb.WorkflowStatusResponse, err error) {
-	return s.moveTablesCreate(ctx, req, binlogdatapb.VReplicationWorkflowType_MoveTables)
+	return s.moveTablesCreate(ctx, req, binlogdatapb.VReplicationWorkflowType_MoveTables, nil)
 }
 
+// moveTablesCreate creates a MoveTables or Migrate workflow. The tables are
+// copied as they are unless a source expression is given for them in
+// sourceExpressions, which is keyed by table name.
 func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTablesCreateRequest,
-	workflowType binlogdatapb.VReplicationWorkflowType,
+	workflowType binlogdatapb.VReplicationWorkflowType, sourceExpressions map[string]string,
 ) (res *vtctldatapb.WorkflowStatusResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "workflow.Server.moveTablesCreate")
 	defer span.Finish()
@@ -1053,6 +1094,13 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 		sourceTopo   = s.ts
 	)
 
+	if err := s.validateWorkflowSchedule(ctx, targetKeyspace, req.Workflow, req.GetWorkflowOptions().GetSchedule()); err != nil {
+		return nil, err
+	}
+	if err := validateCutoverPlan(req.GetWorkflowOptions().GetCutoverPlan()); err != nil {
+		return nil, err
+	}
+
 	if req.GetWorkflowOptions() != nil && req.WorkflowOptions.GlobalKeyspace != "" {
 		// Confirm that the keyspace exists and it is unsharded.
 		gvs, err := s.ts.GetVSchema(ctx, req.WorkflowOptions.GlobalKeyspace)
@@ -1174,11 +1222,15 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 	}
 
 	for _, table := range tables {
-		buf := sqlparser.NewTrackedBuffer(nil)
-		buf.Myprintf("select * from %v", sqlparser.NewIdentifierCS(table))
+		sourceExpression, ok := sourceExpressions[table]
+		if !ok {
+			buf := sqlparser.NewTrackedBuffer(nil)
+			buf.Myprintf("select * from %v", sqlparser.NewIdentifierCS(table))
+			sourceExpression = buf.String()
+		}
 		ms.TableSettings = append(ms.TableSettings, &vtctldatapb.TableMaterializeSettings{
 			TargetTable:      table,
-			SourceExpression: buf.String(),
+			SourceExpression: sourceExpression,
 			CreateDdl:        createDDLMode,
 		})
 	}
@@ -1298,7 +1350,12 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 		}
 	}
 
-	if req.AutoStart {
+	switch {
+	case req.GetWorkflowOptions().GetSchedule() != nil:
+		if err := mz.queueStreams(ctx); err != nil {
+			return nil, err
+		}
+	case req.AutoStart:
 		if err := mz.startStreams(ctx); err != nil {
 			return nil, err
 		}
@@ -1495,6 +1552,12 @@ func (s *Server) ReshardCreate(ctx context.Context, req *vtctldatapb.ReshardCrea
 	cells := req.Cells
 	// TODO: validate workflow does not exist.
 
+	if err := s.validateWorkflowSchedule(ctx, keyspace, req.Workflow, req.GetWorkflowOptions().GetSchedule()); err != nil {
+		return nil, err
+	}
+	if err := validateCutoverPlan(req.GetWorkflowOptions().GetCutoverPlan()); err != nil {
+		return nil, err
+	}
 	if err := s.ts.ValidateSrvKeyspace(ctx, keyspace, strings.Join(cells, ",")); err != nil {
 		err2 := vterrors.Wrapf(err, "SrvKeyspace for keyspace %s is corrupt for cell(s) %s", keyspace, cells)
 		s.Logger().Errorf("%v", err2)
@@ -1516,11 +1579,16 @@ func (s *Server) ReshardCreate(ctx context.Context, req *vtctldatapb.ReshardCrea
 		return nil, vterrors.Wrap(err, "createStreams")
 	}
 
-	if req.AutoStart {
+	switch {
+	case req.GetWorkflowOptions().GetSchedule() != nil:
+		if err := rs.queueStreams(ctx); err != nil {
+			return nil, vterrors.Wrap(err, "queueStreams")
+		}
+	case req.AutoStart:
 		if err := rs.startStreams(ctx); err != nil {
 			return nil, vterrors.Wrap(err, "startStreams")
 		}
-	} else {
+	default:
 		s.Logger().Warningf("Streams will not be started since --auto-start is set to false")
 	}
 	return s.WorkflowStatus(ctx, &vtctldatapb.WorkflowStatusRequest{
@@ -1530,6 +1598,83 @@ func (s *Server) ReshardCreate(ctx context.Context, req *vtctldatapb.ReshardCrea
 	})
 }
 
+// SplitTableCreate is part of the vtctlservicepb.VtctldServer interface.
+// It creates a MoveTables workflow which moves a single table into the target
+// keyspace, defining the table's primary vindex there when the keyspace is
+// sharded and renaming its columns as requested. The workflow's traffic is then
+// switched and reversed, and the workflow completed, like for any other
+// MoveTables workflow.
+func (s *Server) SplitTableCreate(ctx context.Context, req *vtctldatapb.SplitTableCreateRequest) (res *vtctldatapb.WorkflowStatusResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.SplitTableCreate")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("table", req.Table)
+	span.Annotate("vindex", req.Vindex)
+
+	if req.Table == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no table specified")
+	}
+	sourceShards, err := s.ts.GetServingShards(ctx, req.SourceKeyspace)
+	if err != nil {
+		return nil, err
+	}
+	sourceDDLs, err := getSourceTableDDLs(ctx, s.ts, s.tmc, sourceShards)
+	if err != nil {
+		return nil, err
+	}
+	sourceDDL, ok := sourceDDLs[req.Table]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found in the %s keyspace", req.Table, req.SourceKeyspace)
+	}
+	sourceExpression, targetColumns, err := buildSplitTableSourceExpression(req.Table, sourceDDL, req.ColumnMapping, s.env.Parser())
+	if err != nil {
+		return nil, err
+	}
+
+	vschema, err := s.ts.GetVSchema(ctx, req.TargetKeyspace)
+	if err != nil {
+		return nil, err
+	}
+	origVSchema := &topo.KeyspaceVSchemaInfo{ // If we need to rollback a failed create
+		Name:     req.TargetKeyspace,
+		Keyspace: vschema.Keyspace.CloneVT(),
+	}
+	updated, err := addSplitTableToVSchema(vschema.Keyspace, req, targetColumns)
+	if err != nil {
+		return nil, err
+	}
+	if updated {
+		if err := s.ts.SaveVSchema(ctx, vschema); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				if cerr := s.ts.SaveVSchema(ctx, origVSchema); cerr != nil {
+					err = vterrors.Wrapf(err, "failed to restore original target vschema: %v", cerr)
+				}
+			}
+		}()
+	}
+
+	return s.moveTablesCreate(ctx, &vtctldatapb.MoveTablesCreateRequest{
+		Workflow:                  req.Workflow,
+		SourceKeyspace:            req.SourceKeyspace,
+		TargetKeyspace:            req.TargetKeyspace,
+		Cells:                     req.Cells,
+		TabletTypes:               req.TabletTypes,
+		TabletSelectionPreference: req.TabletSelectionPreference,
+		IncludeTables:             []string{req.Table},
+		OnDdl:                     req.OnDdl,
+		StopAfterCopy:             req.StopAfterCopy,
+		DeferSecondaryKeys:        req.DeferSecondaryKeys,
+		AutoStart:                 req.AutoStart,
+		NoRoutingRules:            req.NoRoutingRules,
+		WorkflowOptions:           req.WorkflowOptions,
+	}, binlogdatapb.VReplicationWorkflowType_MoveTables, map[string]string{req.Table: sourceExpression})
+}
+
 // WorkflowDelete is part of the vtctlservicepb.VtctldServer interface.
 // It passes on the request to the target primary tablets that are
 // participating in the given workflow.
@@ -3501,7 +3646,7 @@ func (s *Server) MigrateCreate(ctx context.Context, req *vtctldatapb.MigrateCrea
 		AutoStart:                 req.AutoStart,
 		NoRoutingRules:            req.NoRoutingRules,
 	}
-	return s.moveTablesCreate(ctx, moveTablesCreateRequest, binlogdatapb.VReplicationWorkflowType_Migrate)
+	return s.moveTablesCreate(ctx, moveTablesCreateRequest, binlogdatapb.VReplicationWorkflowType_Migrate, nil)
 }
 
 // getWorkflowStatus gets the overall status of the workflow by checking the status of all the streams. If all streams are not
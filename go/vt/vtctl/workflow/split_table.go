@@ -0,0 +1,152 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// buildSplitTableSourceExpression returns the source expression of a
+// SplitTable workflow, which selects all of the columns of the table renaming
+// those in the column mapping, along with the names of the columns in the
+// target table. Generated columns are not selected as they are generated on
+// the target as well. The column mapping only renames columns: its values must
+// be column names, as expressions could not be reversed when traffic is
+// switched.
+func buildSplitTableSourceExpression(table, ddl string, columnMapping map[string]string, parser *sqlparser.Parser) (string, []sqlparser.IdentifierCI, error) {
+	stmt, err := parser.ParseStrictDDL(ddl)
+	if err != nil {
+		return "", nil, err
+	}
+	createTable, ok := stmt.(*sqlparser.CreateTable)
+	if !ok {
+		return "", nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected definition for table %s: %s", table, ddl)
+	}
+	renames := make(map[string]sqlparser.IdentifierCI, len(columnMapping))
+	for from, to := range columnMapping {
+		if to == "" {
+			return "", nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no target name specified for column %s", from)
+		}
+		expr, err := parser.ParseExpr(to)
+		colName, ok := expr.(*sqlparser.ColName)
+		if err != nil || !ok || !colName.Qualifier.IsEmpty() {
+			return "", nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid target name %s for column %s: the column mapping only renames columns and does not support expressions", to, from)
+		}
+		renames[strings.ToLower(from)] = colName.Name
+	}
+
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.WriteString("select ")
+	selected := 0
+	targetColumns := make([]sqlparser.IdentifierCI, 0, len(createTable.TableSpec.Columns))
+	for _, col := range createTable.TableSpec.Columns {
+		targetColumn := col.Name
+		if newName, ok := renames[col.Name.Lowered()]; ok {
+			targetColumn = newName
+			delete(renames, col.Name.Lowered())
+		}
+		if slices.ContainsFunc(targetColumns, targetColumn.Equal) {
+			return "", nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "duplicate column %s in the target table %s", targetColumn.String(), table)
+		}
+		targetColumns = append(targetColumns, targetColumn)
+		if col.Type.Options != nil && col.Type.Options.As != nil {
+			continue
+		}
+		if selected > 0 {
+			buf.WriteString(", ")
+		}
+		selected++
+		if targetColumn.Equal(col.Name) {
+			buf.Myprintf("%v", col.Name)
+		} else {
+			buf.Myprintf("%v as %v", col.Name, targetColumn)
+		}
+	}
+	if len(renames) > 0 {
+		return "", nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "column %s not found in table %s", slices.Sorted(maps.Keys(renames))[0], table)
+	}
+	buf.Myprintf(" from %v", sqlparser.NewIdentifierCS(table))
+	return buf.String(), targetColumns, nil
+}
+
+// addSplitTableToVSchema adds the table of a SplitTable workflow to the given
+// target keyspace vschema when the keyspace is sharded, along with its primary
+// vindex if the vschema does not have it yet. It returns true if the vschema
+// was changed.
+func addSplitTableToVSchema(ks *vschemapb.Keyspace, req *vtctldatapb.SplitTableCreateRequest, targetColumns []sqlparser.IdentifierCI) (bool, error) {
+	if !ks.Sharded {
+		if req.Vindex != "" || req.VindexColumn != "" {
+			return false, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cannot use a vindex for table %s in the unsharded %s keyspace",
+				req.Table, req.TargetKeyspace)
+		}
+		return false, nil
+	}
+	if _, ok := ks.Tables[req.Table]; ok {
+		if req.Vindex != "" || req.VindexColumn != "" {
+			return false, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s already exists in the vschema of the %s keyspace",
+				req.Table, req.TargetKeyspace)
+		}
+		return false, nil
+	}
+	if req.Vindex == "" || req.VindexColumn == "" {
+		return false, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a vindex and vindex column are required for table %s in the sharded %s keyspace",
+			req.Table, req.TargetKeyspace)
+	}
+	if !slices.ContainsFunc(targetColumns, func(col sqlparser.IdentifierCI) bool { return col.EqualString(req.VindexColumn) }) {
+		return false, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "vindex column %s not found in the target table %s", req.VindexColumn, req.Table)
+	}
+
+	// A vindex which is not in the vschema yet is created using its name as
+	// its type, as is customary for functional vindexes such as xxhash.
+	vindex, ok := ks.Vindexes[req.Vindex]
+	if !ok {
+		vindex = &vschemapb.Vindex{Type: req.Vindex}
+	}
+	v, err := vindexes.CreateVindex(vindex.Type, req.Vindex, vindex.Params)
+	if err != nil {
+		return false, vterrors.Wrapf(err, "invalid vindex %s", req.Vindex)
+	}
+	if !v.IsUnique() {
+		return false, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "vindex %s is not unique and cannot be the primary vindex of table %s", req.Vindex, req.Table)
+	}
+	if !ok {
+		if ks.Vindexes == nil {
+			ks.Vindexes = make(map[string]*vschemapb.Vindex)
+		}
+		ks.Vindexes[req.Vindex] = vindex
+	}
+	if ks.Tables == nil {
+		ks.Tables = make(map[string]*vschemapb.Table)
+	}
+	ks.Tables[req.Table] = &vschemapb.Table{
+		ColumnVindexes: []*vschemapb.ColumnVindex{{
+			Name:   req.Vindex,
+			Column: req.VindexColumn,
+		}},
+	}
+	return true, nil
+}
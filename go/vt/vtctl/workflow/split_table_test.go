@@ -0,0 +1,286 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/topo"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestSplitTableCreate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	const (
+		workflow = "wf"
+		sourceKs = "sourceks"
+		targetKs = "targetks"
+		table    = "customer"
+	)
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:       workflow,
+		SourceKeyspace: sourceKs,
+		TargetKeyspace: targetKs,
+		TableSettings: []*vtctldatapb.TableMaterializeSettings{{
+			TargetTable:      table,
+			CreateDdl:        "create table customer (id bigint not null auto_increment, email varchar(128), primary key (id), key email_idx (email))",
+			SourceExpression: "select * from customer",
+		}},
+	}
+	lookupVindex := &vschemapb.Vindex{
+		Type: "lookup",
+		Params: map[string]string{
+			"table": "lookupks.customer_email",
+			"from":  "email",
+			"to":    "keyspace_id",
+		},
+	}
+	wantVSchema := &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"xxhash": {
+				Type: "xxhash",
+			},
+		},
+		Tables: map[string]*vschemapb.Table{
+			table: {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Name:   "xxhash",
+					Column: "customer_id",
+				}},
+			},
+		},
+	}
+	wantReq := func(keyRange string) *tabletmanagerdatapb.CreateVReplicationWorkflowRequest {
+		return &tabletmanagerdatapb.CreateVReplicationWorkflowRequest{
+			Workflow:     workflow,
+			WorkflowType: binlogdatapb.VReplicationWorkflowType_MoveTables,
+			BinlogSource: []*binlogdatapb.BinlogSource{{
+				Keyspace: sourceKs,
+				Shard:    "0",
+				Filter: &binlogdatapb.Filter{
+					Rules: []*binlogdatapb.Rule{{
+						Match:  table,
+						Filter: fmt.Sprintf("select id as customer_id, email as contact_email from customer where in_keyrange(id, '%s.xxhash', '%s')", targetKs, keyRange),
+					}},
+				},
+			}},
+			Options: `{"sharded_auto_increment_handling":1}`,
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		req           *vtctldatapb.SplitTableCreateRequest
+		targetVSchema *vschemapb.Keyspace
+		wantReqs      map[uint32]*tabletmanagerdatapb.CreateVReplicationWorkflowRequest
+		wantVSchema   *vschemapb.Keyspace
+		wantErr       string
+	}{
+		{
+			name: "new vindex and renamed columns",
+			req: &vtctldatapb.SplitTableCreateRequest{
+				Vindex:        "xxhash",
+				VindexColumn:  "customer_id",
+				ColumnMapping: map[string]string{"id": "customer_id", "email": "contact_email"},
+			},
+			wantReqs: map[uint32]*tabletmanagerdatapb.CreateVReplicationWorkflowRequest{
+				200: wantReq("-80"),
+				210: wantReq("80-"),
+			},
+			wantVSchema: wantVSchema,
+		},
+		{
+			name: "table already in the vschema",
+			req: &vtctldatapb.SplitTableCreateRequest{
+				Vindex:        "xxhash",
+				VindexColumn:  "customer_id",
+				ColumnMapping: map[string]string{"id": "customer_id"},
+			},
+			targetVSchema: wantVSchema,
+			wantErr:       "table customer already exists in the vschema of the targetks keyspace",
+		},
+		{
+			name: "no vindex",
+			req: &vtctldatapb.SplitTableCreateRequest{
+				ColumnMapping: map[string]string{"id": "customer_id"},
+			},
+			wantErr: "a vindex and vindex column are required for table customer in the sharded targetks keyspace",
+		},
+		{
+			name: "unknown vindex column",
+			req: &vtctldatapb.SplitTableCreateRequest{
+				Vindex:        "xxhash",
+				VindexColumn:  "id",
+				ColumnMapping: map[string]string{"id": "customer_id"},
+			},
+			wantErr: "vindex column id not found in the target table customer",
+		},
+		{
+			name: "non-unique vindex",
+			req: &vtctldatapb.SplitTableCreateRequest{
+				Vindex:       "email_lookup",
+				VindexColumn: "email",
+			},
+			targetVSchema: &vschemapb.Keyspace{
+				Sharded: true,
+				Vindexes: map[string]*vschemapb.Vindex{
+					"email_lookup": lookupVindex,
+				},
+			},
+			wantErr: "vindex email_lookup is not unique and cannot be the primary vindex of table customer",
+		},
+		{
+			name: "unknown mapped column",
+			req: &vtctldatapb.SplitTableCreateRequest{
+				Vindex:        "xxhash",
+				VindexColumn:  "id",
+				ColumnMapping: map[string]string{"name": "full_name"},
+			},
+			wantErr: "column name not found in table customer",
+		},
+		{
+			name: "expression in the column mapping",
+			req: &vtctldatapb.SplitTableCreateRequest{
+				Vindex:        "xxhash",
+				VindexColumn:  "id",
+				ColumnMapping: map[string]string{"email": "lower(email)"},
+			},
+			wantErr: "invalid target name lower(email) for column email: the column mapping only renames columns and does not support expressions",
+		},
+		{
+			name: "duplicate target column",
+			req: &vtctldatapb.SplitTableCreateRequest{
+				Vindex:        "xxhash",
+				VindexColumn:  "id",
+				ColumnMapping: map[string]string{"email": "id"},
+			},
+			wantErr: "duplicate column id in the target table customer",
+		},
+		{
+			name: "vschema restored on failure",
+			req: &vtctldatapb.SplitTableCreateRequest{
+				Vindex:        "xxhash",
+				VindexColumn:  "customer_id",
+				ColumnMapping: map[string]string{"id": "customer_id", "email": "contact_email"},
+			},
+			wantReqs: map[uint32]*tabletmanagerdatapb.CreateVReplicationWorkflowRequest{
+				200: {},
+				210: {},
+			},
+			wantErr: "unexpected CreateVReplicationWorkflow request",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newTestMaterializerEnv(t, ctx, ms, []string{"0"}, []string{"-80", "80-"})
+			defer env.close()
+
+			targetVSchema := tc.targetVSchema
+			if targetVSchema == nil {
+				targetVSchema = &vschemapb.Keyspace{Sharded: true}
+			}
+			err := env.ws.ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{
+				Name:     targetKs,
+				Keyspace: targetVSchema,
+			})
+			require.NoError(t, err)
+
+			env.tmc.expectVRQuery(100, mzCheckJournal, &sqltypes.Result{})
+			for uid, req := range tc.wantReqs {
+				env.tmc.expectVRQuery(int(uid), "create table customer (\n\tcustomer_id bigint not null,\n\tcontact_email varchar(128),\n\tprimary key (customer_id),\n\tkey email_idx (contact_email)\n)", &sqltypes.Result{})
+				env.tmc.expectFetchAsAllPrivsQuery(int(uid), "select 1 from `customer` limit 1", &sqltypes.Result{})
+				env.tmc.expectVRQuery(int(uid), mzGetCopyState, &sqltypes.Result{})
+				env.tmc.expectVRQuery(int(uid), mzGetLatestCopyState, &sqltypes.Result{})
+				env.tmc.SetGetSchemaResponse(int(uid), &tabletmanagerdatapb.SchemaDefinition{}) // So that the schema is copied from the source
+				env.tmc.expectCreateVReplicationWorkflowRequest(uid, &createVReplicationWorkflowRequestResponse{req: req})
+			}
+
+			req := tc.req.CloneVT()
+			req.Workflow = workflow
+			req.SourceKeyspace = sourceKs
+			req.TargetKeyspace = targetKs
+			req.Table = table
+			req.WorkflowOptions = &vtctldatapb.WorkflowOptions{
+				ShardedAutoIncrementHandling: vtctldatapb.ShardedAutoIncrementHandling_REMOVE,
+			}
+			_, err = env.ws.SplitTableCreate(ctx, req)
+			gotVSchema, verr := env.ws.ts.GetVSchema(ctx, targetKs)
+			require.NoError(t, verr)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				require.True(t, proto.Equal(targetVSchema, gotVSchema.Keyspace), "got: %v, want: %v", gotVSchema.Keyspace, targetVSchema)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, proto.Equal(tc.wantVSchema, gotVSchema.Keyspace), "got: %v, want: %v", gotVSchema.Keyspace, tc.wantVSchema)
+			env.tmc.verifyQueries(t)
+		})
+	}
+}
+
+// TestMaterializeCopySchemaKeepsColumnNames tests that the schema copied for a
+// Materialize workflow keeps the column names of the source table, as the
+// columns are only renamed for SplitTable workflows.
+func TestMaterializeCopySchemaKeepsColumnNames(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	const sourceDDL = "create table customer (id bigint not null, email varchar(128), primary key (id))"
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:              "wf",
+		SourceKeyspace:        "sourceks",
+		TargetKeyspace:        "targetks",
+		MaterializationIntent: vtctldatapb.MaterializationIntent_CUSTOM,
+		TableSettings: []*vtctldatapb.TableMaterializeSettings{{
+			TargetTable:      "customer",
+			CreateDdl:        sourceDDL,
+			SourceExpression: "select id, email as contact_email from customer",
+		}},
+	}
+	env := newTestMaterializerEnv(t, ctx, ms, []string{"0"}, []string{"0"})
+	defer env.close()
+
+	ms.TableSettings[0].CreateDdl = createDDLAsCopy
+	env.tmc.SetGetSchemaResponse(200, &tabletmanagerdatapb.SchemaDefinition{}) // So that the schema is copied from the source
+	env.tmc.expectFetchAsAllPrivsQuery(200, "select 1 from `customer` limit 1", &sqltypes.Result{})
+	env.tmc.expectVRQuery(200, sourceDDL, &sqltypes.Result{})
+
+	mz := &materializer{
+		ctx:          ctx,
+		ts:           env.topoServ,
+		sourceTs:     env.topoServ,
+		tmc:          env.tmc,
+		ms:           ms,
+		workflowType: binlogdatapb.VReplicationWorkflowType_Materialize,
+		env:          env.venv,
+	}
+	require.NoError(t, mz.buildMaterializer())
+	require.NoError(t, mz.deploySchema())
+	env.tmc.verifyQueries(t)
+}
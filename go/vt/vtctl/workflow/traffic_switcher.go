@@ -60,6 +60,12 @@ import (
 const (
 	// Frozen is the message value of frozen vreplication streams.
 	Frozen = "FROZEN"
+	// Queued is the message value of stopped vreplication streams which
+	// are waiting to be started by the workflow scheduler.
+	Queued = "QUEUED"
+	// CutoverRolledBack is the message value of stopped vreplication streams
+	// whose traffic was switched back by the cutover plan of the workflow.
+	CutoverRolledBack = "CUTOVER ROLLED BACK"
 	// Running is the state value of a vreplication stream in the
 	// replicating state.
 	Running = "RUNNING"
@@ -921,6 +927,11 @@ func (ts *trafficSwitcher) createReverseVReplication(ctx context.Context) error
 					filter = key.KeyRangeString(source.GetShard().KeyRange)
 				}
 			} else {
+				// The reverse workflow renames back any columns the forward one renames.
+				columns, targetColumns, err := reverseSelectColumns(rule.Filter, ts.ws.env.Parser())
+				if err != nil {
+					return vterrors.Wrapf(err, "cannot build the reverse filter for the %s table", rule.Match)
+				}
 				var inKeyrange string
 				if ts.SourceKeyspaceSchema().Keyspace.Sharded {
 					vtable, ok := ts.SourceKeyspaceSchema().Tables[rule.Match]
@@ -937,7 +948,11 @@ func (ts *trafficSwitcher) createReverseVReplication(ctx context.Context) error
 						// For non-reference tables we return an error if there's no primary
 						// vindex as it's not clear what to do.
 						if len(vtable.ColumnVindexes) > 0 && len(vtable.ColumnVindexes[0].Columns) > 0 {
-							inKeyrange = fmt.Sprintf(" where in_keyrange(%s, '%s.%s', %s)", sqlparser.String(vtable.ColumnVindexes[0].Columns[0]),
+							column := vtable.ColumnVindexes[0].Columns[0]
+							if targetColumn, ok := targetColumns[column.Lowered()]; ok {
+								column = targetColumn
+							}
+							inKeyrange = fmt.Sprintf(" where in_keyrange(%s, '%s.%s', %s)", sqlparser.String(column),
 								ts.SourceKeyspaceName(), vtable.ColumnVindexes[0].Name, encodeString(key.KeyRangeString(source.GetShard().KeyRange)))
 						} else {
 							return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "no primary vindex found for the %s table in the %s keyspace",
@@ -945,7 +960,7 @@ func (ts *trafficSwitcher) createReverseVReplication(ctx context.Context) error
 						}
 					}
 				}
-				filter = fmt.Sprintf("select * from %s%s", sqlescape.EscapeID(rule.Match), inKeyrange)
+				filter = fmt.Sprintf("select %s from %s%s", columns, sqlescape.EscapeID(rule.Match), inKeyrange)
 				if ts.IsMultiTenantMigration() {
 					filter, err = ts.addTenantFilter(ctx, filter)
 					if err != nil {
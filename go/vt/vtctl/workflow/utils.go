@@ -217,6 +217,122 @@ func stripTableForeignKeys(ddl string, parser *sqlparser.Parser) (string, error)
 	return newDDL, nil
 }
 
+// renameSelectedColumns renames the columns in the given table definition that
+// the source expression of a workflow selects under a different name, so that a
+// table whose schema is copied from the source has the columns the workflow
+// writes to.
+func renameSelectedColumns(ddl, sourceExpression string, parser *sqlparser.Parser) (string, error) {
+	if sourceExpression == "" {
+		return ddl, nil
+	}
+	stmt, err := parser.Parse(sourceExpression)
+	if err != nil {
+		return "", err
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok {
+		return "", fmt.Errorf("unrecognized statement: %s", sourceExpression)
+	}
+	renames := make(map[string]sqlparser.IdentifierCI)
+	for _, selExpr := range sel.GetColumns() {
+		aliasedExpr, ok := selExpr.(*sqlparser.AliasedExpr)
+		if !ok || aliasedExpr.As.IsEmpty() {
+			continue
+		}
+		if colName, ok := aliasedExpr.Expr.(*sqlparser.ColName); ok && !colName.Name.Equal(aliasedExpr.As) {
+			renames[colName.Name.Lowered()] = aliasedExpr.As
+		}
+	}
+	if len(renames) == 0 {
+		return ddl, nil
+	}
+
+	ast, err := parser.ParseStrictDDL(ddl)
+	if err != nil {
+		return "", err
+	}
+	rename := func(col *sqlparser.IdentifierCI) {
+		if newName, ok := renames[col.Lowered()]; ok {
+			*col = newName
+		}
+	}
+	var renameColumns func(cursor *sqlparser.Cursor) bool
+	renameColumns = func(cursor *sqlparser.Cursor) bool {
+		switch node := cursor.Node().(type) {
+		case *sqlparser.ColumnDefinition:
+			rename(&node.Name)
+			// The expressions of generated columns are not walked as part of the
+			// column definition, so we walk them here.
+			if node.Type.Options != nil && node.Type.Options.As != nil {
+				sqlparser.Rewrite(node.Type.Options.As, renameColumns, nil)
+			}
+		case *sqlparser.IndexDefinition:
+			for _, col := range node.Columns {
+				rename(&col.Column)
+			}
+		case *sqlparser.ForeignKeyDefinition:
+			for i := range node.Source {
+				rename(&node.Source[i])
+			}
+		case *sqlparser.ColName:
+			if node.Qualifier.IsEmpty() {
+				rename(&node.Name)
+			}
+		}
+		return true
+	}
+	return sqlparser.String(sqlparser.Rewrite(ast, renameColumns, nil)), nil
+}
+
+// reverseSelectColumns returns the select expressions to use in the filter of
+// a reverse workflow, given the filter of the forward one, along with the names
+// the source columns have on the target when the forward filter renames any.
+// Filters which select all of the columns are reversed as they are, while
+// filters which rename columns are reversed by renaming them back. Any other
+// expression cannot be reversed.
+func reverseSelectColumns(filter string, parser *sqlparser.Parser) (string, map[string]sqlparser.IdentifierCI, error) {
+	if filter == "" || key.IsValidKeyRange(filter) {
+		return "*", nil, nil
+	}
+	stmt, err := parser.Parse(filter)
+	if err != nil {
+		return "", nil, err
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok {
+		return "", nil, fmt.Errorf("unrecognized statement: %s", filter)
+	}
+	selExprs := sel.GetColumns()
+	if len(selExprs) == 1 {
+		if _, ok := selExprs[0].(*sqlparser.StarExpr); ok {
+			return "*", nil, nil
+		}
+	}
+	buf := sqlparser.NewTrackedBuffer(nil)
+	targetColumns := make(map[string]sqlparser.IdentifierCI, len(selExprs))
+	for i, selExpr := range selExprs {
+		var colName *sqlparser.ColName
+		aliasedExpr, ok := selExpr.(*sqlparser.AliasedExpr)
+		if ok {
+			colName, ok = aliasedExpr.Expr.(*sqlparser.ColName)
+		}
+		if !ok || !colName.Qualifier.IsEmpty() {
+			return "", nil, fmt.Errorf("unsupported select expression in a reversible filter: %s", sqlparser.String(selExpr))
+		}
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		if aliasedExpr.As.IsEmpty() || aliasedExpr.As.Equal(colName.Name) {
+			targetColumns[colName.Name.Lowered()] = colName.Name
+			buf.Myprintf("%v", colName.Name)
+			continue
+		}
+		targetColumns[colName.Name.Lowered()] = aliasedExpr.As
+		buf.Myprintf("%v as %v", aliasedExpr.As, colName.Name)
+	}
+	return buf.String(), targetColumns, nil
+}
+
 // stripAutoIncrement will strip any MySQL auto_increment clause in the given
 // table definition. If an optional replace function is specified then that
 // callback will be used to e.g. replace the MySQL clause with a Vitess
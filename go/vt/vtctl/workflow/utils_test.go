@@ -18,6 +18,7 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/testfiles"
 	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/etcd2topo"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
@@ -283,6 +284,100 @@ func TestValidateSourceTablesExist(t *testing.T) {
 	}
 }
 
+func TestRenameSelectedColumns(t *testing.T) {
+	const ddl = "create table t1 (id int not null, c1 varchar(10), c2 int as (c1 + 1), primary key (id), key c1_idx (c1), constraint c1_fk foreign key (c1) references t2 (c1))"
+	testCases := []struct {
+		name             string
+		sourceExpression string
+		want             string
+	}{
+		{
+			name:             "all columns",
+			sourceExpression: "select * from t1",
+			want:             ddl,
+		},
+		{
+			name:             "no renames",
+			sourceExpression: "select id, c1 as c1 from t1",
+			want:             ddl,
+		},
+		{
+			name:             "renames",
+			sourceExpression: "select id as t1_id, c1 as label, c1 + 1 as c3 from t1",
+			want: `create table t1 (
+	t1_id int not null,
+	label varchar(10),
+	c2 int as (label + 1) virtual,
+	primary key (t1_id),
+	key c1_idx (label),
+	constraint c1_fk foreign key (label) references t2 (c1)
+)`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := renameSelectedColumns(ddl, tc.sourceExpression, sqlparser.NewTestParser())
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestReverseSelectColumns(t *testing.T) {
+	testCases := []struct {
+		name              string
+		filter            string
+		wantColumns       string
+		wantTargetColumns map[string]string
+		wantErr           string
+	}{
+		{
+			name:        "no filter",
+			wantColumns: "*",
+		},
+		{
+			name:        "key range",
+			filter:      "-80",
+			wantColumns: "*",
+		},
+		{
+			name:        "all columns",
+			filter:      "select * from t1 where in_keyrange(id, 'ks.xxhash', '-80')",
+			wantColumns: "*",
+		},
+		{
+			name:              "renamed columns",
+			filter:            "select id as customer_id, name, email as email from t1 where in_keyrange(id, 'ks.xxhash', '-80')",
+			wantColumns:       "customer_id as id, `name`, email",
+			wantTargetColumns: map[string]string{"id": "customer_id", "name": "name", "email": "email"},
+		},
+		{
+			name:    "expression",
+			filter:  "select id, concat(first, last) as name from t1",
+			wantErr: "unsupported select expression in a reversible filter: concat(`first`, `last`) as `name`",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			columns, targetColumns, err := reverseSelectColumns(tc.filter, sqlparser.NewTestParser())
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantColumns, columns)
+			var gotTargetColumns map[string]string
+			if targetColumns != nil {
+				gotTargetColumns = make(map[string]string, len(targetColumns))
+				for col, targetCol := range targetColumns {
+					gotTargetColumns[col] = targetCol.String()
+				}
+			}
+			assert.Equal(t, tc.wantTargetColumns, gotTargetColumns)
+		})
+	}
+}
+
 func TestLegacyBuildTargets(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
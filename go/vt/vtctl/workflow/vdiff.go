@@ -354,14 +354,17 @@ func (s *Server) VDiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRe
 			TargetCell:  strings.Join(req.TargetCells, ","),
 		},
 		CoreOptions: &tabletmanagerdatapb.VDiffCoreOptions{
-			Tables:                strings.Join(req.Tables, ","),
-			AutoRetry:             req.AutoRetry,
-			MaxRows:               req.Limit,
-			TimeoutSeconds:        req.FilteredReplicationWaitTime.Seconds,
-			MaxExtraRowsToCompare: req.MaxExtraRowsToCompare,
-			UpdateTableStats:      req.UpdateTableStats,
-			MaxDiffSeconds:        req.MaxDiffDuration.Seconds,
-			AutoStart:             &autoStart,
+			Tables:                   strings.Join(req.Tables, ","),
+			AutoRetry:                req.AutoRetry,
+			MaxRows:                  req.Limit,
+			TimeoutSeconds:           req.FilteredReplicationWaitTime.Seconds,
+			MaxExtraRowsToCompare:    req.MaxExtraRowsToCompare,
+			UpdateTableStats:         req.UpdateTableStats,
+			MaxDiffSeconds:           req.MaxDiffDuration.Seconds,
+			MaxConcurrentTables:      req.MaxConcurrentTables,
+			MaxTableRanges:           req.MaxTableRanges,
+			MaxConcurrentTableRanges: req.MaxConcurrentTableRanges,
+			AutoStart:                &autoStart,
 		},
 		ReportOptions: &tabletmanagerdatapb.VDiffReportOptions{
 			OnlyPks:                 req.OnlyPKs,
@@ -57,6 +57,11 @@ type (
 		RowsReturned uint64 // RowsReturned is the total number of rows returned to clients.
 		RowsAffected uint64 // RowsAffected is the total number of rows affected by DML operations.
 		Errors       uint64 // Errors is the total count of errors encountered during execution.
+
+		// ExecTimes counts the executions of this plan by execution time. Each
+		// count is for the bucket of ExecTimeBuckets with the same index, and
+		// the last one for the executions slower than all of them.
+		ExecTimes [len(ExecTimeBuckets) + 1]uint64
 	}
 
 	// PlanKey identifies a plan uniquely based on keyspace, destination, query,
@@ -71,6 +76,25 @@ type (
 	}
 )
 
+// ExecTimeBuckets are the upper bounds of the buckets of the execution time
+// histogram of plans.
+var ExecTimeBuckets = [...]time.Duration{
+	500 * time.Microsecond,
+	time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	20 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	200 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+}
+
 const (
 	PlanUnknown PlanType = iota
 	PlanLocal
@@ -336,6 +360,28 @@ func (p *Plan) AddStats(execCount uint64, execTime time.Duration, shardQueries,
 	atomic.AddUint64(&p.RowsAffected, rowsAffected)
 	atomic.AddUint64(&p.RowsReturned, rowsReturned)
 	atomic.AddUint64(&p.Errors, errors)
+
+	if execCount > 0 {
+		atomic.AddUint64(&p.ExecTimes[execTimeBucket(execTime/time.Duration(execCount))], execCount)
+	}
+}
+
+// ExecTimeCounts returns a copy of the execution time histogram of the plan.
+func (p *Plan) ExecTimeCounts() []uint64 {
+	counts := make([]uint64, len(p.ExecTimes))
+	for i := range p.ExecTimes {
+		counts[i] = atomic.LoadUint64(&p.ExecTimes[i])
+	}
+	return counts
+}
+
+func execTimeBucket(execTime time.Duration) int {
+	for i, bound := range ExecTimeBuckets {
+		if execTime <= bound {
+			return i
+		}
+	}
+	return len(ExecTimeBuckets)
 }
 
 // Stats returns a copy of the plan execution statistics
@@ -0,0 +1,52 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanExecTimes(t *testing.T) {
+	plan := &Plan{}
+
+	plan.AddStats(1, 100*time.Microsecond, 1, 0, 1, 0)
+	plan.AddStats(1, time.Millisecond, 1, 0, 1, 0)
+	plan.AddStats(1, 3*time.Millisecond, 1, 0, 1, 0)
+	plan.AddStats(1, time.Minute, 1, 0, 1, 1)
+	// Executions added together are counted in the bucket of their average
+	// time.
+	plan.AddStats(2, 6*time.Millisecond, 2, 0, 2, 0)
+
+	want := make([]uint64, len(ExecTimeBuckets)+1)
+	want[0] = 1                    // 100µs
+	want[1] = 1                    // 1ms
+	want[3] = 3                    // 3ms, 2x3ms
+	want[len(ExecTimeBuckets)] = 1 // 1m
+	assert.Equal(t, want, plan.ExecTimeCounts())
+
+	count, execTime, _, _, _, errors := plan.Stats()
+	assert.EqualValues(t, 6, count)
+	assert.Equal(t, time.Minute+10*time.Millisecond+100*time.Microsecond, execTime)
+	assert.EqualValues(t, 1, errors)
+
+	// Executions which were not counted do not change the histogram.
+	plan.AddStats(0, time.Second, 0, 0, 0, 0)
+	assert.Equal(t, want, plan.ExecTimeCounts())
+}
@@ -252,6 +252,7 @@ func (e *Executor) Execute(
 	defer span.Finish()
 
 	logStats := logstats.NewLogStats(ctx, method, sql, safeSession.GetSessionUUID(), bindVars, streamlog.GetQueryLogConfig())
+	logStats.QueryAttributes = queryAttributesFromContext(ctx)
 	stmtType, result, err := e.execute(ctx, mysqlCtx, safeSession, sql, bindVars, prepared, logStats)
 	logStats.Error = err
 	if result == nil {
@@ -315,6 +316,7 @@ func (e *Executor) StreamExecute(
 	defer span.Finish()
 
 	logStats := logstats.NewLogStats(ctx, method, sql, safeSession.GetSessionUUID(), bindVars, streamlog.GetQueryLogConfig())
+	logStats.QueryAttributes = queryAttributesFromContext(ctx)
 	srr := &streaminResultReceiver{callback: callback}
 	var err error
 
@@ -1116,6 +1118,9 @@ func (e *Executor) fetchOrCreatePlan(
 	}
 
 	query, comments := sqlparser.SplitMarginComments(queryString)
+	if mysqlQueryAttributesAsComment {
+		comments.Trailing += queryAttributesComment(queryAttributesFromContext(ctx))
+	}
 	vcursor, _ = e.newVCursor(safeSession, comments, logStats)
 
 	var setVarComment string
@@ -1154,7 +1159,9 @@ func (e *Executor) fetchOrCreatePlan(
 	}
 
 	// Apply query hints
-	e.applyQueryHints(vcursor, plan)
+	if err := e.applyQueryHints(ctx, vcursor, plan); err != nil {
+		return nil, nil, stmt, err
+	}
 
 	logStats.SQL = comments.Leading + plan.Original + comments.Trailing
 	logStats.BindVariables = sqltypes.CopyBindVariables(bindVars)
@@ -1203,14 +1210,23 @@ func shouldOptimizePlan(preparedPlan, isExecutePath bool, plan *engine.Plan) boo
 		(plan.Type == engine.PlanJoinOp || plan.Type == engine.PlanComplex)
 }
 
-// applyQueryHints applies query hints to the vcursor
-func (e *Executor) applyQueryHints(vcursor *econtext.VCursorImpl, plan *engine.Plan) {
+// applyQueryHints applies query hints to the vcursor, including the ones
+// set by query attributes if they are used as directives
+func (e *Executor) applyQueryHints(ctx context.Context, vcursor *econtext.VCursorImpl, plan *engine.Plan) error {
 	qh := plan.QueryHints
+	if mysqlQueryAttributesAsDirectives {
+		var err error
+		qh, err = mergeQueryAttributesHints(qh, queryAttributesFromContext(ctx))
+		if err != nil {
+			return err
+		}
+	}
 	vcursor.SetIgnoreMaxMemoryRows(qh.IgnoreMaxMemoryRows)
 	vcursor.SetConsolidator(qh.Consolidator)
 	vcursor.SetWorkloadName(qh.Workload)
 	vcursor.SetPriority(qh.Priority)
 	vcursor.SetExecQueryTimeout(qh.Timeout)
+	return nil
 }
 
 func (e *Executor) getCachedOrBuildPlan(
@@ -1686,7 +1702,7 @@ func (e *Executor) startVStream(ctx context.Context, rss []*srvtopo.ResolvedShar
 		return err
 	}
 
-	vsm := newVStreamManager(e.resolver.resolver, e.serv, e.cell)
+	vsm := newVStreamManager(e.env, e.resolver.resolver, e.serv, e.cell)
 	vs := &vstream{
 		vgtid:              vgtid,
 		tabletType:         topodatapb.TabletType_PRIMARY,
@@ -1700,6 +1716,7 @@ func (e *Executor) startVStream(ctx context.Context, rss []*srvtopo.ResolvedShar
 		eventCh:            make(chan []*binlogdatapb.VEvent),
 		ts:                 ts,
 		copyCompletedShard: make(map[string]struct{}),
+		projector:          &vstreamProjector{env: e.env},
 	}
 	_ = vs.stream(ctx)
 	return nil
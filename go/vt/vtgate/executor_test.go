@@ -1842,6 +1842,63 @@ func TestGetPlanPriority(t *testing.T) {
 
 }
 
+func TestGetPlanQueryAttributes(t *testing.T) {
+	defer func(old bool) {
+		mysqlQueryAttributesAsDirectives = old
+	}(mysqlQueryAttributesAsDirectives)
+
+	r, _, _, _, ctx := createExecutorEnvWithConfig(t, createExecutorConfigWithNormalizer())
+	ctx = newContextWithQueryAttributes(ctx, map[string]*querypb.BindVariable{
+		"vt_priority":      sqltypes.StringBindVariable("10"),
+		"vt_workload_name": sqltypes.StringBindVariable("app"),
+		"trace_id":         sqltypes.StringBindVariable("abc"),
+	})
+	query := "select /*vt+ PRIORITY=33 */ * from music_user_map"
+
+	// The query attributes are ignored unless they are used as directives.
+	mysqlQueryAttributesAsDirectives = false
+	session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: "@unknown", Options: &querypb.ExecuteOptions{}})
+	logStats := logstats.NewLogStats(ctx, "Test", "", "", nil, streamlog.NewQueryLogConfigForTest())
+	_, _, _, err := r.fetchOrCreatePlan(ctx, session, query, map[string]*querypb.BindVariable{}, r.config.Normalize, false, logStats, true)
+	require.NoError(t, err)
+	assert.Equal(t, "33", session.Options.Priority)
+	assert.Empty(t, session.Options.WorkloadName)
+
+	mysqlQueryAttributesAsDirectives = true
+	session = econtext.NewSafeSession(&vtgatepb.Session{TargetString: "@unknown", Options: &querypb.ExecuteOptions{}})
+	plan, _, _, err := r.fetchOrCreatePlan(ctx, session, query, map[string]*querypb.BindVariable{}, r.config.Normalize, false, logStats, true)
+	require.NoError(t, err)
+	assert.Equal(t, "10", session.Options.Priority)
+	assert.Equal(t, "app", session.Options.WorkloadName)
+	// The cached plan keeps the hints of the query.
+	assert.Equal(t, "33", plan.QueryHints.Priority)
+
+	ctx = newContextWithQueryAttributes(ctx, map[string]*querypb.BindVariable{"vt_priority": sqltypes.StringBindVariable("200")})
+	_, _, _, err = r.fetchOrCreatePlan(ctx, session, query, map[string]*querypb.BindVariable{}, r.config.Normalize, false, logStats, true)
+	assert.ErrorIs(t, err, sqlparser.ErrInvalidPriority)
+}
+
+func TestExecutorQueryAttributesComment(t *testing.T) {
+	defer func(old bool) {
+		mysqlQueryAttributesAsComment = old
+	}(mysqlQueryAttributesAsComment)
+	mysqlQueryAttributesAsComment = true
+
+	executor, sbc1, _, _, ctx := createExecutorEnv(t)
+	ctx = newContextWithQueryAttributes(ctx, map[string]*querypb.BindVariable{
+		"trace_id":    sqltypes.StringBindVariable("a b*/"),
+		"application": sqltypes.StringBindVariable("app"),
+		"unset":       sqltypes.NullBindVariable,
+	})
+	session := &vtgatepb.Session{TargetString: "@primary"}
+	_, err := executorExec(ctx, executor, session, "update user set a=2 where id = 1 /* trailing */", nil)
+	require.NoError(t, err)
+	assertQueries(t, sbc1, []*querypb.BoundQuery{{
+		Sql:           "update `user` set a = 2 where id = 1 /* trailing */ /*application='app',trace_id='a%20b%2A%2F'*/",
+		BindVariables: map[string]*querypb.BindVariable{},
+	}})
+}
+
 func TestPassthroughDDL(t *testing.T) {
 	executor, sbc1, sbc2, _, ctx := createExecutorEnvWithConfig(t, createExecutorConfigWithNormalizer())
 	session := &vtgatepb.Session{
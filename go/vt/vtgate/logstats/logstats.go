@@ -57,6 +57,7 @@ type LogStats struct {
 	MirrorSourceExecuteTime time.Duration
 	MirrorTargetExecuteTime time.Duration
 	MirrorTargetError       error
+	QueryAttributes         map[string]*querypb.BindVariable
 }
 
 // NewLogStats constructs a new LogStats with supplied Method and ctx
@@ -196,6 +197,12 @@ func (stats *LogStats) Logf(w io.Writer, params url.Values) error {
 	log.Duration(stats.MirrorTargetExecuteTime)
 	log.Key("MirrorTargetError")
 	log.String(stats.MirrorTargetErrorStr())
+	log.Key("QueryAttributes")
+	if stats.Config.RedactDebugUIQueries {
+		log.Redacted()
+	} else {
+		log.BindVariables(stats.QueryAttributes, fullBindParams)
+	}
 
 	return log.Flush(w)
 }
@@ -66,6 +66,9 @@ var (
 	mysqlSslServerCA                  string
 	mysqlTLSMinVersion                string
 
+	mysqlCachingSha2PrivateKeyPath string
+	mysqlCachingSha2PublicKeyPath  string
+
 	mysqlKeepAlivePeriod          time.Duration
 	mysqlConnReadTimeout          time.Duration
 	mysqlConnWriteTimeout         time.Duration
@@ -78,6 +81,9 @@ var (
 	mysqlDrainOnTerm         bool
 
 	mysqlServerFlushDelay = 100 * time.Millisecond
+
+	mysqlQueryAttributesAsComment    bool
+	mysqlQueryAttributesAsDirectives bool
 )
 
 func registerPluginFlags(fs *pflag.FlagSet) {
@@ -95,6 +101,8 @@ func registerPluginFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&mysqlSslCrl, "mysql_server_ssl_crl", mysqlSslCrl, "Path to ssl CRL for mysql server plugin SSL")
 	fs.StringVar(&mysqlTLSMinVersion, "mysql_server_tls_min_version", mysqlTLSMinVersion, "Configures the minimal TLS version negotiated when SSL is enabled. Defaults to TLSv1.2. Options: TLSv1.0, TLSv1.1, TLSv1.2, TLSv1.3.")
 	fs.StringVar(&mysqlSslServerCA, "mysql_server_ssl_server_ca", mysqlSslServerCA, "path to server CA in PEM format, which will be combine with server cert, return full certificate chain to clients")
+	fs.StringVar(&mysqlCachingSha2PrivateKeyPath, "mysql-server-caching-sha2-private-key-path", mysqlCachingSha2PrivateKeyPath, "Path to the RSA private key in PEM format used by caching_sha2_password to decrypt passwords sent over non-SSL connections. If not set, caching_sha2_password requires SSL or a unix socket.")
+	fs.StringVar(&mysqlCachingSha2PublicKeyPath, "mysql-server-caching-sha2-public-key-path", mysqlCachingSha2PublicKeyPath, "Path to the RSA public key in PEM format matching --mysql-server-caching-sha2-private-key-path. If not set, it is derived from the private key.")
 	fs.DurationVar(&mysqlSlowConnectWarnThreshold, "mysql_slow_connect_warn_threshold", mysqlSlowConnectWarnThreshold, "Warn if it takes more than the given threshold for a mysql connection to establish")
 	fs.DurationVar(&mysqlConnReadTimeout, "mysql_server_read_timeout", mysqlConnReadTimeout, "connection read timeout")
 	fs.DurationVar(&mysqlConnWriteTimeout, "mysql_server_write_timeout", mysqlConnWriteTimeout, "connection write timeout")
@@ -104,6 +112,8 @@ func registerPluginFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&mysqlServerFlushDelay, "mysql_server_flush_delay", mysqlServerFlushDelay, "Delay after which buffered response will be flushed to the client.")
 	fs.StringVar(&mysqlDefaultWorkloadName, "mysql_default_workload", mysqlDefaultWorkloadName, "Default session workload (OLTP, OLAP, DBA)")
 	fs.BoolVar(&mysqlDrainOnTerm, "mysql-server-drain-onterm", mysqlDrainOnTerm, "If set, the server waits for --onterm_timeout for already connected clients to complete their in flight work")
+	fs.BoolVar(&mysqlQueryAttributesAsComment, "mysql-server-query-attributes-as-comment", mysqlQueryAttributesAsComment, "If set, the query attributes sent by MySQL clients are appended to their queries as a comment in the sqlcommenter format, which is forwarded to the tablets.")
+	fs.BoolVar(&mysqlQueryAttributesAsDirectives, "mysql-server-query-attributes-as-directives", mysqlQueryAttributesAsDirectives, "If set, the query attributes sent by MySQL clients with a vt_ prefix are used as query directives, e.g. vt_workload_name, vt_priority, vt_query_timeout_ms or vt_ignore_max_memory_rows.")
 }
 
 // vtgateHandler implements the Listener interface.
@@ -240,6 +250,7 @@ func (vh *vtgateHandler) ComQuery(c *mysql.Conn, query string, callback func(*sq
 		c.RemoteAddr().String(), /* component: running client process */
 		"VTGate MySQL Connector" /* subcomponent: part of the client */)
 	ctx = callerid.NewContext(ctx, ef, im)
+	ctx = newContextWithQueryAttributes(ctx, c.QueryAttributes)
 
 	if !session.InTransaction {
 		vh.busyConnections.Add(1)
@@ -297,6 +308,7 @@ func (vh *vtgateHandler) ComQueryMulti(c *mysql.Conn, sql string, callback func(
 		c.RemoteAddr().String(), /* component: running client process */
 		"VTGate MySQL Connector" /* subcomponent: part of the client */)
 	ctx = callerid.NewContext(ctx, ef, im)
+	ctx = newContextWithQueryAttributes(ctx, c.QueryAttributes)
 
 	if !session.InTransaction {
 		vh.busyConnections.Add(1)
@@ -428,6 +440,7 @@ func (vh *vtgateHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareDat
 		c.RemoteAddr().String(), /* component: running client process */
 		"VTGate MySQL Connector" /* subcomponent: part of the client */)
 	ctx = callerid.NewContext(ctx, ef, im)
+	ctx = newContextWithQueryAttributes(ctx, c.QueryAttributes)
 
 	session := vh.session(c)
 	if !session.InTransaction {
@@ -632,6 +645,15 @@ func initMySQLProtocol(vtgate *VTGate) *mysqlServer {
 
 			_ = initTLSConfig(context.Background(), srv, mysqlSslCert, mysqlSslKey, mysqlSslCa, mysqlSslCrl, mysqlSslServerCA, mysqlServerRequireSecureTransport, tlsVersion)
 		}
+		if mysqlCachingSha2PrivateKeyPath != "" {
+			keys, err := mysql.LoadRSAKeyPair(mysqlCachingSha2PrivateKeyPath, mysqlCachingSha2PublicKeyPath)
+			if err != nil {
+				log.Exitf("mysql.LoadRSAKeyPair failed: %v", err)
+			}
+			srv.tcpListener.RSAKeyPair.Store(keys)
+		} else if mysqlCachingSha2PublicKeyPath != "" {
+			log.Exitf("--mysql-server-caching-sha2-public-key-path requires --mysql-server-caching-sha2-private-key-path")
+		}
 		srv.tcpListener.AllowClearTextWithoutTLS.Store(mysqlAllowClearTextWithoutTLS)
 		// Check for the connection threshold
 		if mysqlSlowConnectWarnThreshold != 0 {
@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// queryAttributeDirectivePrefix is the prefix of the query attributes which
+// are used as query directives, e.g. vt_workload_name for WORKLOAD_NAME.
+const queryAttributeDirectivePrefix = "vt_"
+
+// queryAttributesKey is the context key of the query attributes a MySQL
+// client sent with its query.
+type queryAttributesKey struct{}
+
+// newContextWithQueryAttributes returns a copy of ctx holding the query
+// attributes, or ctx itself if there are none.
+func newContextWithQueryAttributes(ctx context.Context, attrs map[string]*querypb.BindVariable) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, queryAttributesKey{}, attrs)
+}
+
+// queryAttributesFromContext returns the query attributes held by ctx, if any.
+func queryAttributesFromContext(ctx context.Context) map[string]*querypb.BindVariable {
+	attrs, _ := ctx.Value(queryAttributesKey{}).(map[string]*querypb.BindVariable)
+	return attrs
+}
+
+// queryAttributeString returns the value of a query attribute as a string,
+// or false if it is NULL or invalid.
+func queryAttributeString(bv *querypb.BindVariable) (string, bool) {
+	val, err := sqltypes.BindVariableToValue(bv)
+	if err != nil || val.IsNull() {
+		return "", false
+	}
+	return val.ToString(), true
+}
+
+// queryAttributesComment formats the query attributes as a comment in the
+// sqlcommenter format, which can be appended to the query. NULL attributes
+// are left out.
+func queryAttributesComment(attrs map[string]*querypb.BindVariable) string {
+	var pairs []string
+	for _, key := range slices.Sorted(maps.Keys(attrs)) {
+		val, ok := queryAttributeString(attrs[key])
+		if !ok {
+			continue
+		}
+		pairs = append(pairs, url.PathEscape(key)+"='"+url.PathEscape(val)+"'")
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return " /*" + strings.Join(pairs, ",") + "*/"
+}
+
+// mergeQueryAttributesHints returns the query hints, overridden by the ones
+// set by the query attributes with the vt_ prefix.
+func mergeQueryAttributesHints(qh sqlparser.QueryHints, attrs map[string]*querypb.BindVariable) (sqlparser.QueryHints, error) {
+	directives := make(map[string]string)
+	for key, bv := range attrs {
+		name, ok := strings.CutPrefix(strings.ToLower(key), queryAttributeDirectivePrefix)
+		if !ok {
+			continue
+		}
+		if val, ok := queryAttributeString(bv); ok {
+			directives[name] = val
+		}
+	}
+	if len(directives) == 0 {
+		return qh, nil
+	}
+
+	attrsHints, err := sqlparser.BuildQueryHintsFromDirectives(directives)
+	if err != nil {
+		return qh, err
+	}
+	if attrsHints.IgnoreMaxMemoryRows {
+		qh.IgnoreMaxMemoryRows = true
+	}
+	if attrsHints.Workload != "" {
+		qh.Workload = attrsHints.Workload
+	}
+	if attrsHints.Priority != "" {
+		qh.Priority = attrsHints.Priority
+	}
+	if attrsHints.Timeout != nil {
+		qh.Timeout = attrsHints.Timeout
+	}
+	return qh, nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestQueryAttributesContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, ctx, newContextWithQueryAttributes(ctx, nil))
+	assert.Nil(t, queryAttributesFromContext(ctx))
+
+	attrs := map[string]*querypb.BindVariable{"trace_id": sqltypes.StringBindVariable("abc")}
+	assert.Equal(t, attrs, queryAttributesFromContext(newContextWithQueryAttributes(ctx, attrs)))
+}
+
+func TestQueryAttributesComment(t *testing.T) {
+	assert.Empty(t, queryAttributesComment(nil))
+	assert.Empty(t, queryAttributesComment(map[string]*querypb.BindVariable{"unset": sqltypes.NullBindVariable}))
+	assert.Equal(t, " /*retries='3',trace_id='it%27s'*/", queryAttributesComment(map[string]*querypb.BindVariable{
+		"trace_id": sqltypes.StringBindVariable("it's"),
+		"retries":  sqltypes.Int64BindVariable(3),
+	}))
+}
+
+func TestMergeQueryAttributesHints(t *testing.T) {
+	timeout, attrsTimeout := 100, 20
+	qh := sqlparser.QueryHints{Workload: "query", Priority: "33", Timeout: &timeout}
+
+	got, err := mergeQueryAttributesHints(qh, map[string]*querypb.BindVariable{
+		"trace_id":            sqltypes.StringBindVariable("abc"),
+		"VT_QUERY_TIMEOUT_MS": sqltypes.Int64BindVariable(20),
+		"vt_priority":         sqltypes.NullBindVariable,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, sqlparser.QueryHints{Workload: "query", Priority: "33", Timeout: &attrsTimeout}, got)
+	assert.Equal(t, 100, timeout)
+
+	_, err = mergeQueryAttributesHints(qh, map[string]*querypb.BindVariable{"vt_priority": sqltypes.StringBindVariable("high")})
+	assert.ErrorIs(t, err, sqlparser.ErrInvalidPriority)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package vtgate
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"sort"
@@ -117,6 +118,31 @@ func (qzs *queryzRow) ErrorsPQ() string {
 	return fmt.Sprintf("%.6f", float64(qzs.Errors)/float64(qzs.Count))
 }
 
+// QueryzJSON is the JSON format of /debug/queryz, served with ?format=json.
+type QueryzJSON struct {
+	// ExecTimeBuckets are the upper bounds of the buckets of the execution
+	// time histograms of the queries.
+	ExecTimeBuckets []time.Duration
+	Queries         []*QueryzJSONRow
+}
+
+// QueryzJSONRow holds the stats of one query plan. Its ExecTimes histogram
+// has one more count than there are ExecTimeBuckets, for the executions slower
+// than all of them.
+type QueryzJSONRow struct {
+	Query        string
+	PlanType     string
+	QueryType    string
+	Tables       []string `json:",omitempty"`
+	Count        uint64
+	Time         time.Duration
+	ShardQueries uint64
+	RowsAffected uint64
+	RowsReturned uint64
+	Errors       uint64
+	ExecTimes    []uint64
+}
+
 type queryzSorter struct {
 	rows []*queryzRow
 	less func(row1, row2 *queryzRow) bool
@@ -131,6 +157,11 @@ func queryzHandler(e *Executor, w http.ResponseWriter, r *http.Request) {
 		acl.SendError(w, err)
 		return
 	}
+	if r.FormValue("format") == "json" {
+		queryzJSONHandler(e, w)
+		return
+	}
+
 	logz.StartHTMLTable(w)
 	defer logz.EndHTMLTable(w)
 	w.Write(queryzHeader)
@@ -169,3 +200,31 @@ func queryzHandler(e *Executor, w http.ResponseWriter, r *http.Request) {
 		}
 	}
 }
+
+func queryzJSONHandler(e *Executor, w http.ResponseWriter) {
+	resp := QueryzJSON{
+		ExecTimeBuckets: engine.ExecTimeBuckets[:],
+		Queries:         []*QueryzJSONRow{},
+	}
+
+	e.ForEachPlan(func(plan *engine.Plan) bool {
+		row := &QueryzJSONRow{
+			Query:     plan.Original,
+			PlanType:  plan.Type.String(),
+			QueryType: plan.QueryType.String(),
+			Tables:    plan.TablesUsed,
+			ExecTimes: plan.ExecTimeCounts(),
+		}
+		row.Count, row.Time, row.ShardQueries, row.RowsAffected, row.RowsReturned, row.Errors = plan.Stats()
+		resp.Queries = append(resp.Queries, row)
+		return true
+	})
+
+	js, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(js)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package vtgate
 
 import (
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -25,6 +26,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
@@ -154,6 +156,48 @@ func TestQueryzHandler(t *testing.T) {
 	checkQueryzHasPlan(t, planPattern4, plan4, body)
 }
 
+func TestQueryzHandlerJSON(t *testing.T) {
+	executor, _, _, _, ctx := createExecutorEnv(t)
+
+	session := &vtgatepb.Session{TargetString: "@primary"}
+	sql := "select id from user where id = 1"
+	_, err := executorExec(ctx, executor, session, sql, nil)
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	plan := assertCacheContains(t, executor, nil, "select id from `user` where id = 1")
+
+	resp := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/debug/queryz?format=json", nil)
+	queryzHandler(executor, resp, req)
+	require.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+
+	var queryz QueryzJSON
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &queryz))
+	require.Equal(t, engine.ExecTimeBuckets[:], queryz.ExecTimeBuckets)
+
+	var row *QueryzJSONRow
+	for _, r := range queryz.Queries {
+		if r.Query == plan.Original {
+			row = r
+		}
+	}
+	require.NotNil(t, row, "queryz does not contain %s", plan.Original)
+
+	assert.Equal(t, "Passthrough", row.PlanType)
+	assert.Equal(t, "SELECT", row.QueryType)
+	assert.Equal(t, []string{"TestExecutor.user"}, row.Tables)
+	assert.EqualValues(t, 1, row.Count)
+	assert.EqualValues(t, 1, row.ShardQueries)
+	assert.EqualValues(t, 1, row.RowsReturned)
+	assert.Len(t, row.ExecTimes, len(engine.ExecTimeBuckets)+1)
+
+	var total uint64
+	for _, count := range row.ExecTimes {
+		total += count
+	}
+	assert.EqualValues(t, 1, total)
+}
+
 func checkQueryzHasPlan(t *testing.T, planPattern []string, plan *engine.Plan, page []byte) {
 	t.Helper()
 	matcher := regexp.MustCompile(strings.Join(planPattern, `\s*`))
@@ -37,6 +37,7 @@ import (
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
 
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
@@ -51,6 +52,7 @@ type vstreamManager struct {
 	resolver *srvtopo.Resolver
 	toposerv srvtopo.Server
 	cell     string
+	env      *vtenv.Environment
 
 	vstreamsCreated         *stats.CountersWithMultiLabels
 	vstreamsLag             *stats.GaugesWithMultiLabels
@@ -137,6 +139,10 @@ type vstream struct {
 	tabletPickerOptions discovery.TabletPickerOptions
 
 	flags *vtgatepb.VStreamFlags
+
+	// projector names the streamed tables and projects their columns and
+	// rows, as requested in the flags.
+	projector *vstreamProjector
 }
 
 type journalEvent struct {
@@ -145,7 +151,7 @@ type journalEvent struct {
 	done         chan struct{}
 }
 
-func newVStreamManager(resolver *srvtopo.Resolver, serv srvtopo.Server, cell string) *vstreamManager {
+func newVStreamManager(env *vtenv.Environment, resolver *srvtopo.Resolver, serv srvtopo.Server, cell string) *vstreamManager {
 	exporter := servenv.NewExporter(cell, "VStreamManager")
 	labels := []string{"Keyspace", "ShardName", "TabletType"}
 
@@ -153,6 +159,7 @@ func newVStreamManager(resolver *srvtopo.Resolver, serv srvtopo.Server, cell str
 		resolver: resolver,
 		toposerv: serv,
 		cell:     cell,
+		env:      env,
 		vstreamsCreated: exporter.NewCountersWithMultiLabels(
 			"VStreamsCreated",
 			"Number of vstreams created",
@@ -182,6 +189,13 @@ func (vsm *vstreamManager) VStream(ctx context.Context, tabletType topodatapb.Ta
 	if err != nil {
 		return vterrors.Wrap(err, "failed to resolve vstream parameters")
 	}
+	projector, err := newVStreamProjector(vsm.env, flags)
+	if err != nil {
+		return err
+	}
+	if err := projector.checkOutputNames(vgtid, filter); err != nil {
+		return err
+	}
 	ts, err := vsm.toposerv.GetTopoServer()
 	if err != nil {
 		return vterrors.Wrap(err, "failed to get topology server")
@@ -216,7 +230,8 @@ func (vsm *vstreamManager) VStream(ctx context.Context, tabletType topodatapb.Ta
 			// health stream.
 			ExcludeTabletsWithMaxReplicationLag: discovery.GetLowReplicationLag(),
 		},
-		flags: flags,
+		flags:     flags,
+		projector: projector,
 	}
 	return vs.stream(ctx)
 }
@@ -651,6 +666,9 @@ func (vs *vstream) streamFromTablet(ctx context.Context, sgtid *binlogdatapb.Sha
 			Options:      options,
 		}
 		log.Infof("Starting to vstream from %s, with req %+v", tabletAliasString, req)
+		// The projector keeps the columns of the tables, which the tablet
+		// sends again on every new stream.
+		projector := vs.projector.forShard(sgtid.Keyspace)
 		err = tabletConn.VStream(ctx, req, func(events []*binlogdatapb.VEvent) error {
 			// We received a valid event. Reset error count.
 			errCount = 0
@@ -684,13 +702,21 @@ func (vs *vstream) streamFromTablet(ctx context.Context, sgtid *binlogdatapb.Sha
 					// If we're streaming from multiple keyspaces, this will disambiguate
 					// duplicate table names.
 					ev := event.CloneVT()
-					ev.FieldEvent.TableName = sgtid.Keyspace + "." + ev.FieldEvent.TableName
+					if err := projector.projectFields(ev.FieldEvent); err != nil {
+						return err
+					}
 					sendevents = append(sendevents, ev)
 				case binlogdatapb.VEventType_ROW:
-					// Update table names and send.
+					// Update table names and send, unless the projection
+					// filtered out all the row changes.
 					ev := event.CloneVT()
-					ev.RowEvent.TableName = sgtid.Keyspace + "." + ev.RowEvent.TableName
-					sendevents = append(sendevents, ev)
+					ok, err := projector.projectRows(ev.RowEvent)
+					if err != nil {
+						return err
+					}
+					if ok {
+						sendevents = append(sendevents, ev)
+					}
 				case binlogdatapb.VEventType_COMMIT, binlogdatapb.VEventType_DDL, binlogdatapb.VEventType_OTHER:
 					sendevents = append(sendevents, event)
 					eventss = append(eventss, sendevents)
@@ -29,6 +29,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
 
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/discovery"
@@ -37,6 +38,7 @@ import (
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
 
@@ -202,6 +204,200 @@ func TestVStreamEvents(t *testing.T) {
 	<-ch
 }
 
+// TestVStreamProjections tests that the tables are renamed, and their columns
+// and rows projected, as requested in the flags.
+func TestVStreamProjections(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cell := "aa"
+	ks := "TestVStream"
+	_ = createSandbox(ks)
+	hc := discovery.NewFakeHealthCheck(nil)
+	st := getSandboxTopo(ctx, cell, ks, []string{"-20"})
+
+	vsm := newTestVStreamManager(ctx, hc, st, cell)
+	sbc0 := hc.AddTestTablet(cell, "1.1.1.1", 1001, ks, "-20", topodatapb.TabletType_PRIMARY, true, 1, nil)
+	addTabletToSandboxTopo(t, ctx, st, ks, "-20", sbc0.Tablet())
+
+	fields := sqltypes.MakeTestFields("id|name|secret", "int64|varchar|varchar")
+	row := func(id int64, name string) *querypb.Row {
+		return sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(id), sqltypes.NewVarChar(name), sqltypes.NewVarChar("s")})
+	}
+	send := []*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_GTID, Gtid: "gtid01"},
+		{Type: binlogdatapb.VEventType_FIELD, FieldEvent: &binlogdatapb.FieldEvent{TableName: "t1", Fields: fields}},
+		{Type: binlogdatapb.VEventType_FIELD, FieldEvent: &binlogdatapb.FieldEvent{TableName: "t2", Fields: fields}},
+		{Type: binlogdatapb.VEventType_ROW, RowEvent: &binlogdatapb.RowEvent{TableName: "t1", RowChanges: []*binlogdatapb.RowChange{
+			{After: row(1, "a")},
+			{Before: row(2, "b"), After: row(12, "b"), DataColumns: &binlogdatapb.RowChange_Bitmap{Count: 3, Cols: []byte{0x05}}},
+			{Before: row(3, "c")},
+		}}},
+		{Type: binlogdatapb.VEventType_ROW, RowEvent: &binlogdatapb.RowEvent{TableName: "t1", RowChanges: []*binlogdatapb.RowChange{
+			{After: row(4, "d")},
+		}}},
+		{Type: binlogdatapb.VEventType_ROW, RowEvent: &binlogdatapb.RowEvent{TableName: "t2", RowChanges: []*binlogdatapb.RowChange{
+			{After: row(1, "a")},
+		}}},
+		{Type: binlogdatapb.VEventType_COMMIT},
+	}
+	sbc0.AddVStreamEvents(send, nil)
+
+	projectedFields := []*querypb.Field{fields[1], fields[0]}
+	projectedRow := func(id int64, name string) *querypb.Row {
+		return sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewVarChar(name), sqltypes.NewInt64(id)})
+	}
+	want := &binlogdatapb.VStreamResponse{Events: []*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_VGTID, Vgtid: &binlogdatapb.VGtid{
+			ShardGtids: []*binlogdatapb.ShardGtid{{
+				Keyspace: ks,
+				Shard:    "-20",
+				Gtid:     "gtid01",
+			}},
+		}},
+		{Type: binlogdatapb.VEventType_FIELD, FieldEvent: &binlogdatapb.FieldEvent{TableName: "out.users", Fields: projectedFields}},
+		{Type: binlogdatapb.VEventType_FIELD, FieldEvent: &binlogdatapb.FieldEvent{TableName: "out.t2", Fields: fields}},
+		{Type: binlogdatapb.VEventType_ROW, RowEvent: &binlogdatapb.RowEvent{TableName: "out.users", RowChanges: []*binlogdatapb.RowChange{
+			{After: projectedRow(1, "a")},
+			{Before: projectedRow(2, "b"), After: projectedRow(12, "b"), DataColumns: &binlogdatapb.RowChange_Bitmap{Count: 2, Cols: []byte{0x02}}},
+		}}},
+		{Type: binlogdatapb.VEventType_ROW, RowEvent: &binlogdatapb.RowEvent{TableName: "out.t2", RowChanges: []*binlogdatapb.RowChange{
+			{After: row(1, "a")},
+		}}},
+		{Type: binlogdatapb.VEventType_COMMIT},
+	}}
+
+	vgtid := &binlogdatapb.VGtid{
+		ShardGtids: []*binlogdatapb.ShardGtid{{
+			Keyspace: ks,
+			Shard:    "-20",
+			Gtid:     "pos",
+		}},
+	}
+	flags := &vtgatepb.VStreamFlags{
+		OutputSchema: "out",
+		TableProjections: []*vtgatepb.VStreamTableProjection{{
+			Keyspace:    ks,
+			Table:       "t1",
+			OutputTable: "users",
+			Columns:     []string{"name", "id"},
+			Where:       "id < 3",
+		}},
+	}
+	ch := make(chan *binlogdatapb.VStreamResponse)
+	go func() {
+		err := vsm.VStream(ctx, topodatapb.TabletType_PRIMARY, vgtid, nil, flags, func(events []*binlogdatapb.VEvent) error {
+			ch <- &binlogdatapb.VStreamResponse{Events: events}
+			return nil
+		})
+		assert.ErrorContains(t, err, "context canceled")
+		ch <- nil
+	}()
+	verifyEvents(t, ch, want)
+
+	cancel()
+	<-ch
+}
+
+func TestVStreamProjectionErrors(t *testing.T) {
+	testcases := []struct {
+		projections []*vtgatepb.VStreamTableProjection
+		wantErr     string
+	}{{
+		projections: []*vtgatepb.VStreamTableProjection{{Keyspace: "ks"}},
+		wantErr:     "table projection has no table",
+	}, {
+		projections: []*vtgatepb.VStreamTableProjection{{Table: "t1"}, {Table: "t1"}},
+		wantErr:     "duplicate projection for table t1",
+	}, {
+		projections: []*vtgatepb.VStreamTableProjection{{Table: "t1", Columns: []string{"id", "id"}}},
+		wantErr:     "duplicate column id in projection for table t1",
+	}, {
+		projections: []*vtgatepb.VStreamTableProjection{{Table: "t1", Where: "id <"}},
+		wantErr:     "invalid where expression in projection for table t1",
+	}}
+	for _, tc := range testcases {
+		t.Run(tc.wantErr, func(t *testing.T) {
+			_, err := newVStreamProjector(vtenv.NewTestEnv(), &vtgatepb.VStreamFlags{TableProjections: tc.projections})
+			require.ErrorContains(t, err, tc.wantErr)
+			require.Equal(t, vtrpcpb.Code_INVALID_ARGUMENT, vterrors.Code(err))
+		})
+	}
+
+	fields := sqltypes.MakeTestFields("id|name", "int64|varchar")
+	vp, err := newVStreamProjector(vtenv.NewTestEnv(), &vtgatepb.VStreamFlags{
+		TableProjections: []*vtgatepb.VStreamTableProjection{{Table: "t1", Columns: []string{"id", "missing"}}},
+	})
+	require.NoError(t, err)
+	err = vp.forShard("ks").projectFields(&binlogdatapb.FieldEvent{TableName: "t1", Fields: fields})
+	require.ErrorContains(t, err, "unknown column missing in projection for table ks.t1")
+	require.Equal(t, vtrpcpb.Code_INVALID_ARGUMENT, vterrors.Code(err))
+
+	vgtid := &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{
+		{Keyspace: "ks1", Shard: "-80"},
+		{Keyspace: "ks1", Shard: "80-"},
+		{Keyspace: "ks2", Shard: "0"},
+	}}
+	tableRules := &binlogdatapb.Filter{Rules: []*binlogdatapb.Rule{{Match: "t1"}, {Match: "t2"}}}
+	allTables := &binlogdatapb.Filter{Rules: []*binlogdatapb.Rule{{Match: "/.*"}}}
+	outputNames := []struct {
+		name    string
+		filter  *binlogdatapb.Filter
+		flags   *vtgatepb.VStreamFlags
+		wantErr string
+	}{{
+		name:   "tables qualified with their keyspace",
+		filter: tableRules,
+		flags:  &vtgatepb.VStreamFlags{},
+	}, {
+		name:    "same table in the output schema",
+		filter:  tableRules,
+		flags:   &vtgatepb.VStreamFlags{OutputSchema: "out"},
+		wantErr: "tables ks1.t1 and ks2.t1 are both streamed as out.t1",
+	}, {
+		name:   "same table renamed in one keyspace",
+		filter: tableRules,
+		flags: &vtgatepb.VStreamFlags{
+			OutputSchema:     "out",
+			TableProjections: []*vtgatepb.VStreamTableProjection{{Keyspace: "ks1", Table: "t1", OutputTable: "ks1_t1"}, {Keyspace: "ks1", Table: "t2", OutputTable: "ks1_t2"}},
+		},
+	}, {
+		name:   "same output table",
+		filter: allTables,
+		flags: &vtgatepb.VStreamFlags{
+			TableProjections: []*vtgatepb.VStreamTableProjection{{Table: "t1", OutputTable: "users"}, {Table: "t2", OutputTable: "users"}},
+		},
+		wantErr: "tables ks1.t1 and ks1.t2 are both streamed as ks1.users",
+	}, {
+		name:   "output table of a streamed table",
+		filter: tableRules,
+		flags: &vtgatepb.VStreamFlags{
+			TableProjections: []*vtgatepb.VStreamTableProjection{{Keyspace: "ks2", Table: "t1", OutputTable: "t2"}},
+		},
+		wantErr: "tables ks2.t1 and ks2.t2 are both streamed as ks2.t2",
+	}, {
+		name:   "same output table in the output schema",
+		filter: allTables,
+		flags: &vtgatepb.VStreamFlags{
+			OutputSchema:     "out",
+			TableProjections: []*vtgatepb.VStreamTableProjection{{Keyspace: "ks1", Table: "t1", OutputTable: "users"}, {Keyspace: "ks2", Table: "t2", OutputTable: "users"}},
+		},
+		wantErr: "tables ks1.t1 and ks2.t2 are both streamed as out.users",
+	}}
+	for _, tc := range outputNames {
+		t.Run(tc.name, func(t *testing.T) {
+			vp, err := newVStreamProjector(vtenv.NewTestEnv(), tc.flags)
+			require.NoError(t, err)
+			err = vp.checkOutputNames(vgtid, tc.filter)
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.wantErr)
+			require.Equal(t, vtrpcpb.Code_INVALID_ARGUMENT, vterrors.Code(err))
+		})
+	}
+}
+
 // TestVStreamChunks ensures that a transaction that's broken
 // into chunks is sent together.
 func TestVStreamChunks(t *testing.T) {
@@ -1779,7 +1975,7 @@ func TestVStreamManagerHealthCheckResponseHandling(t *testing.T) {
 func newTestVStreamManager(ctx context.Context, hc discovery.HealthCheck, serv srvtopo.Server, cell string) *vstreamManager {
 	gw := NewTabletGateway(ctx, hc, serv, cell)
 	srvResolver := srvtopo.NewResolver(serv, gw, cell)
-	return newVStreamManager(srvResolver, serv, cell)
+	return newVStreamManager(vtenv.NewTestEnv(), srvResolver, serv, cell)
 }
 
 func startVStream(ctx context.Context, t *testing.T, vsm *vstreamManager, vgtid *binlogdatapb.VGtid, flags *vtgatepb.VStreamFlags) <-chan *binlogdatapb.VStreamResponse {
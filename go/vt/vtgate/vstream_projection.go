@@ -0,0 +1,318 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// vstreamProjector names the tables of the FIELD and ROW events of a VStream,
+// and restricts their columns and rows to the projections requested in the
+// VStream flags.
+type vstreamProjector struct {
+	env          *vtenv.Environment
+	outputSchema string
+	// projections are the table projections, by keyspace and table. The
+	// projections that apply to all the keyspaces have an empty keyspace.
+	projections map[string]*tableProjection
+}
+
+// tableProjection is a table projection with its where expression parsed.
+type tableProjection struct {
+	*vtgatepb.VStreamTableProjection
+	where sqlparser.Expr
+}
+
+// newVStreamProjector validates the projections of the flags.
+func newVStreamProjector(env *vtenv.Environment, flags *vtgatepb.VStreamFlags) (*vstreamProjector, error) {
+	vp := &vstreamProjector{
+		env:          env,
+		outputSchema: flags.GetOutputSchema(),
+		projections:  make(map[string]*tableProjection),
+	}
+	for _, tp := range flags.GetTableProjections() {
+		if tp.Table == "" {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "table projection has no table: %v", tp)
+		}
+		key := projectionKey(tp.Keyspace, tp.Table)
+		name := tp.Table
+		if tp.Keyspace != "" {
+			name = key
+		}
+		if _, ok := vp.projections[key]; ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "duplicate projection for table %s", name)
+		}
+		seen := make(map[string]bool, len(tp.Columns))
+		for _, col := range tp.Columns {
+			if seen[col] {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "duplicate column %s in projection for table %s", col, name)
+			}
+			seen[col] = true
+		}
+		proj := &tableProjection{VStreamTableProjection: tp}
+		if tp.Where != "" {
+			expr, err := env.Parser().ParseExpr(tp.Where)
+			if err != nil {
+				return nil, vterrors.Wrapf(err, "invalid where expression in projection for table %s", name)
+			}
+			proj.where = expr
+		}
+		vp.projections[key] = proj
+	}
+	return vp, nil
+}
+
+// checkOutputNames verifies that no two tables of the streamed keyspaces named
+// in the filter rules or in the projections are streamed under the same name.
+// The tables matched by regular expressions are not known in advance, and
+// are not checked.
+func (vp *vstreamProjector) checkOutputNames(vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter) error {
+	var tables []string
+	for _, rule := range filter.GetRules() {
+		if rule.Match != "" && !strings.HasPrefix(rule.Match, "/") {
+			tables = append(tables, rule.Match)
+		}
+	}
+	// outputs are the tables streamed under each output name, as
+	// keyspace.table.
+	outputs := make(map[string]string)
+	check := func(sp *shardProjector, table string) error {
+		output := sp.outputName(table, false)
+		source := projectionKey(sp.keyspace, table)
+		if other, ok := outputs[output]; ok && other != source {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "tables %s and %s are both streamed as %s", other, source, output)
+		}
+		outputs[output] = source
+		return nil
+	}
+	for _, sgtid := range vgtid.GetShardGtids() {
+		sp := vp.forShard(sgtid.Keyspace)
+		for _, table := range tables {
+			if err := check(sp, table); err != nil {
+				return err
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(vp.projections)) {
+			proj := vp.projections[key]
+			if proj.Keyspace != "" && proj.Keyspace != sgtid.Keyspace {
+				continue
+			}
+			if err := check(sp, proj.Table); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// forShard returns the projector of the events streamed from a shard of the
+// keyspace.
+func (vp *vstreamProjector) forShard(keyspace string) *shardProjector {
+	return &shardProjector{
+		vstreamProjector: vp,
+		keyspace:         keyspace,
+		tables:           make(map[string]*projectedTable),
+	}
+}
+
+func projectionKey(keyspace, table string) string {
+	return keyspace + "." + table
+}
+
+// shardProjector projects the events streamed from a single shard. It keeps
+// the columns of the tables it received the FIELD events of.
+type shardProjector struct {
+	*vstreamProjector
+	keyspace string
+	// tables are the projected tables, by name in the keyspace.
+	tables map[string]*projectedTable
+}
+
+// projectedTable is a table with a projection, resolved against its columns.
+type projectedTable struct {
+	fields []*querypb.Field
+	// columns are the indexes of the projected columns in the rows of the
+	// table.
+	columns []int
+	// where filters the row changes, nil if they are all streamed.
+	where evalengine.Expr
+}
+
+// outputName returns the name a table of the keyspace is streamed as.
+func (sp *shardProjector) outputName(table string, internal bool) string {
+	schema := sp.keyspace
+	if sp.outputSchema != "" && !internal {
+		schema = sp.outputSchema
+	}
+	if proj := sp.projection(table); proj != nil && proj.OutputTable != "" && !internal {
+		table = proj.OutputTable
+	}
+	return schema + "." + table
+}
+
+// projection returns the projection of a table of the keyspace, nil if it
+// has none.
+func (sp *shardProjector) projection(table string) *tableProjection {
+	if proj, ok := sp.projections[projectionKey(sp.keyspace, table)]; ok {
+		return proj
+	}
+	return sp.projections[projectionKey("", table)]
+}
+
+// projectFields renames the table of a FIELD event and removes the columns
+// that are not projected. The event must be a copy.
+func (sp *shardProjector) projectFields(fe *binlogdatapb.FieldEvent) error {
+	table := fe.TableName
+	fe.TableName = sp.outputName(table, fe.IsInternalTable)
+	proj := sp.projection(table)
+	if proj == nil || fe.IsInternalTable {
+		delete(sp.tables, table)
+		return nil
+	}
+	pt := &projectedTable{fields: fe.Fields}
+	if proj.where != nil {
+		where, err := evalengine.Translate(proj.where, &evalengine.Config{
+			ResolveColumn: evalengine.FieldResolver(fe.Fields).Column,
+			ResolveType:   evalengine.FieldResolver(fe.Fields).Type,
+			Collation:     collations.CollationUtf8mb4ID,
+			Environment:   sp.env,
+		})
+		if err != nil {
+			return vterrors.Wrapf(err, "invalid where expression in projection for table %s.%s", sp.keyspace, table)
+		}
+		pt.where = where
+	}
+	if len(proj.Columns) == 0 {
+		sp.tables[table] = pt
+		return nil
+	}
+	fields := make([]*querypb.Field, 0, len(proj.Columns))
+	for _, col := range proj.Columns {
+		idx := -1
+		for i, field := range fe.Fields {
+			if field.Name == col {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown column %s in projection for table %s.%s", col, sp.keyspace, table)
+		}
+		pt.columns = append(pt.columns, idx)
+		fields = append(fields, fe.Fields[idx])
+	}
+	fe.Fields = fields
+	sp.tables[table] = pt
+	return nil
+}
+
+// projectRows renames the table of a ROW event, removes the row changes that
+// don't match the where expression of its projection, and the columns that
+// are not projected from the remaining ones. The event must be a copy. It
+// returns false if no row changes remain.
+func (sp *shardProjector) projectRows(re *binlogdatapb.RowEvent) (bool, error) {
+	table := re.TableName
+	re.TableName = sp.outputName(table, re.IsInternalTable)
+	pt, ok := sp.tables[table]
+	if !ok || re.IsInternalTable {
+		return true, nil
+	}
+	rowChanges := re.RowChanges[:0]
+	for _, rc := range re.RowChanges {
+		match, err := pt.match(sp.env, rc)
+		if err != nil {
+			return false, vterrors.Wrapf(err, "failed to evaluate where expression in projection for table %s.%s", sp.keyspace, table)
+		}
+		if !match {
+			continue
+		}
+		if pt.columns != nil {
+			rc.Before = pt.projectRow(rc.Before)
+			rc.After = pt.projectRow(rc.After)
+			rc.DataColumns = pt.projectBitmap(rc.DataColumns)
+			rc.JsonPartialValues = pt.projectBitmap(rc.JsonPartialValues)
+		}
+		rowChanges = append(rowChanges, rc)
+	}
+	re.RowChanges = rowChanges
+	return len(rowChanges) > 0, nil
+}
+
+// match returns true if the before or the after image of the row change
+// matches the where expression.
+func (pt *projectedTable) match(env *vtenv.Environment, rc *binlogdatapb.RowChange) (bool, error) {
+	if pt.where == nil {
+		return true, nil
+	}
+	for _, row := range []*querypb.Row{rc.Before, rc.After} {
+		if row == nil {
+			continue
+		}
+		exprEnv := evalengine.EmptyExpressionEnv(env)
+		exprEnv.Row = sqltypes.MakeRowTrusted(pt.fields, row)
+		res, err := exprEnv.Evaluate(pt.where)
+		if err != nil {
+			return false, err
+		}
+		if res.ToBoolean() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (pt *projectedTable) projectRow(row *querypb.Row) *querypb.Row {
+	if row == nil {
+		return nil
+	}
+	values := sqltypes.MakeRowTrusted(pt.fields, row)
+	projected := make([]sqltypes.Value, 0, len(pt.columns))
+	for _, idx := range pt.columns {
+		projected = append(projected, values[idx])
+	}
+	return sqltypes.RowToProto3(projected)
+}
+
+func (pt *projectedTable) projectBitmap(bitmap *binlogdatapb.RowChange_Bitmap) *binlogdatapb.RowChange_Bitmap {
+	if bitmap == nil || bitmap.Count == 0 {
+		return bitmap
+	}
+	cols := make([]byte, (len(pt.columns)+7)/8)
+	for i, idx := range pt.columns {
+		if idx < int(bitmap.Count) && bitmap.Cols[idx/8]&(1<<(idx%8)) != 0 {
+			cols[i/8] |= 1 << (i % 8)
+		}
+	}
+	return &binlogdatapb.RowChange_Bitmap{
+		Count: int64(len(pt.columns)),
+		Cols:  cols,
+	}
+}
@@ -348,7 +348,7 @@ func Init(
 	tr := txresolver.NewTxResolver(gw.hc.Subscribe(txResolverHcName), tc)
 	srvResolver := srvtopo.NewResolver(serv, gw, cell)
 	resolver := NewResolver(srvResolver, serv, cell, sc)
-	vsm := newVStreamManager(srvResolver, serv, cell)
+	vsm := newVStreamManager(env, srvResolver, serv, cell)
 
 	// Create a global cache to use for lookups of the sidecar database
 	// identifier in use by each keyspace.
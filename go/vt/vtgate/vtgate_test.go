@@ -726,7 +726,7 @@ func createVtgateEnv(t testing.TB) (*VTGate, *sandboxconn.SandboxConn, context.C
 	sb.ShardSpec = "-"
 	executor, _, _, sbc, ctx := createExecutorEnvWithConfig(t, createExecutorConfigWithNormalizer())
 
-	vsm := newVStreamManager(executor.resolver.resolver, executor.serv, cell)
+	vsm := newVStreamManager(executor.env, executor.resolver.resolver, executor.serv, cell)
 	vtg := newVTGate(executor, executor.resolver, vsm, nil, executor.scatterConn.gateway)
 
 	return vtg, sbc, ctx
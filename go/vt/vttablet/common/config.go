@@ -49,6 +49,7 @@ type VReplicationConfig struct {
 	HeartbeatUpdateInterval int
 	StoreCompressedGTID     bool
 	ParallelInsertWorkers   int
+	ConcurrentTableCopies   int
 	TabletTypesStr          string
 	EnableHttpLog           bool // Enable the /debug/vrlog endpoint
 
@@ -94,6 +95,7 @@ func GetVReplicationConfigDefaults(useCached bool) *VReplicationConfig {
 		HeartbeatUpdateInterval: vreplicationHeartbeatUpdateInterval,
 		StoreCompressedGTID:     vreplicationStoreCompressedGTID,
 		ParallelInsertWorkers:   vreplicationParallelInsertWorkers,
+		ConcurrentTableCopies:   vreplicationConcurrentTableCopies,
 		TabletTypesStr:          vreplicationTabletTypesStr,
 		EnableHttpLog:           vreplicationEnableHttpLog,
 
@@ -220,6 +222,13 @@ func NewVReplicationConfig(overrides map[string]string) (*VReplicationConfig, er
 			} else {
 				c.ParallelInsertWorkers = value
 			}
+		case "vreplication-concurrent-table-copies":
+			value, err := strconv.Atoi(v)
+			if err != nil {
+				errors = append(errors, getError(k, v))
+			} else {
+				c.ConcurrentTableCopies = value
+			}
 		case "vstream_packet_size":
 			value, err := strconv.Atoi(v)
 			if err != nil {
@@ -271,6 +280,7 @@ func (c VReplicationConfig) Map() map[string]string {
 		"vreplication_heartbeat_update_interval":  strconv.Itoa(c.HeartbeatUpdateInterval),
 		"vreplication_store_compressed_gtid":      strconv.FormatBool(c.StoreCompressedGTID),
 		"vreplication-parallel-insert-workers":    strconv.Itoa(c.ParallelInsertWorkers),
+		"vreplication-concurrent-table-copies":    strconv.Itoa(c.ConcurrentTableCopies),
 		"vstream_packet_size":                     strconv.Itoa(c.VStreamPacketSize),
 		"vstream_dynamic_packet_size":             strconv.FormatBool(c.VStreamDynamicPacketSize),
 		"vstream_binlog_rotation_threshold":       strconv.FormatInt(c.VStreamBinlogRotationThreshold, 10),
@@ -26,12 +26,18 @@ import (
 	"vitess.io/vitess/go/vt/topotools"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil/policy"
 
+	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/base"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
 
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
@@ -41,6 +47,30 @@ const (
 	backupModeOffline = "offline"
 )
 
+// backupThrottler implements mysqlctl.BackupThrottler on top of the tablet throttler.
+type backupThrottler struct {
+	client *throttle.Client
+}
+
+// ThrottleCheckOKOrWait is part of the mysqlctl.BackupThrottler interface.
+func (bt *backupThrottler) ThrottleCheckOKOrWait(ctx context.Context) bool {
+	_, ok := bt.client.ThrottleCheckOKOrWaitAppName(ctx, throttlerapp.BackupName)
+	return ok
+}
+
+// backupTableStreamer implements mysqlctl.TableStreamer on top of the vstreamer of the tablet server.
+type backupTableStreamer struct {
+	tm *TabletManager
+}
+
+// StreamTables is part of the mysqlctl.TableStreamer interface.
+func (bts *backupTableStreamer) StreamTables(ctx context.Context, request *binlogdatapb.VStreamTablesRequest, send func(*binlogdatapb.VStreamTablesResponse) error) error {
+	tablet := bts.tm.Tablet()
+	request = request.CloneVT()
+	request.Target = &querypb.Target{Keyspace: tablet.Keyspace, Shard: tablet.Shard, TabletType: tablet.Type}
+	return bts.tm.QueryServiceControl.QueryService().VStreamTables(ctx, request, send)
+}
+
 // Backup takes a db backup and sends it to the BackupStorage.
 func (tm *TabletManager) Backup(ctx context.Context, logger logutil.Logger, req *tabletmanagerdatapb.BackupRequest) error {
 	if tm.Cnf == nil {
@@ -172,9 +202,26 @@ func (tm *TabletManager) Backup(ctx context.Context, logger logutil.Logger, req
 		UpgradeSafe:          req.UpgradeSafe,
 		MysqlShutdownTimeout: shutdownTimeout(l, req.MysqlShutdownTimeout),
 		BackupEngine:         backupEngine,
+		DbName:               topoproto.TabletDbName(tablet.Tablet),
+		TableStreamer:        &backupTableStreamer{tm: tm},
+	}
+	if !engine.ShouldDrainForBackup(req) {
+		// The tablet keeps serving throughout the backup, so the backup should yield to it.
+		backupParams.Throttler = &backupThrottler{
+			client: throttle.NewBackgroundClient(tm.LagThrottler, throttlerapp.BackupName, base.UndefinedScope),
+		}
 	}
 
 	returnErr := mysqlctl.Backup(ctx, backupParams)
+	if returnErr == nil {
+		// Copying the backup to the secondary backup storage can take a while, and does not
+		// need to hold the backup lock.
+		go func() {
+			if err := mysqlctl.ReplicateBackups(tm.BatchCtx, logutil.NewConsoleLogger(), tablet.Keyspace, tablet.Shard); err != nil {
+				log.Errorf("Failed to copy backups to secondary backup storage: %v", err)
+			}
+		}()
+	}
 
 	return returnErr
 }
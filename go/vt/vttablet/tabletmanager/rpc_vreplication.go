@@ -577,6 +577,15 @@ func (tm *TabletManager) UpdateVReplicationWorkflow(ctx context.Context, req *ta
 		if !textutil.ValueIsSimulatedNull(req.TabletTypes) {
 			tabletTypes = req.TabletTypes
 		}
+		if req.State != nil && *req.State == binlogdatapb.VReplicationWorkflowState_Running {
+			switch strings.ToUpper(message) {
+			case workflow.Queued, workflow.CutoverRolledBack:
+				// Starting a queued workflow, whether from the workflow scheduler
+				// or by hand, takes it out of the queue. Starting a workflow whose
+				// cutover was rolled back runs its cutover plan again.
+				message = ""
+			}
+		}
 		if req.Message != nil {
 			message = *req.Message
 		}
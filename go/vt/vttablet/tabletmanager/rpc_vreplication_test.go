@@ -912,6 +912,13 @@ func TestUpdateVReplicationWorkflow(t *testing.T) {
 		),
 		fmt.Sprintf("%d|%s|%s|%s|Running|initial test message", vreplID, blsStr, cells[0], tabletTypes[0]),
 	)
+	selectResQueued := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"id|source|cell|tablet_types|state|message",
+			"int64|varchar|varchar|varchar|varchar|varbinary",
+		),
+		fmt.Sprintf("%d|%s|%s|%s|Stopped|QUEUED", vreplID, blsStr, cells[0], tabletTypes[0]),
+	)
 
 	idQuery, err := sqlparser.ParseAndBind("select id from _vt.vreplication where id = %a",
 		sqltypes.Int64BindVariable(int64(vreplID)))
@@ -938,6 +945,7 @@ func TestUpdateVReplicationWorkflow(t *testing.T) {
 		query                    string
 		isCopying                bool
 		initiallyNonEmptyMessage bool
+		initiallyQueued          bool
 	}{
 		{
 			name: "update cells",
@@ -1052,6 +1060,18 @@ func TestUpdateVReplicationWorkflow(t *testing.T) {
 			query: fmt.Sprintf(`update _vt.vreplication set state = 'Copying', source = 'keyspace:"%s" shard:"%s" filter:{rules:{match:"corder" filter:"select * from corder"} rules:{match:"customer" filter:"select * from customer"}}', cell = '%s', tablet_types = '%s', message = '' where id in (%d)`,
 				keyspace, shard, cells[0], tabletTypes[0], vreplID),
 		},
+		{
+			name: "start a queued workflow",
+			request: &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
+				Workflow:    workflow,
+				State:       &running,
+				Cells:       textutil.SimulatedNullStringSlice,
+				TabletTypes: textutil.SimulatedNullTabletTypeSlice,
+			},
+			initiallyQueued: true,
+			query: fmt.Sprintf(`update _vt.vreplication set state = 'Running', source = 'keyspace:"%s" shard:"%s" filter:{rules:{match:"corder" filter:"select * from corder"} rules:{match:"customer" filter:"select * from customer"}}', cell = '%s', tablet_types = '%s', message = '' where id in (%d)`,
+				keyspace, shard, cells[0], tabletTypes[0], vreplID),
+		},
 		{
 			name: "update cells and options",
 			request: &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
@@ -1084,9 +1104,12 @@ func TestUpdateVReplicationWorkflow(t *testing.T) {
 
 			// These are the same for each RPC call.
 			tenv.tmc.tablets[tabletUID].vrdbClient.ExpectRequest(fmt.Sprintf("use %s", sidecar.GetIdentifier()), &sqltypes.Result{}, nil)
-			if tt.initiallyNonEmptyMessage {
+			switch {
+			case tt.initiallyNonEmptyMessage:
 				tenv.tmc.tablets[tabletUID].vrdbClient.ExpectRequest(selectQuery, selectResNonEmptyMessage, nil)
-			} else {
+			case tt.initiallyQueued:
+				tenv.tmc.tablets[tabletUID].vrdbClient.ExpectRequest(selectQuery, selectResQueued, nil)
+			default:
 				tenv.tmc.tablets[tabletUID].vrdbClient.ExpectRequest(selectQuery, selectRes, nil)
 			}
 			if tt.request.State == nil || *tt.request.State == binlogdatapb.VReplicationWorkflowState_Running {
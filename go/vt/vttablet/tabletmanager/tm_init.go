@@ -78,6 +78,7 @@ import (
 	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vreplication"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 )
 
@@ -165,6 +166,8 @@ type TabletManager struct {
 	VREngine            *vreplication.Engine
 	SemiSyncMonitor     *semisyncmonitor.Monitor
 	VDiffEngine         *vdiff.Engine
+	BinlogArchiver      *mysqlctl.BinlogArchiver
+	LagThrottler        *throttle.Throttler
 	Env                 *vtenv.Environment
 
 	// tmc is used to run an RPC against other vttablets.
@@ -420,6 +423,10 @@ func (tm *TabletManager) Start(tablet *topodatapb.Tablet, config *tabletenv.Tabl
 		servenv.OnTerm(tm.VDiffEngine.Close)
 	}
 
+	if tm.BinlogArchiver != nil {
+		servenv.OnTerm(tm.BinlogArchiver.Close)
+	}
+
 	// The following initializations don't need to be done
 	// in any specific order.
 	tm.startShardSync()
@@ -509,6 +516,10 @@ func (tm *TabletManager) Stop() {
 		tm.VDiffEngine.Close()
 	}
 
+	if tm.BinlogArchiver != nil {
+		tm.BinlogArchiver.Close()
+	}
+
 	tm.MysqlDaemon.Close()
 	tm.tmState.Close()
 }
@@ -355,6 +355,17 @@ func (ts *tmState) updateLocked(ctx context.Context) error {
 		}
 	}
 
+	if ts.tm.BinlogArchiver != nil {
+		// Only the primary archives, so that the tablets of the shard don't race to upload
+		// the same binary logs. Stop doesn't wait for a running upload, which must not
+		// block the state changes of the tablet.
+		if ts.tablet.Type == topodatapb.TabletType_PRIMARY {
+			ts.tm.BinlogArchiver.Open(ts.tm.BatchCtx)
+		} else {
+			ts.tm.BinlogArchiver.Stop()
+		}
+	}
+
 	if ts.isShardServing[ts.tablet.Type] {
 		ts.isInSrvKeyspace = true
 		statsIsInSrvKeyspace.Set(1)
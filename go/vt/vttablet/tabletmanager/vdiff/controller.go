@@ -64,14 +64,13 @@ type controller struct {
 	vde             *Engine // The singleton vdiff engine
 	done            chan struct{}
 
-	sources        map[string]*migrationSource // Currently picked source tablets for this shard's data
+	sources        map[string]*migrationSource // The source streams for this shard's data, copied by each tableDiffer
 	workflowFilter string
 	sourceKeyspace string
 	tmc            tmclient.TabletManagerClient
 
-	targetShardStreamer *shardStreamer
-	filter              *binlogdatapb.Filter            // VReplication row filter
-	options             *tabletmanagerdata.VDiffOptions // Options initially from vtctld command and later from _vt.vdiff
+	filter  *binlogdatapb.Filter            // VReplication row filter
+	options *tabletmanagerdata.VDiffOptions // Options initially from vtctld command and later from _vt.vdiff
 
 	sourceTimeZone, targetTimeZone string // Named time zones if conversions are necessary for datetime values
 
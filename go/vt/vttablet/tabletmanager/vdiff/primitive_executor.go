@@ -48,6 +48,11 @@ type primitiveExecutor struct {
 	resultch chan *sqltypes.Result
 	err      error
 
+	// pastEnd, when set, tells if a row is past the end of the range being
+	// diffed. The stream ends before the first such row.
+	pastEnd func(row []sqltypes.Value) (bool, error)
+	ended   bool
+
 	name string // for debug purposes only
 }
 
@@ -78,6 +83,9 @@ func newPrimitiveExecutor(ctx context.Context, prim vtgateEngine.Primitive, name
 // next gets the next row in the stream for this shard, if there's currently no rows to process in the stream then wait on the
 // result channel for the shard streamer to produce them.
 func (pe *primitiveExecutor) next() ([]sqltypes.Value, error) {
+	if pe.ended {
+		return nil, nil
+	}
 	for len(pe.rows) == 0 {
 		qr, ok := <-pe.resultch
 		if !ok {
@@ -88,6 +96,17 @@ func (pe *primitiveExecutor) next() ([]sqltypes.Value, error) {
 
 	row := pe.rows[0]
 	pe.rows = pe.rows[1:]
+	if pe.pastEnd != nil {
+		pastEnd, err := pe.pastEnd(row)
+		if err != nil {
+			return nil, err
+		}
+		if pastEnd {
+			pe.ended = true
+			pe.rows = nil
+			return nil, nil
+		}
+	}
 	return row, nil
 }
 
@@ -66,4 +66,8 @@ const (
 	sqlUpdateTableMismatch       = "update _vt.vdiff_table set mismatch = true where vdiff_id = %a and table_name = %a"
 
 	sqlGetIncompleteTables = "select table_name as table_name from _vt.vdiff_table where vdiff_id = %a and state != 'completed' order by table_name"
+
+	// These are used to sample the primary key range boundaries of large tables.
+	sqlGetPKColumnRange = "select min(%s) as min_pk, max(%s) as max_pk from %s.%s"
+	sqlGetPKBoundary    = "select %s from %s.%s where %s >= %s order by %s limit 1"
 )
@@ -99,10 +99,39 @@ type tableDiffer struct {
 	wgShardStreamers   sync.WaitGroup
 	shardStreamsCtx    context.Context
 	shardStreamsCancel context.CancelFunc
+
+	// sources and targetShardStreamer are owned by this table so that multiple
+	// tables can be diffed concurrently, each using its own database snapshots.
+	sources             map[string]*migrationSource
+	targetShardStreamer *shardStreamer
+
+	// savedRanges are the primary key ranges that an earlier run of the table
+	// diff used, if any.
+	savedRanges []*tabletmanagerdatapb.VDiffTableRange
+	// ranges and tableRange are set when this tableDiffer diffs one of the
+	// primary key ranges of the table.
+	ranges     *tableRanges
+	tableRange *tableRange
 }
 
 func newTableDiffer(wd *workflowDiffer, table *tabletmanagerdatapb.TableDefinition, sourceQuery string) *tableDiffer {
-	return &tableDiffer{wd: wd, table: table, sourceQuery: sourceQuery}
+	return &tableDiffer{wd: wd, table: table, sourceQuery: sourceQuery, sources: copyMigrationSources(wd.ct.sources)}
+}
+
+// copyMigrationSources returns a copy of the sources with new shard streamers.
+func copyMigrationSources(sources map[string]*migrationSource) map[string]*migrationSource {
+	copies := make(map[string]*migrationSource, len(sources))
+	for shard, source := range sources {
+		copies[shard] = &migrationSource{
+			shardStreamer: &shardStreamer{
+				tablet: source.tablet,
+				shard:  source.shard,
+			},
+			vrID:     source.vrID,
+			position: source.position,
+		}
+	}
+	return copies
 }
 
 // initialize
@@ -205,17 +234,21 @@ func (td *tableDiffer) stopTargetVReplicationStreams(ctx context.Context, dbClie
 		if err := prototext.Unmarshal(sourceBytes, &bls); err != nil {
 			return err
 		}
-		ct.sources[bls.Shard].position = mpos
+		source, ok := td.sources[bls.Shard]
+		if !ok {
+			return fmt.Errorf("stream %d has an unknown source shard %s on tablet %v",
+				id, bls.Shard, td.wd.ct.vde.thisTablet.Alias)
+		}
+		source.position = mpos
 	}
 
 	return nil
 }
 
 func (td *tableDiffer) forEachSource(cb func(source *migrationSource) error) error {
-	ct := td.wd.ct
 	var wg sync.WaitGroup
 	allErrors := &concurrency.AllErrorRecorder{}
-	for _, source := range ct.sources {
+	for _, source := range td.sources {
 		wg.Add(1)
 		go func(source *migrationSource) {
 			defer wg.Done()
@@ -276,7 +309,7 @@ func (td *tableDiffer) selectTablets(ctx context.Context) error {
 		if targetErr != nil {
 			return
 		}
-		td.wd.ct.targetShardStreamer = &shardStreamer{
+		td.targetShardStreamer = &shardStreamer{
 			tablet: targetTablet,
 			shard:  targetTablet.Shard,
 		}
@@ -344,16 +377,15 @@ func (td *tableDiffer) syncTargetStreams(ctx context.Context) error {
 
 func (td *tableDiffer) startTargetDataStream(ctx context.Context) error {
 	defer td.wd.ct.TableDiffPhaseTimings.Record(fmt.Sprintf("%s.%s", td.table.Name, startingTargets), time.Now())
-	ct := td.wd.ct
 	gtidch := make(chan string, 1)
-	ct.targetShardStreamer.result = make(chan *sqltypes.Result, 1)
-	go td.streamOneShard(ctx, ct.targetShardStreamer, td.tablePlan.targetQuery, td.lastTargetPK, gtidch)
+	td.targetShardStreamer.result = make(chan *sqltypes.Result, 1)
+	go td.streamOneShard(ctx, td.targetShardStreamer, td.tablePlan.targetQuery, td.lastTargetPK, gtidch)
 	gtid, ok := <-gtidch
 	if !ok {
-		log.Infof("streaming error: %v", ct.targetShardStreamer.err)
-		return ct.targetShardStreamer.err
+		log.Infof("streaming error: %v", td.targetShardStreamer.err)
+		return td.targetShardStreamer.err
 	}
-	ct.targetShardStreamer.snapshotPosition = gtid
+	td.targetShardStreamer.snapshotPosition = gtid
 	return nil
 }
 
@@ -469,14 +501,14 @@ func (td *tableDiffer) streamOneShard(ctx context.Context, participant *shardStr
 func (td *tableDiffer) setupRowSorters() {
 	// Combine all sources into a slice and create a merge sorter for it.
 	sources := make(map[string]*shardStreamer)
-	for shard, source := range td.wd.ct.sources {
+	for shard, source := range td.sources {
 		sources[shard] = source.shardStreamer
 	}
 	td.sourcePrimitive = newMergeSorter(sources, td.tablePlan.comparePKs, td.wd.collationEnv)
 
 	// Create a merge sorter for the target.
 	targets := make(map[string]*shardStreamer)
-	targets[td.wd.ct.targetShardStreamer.shard] = td.wd.ct.targetShardStreamer
+	targets[td.targetShardStreamer.shard] = td.targetShardStreamer
 	td.targetPrimitive = newMergeSorter(targets, td.tablePlan.comparePKs, td.wd.collationEnv)
 
 	// If there were aggregate expressions, we have to re-aggregate
@@ -501,36 +533,22 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 	// We need to continue were we left off when appropriate. This can be an
 	// auto-retry on error, or a manual retry via the resume command.
 	// Otherwise the existing state will be empty and we start from scratch.
-	query, err := sqlparser.ParseAndBind(sqlGetVDiffTable,
-		sqltypes.Int64BindVariable(td.wd.ct.id),
-		sqltypes.StringBindVariable(td.table.Name),
-	)
+	dr, mismatch, err := td.getTableState(dbClient)
 	if err != nil {
 		return nil, err
 	}
-	cs, err := dbClient.ExecuteFetch(query, -1)
-	if err != nil {
-		return nil, err
+	if td.tableRange != nil {
+		// The table's report is the sum of those of its ranges, so a range
+		// continues from its own report.
+		dr = td.ranges.rangeReport(td.tableRange)
 	}
-	if len(cs.Rows) == 0 {
-		return nil, fmt.Errorf("no state found for vdiff table %s for vdiff_id %d on tablet %v",
-			td.table.Name, td.wd.ct.id, td.wd.ct.vde.thisTablet.Alias)
-	} else if len(cs.Rows) > 1 {
-		return nil, fmt.Errorf("invalid state found for vdiff table %s (multiple records) for vdiff_id %d on tablet %v",
-			td.table.Name, td.wd.ct.id, td.wd.ct.vde.thisTablet.Alias)
-	}
-	curState := cs.Named().Row()
-	mismatch := curState.AsBool("mismatch", false)
-	dr := &DiffReport{}
-	if rpt := curState.AsBytes("report", []byte("{}")); json.Valid(rpt) {
-		if err = json.Unmarshal(rpt, dr); err != nil {
-			return nil, err
-		}
-	}
-	dr.TableName = td.table.Name
 
 	sourceExecutor := newPrimitiveExecutor(ctx, td.sourcePrimitive, "source")
 	targetExecutor := newPrimitiveExecutor(ctx, td.targetPrimitive, "target")
+	if td.tableRange != nil && td.tableRange.end != nil {
+		sourceExecutor.pastEnd = td.pastRangeEnd
+		targetExecutor.pastEnd = td.pastRangeEnd
+	}
 	var sourceRow, lastProcessedRow, targetRow []sqltypes.Value
 	advanceSource := true
 	advanceTarget := true
@@ -694,6 +712,38 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 	}
 }
 
+// getTableState returns the report and mismatch flag that were saved for the table.
+func (td *tableDiffer) getTableState(dbClient binlogplayer.DBClient) (*DiffReport, bool, error) {
+	query, err := sqlparser.ParseAndBind(sqlGetVDiffTable,
+		sqltypes.Int64BindVariable(td.wd.ct.id),
+		sqltypes.StringBindVariable(td.table.Name),
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	cs, err := dbClient.ExecuteFetch(query, -1)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(cs.Rows) == 0 {
+		return nil, false, fmt.Errorf("no state found for vdiff table %s for vdiff_id %d on tablet %v",
+			td.table.Name, td.wd.ct.id, td.wd.ct.vde.thisTablet.Alias)
+	} else if len(cs.Rows) > 1 {
+		return nil, false, fmt.Errorf("invalid state found for vdiff table %s (multiple records) for vdiff_id %d on tablet %v",
+			td.table.Name, td.wd.ct.id, td.wd.ct.vde.thisTablet.Alias)
+	}
+	curState := cs.Named().Row()
+	mismatch := curState.AsBool("mismatch", false)
+	dr := &DiffReport{}
+	if rpt := curState.AsBytes("report", []byte("{}")); json.Valid(rpt) {
+		if err = json.Unmarshal(rpt, dr); err != nil {
+			return nil, false, err
+		}
+	}
+	dr.TableName = td.table.Name
+	return dr, mismatch, nil
+}
+
 func (td *tableDiffer) compare(sourceRow, targetRow []sqltypes.Value, cols []compareColInfo, compareOnlyNonPKs bool) (int, error) {
 	for _, col := range cols {
 		if col.isPK && compareOnlyNonPKs {
@@ -726,6 +776,20 @@ func (td *tableDiffer) updateTableProgress(dbClient binlogplayer.DBClient, dr *D
 		return fmt.Errorf("cannot update progress with a nil diff report")
 	}
 
+	if td.tableRange != nil {
+		if err := td.ranges.updateProgress(dbClient, td.tableRange, dr, lastRow); err != nil {
+			return err
+		}
+		if lastRow != nil {
+			// Update the in-memory lastPK as well so that we can restart the
+			// diff of the range if needed.
+			td.lastTargetPK = td.pkQueryResult(td.pkValues(lastRow))
+			td.lastSourcePK = td.lastTargetPK
+		}
+		td.wd.ct.TableDiffRowCounts.Add(td.table.Name, dr.ProcessedRows)
+		return nil
+	}
+
 	var err error
 	var query string
 	rpt, err := json.Marshal(dr)
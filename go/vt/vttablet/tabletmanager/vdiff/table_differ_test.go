@@ -129,3 +129,30 @@ func TestUpdateTableProgress(t *testing.T) {
 		})
 	}
 }
+
+func TestNewTableDifferSources(t *testing.T) {
+	wd := &workflowDiffer{
+		ct: &controller{
+			sources: map[string]*migrationSource{
+				"-80": {vrID: 1, shardStreamer: &shardStreamer{shard: "-80"}},
+				"80-": {vrID: 2, shardStreamer: &shardStreamer{shard: "80-"}},
+			},
+		},
+	}
+	table := &tabletmanagerdatapb.TableDefinition{Name: "test"}
+	td1 := newTableDiffer(wd, table, "select * from test")
+	td2 := newTableDiffer(wd, table, "select * from test")
+
+	// Each table differ gets its own copy of the sources so that their
+	// streams and snapshot positions do not interfere with one another.
+	require.Len(t, td1.sources, 2)
+	for shard, source := range wd.ct.sources {
+		require.Equal(t, source.vrID, td1.sources[shard].vrID)
+		require.Equal(t, shard, td1.sources[shard].shard)
+		require.NotSame(t, source, td1.sources[shard])
+		require.NotSame(t, td1.sources[shard].shardStreamer, td2.sources[shard].shardStreamer)
+	}
+	td1.sources["-80"].snapshotPosition = "pos1"
+	require.Empty(t, td2.sources["-80"].snapshotPosition)
+	require.Empty(t, wd.ct.sources["-80"].snapshotPosition)
+}
@@ -0,0 +1,421 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+const (
+	// minRowsPerTableRange is the minimum number of rows, based on the table
+	// statistics, in each of the primary key ranges that a table is split into.
+	minRowsPerTableRange = 1_000_000
+	// maxTableRanges limits the number of ranges so that the progress of all of
+	// them fits in the lastpk column of the _vt.vdiff_table record.
+	maxTableRanges = 64
+)
+
+// tableRange is one of the primary key ranges of a table. Each range is diffed
+// concurrently with the other ranges of the table, using its own snapshots. It
+// holds the rows after start, up to and including end. A nil start or end means
+// that the range is not bounded on that side. All of the values are those of the
+// table's primary key columns.
+type tableRange struct {
+	start, end []sqltypes.Value
+	// lastPK is the primary key of the last row that was compared in this range.
+	lastPK []sqltypes.Value
+	// report has the differences found in this range since the table diff started.
+	report *DiffReport
+}
+
+// tableRanges tracks the progress of the ranges of one table and saves it in the
+// _vt.vdiff_table record of the table.
+type tableRanges struct {
+	td *tableDiffer
+
+	mu     sync.Mutex
+	ranges []*tableRange
+	// base is the report of the table as it was when the table diff started.
+	base *DiffReport
+}
+
+// canDiffRanges returns true if the table can be split into primary key ranges.
+// The source and target have to use the same primary key, as each range keeps a
+// single lastpk, and the first primary key column has to be an integral type so
+// that the boundaries can be sampled from its range of values.
+func (td *tableDiffer) canDiffRanges() bool {
+	tp := td.tablePlan
+	if len(tp.aggregates) != 0 || len(tp.pkCols) == 0 || !slices.Equal(tp.pkCols, tp.sourcePkCols) {
+		return false
+	}
+	return sqltypes.IsIntegral(tp.table.Fields[tp.pkCols[0]].Type)
+}
+
+// getTableRanges returns the ranges to diff the table in, or nil if the table
+// should be diffed as a single range. Ranges that were saved by an earlier run
+// are always resumed. Otherwise a large table is split into up to the
+// MaxTableRanges option number of ranges.
+func (wd *workflowDiffer) getTableRanges(dbClient binlogplayer.DBClient, td *tableDiffer) (*tableRanges, error) {
+	var tr *tableRanges
+	if len(td.savedRanges) > 0 {
+		var err error
+		if tr, err = td.loadTableRanges(td.savedRanges); err != nil {
+			return nil, err
+		}
+	} else {
+		maxRanges := min(wd.opts.CoreOptions.GetMaxTableRanges(), maxTableRanges)
+		if maxRanges < 2 || td.lastTargetPK != nil || !td.canDiffRanges() {
+			return nil, nil
+		}
+		query, err := sqlparser.ParseAndBind(sqlGetTableRows,
+			sqltypes.StringBindVariable(wd.ct.vde.dbName),
+			sqltypes.StringBindVariable(td.table.Name),
+		)
+		if err != nil {
+			return nil, err
+		}
+		qr, err := dbClient.ExecuteFetch(query, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(qr.Rows) != 1 {
+			return nil, nil
+		}
+		tableRows, _ := qr.Named().Row().ToInt64("table_rows")
+		numRanges := min(maxRanges, tableRows/minRowsPerTableRange)
+		if numRanges < 2 {
+			return nil, nil
+		}
+		boundaries, err := td.sampleRangeBoundaries(dbClient, numRanges)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "failed to sample the primary key ranges of the %s table", td.table.Name)
+		}
+		if len(boundaries) == 0 {
+			return nil, nil
+		}
+		tr = &tableRanges{td: td}
+		var start []sqltypes.Value
+		for _, boundary := range boundaries {
+			tr.ranges = append(tr.ranges, &tableRange{start: start, end: boundary})
+			start = boundary
+		}
+		tr.ranges = append(tr.ranges, &tableRange{start: start})
+	}
+
+	var err error
+	if tr.base, _, err = td.getTableState(dbClient); err != nil {
+		return nil, err
+	}
+	// Save the ranges right away so that the table is diffed in the same
+	// ranges if it is resumed or retried.
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if err := tr.save(dbClient); err != nil {
+		return nil, err
+	}
+	log.Infof("Diffing table %s for vdiff %s in %d primary key ranges", td.table.Name, wd.ct.uuid, len(tr.ranges))
+	return tr, nil
+}
+
+// sampleRangeBoundaries returns up to numRanges-1 boundaries that split the
+// table into ranges of roughly the same size. It spreads the boundaries evenly
+// between the lowest and highest values of the first primary key column and
+// then looks up the first row at or after each of them.
+func (td *tableDiffer) sampleRangeBoundaries(dbClient binlogplayer.DBClient, numRanges int64) ([][]sqltypes.Value, error) {
+	dbName := sqlescape.EscapeID(td.wd.ct.vde.dbName)
+	tableName := sqlescape.EscapeID(td.table.Name)
+	pkNames := make([]string, len(td.tablePlan.table.PrimaryKeyColumns))
+	for i, pk := range td.tablePlan.table.PrimaryKeyColumns {
+		pkNames[i] = sqlescape.EscapeID(pk)
+	}
+	firstPK := pkNames[0]
+	pkList := strings.Join(pkNames, ", ")
+
+	query := sqlparser.BuildParsedQuery(sqlGetPKColumnRange, firstPK, firstPK, dbName, tableName)
+	qr, err := dbClient.ExecuteFetch(query.Query, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(qr.Rows) != 1 || qr.Rows[0][0].IsNull() || qr.Rows[0][1].IsNull() {
+		return nil, nil
+	}
+	// Boundaries only need to be roughly evenly spread, so floating point
+	// precision is enough while also covering unsigned values.
+	minValue, err := qr.Rows[0][0].ToFloat64()
+	if err != nil {
+		return nil, err
+	}
+	maxValue, err := qr.Rows[0][1].ToFloat64()
+	if err != nil {
+		return nil, err
+	}
+
+	var boundaries [][]sqltypes.Value
+	for i := int64(1); i < numRanges; i++ {
+		value := minValue + (maxValue-minValue)*float64(i)/float64(numRanges)
+		query := sqlparser.BuildParsedQuery(sqlGetPKBoundary, pkList, dbName, tableName, firstPK,
+			strconv.FormatFloat(value, 'f', 0, 64), pkList)
+		qr, err := dbClient.ExecuteFetch(query.Query, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(qr.Rows) != 1 {
+			break
+		}
+		boundary := qr.Rows[0]
+		if len(boundaries) > 0 {
+			c, err := td.comparePKValues(boundaries[len(boundaries)-1], boundary)
+			if err != nil {
+				return nil, err
+			}
+			if c >= 0 { // The ranges would be empty
+				continue
+			}
+		}
+		boundaries = append(boundaries, boundary)
+	}
+	return boundaries, nil
+}
+
+// loadTableRanges restores the ranges that were saved in the lastpk column.
+func (td *tableDiffer) loadTableRanges(saved []*tabletmanagerdatapb.VDiffTableRange) (*tableRanges, error) {
+	fields := td.pkFields()
+	fromProto := func(row *querypb.Row) ([]sqltypes.Value, error) {
+		if row == nil {
+			return nil, nil
+		}
+		if len(row.Lengths) != len(fields) {
+			return nil, fmt.Errorf("invalid primary key range value %v for the %s table, which has %d primary key columns",
+				row, td.table.Name, len(fields))
+		}
+		return sqltypes.MakeRowTrusted(fields, row), nil
+	}
+	tr := &tableRanges{td: td}
+	for _, sr := range saved {
+		var (
+			r   = &tableRange{}
+			err error
+		)
+		if r.start, err = fromProto(sr.Start); err != nil {
+			return nil, err
+		}
+		if r.end, err = fromProto(sr.End); err != nil {
+			return nil, err
+		}
+		if r.lastPK, err = fromProto(sr.Lastpk); err != nil {
+			return nil, err
+		}
+		tr.ranges = append(tr.ranges, r)
+	}
+	return tr, nil
+}
+
+// newRangeDiffer returns a tableDiffer for one of the ranges of the table. It
+// shares the table plan, but uses its own streams and snapshots.
+func (td *tableDiffer) newRangeDiffer(tr *tableRanges, r *tableRange) *tableDiffer {
+	rtd := &tableDiffer{
+		wd:          td.wd,
+		tablePlan:   td.tablePlan,
+		sourceQuery: td.sourceQuery,
+		table:       td.table,
+		sources:     copyMigrationSources(td.sources),
+		ranges:      tr,
+		tableRange:  r,
+	}
+	lastPK := r.lastPK
+	if lastPK == nil {
+		lastPK = r.start
+	}
+	if lastPK != nil {
+		rtd.lastTargetPK = td.pkQueryResult(lastPK)
+		rtd.lastSourcePK = rtd.lastTargetPK
+	}
+	return rtd
+}
+
+// pastRangeEnd returns true if the row comes after the end of the range.
+func (td *tableDiffer) pastRangeEnd(row []sqltypes.Value) (bool, error) {
+	c, err := td.comparePKValues(td.pkValues(row), td.tableRange.end)
+	if err != nil {
+		return false, err
+	}
+	return c > 0, nil
+}
+
+// pkFields returns the fields of the primary key columns.
+func (td *tableDiffer) pkFields() []*querypb.Field {
+	fields := make([]*querypb.Field, len(td.tablePlan.pkCols))
+	for i, colIndex := range td.tablePlan.pkCols {
+		fields[i] = td.tablePlan.table.Fields[colIndex]
+	}
+	return fields
+}
+
+// pkValues returns the primary key values of the row.
+func (td *tableDiffer) pkValues(row []sqltypes.Value) []sqltypes.Value {
+	values := make([]sqltypes.Value, len(td.tablePlan.pkCols))
+	for i, colIndex := range td.tablePlan.pkCols {
+		values[i] = row[colIndex]
+	}
+	return values
+}
+
+// pkQueryResult returns the primary key values as a lastpk for VStreamRows.
+func (td *tableDiffer) pkQueryResult(values []sqltypes.Value) *querypb.QueryResult {
+	return &querypb.QueryResult{
+		Fields: td.pkFields(),
+		Rows:   []*querypb.Row{sqltypes.RowToProto3(values)},
+	}
+}
+
+// comparePKValues compares two sets of primary key values in the order that the
+// table is diffed in.
+func (td *tableDiffer) comparePKValues(a, b []sqltypes.Value) (int, error) {
+	for i, col := range td.tablePlan.comparePKs {
+		collationID := col.collation
+		if collationID == collations.Unknown {
+			collationID = collations.CollationBinaryID
+		}
+		c, err := evalengine.NullsafeCompare(a[i], b[i], td.wd.collationEnv, collationID, nil)
+		if err != nil {
+			return 0, err
+		}
+		if c != 0 {
+			return c, nil
+		}
+	}
+	return 0, nil
+}
+
+// rangeReport returns the report of the range so far, which is where a
+// restarted diff of the range continues from.
+func (tr *tableRanges) rangeReport(r *tableRange) *DiffReport {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if r.report == nil {
+		return &DiffReport{TableName: tr.td.table.Name}
+	}
+	dr := *r.report
+	return &dr
+}
+
+// updateProgress records the progress of the range and saves the progress of
+// the table.
+func (tr *tableRanges) updateProgress(dbClient binlogplayer.DBClient, r *tableRange, dr *DiffReport, lastRow []sqltypes.Value) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	// The range keeps adding to its report, so we save a copy of it.
+	rpt := *dr
+	r.report = &rpt
+	if lastRow != nil {
+		r.lastPK = tr.td.pkValues(lastRow)
+	}
+	return tr.save(dbClient)
+}
+
+// report returns the report of the table: the one it started with plus those
+// of all of its ranges. The caller must hold the mutex.
+func (tr *tableRanges) report() *DiffReport {
+	dr := *tr.base
+	dr.TableName = tr.td.table.Name
+	// Make sure that appending does not modify the base report.
+	dr.ExtraRowsSourceDiffs = slices.Clip(dr.ExtraRowsSourceDiffs)
+	dr.ExtraRowsTargetDiffs = slices.Clip(dr.ExtraRowsTargetDiffs)
+	dr.MismatchedRowsDiffs = slices.Clip(dr.MismatchedRowsDiffs)
+	for _, r := range tr.ranges {
+		if r.report == nil {
+			continue
+		}
+		dr.ProcessedRows += r.report.ProcessedRows
+		dr.MatchingRows += r.report.MatchingRows
+		dr.MismatchedRows += r.report.MismatchedRows
+		dr.ExtraRowsSource += r.report.ExtraRowsSource
+		dr.ExtraRowsTarget += r.report.ExtraRowsTarget
+		dr.ExtraRowsSourceDiffs = append(dr.ExtraRowsSourceDiffs, r.report.ExtraRowsSourceDiffs...)
+		dr.ExtraRowsTargetDiffs = append(dr.ExtraRowsTargetDiffs, r.report.ExtraRowsTargetDiffs...)
+		dr.MismatchedRowsDiffs = append(dr.MismatchedRowsDiffs, r.report.MismatchedRowsDiffs...)
+	}
+	if maxSampleRows := tr.td.wd.opts.ReportOptions.GetMaxSampleRows(); maxSampleRows > 0 && int64(len(dr.MismatchedRowsDiffs)) > maxSampleRows {
+		dr.MismatchedRowsDiffs = dr.MismatchedRowsDiffs[:maxSampleRows]
+	}
+	return &dr
+}
+
+// lastPK returns the progress of all of the ranges as it is saved in the lastpk
+// column. The caller must hold the mutex.
+func (tr *tableRanges) lastPK() *tabletmanagerdatapb.VDiffTableLastPK {
+	toProto := func(values []sqltypes.Value) *querypb.Row {
+		if values == nil {
+			return nil
+		}
+		return sqltypes.RowToProto3(values)
+	}
+	lastPK := &tabletmanagerdatapb.VDiffTableLastPK{}
+	for _, r := range tr.ranges {
+		lastPK.Ranges = append(lastPK.Ranges, &tabletmanagerdatapb.VDiffTableRange{
+			Start:  toProto(r.start),
+			End:    toProto(r.end),
+			Lastpk: toProto(r.lastPK),
+		})
+	}
+	return lastPK
+}
+
+// save saves the progress of the table. The caller must hold the mutex.
+func (tr *tableRanges) save(dbClient binlogplayer.DBClient) error {
+	dr := tr.report()
+	rpt, err := json.Marshal(dr)
+	if err != nil {
+		return err
+	}
+	lastPK := tr.lastPK()
+	lastPKTxt, err := prototext.Marshal(lastPK)
+	if err != nil {
+		return vterrors.Wrapf(err, "failed to marshal lastpk value %+v for table %s", lastPK, tr.td.table.Name)
+	}
+	query, err := sqlparser.ParseAndBind(sqlUpdateTableProgress,
+		sqltypes.Int64BindVariable(dr.ProcessedRows),
+		sqltypes.StringBindVariable(string(lastPKTxt)),
+		sqltypes.StringBindVariable(string(rpt)),
+		sqltypes.Int64BindVariable(tr.td.wd.ct.id),
+		sqltypes.StringBindVariable(tr.td.table.Name),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = dbClient.ExecuteFetch(query, 1)
+	return err
+}
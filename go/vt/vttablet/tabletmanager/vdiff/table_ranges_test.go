@@ -0,0 +1,270 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vdiff
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+func newTestRangesTableDiffer() *tableDiffer {
+	wd := &workflowDiffer{
+		ct: &controller{
+			id:                 1,
+			vde:                &Engine{dbName: "vt_ks"},
+			TableDiffRowCounts: stats.NewCountersWithSingleLabel("", "", "Rows"),
+		},
+		opts: &tabletmanagerdatapb.VDiffOptions{
+			CoreOptions:   &tabletmanagerdatapb.VDiffCoreOptions{},
+			ReportOptions: &tabletmanagerdatapb.VDiffReportOptions{MaxSampleRows: 2},
+		},
+		collationEnv: collations.MySQL8(),
+	}
+	table := &tabletmanagerdatapb.TableDefinition{
+		Name:              "t1",
+		Fields:            sqltypes.MakeTestFields("id|name", "int64|varchar"),
+		PrimaryKeyColumns: []string{"id"},
+	}
+	return &tableDiffer{
+		wd:    wd,
+		table: table,
+		tablePlan: &tablePlan{
+			table:        table,
+			pkCols:       []int{0},
+			sourcePkCols: []int{0},
+			comparePKs:   []compareColInfo{{colIndex: 0, isPK: true, colName: "id"}},
+		},
+	}
+}
+
+func TestCanDiffRanges(t *testing.T) {
+	td := newTestRangesTableDiffer()
+	require.True(t, td.canDiffRanges())
+
+	td.tablePlan.sourcePkCols = []int{1}
+	require.False(t, td.canDiffRanges())
+
+	td = newTestRangesTableDiffer()
+	td.tablePlan.pkCols = []int{1}
+	td.tablePlan.sourcePkCols = []int{1}
+	require.False(t, td.canDiffRanges())
+}
+
+func TestSampleRangeBoundaries(t *testing.T) {
+	td := newTestRangesTableDiffer()
+	dbc := binlogplayer.NewMockDBClient(t)
+	dbc.ExpectRequest("select min(`id`) as min_pk, max(`id`) as max_pk from `vt_ks`.`t1`",
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("min_pk|max_pk", "int64|int64"), "0|400"), nil)
+	idResult := func(id string) *sqltypes.Result {
+		return sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), id)
+	}
+	dbc.ExpectRequest("select `id` from `vt_ks`.`t1` where `id` >= 100 order by `id` limit 1", idResult("100"), nil)
+	dbc.ExpectRequest("select `id` from `vt_ks`.`t1` where `id` >= 200 order by `id` limit 1", idResult("350"), nil)
+	// Would be an empty range.
+	dbc.ExpectRequest("select `id` from `vt_ks`.`t1` where `id` >= 300 order by `id` limit 1", idResult("350"), nil)
+
+	boundaries, err := td.sampleRangeBoundaries(dbc, 4)
+	require.NoError(t, err)
+	require.Equal(t, [][]sqltypes.Value{{sqltypes.NewInt64(100)}, {sqltypes.NewInt64(350)}}, boundaries)
+	dbc.Wait()
+}
+
+func TestTableRangesProgress(t *testing.T) {
+	td := newTestRangesTableDiffer()
+	tr := &tableRanges{
+		td:   td,
+		base: &DiffReport{TableName: "t1", ProcessedRows: 10, MatchingRows: 10},
+		ranges: []*tableRange{
+			{end: []sqltypes.Value{sqltypes.NewInt64(100)}},
+			{start: []sqltypes.Value{sqltypes.NewInt64(100)}},
+		},
+	}
+	rtd := td.newRangeDiffer(tr, tr.ranges[1])
+	require.Equal(t, td.pkQueryResult([]sqltypes.Value{sqltypes.NewInt64(100)}), rtd.lastTargetPK)
+	require.Equal(t, rtd.lastTargetPK, rtd.lastSourcePK)
+
+	// Only the range that has an end can be past it.
+	rtd = td.newRangeDiffer(tr, tr.ranges[0])
+	require.Nil(t, rtd.lastTargetPK)
+	pastEnd, err := rtd.pastRangeEnd([]sqltypes.Value{sqltypes.NewInt64(100), sqltypes.NewVarChar("a")})
+	require.NoError(t, err)
+	require.False(t, pastEnd)
+	pastEnd, err = rtd.pastRangeEnd([]sqltypes.Value{sqltypes.NewInt64(101), sqltypes.NewVarChar("b")})
+	require.NoError(t, err)
+	require.True(t, pastEnd)
+
+	dbc := binlogplayer.NewMockDBClient(t)
+	dbc.ExpectRequestRE("update _vt.vdiff_table set rows_compared = 13, lastpk = .* where vdiff_id = 1 and table_name = 't1'", &sqltypes.Result{}, nil)
+	dbc.ExpectRequestRE("update _vt.vdiff_table set rows_compared = 18, lastpk = .* where vdiff_id = 1 and table_name = 't1'", &sqltypes.Result{}, nil)
+	mismatch := &DiffMismatch{Source: &RowDiff{Row: map[string]string{"id": "1"}}}
+	err = rtd.updateTableProgress(dbc, &DiffReport{
+		TableName:           "t1",
+		ProcessedRows:       3,
+		MatchingRows:        1,
+		MismatchedRows:      2,
+		MismatchedRowsDiffs: []*DiffMismatch{mismatch, mismatch},
+	}, []sqltypes.Value{sqltypes.NewInt64(50), sqltypes.NewVarChar("a")})
+	require.NoError(t, err)
+	require.Equal(t, td.pkQueryResult([]sqltypes.Value{sqltypes.NewInt64(50)}), rtd.lastTargetPK)
+	err = td.newRangeDiffer(tr, tr.ranges[1]).updateTableProgress(dbc, &DiffReport{
+		TableName:           "t1",
+		ProcessedRows:       5,
+		MatchingRows:        4,
+		MismatchedRows:      1,
+		MismatchedRowsDiffs: []*DiffMismatch{mismatch},
+	}, []sqltypes.Value{sqltypes.NewInt64(150), sqltypes.NewVarChar("b")})
+	require.NoError(t, err)
+	dbc.Wait()
+
+	// The table's report is the base report plus those of the ranges, with
+	// the samples limited to the max sample rows.
+	tr.mu.Lock()
+	dr := tr.report()
+	lastPK := tr.lastPK()
+	tr.mu.Unlock()
+	require.Equal(t, &DiffReport{
+		TableName:           "t1",
+		ProcessedRows:       18,
+		MatchingRows:        15,
+		MismatchedRows:      3,
+		MismatchedRowsDiffs: []*DiffMismatch{mismatch, mismatch},
+	}, dr)
+	require.Empty(t, tr.base.MismatchedRowsDiffs)
+
+	// The ranges are restored as they were saved.
+	loaded, err := td.loadTableRanges(lastPK.Ranges)
+	require.NoError(t, err)
+	require.Len(t, loaded.ranges, 2)
+	for i, r := range loaded.ranges {
+		require.Equal(t, tr.ranges[i].start, r.start)
+		require.Equal(t, tr.ranges[i].end, r.end)
+		require.Equal(t, tr.ranges[i].lastPK, r.lastPK)
+	}
+	require.Equal(t, []sqltypes.Value{sqltypes.NewInt64(150)}, loaded.ranges[1].lastPK)
+
+	_, err = td.loadTableRanges([]*tabletmanagerdatapb.VDiffTableRange{{
+		End: sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(2)}),
+	}})
+	require.ErrorContains(t, err, "invalid primary key range value")
+}
+
+// TestDiffTableRanges tests that diffTableRanges() diffs up to
+// MaxConcurrentTableRanges ranges at the same time, and that a failed range
+// is retried from its last primary key once the other ranges are done.
+func TestDiffTableRanges(t *testing.T) {
+	newRanges := func() *tableRanges {
+		td := newTestRangesTableDiffer()
+		td.wd.opts.CoreOptions.MaxConcurrentTableRanges = 2
+		return &tableRanges{
+			td:   td,
+			base: &DiffReport{TableName: "t1"},
+			ranges: []*tableRange{
+				{end: []sqltypes.Value{sqltypes.NewInt64(100)}},
+				{start: []sqltypes.Value{sqltypes.NewInt64(100)}, end: []sqltypes.Value{sqltypes.NewInt64(200)}},
+				{start: []sqltypes.Value{sqltypes.NewInt64(200)}},
+			},
+		}
+	}
+	rangeStart := func(rtd *tableDiffer) string {
+		if rtd.tableRange.start == nil {
+			return "-"
+		}
+		return rtd.tableRange.start[0].ToString()
+	}
+
+	t.Run("failed range is retried", func(t *testing.T) {
+		tr := newRanges()
+		var (
+			running, maxRunning atomic.Int64
+			mu                  sync.Mutex
+			diffed              []string
+			resumedFrom         *querypb.QueryResult
+		)
+		dr, err := tr.td.wd.diffTableRanges(context.Background(), tr, func(ctx context.Context, rtd *tableDiffer) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				prev := maxRunning.Load()
+				if n <= prev || maxRunning.CompareAndSwap(prev, n) {
+					break
+				}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			start := rangeStart(rtd)
+			if start == "100" {
+				if rtd.tableRange.lastPK == nil {
+					// Fail after some progress, which the retry resumes from.
+					rtd.tableRange.lastPK = []sqltypes.Value{sqltypes.NewInt64(150)}
+					return errors.New("lost connection")
+				}
+				resumedFrom = rtd.lastTargetPK
+			}
+			diffed = append(diffed, start)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, "t1", dr.TableName)
+		require.LessOrEqual(t, maxRunning.Load(), int64(2))
+		require.Len(t, diffed, 3)
+		require.ElementsMatch(t, []string{"-", "200"}, diffed[:2])
+		require.Equal(t, "100", diffed[2])
+		require.Equal(t, tr.td.pkQueryResult([]sqltypes.Value{sqltypes.NewInt64(150)}), resumedFrom)
+	})
+
+	t.Run("range failing twice fails the table", func(t *testing.T) {
+		tr := newRanges()
+		var calls atomic.Int64
+		_, err := tr.td.wd.diffTableRanges(context.Background(), tr, func(ctx context.Context, rtd *tableDiffer) error {
+			calls.Add(1)
+			if rangeStart(rtd) == "200" {
+				return errors.New("lost connection")
+			}
+			return nil
+		})
+		require.ErrorContains(t, err, "failed to diff primary key range 2 of table t1: lost connection")
+		require.EqualValues(t, 4, calls.Load())
+	})
+
+	t.Run("stopped vdiff is not retried", func(t *testing.T) {
+		tr := newRanges()
+		var calls atomic.Int64
+		_, err := tr.td.wd.diffTableRanges(context.Background(), tr, func(ctx context.Context, rtd *tableDiffer) error {
+			calls.Add(1)
+			if rangeStart(rtd) == "-" {
+				return ErrVDiffStoppedByUser
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		require.ErrorIs(t, err, ErrVDiffStoppedByUser)
+		require.LessOrEqual(t, calls.Load(), int64(3))
+	})
+}
@@ -23,8 +23,10 @@ import (
 	"reflect"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/encoding/prototext"
 
 	"vitess.io/vitess/go/mysql/collations"
@@ -194,6 +196,99 @@ func (wd *workflowDiffer) doReconcileExtraRows(dr *DiffReport, maxExtraRowsToCom
 }
 
 func (wd *workflowDiffer) diffTable(ctx context.Context, dbClient binlogplayer.DBClient, td *tableDiffer) error {
+	log.Infof("Starting differ on table %s for vdiff %s", td.table.Name, wd.ct.uuid)
+	if err := td.updateTableState(ctx, dbClient, StartedState); err != nil {
+		return err
+	}
+
+	ranges, err := wd.getTableRanges(dbClient, td)
+	if err != nil {
+		return err
+	}
+	var diffReport *DiffReport
+	if ranges == nil {
+		diffReport, err = wd.diffTableSnapshots(ctx, td)
+	} else {
+		diffReport, err = wd.diffTableRanges(ctx, ranges, func(ctx context.Context, rtd *tableDiffer) error {
+			_, err := wd.diffTableSnapshots(ctx, rtd)
+			return err
+		})
+	}
+	if err != nil {
+		return err
+	}
+	log.Infof("Table diff done on table %s for vdiff %s with report: %+v", td.table.Name, wd.ct.uuid, diffReport)
+
+	if diffReport.ExtraRowsSource > 0 || diffReport.ExtraRowsTarget > 0 {
+		if err := wd.reconcileExtraRows(diffReport, wd.opts.CoreOptions.MaxExtraRowsToCompare, wd.opts.ReportOptions.MaxSampleRows); err != nil {
+			log.Errorf("Encountered an error reconciling extra rows found for table %s for vdiff %s: %v", td.table.Name, wd.ct.uuid, err)
+			return vterrors.Wrap(err, "failed to reconcile extra rows")
+		}
+	}
+
+	if diffReport.MismatchedRows > 0 || diffReport.ExtraRowsTarget > 0 || diffReport.ExtraRowsSource > 0 {
+		if err := updateTableMismatch(dbClient, wd.ct.id, td.table.Name); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Completed reconciliation on table %s for vdiff %s with updated report: %+v", td.table.Name, wd.ct.uuid, diffReport)
+	if err := td.updateTableStateAndReport(ctx, dbClient, CompletedState, diffReport); err != nil {
+		return err
+	}
+	return nil
+}
+
+// diffTableRanges diffs all of the primary key ranges of a table, up to the
+// MaxConcurrentTableRanges option number of them at the same time. A range that
+// fails does not stop the others. Once they are done, the failed ranges are
+// retried one at a time, from the last primary key they compared, and the first
+// one that fails again fails the table.
+func (wd *workflowDiffer) diffTableRanges(ctx context.Context, tr *tableRanges, diffRange func(context.Context, *tableDiffer) error) (*DiffReport, error) {
+	var (
+		mu     sync.Mutex
+		failed []int
+	)
+	g, gCtx := errgroup.WithContext(ctx)
+	if limit := wd.opts.CoreOptions.GetMaxConcurrentTableRanges(); limit > 0 {
+		g.SetLimit(int(limit))
+	}
+	for i, r := range tr.ranges {
+		g.Go(func() error {
+			err := diffRange(gCtx, tr.td.newRangeDiffer(tr, r))
+			if err == nil {
+				return nil
+			}
+			if gCtx.Err() != nil || errors.Is(err, ErrVDiffStoppedByUser) {
+				return err
+			}
+			log.Warningf("Failed to diff primary key range %d of table %s for vdiff %s, it will be retried: %v",
+				i, tr.td.table.Name, wd.ct.uuid, err)
+			mu.Lock()
+			defer mu.Unlock()
+			failed = append(failed, i)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	slices.Sort(failed)
+	for _, i := range failed {
+		// The range differ picks up from the progress the range saved.
+		if err := diffRange(ctx, tr.td.newRangeDiffer(tr, tr.ranges[i])); err != nil {
+			return nil, vterrors.Wrapf(err, "failed to diff primary key range %d of table %s", i, tr.td.table.Name)
+		}
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return tr.report(), nil
+}
+
+// diffTableSnapshots diffs the table, or one of its ranges, using consistent
+// snapshots of the source and target. The snapshots are replaced with new ones
+// when a diff runs for longer than the max-diff-duration.
+func (wd *workflowDiffer) diffTableSnapshots(ctx context.Context, td *tableDiffer) (*DiffReport, error) {
 	cancelShardStreams := func() {
 		if td.shardStreamsCancel != nil {
 			td.shardStreamsCancel()
@@ -227,17 +322,12 @@ func (wd *workflowDiffer) diffTable(ctx context.Context, dbClient binlogplayer.D
 		maxDiffRuntime = time.Duration(wd.ct.options.CoreOptions.MaxDiffSeconds) * time.Second
 	}
 
-	log.Infof("Starting differ on table %s for vdiff %s", td.table.Name, wd.ct.uuid)
-	if err := td.updateTableState(ctx, dbClient, StartedState); err != nil {
-		return err
-	}
-
 	for {
 		select {
 		case <-ctx.Done():
-			return vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired")
+			return nil, vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired")
 		case <-wd.ct.done:
-			return ErrVDiffStoppedByUser
+			return nil, ErrVDiffStoppedByUser
 		default:
 		}
 
@@ -255,7 +345,7 @@ func (wd *workflowDiffer) diffTable(ctx context.Context, dbClient binlogplayer.D
 			time.Sleep(30 * time.Second)
 		}
 		if err := td.initialize(ctx); err != nil { // Setup the consistent snapshots
-			return err
+			return nil, err
 		}
 		log.Infof("Table initialization done on table %s for vdiff %s", td.table.Name, wd.ct.uuid)
 		diffTimer = time.NewTimer(maxDiffRuntime)
@@ -265,29 +355,10 @@ func (wd *workflowDiffer) diffTable(ctx context.Context, dbClient binlogplayer.D
 		}
 		log.Errorf("Encountered an error diffing table %s for vdiff %s: %v", td.table.Name, wd.ct.uuid, diffErr)
 		if !errors.Is(diffErr, ErrMaxDiffDurationExceeded) { // We only want to retry if we hit the max-diff-duration
-			return diffErr
-		}
-	}
-	log.Infof("Table diff done on table %s for vdiff %s with report: %+v", td.table.Name, wd.ct.uuid, diffReport)
-
-	if diffReport.ExtraRowsSource > 0 || diffReport.ExtraRowsTarget > 0 {
-		if err := wd.reconcileExtraRows(diffReport, wd.opts.CoreOptions.MaxExtraRowsToCompare, wd.opts.ReportOptions.MaxSampleRows); err != nil {
-			log.Errorf("Encountered an error reconciling extra rows found for table %s for vdiff %s: %v", td.table.Name, wd.ct.uuid, err)
-			return vterrors.Wrap(err, "failed to reconcile extra rows")
-		}
-	}
-
-	if diffReport.MismatchedRows > 0 || diffReport.ExtraRowsTarget > 0 || diffReport.ExtraRowsSource > 0 {
-		if err := updateTableMismatch(dbClient, wd.ct.id, td.table.Name); err != nil {
-			return err
+			return nil, diffErr
 		}
 	}
-
-	log.Infof("Completed reconciliation on table %s for vdiff %s with updated report: %+v", td.table.Name, wd.ct.uuid, diffReport)
-	if err := td.updateTableStateAndReport(ctx, dbClient, CompletedState, diffReport); err != nil {
-		return err
-	}
-	return nil
+	return diffReport, nil
 }
 
 func (wd *workflowDiffer) diff(ctx context.Context) (err error) {
@@ -323,46 +394,74 @@ func (wd *workflowDiffer) diff(ctx context.Context) (err error) {
 	if err := wd.initVDiffTables(dbClient); err != nil {
 		return err
 	}
+	if err := wd.diffTables(ctx, wd.diffTableWithState); err != nil {
+		return err
+	}
+	if err := wd.markIfCompleted(ctx, dbClient); err != nil {
+		return err
+	}
+	return nil
+}
+
+// diffTables runs diffTable for every table in the plan. Each table diff sets
+// up its own consistent snapshots, so up to MaxConcurrentTables tables can be
+// diffed at the same time. The first error cancels the context passed to the
+// table diffs that are still running and is returned once they have stopped.
+func (wd *workflowDiffer) diffTables(ctx context.Context, diffTable func(context.Context, *tableDiffer) error) error {
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(int(max(wd.opts.CoreOptions.GetMaxConcurrentTables(), 1)))
 	for _, td := range wd.tableDiffers {
-		select {
-		case <-ctx.Done():
-			return vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired")
-		case <-wd.ct.done:
-			return ErrVDiffStoppedByUser
-		default:
-		}
-		query, err := sqlparser.ParseAndBind(sqlGetVDiffTable,
-			sqltypes.Int64BindVariable(wd.ct.id),
-			sqltypes.StringBindVariable(td.table.Name),
-		)
-		if err != nil {
-			return err
-		}
-		qr, err := dbClient.ExecuteFetch(query, 1)
-		if err != nil {
-			return err
-		}
-		if len(qr.Rows) == 0 {
-			return fmt.Errorf("no vdiff table found for %s on tablet %v",
-				td.table.Name, wd.ct.vde.thisTablet.Alias)
-		}
+		g.Go(func() error {
+			return diffTable(gCtx, td)
+		})
+	}
+	return g.Wait()
+}
 
-		log.Infof("Starting diff of table %s for vdiff %s", td.table.Name, wd.ct.uuid)
-		if err := wd.diffTable(ctx, dbClient, td); err != nil {
-			if err := td.updateTableState(ctx, dbClient, ErrorState); err != nil {
-				return err
-			}
-			insertVDiffLog(ctx, dbClient, wd.ct.id, fmt.Sprintf("Table %s Error: %s", td.table.Name, err))
-			return err
-		}
-		if err := td.updateTableState(ctx, dbClient, CompletedState); err != nil {
+// diffTableWithState diffs one table, using its own connection so that it can
+// run concurrently with other tables, and records the outcome in _vt.vdiff_table.
+func (wd *workflowDiffer) diffTableWithState(ctx context.Context, td *tableDiffer) error {
+	select {
+	case <-ctx.Done():
+		return vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired")
+	case <-wd.ct.done:
+		return ErrVDiffStoppedByUser
+	default:
+	}
+	dbClient := wd.ct.dbClientFactory()
+	if err := dbClient.Connect(); err != nil {
+		return err
+	}
+	defer dbClient.Close()
+
+	query, err := sqlparser.ParseAndBind(sqlGetVDiffTable,
+		sqltypes.Int64BindVariable(wd.ct.id),
+		sqltypes.StringBindVariable(td.table.Name),
+	)
+	if err != nil {
+		return err
+	}
+	qr, err := dbClient.ExecuteFetch(query, 1)
+	if err != nil {
+		return err
+	}
+	if len(qr.Rows) == 0 {
+		return fmt.Errorf("no vdiff table found for %s on tablet %v",
+			td.table.Name, wd.ct.vde.thisTablet.Alias)
+	}
+
+	log.Infof("Starting diff of table %s for vdiff %s", td.table.Name, wd.ct.uuid)
+	if err := wd.diffTable(ctx, dbClient, td); err != nil {
+		if err := td.updateTableState(ctx, dbClient, ErrorState); err != nil {
 			return err
 		}
-		log.Infof("Completed diff of table %s for vdiff %s", td.table.Name, wd.ct.uuid)
+		insertVDiffLog(ctx, dbClient, wd.ct.id, fmt.Sprintf("Table %s Error: %s", td.table.Name, err))
+		return err
 	}
-	if err := wd.markIfCompleted(ctx, dbClient); err != nil {
+	if err := td.updateTableState(ctx, dbClient, CompletedState); err != nil {
 		return err
 	}
+	log.Infof("Completed diff of table %s for vdiff %s", td.table.Name, wd.ct.uuid)
 	return nil
 }
 
@@ -428,6 +527,7 @@ func (wd *workflowDiffer) buildPlan(dbClient binlogplayer.DBClient, filter *binl
 		if lastPK != nil {
 			td.lastSourcePK = lastPK.Source
 			td.lastTargetPK = lastPK.Target
+			td.savedRanges = lastPK.Ranges
 		}
 		wd.tableDiffers[table.Name] = td
 		if _, err := td.buildTablePlan(dbClient, wd.ct.vde.dbName, wd.collationEnv); err != nil {
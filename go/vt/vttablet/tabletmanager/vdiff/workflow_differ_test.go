@@ -17,8 +17,12 @@ limitations under the License.
 package vdiff
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/google/uuid"
@@ -29,11 +33,13 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/binlog/binlogplayer"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
 
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 // TestReconcileExtraRows tests reconcileExtraRows() by providing different types of source and target slices and validating
@@ -206,6 +212,102 @@ func TestReconcileExtraRows(t *testing.T) {
 	}
 }
 
+// TestDiffTablesConcurrently tests that diffTables() diffs up to
+// MaxConcurrentTables tables at the same time and that an error in one
+// table cancels the diffs of the other tables.
+func TestDiffTablesConcurrently(t *testing.T) {
+	vdenv := newTestVDiffEnv(t)
+	defer vdenv.close()
+	UUID := uuid.New()
+	controllerQR := sqltypes.MakeTestResult(sqltypes.MakeTestFields(
+		vdiffTestCols,
+		vdiffTestColTypes,
+	),
+		fmt.Sprintf("1|%s|%s|%s|%s|%s|%s|%s|", UUID, vdiffenv.workflow, tstenv.KeyspaceName, tstenv.ShardName, vdiffDBName, PendingState, optionsJS),
+	)
+
+	vdiffenv.dbClient.ExpectRequest("select * from _vt.vdiff where id = 1", noResults, nil)
+	ct := vdenv.newController(t, controllerQR)
+	newDiffer := func(tables ...string) *workflowDiffer {
+		wd, err := newWorkflowDiffer(ct, &tabletmanagerdatapb.VDiffOptions{
+			CoreOptions: &tabletmanagerdatapb.VDiffCoreOptions{
+				MaxConcurrentTables: 2,
+			},
+		}, collations.MySQL8())
+		require.NoError(t, err)
+		for _, table := range tables {
+			wd.tableDiffers[table] = &tableDiffer{
+				wd:    wd,
+				table: &tabletmanagerdatapb.TableDefinition{Name: table},
+			}
+		}
+		return wd
+	}
+
+	t.Run("all tables complete", func(t *testing.T) {
+		wd := newDiffer("t1", "t2", "t3")
+		var (
+			running, maxRunning atomic.Int64
+			mu                  sync.Mutex
+			diffed              []string
+		)
+		started := make(chan string, 3)
+		release := make(chan struct{})
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- wd.diffTables(context.Background(), func(ctx context.Context, td *tableDiffer) error {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					prev := maxRunning.Load()
+					if n <= prev || maxRunning.CompareAndSwap(prev, n) {
+						break
+					}
+				}
+				started <- td.table.Name
+				<-release
+				mu.Lock()
+				defer mu.Unlock()
+				diffed = append(diffed, td.table.Name)
+				return nil
+			})
+		}()
+		// Two tables must be in flight at the same time before either of
+		// them is allowed to finish.
+		<-started
+		<-started
+		close(release)
+		require.NoError(t, <-errCh)
+		require.LessOrEqual(t, maxRunning.Load(), int64(2))
+		require.ElementsMatch(t, []string{"t1", "t2", "t3"}, diffed)
+	})
+
+	t.Run("error cancels other tables", func(t *testing.T) {
+		wd := newDiffer("t1", "t2")
+		diffErr := errors.New("failed to diff t1")
+		t2Started := make(chan struct{})
+		var t2Err, t2StateErr error
+		err := wd.diffTables(context.Background(), func(ctx context.Context, td *tableDiffer) error {
+			switch td.table.Name {
+			case "t1":
+				<-t2Started
+				return diffErr
+			default:
+				close(t2Started)
+				<-ctx.Done()
+				t2Err = ctx.Err()
+				// diffTableWithState does not start a table diff once
+				// the vdiff has been canceled.
+				t2StateErr = wd.diffTableWithState(ctx, td)
+				return t2StateErr
+			}
+		})
+		require.ErrorIs(t, err, diffErr)
+		require.ErrorIs(t, t2Err, context.Canceled)
+		require.Equal(t, vtrpcpb.Code_CANCELED, vterrors.Code(t2StateErr))
+	})
+}
+
 func TestBuildPlanSuccess(t *testing.T) {
 	vdenv := newTestVDiffEnv(t)
 	defer vdenv.close()
@@ -0,0 +1,352 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/ptr"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// The aggregations that cannot be updated from the changed rows alone,
+// count(distinct a), min(a) and max(a), are kept in a state table on the
+// target. The state table of an aggregated column has the primary key
+// columns of the target table, the distinct values of the aggregated column
+// for each of its rows, and the number of source rows with each value.
+const (
+	aggregateStateTableFormat = "_vt_agg_%s_%s"
+	aggregateStateValueColumn = "_vt_value"
+	aggregateStateCountColumn = "_vt_count"
+)
+
+// AggregateStateTableName returns the name of the state table of an
+// aggregated column of a target table.
+func AggregateStateTableName(table, column string) string {
+	return fmt.Sprintf(aggregateStateTableFormat, table, column)
+}
+
+// AggregateState has the statements that maintain the state table of an
+// aggregated column.
+type AggregateState struct {
+	// Insert adds the value of the after image.
+	Insert *sqlparser.ParsedQuery
+	// Decrement and Delete remove the value of the before image.
+	Decrement *sqlparser.ParsedQuery
+	Delete    *sqlparser.ParsedQuery
+}
+
+// MaterializeAggregation is an aggregated column of a Materialize query
+// that is kept in a state table.
+type MaterializeAggregation struct {
+	// Column is the column of the target table.
+	Column string
+	// SourceColumn is the aggregated column of the source table.
+	SourceColumn string
+}
+
+// AnalyzeMaterializeQuery analyzes the query of a Materialize rule the same
+// way it is planned by the workflow streams, so that unsupported expressions
+// are rejected when the workflow is created. It returns the aggregations of
+// the query that are kept in state tables.
+func AnalyzeMaterializeQuery(query string, parser *sqlparser.Parser) ([]*MaterializeAggregation, error) {
+	sel, _, err := analyzeSelectFrom(query, parser)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := sel.SelectExprs.Exprs[0].(*sqlparser.StarExpr); ok && len(sel.SelectExprs.Exprs) == 1 {
+		return nil, nil
+	}
+	tpb := &tablePlanBuilder{
+		sendSelect: &sqlparser.Select{
+			From:  sel.From,
+			Where: sel.Where,
+		},
+	}
+	if err := tpb.analyzeExprs(sel.SelectExprs.Exprs); err != nil {
+		return nil, err
+	}
+	if err := tpb.analyzeGroupBy(sel.GroupBy); err != nil {
+		return nil, err
+	}
+	var aggregations []*MaterializeAggregation
+	for _, cexpr := range tpb.colExprs {
+		if !cexpr.operation.hasState() {
+			continue
+		}
+		aggregations = append(aggregations, &MaterializeAggregation{
+			Column:       cexpr.colName.String(),
+			SourceColumn: cexpr.expr.(*sqlparser.ColName).Name.String(),
+		})
+	}
+	return aggregations, nil
+}
+
+// AggregateStateTableDDL returns the statement that creates the state table
+// of an aggregation, given the CREATE TABLE statements of the target and the
+// source tables.
+func AggregateStateTableDDL(parser *sqlparser.Parser, targetDDL, sourceDDL string, aggregation *MaterializeAggregation) (string, error) {
+	target, err := parseCreateTable(parser, targetDDL)
+	if err != nil {
+		return "", err
+	}
+	source, err := parseCreateTable(parser, sourceDDL)
+	if err != nil {
+		return "", err
+	}
+	name := AggregateStateTableName(target.Table.Name.String(), aggregation.Column)
+	if len(name) > mysql.MaxIdentifierLength {
+		return "", fmt.Errorf("state table name %s of column %s is too long", name, aggregation.Column)
+	}
+	var pkCols []sqlparser.IdentifierCI
+	for _, index := range target.TableSpec.Indexes {
+		if index.Info.Type != sqlparser.IndexTypePrimary {
+			continue
+		}
+		for _, col := range index.Columns {
+			pkCols = append(pkCols, col.Column)
+		}
+	}
+	for _, col := range target.TableSpec.Columns {
+		if col.Type.Options != nil && col.Type.Options.KeyOpt == sqlparser.ColKeyPrimary {
+			pkCols = append(pkCols, col.Name)
+		}
+	}
+	if len(pkCols) == 0 {
+		return "", fmt.Errorf("table %s has no primary key", sqlparser.String(target.Table))
+	}
+	spec := &sqlparser.TableSpec{}
+	for _, pkCol := range pkCols {
+		col := findColumnDefinition(target.TableSpec, pkCol)
+		if col == nil {
+			return "", fmt.Errorf("primary key column %v not found in table %s", pkCol, sqlparser.String(target.Table))
+		}
+		spec.Columns = append(spec.Columns, stateColumnDefinition(col.Name, col.Type))
+	}
+	valueCol := findColumnDefinition(source.TableSpec, sqlparser.NewIdentifierCI(aggregation.SourceColumn))
+	if valueCol == nil {
+		return "", fmt.Errorf("column %s not found in table %s", aggregation.SourceColumn, sqlparser.String(source.Table))
+	}
+	if typ := strings.ToLower(valueCol.Type.Type); typ == "json" || schemadiff.BlobTypeStorage(typ) > 0 {
+		return "", fmt.Errorf("unsupported aggregation of %s column %s", typ, aggregation.SourceColumn)
+	}
+	spec.Columns = append(spec.Columns,
+		stateColumnDefinition(sqlparser.NewIdentifierCI(aggregateStateValueColumn), valueCol.Type),
+		&sqlparser.ColumnDefinition{
+			Name: sqlparser.NewIdentifierCI(aggregateStateCountColumn),
+			Type: &sqlparser.ColumnType{
+				Type:     "bigint",
+				Unsigned: true,
+				Options:  &sqlparser.ColumnTypeOptions{Null: ptr.Of(false)},
+			},
+		},
+	)
+	pkIndex := &sqlparser.IndexDefinition{Info: &sqlparser.IndexInfo{Type: sqlparser.IndexTypePrimary}}
+	for _, pkCol := range pkCols {
+		pkIndex.Columns = append(pkIndex.Columns, &sqlparser.IndexColumn{Column: pkCol})
+	}
+	pkIndex.Columns = append(pkIndex.Columns, &sqlparser.IndexColumn{Column: sqlparser.NewIdentifierCI(aggregateStateValueColumn)})
+	spec.Indexes = append(spec.Indexes, pkIndex)
+	return sqlparser.String(&sqlparser.CreateTable{
+		Table:       sqlparser.NewTableName(name),
+		IfNotExists: true,
+		TableSpec:   spec,
+	}), nil
+}
+
+func parseCreateTable(parser *sqlparser.Parser, ddl string) (*sqlparser.CreateTable, error) {
+	stmt, err := parser.ParseStrictDDL(ddl)
+	if err != nil {
+		return nil, err
+	}
+	create, ok := stmt.(*sqlparser.CreateTable)
+	if !ok || create.TableSpec == nil {
+		return nil, fmt.Errorf("not a create table statement: %s", ddl)
+	}
+	return create, nil
+}
+
+func findColumnDefinition(spec *sqlparser.TableSpec, name sqlparser.IdentifierCI) *sqlparser.ColumnDefinition {
+	for _, col := range spec.Columns {
+		if col.Name.Equal(name) {
+			return col
+		}
+	}
+	return nil
+}
+
+// stateColumnDefinition returns a column of a state table with the type of
+// a column of the target or the source table.
+func stateColumnDefinition(name sqlparser.IdentifierCI, typ *sqlparser.ColumnType) *sqlparser.ColumnDefinition {
+	options := &sqlparser.ColumnTypeOptions{Null: ptr.Of(false)}
+	if typ.Options != nil {
+		options.Collate = typ.Options.Collate
+	}
+	return &sqlparser.ColumnDefinition{
+		Name: name,
+		Type: &sqlparser.ColumnType{
+			Type:       typ.Type,
+			Options:    options,
+			Length:     typ.Length,
+			Unsigned:   typ.Unsigned,
+			Zerofill:   typ.Zerofill,
+			Scale:      typ.Scale,
+			Charset:    typ.Charset,
+			EnumValues: typ.EnumValues,
+		},
+	}
+}
+
+// generateAggregateStates generates the statements that maintain the state
+// tables of the aggregations of the table.
+func (tpb *tablePlanBuilder) generateAggregateStates() []*AggregateState {
+	var states []*AggregateState
+	for _, cexpr := range tpb.colExprs {
+		if !cexpr.operation.hasState() {
+			continue
+		}
+		states = append(states, &AggregateState{
+			Insert:    tpb.generateStateInsert(cexpr),
+			Decrement: tpb.generateStateDecrement(cexpr),
+			Delete:    tpb.generateStateDelete(cexpr),
+		})
+	}
+	return states
+}
+
+func (tpb *tablePlanBuilder) stateTable(cexpr *colExpr) sqlparser.IdentifierCS {
+	return sqlparser.NewIdentifierCS(AggregateStateTableName(tpb.name.String(), cexpr.colName.String()))
+}
+
+func (tpb *tablePlanBuilder) generateStateInsert(cexpr *colExpr) *sqlparser.ParsedQuery {
+	bvf := &bindvarFormatter{mode: bvAfter}
+	buf := sqlparser.NewTrackedBuffer(bvf.formatter)
+	buf.Myprintf("insert into %v(", tpb.stateTable(cexpr))
+	for _, pkCol := range tpb.pkCols {
+		buf.Myprintf("%v,", pkCol.colName)
+	}
+	buf.Myprintf("%s,%s) select ", aggregateStateValueColumn, aggregateStateCountColumn)
+	for _, pkCol := range tpb.pkCols {
+		buf.Myprintf("%v, ", pkCol.expr)
+	}
+	buf.Myprintf("%v, 1 from dual where %v is not null", cexpr.expr, cexpr.expr)
+	if tpb.lastpk != nil {
+		buf.WriteString(" and ")
+		tpb.generatePKConstraint(buf, bvf)
+	}
+	buf.Myprintf(" on duplicate key update %s=%s+1", aggregateStateCountColumn, aggregateStateCountColumn)
+	return buf.ParsedQuery()
+}
+
+func (tpb *tablePlanBuilder) generateStateDecrement(cexpr *colExpr) *sqlparser.ParsedQuery {
+	bvf := &bindvarFormatter{mode: bvBefore}
+	buf := sqlparser.NewTrackedBuffer(bvf.formatter)
+	buf.Myprintf("update %v set %s=%s-1 where ", tpb.stateTable(cexpr), aggregateStateCountColumn, aggregateStateCountColumn)
+	tpb.generateStateWhere(buf)
+	buf.Myprintf(" and %s=%v", aggregateStateValueColumn, cexpr.expr)
+	if tpb.lastpk != nil {
+		buf.WriteString(" and ")
+		tpb.generatePKConstraint(buf, bvf)
+	}
+	return buf.ParsedQuery()
+}
+
+func (tpb *tablePlanBuilder) generateStateDelete(cexpr *colExpr) *sqlparser.ParsedQuery {
+	bvf := &bindvarFormatter{mode: bvBefore}
+	buf := sqlparser.NewTrackedBuffer(bvf.formatter)
+	buf.Myprintf("delete from %v where ", tpb.stateTable(cexpr))
+	tpb.generateStateWhere(buf)
+	buf.Myprintf(" and %s=%v and %s=0", aggregateStateValueColumn, cexpr.expr, aggregateStateCountColumn)
+	return buf.ParsedQuery()
+}
+
+// generateStateValue generates the subquery that computes an aggregation
+// from its state table, for the group of the before or the after image
+// depending on the mode of the formatter of the buffer.
+func (tpb *tablePlanBuilder) generateStateValue(buf *sqlparser.TrackedBuffer, cexpr *colExpr) {
+	var fname string
+	switch cexpr.operation {
+	case opCountDistinct:
+		fname = "count"
+	case opMin:
+		fname = "min"
+	case opMax:
+		fname = "max"
+	}
+	buf.Myprintf("(select %s(%s) from %v where ", fname, aggregateStateValueColumn, tpb.stateTable(cexpr))
+	tpb.generateStateWhere(buf)
+	buf.WriteString(")")
+}
+
+// generateStateWhere generates the condition on the rows of a state table
+// that belong to the group of a row.
+func (tpb *tablePlanBuilder) generateStateWhere(buf *sqlparser.TrackedBuffer) {
+	separator := ""
+	for _, pkCol := range tpb.pkCols {
+		if _, ok := pkCol.expr.(*sqlparser.ColName); ok {
+			buf.Myprintf("%s%v=%v", separator, pkCol.colName, pkCol.expr)
+		} else {
+			buf.Myprintf("%s%v=(%v)", separator, pkCol.colName, pkCol.expr)
+		}
+		separator = " and "
+	}
+}
+
+// applyAggregateState removes the before image of a row change from the
+// state tables of the aggregations of the table, and adds its after image.
+// It must be applied before the statement that changes the target table,
+// which recomputes the aggregations from the state tables.
+func (tp *TablePlan) applyAggregateState(bindvars map[string]*querypb.BindVariable, before, after bool, executor func(string) (*sqltypes.Result, error)) error {
+	for _, state := range tp.AggregateStates {
+		var queries []*sqlparser.ParsedQuery
+		if before {
+			queries = append(queries, state.Decrement, state.Delete)
+		}
+		if after {
+			queries = append(queries, state.Insert)
+		}
+		for _, pq := range queries {
+			if _, err := execParsedQuery(pq, bindvars, executor); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyRowChanges applies row changes one at a time, for the tables whose
+// changes can't be applied in bulk.
+func (tp *TablePlan) applyRowChanges(rowChanges []*binlogdatapb.RowChange, executor func(string) (*sqltypes.Result, error)) (*sqltypes.Result, error) {
+	result := &sqltypes.Result{}
+	for _, rowChange := range rowChanges {
+		qr, err := tp.applyChange(rowChange, executor)
+		if err != nil {
+			return nil, err
+		}
+		if qr != nil {
+			result.RowsAffected += qr.RowsAffected
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vttablet "vitess.io/vitess/go/vt/vttablet/common"
+)
+
+func TestAnalyzeMaterializeQuery(t *testing.T) {
+	testcases := []struct {
+		query   string
+		want    []*MaterializeAggregation
+		wantErr string
+	}{{
+		query: "select * from t1",
+	}, {
+		query: "select c1, count(*) as cnt, sum(c2) as total from t1 group by c1",
+	}, {
+		query: "select c1, min(c2) as lo, max(c2) as hi, count(distinct c3) as n from t1 group by c1",
+		want: []*MaterializeAggregation{
+			{Column: "lo", SourceColumn: "c2"},
+			{Column: "hi", SourceColumn: "c2"},
+			{Column: "n", SourceColumn: "c3"},
+		},
+	}, {
+		query:   "select c1, avg(c2) as a from t1 group by c1",
+		wantErr: "unsupported aggregation function: avg(c2)",
+	}, {
+		query:   "select c1, count(distinct c2 + 1) as n from t1 group by c1",
+		wantErr: "unsupported non-column name in count clause: count(distinct c2 + 1)",
+	}, {
+		query:   "select min(c2) as lo from t1",
+		wantErr: "unsupported aggregation without a group by clause: lo",
+	}, {
+		query:   "select c1, * from t1",
+		wantErr: "invalid expression: *",
+	}}
+	for _, tc := range testcases {
+		t.Run(tc.query, func(t *testing.T) {
+			got, err := AnalyzeMaterializeQuery(tc.query, sqlparser.NewTestParser())
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestAggregateStateTableDDL(t *testing.T) {
+	const source = "create table t1 (id int primary key, c1 varchar(16) collate utf8mb4_bin, c2 decimal(10,2) default null, c3 text)"
+	testcases := []struct {
+		name        string
+		target      string
+		aggregation *MaterializeAggregation
+		want        string
+		wantErr     string
+	}{{
+		name:        "min of decimal",
+		target:      "create table totals (c1 varchar(16) not null, lo decimal(10,2), primary key (c1))",
+		aggregation: &MaterializeAggregation{Column: "lo", SourceColumn: "c2"},
+		want:        "create table if not exists _vt_agg_totals_lo (\n\tc1 varchar(16) not null,\n\t_vt_value decimal(10,2) not null,\n\t_vt_count bigint unsigned not null,\n\tprimary key (c1, _vt_value)\n)",
+	}, {
+		name:        "count distinct of varchar",
+		target:      "create table totals (id bigint not null primary key, n bigint)",
+		aggregation: &MaterializeAggregation{Column: "n", SourceColumn: "c1"},
+		want:        "create table if not exists _vt_agg_totals_n (\n\tid bigint not null,\n\t_vt_value varchar(16) collate utf8mb4_bin not null,\n\t_vt_count bigint unsigned not null,\n\tprimary key (id, _vt_value)\n)",
+	}, {
+		name:        "text column",
+		target:      "create table totals (id bigint primary key, n bigint)",
+		aggregation: &MaterializeAggregation{Column: "n", SourceColumn: "c3"},
+		wantErr:     "unsupported aggregation of text column c3",
+	}, {
+		name:        "unknown column",
+		target:      "create table totals (id bigint primary key, n bigint)",
+		aggregation: &MaterializeAggregation{Column: "n", SourceColumn: "c4"},
+		wantErr:     "column c4 not found in table t1",
+	}, {
+		name:        "no primary key",
+		target:      "create table totals (id bigint, n bigint)",
+		aggregation: &MaterializeAggregation{Column: "n", SourceColumn: "c1"},
+		wantErr:     "table totals has no primary key",
+	}}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := AggregateStateTableDDL(sqlparser.NewTestParser(), tc.target, source, tc.aggregation)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestApplyChangeAggregateState(t *testing.T) {
+	filter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match:  "totals",
+			Filter: "select c1, max(c2) as hi from t1 group by c1",
+		}},
+	}
+	colInfos := map[string][]*ColumnInfo{
+		"totals": {&ColumnInfo{Name: "c1", IsPK: true}},
+	}
+	vttablet.InitVReplicationConfigDefaults()
+	vr := &vreplicator{
+		workflowConfig: vttablet.DefaultVReplicationConfig,
+	}
+	plan, err := vr.buildReplicatorPlan(getSource(filter), colInfos, nil, binlogplayer.NewStats(), collations.MySQL8(), sqlparser.NewTestParser())
+	require.NoError(t, err)
+	fields := sqltypes.MakeTestFields("c1|c2", "varchar|int64")
+	tp, err := plan.buildExecutionPlan(&binlogdatapb.FieldEvent{TableName: "t1", Fields: fields})
+	require.NoError(t, err)
+	require.Nil(t, tp.MultiDelete)
+
+	var queries []string
+	executor := func(query string) (*sqltypes.Result, error) {
+		queries = append(queries, query)
+		return &sqltypes.Result{RowsAffected: 1}, nil
+	}
+	row := func(values ...string) *binlogdatapb.RowChange {
+		return &binlogdatapb.RowChange{After: sqltypes.RowToProto3(sqltypes.MakeTestResult(fields, values...).Rows[0])}
+	}
+
+	// Rows copied in bulk are applied one at a time.
+	qr, err := tp.applyBulkInsert(nil, []*querypb.Row{row("a|1").After, row("a|2").After}, executor)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, qr.RowsAffected)
+	assert.Equal(t, []string{
+		"insert into _vt_agg_totals_hi(c1,_vt_value,_vt_count) select 'a', 1, 1 from dual where 1 is not null on duplicate key update _vt_count=_vt_count+1",
+		"insert into totals(c1,hi) values ('a',1) on duplicate key update hi=(select max(_vt_value) from _vt_agg_totals_hi where c1='a')",
+		"insert into _vt_agg_totals_hi(c1,_vt_value,_vt_count) select 'a', 2, 1 from dual where 2 is not null on duplicate key update _vt_count=_vt_count+1",
+		"insert into totals(c1,hi) values ('a',2) on duplicate key update hi=(select max(_vt_value) from _vt_agg_totals_hi where c1='a')",
+	}, queries)
+
+	// The state is changed before the aggregation is recomputed from it.
+	queries = nil
+	update := &binlogdatapb.RowChange{Before: row("a|2").After, After: row("a|0").After}
+	_, err = tp.applyChange(update, executor)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"update _vt_agg_totals_hi set _vt_count=_vt_count-1 where c1='a' and _vt_value=2",
+		"delete from _vt_agg_totals_hi where c1='a' and _vt_value=2 and _vt_count=0",
+		"insert into _vt_agg_totals_hi(c1,_vt_value,_vt_count) select 'a', 0, 1 from dual where 0 is not null on duplicate key update _vt_count=_vt_count+1",
+		"update totals set hi=(select max(_vt_value) from _vt_agg_totals_hi where c1='a') where c1='a'",
+	}, queries)
+
+	// Partial row images can't be applied.
+	update.DataColumns = &binlogdatapb.RowChange_Bitmap{Count: 2, Cols: []byte{0x01}}
+	_, err = tp.applyChange(update, executor)
+	require.ErrorContains(t, err, "binary log event missing values needed for the aggregations of totals")
+}
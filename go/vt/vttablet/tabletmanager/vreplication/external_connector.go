@@ -154,7 +154,7 @@ func (c *mysqlConnector) VStreamRows(ctx context.Context, query string, lastpk *
 
 func (c *mysqlConnector) VStreamTables(ctx context.Context,
 	send func(response *binlogdatapb.VStreamTablesResponse) error, options *binlogdatapb.VStreamOptions) error {
-	return c.vstreamer.StreamTables(ctx, send, options)
+	return c.vstreamer.StreamTables(ctx, &binlogdatapb.VStreamTablesRequest{Options: options}, send)
 }
 
 // -----------------------------------------------------------
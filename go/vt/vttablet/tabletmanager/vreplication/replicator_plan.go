@@ -225,6 +225,10 @@ type TablePlan struct {
 	PartialInserts map[string]*sqlparser.ParsedQuery
 	// PartialUpdates are same as PartialInserts, but for update statements
 	PartialUpdates map[string]*sqlparser.ParsedQuery
+	// AggregateStates maintain the state tables of the count(distinct),
+	// min and max aggregations of the table. Rows of tables that have any
+	// are applied one at a time.
+	AggregateStates []*AggregateState
 
 	CollationEnv   *collations.Environment
 	WorkflowConfig *vttablet.VReplicationConfig
@@ -242,6 +246,8 @@ func (tp *TablePlan) MarshalJSON() ([]byte, error) {
 		Update       *sqlparser.ParsedQuery `json:",omitempty"`
 		Delete       *sqlparser.ParsedQuery `json:",omitempty"`
 		PKReferences []string               `json:",omitempty"`
+
+		AggregateStates []*AggregateState `json:",omitempty"`
 	}{
 		TargetName:   tp.TargetName,
 		SendRule:     tp.SendRule.Match,
@@ -252,11 +258,20 @@ func (tp *TablePlan) MarshalJSON() ([]byte, error) {
 		Update:       tp.Update,
 		Delete:       tp.Delete,
 		PKReferences: tp.PKReferences,
+
+		AggregateStates: tp.AggregateStates,
 	}
 	return json.Marshal(&v)
 }
 
 func (tp *TablePlan) applyBulkInsert(sqlbuffer *bytes2.Buffer, rows []*querypb.Row, executor func(string) (*sqltypes.Result, error)) (*sqltypes.Result, error) {
+	if len(tp.AggregateStates) > 0 {
+		rowChanges := make([]*binlogdatapb.RowChange, 0, len(rows))
+		for _, row := range rows {
+			rowChanges = append(rowChanges, &binlogdatapb.RowChange{After: row})
+		}
+		return tp.applyRowChanges(rowChanges, executor)
+	}
 	sqlbuffer.Reset()
 	sqlbuffer.WriteString(tp.BulkInsertFront.Query)
 	sqlbuffer.WriteString(" values ")
@@ -433,6 +448,16 @@ func (tp *TablePlan) applyChange(rowChange *binlogdatapb.RowChange, executor fun
 			bindvars["a_"+field.Name] = bindVar
 		}
 	}
+	if len(tp.AggregateStates) > 0 {
+		if tp.isPartial(rowChange) {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION,
+				"binary log event missing values needed for the aggregations of %s due to not using binlog-row-image=FULL; you will need to re-run the workflow with binlog-row-image=FULL",
+				tp.TargetName)
+		}
+		if err := tp.applyAggregateState(bindvars, before, after, executor); err != nil {
+			return nil, err
+		}
+	}
 	switch {
 	case !before && after:
 		// Only apply inserts for rows whose primary keys are within the range of rows already copied.
@@ -605,6 +630,9 @@ func (tp *TablePlan) applyBulkInsertChanges(rowInserts []*binlogdatapb.RowChange
 	if len(rowInserts) == 0 {
 		return &sqltypes.Result{}, nil
 	}
+	if len(tp.AggregateStates) > 0 {
+		return tp.applyRowChanges(rowInserts, executor)
+	}
 	if tp.BulkInsertFront == nil {
 		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "plan has no bulk insert query")
 	}
@@ -51,6 +51,14 @@ type TestTablePlan struct {
 	Update       string   `json:",omitempty"`
 	Delete       string   `json:",omitempty"`
 	PKReferences []string `json:",omitempty"`
+
+	AggregateStates []*TestAggregateState `json:",omitempty"`
+}
+
+type TestAggregateState struct {
+	Insert    string
+	Decrement string
+	Delete    string
 }
 
 func TestBuildPlayerPlan(t *testing.T) {
@@ -322,6 +330,70 @@ func TestBuildPlayerPlan(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		// aggregations kept in state tables
+		input: &binlogdatapb.Filter{
+			Rules: []*binlogdatapb.Rule{{
+				Match:  "t1",
+				Filter: "select c1, min(c2) as c2, count(distinct c3) as c3 from t2 group by c1",
+			}},
+		},
+		plan: &TestReplicatorPlan{
+			VStreamFilter: &binlogdatapb.Filter{
+				Rules: []*binlogdatapb.Rule{{
+					Match:  "t2",
+					Filter: "select c1, c2, c3 from t2",
+				}},
+			},
+			TargetTables: []string{"t1"},
+			TablePlans: map[string]*TestTablePlan{
+				"t2": {
+					TargetName:   "t1",
+					SendRule:     "t2",
+					PKReferences: []string{"c1"},
+					Insert:       "insert into t1(c1,c2,c3) values (:a_c1,:a_c2,if(:a_c3 is null, 0, 1)) on duplicate key update c2=(select min(_vt_value) from _vt_agg_t1_c2 where c1=:a_c1), c3=(select count(_vt_value) from _vt_agg_t1_c3 where c1=:a_c1)",
+					Update:       "update t1 set c2=(select min(_vt_value) from _vt_agg_t1_c2 where c1=:a_c1), c3=(select count(_vt_value) from _vt_agg_t1_c3 where c1=:a_c1) where c1=:b_c1",
+					Delete:       "update t1 set c2=(select min(_vt_value) from _vt_agg_t1_c2 where c1=:b_c1), c3=(select count(_vt_value) from _vt_agg_t1_c3 where c1=:b_c1) where c1=:b_c1",
+					AggregateStates: []*TestAggregateState{{
+						Insert:    "insert into _vt_agg_t1_c2(c1,_vt_value,_vt_count) select :a_c1, :a_c2, 1 from dual where :a_c2 is not null on duplicate key update _vt_count=_vt_count+1",
+						Decrement: "update _vt_agg_t1_c2 set _vt_count=_vt_count-1 where c1=:b_c1 and _vt_value=:b_c2",
+						Delete:    "delete from _vt_agg_t1_c2 where c1=:b_c1 and _vt_value=:b_c2 and _vt_count=0",
+					}, {
+						Insert:    "insert into _vt_agg_t1_c3(c1,_vt_value,_vt_count) select :a_c1, :a_c3, 1 from dual where :a_c3 is not null on duplicate key update _vt_count=_vt_count+1",
+						Decrement: "update _vt_agg_t1_c3 set _vt_count=_vt_count-1 where c1=:b_c1 and _vt_value=:b_c3",
+						Delete:    "delete from _vt_agg_t1_c3 where c1=:b_c1 and _vt_value=:b_c3 and _vt_count=0",
+					}},
+				},
+			},
+		},
+		planpk: &TestReplicatorPlan{
+			VStreamFilter: &binlogdatapb.Filter{
+				Rules: []*binlogdatapb.Rule{{
+					Match:  "t2",
+					Filter: "select c1, c2, c3, pk1, pk2 from t2",
+				}},
+			},
+			TargetTables: []string{"t1"},
+			TablePlans: map[string]*TestTablePlan{
+				"t2": {
+					TargetName:   "t1",
+					SendRule:     "t2",
+					PKReferences: []string{"c1", "pk1", "pk2"},
+					Insert:       "insert into t1(c1,c2,c3) select :a_c1, :a_c2, if(:a_c3 is null, 0, 1) from dual where (:a_pk1,:a_pk2) <= (1,'aaa') on duplicate key update c2=(select min(_vt_value) from _vt_agg_t1_c2 where c1=:a_c1), c3=(select count(_vt_value) from _vt_agg_t1_c3 where c1=:a_c1)",
+					Update:       "update t1 set c2=(select min(_vt_value) from _vt_agg_t1_c2 where c1=:a_c1), c3=(select count(_vt_value) from _vt_agg_t1_c3 where c1=:a_c1) where c1=:b_c1 and (:b_pk1,:b_pk2) <= (1,'aaa')",
+					Delete:       "update t1 set c2=(select min(_vt_value) from _vt_agg_t1_c2 where c1=:b_c1), c3=(select count(_vt_value) from _vt_agg_t1_c3 where c1=:b_c1) where c1=:b_c1 and (:b_pk1,:b_pk2) <= (1,'aaa')",
+					AggregateStates: []*TestAggregateState{{
+						Insert:    "insert into _vt_agg_t1_c2(c1,_vt_value,_vt_count) select :a_c1, :a_c2, 1 from dual where :a_c2 is not null and (:a_pk1,:a_pk2) <= (1,'aaa') on duplicate key update _vt_count=_vt_count+1",
+						Decrement: "update _vt_agg_t1_c2 set _vt_count=_vt_count-1 where c1=:b_c1 and _vt_value=:b_c2 and (:b_pk1,:b_pk2) <= (1,'aaa')",
+						Delete:    "delete from _vt_agg_t1_c2 where c1=:b_c1 and _vt_value=:b_c2 and _vt_count=0",
+					}, {
+						Insert:    "insert into _vt_agg_t1_c3(c1,_vt_value,_vt_count) select :a_c1, :a_c3, 1 from dual where :a_c3 is not null and (:a_pk1,:a_pk2) <= (1,'aaa') on duplicate key update _vt_count=_vt_count+1",
+						Decrement: "update _vt_agg_t1_c3 set _vt_count=_vt_count-1 where c1=:b_c1 and _vt_value=:b_c3 and (:b_pk1,:b_pk2) <= (1,'aaa')",
+						Delete:    "delete from _vt_agg_t1_c3 where c1=:b_c1 and _vt_value=:b_c3 and _vt_count=0",
+					}},
+				},
+			},
+		},
 	}, {
 		input: &binlogdatapb.Filter{
 			Rules: []*binlogdatapb.Rule{{
@@ -668,6 +740,42 @@ func TestBuildPlayerPlan(t *testing.T) {
 			}},
 		},
 		err: "failed to build table replication plan for t1 table: only count(*) is supported: count(c1) in query: select count(c1) as c from t1",
+	}, {
+		// no distinct in sum
+		input: &binlogdatapb.Filter{
+			Rules: []*binlogdatapb.Rule{{
+				Match:  "t1",
+				Filter: "select sum(distinct c1) as c from t1",
+			}},
+		},
+		err: "failed to build table replication plan for t1 table: unsupported distinct expression usage: sum(distinct c1) in query: select sum(distinct c1) as c from t1",
+	}, {
+		// count distinct should have only one argument
+		input: &binlogdatapb.Filter{
+			Rules: []*binlogdatapb.Rule{{
+				Match:  "t1",
+				Filter: "select count(distinct a, b) as c from t1 group by c",
+			}},
+		},
+		err: "failed to build table replication plan for t1 table: unsupported multiple columns in count clause: count(distinct a, b) in query: select count(distinct a, b) as c from t1 group by c",
+	}, {
+		// no complex expr in min
+		input: &binlogdatapb.Filter{
+			Rules: []*binlogdatapb.Rule{{
+				Match:  "t1",
+				Filter: "select c1, min(a + b) as c from t1 group by c1",
+			}},
+		},
+		err: "failed to build table replication plan for t1 table: unsupported non-column name in min clause: min(a + b) in query: select c1, min(a + b) as c from t1 group by c1",
+	}, {
+		// max needs a group by
+		input: &binlogdatapb.Filter{
+			Rules: []*binlogdatapb.Rule{{
+				Match:  "t1",
+				Filter: "select max(a) as c from t1",
+			}},
+		},
+		err: "failed to build table replication plan for t1 table: unsupported aggregation without a group by clause: c in query: select max(a) as c from t1",
 	}, {
 		// no sum(*)
 		input: &binlogdatapb.Filter{
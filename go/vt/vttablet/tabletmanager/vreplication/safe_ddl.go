@@ -0,0 +1,217 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"fmt"
+
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+const errUnsupportedDDL = "only ALTER TABLE and CREATE TABLE statements can be compatible changes"
+
+// unsafeDDLError is returned for a DDL that makes changes to the target
+// table that are not known to be compatible with its existing rows and with
+// the rows being replicated.
+type unsafeDDLError struct {
+	reason string
+	// diff is the change the DDL makes to the target table, if known.
+	diff string
+}
+
+func (e *unsafeDDLError) Error() string {
+	if e.diff == "" {
+		return e.reason
+	}
+	return fmt.Sprintf("%s: %s", e.reason, e.diff)
+}
+
+// safeDDL returns the statements that apply the DDL to the target table, if
+// all the changes it makes are compatible. It returns an unsafeDDLError
+// otherwise.
+func (vp *vplayer) safeDDL(ctx context.Context, statement string) ([]string, error) {
+	env := vp.vr.vre.env
+	stmt, err := env.Parser().ParseStrictDDL(statement)
+	if err != nil {
+		return nil, err
+	}
+	var table string
+	switch stmt := stmt.(type) {
+	case *sqlparser.AlterTable:
+		table = stmt.Table.Name.String()
+	case *sqlparser.CreateTable:
+		table = stmt.Table.Name.String()
+	default:
+		return nil, &unsafeDDLError{reason: errUnsupportedDDL}
+	}
+	req := &tabletmanagerdatapb.GetSchemaRequest{Tables: []string{table}}
+	schema, err := vp.vr.mysqld.GetSchema(ctx, vp.vr.dbClient.DBName(), req)
+	if err != nil {
+		return nil, err
+	}
+	var targetSchema string
+	if len(schema.TableDefinitions) == 1 {
+		targetSchema = schema.TableDefinitions[0].Schema
+	}
+	return compatibleDDL(schemadiff.NewEnv(env, env.CollationEnv().DefaultConnectionCharset()), targetSchema, stmt)
+}
+
+// compatibleDDL runs a DDL through schemadiff against the schema of the
+// target table, which is empty if the table does not exist. It returns the
+// statements that bring the target table to the schema that results from
+// the DDL, which are none if the target table already has it. These are:
+//   - adding a column that is nullable or has a default value
+//   - adding a non unique index
+//   - widening the type of a column, or making it nullable
+//   - changing the default value of a column
+//   - creating a table that does not exist
+//
+// An unsafeDDLError is returned if the DDL makes any other change.
+func compatibleDDL(env *schemadiff.Environment, targetSchema string, stmt sqlparser.Statement) ([]string, error) {
+	if targetSchema == "" {
+		if _, ok := stmt.(*sqlparser.CreateTable); ok {
+			return []string{sqlparser.String(stmt)}, nil
+		}
+		return nil, &unsafeDDLError{reason: "table does not exist on the target"}
+	}
+	from, err := schemadiff.NewCreateTableEntityFromSQL(env, targetSchema)
+	if err != nil {
+		return nil, err
+	}
+	var to *schemadiff.CreateTableEntity
+	switch stmt := stmt.(type) {
+	case *sqlparser.CreateTable:
+		to, err = schemadiff.NewCreateTableEntity(env, stmt)
+		if err != nil {
+			return nil, &unsafeDDLError{reason: err.Error()}
+		}
+	case *sqlparser.AlterTable:
+		applied, err := from.Apply(schemadiff.EntityDiffByStatement(stmt))
+		if err != nil {
+			return nil, &unsafeDDLError{reason: fmt.Sprintf("cannot apply %s to the target table: %v", sqlparser.String(stmt), err)}
+		}
+		to = applied.(*schemadiff.CreateTableEntity)
+	default:
+		return nil, &unsafeDDLError{reason: errUnsupportedDDL}
+	}
+	diff, err := from.TableDiff(to, schemadiff.EmptyDiffHints())
+	if err != nil {
+		return nil, &unsafeDDLError{reason: err.Error()}
+	}
+	if diff.IsEmpty() {
+		return nil, nil
+	}
+	var statements []string
+	for _, d := range schemadiff.AllSubsequent(diff) {
+		alter := d.(*schemadiff.AlterTableEntityDiff).AlterTable()
+		if reason := incompatibleAlter(from, to, alter); reason != "" {
+			return nil, &unsafeDDLError{reason: reason, diff: d.CanonicalStatementString()}
+		}
+		statements = append(statements, d.CanonicalStatementString())
+	}
+	return statements, nil
+}
+
+// incompatibleAlter returns why an ALTER TABLE from the diff between two
+// versions of a table is not a compatible change, or an empty string if it
+// is.
+func incompatibleAlter(from, to *schemadiff.CreateTableEntity, alter *sqlparser.AlterTable) string {
+	if alter.PartitionSpec != nil || alter.PartitionOption != nil {
+		return "changes the partitioning of the table"
+	}
+	fromColumns := from.ColumnDefinitionEntitiesMap()
+	toColumns := to.ColumnDefinitionEntitiesMap()
+	for _, option := range alter.AlterOptions {
+		switch option := option.(type) {
+		case *sqlparser.AddColumns:
+			for _, col := range option.Columns {
+				c := toColumns[col.Name.Lowered()]
+				if c.IsAutoIncrement() || !(c.IsNullable() || c.HasDefault()) {
+					return fmt.Sprintf("adds column %s which is not nullable and has no default value", c.Name())
+				}
+			}
+		case *sqlparser.ModifyColumn:
+			name := option.NewColDefinition.Name.Lowered()
+			if reason := incompatibleColumnChange(fromColumns[name], toColumns[name]); reason != "" {
+				return fmt.Sprintf("changes column %s: %s", option.NewColDefinition.Name.String(), reason)
+			}
+		case *sqlparser.AlterColumn:
+			// Changes the default value or the visibility of the column.
+		case *sqlparser.AddIndexDefinition:
+			switch option.IndexDefinition.Info.Type {
+			case sqlparser.IndexTypePrimary, sqlparser.IndexTypeUnique:
+				return fmt.Sprintf("adds unique key %s", option.IndexDefinition.Info.Name.String())
+			}
+		default:
+			return fmt.Sprintf("%s is not a compatible change", sqlparser.String(option))
+		}
+	}
+	return ""
+}
+
+// incompatibleColumnChange returns why the change of a column does not keep
+// all its values, or an empty string if it does.
+func incompatibleColumnChange(from, to *schemadiff.ColumnDefinitionEntity) string {
+	if from == nil || to == nil {
+		return "column is renamed"
+	}
+	if from.IsGenerated() || to.IsGenerated() {
+		return "generated columns cannot be changed"
+	}
+	if from.IsAutoIncrement() != to.IsAutoIncrement() {
+		return "changes AUTO_INCREMENT"
+	}
+	if fromFamily, toFamily := columnTypeFamily(from), columnTypeFamily(to); fromFamily != toFamily {
+		return fmt.Sprintf("changes type %s to %s", from.Type(), to.Type())
+	}
+	if from.Charset() != to.Charset() || from.Collate() != to.Collate() {
+		return "changes the character set or collation"
+	}
+	// The change is compatible if the old column does not hold any value the
+	// new one cannot.
+	if narrows, _ := schemadiff.ColumnChangeExpandsDataRange(to, from); narrows {
+		return "the new definition cannot hold all the values of the original one"
+	}
+	return ""
+}
+
+// columnTypeFamily groups the column types whose values convert without loss
+// into the wider types of the same group.
+func columnTypeFamily(col *schemadiff.ColumnDefinitionEntity) string {
+	switch typ := col.Type(); {
+	case schemadiff.IsIntegralType(typ):
+		return "integer"
+	case schemadiff.IsFloatingPointType(typ):
+		return "float"
+	case schemadiff.IsDecimalType(typ):
+		return "decimal"
+	case typ == "char" || typ == "varchar":
+		return "char"
+	case typ == "binary" || typ == "varbinary":
+		return "binary"
+	case schemadiff.BlobTypeStorage(typ) > 0 && col.IsTextual():
+		return "text"
+	case schemadiff.BlobTypeStorage(typ) > 0:
+		return "blob"
+	default:
+		return typ
+	}
+}
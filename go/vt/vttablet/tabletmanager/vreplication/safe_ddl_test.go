@@ -0,0 +1,141 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestCompatibleDDL(t *testing.T) {
+	const target = "create table t1 (id int not null, name varchar(32) default null, price decimal(10,2) not null, kind enum('a','b'), primary key (id), key name_idx (name))"
+	testcases := []struct {
+		name   string
+		target string
+		ddl    string
+		want   []string
+		// wantErr is the reason the DDL is not compatible.
+		wantErr string
+	}{{
+		name: "add nullable column",
+		ddl:  "alter table t1 add column note text",
+		want: []string{"ALTER TABLE `t1` ADD COLUMN `note` text"},
+	}, {
+		name: "add not null column with default",
+		ddl:  "alter table t1 add column qty int not null default 0",
+		want: []string{"ALTER TABLE `t1` ADD COLUMN `qty` int NOT NULL DEFAULT 0"},
+	}, {
+		name:    "add not null column without default",
+		ddl:     "alter table t1 add column qty int not null",
+		wantErr: "adds column qty which is not nullable and has no default value",
+	}, {
+		name: "add index",
+		ddl:  "create index price_idx on t1 (price)",
+		want: []string{"ALTER TABLE `t1` ADD KEY `price_idx` (`price`)"},
+	}, {
+		name:    "add unique index",
+		ddl:     "alter table t1 add unique key name_uidx (name)",
+		wantErr: "adds unique key name_uidx",
+	}, {
+		name: "widen integer",
+		ddl:  "alter table t1 modify column id bigint not null",
+		want: []string{"ALTER TABLE `t1` MODIFY COLUMN `id` bigint NOT NULL"},
+	}, {
+		name: "widen varchar",
+		ddl:  "alter table t1 modify column name varchar(64) default null",
+		want: []string{"ALTER TABLE `t1` MODIFY COLUMN `name` varchar(64)"},
+	}, {
+		name: "widen decimal and make it nullable",
+		ddl:  "alter table t1 modify column price decimal(12,3)",
+		want: []string{"ALTER TABLE `t1` MODIFY COLUMN `price` decimal(12,3)"},
+	}, {
+		name: "append enum value",
+		ddl:  "alter table t1 modify column kind enum('a','b','c')",
+		want: []string{"ALTER TABLE `t1` MODIFY COLUMN `kind` enum('a', 'b', 'c')"},
+	}, {
+		name:    "narrow varchar",
+		ddl:     "alter table t1 modify column name varchar(16) default null",
+		wantErr: "changes column name: the new definition cannot hold all the values of the original one: ALTER TABLE `t1` MODIFY COLUMN `name` varchar(16)",
+	}, {
+		name:    "make column not null",
+		ddl:     "alter table t1 modify column name varchar(32) not null",
+		wantErr: "changes column name: the new definition cannot hold all the values of the original one",
+	}, {
+		name:    "change type",
+		ddl:     "alter table t1 modify column id varchar(32) not null",
+		wantErr: "changes column id: changes type int to varchar",
+	}, {
+		name:    "drop column",
+		ddl:     "alter table t1 drop column price",
+		wantErr: "drop column price is not a compatible change: ALTER TABLE `t1` DROP COLUMN `price`",
+	}, {
+		name:    "drop index",
+		ddl:     "alter table t1 drop key name_idx",
+		wantErr: "drop key name_idx is not a compatible change: ALTER TABLE `t1` DROP KEY `name_idx`",
+	}, {
+		name:    "drop table",
+		ddl:     "drop table t1",
+		wantErr: "only ALTER TABLE and CREATE TABLE statements can be compatible changes",
+	}, {
+		name: "already applied",
+		ddl:  "alter table t1 add key name_idx (name)",
+		// The target table already has an index with this name.
+		wantErr: "cannot apply",
+	}, {
+		name: "create identical table",
+		ddl:  target,
+	}, {
+		name: "create table with an extra column",
+		ddl:  "create table t1 (id int not null, name varchar(32) default null, price decimal(10,2) not null, kind enum('a','b'), note text, primary key (id), key name_idx (name))",
+		want: []string{"ALTER TABLE `t1` ADD COLUMN `note` text"},
+	}, {
+		name:   "create missing table",
+		target: "none",
+		ddl:    "create table t2 (id int primary key)",
+		want:   []string{"create table t2 (\n\tid int primary key\n)"},
+	}, {
+		name:    "alter missing table",
+		target:  "none",
+		ddl:     "alter table t2 add column note text",
+		wantErr: "table does not exist on the target",
+	}}
+	env := schemadiff.NewTestEnv()
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			targetSchema := target
+			if tc.target == "none" {
+				targetSchema = ""
+			}
+			stmt, err := sqlparser.NewTestParser().ParseStrictDDL(tc.ddl)
+			require.NoError(t, err)
+			got, err := compatibleDDL(env, targetSchema, stmt)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				var uerr *unsafeDDLError
+				assert.ErrorAs(t, err, &uerr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
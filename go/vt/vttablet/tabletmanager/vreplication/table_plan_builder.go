@@ -73,6 +73,7 @@ type colExpr struct {
 	// operation==opExpr: full expression is set
 	// operation==opCount: nothing is set.
 	// operation==opSum: for 'sum(a)', expr is set to 'a'.
+	// operation==opCountDistinct, opMin, opMax: for 'min(a)', expr is set to 'a'.
 	operation operation
 	// expr stores the expected field name from vstreamer and dictates
 	// the generated bindvar names, like a_col or b_col.
@@ -95,8 +96,22 @@ const (
 	opExpr = operation(iota)
 	opCount
 	opSum
+	opCountDistinct
+	opMin
+	opMax
 )
 
+// hasState returns true for the aggregations whose value cannot be updated
+// from the changed rows alone. Their values are kept in a state table and the
+// aggregation is recomputed from it.
+func (op operation) hasState() bool {
+	switch op {
+	case opCountDistinct, opMin, opMax:
+		return true
+	}
+	return false
+}
+
 // insertType describes the type of insert statement to generate.
 // Please refer to TestBuildPlayerPlan for examples.
 type insertType int
@@ -362,12 +377,9 @@ func (tpb *tablePlanBuilder) generate() *TablePlan {
 			fieldsToSkip[strings.ToLower(colInfo.Name)] = true
 		}
 	}
-	return &TablePlan{
+	tablePlan := &TablePlan{
 		TargetName:              tpb.name.String(),
 		Lastpk:                  tpb.lastpk,
-		BulkInsertFront:         tpb.generateInsertPart(sqlparser.NewTrackedBuffer(bvf.formatter)),
-		BulkInsertValues:        tpb.generateValuesPart(sqlparser.NewTrackedBuffer(bvf.formatter), bvf),
-		BulkInsertOnDup:         tpb.generateOnDupPart(sqlparser.NewTrackedBuffer(bvf.formatter)),
 		Insert:                  tpb.generateInsertStatement(),
 		Update:                  tpb.generateUpdateStatement(),
 		Delete:                  tpb.generateDeleteStatement(),
@@ -383,6 +395,18 @@ func (tpb *tablePlanBuilder) generate() *TablePlan {
 		CollationEnv:            tpb.collationEnv,
 		WorkflowConfig:          tpb.workflowConfig,
 	}
+	aggregateStates := tpb.generateAggregateStates()
+	if len(aggregateStates) == 0 {
+		tablePlan.BulkInsertFront = tpb.generateInsertPart(sqlparser.NewTrackedBuffer(bvf.formatter))
+		tablePlan.BulkInsertValues = tpb.generateValuesPart(sqlparser.NewTrackedBuffer(bvf.formatter), bvf)
+		tablePlan.BulkInsertOnDup = tpb.generateOnDupPart(sqlparser.NewTrackedBuffer(bvf.formatter), bvf)
+	} else {
+		// The aggregations kept in state tables are recomputed for every
+		// row, so rows can't be inserted in bulk.
+		tablePlan.AggregateStates = aggregateStates
+		tablePlan.MultiDelete = nil
+	}
+	return tablePlan
 }
 
 func analyzeSelectFrom(query string, parser *sqlparser.Parser) (sel *sqlparser.Select, from string, err error) {
@@ -484,6 +508,10 @@ func (tpb *tablePlanBuilder) analyzeExpr(selExpr sqlparser.SelectExpr) (*colExpr
 		}
 	}
 	if expr, ok := aliased.Expr.(sqlparser.AggrFunc); ok {
+		switch fname := expr.AggrName(); {
+		case fname == "count" && sqlparser.IsDistinct(expr), fname == "min", fname == "max":
+			return tpb.analyzeStateAggregate(cexpr, expr)
+		}
 		if sqlparser.IsDistinct(expr) {
 			return nil, fmt.Errorf("unsupported distinct expression usage: %v", sqlparser.String(expr))
 		}
@@ -534,6 +562,34 @@ func (tpb *tablePlanBuilder) analyzeExpr(selExpr sqlparser.SelectExpr) (*colExpr
 	return cexpr, nil
 }
 
+// analyzeStateAggregate analyzes the aggregations that are kept in a state
+// table: count(distinct a), min(a) and max(a).
+func (tpb *tablePlanBuilder) analyzeStateAggregate(cexpr *colExpr, expr sqlparser.AggrFunc) (*colExpr, error) {
+	fname := expr.AggrName()
+	if len(expr.GetArgs()) != 1 {
+		return nil, fmt.Errorf("unsupported multiple columns in %s clause: %v", fname, sqlparser.String(expr))
+	}
+	innerCol, ok := expr.GetArg().(*sqlparser.ColName)
+	if !ok {
+		return nil, fmt.Errorf("unsupported non-column name in %s clause: %v", fname, sqlparser.String(expr))
+	}
+	if !innerCol.Qualifier.IsEmpty() {
+		return nil, fmt.Errorf("unsupported qualifier for column: %v", sqlparser.String(innerCol))
+	}
+	switch fname {
+	case "count":
+		cexpr.operation = opCountDistinct
+	case "min":
+		cexpr.operation = opMin
+	case "max":
+		cexpr.operation = opMax
+	}
+	cexpr.expr = innerCol
+	tpb.addCol(innerCol.Name)
+	cexpr.references[innerCol.Name.String()] = true
+	return cexpr, nil
+}
+
 // addCol adds the specified column to the send query
 // if it's not already present.
 func (tpb *tablePlanBuilder) addCol(ident sqlparser.IdentifierCI) {
@@ -544,6 +600,11 @@ func (tpb *tablePlanBuilder) addCol(ident sqlparser.IdentifierCI) {
 
 func (tpb *tablePlanBuilder) analyzeGroupBy(groupBy *sqlparser.GroupBy) error {
 	if groupBy == nil {
+		for _, cexpr := range tpb.colExprs {
+			if cexpr.operation.hasState() {
+				return fmt.Errorf("unsupported aggregation without a group by clause: %v", cexpr.colName)
+			}
+		}
 		// If there's no grouping, the it's an insertNormal.
 		return nil
 	}
@@ -681,7 +742,7 @@ func (tpb *tablePlanBuilder) generateInsertStatement() *sqlparser.ParsedQuery {
 		// where the pks < lastpk
 		tpb.generateSelectPart(buf, bvf)
 	}
-	tpb.generateOnDupPart(buf)
+	tpb.generateOnDupPart(buf, bvf)
 
 	return buf.ParsedQuery()
 }
@@ -734,6 +795,10 @@ func (tpb *tablePlanBuilder) generateValuesPart(buf *sqlparser.TrackedBuffer, bv
 		case opSum:
 			// NULL values must be treated as 0 for SUM.
 			buf.Myprintf("ifnull(%v, 0)", cexpr.expr)
+		case opCountDistinct:
+			buf.Myprintf("if(%v is null, 0, 1)", cexpr.expr)
+		case opMin, opMax:
+			buf.Myprintf("%v", cexpr.expr)
 		}
 	}
 	buf.Myprintf(")")
@@ -757,6 +822,10 @@ func (tpb *tablePlanBuilder) generateSelectPart(buf *sqlparser.TrackedBuffer, bv
 			buf.WriteString("1")
 		case opSum:
 			buf.Myprintf("ifnull(%v, 0)", cexpr.expr)
+		case opCountDistinct:
+			buf.Myprintf("if(%v is null, 0, 1)", cexpr.expr)
+		case opMin, opMax:
+			buf.Myprintf("%v", cexpr.expr)
 		}
 	}
 	buf.WriteString(" from dual where ")
@@ -764,7 +833,7 @@ func (tpb *tablePlanBuilder) generateSelectPart(buf *sqlparser.TrackedBuffer, bv
 	return buf.ParsedQuery()
 }
 
-func (tpb *tablePlanBuilder) generateOnDupPart(buf *sqlparser.TrackedBuffer) *sqlparser.ParsedQuery {
+func (tpb *tablePlanBuilder) generateOnDupPart(buf *sqlparser.TrackedBuffer, bvf *bindvarFormatter) *sqlparser.ParsedQuery {
 	if tpb.onInsert != insertOnDup {
 		return nil
 	}
@@ -794,6 +863,9 @@ func (tpb *tablePlanBuilder) generateOnDupPart(buf *sqlparser.TrackedBuffer) *sq
 		case opSum:
 			buf.Myprintf("%v", cexpr.colName)
 			buf.Myprintf("+ifnull(values(%v), 0)", cexpr.colName)
+		case opCountDistinct, opMin, opMax:
+			bvf.mode = bvAfter
+			tpb.generateStateValue(buf, cexpr)
 		}
 	}
 	return buf.ParsedQuery()
@@ -842,6 +914,9 @@ func (tpb *tablePlanBuilder) generateUpdateStatement() *sqlparser.ParsedQuery {
 			buf.Myprintf("-ifnull(%v, 0)", cexpr.expr)
 			bvf.mode = bvAfter
 			buf.Myprintf("+ifnull(%v, 0)", cexpr.expr)
+		case opCountDistinct, opMin, opMax:
+			bvf.mode = bvAfter
+			tpb.generateStateValue(buf, cexpr)
 		}
 	}
 	tpb.generateWhere(buf, bvf)
@@ -872,6 +947,8 @@ func (tpb *tablePlanBuilder) generateDeleteStatement() *sqlparser.ParsedQuery {
 				buf.Myprintf("%v-1", cexpr.colName)
 			case opSum:
 				buf.Myprintf("%v-ifnull(%v, 0)", cexpr.colName, cexpr.expr)
+			case opCountDistinct, opMin, opMax:
+				tpb.generateStateValue(buf, cexpr)
 			}
 		}
 		tpb.generateWhere(buf, bvf)
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"golang.org/x/exp/maps"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/encoding/prototext"
 
 	"vitess.io/vitess/go/bytes2"
@@ -47,6 +48,22 @@ import (
 type vcopier struct {
 	vr               *vreplicator
 	throttlerAppName string
+	// snapshotPositions contains the snapshot positions of the tables that
+	// are copied alongside other tables, see copyNext.
+	snapshotPositions map[string]replication.Position
+}
+
+// vcopierTableCopy describes the copy of a table's rows from a snapshot.
+type vcopierTableCopy struct {
+	tableName string
+	lastpk    *querypb.QueryResult
+	// concurrent is set for a table that is copied alongside the table that
+	// the copy phase is working on. Its rows are copied using its own
+	// connections, as the workflow's db client is used by the other table.
+	concurrent bool
+	// onSnapshot is called with the position of the snapshot before any
+	// rows are copied.
+	onSnapshot func(gtid string) error
 }
 
 // vcopierCopyTask stores the args and lifecycle hooks of a copy task.
@@ -143,8 +160,9 @@ type vcopierCopyWorker struct {
 
 func newVCopier(vr *vreplicator) *vcopier {
 	return &vcopier{
-		vr:               vr,
-		throttlerAppName: throttlerapp.VCopierName.ConcatenateString(vr.throttlerAppName()),
+		vr:                vr,
+		throttlerAppName:  throttlerapp.VCopierName.ConcatenateString(vr.throttlerAppName()),
+		snapshotPositions: make(map[string]replication.Position),
 	}
 }
 
@@ -289,16 +307,37 @@ func (vc *vcopier) initTablesForCopy(ctx context.Context) error {
 // copyNext also builds the copyState metadata that contains the tables and their last
 // primary key that was copied. A nil Result means that nothing has been copied.
 // A table that was fully copied is removed from copyState.
+//
+// When more than one concurrent table copy is configured, tables that have not been
+// copied yet are copied alongside the table from steps 2 to 4, each from its own
+// snapshot that is taken once the target has been fast-forwarded. The position of
+// such a snapshot is saved in copy_state along with the lastpk, and the events up to
+// it are skipped for the table, as the copied rows already reflect them. A table
+// whose rows were all copied this way is only removed from copy_state once the
+// target's position has reached its snapshot.
 func (vc *vcopier) copyNext(ctx context.Context, settings binlogplayer.VRSettings) error {
-	qr, err := vc.vr.dbClient.Execute(fmt.Sprintf("select table_name, lastpk from _vt.copy_state where vrepl_id = %d and id in (select max(id) from _vt.copy_state group by vrepl_id, table_name) order by table_name", vc.vr.id))
+	qr, err := vc.vr.dbClient.Execute(fmt.Sprintf("select table_name, lastpk, snapshot_pos from _vt.copy_state where vrepl_id = %d and id in (select max(id) from _vt.copy_state group by vrepl_id, table_name) order by table_name", vc.vr.id))
 	if err != nil {
 		return err
 	}
 	var tableToCopy string
+	var copiedTables []string
 	copyState := make(map[string]*sqltypes.Result)
 	for _, row := range qr.Rows {
 		tableName := row[0].ToString()
 		lastpk := row[1].ToString()
+		if snapshotPos := row[2].ToString(); snapshotPos != "" {
+			pos, err := binlogplayer.DecodePosition(snapshotPos)
+			if err != nil {
+				return err
+			}
+			vc.snapshotPositions[tableName] = pos
+			if lastpk == "" {
+				// All the rows of the table were copied from its snapshot.
+				copiedTables = append(copiedTables, tableName)
+				continue
+			}
+		}
 		if tableToCopy == "" {
 			tableToCopy = tableName
 		}
@@ -311,12 +350,18 @@ func (vc *vcopier) copyNext(ctx context.Context, settings binlogplayer.VRSetting
 			copyState[tableName] = sqltypes.Proto3ToResult(&r)
 		}
 	}
-	if len(copyState) == 0 {
+	if len(copyState) == 0 && len(copiedTables) == 0 {
 		return fmt.Errorf("unexpected: there are no tables to copy")
 	}
 	if err := vc.catchup(ctx, copyState); err != nil {
 		return err
 	}
+	if err := vc.finishCopiedTables(ctx, copyState, copiedTables); err != nil {
+		return err
+	}
+	if tableToCopy == "" {
+		return nil
+	}
 	return vc.copyTable(ctx, tableToCopy, copyState)
 }
 
@@ -341,7 +386,7 @@ func (vc *vcopier) catchup(ctx context.Context, copyState map[string]*sqltypes.R
 	// Start vreplication.
 	errch := make(chan error, 1)
 	go func() {
-		errch <- newVPlayer(vc.vr, settings, copyState, replication.Position{}, "catchup").play(ctx)
+		errch <- vc.newVPlayer(settings, copyState, replication.Position{}, "catchup").play(ctx)
 	}()
 
 	// Wait for catchup.
@@ -371,6 +416,39 @@ func (vc *vcopier) catchup(ctx context.Context, copyState map[string]*sqltypes.R
 	}
 }
 
+// finishCopiedTables completes the copy of the tables whose rows were all copied
+// alongside another table, once the target's position has reached the snapshots
+// they were copied from. If no other table is left to copy, the target is
+// fast-forwarded to the snapshots that have not been reached yet.
+func (vc *vcopier) finishCopiedTables(ctx context.Context, copyState map[string]*sqltypes.Result, tableNames []string) error {
+	if len(tableNames) == 0 {
+		return nil
+	}
+	settings, err := binlogplayer.ReadVRSettings(vc.vr.dbClient, vc.vr.id)
+	if err != nil {
+		return err
+	}
+	var pendingPos replication.Position
+	for _, tableName := range tableNames {
+		snapshotPos := vc.snapshotPositions[tableName]
+		if !settings.StartPos.AtLeast(snapshotPos) {
+			if !pendingPos.AtLeast(snapshotPos) {
+				pendingPos = snapshotPos
+			}
+			continue
+		}
+		if err := vc.finishTable(ctx, tableName); err != nil {
+			return err
+		}
+		delete(vc.snapshotPositions, tableName)
+		log.Infof("Copy of %v finished at snapshot position %v", tableName, snapshotPos)
+	}
+	if len(copyState) != 0 || pendingPos.IsZero() {
+		return nil
+	}
+	return vc.fastForward(ctx, copyState, replication.EncodePosition(pendingPos))
+}
+
 // copyTable performs the synchronized copy of the next set of rows from
 // the current table being copied. Each packet received is transactionally
 // committed with the lastpk. This allows for consistent resumability.
@@ -386,11 +464,6 @@ func (vc *vcopier) copyTable(ctx context.Context, tableName string, copyState ma
 		return err
 	}
 
-	initialPlan, ok := plan.TargetTables[tableName]
-	if !ok {
-		return fmt.Errorf("plan not found for table: %s, current plans are: %#v", tableName, plan.TargetTables)
-	}
-
 	ctx, cancel := context.WithTimeout(ctx, vc.vr.workflowConfig.CopyPhaseDuration)
 	defer cancel()
 
@@ -399,13 +472,201 @@ func (vc *vcopier) copyTable(ctx context.Context, tableName string, copyState ma
 		lastpkpb = sqltypes.ResultToProto3(lastpkqr)
 	}
 
+	// The tables copied alongside this one report errors through this group.
+	var concurrentCopies errgroup.Group
+	lastpk, pkfields, err := vc.copyTableRows(ctx, plan, &vcopierTableCopy{
+		tableName: tableName,
+		lastpk:    lastpkpb,
+		onSnapshot: func(gtid string) error {
+			if err := vc.fastForward(ctx, copyState, gtid); err != nil {
+				return err
+			}
+			// The target is now at the position of this table's snapshot, so
+			// the snapshots of the tables copied alongside it are taken at or
+			// after the target's position.
+			pos, err := replication.DecodePosition(gtid)
+			if err != nil {
+				return err
+			}
+			for _, concurrentTable := range vc.concurrentTables(copyState, tableName) {
+				concurrentCopies.Go(func() error {
+					if err := vc.copyConcurrentTable(ctx, plan, concurrentTable, pos); err != nil {
+						cancel()
+						return vterrors.Wrapf(err, "failed to copy table %q", concurrentTable)
+					}
+					return nil
+				})
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		cancel()
+	}
+	if cerr := concurrentCopies.Wait(); cerr != nil {
+		return cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	// Get the last committed pk into a loggable form.
+	lastpkbuf, merr := prototext.Marshal(&querypb.QueryResult{
+		Fields: pkfields,
+		Rows:   []*querypb.Row{lastpk},
+	})
+	if merr != nil {
+		return fmt.Errorf("failed to marshal pk fields and value into query result: %s", merr.Error())
+	}
+	lastpkbv := map[string]*querypb.BindVariable{
+		"lastpk": {
+			Type:  sqltypes.VarBinary,
+			Value: lastpkbuf,
+		},
+	}
+
+	// A context expiration was probably caused by a PlannedReparentShard or an
+	// elapsed copy phase duration. Those are normal, non-error interruptions
+	// of a copy phase.
+	select {
+	case <-ctx.Done():
+		log.Infof("Copy of %v stopped at lastpk: %v", tableName, lastpkbv)
+		return nil
+	default:
+	}
+
+	log.Infof("Copy of %v finished at lastpk: %v", tableName, lastpkbv)
+	if err := vc.finishTable(ctx, tableName); err != nil {
+		return err
+	}
+	// The table may have been partly copied alongside another table before,
+	// in which case its rows have since been copied from a snapshot at or after
+	// the target's position and there are no events left to skip for it.
+	delete(vc.snapshotPositions, tableName)
+	return nil
+}
+
+// copyConcurrentTable copies a table that has not been copied yet alongside the
+// table that the copy phase is working on. pos is the target's position, which
+// the table's snapshot must have reached. As the target stays at pos while the
+// table is copied, the table's snapshot position is saved along with each lastpk
+// and, once all the rows have been copied, on its own.
+func (vc *vcopier) copyConcurrentTable(ctx context.Context, plan *ReplicatorPlan, tableName string, pos replication.Position) error {
+	log.Infof("Copying table %s alongside other tables at position %v", tableName, pos)
+
+	var snapshotPos string
+	_, _, err := vc.copyTableRows(ctx, plan, &vcopierTableCopy{
+		tableName:  tableName,
+		concurrent: true,
+		onSnapshot: func(gtid string) error {
+			tablePos, err := replication.DecodePosition(gtid)
+			if err != nil {
+				return err
+			}
+			if !tablePos.AtLeast(pos) {
+				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "snapshot position %v of table %s is behind the workflow position %v",
+					tablePos, tableName, pos)
+			}
+			snapshotPos = gtid
+			return nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		log.Infof("Copy of %v stopped", tableName)
+		return nil
+	default:
+	}
+
+	dbClient, err := vc.vr.newClientConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer dbClient.Close()
+	if err := dbClient.Begin(); err != nil {
+		return err
+	}
+	defer dbClient.Rollback()
+	if _, err := dbClient.Execute(fmt.Sprintf("delete from _vt.copy_state where vrepl_id = %d and table_name = %s",
+		vc.vr.id, encodeString(tableName))); err != nil {
+		return err
+	}
+	if _, err := dbClient.Execute(fmt.Sprintf("insert into _vt.copy_state (vrepl_id, table_name, snapshot_pos) values (%d, %s, %s)",
+		vc.vr.id, encodeString(tableName), encodeString(snapshotPos))); err != nil {
+		return err
+	}
+	if err := dbClient.Commit(); err != nil {
+		return err
+	}
+	log.Infof("Copied all rows of %v from snapshot position %v", tableName, snapshotPos)
+	return nil
+}
+
+// concurrentTables returns the tables to copy alongside tableName. Only the
+// tables that have not been copied at all yet can be, as the rows that were
+// already copied are at the target's position rather than at the table's
+// snapshot position.
+func (vc *vcopier) concurrentTables(copyState map[string]*sqltypes.Result, tableName string) []string {
+	maxTables := vc.vr.workflowConfig.ConcurrentTableCopies - 1
+	if maxTables <= 0 {
+		return nil
+	}
+	names := maps.Keys(copyState)
+	slices.Sort(names)
+	var tableNames []string
+	for _, name := range names {
+		if len(tableNames) == maxTables {
+			break
+		}
+		if _, ok := vc.snapshotPositions[name]; ok || name == tableName || copyState[name] != nil {
+			continue
+		}
+		tableNames = append(tableNames, name)
+	}
+	return tableNames
+}
+
+// finishTable performs the post copy actions of a table whose rows have all
+// been copied and removes it from copy_state.
+func (vc *vcopier) finishTable(ctx context.Context, tableName string) error {
+	// Perform any post copy actions
+	if err := vc.vr.execPostCopyActions(ctx, tableName); err != nil {
+		return vterrors.Wrapf(err, "failed to execute post copy actions for table %q", tableName)
+	}
+
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf(
+		"delete cs, pca from _vt.%s as cs left join _vt.%s as pca on cs.vrepl_id=pca.vrepl_id and cs.table_name=pca.table_name where cs.vrepl_id=%d and cs.table_name=%s",
+		copyStateTableName, postCopyActionTableName,
+		vc.vr.id, encodeString(tableName),
+	)
+	if _, err := vc.vr.dbClient.Execute(buf.String()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// copyTableRows streams the rows of a table from a new snapshot and copies them
+// until all rows are copied, or the context expires. It returns the last
+// committed pk along with the pk fields.
+func (vc *vcopier) copyTableRows(ctx context.Context, plan *ReplicatorPlan, tc *vcopierTableCopy) (*querypb.Row, []*querypb.Field, error) {
+	tableName := tc.tableName
+	initialPlan, ok := plan.TargetTables[tableName]
+	if !ok {
+		return nil, nil, fmt.Errorf("plan not found for table: %s, current plans are: %#v", tableName, plan.TargetTables)
+	}
+
 	rowsCopiedTicker := time.NewTicker(rowsCopiedUpdateInterval)
 	defer rowsCopiedTicker.Stop()
 	copyStateGCTicker := time.NewTicker(copyStateGCInterval)
 	defer copyStateGCTicker.Stop()
 
 	parallelism := int(math.Max(1, float64(vc.vr.workflowConfig.ParallelInsertWorkers)))
-	copyWorkerFactory := vc.newCopyWorkerFactory(parallelism)
+	copyWorkerFactory := vc.newCopyWorkerFactory(parallelism, tc.concurrent)
 	copyWorkQueue := vc.newCopyWorkQueue(parallelism, copyWorkerFactory)
 	defer copyWorkQueue.close()
 
@@ -422,12 +683,14 @@ func (vc *vcopier) copyTable(ctx context.Context, tableName string, copyState ma
 	vstreamOptions := &binlogdatapb.VStreamOptions{
 		ConfigOverrides: vc.vr.workflowConfig.Overrides,
 	}
-	serr := vc.vr.sourceVStreamer.VStreamRows(ctx, initialPlan.SendRule.Filter, lastpkpb, func(rows *binlogdatapb.VStreamRowsResponse) error {
+	serr := vc.vr.sourceVStreamer.VStreamRows(ctx, initialPlan.SendRule.Filter, tc.lastpk, func(rows *binlogdatapb.VStreamRowsResponse) error {
 		for {
 			select {
 			case <-rowsCopiedTicker.C:
-				update := binlogplayer.GenerateUpdateRowsCopied(vc.vr.id, vc.vr.stats.CopyRowCount.Get())
-				_, _ = vc.vr.dbClient.Execute(update)
+				if !tc.concurrent {
+					update := binlogplayer.GenerateUpdateRowsCopied(vc.vr.id, vc.vr.stats.CopyRowCount.Get())
+					_, _ = vc.vr.dbClient.Execute(update)
+				}
 			case <-ctx.Done():
 				return io.EOF
 			default:
@@ -457,18 +720,25 @@ func (vc *vcopier) copyTable(ctx context.Context, tableName string, copyState ma
 				return io.EOF
 			default:
 			}
+			// Only the copy of the first table updates the workflow's
+			// throttling and heartbeat times, as the others can't use the
+			// workflow's db client.
 			if rows.Throttled {
-				_ = vc.vr.updateTimeThrottled(throttlerapp.RowStreamerName, rows.ThrottledReason)
+				if !tc.concurrent {
+					_ = vc.vr.updateTimeThrottled(throttlerapp.RowStreamerName, rows.ThrottledReason)
+				}
 				return nil
 			}
 			if rows.Heartbeat {
-				_ = vc.vr.updateHeartbeatTime(time.Now().Unix())
+				if !tc.concurrent {
+					_ = vc.vr.updateHeartbeatTime(time.Now().Unix())
+				}
 				return nil
 			}
 			// verify throttler is happy, otherwise keep looping
 			if checkResult, ok := vc.vr.vre.throttlerClient.ThrottleCheckOKOrWaitAppName(ctx, throttlerapp.Name(vc.throttlerAppName)); ok {
 				break // out of 'for' loop
-			} else { // we're throttled
+			} else if !tc.concurrent { // we're throttled
 				_ = vc.vr.updateTimeThrottled(throttlerapp.VCopierName, checkResult.Summary())
 			}
 		}
@@ -476,7 +746,7 @@ func (vc *vcopier) copyTable(ctx context.Context, tableName string, copyState ma
 			if len(rows.Fields) == 0 {
 				return fmt.Errorf("expecting field event first, got: %v", rows)
 			}
-			if err := vc.fastForward(ctx, copyState, rows.Gtid); err != nil {
+			if err := tc.onSnapshot(rows.Gtid); err != nil {
 				return err
 			}
 			fieldEvent := &binlogdatapb.FieldEvent{
@@ -493,10 +763,18 @@ func (vc *vcopier) copyTable(ctx context.Context, tableName string, copyState ma
 				pkfields = append(pkfields, f.CloneVT())
 			}
 			buf := sqlparser.NewTrackedBuffer(nil)
-			buf.Myprintf(
-				"insert into _vt.copy_state (lastpk, vrepl_id, table_name) values (%a, %s, %s)", ":lastpk",
-				strconv.Itoa(int(vc.vr.id)),
-				encodeString(tableName))
+			if tc.concurrent {
+				buf.Myprintf(
+					"insert into _vt.copy_state (lastpk, vrepl_id, table_name, snapshot_pos) values (%a, %s, %s, %s)", ":lastpk",
+					strconv.Itoa(int(vc.vr.id)),
+					encodeString(tableName),
+					encodeString(rows.Gtid))
+			} else {
+				buf.Myprintf(
+					"insert into _vt.copy_state (lastpk, vrepl_id, table_name) values (%a, %s, %s)", ":lastpk",
+					strconv.Itoa(int(vc.vr.id)),
+					encodeString(tableName))
+			}
 			addLatestCopyState := buf.ParsedQuery()
 			copyWorkQueue.open(addLatestCopyState, pkfields, tablePlan)
 		}
@@ -628,22 +906,7 @@ func (vc *vcopier) copyTable(ctx context.Context, tableName string, copyState ma
 	if len(terrs) > 0 {
 		terr := vterrors.Aggregate(terrs)
 		log.Warningf("task error in workflow %s: %v", vc.vr.WorkflowName, terr)
-		return vterrors.Wrapf(terr, "task error")
-	}
-
-	// Get the last committed pk into a loggable form.
-	lastpkbuf, merr := prototext.Marshal(&querypb.QueryResult{
-		Fields: pkfields,
-		Rows:   []*querypb.Row{lastpk},
-	})
-	if merr != nil {
-		return fmt.Errorf("failed to marshal pk fields and value into query result: %s", merr.Error())
-	}
-	lastpkbv := map[string]*querypb.BindVariable{
-		"lastpk": {
-			Type:  sqltypes.VarBinary,
-			Value: lastpkbuf,
-		},
+		return nil, nil, vterrors.Wrapf(terr, "task error")
 	}
 
 	// A context expiration was probably caused by a PlannedReparentShard or an
@@ -651,31 +914,13 @@ func (vc *vcopier) copyTable(ctx context.Context, tableName string, copyState ma
 	// of a copy phase.
 	select {
 	case <-ctx.Done():
-		log.Infof("Copy of %v stopped at lastpk: %v", tableName, lastpkbv)
-		return nil
+		return lastpk, pkfields, nil
 	default:
 	}
 	if serr != nil {
-		return serr
-	}
-
-	// Perform any post copy actions
-	if err := vc.vr.execPostCopyActions(ctx, tableName); err != nil {
-		return vterrors.Wrapf(err, "failed to execute post copy actions for table %q", tableName)
-	}
-
-	log.Infof("Copy of %v finished at lastpk: %v", tableName, lastpkbv)
-	buf := sqlparser.NewTrackedBuffer(nil)
-	buf.Myprintf(
-		"delete cs, pca from _vt.%s as cs left join _vt.%s as pca on cs.vrepl_id=pca.vrepl_id and cs.table_name=pca.table_name where cs.vrepl_id=%d and cs.table_name=%s",
-		copyStateTableName, postCopyActionTableName,
-		vc.vr.id, encodeString(tableName),
-	)
-	if _, err := vc.vr.dbClient.Execute(buf.String()); err != nil {
-		return err
+		return nil, nil, serr
 	}
-
-	return nil
+	return lastpk, pkfields, nil
 }
 
 // updatePos is called after the last table is copied in an atomic copy, to set the gtid so that the replicating phase
@@ -705,7 +950,15 @@ func (vc *vcopier) fastForward(ctx context.Context, copyState map[string]*sqltyp
 		_, err := vc.vr.dbClient.Execute(update)
 		return err
 	}
-	return newVPlayer(vc.vr, settings, copyState, pos, "fastforward").play(ctx)
+	return vc.newVPlayer(settings, copyState, pos, "fastforward").play(ctx)
+}
+
+// newVPlayer creates a vplayer that skips the events that are part of the
+// snapshots of the tables copied alongside other tables.
+func (vc *vcopier) newVPlayer(settings binlogplayer.VRSettings, copyState map[string]*sqltypes.Result, pausePos replication.Position, phase string) *vplayer {
+	vp := newVPlayer(vc.vr, settings, copyState, pausePos, phase)
+	vp.snapshotPositions = vc.snapshotPositions
+	return vp
 }
 
 func (vc *vcopier) newCopyWorkQueue(
@@ -716,8 +969,11 @@ func (vc *vcopier) newCopyWorkQueue(
 	return newVCopierCopyWorkQueue(concurrent, parallelism, workerFactory)
 }
 
-func (vc *vcopier) newCopyWorkerFactory(parallelism int) func(context.Context) (*vcopierCopyWorker, error) {
-	if parallelism > 1 {
+// newCopyWorkerFactory returns a factory of copy workers. The workers use
+// their own db clients if there is more than one of them, or if ownDBClient
+// is set.
+func (vc *vcopier) newCopyWorkerFactory(parallelism int, ownDBClient bool) func(context.Context) (*vcopierCopyWorker, error) {
+	if parallelism > 1 || ownDBClient {
 		return func(ctx context.Context) (*vcopierCopyWorker, error) {
 			dbClient, err := vc.vr.newClientConnection(ctx)
 			if err != nil {
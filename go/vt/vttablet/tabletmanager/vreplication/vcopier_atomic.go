@@ -85,7 +85,7 @@ func (vc *vcopier) copyAll(ctx context.Context, settings binlogplayer.VRSettings
 
 	parallelism := int(math.Max(1, float64(vc.vr.workflowConfig.ParallelInsertWorkers)))
 
-	copyWorkerFactory := vc.newCopyWorkerFactory(parallelism)
+	copyWorkerFactory := vc.newCopyWorkerFactory(parallelism, false)
 	var copyWorkQueue *vcopierCopyWorkQueue
 
 	// Allocate a result channel to collect results from tasks. To not block fast workers, we allocate a buffer of
@@ -22,6 +22,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -32,6 +33,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/binlog/binlogplayer"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
@@ -1861,3 +1863,369 @@ func testCopyInvisibleColumns(t *testing.T) {
 		{"2", "20", "200", "2000"},
 	})
 }
+
+// TestPlayerCopyTablesConcurrently tests that tables are copied at the same
+// time, each from its own snapshot, and that the events that are part of a
+// table's snapshot are not applied to it again.
+func TestPlayerCopyTablesConcurrently(t *testing.T) {
+	testVcopierTestCases(t, testPlayerCopyTablesConcurrently, commonVcopierTestCases())
+}
+
+func testPlayerCopyTablesConcurrently(t *testing.T) {
+	doNotLogDBQueries = true
+	defer func() { doNotLogDBQueries = false }()
+	defer deleteTablet(addTablet(100))
+
+	savedConcurrentTableCopies := vttablet.DefaultVReplicationConfig.ConcurrentTableCopies
+	vttablet.DefaultVReplicationConfig.ConcurrentTableCopies = 2
+	defer func() { vttablet.DefaultVReplicationConfig.ConcurrentTableCopies = savedConcurrentTableCopies }()
+
+	execStatements(t, []string{
+		"create table src1(id int, val varchar(128), primary key(id))",
+		"insert into src1 values(1, 'aaa'), (2, 'bbb')",
+		fmt.Sprintf("create table %s.dst1(id int, val varchar(128), primary key(id))", vrepldb),
+		"create table src2(id int, val varchar(128), primary key(id))",
+		"insert into src2 values(1, 'aaa'), (2, 'bbb')",
+		fmt.Sprintf("create table %s.dst2(id int, val varchar(128), primary key(id))", vrepldb),
+	})
+	defer execStatements(t, []string{
+		"drop table src1",
+		fmt.Sprintf("drop table %s.dst1", vrepldb),
+		"drop table src2",
+		fmt.Sprintf("drop table %s.dst2", vrepldb),
+	})
+
+	// dst1 is copied first and dst2 alongside it. Modify src2 just before its
+	// snapshot is taken, so that its snapshot is ahead of dst1's and the
+	// modifications are part of it. Applying them again to dst2 would fail on
+	// the duplicate key.
+	var vstreamRowsCalls atomic.Int64
+	vstreamRowsHook = func(context.Context) {
+		if vstreamRowsCalls.Add(1) != 2 {
+			return
+		}
+		execStatements(t, []string{
+			"insert into src2 values(3, 'ccc')",
+			"update src2 set val='bbb2' where id=2",
+		})
+	}
+	defer func() { vstreamRowsHook = nil }()
+
+	filter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match:  "dst1",
+			Filter: "select * from src1",
+		}, {
+			Match:  "dst2",
+			Filter: "select * from src2",
+		}},
+	}
+	bls := &binlogdatapb.BinlogSource{
+		Keyspace: env.KeyspaceName,
+		Shard:    env.ShardName,
+		Filter:   filter,
+		OnDdl:    binlogdatapb.OnDDLAction_IGNORE,
+	}
+	query := binlogplayer.CreateVReplicationState("test", bls, "", binlogdatapb.VReplicationWorkflowState_Init, playerEngine.dbName, 0, 0)
+	qr, err := playerEngine.Exec(query)
+	require.NoError(t, err)
+	defer func() {
+		query := fmt.Sprintf("delete from _vt.vreplication where id = %d", qr.InsertID)
+		_, err := playerEngine.Exec(query)
+		require.NoError(t, err)
+	}()
+
+	expectWorkflowState(t, qr.InsertID, binlogdatapb.VReplicationWorkflowState_Running)
+	require.EqualValues(t, 2, vstreamRowsCalls.Load())
+	expectData(t, "_vt.copy_state", [][]string{})
+	expectData(t, "dst1", [][]string{
+		{"1", "aaa"},
+		{"2", "bbb"},
+	})
+	expectData(t, "dst2", [][]string{
+		{"1", "aaa"},
+		{"2", "bbb2"},
+		{"3", "ccc"},
+	})
+
+	// The events that follow the snapshots are applied to both tables.
+	execStatements(t, []string{
+		"insert into src1 values(3, 'ccc')",
+		"update src2 set val='ccc2' where id=3",
+	})
+	expectData(t, "dst1", [][]string{
+		{"1", "aaa"},
+		{"2", "bbb"},
+		{"3", "ccc"},
+	})
+	expectData(t, "dst2", [][]string{
+		{"1", "aaa"},
+		{"2", "bbb2"},
+		{"3", "ccc2"},
+	})
+}
+
+// TestPlayerCopyTablesConcurrentlyCatchup tests that the catchup of a table whose
+// rows were all copied from its own snapshot skips the events up to and including
+// the snapshot position, and applies the events that follow it.
+func TestPlayerCopyTablesConcurrentlyCatchup(t *testing.T) {
+	testVcopierTestCases(t, testPlayerCopyTablesConcurrentlyCatchup, []vcopierTestCase{
+		{
+			vreplicationExperimentalFlags: 0,
+		},
+	})
+}
+
+func testPlayerCopyTablesConcurrentlyCatchup(t *testing.T) {
+	doNotLogDBQueries = true
+	defer func() { doNotLogDBQueries = false }()
+	defer deleteTablet(addTablet(100))
+
+	execStatements(t, []string{
+		// not_copied has not been copied yet.
+		"create table not_copied(id int, val varchar(128), primary key(id))",
+		"insert into not_copied values(1, 'aaa')",
+		fmt.Sprintf("create table %s.not_copied(id int, val varchar(128), primary key(id))", vrepldb),
+		// snapshot_copied was fully copied from a snapshot taken after pos.
+		"create table snapshot_copied(id int, val varchar(128), primary key(id))",
+		"insert into snapshot_copied values(1, 'aaa')",
+		fmt.Sprintf("create table %s.snapshot_copied(id int, val varchar(128), primary key(id))", vrepldb),
+	})
+	defer execStatements(t, []string{
+		"drop table not_copied",
+		fmt.Sprintf("drop table %s.not_copied", vrepldb),
+		"drop table snapshot_copied",
+		fmt.Sprintf("drop table %s.snapshot_copied", vrepldb),
+	})
+
+	filter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match:  "not_copied",
+			Filter: "select * from not_copied",
+		}, {
+			Match:  "snapshot_copied",
+			Filter: "select * from snapshot_copied",
+		}},
+	}
+	pos := primaryPosition(t)
+	// These events are part of the snapshot.
+	execStatements(t, []string{
+		"insert into snapshot_copied values(2, 'bbb')",
+		"update snapshot_copied set val='aaa2' where id=1",
+	})
+	snapshotPos := primaryPosition(t)
+	execStatements(t, []string{
+		fmt.Sprintf("insert into %s.snapshot_copied values(1, 'aaa2'), (2, 'bbb')", vrepldb),
+	})
+	// These events follow the snapshot.
+	execStatements(t, []string{
+		"insert into snapshot_copied values(3, 'ccc')",
+		"update snapshot_copied set val='bbb2' where id=2",
+		"update not_copied set val='aaa2' where id=1",
+	})
+
+	bls := &binlogdatapb.BinlogSource{
+		Keyspace: env.KeyspaceName,
+		Shard:    env.ShardName,
+		Filter:   filter,
+		OnDdl:    binlogdatapb.OnDDLAction_IGNORE,
+	}
+	query := binlogplayer.CreateVReplicationState("test", bls, "", binlogdatapb.VReplicationWorkflowState_Stopped, playerEngine.dbName, 0, 0)
+	qr, err := playerEngine.Exec(query)
+	require.NoError(t, err)
+	id := qr.InsertID
+	execStatements(t, []string{
+		fmt.Sprintf("insert into _vt.copy_state (vrepl_id, table_name, lastpk) values(%d, '%s', null)", id, "not_copied"),
+		fmt.Sprintf("insert into _vt.copy_state (vrepl_id, table_name, lastpk, snapshot_pos) values(%d, '%s', null, %s)", id, "snapshot_copied", encodeString(snapshotPos)),
+	})
+	_, err = playerEngine.Exec(fmt.Sprintf("update _vt.vreplication set state='Copying', pos=%s where id=%d", encodeString(pos), id))
+	require.NoError(t, err)
+	defer func() {
+		query := fmt.Sprintf("delete from _vt.vreplication where id = %d", id)
+		_, err := playerEngine.Exec(query)
+		require.NoError(t, err)
+	}()
+
+	expectWorkflowState(t, id, binlogdatapb.VReplicationWorkflowState_Running)
+	expectData(t, "_vt.copy_state", [][]string{})
+	expectData(t, "snapshot_copied", [][]string{
+		{"1", "aaa2"},
+		{"2", "bbb2"},
+		{"3", "ccc"},
+	})
+	expectData(t, "not_copied", [][]string{
+		{"1", "aaa2"},
+	})
+}
+
+// TestPlayerCopyTablesConcurrentlyContinuation tests that a copy resumes
+// correctly after a restart that interrupted the copy of a table alongside
+// another table, from the lastpk and snapshot position saved in copy_state.
+func TestPlayerCopyTablesConcurrentlyContinuation(t *testing.T) {
+	testVcopierTestCases(t, testPlayerCopyTablesConcurrentlyContinuation, []vcopierTestCase{
+		{
+			vreplicationExperimentalFlags: 0,
+		},
+	})
+}
+
+func testPlayerCopyTablesConcurrentlyContinuation(t *testing.T) {
+	doNotLogDBQueries = true
+	defer func() { doNotLogDBQueries = false }()
+	defer deleteTablet(addTablet(100))
+
+	savedConcurrentTableCopies := vttablet.DefaultVReplicationConfig.ConcurrentTableCopies
+	vttablet.DefaultVReplicationConfig.ConcurrentTableCopies = 2
+	defer func() { vttablet.DefaultVReplicationConfig.ConcurrentTableCopies = savedConcurrentTableCopies }()
+
+	execStatements(t, []string{
+		// dst1 had not been copied yet.
+		"create table src1(id int, val varchar(128), primary key(id))",
+		"insert into src1 values(1, 'aaa'), (2, 'bbb')",
+		fmt.Sprintf("create table %s.dst1(id int, val varchar(128), primary key(id))", vrepldb),
+		// dst2 was being copied alongside dst1 from a snapshot taken after pos,
+		// up to a lastpk of 2.
+		"create table src2(id int, val varchar(128), primary key(id))",
+		"insert into src2 values(1, 'aaa'), (2, 'bbb'), (3, 'ccc'), (4, 'ddd')",
+		fmt.Sprintf("create table %s.dst2(id int, val varchar(128), primary key(id))", vrepldb),
+	})
+	defer execStatements(t, []string{
+		"drop table src1",
+		fmt.Sprintf("drop table %s.dst1", vrepldb),
+		"drop table src2",
+		fmt.Sprintf("drop table %s.dst2", vrepldb),
+	})
+
+	filter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match:  "dst1",
+			Filter: "select * from src1",
+		}, {
+			Match:  "dst2",
+			Filter: "select * from src2",
+		}},
+	}
+	pos := primaryPosition(t)
+	// These events are part of dst2's snapshot.
+	execStatements(t, []string{
+		"insert into src2 values(0, 'zzz')",
+		"update src2 set val='aaa2' where id=1",
+	})
+	snapshotPos := primaryPosition(t)
+	execStatements(t, []string{
+		fmt.Sprintf("insert into %s.dst2 values(0, 'zzz'), (1, 'aaa2'), (2, 'bbb')", vrepldb),
+	})
+	// These events follow dst2's snapshot, inside and outside of the copied range.
+	execStatements(t, []string{
+		"update src2 set val='aaa3' where id=1",
+		"update src2 set val='ccc2' where id=3",
+		"insert into src2 values(5, 'eee')",
+		"update src1 set val='bbb2' where id=2",
+	})
+
+	bls := &binlogdatapb.BinlogSource{
+		Keyspace: env.KeyspaceName,
+		Shard:    env.ShardName,
+		Filter:   filter,
+		OnDdl:    binlogdatapb.OnDDLAction_IGNORE,
+	}
+	query := binlogplayer.CreateVReplicationState("test", bls, "", binlogdatapb.VReplicationWorkflowState_Stopped, playerEngine.dbName, 0, 0)
+	qr, err := playerEngine.Exec(query)
+	require.NoError(t, err)
+	id := qr.InsertID
+	lastpk := sqltypes.ResultToProto3(sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"id",
+			"int32",
+		),
+		"2",
+	))
+	lastpk.RowsAffected = 0
+	execStatements(t, []string{
+		fmt.Sprintf("insert into _vt.copy_state (vrepl_id, table_name, lastpk) values(%d, '%s', null)", id, "dst1"),
+		fmt.Sprintf("insert into _vt.copy_state (vrepl_id, table_name, lastpk, snapshot_pos) values(%d, '%s', %s, %s)", id, "dst2", encodeString(fmt.Sprintf("%v", lastpk)), encodeString(snapshotPos)),
+	})
+	_, err = playerEngine.Exec(fmt.Sprintf("update _vt.vreplication set state='Copying', pos=%s where id=%d", encodeString(pos), id))
+	require.NoError(t, err)
+	defer func() {
+		query := fmt.Sprintf("delete from _vt.vreplication where id = %d", id)
+		_, err := playerEngine.Exec(query)
+		require.NoError(t, err)
+	}()
+
+	expectWorkflowState(t, id, binlogdatapb.VReplicationWorkflowState_Running)
+	expectData(t, "_vt.copy_state", [][]string{})
+	expectData(t, "dst1", [][]string{
+		{"1", "aaa"},
+		{"2", "bbb2"},
+	})
+	expectData(t, "dst2", [][]string{
+		{"0", "zzz"},
+		{"1", "aaa3"},
+		{"2", "bbb"},
+		{"3", "ccc2"},
+		{"4", "ddd"},
+		{"5", "eee"},
+	})
+
+	// dst2 keeps replicating once its copy was finished from a new snapshot.
+	execStatements(t, []string{
+		"update src2 set val='zzz2' where id=0",
+	})
+	expectData(t, "dst2", [][]string{
+		{"0", "zzz2"},
+		{"1", "aaa3"},
+		{"2", "bbb"},
+		{"3", "ccc2"},
+		{"4", "ddd"},
+		{"5", "eee"},
+	})
+}
+
+// expectWorkflowState waits for the workflow to reach the given state.
+func expectWorkflowState(t *testing.T, id uint64, state binlogdatapb.VReplicationWorkflowState) {
+	t.Helper()
+	query := fmt.Sprintf("select state from _vt.vreplication where id = %d", id)
+	require.Eventually(t, func() bool {
+		return compareQueryResults(t, query, [][]string{{state.String()}}, env.Mysqld.FetchSuperQuery) == nil
+	}, 30*time.Second, 100*time.Millisecond, "workflow %d did not reach the %s state", id, state)
+}
+
+func TestConcurrentTables(t *testing.T) {
+	copyState := map[string]*sqltypes.Result{
+		"t1": nil,
+		"t2": sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1"),
+		"t3": nil,
+		"t4": nil,
+		"t5": nil,
+	}
+	testcases := []struct {
+		name       string
+		concurrent int
+		want       []string
+	}{{
+		name:       "disabled",
+		concurrent: 1,
+	}, {
+		name:       "one follower",
+		concurrent: 2,
+		want:       []string{"t3"},
+	}, {
+		name:       "all fresh tables",
+		concurrent: 10,
+		want:       []string{"t3", "t5"},
+	}}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			workflowConfig := vttablet.InitVReplicationConfigDefaults()
+			workflowConfig.ConcurrentTableCopies = tc.concurrent
+			vc := &vcopier{
+				vr: &vreplicator{workflowConfig: workflowConfig},
+				snapshotPositions: map[string]replication.Position{
+					"t4": {},
+				},
+			}
+			require.Equal(t, tc.want, vc.concurrentTables(copyState, "t1"))
+		})
+	}
+}
@@ -56,6 +56,11 @@ type vplayer struct {
 	stopPos   replication.Position
 	saveStop  bool
 	copyState map[string]*sqltypes.Result
+	// snapshotPositions contains the snapshot positions of the tables that
+	// were copied alongside another table during the copy phase. The rows
+	// copied from such a snapshot already reflect the events up to its
+	// position, so those events are skipped for the table.
+	snapshotPositions map[string]replication.Position
 
 	replicatorPlan *ReplicatorPlan
 	tablePlans     map[string]*TablePlan
@@ -346,6 +351,10 @@ func (vp *vplayer) applyRowEvent(ctx context.Context, rowEvent *binlogdatapb.Row
 	if tplan == nil {
 		return fmt.Errorf("unexpected event on table %s", rowEvent.TableName)
 	}
+	if snapshotPos, ok := vp.snapshotPositions[tplan.TargetName]; ok && !vp.pos.AtLeast(snapshotPos) {
+		// The event is part of the snapshot the table was copied from.
+		return nil
+	}
 	applyFunc := func(sql string) (*sqltypes.Result, error) {
 		start := time.Now()
 		qr, err := vp.query(ctx, sql)
@@ -406,6 +415,24 @@ func (vp *vplayer) updatePos(ctx context.Context, ts int64) (posReached bool, er
 	return posReached, nil
 }
 
+// stopAtDDL saves the position past the DDL event and stops the workflow with
+// the given message. The DDL is not applied.
+func (vp *vplayer) stopAtDDL(ctx context.Context, event *binlogdatapb.VEvent, message string) error {
+	if err := vp.vr.dbClient.Begin(); err != nil {
+		return err
+	}
+	if _, err := vp.updatePos(ctx, event.Timestamp); err != nil {
+		return err
+	}
+	if err := vp.vr.setState(binlogdatapb.VReplicationWorkflowState_Stopped, message); err != nil {
+		return err
+	}
+	if err := vp.commit(); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
 func (vp *vplayer) mustUpdateHeartbeat() bool {
 	return vp.numAccumulatedHeartbeats >= vp.vr.workflowConfig.HeartbeatUpdateInterval ||
 		vp.numAccumulatedHeartbeats >= vreplicationMinimumHeartbeatUpdateInterval
@@ -759,19 +786,7 @@ func (vp *vplayer) applyEvent(ctx context.Context, event *binlogdatapb.VEvent, m
 				return io.EOF
 			}
 		case binlogdatapb.OnDDLAction_STOP:
-			if err := vp.vr.dbClient.Begin(); err != nil {
-				return err
-			}
-			if _, err := vp.updatePos(ctx, event.Timestamp); err != nil {
-				return err
-			}
-			if err := vp.vr.setState(binlogdatapb.VReplicationWorkflowState_Stopped, fmt.Sprintf("Stopped at DDL %s", event.Statement)); err != nil {
-				return err
-			}
-			if err := vp.commit(); err != nil {
-				return err
-			}
-			return io.EOF
+			return vp.stopAtDDL(ctx, event, fmt.Sprintf("Stopped at DDL %s", event.Statement))
 		case binlogdatapb.OnDDLAction_EXEC:
 			// It's impossible to save the position transactionally with the statement.
 			// So, we apply the DDL first, and then save the position.
@@ -804,6 +819,32 @@ func (vp *vplayer) applyEvent(ctx context.Context, event *binlogdatapb.VEvent, m
 			if posReached {
 				return io.EOF
 			}
+		case binlogdatapb.OnDDLAction_EXEC_SAFE:
+			// The DDL is applied as the changes it makes to the target table,
+			// as computed by schemadiff. Like for EXEC, the position is saved
+			// after the statements are applied.
+			statements, err := vp.safeDDL(ctx, event.Statement)
+			if uerr := (*unsafeDDLError)(nil); errors.As(err, &uerr) {
+				return vp.stopAtDDL(ctx, event, fmt.Sprintf("Stopped at DDL %s: %v", event.Statement, uerr))
+			}
+			if err != nil {
+				return err
+			}
+			for _, statement := range statements {
+				if _, err := vp.query(ctx, statement); err != nil {
+					return err
+				}
+			}
+			if stats != nil {
+				stats.Send(fmt.Sprintf("%v", event.Statement))
+			}
+			posReached, err := vp.updatePos(ctx, event.Timestamp)
+			if err != nil {
+				return err
+			}
+			if posReached {
+				return io.EOF
+			}
 		}
 	case binlogdatapb.VEventType_JOURNAL:
 		if vp.vr.dbClient.InTransaction {
@@ -1296,7 +1296,7 @@ func (tsv *TabletServer) VStreamTables(ctx context.Context, request *binlogdatap
 	if err := tsv.sm.VerifyTarget(ctx, request.Target); err != nil {
 		return err
 	}
-	return tsv.vstreamer.StreamTables(ctx, send, request.Options)
+	return tsv.vstreamer.StreamTables(ctx, request, send)
 }
 
 // VStreamResults streams rows from the specified starting point.
@@ -324,9 +324,9 @@ func (vse *Engine) StreamRows(ctx context.Context, query string, lastpk []sqltyp
 	return rowStreamer.Stream()
 }
 
-// StreamTables streams all tables.
-func (vse *Engine) StreamTables(ctx context.Context,
-	send func(*binlogdatapb.VStreamTablesResponse) error, options *binlogdatapb.VStreamOptions) error {
+// StreamTables streams all tables, but for the excluded ones of the request.
+func (vse *Engine) StreamTables(ctx context.Context, request *binlogdatapb.VStreamTablesRequest,
+	send func(*binlogdatapb.VStreamTablesResponse) error) error {
 
 	// Ensure vschema is initialized and the watcher is started.
 	// Starting of the watcher is delayed till the first call to StreamTables
@@ -342,7 +342,10 @@ func (vse *Engine) StreamTables(ctx context.Context,
 		vse.mu.Lock()
 		defer vse.mu.Unlock()
 
-		tableStreamer := newTableStreamer(ctx, vse.env.Config().DB.FilteredWithDB(), vse.se, vse.lvschema, send, vse, options)
+		tableStreamer, err := newTableStreamer(ctx, vse.env.Config().DB.FilteredWithDB(), vse.se, vse.lvschema, send, vse, request)
+		if err != nil {
+			return nil, 0, err
+		}
 		idx := vse.streamIdx
 		vse.tableStreamers[idx] = tableStreamer
 		vse.streamIdx++
@@ -62,27 +62,37 @@ type tableStreamer struct {
 	gtid         string
 	options      *binlogdatapb.VStreamOptions
 	config       *vttablet.VReplicationConfig
+	// exclude filters out the tables that are not streamed.
+	exclude *tmutils.TableFilter
+	// tableSchemas makes the first response of each table carry its CREATE TABLE statement.
+	tableSchemas bool
 }
 
 func newTableStreamer(ctx context.Context, cp dbconfigs.Connector, se *schema.Engine, vschema *localVSchema,
-	send func(response *binlogdatapb.VStreamTablesResponse) error, vse *Engine, options *binlogdatapb.VStreamOptions) *tableStreamer {
+	send func(response *binlogdatapb.VStreamTablesResponse) error, vse *Engine, request *binlogdatapb.VStreamTablesRequest) (*tableStreamer, error) {
 
-	config, err := GetVReplicationConfig(options)
+	config, err := GetVReplicationConfig(request.GetOptions())
 	if err != nil {
-		return nil
+		return nil, err
+	}
+	exclude, err := tmutils.NewTableFilter(nil, request.GetExcludeTables(), false)
+	if err != nil {
+		return nil, err
 	}
 	ctx, cancel := context.WithCancel(ctx)
 	return &tableStreamer{
-		ctx:     ctx,
-		cancel:  cancel,
-		cp:      cp,
-		se:      se,
-		send:    send,
-		vschema: vschema,
-		vse:     vse,
-		options: options,
-		config:  config,
-	}
+		ctx:          ctx,
+		cancel:       cancel,
+		cp:           cp,
+		se:           se,
+		send:         send,
+		vschema:      vschema,
+		vse:          vse,
+		options:      request.GetOptions(),
+		config:       config,
+		exclude:      exclude,
+		tableSchemas: request.GetTableSchemas(),
+	}, nil
 }
 
 func (ts *tableStreamer) Cancel() {
@@ -133,6 +143,10 @@ func (ts *tableStreamer) Stream() error {
 			log.Infof("Skipping internal table %s", tableName)
 			continue
 		}
+		if !ts.exclude.Includes(tableName, tableType) {
+			log.Infof("Skipping excluded table %s", tableName)
+			continue
+		}
 		ts.tables = append(ts.tables, tableName)
 	}
 	log.Infof("Found %d tables to stream: %s", len(ts.tables), strings.Join(ts.tables, ", "))
@@ -177,18 +191,46 @@ func (ts *tableStreamer) newRowStreamer(ctx context.Context, query string, lastp
 	return rowStreamer, cancel, nil
 }
 
+// tableSchema returns the CREATE TABLE statement of a table, as of the snapshot. Reading from
+// the table first makes the snapshot transaction hold its metadata lock until the stream ends,
+// so the table cannot change anymore. A DDL that ran after the snapshot was taken either leaves
+// rows that match the returned statement, or makes reading the rows fail.
+func (ts *tableStreamer) tableSchema(tableName string) (string, error) {
+	if _, err := ts.snapshotConn.ExecuteFetch(fmt.Sprintf("select 1 from %s limit 0", sqlescape.EscapeID(tableName)), 1, false); err != nil {
+		return "", err
+	}
+	qr, err := ts.snapshotConn.ExecuteFetch("show create table "+sqlescape.EscapeID(tableName), 1, false)
+	if err != nil {
+		return "", err
+	}
+	if len(qr.Rows) != 1 || len(qr.Rows[0]) < 2 {
+		return "", fmt.Errorf("unexpected result for show create table %s: %v", tableName, qr.Rows)
+	}
+	return qr.Rows[0][1].ToString(), nil
+}
+
 func (ts *tableStreamer) streamTable(ctx context.Context, tableName string) error {
 	query := fmt.Sprintf("select * from %s", sqlescape.EscapeID(tableName))
 
+	var tableSchema string
+	if ts.tableSchemas {
+		var err error
+		if tableSchema, err = ts.tableSchema(tableName); err != nil {
+			return err
+		}
+	}
 	send := func(response *binlogdatapb.VStreamRowsResponse) error {
-		return ts.send(&binlogdatapb.VStreamTablesResponse{
-			TableName: tableName,
-			Fields:    response.GetFields(),
-			Pkfields:  response.GetPkfields(),
-			Gtid:      ts.gtid,
-			Rows:      response.GetRows(),
-			Lastpk:    response.Lastpk,
-		})
+		resp := &binlogdatapb.VStreamTablesResponse{
+			TableName:   tableName,
+			Fields:      response.GetFields(),
+			Pkfields:    response.GetPkfields(),
+			Gtid:        ts.gtid,
+			Rows:        response.GetRows(),
+			Lastpk:      response.Lastpk,
+			TableSchema: tableSchema,
+		}
+		tableSchema = ""
+		return ts.send(resp)
 	}
 	rs, cancel, err := ts.newRowStreamer(ctx, query, nil, send)
 	if err != nil {
@@ -196,7 +238,7 @@ func (ts *tableStreamer) streamTable(ctx context.Context, tableName string) erro
 	}
 	defer cancel()
 
-	if rs.Stream() != nil {
+	if err := rs.Stream(); err != nil {
 		return err
 	}
 	rs.vse.tableStreamerNumTables.Add(int64(1))
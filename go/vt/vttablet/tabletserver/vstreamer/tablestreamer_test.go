@@ -19,6 +19,8 @@ package vstreamer
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -62,15 +64,53 @@ func TestTableStreamer(t *testing.T) {
 		"table_name:\"t4\" rows:{lengths:1 lengths:1 lengths:1 lengths:3 values:\"123aaa\"} rows:{lengths:1 lengths:1 lengths:1 lengths:3 values:\"234bbb\"} lastpk:{lengths:1 lengths:1 lengths:1 values:\"234\"}",
 	}
 	var gotStream []string
-	err := engine.StreamTables(ctx, func(response *binlogdatapb.VStreamTablesResponse) error {
+	err := engine.StreamTables(ctx, &binlogdatapb.VStreamTablesRequest{}, func(response *binlogdatapb.VStreamTablesResponse) error {
 		response.Gtid = ""
 		for _, fld := range response.Fields {
 			fld.ColumnType = ""
 		}
 		gotStream = append(gotStream, fmt.Sprintf("%v", response))
 		return nil
-	}, nil)
+	})
 	require.NoError(t, err)
 	require.EqualValues(t, wantStream, gotStream)
 	require.Equal(t, int64(4), engine.tableStreamerNumTables.Get())
 }
+
+// TestTableStreamerExcludeTables ensures excluded tables are not streamed, and that the first
+// response of each table carries its schema when asked for.
+func TestTableStreamerExcludeTables(t *testing.T) {
+	ctx := context.Background()
+	execStatements(t, []string{
+		"create table t1(id int, val varbinary(128), primary key(id))",
+		"insert into t1 values (1, 'aaa'), (2, 'bbb')",
+		"create table t2(id int, val varbinary(128), primary key(id))",
+		"insert into t2 values (1, 'aaa')",
+		"create table t3(id int, val varbinary(128), primary key(id))",
+		"insert into t3 values (1, 'aaa')",
+	})
+	defer execStatements(t, []string{
+		"drop table t1",
+		"drop table t2",
+		"drop table t3",
+	})
+
+	schemas := make(map[string]string)
+	responses := make(map[string]int)
+	err := engine.StreamTables(ctx, &binlogdatapb.VStreamTablesRequest{
+		ExcludeTables: []string{"t2", "/^t[4-9]$/"},
+		TableSchemas:  true,
+	}, func(response *binlogdatapb.VStreamTablesResponse) error {
+		if response.TableSchema != "" {
+			require.Zero(t, responses[response.TableName], "schema of %s sent after its first response", response.TableName)
+			schemas[response.TableName] = response.TableSchema
+		}
+		responses[response.TableName]++
+		return nil
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"t1", "t3"}, slices.Collect(maps.Keys(responses)))
+	require.Len(t, schemas, 2)
+	require.Contains(t, schemas["t1"], "CREATE TABLE `t1`")
+	require.Contains(t, schemas["t3"], "CREATE TABLE `t3`")
+}
@@ -0,0 +1,434 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttopo
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/raft"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// watchBufferSize is the number of events a watch can be behind before
+// it is canceled.
+const watchBufferSize = 1000
+
+// fsm is the state machine replicated with Raft: the files, and the
+// leases they can be attached to. It notifies the watches of the changes
+// of the files.
+//
+// The KeyValue protos of the files are never modified once stored, so
+// they can be returned without copying them.
+type fsm struct {
+	mu      sync.Mutex
+	kvs     map[string]*vttopopb.KeyValue
+	leases  map[int64]*lease
+	watches map[*watch]struct{}
+
+	// index is the Raft index of the last command applied.
+	index int64
+	// applied is closed, and replaced, when a command is applied.
+	applied chan struct{}
+}
+
+// lease is a lease, and the files attached to it.
+type lease struct {
+	ttl  int64
+	keys map[string]struct{}
+}
+
+// watch is a watch of a file, or of the files with a prefix.
+type watch struct {
+	key    string
+	prefix bool
+	events chan *vttopopb.WatchEvent
+
+	// done is closed when the watch is canceled by the fsm, with the
+	// reason in err.
+	done chan struct{}
+	err  error
+}
+
+func newFSM() *fsm {
+	return &fsm{
+		kvs:     make(map[string]*vttopopb.KeyValue),
+		leases:  make(map[int64]*lease),
+		watches: make(map[*watch]struct{}),
+		applied: make(chan struct{}),
+	}
+}
+
+// matches returns true if the watch is interested in the given key.
+func (w *watch) matches(key string) bool {
+	if w.prefix {
+		return strings.HasPrefix(key, w.key)
+	}
+	return key == w.key
+}
+
+// Apply is part of the raft.FSM interface. It returns nil, or the error
+// the command failed with.
+func (f *fsm) Apply(l *raft.Log) any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer f.setIndex(int64(l.Index))
+
+	cmd := &vttopopb.Command{}
+	if err := cmd.UnmarshalVT(l.Data); err != nil {
+		return vterrors.Wrapf(err, "cannot decode command at index %v", l.Index)
+	}
+
+	index := int64(l.Index)
+	switch c := cmd.Command.(type) {
+	case *vttopopb.Command_Create:
+		return f.create(index, c.Create)
+	case *vttopopb.Command_Update:
+		return f.update(index, c.Update)
+	case *vttopopb.Command_Delete:
+		return f.delete(index, c.Delete)
+	case *vttopopb.Command_Grant:
+		f.leases[index] = &lease{
+			ttl:  c.Grant.Ttl,
+			keys: make(map[string]struct{}),
+		}
+		return nil
+	case *vttopopb.Command_Revoke:
+		return f.revoke(index, c.Revoke)
+	default:
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unknown command %T at index %v", c, l.Index)
+	}
+}
+
+// setIndex records the index of the last command applied, and wakes up
+// the waitForIndex callers. f.mu must be held.
+func (f *fsm) setIndex(index int64) {
+	f.index = index
+	close(f.applied)
+	f.applied = make(chan struct{})
+}
+
+func (f *fsm) create(index int64, req *vttopopb.CreateRequest) error {
+	if _, ok := f.kvs[req.Key]; ok {
+		return vterrors.Errorf(vtrpcpb.Code_ALREADY_EXISTS, "file %v already exists", req.Key)
+	}
+	if req.LeaseId != 0 {
+		l, ok := f.leases[req.LeaseId]
+		if !ok {
+			return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "lease %v not found", req.LeaseId)
+		}
+		l.keys[req.Key] = struct{}{}
+	}
+	f.put(&vttopopb.KeyValue{
+		Key:     req.Key,
+		Value:   req.Value,
+		Version: index,
+		LeaseId: req.LeaseId,
+	})
+	return nil
+}
+
+func (f *fsm) update(index int64, req *vttopopb.UpdateRequest) error {
+	kv, ok := f.kvs[req.Key]
+	if req.Version != 0 {
+		if !ok {
+			return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "file %v not found", req.Key)
+		}
+		if kv.Version != req.Version {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "file %v is at version %v, not %v", req.Key, kv.Version, req.Version)
+		}
+	}
+	var leaseID int64
+	if ok {
+		leaseID = kv.LeaseId
+	}
+	f.put(&vttopopb.KeyValue{
+		Key:     req.Key,
+		Value:   req.Value,
+		Version: index,
+		LeaseId: leaseID,
+	})
+	return nil
+}
+
+func (f *fsm) delete(index int64, req *vttopopb.DeleteRequest) error {
+	kv, ok := f.kvs[req.Key]
+	if !ok {
+		return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "file %v not found", req.Key)
+	}
+	if req.Version != 0 && kv.Version != req.Version {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "file %v is at version %v, not %v", req.Key, kv.Version, req.Version)
+	}
+	f.remove(index, req.Key)
+	return nil
+}
+
+func (f *fsm) revoke(index int64, req *vttopopb.RevokeRequest) error {
+	l, ok := f.leases[req.LeaseId]
+	if !ok {
+		return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "lease %v not found", req.LeaseId)
+	}
+	keys := make([]string, 0, len(l.keys))
+	for key := range l.keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		f.remove(index, key)
+	}
+	delete(f.leases, req.LeaseId)
+	return nil
+}
+
+// put stores a file, and notifies the watches. f.mu must be held.
+func (f *fsm) put(kv *vttopopb.KeyValue) {
+	f.kvs[kv.Key] = kv
+	f.notify(&vttopopb.WatchEvent{
+		Type: vttopopb.WatchEvent_PUT,
+		Kv:   kv,
+	})
+}
+
+// remove deletes a file, and notifies the watches. f.mu must be held.
+func (f *fsm) remove(index int64, key string) {
+	if kv := f.kvs[key]; kv.LeaseId != 0 {
+		if l, ok := f.leases[kv.LeaseId]; ok {
+			delete(l.keys, key)
+		}
+	}
+	delete(f.kvs, key)
+	f.notify(&vttopopb.WatchEvent{
+		Type: vttopopb.WatchEvent_DELETE,
+		Kv: &vttopopb.KeyValue{
+			Key:     key,
+			Version: index,
+		},
+	})
+}
+
+// notify sends an event to the watches of the file. We never block
+// the state machine on a watch: watches too far behind are canceled.
+// f.mu must be held.
+func (f *fsm) notify(event *vttopopb.WatchEvent) {
+	for w := range f.watches {
+		if !w.matches(event.Kv.Key) {
+			continue
+		}
+		select {
+		case w.events <- event:
+		default:
+			f.cancelWatch(w, vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "watch of %v is too far behind", w.key))
+		}
+	}
+}
+
+// cancelWatch stops sending events to a watch. f.mu must be held.
+func (f *fsm) cancelWatch(w *watch, err error) {
+	delete(f.watches, w)
+	w.err = err
+	close(w.done)
+}
+
+// get returns a file, or nil if it doesn't exist.
+func (f *fsm) get(key string) *vttopopb.KeyValue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.kvs[key]
+}
+
+// list returns the files with the given prefix, sorted by key.
+func (f *fsm) list(prefix string, keysOnly bool) []*vttopopb.KeyValue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.listLocked(prefix, keysOnly)
+}
+
+func (f *fsm) listLocked(prefix string, keysOnly bool) []*vttopopb.KeyValue {
+	var kvs []*vttopopb.KeyValue
+	for key, kv := range f.kvs {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if keysOnly {
+			kv = &vttopopb.KeyValue{
+				Key:     kv.Key,
+				Version: kv.Version,
+				LeaseId: kv.LeaseId,
+			}
+		}
+		kvs = append(kvs, kv)
+	}
+	sort.Slice(kvs, func(i, j int) bool {
+		return kvs[i].Key < kvs[j].Key
+	})
+	return kvs
+}
+
+// watch starts a watch, and returns the files it watches at that point.
+// The caller must call unwatch when done.
+func (f *fsm) watch(key string, prefix bool) ([]*vttopopb.KeyValue, *watch) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var kvs []*vttopopb.KeyValue
+	if prefix {
+		kvs = f.listLocked(key, false)
+	} else if kv, ok := f.kvs[key]; ok {
+		kvs = []*vttopopb.KeyValue{kv}
+	}
+	w := &watch{
+		key:    key,
+		prefix: prefix,
+		events: make(chan *vttopopb.WatchEvent, watchBufferSize),
+		done:   make(chan struct{}),
+	}
+	f.watches[w] = struct{}{}
+	return kvs, w
+}
+
+// unwatch stops a watch.
+func (f *fsm) unwatch(w *watch) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.watches, w)
+}
+
+// lease returns the TTL of a lease, and whether it exists.
+func (f *fsm) lease(id int64) (int64, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	l, ok := f.leases[id]
+	if !ok {
+		return 0, false
+	}
+	return l.ttl, true
+}
+
+// leaseList returns all the leases.
+func (f *fsm) leaseList() []*vttopopb.Lease {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	leases := make([]*vttopopb.Lease, 0, len(f.leases))
+	for id, l := range f.leases {
+		leases = append(leases, &vttopopb.Lease{
+			Id:  id,
+			Ttl: l.ttl,
+		})
+	}
+	return leases
+}
+
+// waitForIndex waits until the command at the given index is applied.
+func (f *fsm) waitForIndex(ctx context.Context, index int64) error {
+	for {
+		f.mu.Lock()
+		current, applied := f.index, f.applied
+		f.mu.Unlock()
+		if current >= index {
+			return nil
+		}
+		select {
+		case <-applied:
+		case <-ctx.Done():
+			return vterrors.Errorf(vtrpcpb.Code_DEADLINE_EXCEEDED, "timed out waiting for index %v to be applied, at %v: %v", index, current, ctx.Err())
+		}
+	}
+}
+
+// Snapshot is part of the raft.FSM interface.
+func (f *fsm) Snapshot() (raft.FSMSnapshot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	snapshot := &vttopopb.Snapshot{
+		Kvs:   make([]*vttopopb.KeyValue, 0, len(f.kvs)),
+		Index: f.index,
+	}
+	for _, kv := range f.kvs {
+		snapshot.Kvs = append(snapshot.Kvs, kv)
+	}
+	for id, l := range f.leases {
+		snapshot.Leases = append(snapshot.Leases, &vttopopb.Lease{
+			Id:  id,
+			Ttl: l.ttl,
+		})
+	}
+	return &fsmSnapshot{snapshot: snapshot}, nil
+}
+
+// Restore is part of the raft.FSM interface. The watches are canceled,
+// as they may have missed changes.
+func (f *fsm) Restore(rc io.ReadCloser) error {
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+	snapshot := &vttopopb.Snapshot{}
+	if err := snapshot.UnmarshalVT(data); err != nil {
+		return vterrors.Wrap(err, "cannot decode snapshot")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.kvs = make(map[string]*vttopopb.KeyValue, len(snapshot.Kvs))
+	f.leases = make(map[int64]*lease, len(snapshot.Leases))
+	for _, l := range snapshot.Leases {
+		f.leases[l.Id] = &lease{
+			ttl:  l.Ttl,
+			keys: make(map[string]struct{}),
+		}
+	}
+	for _, kv := range snapshot.Kvs {
+		f.kvs[kv.Key] = kv
+		if l, ok := f.leases[kv.LeaseId]; ok {
+			l.keys[kv.Key] = struct{}{}
+		}
+	}
+	for w := range f.watches {
+		f.cancelWatch(w, vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "data restored from a snapshot"))
+	}
+	f.setIndex(snapshot.Index)
+	return nil
+}
+
+// fsmSnapshot implements raft.FSMSnapshot.
+type fsmSnapshot struct {
+	snapshot *vttopopb.Snapshot
+}
+
+// Persist is part of the raft.FSMSnapshot interface.
+func (s *fsmSnapshot) Persist(sink raft.SnapshotSink) error {
+	data, err := s.snapshot.MarshalVT()
+	if err == nil {
+		_, err = sink.Write(data)
+	}
+	if err != nil {
+		_ = sink.Cancel()
+		return err
+	}
+	return sink.Close()
+}
+
+// Release is part of the raft.FSMSnapshot interface.
+func (s *fsmSnapshot) Release() {}
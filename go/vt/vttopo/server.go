@@ -0,0 +1,402 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package vttopo implements a topology server which replicates its data
+among a few nodes with Raft, for deployments which don't want to run
+etcd, ZooKeeper or Consul. The rafttopo package is its topo.Conn client.
+
+Each node serves the Topo gRPC service:
+  - Reads and watches are served by the node from its copy of the data.
+  - Changes are applied by the leader through the Raft log. The other
+    nodes forward them to the leader, and wait until they applied them
+    too before answering, so the clients of a node always read their own
+    changes.
+  - Files can be attached to leases, which the clients keep alive. The
+    leader revokes the leases which expire, which deletes their files.
+
+The version of a file is the Raft index of its last change.
+*/
+package vttopo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/log"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	// applyTimeout is how long we wait for the leader to accept a
+	// command, when the context has no deadline.
+	applyTimeout = 10 * time.Second
+
+	// leaseCheckInterval is how often the leader checks for expired
+	// leases.
+	leaseCheckInterval = 250 * time.Millisecond
+
+	// retainSnapshots is the number of snapshots kept on disk.
+	retainSnapshots = 2
+)
+
+// Options are the options of a Server.
+type Options struct {
+	// ID is the address of the Topo service of the node, used by the
+	// clients and the other nodes. It identifies the node in the Raft
+	// cluster.
+	ID string
+
+	// RaftAddress is the address the Raft transport of the node listens
+	// on, and is reached at by the other nodes.
+	RaftAddress string
+
+	// DataDir is the directory the Raft log and snapshots are stored in.
+	// If empty, they are only kept in memory, and lost on restart.
+	DataDir string
+
+	// DialOption is used to connect to the Topo service of the other
+	// nodes. If nil, the connections are insecure.
+	DialOption grpc.DialOption
+}
+
+// Server is a vttopo node. It implements vttopopb.TopoServer.
+type Server struct {
+	vttopopb.UnimplementedTopoServer
+
+	id         string
+	dialOption grpc.DialOption
+
+	fsm       *fsm
+	raft      *raft.Raft
+	transport *raft.NetworkTransport
+	closers   []io.Closer
+
+	mu sync.Mutex
+	// conns are the connections to the other nodes, by ID.
+	conns map[string]*grpc.ClientConn
+	// deadlines are the expiry times of the leases. Only the leader
+	// tracks them.
+	deadlines map[int64]time.Time
+
+	leaderNotify chan bool
+	done         chan struct{}
+	wg           sync.WaitGroup
+}
+
+// NewServer creates a node and starts its Raft instance. A new cluster
+// then needs to be bootstrapped with Bootstrap.
+func NewServer(opts Options) (*Server, error) {
+	if opts.ID == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the node ID is required")
+	}
+	if opts.RaftAddress == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the Raft address is required")
+	}
+	s := &Server{
+		id:           opts.ID,
+		dialOption:   opts.DialOption,
+		fsm:          newFSM(),
+		conns:        make(map[string]*grpc.ClientConn),
+		deadlines:    make(map[int64]time.Time),
+		leaderNotify: make(chan bool, 1),
+		done:         make(chan struct{}),
+	}
+	if s.dialOption == nil {
+		s.dialOption = grpc.WithTransportCredentials(insecure.NewCredentials())
+	}
+
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:   "raft",
+		Level:  hclog.Warn,
+		Output: os.Stderr,
+	})
+	config := raft.DefaultConfig()
+	config.LocalID = raft.ServerID(opts.ID)
+	config.Logger = logger
+	config.NotifyCh = s.leaderNotify
+
+	var (
+		logs      raft.LogStore
+		stable    raft.StableStore
+		snapshots raft.SnapshotStore
+	)
+	if opts.DataDir == "" {
+		store := raft.NewInmemStore()
+		logs, stable = store, store
+		snapshots = raft.NewInmemSnapshotStore()
+	} else {
+		if err := os.MkdirAll(opts.DataDir, 0o700); err != nil {
+			return nil, err
+		}
+		store, err := raftboltdb.NewBoltStore(filepath.Join(opts.DataDir, "raft.db"))
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot open the Raft log in %v", opts.DataDir)
+		}
+		s.closers = append(s.closers, store)
+		logs, stable = store, store
+		snapshots, err = raft.NewFileSnapshotStoreWithLogger(opts.DataDir, retainSnapshots, logger)
+		if err != nil {
+			s.closeStores()
+			return nil, vterrors.Wrapf(err, "cannot open the Raft snapshots in %v", opts.DataDir)
+		}
+	}
+
+	transport, err := raft.NewTCPTransportWithLogger(opts.RaftAddress, nil, 3, 10*time.Second, logger)
+	if err != nil {
+		s.closeStores()
+		return nil, vterrors.Wrapf(err, "cannot listen on %v", opts.RaftAddress)
+	}
+	s.transport = transport
+
+	s.raft, err = raft.NewRaft(config, s.fsm, logs, stable, snapshots, transport)
+	if err != nil {
+		transport.Close()
+		s.closeStores()
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go s.expireLeases()
+	return s, nil
+}
+
+// RaftAddress returns the address of the Raft transport of the node.
+func (s *Server) RaftAddress() string {
+	return string(s.transport.LocalAddr())
+}
+
+// Bootstrap bootstraps a new cluster, made of the given nodes, as a map
+// of their IDs to their Raft addresses. It does nothing if the node
+// already has some state, so it can be called every time the node
+// starts. Bootstrapping a single node of the cluster is enough, but all
+// of them can be bootstrapped with the same nodes.
+func (s *Server) Bootstrap(peers map[string]string) error {
+	configuration := raft.Configuration{}
+	for id, address := range peers {
+		configuration.Servers = append(configuration.Servers, raft.Server{
+			Suffrage: raft.Voter,
+			ID:       raft.ServerID(id),
+			Address:  raft.ServerAddress(address),
+		})
+	}
+	sort.Slice(configuration.Servers, func(i, j int) bool {
+		return configuration.Servers[i].ID < configuration.Servers[j].ID
+	})
+	if err := s.raft.BootstrapCluster(configuration).Error(); err != nil && !errors.Is(err, raft.ErrCantBootstrap) {
+		return err
+	}
+	return nil
+}
+
+// IsLeader returns true if the node is the Raft leader.
+func (s *Server) IsLeader() bool {
+	return s.raft.State() == raft.Leader
+}
+
+// Leader returns the ID of the Raft leader, or an empty string if there
+// is none.
+func (s *Server) Leader() string {
+	_, id := s.raft.LeaderWithID()
+	return string(id)
+}
+
+// Register registers the Topo service of the node with a gRPC server.
+func (s *Server) Register(gs *grpc.Server) {
+	vttopopb.RegisterTopoServer(gs, s)
+}
+
+// Close stops the node.
+func (s *Server) Close() error {
+	// Raft notifies leadership changes while shutting down, so we
+	// stop expireLeases after it.
+	err := s.raft.Shutdown().Error()
+	close(s.done)
+	s.wg.Wait()
+	s.transport.Close()
+	s.closeStores()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+	return err
+}
+
+func (s *Server) closeStores() {
+	for _, closer := range s.closers {
+		if err := closer.Close(); err != nil {
+			log.Warningf("cannot close the Raft store: %v", err)
+		}
+	}
+}
+
+// leaderClient returns a client of the Topo service of the leader.
+func (s *Server) leaderClient(ctx context.Context) (vttopopb.TopoClient, error) {
+	leader := s.Leader()
+	if leader == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "no Raft leader")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conns == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "node is closed")
+	}
+	conn, ok := s.conns[leader]
+	if !ok {
+		var err error
+		conn, err = grpcclient.DialContext(ctx, leader, grpcclient.FailFast(true), s.dialOption)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot connect to the leader %v", leader)
+		}
+		s.conns[leader] = conn
+	}
+	return vttopopb.NewTopoClient(conn), nil
+}
+
+// apply applies a command, and returns the Raft index it was applied
+// at. If the node is not the leader, the command is forwarded to the
+// leader, and we wait until the node applied it too.
+func (s *Server) apply(ctx context.Context, cmd *vttopopb.Command) (int64, error) {
+	if s.IsLeader() {
+		return s.applyLocal(ctx, cmd)
+	}
+
+	client, err := s.leaderClient(ctx)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Apply(ctx, &vttopopb.ApplyRequest{Command: cmd})
+	if err != nil {
+		return 0, vterrors.FromGRPC(err)
+	}
+	if err := s.fsm.waitForIndex(ctx, resp.Index); err != nil {
+		return 0, err
+	}
+	return resp.Index, nil
+}
+
+// applyLocal applies a command through the Raft log of the node, which
+// must be the leader.
+func (s *Server) applyLocal(ctx context.Context, cmd *vttopopb.Command) (int64, error) {
+	data, err := cmd.MarshalVT()
+	if err != nil {
+		return 0, err
+	}
+	timeout := applyTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	future := s.raft.Apply(data, timeout)
+	if err := future.Error(); err != nil {
+		if errors.Is(err, raft.ErrEnqueueTimeout) {
+			return 0, vterrors.Errorf(vtrpcpb.Code_DEADLINE_EXCEEDED, "timed out applying the change on %v", s.id)
+		}
+		// This includes losing the leadership.
+		return 0, vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "cannot apply the change on %v: %v", s.id, err)
+	}
+	if err, ok := future.Response().(error); ok && err != nil {
+		return 0, err
+	}
+	return int64(future.Index()), nil
+}
+
+// keepAlive pushes back the expiry of a lease. The node must be the
+// leader.
+func (s *Server) keepAlive(leaseID int64) (int64, error) {
+	ttl, ok := s.fsm.lease(leaseID)
+	if !ok {
+		return 0, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "lease %v not found", leaseID)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deadlines[leaseID] = time.Now().Add(time.Duration(ttl) * time.Second)
+	return ttl, nil
+}
+
+// expireLeases revokes the leases which expired, when the node is the
+// leader. A new leader doesn't know when the leases were last kept
+// alive, so it gives them their full TTL again.
+func (s *Server) expireLeases() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(leaseCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.leaderNotify:
+			s.mu.Lock()
+			s.deadlines = make(map[int64]time.Time)
+			s.mu.Unlock()
+		case <-ticker.C:
+			if !s.IsLeader() {
+				continue
+			}
+			for _, id := range s.expiredLeases() {
+				ctx, cancel := context.WithTimeout(context.Background(), applyTimeout)
+				_, err := s.applyLocal(ctx, &vttopopb.Command{Command: &vttopopb.Command_Revoke{Revoke: &vttopopb.RevokeRequest{LeaseId: id}}})
+				cancel()
+				if err != nil && vterrors.Code(err) != vtrpcpb.Code_NOT_FOUND {
+					log.Warningf("cannot revoke expired lease %v: %v", id, err)
+				}
+			}
+		}
+	}
+}
+
+// expiredLeases returns the leases which expired, and tracks the
+// deadlines of the new ones.
+func (s *Server) expiredLeases() []int64 {
+	leases := s.fsm.leaseList()
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var expired []int64
+	deadlines := make(map[int64]time.Time, len(leases))
+	for _, l := range leases {
+		deadline, ok := s.deadlines[l.Id]
+		if !ok {
+			deadline = now.Add(time.Duration(l.Ttl) * time.Second)
+		}
+		if now.After(deadline) {
+			expired = append(expired, l.Id)
+		}
+		deadlines[l.Id] = deadline
+	}
+	s.deadlines = deadlines
+	return expired
+}
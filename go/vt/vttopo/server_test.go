@@ -0,0 +1,135 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttopo
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// applyCommand applies a command to the fsm at the given index.
+func applyCommand(t *testing.T, f *fsm, index uint64, cmd *vttopopb.Command) error {
+	data, err := cmd.MarshalVT()
+	require.NoError(t, err)
+	result := f.Apply(&raft.Log{Index: index, Data: data})
+	if result == nil {
+		return nil
+	}
+	return result.(error)
+}
+
+// snapshotSink is an in memory raft.SnapshotSink.
+type snapshotSink struct {
+	bytes.Buffer
+}
+
+func (s *snapshotSink) ID() string    { return "test" }
+func (s *snapshotSink) Cancel() error { return nil }
+func (s *snapshotSink) Close() error  { return nil }
+
+func TestFSM(t *testing.T) {
+	f := newFSM()
+
+	kvs, w := f.watch("/a/", true)
+	require.Empty(t, kvs)
+	defer f.unwatch(w)
+
+	require.NoError(t, applyCommand(t, f, 1, &vttopopb.Command{Command: &vttopopb.Command_Create{Create: &vttopopb.CreateRequest{Key: "/a/1", Value: []byte("1")}}}))
+	err := applyCommand(t, f, 2, &vttopopb.Command{Command: &vttopopb.Command_Create{Create: &vttopopb.CreateRequest{Key: "/a/1"}}})
+	require.Equal(t, vtrpcpb.Code_ALREADY_EXISTS, vterrors.Code(err))
+	err = applyCommand(t, f, 3, &vttopopb.Command{Command: &vttopopb.Command_Update{Update: &vttopopb.UpdateRequest{Key: "/a/1", Version: 2}}})
+	require.Equal(t, vtrpcpb.Code_FAILED_PRECONDITION, vterrors.Code(err))
+	require.NoError(t, applyCommand(t, f, 4, &vttopopb.Command{Command: &vttopopb.Command_Update{Update: &vttopopb.UpdateRequest{Key: "/a/1", Value: []byte("2"), Version: 1}}}))
+	require.EqualValues(t, 4, f.get("/a/1").Version)
+	require.EqualValues(t, 4, f.index)
+
+	// Files attached to a lease are deleted when it's revoked.
+	require.NoError(t, applyCommand(t, f, 5, &vttopopb.Command{Command: &vttopopb.Command_Grant{Grant: &vttopopb.GrantRequest{Ttl: 10}}}))
+	require.NoError(t, applyCommand(t, f, 6, &vttopopb.Command{Command: &vttopopb.Command_Create{Create: &vttopopb.CreateRequest{Key: "/a/2", LeaseId: 5}}}))
+	require.Len(t, f.list("/a/", true), 2)
+	require.NoError(t, applyCommand(t, f, 7, &vttopopb.Command{Command: &vttopopb.Command_Revoke{Revoke: &vttopopb.RevokeRequest{LeaseId: 5}}}))
+	require.Nil(t, f.get("/a/2"))
+	_, ok := f.lease(5)
+	require.False(t, ok)
+
+	var events []*vttopopb.WatchEvent
+	for len(w.events) > 0 {
+		events = append(events, <-w.events)
+	}
+	require.Len(t, events, 4)
+	require.Equal(t, vttopopb.WatchEvent_DELETE, events[3].Type)
+	require.Equal(t, "/a/2", events[3].Kv.Key)
+	require.EqualValues(t, 7, events[3].Kv.Version)
+
+	// The data survives a snapshot, and the watches are canceled.
+	require.NoError(t, applyCommand(t, f, 8, &vttopopb.Command{Command: &vttopopb.Command_Grant{Grant: &vttopopb.GrantRequest{Ttl: 10}}}))
+	snapshot, err := f.Snapshot()
+	require.NoError(t, err)
+	sink := &snapshotSink{}
+	require.NoError(t, snapshot.Persist(sink))
+
+	restored := newFSM()
+	_, w2 := restored.watch("/a/", true)
+	require.NoError(t, restored.Restore(io.NopCloser(&sink.Buffer)))
+	require.Equal(t, f.list("/", false), restored.list("/", false))
+	require.Equal(t, f.leaseList(), restored.leaseList())
+	require.EqualValues(t, 8, restored.index)
+	<-w2.done
+	require.Equal(t, vtrpcpb.Code_UNAVAILABLE, vterrors.Code(w2.err))
+}
+
+func TestRestart(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	dir := t.TempDir()
+
+	start := func() *Server {
+		s, err := NewServer(Options{
+			ID:          "localhost:15999",
+			RaftAddress: "127.0.0.1:0",
+			DataDir:     dir,
+		})
+		require.NoError(t, err)
+		require.NoError(t, s.Bootstrap(map[string]string{"localhost:15999": s.RaftAddress()}))
+		require.Eventually(t, s.IsLeader, 30*time.Second, 100*time.Millisecond)
+		return s
+	}
+
+	s := start()
+	resp, err := s.Create(ctx, &vttopopb.CreateRequest{Key: "/file", Value: []byte("contents")})
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	// The Raft log is replayed when the node restarts.
+	s = start()
+	defer s.Close()
+	require.NoError(t, s.fsm.waitForIndex(ctx, resp.Version))
+	got, err := s.Get(ctx, &vttopopb.GetRequest{Key: "/file"})
+	require.NoError(t, err)
+	require.Equal(t, "contents", string(got.Kv.Value))
+	require.Equal(t, resp.Version, got.Kv.Version)
+}
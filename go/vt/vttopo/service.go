@@ -0,0 +1,143 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttopo
+
+import (
+	"context"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// Create is part of the vttopopb.TopoServer interface.
+func (s *Server) Create(ctx context.Context, req *vttopopb.CreateRequest) (*vttopopb.CreateResponse, error) {
+	index, err := s.apply(ctx, &vttopopb.Command{Command: &vttopopb.Command_Create{Create: req}})
+	if err != nil {
+		return nil, vterrors.ToGRPC(err)
+	}
+	return &vttopopb.CreateResponse{Version: index}, nil
+}
+
+// Update is part of the vttopopb.TopoServer interface.
+func (s *Server) Update(ctx context.Context, req *vttopopb.UpdateRequest) (*vttopopb.UpdateResponse, error) {
+	index, err := s.apply(ctx, &vttopopb.Command{Command: &vttopopb.Command_Update{Update: req}})
+	if err != nil {
+		return nil, vterrors.ToGRPC(err)
+	}
+	return &vttopopb.UpdateResponse{Version: index}, nil
+}
+
+// Get is part of the vttopopb.TopoServer interface.
+func (s *Server) Get(ctx context.Context, req *vttopopb.GetRequest) (*vttopopb.GetResponse, error) {
+	kv := s.fsm.get(req.Key)
+	if kv == nil {
+		return nil, vterrors.ToGRPC(vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "file %v not found", req.Key))
+	}
+	return &vttopopb.GetResponse{Kv: kv}, nil
+}
+
+// List is part of the vttopopb.TopoServer interface.
+func (s *Server) List(ctx context.Context, req *vttopopb.ListRequest) (*vttopopb.ListResponse, error) {
+	return &vttopopb.ListResponse{Kvs: s.fsm.list(req.Prefix, req.KeysOnly)}, nil
+}
+
+// Delete is part of the vttopopb.TopoServer interface.
+func (s *Server) Delete(ctx context.Context, req *vttopopb.DeleteRequest) (*vttopopb.DeleteResponse, error) {
+	if _, err := s.apply(ctx, &vttopopb.Command{Command: &vttopopb.Command_Delete{Delete: req}}); err != nil {
+		return nil, vterrors.ToGRPC(err)
+	}
+	return &vttopopb.DeleteResponse{}, nil
+}
+
+// Watch is part of the vttopopb.TopoServer interface. The first response
+// has the files watched, the next ones their changes. The stream ends
+// with an error if the watch falls too far behind.
+func (s *Server) Watch(req *vttopopb.WatchRequest, stream vttopopb.Topo_WatchServer) error {
+	kvs, w := s.fsm.watch(req.Key, req.Prefix)
+	defer s.fsm.unwatch(w)
+
+	if err := stream.Send(&vttopopb.WatchResponse{Kvs: kvs}); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-s.done:
+			return vterrors.ToGRPC(vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "node is closing"))
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-w.done:
+			return vterrors.ToGRPC(w.err)
+		case event := <-w.events:
+			if err := stream.Send(&vttopopb.WatchResponse{Events: []*vttopopb.WatchEvent{event}}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Grant is part of the vttopopb.TopoServer interface.
+func (s *Server) Grant(ctx context.Context, req *vttopopb.GrantRequest) (*vttopopb.GrantResponse, error) {
+	if req.Ttl <= 0 {
+		return nil, vterrors.ToGRPC(vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid lease TTL %v", req.Ttl))
+	}
+	index, err := s.apply(ctx, &vttopopb.Command{Command: &vttopopb.Command_Grant{Grant: req}})
+	if err != nil {
+		return nil, vterrors.ToGRPC(err)
+	}
+	return &vttopopb.GrantResponse{LeaseId: index}, nil
+}
+
+// KeepAlive is part of the vttopopb.TopoServer interface. Only the
+// leader tracks the expiry of the leases, so the other nodes forward
+// the request to it.
+func (s *Server) KeepAlive(ctx context.Context, req *vttopopb.KeepAliveRequest) (*vttopopb.KeepAliveResponse, error) {
+	if !s.IsLeader() {
+		client, err := s.leaderClient(ctx)
+		if err != nil {
+			return nil, vterrors.ToGRPC(err)
+		}
+		return client.KeepAlive(ctx, req)
+	}
+
+	ttl, err := s.keepAlive(req.LeaseId)
+	if err != nil {
+		return nil, vterrors.ToGRPC(err)
+	}
+	return &vttopopb.KeepAliveResponse{Ttl: ttl}, nil
+}
+
+// Revoke is part of the vttopopb.TopoServer interface.
+func (s *Server) Revoke(ctx context.Context, req *vttopopb.RevokeRequest) (*vttopopb.RevokeResponse, error) {
+	if _, err := s.apply(ctx, &vttopopb.Command{Command: &vttopopb.Command_Revoke{Revoke: req}}); err != nil {
+		return nil, vterrors.ToGRPC(err)
+	}
+	return &vttopopb.RevokeResponse{}, nil
+}
+
+// Apply is part of the vttopopb.TopoServer interface. It is called by
+// the other nodes, and fails if the node is not the leader anymore.
+func (s *Server) Apply(ctx context.Context, req *vttopopb.ApplyRequest) (*vttopopb.ApplyResponse, error) {
+	if !s.IsLeader() {
+		return nil, vterrors.ToGRPC(vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "node %v is not the leader", s.id))
+	}
+	index, err := s.applyLocal(ctx, req.Command)
+	if err != nil {
+		return nil, vterrors.ToGRPC(err)
+	}
+	return &vttopopb.ApplyResponse{Index: index}, nil
+}
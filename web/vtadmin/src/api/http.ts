@@ -210,6 +210,31 @@ export const createKeyspace = async (params: CreateKeyspaceParams) => {
     return pb.CreateKeyspaceResponse.create(result);
 };
 
+export interface FetchQueryInsightsParams {
+    clusterIDs?: string[];
+    // The name of a GetQueryInsightsRequest.Sort, e.g. 'p99_latency'.
+    sort?: string;
+    limit?: number;
+    table?: string;
+}
+
+export const fetchQueryInsights = async ({ clusterIDs = [], sort, limit, table }: FetchQueryInsightsParams = {}) => {
+    const req = new URLSearchParams();
+    clusterIDs.forEach((id) => req.append('cluster_id', id));
+
+    // Do not append optional parameters if undefined in order to fall back to server defaults
+    if (sort) req.append('sort', sort);
+    if (typeof limit === 'number') req.append('limit', limit.toString());
+    if (table) req.append('table', table);
+
+    const { result } = await vtfetch(`/api/queryinsights?${req}`);
+
+    const err = pb.GetQueryInsightsResponse.verify(result);
+    if (err) throw Error(err);
+
+    return pb.GetQueryInsightsResponse.create(result);
+};
+
 export const fetchSchemas = async () =>
     vtfetchEntities({
         endpoint: '/api/schemas',
@@ -604,6 +629,17 @@ export const workflowSwitchTraffic = async ({ clusterID, request }: WorkflowSwit
     return vtctldata.WorkflowSwitchTrafficResponse.create(result);
 };
 
+export const workflowReverseTraffic = async ({ clusterID, request }: WorkflowSwitchTrafficParams) => {
+    const { result } = await vtfetch(`/api/workflow/${clusterID}/reversetraffic`, {
+        body: JSON.stringify(request),
+        method: 'post',
+    });
+    const err = vtctldata.WorkflowSwitchTrafficResponse.verify(result);
+    if (err) throw Error(err);
+
+    return vtctldata.WorkflowSwitchTrafficResponse.create(result);
+};
+
 export interface WorkflowDeleteParams {
     clusterID: string;
     request: vtctldata.IWorkflowDeleteRequest;
@@ -620,6 +656,28 @@ export const workflowDelete = async ({ clusterID, request }: WorkflowDeleteParam
     return vtctldata.WorkflowDeleteResponse.create(result);
 };
 
+export const workflowCancel = async ({ clusterID, request }: WorkflowDeleteParams) => {
+    const { result } = await vtfetch(`/api/workflow/${clusterID}/cancel`, {
+        body: JSON.stringify(request),
+        method: 'post',
+    });
+    const err = vtctldata.WorkflowDeleteResponse.verify(result);
+    if (err) throw Error(err);
+
+    return vtctldata.WorkflowDeleteResponse.create(result);
+};
+
+export const workflowComplete = async ({ clusterID, request }: MoveTablesCompleteParams) => {
+    const { result } = await vtfetch(`/api/workflow/${clusterID}/complete`, {
+        body: JSON.stringify(request),
+        method: 'post',
+    });
+    const err = vtctldata.MoveTablesCompleteResponse.verify(result);
+    if (err) throw Error(err);
+
+    return vtctldata.MoveTablesCompleteResponse.create(result);
+};
+
 export const fetchVTExplain = async <R extends pb.IVTExplainRequest>({ cluster, keyspace, sql }: R) => {
     // As an easy enhancement for later, we can also validate the request parameters on the front-end
     // instead of defaulting to '', to save a round trip.
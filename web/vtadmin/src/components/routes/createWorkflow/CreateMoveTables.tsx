@@ -63,7 +63,7 @@ const DEFAULT_FORM_DATA: FormData = {
 
 const TABLET_OPTIONS = [1, 2, 3];
 
-const onDDLOptions = ['IGNORE', 'STOP', 'EXEC', 'EXEC_IGNORE'];
+const onDDLOptions = ['IGNORE', 'STOP', 'EXEC', 'EXEC_IGNORE', 'EXEC_SAFE'];
 
 export const CreateMoveTables = () => {
     useDocumentTitle('Create a MoveTables Workflow');